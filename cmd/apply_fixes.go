@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/session"
+
+	"github.com/shouni/go-utils/urlpath"
+	"github.com/spf13/cobra"
+)
+
+// ApplyFixesFlags は apply-fixes コマンド固有のフラグを保持します。
+type ApplyFixesFlags struct {
+	Select string // 適用する修正案の番号 ("all" または "1,3-4" 形式)
+	Branch string // 修正案をコミットする新しいブランチ
+}
+
+var applyFixesFlags ApplyFixesFlags
+
+// applyFixesCmd は 'apply-fixes' サブコマンドを定義します。
+var applyFixesCmd = &cobra.Command{
+	Use:   "apply-fixes",
+	Short: "最後に実行したレビューの修正案を、ローカルのクローンに適用します。",
+	Long: `このコマンドは、--suggest-patches を指定して最後に実行したレビューの結果から修正案を読み込み、選択した修正案をローカルのクローン (--local-path) に適用します。
+--select を省略した場合は、修正案ごとに内容を表示して適用するかを尋ねます。
+適用前にワーキングツリーに未コミットの変更がないことを確認し、レビュー対象のブランチをチェックアウトします。--branch を指定した場合は新しいブランチを作成して修正をコミットし、省略した場合は detached HEAD のワーキングツリーに変更を残します。
+レビュー結果は --session-file (未指定の場合はユーザーのキャッシュディレクトリ) から読み込むため、--repo-url は不要です。`,
+	Example: `  git-gemini-cli generic -u git@github.com:org/repo.git -f feature/x --suggest-patches
+  git-gemini-cli apply-fixes
+  git-gemini-cli apply-fixes --select 1,3 --branch feature/x-review-fixes`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNoRepo: "true"},
+	RunE:        applyFixesCommand,
+}
+
+func init() {
+	applyFixesCmd.Flags().StringVar(&applyFixesFlags.Select, "select", "", "適用する修正案の番号 ('all' または '1,3-4' 形式)。省略した場合は、修正案ごとに適用するかを尋ねます。")
+	applyFixesCmd.Flags().StringVar(&applyFixesFlags.Branch, "branch", "", "レビュー対象のブランチから作成し、適用した修正案をコミットする新しいブランチ。既に存在する場合はエラーになります。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// applyFixesCommand は、保存されたレビュー結果の修正案をローカルのクローンに適用します。
+func applyFixesCommand(cmd *cobra.Command, args []string) error {
+	// --local-path も --repo-url も指定されていない場合は、レビューしたリポジトリのクローンを使用する
+	if ReviewConfig.LocalPath == "" && !ReviewConfig.Ephemeral {
+		path, err := session.ResolvePath(ReviewConfig.SessionFile)
+		if err != nil {
+			return err
+		}
+		review, err := session.Load(path)
+		if err != nil {
+			return err
+		}
+		ReviewConfig.LocalPath = urlpath.SanitizeURLToUniquePath(review.RepoURL, baseRepoDirName)
+		slog.Debug("レビューしたリポジトリのクローンを使用します。", "repoURL", review.RepoURL, "localPath", ReviewConfig.LocalPath)
+	}
+
+	if err := pipeline.ApplyFixes(cmd.Context(), ReviewConfig, cmd.InOrStdin(), cmd.OutOrStdout(), applyFixesFlags.Select, applyFixesFlags.Branch); err != nil {
+		return fmt.Errorf("修正案の適用に失敗しました: %w", err)
+	}
+	return nil
+}
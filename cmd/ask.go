@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/pipeline"
+
+	"github.com/spf13/cobra"
+)
+
+// askCmd は 'ask' サブコマンドを定義します。
+var askCmd = &cobra.Command{
+	Use:     "ask <question>",
+	Short:   "リポジトリのコードについて質問し、ファイル/行の引用付きで回答を得ます。",
+	Long:    `このコマンドは、リポジトリをベースブランチの最新状態に揃えた上で質問に関連するコードを検索し、その内容を根拠としてAIが回答します。差分は使用しないため --feature-branch は不要です。`,
+	Example: `  git-gemini-cli ask "where is retry logic implemented?" --repo-url git@github.com:org/repo.git`,
+	Args:    cobra.ExactArgs(1),
+	RunE:    askCommand,
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// askCommand は、質問応答パイプラインを実行し、回答を標準出力に出力します。
+func askCommand(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	question := strings.TrimSpace(args[0])
+	if question == "" {
+		return fmt.Errorf("質問が空です")
+	}
+
+	answer, err := pipeline.Ask(ctx, ReviewConfig, question)
+	if err != nil {
+		return fmt.Errorf("質問応答の実行に失敗しました: %w", err)
+	}
+
+	fmt.Println("\n--- Gemini AI 回答 ---")
+	fmt.Println(answer)
+	fmt.Println("-----------------------------------------------------")
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"git-gemini-cli/internal/baseline"
+	"git-gemini-cli/internal/pipeline"
+
+	"github.com/spf13/cobra"
+)
+
+// BaselineFlags は baseline コマンド固有のフラグを保持します。
+type BaselineFlags struct {
+	Output string // ベースラインの保存先
+}
+
+var baselineFlags BaselineFlags
+
+// baselineCmd は 'baseline' サブコマンドを定義します。
+var baselineCmd = &cobra.Command{
+	Use:   "baseline",
+	Short: "現在の指摘事項を受け入れ済みとして記録したベースラインを生成します。",
+	Long:  `このコマンドは、ブランチ間の差分をレビューし、すべての指摘事項の指紋 (ファイルと正規化したタイトル) をベースラインファイルに記録します。ベースラインをリポジトリのルートにコミットすると、以降のレビューでは一致する指摘事項が抑制され、--fail-on の判定対象から除かれます。既存のベースラインは使用せずに置き換えます。`,
+	Args:  cobra.NoArgs,
+	RunE:  baselineCommand,
+}
+
+func init() {
+	baselineCmd.Flags().StringVarP(&baselineFlags.Output, "output-file", "o", baseline.FileName, "ベースラインの保存先。レビュー対象リポジトリのルートにコミットしてください。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// baselineCommand は、レビューを実行し、現在の指摘事項からベースラインを生成します。
+func baselineCommand(cmd *cobra.Command, args []string) error {
+	if err := requireFeatureBranch(); err != nil {
+		return err
+	}
+
+	count, err := pipeline.GenerateBaseline(cmd.Context(), ReviewConfig, baselineFlags.Output)
+	if errors.Is(err, pipeline.ErrSkipReview) {
+		slog.Info("レビュー対象の差分がないため、ベースラインは生成しませんでした。")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "%d 件の指摘事項をベースライン '%s' に記録しました。\n", count, baselineFlags.Output)
+	return nil
+}
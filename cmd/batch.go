@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/runner"
+)
+
+// reviewBatch は、--feature-branch の複数指定・--all-branches-matching で指定されたブランチを一括でレビューします。
+// 中断された場合は、それまでに完了したブランチの結果と interrupt.ErrInterrupted を返します。
+func reviewBatch(ctx context.Context) ([]runner.BranchResult, error) {
+	branches, err := pipeline.ResolveBranches(ctx, ReviewConfig, BatchConfig.Branches, BatchConfig.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("ブランチを一括でレビューします。", "base", ReviewConfig.BaseBranch, "branches", branches)
+
+	results, err := pipeline.ReviewBranches(ctx, ReviewConfig, branches)
+	if err != nil && !errors.Is(err, interrupt.ErrInterrupted) {
+		return nil, err
+	}
+	return results, err
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/pipeline"
+
+	"github.com/spf13/cobra"
+)
+
+// chatCmd は 'chat' サブコマンドを定義します。
+var chatCmd = &cobra.Command{
+	Use:   "chat [question]",
+	Short: "最後に実行したレビューの結果について、対話形式で追加の質問をします。",
+	Long: `このコマンドは、最後に実行したレビュー (generic / publish / explain など) の結果と差分を会話の前提として読み込み、「指摘 3 はなぜ問題なのか」「修正例を示して」といった追加の質問に、レビューと同じAIのバックエンドで回答します。
+質問を引数で指定した場合は、1回だけ回答して終了します。省略した場合は、exit と入力するか Ctrl+D を押すまで対話を続けます。
+レビュー結果は --session-file (未指定の場合はユーザーのキャッシュディレクトリ) から読み込むため、--repo-url は不要です。`,
+	Example: `  git-gemini-cli generic -u git@github.com:org/repo.git -f feature/x
+  git-gemini-cli chat
+  git-gemini-cli chat "指摘 3 の修正例を示してください"`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{annotationNoRepo: "true"},
+	RunE:        chatCommand,
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// chatCommand は、保存されたレビュー結果についての対話を実行します。
+func chatCommand(cmd *cobra.Command, args []string) error {
+	var questions []string
+	if len(args) > 0 {
+		question := strings.TrimSpace(args[0])
+		if question == "" {
+			return fmt.Errorf("質問が空です")
+		}
+		questions = append(questions, question)
+	}
+
+	if err := pipeline.Chat(cmd.Context(), ReviewConfig, cmd.InOrStdin(), cmd.OutOrStdout(), questions...); err != nil {
+		return fmt.Errorf("会話の実行に失敗しました: %w", err)
+	}
+	return nil
+}
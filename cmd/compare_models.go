@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/pipeline"
+
+	"github.com/spf13/cobra"
+)
+
+// CompareModelsFlags は compare-models コマンド固有のフラグを保持します。
+type CompareModelsFlags struct {
+	Models string // 比較する2つのモデル名 (カンマ区切り)
+	URI    string // 公開先URI (省略時は標準出力のみ)
+}
+
+var compareModelsFlags CompareModelsFlags
+
+// compareModelsCmd は 'compare-models' サブコマンドを定義します。
+var compareModelsCmd = &cobra.Command{
+	Use:   "compare-models",
+	Short: "同じ差分を2つのモデルでレビューし、結果を並べて比較します。",
+	Long:  `このコマンドは、ブランチ間の差分を --models で指定した2つのモデルで順にレビューし、判定・指摘事項の件数・所要時間の比較、どちらのモデルが指摘したかの対応表、各モデルのレポート本文を並べた比較レポートを出力します。上位モデルに切り替える価値があるかの判断に使用します。--uri を指定した場合は publish と同じパイプラインでクラウドストレージに保存します。`,
+	Args:  cobra.NoArgs,
+	RunE:  compareModelsCommand,
+}
+
+func init() {
+	compareModelsCmd.Flags().StringVar(&compareModelsFlags.Models, "models", "", "比較する2つのモデル名をカンマ区切りで指定します (例: 'gemini-2.5-flash,gemini-2.5-pro')。どちらも --backend のモデルである必要があります。")
+	compareModelsCmd.Flags().StringVarP(&compareModelsFlags.URI, "uri", "s", "", "比較レポートを保存するURI (例: gs://bucket/compare.html)。省略時は標準出力に出力します。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// compareModelsCommand は、2つのモデルでレビューを実行し、比較レポートを標準出力に出力するか、指定されたURIに公開します。
+func compareModelsCommand(cmd *cobra.Command, args []string) error {
+	if err := requireFeatureBranch(); err != nil {
+		return err
+	}
+	models, err := parseModels(compareModelsFlags.Models)
+	if err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	report, err := pipeline.CompareModels(ctx, ReviewConfig, models)
+	if errors.Is(err, pipeline.ErrSkipReview) {
+		slog.Info("差分が空のため、モデルの比較はスキップしました。")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if compareModelsFlags.URI == "" {
+		printReviewResult(report)
+		return nil
+	}
+
+	httpClient, err := GetHTTPClient(ctx)
+	if err != nil {
+		return fmt.Errorf("HTTPクライアントの取得に失敗しました: %w", err)
+	}
+	publishCfg := config.PublishConfig{
+		HttpClient:      httpClient,
+		ReviewConfig:    ReviewConfig,
+		StorageURI:      compareModelsFlags.URI,
+		SlackWebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+	}
+	if err := pipeline.Publish(ctx, publishCfg, report); err != nil {
+		return err
+	}
+
+	slog.Info("処理完了", "uri", publishCfg.StorageURI)
+	return nil
+}
+
+// parseModels は、--models の値を2つの異なるモデル名に分割します。
+func parseModels(value string) ([]string, error) {
+	var models []string
+	for _, m := range strings.Split(value, ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			models = append(models, m)
+		}
+	}
+	if len(models) != 2 || models[0] == models[1] {
+		return nil, fmt.Errorf("--models には異なる2つのモデル名をカンマ区切りで指定してください (例: 'gemini-2.5-flash,gemini-2.5-pro'): %q", value)
+	}
+	return models, nil
+}
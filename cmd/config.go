@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"git-gemini-cli/internal/configfile"
+
+	"github.com/spf13/cobra"
+)
+
+// configMigrateFlags は 'config migrate' のフラグを保持します。
+var configMigrateFlags struct {
+	Write bool // 設定ファイルを上書きする
+}
+
+// configCmd は 'config' サブコマンドを定義します。
+var configCmd = &cobra.Command{
+	Use:         "config",
+	Short:       "設定ファイルを操作します。",
+	Long:        `このコマンドは、--config-file で読み込む設定ファイルを操作するサブコマンドをまとめたものです。`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNoRepo: "true"},
+}
+
+// configMigrateCmd は 'config migrate' サブコマンドを定義します。
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate [path]",
+	Short: "設定ファイルの非推奨のキーを現在の名前に書き換えます。",
+	Long:  `このコマンドは、旧バージョンの設定ファイルで使用されている非推奨のキー (例: gemini) を現在のフラグ名 (例: model) に書き換えます。コメントとキーの順序は維持します。path を省略した場合は --config-file (または環境変数 GIT_GEMINI_CLI_CONFIG) のファイルを対象にします。`,
+	Example: `  git-gemini-cli config migrate .gemini-review/config.yaml
+  git-gemini-cli config migrate .gemini-review/config.yaml --write`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{annotationNoRepo: "true"},
+	RunE:        configMigrateCommand,
+}
+
+func init() {
+	configMigrateCmd.Flags().BoolVarP(&configMigrateFlags.Write, "write", "w", false, "書き換えた内容を標準出力ではなく元のファイルに書き込みます。元の内容は <path>.bak に保存します。")
+	configCmd.AddCommand(configMigrateCmd)
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// configMigrateCommand は、設定ファイルの旧名のキーを書き換え、結果を標準出力またはファイルに出力します。
+func configMigrateCommand(cmd *cobra.Command, args []string) error {
+	path := configFilePath()
+	if len(args) > 0 {
+		path = args[0]
+	}
+	if path == "" {
+		return fmt.Errorf("書き換える設定ファイルのパスを指定してください")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	migrated, applied, err := configfile.Migrate(data)
+	if err != nil {
+		return fmt.Errorf("設定ファイル %s の書き換えに失敗しました: %w", path, err)
+	}
+	for _, r := range applied {
+		slog.Info("非推奨のキーを書き換えます。", "file", path, "deprecated", r.Old, "replacement", r.New, "note", r.Note)
+	}
+
+	if !configMigrateFlags.Write {
+		_, err := cmd.OutOrStdout().Write(migrated)
+		return err
+	}
+	if len(applied) == 0 {
+		slog.Info("書き換えが必要なキーはありません。", "file", path)
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの情報の取得に失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path+".bak", data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("設定ファイルのバックアップに失敗しました: %w", err)
+	}
+	if err := os.WriteFile(path, migrated, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("設定ファイルの書き込みに失敗しました: %w", err)
+	}
+	slog.Info("設定ファイルを書き換えました。", "file", path, "backup", path+".bak", "renamed", len(applied))
+	return nil
+}
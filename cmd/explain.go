@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/prompts"
+
+	"github.com/spf13/cobra"
+)
+
+// ExplainFlags は explain コマンド固有のフラグを保持します。
+type ExplainFlags struct {
+	URI string // 公開先URI (省略時は標準出力のみ)
+}
+
+var explainFlags ExplainFlags
+
+// explainCmd は 'explain' サブコマンドを定義します。
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "差分の内容を新メンバー向けに解説します。",
+	Long:  `このコマンドは、ブランチ間の差分について「何が変わったか」「なぜ変わったと考えられるか」「影響を受けるコンポーネント」を解説する文書をAIで生成します。--uri を指定した場合は publish と同じパイプラインでクラウドストレージに保存します。`,
+	Args:  cobra.NoArgs,
+	RunE:  explainCommand,
+}
+
+func init() {
+	explainCmd.Flags().StringVarP(&explainFlags.URI, "uri", "s", "", "解説を保存するURI (例: gs://bucket/explain.html)。省略時は標準出力に出力します。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// explainCommand は、explain モードでパイプラインを実行し、
+// 結果を標準出力に出力するか、指定されたURIに公開します。
+func explainCommand(cmd *cobra.Command, args []string) error {
+	if err := requireFeatureBranch(); err != nil {
+		return err
+	}
+
+	ctx := cmd.Context()
+
+	// --mode の指定に関わらず、解説用テンプレートを使用する
+	reviewCfg := ReviewConfig
+	reviewCfg.ReviewMode = prompts.ModeExplain
+	// 解説は指摘事項を含まないため、--fail-on による判定は行わない
+	reviewCfg.FailOn = ""
+
+	if explainFlags.URI == "" {
+		result, err := pipeline.Review(ctx, reviewCfg)
+		if errors.Is(err, pipeline.ErrSkipReview) {
+			slog.Info("差分が空のため、解説の出力はスキップしました。")
+			return nil
+		}
+		// 中断された場合も、途中までの解説を出力してから終了する
+		if errors.Is(err, interrupt.ErrInterrupted) {
+			printReviewResult(result)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		printReviewResult(result)
+		return nil
+	}
+
+	httpClient, err := GetHTTPClient(ctx)
+	if err != nil {
+		return fmt.Errorf("HTTPクライアントの取得に失敗しました: %w", err)
+	}
+
+	publishCfg := config.PublishConfig{
+		HttpClient:      httpClient,
+		ReviewConfig:    reviewCfg,
+		StorageURI:      explainFlags.URI,
+		SlackWebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+	}
+
+	if err := pipeline.ReviewAndPublish(ctx, publishCfg); err != nil {
+		if errors.Is(err, pipeline.ErrSkipReview) {
+			slog.Info("差分が空のため、解説の公開処理をスキップします", "uri", publishCfg.StorageURI)
+			return nil
+		}
+		return fmt.Errorf("解説の生成および公開パイプラインの実行に失敗しました: %w", err)
+	}
+
+	slog.Info("処理完了", "uri", publishCfg.StorageURI)
+	return nil
+}
@@ -4,8 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/interrupt"
 	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/runner"
 
 	"github.com/spf13/cobra"
 )
@@ -19,6 +24,17 @@ var genericCmd = &cobra.Command{
 	RunE:  genericCommand,
 }
 
+// genericFlags は 'generic' の出力形式のフラグを保持します。
+var genericFlags struct {
+	Format string // 出力形式 (config.FormatText、config.FormatAnnotatedDiff、config.FormatCodeQuality または config.FormatGitHubAnnotations)
+	Output string // 出力する範囲 (config.OutputFull または config.OutputSummary)
+}
+
+func init() {
+	genericCmd.Flags().StringVar(&genericFlags.Format, "format", config.FormatText, "出力形式: 'text' (レビュー結果) 、'annotated-diff' (unified diff の該当行の直後に指摘事項を '#>' で始まる行として埋め込んだもの。メールやターミナルでのレビュー向け)、'code-quality' (GitLab の Code Quality レポートの JSON。GitLab CI の artifacts:reports:codequality 向け) または 'github-annotations' (GitHub Actions のワークフローコマンド。プルリクエストの差分に注釈として表示される)。")
+	genericCmd.Flags().StringVar(&genericFlags.Output, "output", config.OutputFull, "出力する範囲: 'full' (サマリーと詳細を含むレポート全体) または 'summary' (判定・主なリスク・件数のサマリーのみ)。--format text の場合のみ有効です。")
+}
+
 // --------------------------------------------------------------------------
 // コマンドの実行ロジック
 // --------------------------------------------------------------------------
@@ -26,23 +42,166 @@ var genericCmd = &cobra.Command{
 // genericCommand は、リモートリポジトリのブランチ比較を Gemini AI に依頼し、
 // 結果を標準出力に出力する generic コマンドの実行ロジックです。
 func genericCommand(cmd *cobra.Command, args []string) error {
+	if !isBatch() {
+		if err := requireFeatureBranch(); err != nil {
+			return err
+		}
+	}
+
+	format := strings.ToLower(strings.TrimSpace(genericFlags.Format))
+	switch format {
+	case config.FormatText:
+	case config.FormatAnnotatedDiff:
+		if isBatch() {
+			return fmt.Errorf("--format %s は複数のブランチの一括レビューでは指定できません", config.FormatAnnotatedDiff)
+		}
+		return annotatedDiffCommand(cmd)
+	case config.FormatCodeQuality, config.FormatGitHubAnnotations:
+		if isBatch() {
+			return fmt.Errorf("--format %s は複数のブランチの一括レビューでは指定できません", format)
+		}
+		return findingsReportCommand(cmd, format)
+	default:
+		return fmt.Errorf("--format には '%s'、'%s'、'%s' または '%s' を指定してください: %s", config.FormatText, config.FormatAnnotatedDiff, config.FormatCodeQuality, config.FormatGitHubAnnotations, genericFlags.Format)
+	}
+
+	output := strings.ToLower(strings.TrimSpace(genericFlags.Output))
+	switch output {
+	case config.OutputFull, config.OutputSummary:
+	default:
+		return fmt.Errorf("--output には '%s' または '%s' を指定してください: %s", config.OutputFull, config.OutputSummary, genericFlags.Output)
+	}
+	if isBatch() {
+		return genericBatchCommand(cmd, output)
+	}
+	cfg := ReviewConfig
+	// サマリーは構造化された指摘事項から求めるため、指摘事項の出力を指示する
+	cfg.RequireFindings = cfg.RequireFindings || output == config.OutputSummary
+
 	ctx := cmd.Context()
 
 	// 1. パイプラインを実行し、結果を受け取る
-	reviewResult, err := pipeline.Review(ctx, ReviewConfig)
+	reviewResult, err := pipeline.Review(ctx, cfg)
 	if errors.Is(err, pipeline.ErrSkipReview) {
 		slog.Info("レビュー結果の内容が空のため、標準出力への出力はスキップしました。")
 		return nil
 	}
+	// 中断された場合は、途中までの結果を出力してから終了する (不完全な結果で --fail-on の判定は行わない)
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		report, _ := findings.Split(reviewResult)
+		printReviewResult(report)
+		return err
+	}
 	if err != nil {
 		return err
 	}
 
 	// 2. レビュー結果の出力、レビュー結果の内容が空でない場合にのみ標準出力に出力する
-	printReviewResult(reviewResult)
-	slog.Info("レビュー結果を標準出力に出力しました。")
+	// --fail-on 指定時は、出力後にしきい値の判定結果を終了コードに反映する
+	report, gateErr := pipeline.SeverityGate(cfg, reviewResult)
+	if output == config.OutputSummary {
+		_, list := findings.Split(reviewResult)
+		report = findings.Summarize(list).Markdown()
+	}
+	printReviewResult(report)
+	slog.Info("レビュー結果を標準出力に出力しました。", "output", output)
+
+	return gateErr
+}
+
+// genericBatchCommand は、複数のブランチを一括でレビューし、索引と各ブランチのレポートを標準出力に出力します。
+// --fail-on 指定時やレビューに失敗したブランチがある場合は、出力後にブランチ名付きのエラーを返します。
+func genericBatchCommand(cmd *cobra.Command, output string) error {
+	results, err := reviewBatch(cmd.Context())
+	if errors.Is(err, pipeline.ErrNoBranches) {
+		slog.Info(err.Error(), "pattern", BatchConfig.Pattern)
+		return nil
+	}
+	if err != nil && !errors.Is(err, interrupt.ErrInterrupted) {
+		return err
+	}
+
+	if output == config.OutputSummary {
+		for i := range results {
+			results[i].Report = findings.Summarize(results[i].Findings).Markdown()
+		}
+	}
+	if len(results) > 0 {
+		printReviewResult(runner.BatchIndex(ReviewConfig, results))
+		slog.Info("一括レビューの結果を標準出力に出力しました。", "branches", len(results), "output", output)
+		pipeline.SaveBranchStates(cmd.Context(), results)
+	}
+	// 中断された場合は、完了したブランチの結果を出力してから終了する (不完全な結果で --fail-on の判定は行わない)
+	if err != nil {
+		return err
+	}
+	return pipeline.BatchGate(ReviewConfig, results)
+}
+
+// annotatedDiffCommand は、レビュー結果の指摘事項を差分の該当行に埋め込み、注釈付きの差分を標準出力に出力します。
+// 貼り付けてそのまま使えるよう、区切り線などは付けずに差分のみを出力します。
+func annotatedDiffCommand(cmd *cobra.Command) error {
+	cfg := ReviewConfig
+	cfg.RequireFindings = true
+
+	reviewResult, codeDiff, err := pipeline.ReviewWithDiff(cmd.Context(), cfg)
+	if errors.Is(err, pipeline.ErrSkipReview) {
+		slog.Info("レビュー対象の差分がないため、注釈付き差分の出力はスキップしました。")
+		return nil
+	}
+	if err != nil && !errors.Is(err, interrupt.ErrInterrupted) {
+		return err
+	}
+
+	_, list := findings.Split(reviewResult)
+	fmt.Fprint(cmd.OutOrStdout(), findings.AnnotateDiff(codeDiff, list))
+	if err != nil {
+		// 中断された場合は、途中までの指摘事項を埋め込んだ上で終了する (不完全な結果で --fail-on の判定は行わない)
+		return err
+	}
+
+	_, gateErr := pipeline.SeverityGate(cfg, reviewResult)
+	slog.Info("注釈付き差分を標準出力に出力しました。", "findings", len(list))
+	return gateErr
+}
+
+// findingsReportCommand は、レビュー結果の指摘事項を CI が解釈する形式 (config.FormatCodeQuality または config.FormatGitHubAnnotations) で標準出力に出力します。
+//   - code-quality: GitLab CI でリダイレクトしたファイルを artifacts:reports:codequality に指定すると、マージリクエストのウィジェットに指摘事項が表示されます。
+//   - github-annotations: GitHub Actions の実行中に出力すると、プルリクエストの差分の該当行に注釈が表示されます。
+//
+// 差分がない場合も、前回の結果が残らないよう指摘事項のないレポート (Code Quality の場合は空の配列) を出力します。
+func findingsReportCommand(cmd *cobra.Command, format string) error {
+	cfg := ReviewConfig
+	cfg.RequireFindings = true
+
+	reviewResult, err := pipeline.Review(cmd.Context(), cfg)
+	if errors.Is(err, pipeline.ErrSkipReview) {
+		slog.Info("レビュー対象の差分がないため、指摘事項のないレポートを出力します。", "format", format)
+		err = nil
+	}
+	if err != nil && !errors.Is(err, interrupt.ErrInterrupted) {
+		return err
+	}
+
+	_, list := findings.Split(reviewResult)
+	switch format {
+	case config.FormatCodeQuality:
+		report, marshalErr := findings.CodeQualityReport(list)
+		if marshalErr != nil {
+			return fmt.Errorf("Code Quality レポートの生成に失敗しました: %w", marshalErr)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), string(report))
+	case config.FormatGitHubAnnotations:
+		fmt.Fprint(cmd.OutOrStdout(), findings.GitHubAnnotations(list))
+	}
+	if err != nil {
+		// 中断された場合は、途中までの指摘事項を出力した上で終了する (不完全な結果で --fail-on の判定は行わない)
+		return err
+	}
 
-	return nil
+	_, gateErr := pipeline.SeverityGate(cfg, reviewResult)
+	slog.Info("指摘事項を標準出力に出力しました。", "format", format, "findings", len(list))
+	return gateErr
 }
 
 // printReviewResult は noPost 時に結果を標準出力します。
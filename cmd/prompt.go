@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/runner"
+
+	"github.com/spf13/cobra"
+)
+
+// PromptFlags は prompt コマンド固有のフラグを保持します。
+type PromptFlags struct {
+	Summary bool // プロンプト本文を出力せず、パートごとのトークン数のみを出力する
+}
+
+var promptFlags PromptFlags
+
+// promptCmd は 'prompt' サブコマンドを定義します。
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "AIに送信するプロンプトを生成して出力します (AIは呼び出しません)。",
+	Long:  `このコマンドは、generic と同じ手順で差分の取得・機密情報のマスク・プロンプトの生成・差分の分割 (--max-prompt-tokens、--parallel-files) の判断を行い、AIに送信する予定のプロンプトをそのまま標準出力に出力します。AIは呼び出さないため、トークン数の急増の原因調査や、プロンプトのテンプレート・リポジトリ固有の設定の変更の確認を費用をかけずに行えます。分割したパートの統合や指摘事項の検証など、AIの応答に依存するプロンプトは出力しません。`,
+	Args:  cobra.NoArgs,
+	RunE:  promptCommand,
+}
+
+func init() {
+	promptCmd.Flags().BoolVar(&promptFlags.Summary, "summary", false, "プロンプト本文を出力せず、モード・パートごとの対象ファイルとトークン数の一覧のみを出力します。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// promptCommand は、AIに送信するプロンプトを生成し、標準出力に出力します。
+func promptCommand(cmd *cobra.Command, args []string) error {
+	if err := requireFeatureBranch(); err != nil {
+		return err
+	}
+
+	parts, err := pipeline.BuildPrompts(cmd.Context(), ReviewConfig)
+	if errors.Is(err, pipeline.ErrSkipReview) {
+		slog.Info("レビュー対象の差分がないため、送信するプロンプトはありません。")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if promptFlags.Summary {
+		writePromptSummary(cmd.OutOrStdout(), parts)
+		return nil
+	}
+	for _, p := range parts {
+		fmt.Fprintf(cmd.OutOrStdout(), "===== %s | tokens: %d =====\n", p.Label(), p.Tokens)
+		if p.Note != "" {
+			fmt.Fprintf(cmd.OutOrStdout(), "# 注記: %s\n", p.Note)
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), strings.TrimRight(p.Prompt, "\n"))
+		fmt.Fprintln(cmd.OutOrStdout())
+	}
+	slog.Info("プロンプトを標準出力に出力しました。", "prompts", len(parts))
+	return nil
+}
+
+// writePromptSummary は、各プロンプトのモード・パート・トークン数と、合計のトークン数を出力します。
+func writePromptSummary(w io.Writer, parts []runner.PromptPart) {
+	total := 0
+	for _, p := range parts {
+		fmt.Fprintf(w, "%s\ttokens: %d\tbytes: %d\n", p.Label(), p.Tokens, len(p.Prompt))
+		if p.Note != "" {
+			fmt.Fprintf(w, "\t注記: %s\n", p.Note)
+		}
+		total += p.Tokens
+	}
+	fmt.Fprintf(w, "合計: %d 件のプロンプト, tokens: %d\n", len(parts), total)
+}
@@ -1,20 +1,57 @@
 package cmd
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/objectstore"
 	"git-gemini-cli/internal/pipeline"
 
+	"github.com/shouni/go-utils/urlpath"
 	"github.com/spf13/cobra"
 )
 
 // PublishFlags は GCS/S3 への公開フラグを保持します。
 type PublishFlags struct {
-	URI string // 宛先URI (例: gs://bucket/..., s3://bucket/...)
+	URIs                []string // 宛先URI (例: gs://bucket/..., s3://bucket/..., az://container/..., https://..., sftp://user@host/..., gist://..., file:///path/...)
+	IdempotencyKey      string   // 再実行時の重複排除に使用するキー
+	DisableIdempotency  bool     // 重複排除を無効にする
+	OnConflict          string   // 公開先に既にレポートが存在する場合の動作
+	Provisional         bool     // 途中経過を暫定版として公開する
+	PublishOnInterrupt  bool     // 中断時に途中までの結果を公開する
+	Languages           []string // 公開するレポートの言語コード
+	Format              string   // 公開するレポートの形式 (config.FormatHTML / config.FormatMarkdown / config.FormatPDF)
+	HTMLTemplate        string   // レポートの HTML に使用する独自のテンプレートのパス
+	EmbedDiff           bool     // レビュー対象の差分をレポートに埋め込む
+	ContentType         string   // レポート本体の Content-Type
+	CacheControl        string   // レポート本体の Cache-Control
+	ObjectMetadata      []string // レポート本体のメタデータ ("name=value")
+	Compress            string   // レポートと JSON サイドカーの圧縮形式
+	SignedURLExpiration string   // 通知する署名付きURLの有効期限 (例: 24h, 7d)。'none' の場合は署名しない
+	UploadRetries       int      // 公開が一時的なエラーで失敗した場合の最大再試行回数
+	AtomicPublish       bool     // 一時的なオブジェクトに書き込んでから公開先にコピーする
+	Retention           string   // 公開済みのレポートの保持期間 (例: 90d) または保持件数 (例: 50)
+	UpdateIndex         bool     // 公開先のプレフィックスにある過去のレビューの一覧を更新する
+	Manifest            bool     // 公開先のプレフィックスにあるレビューのマニフェストに記録する
+	JSONSidecar         bool     // レポートと同じ場所に JSON サイドカーを保存する
+	DocsRepoURL         string   // git-branch:// の公開先のリポジトリ
+	HTTPMethod          string   // HTTP の公開先に送信するメソッド
+	HTTPHeaders         []string // HTTP の公開先へのリクエストに付加するヘッダー ("Name: value")
+	PRComment           string   // レビュー結果をコメントとして投稿するプルリクエストのホスティングサービス
+	PRNumber            int      // コメントを投稿するプルリクエスト (マージリクエスト) の番号
+	GerritLabel         string   // Gerrit の変更に投票するラベル
+	GitHubCheck         bool     // レビューしたコミットに GitHub のチェックランを作成
 }
 
 var publishFlags PublishFlags
@@ -22,15 +59,42 @@ var publishFlags PublishFlags
 // publishCmd は 'publish' サブコマンドを定義します。
 var publishCmd = &cobra.Command{
 	Use:   "publish",
-	Short: "AIレビュー結果をHTMLに変換し、指定されたGCS/S3 URIに保存します。",
-	Long:  `このコマンドは、AIレビュー結果をスタイル付きHTMLに変換した後、go-remote-io を利用してURIスキームに応じたクラウドストレージ（gs:// または s3://）にアップロードします。`,
+	Short: "AIレビュー結果をHTMLに変換し、指定されたGCS/S3/Azure URIに保存します。",
+	Long:  `このコマンドは、AIレビュー結果をスタイル付きHTMLに変換した後、go-remote-io を利用してURIスキームに応じたクラウドストレージ（gs://、s3:// または az://）にアップロードします。https:// の場合は JSON として HTTP で送信し、sftp:// の場合は SSH 経由でサーバーに、gist:// の場合は GitHub の秘密の Gist に、git-branch:// の場合はリポジトリのブランチへのコミットとして、file:// のURIまたはローカルのパスを指定した場合は、ローカルファイルに保存します。`,
 	Args:  cobra.NoArgs,
 	RunE:  publishCommand,
 }
 
 func init() {
 	// フラグ名を汎用的なものに変更
-	publishCmd.Flags().StringVarP(&publishFlags.URI, "uri", "s", "", "保存先のURI (例: gs://bucket/result.html, s3://bucket/result.html)")
+	publishCmd.Flags().StringSliceVarP(&publishFlags.URIs, "uri", "s", nil, "保存先のURI (例: gs://bucket/result.html, s3://bucket/result.html, az://container/result.html, https://example.com/api/reports, sftp://user@host/path/result.html, gist://review.md, git-branch://gh-pages/reviews/result.html, file:///path/result.html)。スキームのないパスはローカルファイルとして扱います。{repo}・{branch}・{base}・{mode}・{date}・{time}・{sha}・{shortsha} のプレースホルダを公開時に置き換えます (例: gs://bucket/reviews/{repo}/{branch}/{date}-{shortsha}.html)。複数指定 (繰り返しまたはカンマ区切り) すると、1回のレビュー結果を各URIに公開し、Slack通知は最初のURIの公開時にのみ行います。")
+	publishCmd.Flags().StringVar(&publishFlags.IdempotencyKey, "idempotency-key", "", "CIの再実行などで同じ公開を重複させないための冪等キー。省略時はCIの実行ID (GITHUB_RUN_ID など) と公開内容から自動生成します。")
+	publishCmd.Flags().BoolVar(&publishFlags.DisableIdempotency, "no-idempotency", false, "公開先に重複排除マーカー (<uri>.publish.json) を作成せず、内容が前回の公開と同じ場合も含めて毎回アップロードと通知を行います。")
+	publishCmd.Flags().StringVar(&publishFlags.OnConflict, "on-conflict", config.ConflictOverwrite, "公開先に既にレポートが存在する場合の動作: 'overwrite' (上書き)、'version' (report-v2.html のようにバージョン番号を付けて保存) または 'fail' (公開を中止)。")
+	publishCmd.Flags().BoolVar(&publishFlags.Provisional, "provisional", false, "差分を分割してレビューする場合や複数モードを実行する場合に、パートごとの完了時点で暫定版のレポートを同じURIに公開します。Slack通知は最終版の公開時にのみ行います。")
+	publishCmd.Flags().BoolVar(&publishFlags.PublishOnInterrupt, "publish-on-interrupt", false, "レビュー中に中断シグナル (Ctrl+C / SIGTERM) を受信した場合に、実行中のパートの完了を待って、途中までの結果を未完了の注記付きで公開・通知します。")
+	publishCmd.Flags().StringSliceVar(&publishFlags.Languages, "languages", nil, "公開するレポートの言語コードをカンマ区切りで指定します (例: 'ja,en')。日本語以外はAIで翻訳し、2つ目以降の言語は result.en.html のように言語コード付きのURIに公開して、各版を相互にリンクします。最初の言語の版を --uri に公開し、Slack通知はその版でのみ行います。")
+	publishCmd.Flags().StringVar(&publishFlags.Format, "format", config.FormatHTML, "公開するレポートの形式: 'html' (スタイル付きの HTML に変換) または 'markdown' (AI が出力した Markdown を変換せずに公開。Wiki や静的サイトジェネレーター向け)、'pdf' (HTML と同じテンプレートから PDF に変換。監査証跡や承認のワークフロー向け。Chrome / Chromium が必要)。")
+	publishCmd.Flags().StringVar(&publishFlags.HTMLTemplate, "html-template", "", "レポートの HTML に使用する独自のテンプレート (Go の html/template 形式) のパス。社内のロゴ・CSS・フッターに合わせる場合に指定します。未指定の場合は組み込みのスタイルを使用します。")
+	publishCmd.Flags().BoolVar(&publishFlags.EmbedDiff, "embed-diff", false, "レビュー対象の差分を、ファイルごとに折りたたみ可能な色付きの表示で HTML / PDF のレポートに埋め込みます。")
+	publishCmd.Flags().StringVar(&publishFlags.ContentType, "content-type", "", "GCS / S3 / Azure に公開するレポートの Content-Type (例: 'text/html; charset=shift_jis')。未指定の場合は形式ごとの既定値を使用します。")
+	publishCmd.Flags().StringVar(&publishFlags.CacheControl, "cache-control", "", "GCS / S3 / Azure に公開するレポートの Cache-Control (例: 'no-cache', 'public, max-age=300')。CDN に古いレポートがキャッシュされないように指定します。")
+	publishCmd.Flags().StringArrayVar(&publishFlags.ObjectMetadata, "object-metadata", nil, "GCS / S3 / Azure に公開するレポートに設定するメタデータを 'name=value' の形式で指定します (複数指定可)。値の $VAR / ${VAR} は環境変数で置き換えるため、'commit_sha=${GITHUB_SHA}' のようにCIの変数を渡せます。指定した場合は repo_url・base_branch・head_branch も自動で設定します。")
+	publishCmd.Flags().StringVar(&publishFlags.Compress, "compress", "", "GCS / S3 / Azure に公開する HTML / Markdown のレポートと JSON サイドカーを圧縮し、Content-Encoding を設定します: 'gzip'。大きな差分のレポートの表示を速くし、保存容量を減らします。")
+	publishCmd.Flags().StringVar(&publishFlags.SignedURLExpiration, "signed-url-expiration", "30m", "Slack に通知する署名付きURL (GCS / S3 / Azure) の有効期限 (例: '24h', '7d')。GCS と S3 の上限は 7日 (168h) です。'none' を指定すると、公開バケットを前提として署名せずに公開URLを通知します。")
+	publishCmd.Flags().IntVar(&publishFlags.UploadRetries, "upload-retries", defaultUploadRetries, "レポートの公開がネットワークエラーや 429・5xx で失敗した場合の最大再試行回数。待機時間は --retry-initial-backoff・--retry-max-backoff に従います。0 を指定すると再試行しません。")
+	publishCmd.Flags().BoolVar(&publishFlags.AtomicPublish, "atomic-publish", false, "GCS / S3 / Azure に公開するレポートを同じ場所の一時的なオブジェクトに書き込み、書き込みが完了してからサーバー側のコピーで公開先に反映します。公開先のURLで書き込み途中や失敗した書き込みの内容が読まれないようにします。")
+	publishCmd.Flags().StringVar(&publishFlags.Retention, "retention", "", "公開後に、公開先のプレフィックスにある古いレポートを削除します。保持期間 (例: '90d', '720h') または保持件数 (例: '50') を指定します。")
+	publishCmd.Flags().BoolVar(&publishFlags.UpdateIndex, "update-index", false, "公開先のプレフィックス (レポートと同じディレクトリ) にある過去のレビューの一覧 (index.html と index.json) にレポートを追加します。")
+	publishCmd.Flags().BoolVar(&publishFlags.Manifest, "manifest", false, "公開先のプレフィックスにある manifest.json に、公開したレビューのブランチ・コミットハッシュ・判定・深刻度ごとの件数・URI・公開日時を記録します。他の実行と同時に公開しても記録を失わないよう、条件付き書き込みで更新します。")
+	publishCmd.Flags().BoolVar(&publishFlags.JSONSidecar, "json-sidecar", false, "レポートと同じ場所に、指摘事項・メタデータ・AI の使用量を記録した機械可読な JSON (result.html に対して result.json) を保存します。")
+	publishCmd.Flags().StringVar(&publishFlags.DocsRepoURL, "docs-repo-url", "", "git-branch:// の公開先のリポジトリのURL。未指定の場合は --repo-url のリポジトリのブランチにコミットします。")
+	publishCmd.Flags().StringVar(&publishFlags.HTTPMethod, "http-method", http.MethodPost, "https:// の公開先にレポートを送信するメソッド: 'POST' または 'PUT'。")
+	publishCmd.Flags().StringArrayVar(&publishFlags.HTTPHeaders, "http-header", nil, "https:// の公開先へのリクエストに付加するヘッダーを 'Name: value' の形式で指定します (複数指定可)。値の $VAR / ${VAR} は環境変数で置き換えるため、認証トークンは 'Authorization: Bearer ${REVIEW_API_TOKEN}' のように環境変数から渡せます。")
+	publishCmd.Flags().StringVar(&publishFlags.PRComment, "pr-comment", "", "公開後に、フィーチャーブランチのオープンなプルリクエストにレビュー結果を1つのコメントとして投稿します (2回目以降は同じコメントを更新): 'github'、または 'github-review' (指摘事項を差分の該当行へのインラインコメントとして1つのレビューにまとめて投稿。行に結び付けられない場合は 'github' と同じコメントを投稿)、'gitlab' (マージリクエストのコメント)、'gitlab-review' (指摘事項を差分の該当行へのディスカッションとして作成し、サマリーをコメントとして投稿)、'bitbucket' (Bitbucket Cloud / Server のプルリクエストのコメント)、'gerrit' (Gerrit の変更メッセージ)。GitHub の認証には環境変数 GITHUB_TOKEN (または GH_TOKEN)、または GitHub App の GITHUB_APP_ID と GITHUB_APP_PRIVATE_KEY を、GitLab の認証には GITLAB_TOKEN を、Bitbucket の認証には BITBUCKET_TOKEN、または BITBUCKET_USERNAME と BITBUCKET_APP_PASSWORD を、Gerrit の認証には GERRIT_USERNAME と GERRIT_HTTP_PASSWORD を使用します。")
+	publishCmd.Flags().IntVar(&publishFlags.PRNumber, "pr-number", 0, "--pr-comment でコメントを投稿するプルリクエストの番号 (GitLab の場合はマージリクエストの IID、Bitbucket の場合はプルリクエストの ID、Gerrit の場合は変更の番号)。未指定の場合はフィーチャーブランチから検索します (Gerrit の場合は refs/changes/ の参照から求めます)。")
+	publishCmd.Flags().StringVar(&publishFlags.GerritLabel, "gerrit-label", "", "--pr-comment gerrit で、変更メッセージとともに投票するラベル (例: 'AI-Review')。--fail-on のしきい値以上の指摘事項があれば -1、なければ +1 を投票します。--fail-on と同時に指定してください。")
+	publishCmd.Flags().BoolVar(&publishFlags.GitHubCheck, "github-check", false, "公開後に、レビューしたコミットに GitHub のチェックラン ('Gemini Review') を作成し、サマリーとレビュー結果をプルリクエストの Checks タブに表示します。結論は --fail-on のしきい値以上の指摘事項があれば failure、なければ success (--fail-on が未指定の場合は neutral) です。--pr-comment と同時に指定できます。認証には --pr-comment github と同じ環境変数を使用し、checks: write の権限が必要です。")
 	// URIフラグは必須にする
 	publishCmd.MarkFlagRequired("uri")
 }
@@ -42,6 +106,12 @@ func init() {
 // publishCommand は、AIによるレビュー結果を生成し、指定されたURIのクラウドストレージに
 // 公開（アップロード）と通知を行う publish コマンドの実行ロジックです。
 func publishCommand(cmd *cobra.Command, args []string) error {
+	if !isBatch() {
+		if err := requireFeatureBranch(); err != nil {
+			return err
+		}
+	}
+
 	ctx := cmd.Context()
 
 	httpClient, err := GetHTTPClient(ctx)
@@ -51,10 +121,116 @@ func publishCommand(cmd *cobra.Command, args []string) error {
 
 	// パイプラインを実行し、結果を受け取る
 	publishCfg := config.PublishConfig{
-		HttpClient:      httpClient,
-		ReviewConfig:    ReviewConfig,
-		StorageURI:      publishFlags.URI,
-		SlackWebhookURL: os.Getenv("SLACK_WEBHOOK_URL"),
+		HttpClient:   httpClient,
+		ReviewConfig: ReviewConfig,
+
+		SlackWebhookURL:    os.Getenv("SLACK_WEBHOOK_URL"),
+		IdempotencyKey:     strings.TrimSpace(publishFlags.IdempotencyKey),
+		DisableIdempotency: publishFlags.DisableIdempotency,
+		OnConflict:         strings.ToLower(strings.TrimSpace(publishFlags.OnConflict)),
+		Provisional:        publishFlags.Provisional,
+		PublishOnInterrupt: publishFlags.PublishOnInterrupt,
+		HTMLTemplate:       publishFlags.HTMLTemplate,
+		EmbedDiff:          publishFlags.EmbedDiff,
+		UpdateIndex:        publishFlags.UpdateIndex,
+		Manifest:           publishFlags.Manifest,
+		GitHubCheck:        publishFlags.GitHubCheck,
+		JSONSidecar:        publishFlags.JSONSidecar,
+		UploadRetries:      publishFlags.UploadRetries,
+		AtomicPublish:      publishFlags.AtomicPublish,
+	}
+	uris := normalizeURIs(publishFlags.URIs)
+	if len(uris) == 0 {
+		return fmt.Errorf("--uri に公開先を指定してください")
+	}
+	for _, uri := range uris {
+		if err := pipeline.ValidateURITemplate(uri, !isBatch()); err != nil {
+			return err
+		}
+	}
+	publishCfg.StorageURI, publishCfg.AdditionalURIs = uris[0], uris[1:]
+
+	languages, err := parseLanguages(publishFlags.Languages)
+	if err != nil {
+		return err
+	}
+	publishCfg.Languages = languages
+
+	if publishCfg.RetentionAge, publishCfg.RetentionCount, err = parseRetention(publishFlags.Retention); err != nil {
+		return err
+	}
+	if publishCfg.SignedURLExpiration, publishCfg.PublicURL, err = parseSignedURLExpiration(publishFlags.SignedURLExpiration); err != nil {
+		return err
+	}
+	// 上限を超える場合は署名付きURLの生成 (レポートのアップロード後) に失敗するため、アップロード前に検出する
+	if err := validateSignedURLExpiration(publishCfg.SignedURLExpiration, uris); err != nil {
+		return err
+	}
+
+	publishCfg.Format = strings.ToLower(strings.TrimSpace(publishFlags.Format))
+	switch publishCfg.Format {
+	case config.FormatHTML, config.FormatMarkdown, config.FormatPDF:
+	default:
+		return fmt.Errorf("--format には '%s'、'%s' または '%s' を指定してください: %s", config.FormatHTML, config.FormatMarkdown, config.FormatPDF, publishFlags.Format)
+	}
+
+	publishCfg.HTTPMethod = strings.ToUpper(strings.TrimSpace(publishFlags.HTTPMethod))
+	if publishCfg.HTTPMethod != http.MethodPost && publishCfg.HTTPMethod != http.MethodPut {
+		return fmt.Errorf("--http-method には 'POST' または 'PUT' を指定してください: %s", publishFlags.HTTPMethod)
+	}
+	httpHeader, err := parseHTTPHeaders(publishFlags.HTTPHeaders)
+	if err != nil {
+		return err
+	}
+	publishCfg.HTTPHeader = httpHeader
+
+	// git-branch:// の公開先のリポジトリは、レビュー対象と同じ場合も、レビューのクローンとは別のディレクトリにクローンする
+	// (暫定版の公開など、レビュー中のクローンのロックを保持したまま公開する場合に競合しないようにするため)
+	publishCfg.DocsRepoURL = strings.TrimSpace(publishFlags.DocsRepoURL)
+	if docsRepo := cmp.Or(publishCfg.DocsRepoURL, ReviewConfig.RepoURL); docsRepo != "" {
+		publishCfg.DocsLocalPath = urlpath.SanitizeURLToUniquePath(docsRepo, docsRepoDirName)
+	}
+
+	publishCfg.PRComment = strings.ToLower(strings.TrimSpace(publishFlags.PRComment))
+	switch publishCfg.PRComment {
+	case "", config.PRCommentGitHub, config.PRCommentGitHubReview, config.PRCommentGitLab, config.PRCommentGitLabReview, config.PRCommentBitbucket, config.PRCommentGerrit:
+	default:
+		return fmt.Errorf("--pr-comment には '%s'、'%s'、'%s'、'%s'、'%s' または '%s' を指定してください: %s",
+			config.PRCommentGitHub, config.PRCommentGitHubReview, config.PRCommentGitLab, config.PRCommentGitLabReview, config.PRCommentBitbucket, config.PRCommentGerrit, publishFlags.PRComment)
+	}
+	if publishFlags.PRNumber < 0 {
+		return fmt.Errorf("--pr-number には正の整数を指定してください: %d", publishFlags.PRNumber)
+	}
+	publishCfg.PRNumber = publishFlags.PRNumber
+	publishCfg.GerritLabel = strings.TrimSpace(publishFlags.GerritLabel)
+	if publishCfg.GerritLabel != "" {
+		if publishCfg.PRComment != config.PRCommentGerrit {
+			return fmt.Errorf("--gerrit-label は --pr-comment %s と同時に指定してください", config.PRCommentGerrit)
+		}
+		if ReviewConfig.FailOn == "" {
+			return fmt.Errorf("--gerrit-label の投票には --fail-on で深刻度のしきい値を指定してください")
+		}
+	}
+
+	publishCfg.ContentType = strings.TrimSpace(publishFlags.ContentType)
+	publishCfg.CacheControl = strings.TrimSpace(publishFlags.CacheControl)
+	if publishCfg.ObjectMetadata, err = parseObjectMetadata(publishFlags.ObjectMetadata); err != nil {
+		return err
+	}
+	publishCfg.Compression = strings.ToLower(strings.TrimSpace(publishFlags.Compress))
+	if publishCfg.Compression != "" && publishCfg.Compression != objectstore.EncodingGzip {
+		return fmt.Errorf("--compress には '%s' を指定してください: %s", objectstore.EncodingGzip, publishFlags.Compress)
+	}
+
+	switch publishCfg.OnConflict {
+	case config.ConflictOverwrite, config.ConflictVersion, config.ConflictFail:
+	default:
+		return fmt.Errorf("--on-conflict には '%s'、'%s' または '%s' を指定してください: %s",
+			config.ConflictOverwrite, config.ConflictVersion, config.ConflictFail, publishCfg.OnConflict)
+	}
+
+	if isBatch() {
+		return publishBatch(ctx, publishCfg)
 	}
 
 	if err := pipeline.ReviewAndPublish(ctx, publishCfg); err != nil {
@@ -69,3 +245,166 @@ func publishCommand(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// publishBatch は、複数のブランチを一括でレビューし、ブランチごとのレポートを --uri と同じ場所に、索引を --uri に公開します。
+// 中断された場合は、--publish-on-interrupt が指定されていれば完了したブランチの結果のみを公開します。
+func publishBatch(ctx context.Context, publishCfg config.PublishConfig) error {
+	if len(publishCfg.Languages) > 0 || publishCfg.Provisional {
+		slog.Warn("複数のブランチの一括レビューでは、--languages と --provisional は無視されます。")
+	}
+
+	results, err := reviewBatch(ctx)
+	if errors.Is(err, pipeline.ErrNoBranches) {
+		slog.Info("一括レビューの対象となるブランチがないため、公開処理をスキップします", "pattern", BatchConfig.Pattern, "uri", publishCfg.StorageURI)
+		return nil
+	}
+	interrupted := errors.Is(err, interrupt.ErrInterrupted)
+	if err != nil && !interrupted {
+		return fmt.Errorf("一括レビューの実行に失敗しました: %w", err)
+	}
+	if interrupted && (!publishCfg.PublishOnInterrupt || len(results) == 0) {
+		slog.Warn("一括レビューが途中で終了したため、公開をスキップします。", "uri", publishCfg.StorageURI)
+		return err
+	}
+
+	if pubErr := pipeline.PublishBranches(ctx, publishCfg, results); pubErr != nil {
+		return fmt.Errorf("一括レビューの結果の公開に失敗しました: %w", pubErr)
+	}
+	slog.Info("処理完了", "uri", publishCfg.StorageURI, "branches", len(results))
+
+	if interrupted {
+		return err
+	}
+	return pipeline.BatchGate(ReviewConfig, results)
+}
+
+// languageCodePattern は、--languages に指定できる言語コードの形式です (例: "en", "zh-tw")。
+// 言語コードは公開先のURIの一部になるため、英数字とハイフン以外は受け付けません。
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// parseLanguages は、--languages に指定された言語コードを小文字に正規化し、重複を取り除きます。
+func parseLanguages(values []string) ([]string, error) {
+	var languages []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		lang := strings.ToLower(strings.TrimSpace(v))
+		if lang == "" || seen[lang] {
+			continue
+		}
+		if !languageCodePattern.MatchString(lang) {
+			return nil, fmt.Errorf("--languages に不正な言語コードが指定されました: %s", v)
+		}
+		seen[lang] = true
+		languages = append(languages, lang)
+	}
+	return languages, nil
+}
+
+// parseRetention は、--retention の値を保持期間または保持件数に変換します。
+// "90d" のような日数、"720h" のような time.ParseDuration の形式、または "50" のような件数を指定できます。空の場合は保持期間を適用しません。
+func parseRetention(value string) (time.Duration, int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, 0, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n > 0 {
+		return 0, n, nil
+	}
+	if d, ok := parseDays(value); ok {
+		return d, 0, nil
+	}
+	return 0, 0, fmt.Errorf("--retention には保持期間 (例: '90d', '720h') または保持件数 (例: '50') を指定してください: %s", value)
+}
+
+// parseSignedURLExpiration は、--signed-url-expiration の値を有効期限に変換します。
+// 'none' の場合は、署名せずに公開URLを通知することを示す true を返します。
+func parseSignedURLExpiration(value string) (time.Duration, bool, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "none" {
+		return 0, true, nil
+	}
+	if d, ok := parseDays(value); ok {
+		return d, false, nil
+	}
+	return 0, false, fmt.Errorf("--signed-url-expiration には有効期限 (例: '24h', '7d') または 'none' を指定してください: %s", value)
+}
+
+// maxPresignedURLExpiration は、GCS (V4 署名) と S3 の署名付きURLの有効期限の上限 (7日) です。
+const maxPresignedURLExpiration = 7 * 24 * time.Hour
+
+// validateSignedURLExpiration は、gs:// と s3:// の公開先に対して、署名付きURLの有効期限が上限 (7日) 以下であることを確認します。
+func validateSignedURLExpiration(expiration time.Duration, uris []string) error {
+	if expiration <= maxPresignedURLExpiration {
+		return nil
+	}
+	for _, uri := range uris {
+		if strings.HasPrefix(uri, "gs://") || strings.HasPrefix(uri, "s3://") {
+			return fmt.Errorf("gs:// と s3:// の署名付きURLの有効期限は最大 7日 (168h) です。--signed-url-expiration に 168h 以下を指定してください: %s (公開先: %s)", expiration, uri)
+		}
+	}
+	return nil
+}
+
+// parseDays は、"90d" のような日数、または "720h" のような time.ParseDuration の形式の正の期間を変換します。
+func parseDays(value string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, true
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return d, true
+	}
+	return 0, false
+}
+
+// normalizeURIs は、--uri の値の前後の空白を取り除き、空の値と重複を除きます。
+func normalizeURIs(values []string) []string {
+	var uris []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		uri := strings.TrimSpace(v)
+		if uri == "" || seen[uri] {
+			continue
+		}
+		seen[uri] = true
+		uris = append(uris, uri)
+	}
+	return uris
+}
+
+// metadataNamePattern は、メタデータの名前に使用できる文字列です。Azure Blob Storage のメタデータの制約 (C# の識別子) に合わせます。
+var metadataNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// parseObjectMetadata は、--object-metadata の 'name=value' 形式の値を解析します。値の環境変数は展開します。
+// S3 はメタデータの名前を小文字で保存するため、名前は小文字に統一します。
+func parseObjectMetadata(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	metadata := make(map[string]string, len(values))
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !ok || !metadataNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("--object-metadata は 'name=value' の形式 (名前は英数字とアンダースコア) で指定してください: %s", v)
+		}
+		metadata[name] = os.ExpandEnv(strings.TrimSpace(value))
+	}
+	return metadata, nil
+}
+
+// parseHTTPHeaders は、--http-header の 'Name: value' 形式の値を解析します。
+// 認証トークンをコマンドラインや設定ファイルに直接記述しなくて済むよう、値の環境変数の参照を展開します。
+func parseHTTPHeaders(values []string) (http.Header, error) {
+	header := http.Header{}
+	for _, v := range values {
+		name, value, ok := strings.Cut(v, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("--http-header は 'Name: value' の形式で指定してください: %s", v)
+		}
+		header.Add(name, os.ExpandEnv(strings.TrimSpace(value)))
+	}
+	return header, nil
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/runner"
+
+	"github.com/spf13/cobra"
+)
+
+// ReviewersFlags は reviewers コマンド固有のフラグを保持します。
+type ReviewersFlags struct {
+	Count        int           // 推奨する人数
+	History      time.Duration // 経験の集計に使用する変更履歴の期間
+	TeamFile     string        // レビュアー候補のハンドルとメールアドレスの対応、不在の候補を記述した YAML ファイル
+	HistoryURI   string        // 担当中のレビューの件数を求めるレビューの履歴 (manifest.json)
+	LoadWindow   time.Duration // 担当中として数える推奨の期間
+	IncludeTeams bool          // CODEOWNERS のチームも候補に含める
+	NotifySlack  bool          // 推奨結果を Slack に投稿する
+	PRComment    string        // 推奨結果をコメントとして投稿するプルリクエストのホスティングサービス
+	PRNumber     int           // コメントを投稿するプルリクエスト (マージリクエスト) の番号
+}
+
+var reviewersFlags ReviewersFlags
+
+const (
+	// defaultReviewersHistory は、経験の集計に使用する変更履歴の既定の期間です。
+	defaultReviewersHistory = 365 * 24 * time.Hour
+	// defaultReviewersLoadWindow は、担当中のレビューとして数える推奨の既定の期間です。
+	defaultReviewersLoadWindow = 14 * 24 * time.Hour
+)
+
+// reviewersCmd は 'reviewers' サブコマンドを定義します。
+var reviewersCmd = &cobra.Command{
+	Use:   "reviewers",
+	Short: "CODEOWNERS・変更履歴・レビューの履歴から、差分のレビュアーを推奨します。",
+	Long:  `このコマンドは、変更されたファイルの CODEOWNERS の所有者と、ベースブランチにおける過去の変更者から各候補の経験をスコア化し、レビューの履歴 (--history-uri の manifest.json) に記録した他のブランチへの推奨から求めた担当中のレビューの件数で割り引いて、推奨するレビュアーを出力します。推奨したレビュアーはレビューの履歴に記録し、以降の推奨で担当中のレビューとして数えます。結果はプルリクエスト (--pr-comment) や Slack (--notify-slack) にも投稿できます。AI は使用しません。PR の作成者 (レビュー対象のコミットの作成者) は候補から除外します。`,
+	Args:  cobra.NoArgs,
+	RunE:  reviewersCommand,
+}
+
+func init() {
+	reviewersCmd.Flags().IntVar(&reviewersFlags.Count, "count", 2, "推奨するレビュアーの人数。")
+	reviewersCmd.Flags().DurationVar(&reviewersFlags.History, "history", defaultReviewersHistory, "経験の集計に使用する変更履歴の期間 (例: '2160h')。")
+	reviewersCmd.Flags().StringVar(&reviewersFlags.TeamFile, "team-file", "", "レビュアー候補のハンドルとメールアドレス、不在の候補を記述した YAML ファイル。コミットの作成者を CODEOWNERS のハンドルに対応付けます。")
+	reviewersCmd.Flags().StringVar(&reviewersFlags.HistoryURI, "history-uri", "", "レビューの履歴として使用する、publish --manifest で記録した manifest.json のURI、またはそのプレフィックス (例: 'gs://bucket/reviews/')。他のブランチに推奨したレビュアーの件数を担当中のレビューとしてスコアを割り引き、今回の推奨をこのブランチの最新のレビューに記録します。")
+	reviewersCmd.Flags().DurationVar(&reviewersFlags.LoadWindow, "load-window", defaultReviewersLoadWindow, "--history-uri のレビューの履歴のうち、担当中のレビューとして数える推奨の期間 (例: '168h')。")
+	reviewersCmd.Flags().BoolVar(&reviewersFlags.IncludeTeams, "include-teams", false, "CODEOWNERS のチーム ('@org/team') も候補に含めます。")
+	reviewersCmd.Flags().BoolVar(&reviewersFlags.NotifySlack, "notify-slack", false, "推奨結果を環境変数 SLACK_WEBHOOK_URL の Slack チャンネルに投稿します。")
+	reviewersCmd.Flags().StringVar(&reviewersFlags.PRComment, "pr-comment", "", "推奨結果を、フィーチャーブランチのオープンなプルリクエストに1つのコメントとして投稿します (2回目以降は同じコメントを更新): 'github'、'gitlab'、'bitbucket' または 'gerrit'。認証には publish --pr-comment と同じ環境変数を使用します。")
+	reviewersCmd.Flags().IntVar(&reviewersFlags.PRNumber, "pr-number", 0, "--pr-comment でコメントを投稿するプルリクエストの番号。未指定の場合はフィーチャーブランチから検索します。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// reviewersCommand は、レビュアーの推奨を実行し、結果を標準出力に出力します。
+func reviewersCommand(cmd *cobra.Command, args []string) error {
+	if err := requireFeatureBranch(); err != nil {
+		return err
+	}
+	if reviewersFlags.Count <= 0 {
+		return fmt.Errorf("--count には 1 以上の値を指定してください: %d", reviewersFlags.Count)
+	}
+	if reviewersFlags.LoadWindow <= 0 {
+		return fmt.Errorf("--load-window には正の期間を指定してください: %s", reviewersFlags.LoadWindow)
+	}
+	if reviewersFlags.PRNumber < 0 {
+		return fmt.Errorf("--pr-number には正の整数を指定してください: %d", reviewersFlags.PRNumber)
+	}
+
+	ctx := cmd.Context()
+	httpClient, err := GetHTTPClient(ctx)
+	if err != nil {
+		return fmt.Errorf("HTTPクライアントの取得に失敗しました: %w", err)
+	}
+
+	reviewersCfg := config.ReviewersConfig{
+		HttpClient:   httpClient,
+		ReviewConfig: ReviewConfig,
+		Count:        reviewersFlags.Count,
+		History:      reviewersFlags.History,
+		TeamFile:     strings.TrimSpace(reviewersFlags.TeamFile),
+		LoadWindow:   reviewersFlags.LoadWindow,
+		IncludeTeams: reviewersFlags.IncludeTeams,
+		PRComment:    strings.ToLower(strings.TrimSpace(reviewersFlags.PRComment)),
+		PRNumber:     reviewersFlags.PRNumber,
+	}
+	if historyURI := strings.TrimSpace(reviewersFlags.HistoryURI); historyURI != "" {
+		reviewersCfg.HistoryURI = runner.ManifestURI(historyURI)
+	}
+	switch reviewersCfg.PRComment {
+	case "", config.PRCommentGitHub, config.PRCommentGitLab, config.PRCommentBitbucket, config.PRCommentGerrit:
+	default:
+		return fmt.Errorf("--pr-comment には '%s'、'%s'、'%s' または '%s' を指定してください: %s",
+			config.PRCommentGitHub, config.PRCommentGitLab, config.PRCommentBitbucket, config.PRCommentGerrit, reviewersFlags.PRComment)
+	}
+	if reviewersFlags.NotifySlack {
+		reviewersCfg.SlackWebhookURL = os.Getenv("SLACK_WEBHOOK_URL")
+		if reviewersCfg.SlackWebhookURL == "" {
+			return errors.New("--notify-slack には環境変数 SLACK_WEBHOOK_URL の設定が必要です")
+		}
+	}
+
+	report, err := pipeline.SuggestReviewers(ctx, reviewersCfg)
+	if errors.Is(err, pipeline.ErrSkipReview) {
+		slog.Info("差分が空のため、レビュアーの推奨はスキップしました。")
+		return nil
+	}
+	if report != "" {
+		fmt.Println(report)
+	}
+	return err
+}
@@ -1,27 +1,76 @@
 package cmd
 
 import (
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
+	"git-gemini-cli/internal/compat"
 	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/configfile"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/netconfig"
+	"git-gemini-cli/internal/objectstore"
+	"git-gemini-cli/internal/timeutil"
 
 	"github.com/shouni/go-cli-base"
 	"github.com/shouni/go-http-kit/pkg/httpkit"
 	"github.com/shouni/go-utils/urlpath"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // ReviewConfig は、レビュー実行のパラメータです
 var ReviewConfig config.ReviewConfig
 
+// NetworkConfig は、外部サービスへの接続に使用するネットワーク設定です
+var NetworkConfig config.NetworkConfig
+
+// S3Config は、S3 互換ストレージに接続する場合の設定です
+var S3Config config.S3Config
+
+// EncryptionConfig は、ストレージに書き込むオブジェクトの暗号化の設定です
+var EncryptionConfig config.EncryptionConfig
+
+// configFile は、フラグの値を読み込む設定ファイルのパスです
+var configFile string
+
+// BatchConfig は、複数のフィーチャーブランチを一括でレビューする場合の指定です
+var BatchConfig struct {
+	Branches []string // --feature-branch に指定されたブランチ (1つの場合は ReviewConfig.FeatureBranch にも設定する)
+	Pattern  string   // --all-branches-matching に指定された glob パターン
+}
+
 const (
 	defaultHTTPTimeout = 30 * time.Second
+	defaultLockTimeout = 5 * time.Minute
 	baseRepoDirName    = "reviewerRepos"
+	// docsRepoDirName は、git-branch:// の公開先のリポジトリをクローンするディレクトリ名です (レビュー対象と同じリポジトリの場合も別にクローンする)。
+	docsRepoDirName = "reviewerDocsRepos"
+	// Gemini API の再試行の既定値
+	defaultMaxRetries          = 3
+	defaultRetryInitialBackoff = 2 * time.Second
+	defaultRetryMaxBackoff     = time.Minute
+	// defaultUploadRetries は、レポートの公開の再試行回数の既定値です (待機時間は Gemini API の再試行と共通)。
+	defaultUploadRetries = 3
+	// defaultContextMaxBytes は、--context full-files で追加するファイル内容の合計サイズの既定の上限です。
+	defaultContextMaxBytes = 200 * 1024
+
+	// defaultOpenAIBaseURL は、--backend openai で --openai-base-url・OPENAI_BASE_URL が未指定の場合に使用する API のベースURLです。
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	// defaultOllamaHost は、--backend ollama で --ollama-host・OLLAMA_HOST が未指定の場合に使用する Ollama サーバーのURLです。
+	defaultOllamaHost = "http://localhost:11434"
+	// defaultAnthropicBaseURL は、--backend claude で --anthropic-base-url・ANTHROPIC_BASE_URL が未指定の場合に使用する API のベースURLです。
+	defaultAnthropicBaseURL = "https://api.anthropic.com"
+	// annotationNoRepo は、--repo-url を必要としないコマンドに付与するアノテーションです。
+	annotationNoRepo = "git-gemini-cli/no-repo"
 )
 
 // clientKey は context.Context に httpkit.Client を格納・取得するための非公開キー
@@ -41,9 +90,6 @@ func GetHTTPClient(ctx context.Context) (httpkit.ClientInterface, error) {
 // initAppPreRunE は、アプリケーション固有のPersistentPreRunEです。
 func initAppPreRunE(cmd *cobra.Command, args []string) error {
 
-	// ユーザー入力の前後にある余計なスペースを除去
-	ReviewConfig.Normalize()
-
 	// slog ハンドラの設定
 	logLevel := slog.LevelInfo
 	if clibase.Flags.Verbose {
@@ -55,22 +101,195 @@ func initAppPreRunE(cmd *cobra.Command, args []string) error {
 	})
 	slog.SetDefault(slog.New(handler))
 
+	// 旧名のフラグ・設定ファイルのキーの使用を警告する (フラグの解析時にはロガーが未設定のため、ここでまとめて出力する)
+	compat.Report()
+
+	if ReviewConfig.RepoURL == "" && !skipsRepo(cmd) {
+		return ErrRepoURLRequired
+	}
+
+	// --feature-branch が1つの場合は通常のレビュー、複数の場合は一括レビューの対象とする
+	BatchConfig.Branches = normalizeBranches(BatchConfig.Branches)
+	BatchConfig.Pattern = strings.TrimSpace(BatchConfig.Pattern)
+	if len(BatchConfig.Branches) == 1 {
+		ReviewConfig.FeatureBranch = BatchConfig.Branches[0]
+	}
+	if isBatch() && ReviewConfig.FromTag != "" {
+		return errors.New("複数の --feature-branch および --all-branches-matching は --from-tag と同時に指定できません")
+	}
+
+	// ユーザー入力の前後にある余計なスペースを除去
+	ReviewConfig.Normalize()
+	if ReviewConfig.OnBudgetExceeded != config.BudgetRefuse && ReviewConfig.OnBudgetExceeded != config.BudgetChunk {
+		return fmt.Errorf("--on-budget-exceeded には '%s' または '%s' を指定してください: %s", config.BudgetRefuse, config.BudgetChunk, ReviewConfig.OnBudgetExceeded)
+	}
+	if ReviewConfig.ParallelFiles < 0 {
+		return fmt.Errorf("--parallel-files には 0 以上の値を指定してください: %d", ReviewConfig.ParallelFiles)
+	}
+	for _, c := range ReviewConfig.Contexts() {
+		switch c {
+		case config.ContextDiff, config.ContextFullFiles, config.ContextImports:
+		default:
+			return fmt.Errorf("--context には '%s'、'%s'、'%s' またはそのカンマ区切りを指定してください: %s",
+				config.ContextDiff, config.ContextFullFiles, config.ContextImports, c)
+		}
+	}
+	for _, mode := range ReviewConfig.Modes() {
+		if _, err := ReviewConfig.GenerationParams(mode); err != nil {
+			return err
+		}
+	}
+	switch ReviewConfig.Backend {
+	case config.BackendGemini:
+	case config.BackendOpenAI:
+		if ReviewConfig.OpenAIBaseURL == "" {
+			ReviewConfig.OpenAIBaseURL = cmp.Or(os.Getenv("OPENAI_BASE_URL"), defaultOpenAIBaseURL)
+		}
+	case config.BackendOllama:
+		if ReviewConfig.OllamaHost == "" {
+			ReviewConfig.OllamaHost = cmp.Or(os.Getenv("OLLAMA_HOST"), defaultOllamaHost)
+		}
+		if ReviewConfig.OllamaNumCtx < 0 {
+			return fmt.Errorf("--ollama-num-ctx には 0 以上の値を指定してください: %d", ReviewConfig.OllamaNumCtx)
+		}
+	case config.BackendClaude:
+		if ReviewConfig.AnthropicBaseURL == "" {
+			ReviewConfig.AnthropicBaseURL = cmp.Or(os.Getenv("ANTHROPIC_BASE_URL"), defaultAnthropicBaseURL)
+		}
+	default:
+		return fmt.Errorf("--backend には '%s'、'%s'、'%s' または '%s' を指定してください: %s",
+			config.BackendGemini, config.BackendOpenAI, config.BackendOllama, config.BackendClaude, ReviewConfig.Backend)
+	}
+	switch ReviewConfig.ImpactAnalysis {
+	case config.ImpactNone, config.ImpactGo, config.ImpactBazel:
+	default:
+		return fmt.Errorf("--impact-analysis には '%s'、'%s' または '%s' を指定してください: %s",
+			config.ImpactNone, config.ImpactGo, config.ImpactBazel, ReviewConfig.ImpactAnalysis)
+	}
+	switch ReviewConfig.PRContext {
+	case config.PRContextNone, config.PRContextGitHub, config.PRContextGitLab, config.PRContextBitbucket:
+	default:
+		return fmt.Errorf("--pr-context には '%s'、'%s'、'%s' または '%s' を指定してください: %s",
+			config.PRContextNone, config.PRContextGitHub, config.PRContextGitLab, config.PRContextBitbucket, ReviewConfig.PRContext)
+	}
+	if _, err := findings.ParseSeverity(ReviewConfig.BlastRadiusSeverity); err != nil {
+		return fmt.Errorf("--blast-radius-severity の指定が不正です: %w", err)
+	}
+	if ReviewConfig.FailOn != "" {
+		if _, err := findings.ParseSeverity(ReviewConfig.FailOn); err != nil {
+			return fmt.Errorf("--fail-on の指定が不正です: %w", err)
+		}
+	}
+	// タグ範囲は固定のため、前回のレビュー以降の差分という考え方が当てはまらない
+	if ReviewConfig.Incremental && ReviewConfig.FromTag != "" {
+		return errors.New("--incremental は --from-tag と同時に指定できません")
+	}
+
+	// レポート・通知の日時を実行環境 (CIランナーのリージョン) に依存させないため、タイムゾーンを統一する
+	loc, err := timeutil.LoadLocation(ReviewConfig.Timezone)
+	if err != nil {
+		return fmt.Errorf("--timezone の指定が不正です: %w", err)
+	}
+	timeutil.SetDefault(loc)
+
+	// プロキシ・TLS設定の適用 (HTTPクライアントや Gemini SDK の生成前に行う)
+	NetworkConfig.Normalize()
+	if err := netconfig.ConfigureDefaultTransport(NetworkConfig); err != nil {
+		return fmt.Errorf("ネットワーク設定の適用に失敗しました: %w", err)
+	}
+	// 詳細ログ有効時は、通信ごとのサイズ・所要時間を記録する (公開や通知が遅い・失敗する場合の調査用)
+	// serve では、/debug/vars で参照できるよう、詳細ログの有無に関わらずメトリクスを集計する
+	if clibase.Flags.Verbose || cmd == serveCmd {
+		netconfig.InstallLogging()
+	}
+	// AWS SDK・GCS クライアント・Gemini SDK には、既定の Transport ではなく設定を適用したクライアントを明示的に渡す
+	sdkClient, err := netconfig.NewHTTPClient(NetworkConfig)
+	if err != nil {
+		return fmt.Errorf("ネットワーク設定の適用に失敗しました: %w", err)
+	}
+	ReviewConfig.HTTPClient = sdkClient
+	objectstore.ConfigureHTTPClient(sdkClient)
+	// s3:// の接続先 (S3 互換ストレージ) の適用 (レポートの公開や状態ファイルの読み書きの前に行う)
+	objectstore.ConfigureS3(S3Config.Endpoint, S3Config.PathStyle)
+	objectstore.ConfigureEncryption(EncryptionConfig.GCSKMSKey, EncryptionConfig.S3KMSKey)
+
 	// HTTPクライアントの初期化
 	httpClient := httpkit.New(defaultHTTPTimeout)
+	ReviewConfig.APIClient = httpClient
+	objectstore.ConfigureAPIClient(httpClient)
+
+	// インメモリモードではディスク上のクローンを使用しないため、LocalPath は不要
+	if ReviewConfig.Ephemeral {
+		if ReviewConfig.LocalPath != "" {
+			slog.Warn("--ephemeral が指定されているため、--local-path は無視されます。", "localPath", ReviewConfig.LocalPath)
+		}
+		ReviewConfig.LocalPath = ""
+	}
 
 	// RepoURLが指定されている場合のみ、LocalPathの動的生成を試みる
-	if ReviewConfig.LocalPath == "" && ReviewConfig.RepoURL != "" {
+	if !ReviewConfig.Ephemeral && ReviewConfig.LocalPath == "" && ReviewConfig.RepoURL != "" {
 		ReviewConfig.LocalPath = urlpath.SanitizeURLToUniquePath(ReviewConfig.RepoURL, baseRepoDirName)
 		slog.Debug("LocalPathが未指定のため、URLから動的にパスを生成しました。", "generatedPath", ReviewConfig.LocalPath)
 	}
 
 	// コマンドのコンテキストに HTTP Client を格納
-	ctx := context.WithValue(cmd.Context(), clientKey{}, httpClient)
-	cmd.SetContext(ctx)
+	ctx := cmd.Context()
+	if summarizesOnInterrupt(cmd) {
+		// 中断シグナル (Ctrl+C) の受信時は、実行中のパートの完了を待って途中までの結果をまとめる
+		ctx = interrupt.Install(ctx)
+	}
+	cmd.SetContext(context.WithValue(ctx, clientKey{}, httpClient))
 
 	return nil
 }
 
+// summarizesOnInterrupt は、中断時に途中までの結果をまとめるコマンド (レビュー・公開・serve) かを返します。
+// それ以外のコマンドでは 1回目のシグナルで処理が止まらなくなるため、interrupt.Install を行わず、既定の動作で即座に終了させます。
+func summarizesOnInterrupt(cmd *cobra.Command) bool {
+	switch cmd {
+	case genericCmd, publishCmd, explainCmd, serveCmd:
+		return true
+	}
+	return false
+}
+
+// configFilePath は、読み込む設定ファイルのパスを返します。--config-file が未指定の場合は環境変数を参照します。
+func configFilePath() string {
+	if configFile != "" {
+		return configFile
+	}
+	return os.Getenv(configfile.EnvPath)
+}
+
+// loadConfigFile は、設定ファイルの値をフラグに反映します。
+// cobra の必須フラグの検証より前に反映する必要があるため、PersistentPreRunE ではなく cobra.OnInitialize から呼び出します。
+func loadConfigFile(rootCmd *cobra.Command) error {
+	path := configFilePath()
+	if path == "" {
+		return nil
+	}
+	values, err := configfile.Load(path)
+	if err != nil {
+		return err
+	}
+	// 実行するサブコマンドが決まる前のため、すべてのサブコマンドのフラグを対象にする
+	sets := []*pflag.FlagSet{rootCmd.PersistentFlags()}
+	for _, c := range rootCmd.Commands() {
+		sets = append(sets, c.Flags())
+	}
+	return configfile.Apply(path, values, sets...)
+}
+
+// skipsRepo は、コマンド (またはその親) が --repo-url を必要としないかを返します。
+func skipsRepo(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[annotationNoRepo] != "" {
+			return true
+		}
+	}
+	return false
+}
+
 // getDefaultSSHKeyPath は、ユーザーのホームディレクトリに基づいてSSH秘密鍵のデフォルトパスを解決します。
 func getDefaultSSHKeyPath() string {
 	home, err := os.UserHomeDir()
@@ -85,19 +304,129 @@ func getDefaultSSHKeyPath() string {
 // addAppPersistentFlags は、アプリケーション固有の永続フラグをルートコマンドに追加します。
 func addAppPersistentFlags(rootCmd *cobra.Command) {
 	defaultSSHKeyPath := getDefaultSSHKeyPath()
+	// 旧バージョンのフラグ名 (例: --gemini) を現在の名前に読み替える
+	rootCmd.SetGlobalNormalizationFunc(compat.NormalizeFlagName)
+	rootCmd.PersistentFlags().StringVar(&configFile, "config-file", "", "フラグの値を記述した YAML 形式の設定ファイルのパス (キーはフラグ名)。コマンドラインで指定したフラグが優先されます。未指定の場合は環境変数 GIT_GEMINI_CLI_CONFIG を参照します。")
+	cobra.OnInitialize(func() { cobra.CheckErr(loadConfigFile(rootCmd)) })
 	// ReviewConfig.ReviewMode にバインド
-	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.ReviewMode, "mode", "m", "detail", "レビューモードを指定: 'release' (リリース判定) または 'detail' (詳細レビュー)")
+	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.ReviewMode, "mode", "m", "detail", "レビューモードを指定: 'release' (リリース判定)、'detail' (詳細レビュー)、'security' (セキュリティ)、'performance' (パフォーマンス)、'test-gap' (テスト不足の分析)、'changelog' (変更履歴の生成)、'commit-msg' (コミットメッセージ)、'release-notes' (リリースノート) または 'explain' (変更内容の解説)。カンマ区切りで複数指定すると (例: 'detail,security')、同じ差分に対して各モードを実行し1つのレポートにまとめます。")
 	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.RepoURL, "repo-url", "u", "", "レビュー対象の Git リポジトリの SSH URL。")
 	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.BaseBranch, "base-branch", "b", "main", "差分比較の基準ブランチ (例: 'main').")
-	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.FeatureBranch, "feature-branch", "f", "", "レビュー対象のフィーチャーブランチ (例: 'feature/my-branch').")
+	rootCmd.PersistentFlags().StringArrayVarP(&BatchConfig.Branches, "feature-branch", "f", nil, "レビュー対象のフィーチャーブランチ (例: 'feature/my-branch')。generic・publish では複数回指定すると、各ブランチを --base-branch と比較して一括でレビューし、ブランチごとのレポートと索引を出力します。")
+	rootCmd.PersistentFlags().StringVar(&BatchConfig.Pattern, "all-branches-matching", "", "generic・publish で、リモートのブランチのうちこの glob パターン (例: 'release/*') に一致するものをすべて一括でレビューします。--feature-branch と併用できます。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.FromTag, "from-tag", "", "タグ範囲の差分を取る場合の起点タグ (例: 'v1.2.0')。指定するとブランチではなくタグ間の差分を対象にします。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.ToTag, "to-tag", "", "タグ範囲の差分を取る場合の終点タグ (例: 'v1.3.0')。省略時は --feature-branch、それもなければ --base-branch の最新を終点とします。")
 	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.LocalPath, "local-path", "l", "", "リポジトリをクローンするローカルパス。")
-	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.GeminiModel, "gemini", "g", "gemini-2.5-flash", "レビューに使用する Gemini モデル名 (例: 'gemini-2.5-flash').")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.Backend, "backend", config.BackendGemini, "レビューに使用するAIのバックエンド: 'gemini' (Gemini API)、'openai' (OpenAI の Chat Completions API 互換のエンドポイント。Azure OpenAI や社内ゲートウェイを含む) 'ollama' (Ollama サーバー上のローカル LLM。差分をクラウドに送信しません) または 'claude' (Anthropic API の Claude)。")
+	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.Model, "model", "g", "", "レビューに使用するモデル名 (例: 'gemini-2.5-flash', 'gpt-4o')。Azure OpenAI の場合はデプロイメント名を指定します。未指定の場合は --backend ごとの既定値 (gemini: 'gemini-2.5-flash'、openai: 'gpt-4o-mini'、ollama: 'codellama'、claude: 'claude-sonnet-4-5') を使用します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.OpenAIBaseURL, "openai-base-url", "", "--backend openai で使用する API のベースURL (例: 'https://api.openai.com/v1')。Azure OpenAI の場合はリソースのURL (例: 'https://my-resource.openai.azure.com') を指定します。未指定の場合は環境変数 OPENAI_BASE_URL、それもなければ OpenAI の API を使用します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.OpenAIAPIVersion, "openai-api-version", "", "Azure OpenAI の API バージョン (例: '2024-10-21')。指定すると Azure OpenAI の形式 (api-key ヘッダ、デプロイメント単位のURL) で呼び出します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.OllamaHost, "ollama-host", "", "--backend ollama で使用する Ollama サーバーのURL。未指定の場合は環境変数 OLLAMA_HOST、それもなければ 'http://localhost:11434' を使用します。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.OllamaNumCtx, "ollama-num-ctx", 0, "--backend ollama で使用するモデルのコンテキスト長 (num_ctx)。Ollama の既定値は差分全体を含めるには短く、超過した部分は切り捨てられるため、大きな差分ではモデルが対応する値 (例: 16384) を指定してください。0 はサーバー・モデルの既定値です。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.AnthropicBaseURL, "anthropic-base-url", "", "--backend claude で使用する API のベースURL。未指定の場合は環境変数 ANTHROPIC_BASE_URL、それもなければ 'https://api.anthropic.com' を使用します。")
 	rootCmd.PersistentFlags().StringVarP(&ReviewConfig.SSHKeyPath, "ssh-key-path", "k", defaultSSHKeyPath, "Git 認証に使用する SSH 秘密鍵のパス。")
 	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.SkipHostKeyCheck, "skip-host-key-check", false, "【🚨 危険な設定】 SSH ホストキーの検証を無効にします。中間者攻撃のリスクを劇的に高めるため、本番環境では絶対に使用しないでください。開発/テスト環境でのみ使用してください。")
 	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.UseExternalGitCommand, "use-external-git-command", true, "Go実装の内部アダプターではなく、外部のローカルGitコマンド（git）を使用してリポジトリを操作します。")
+	rootCmd.PersistentFlags().DurationVar(&ReviewConfig.LockTimeout, "lock-timeout", defaultLockTimeout, "別プロセスが同じローカルパスを使用中の場合に、ロックの解放を待機する最大時間。0 を指定すると待機せずに失敗します。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.DisableGitFallback, "no-git-fallback", false, "go-git アダプタ (--use-external-git-command=false) が失敗した場合に、外部Gitコマンドへ自動で切り替える動作を無効にします。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.ReadOnly, "read-only", false, "ローカルリポジトリを変更しない読み取り専用モード。git fetch とリモート参照間の差分取得のみを行い、checkout -B や clean は実行しません。作業中のワーキングコピーに対しても安全に実行できます。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.Ephemeral, "ephemeral", false, "ディスクにクローンせず、go-git のインメモリストレージ上でリポジトリを扱います。--local-path は不要になります。小規模リポジトリや使い捨てのCI環境向けです。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.CommitConventionFile, "commit-convention", "", "commit-msg モードで使用するチーム独自のコミット規約ファイル。未指定の場合はリポジトリ内の .gemini-review/commit-convention.md、それもなければ Conventional Commits を使用します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.GlossaryFile, "glossary", "", "プロンプトに追加するチームの用語集ファイル (ドメイン用語、社内サービス名、略語など)。未指定の場合はリポジトリ内の .gemini-review/glossary.md を使用します (--ignore-repo-prompt 指定時を除く)。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.Persona, "persona", "", "プロンプトの先頭に追加するレビュアーのペルソナ (例: '信頼性を重視するスタッフSREとして、障害時の影響と運用性を中心にレビューする')。チームごとの基準に沿ったレビューにするために使用します。--persona-file とリポジトリ内の .gemini-review/persona.md より優先します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.PersonaFile, "persona-file", "", "レビュアーのペルソナを記述したファイル。未指定の場合はリポジトリ内の .gemini-review/persona.md を使用します (--ignore-repo-prompt 指定時を除く)。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.SystemInstruction, "system-instruction", "", "上級者向け: 組み込みのレビュープロンプト全体を置き換えるシステムプロンプト。Go テンプレートとして解釈され、{{.DiffContent}}、{{.BaseBranch}}、{{.FeatureBranch}}、{{.Mode}} などの変数を使用できます。--system-instruction-file より優先します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.SystemInstructionFile, "system-instruction-file", "", "上級者向け: 組み込みのレビュープロンプト全体を置き換えるシステムプロンプトのファイル (--system-instruction と同じ形式)。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.ExamplesFile, "examples", "", "プロンプトの先頭に追加するレビューの例 (差分の抜粋と、チームが期待するレビューの組) の YAML ファイル。AI がチームの求める口調と深さに合わせます。未指定の場合はリポジトリ内の .gemini-review/examples.yaml を使用します (--ignore-repo-prompt 指定時を除く)。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.RubricFile, "rubric", "", "release モードで採点するチームのリリース判定基準 (重み付きの評価項目としきい値) の YAML ファイル。総合スコアがしきい値を下回る場合は --fail-on の判定対象となる指摘事項を追加します。未指定の場合はリポジトリ内の .gemini-review/rubric.yaml を使用します (--ignore-repo-prompt 指定時を除く)。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.FailOn, "fail-on", "", "指定した深刻度 ('critical', 'high', 'medium', 'low') 以上の指摘事項がレビュー結果に含まれる場合、結果を出力・公開した上で終了コード 0 以外で終了します。CIのゲートとして使用します。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.VerifyFindings, "verify-findings", false, "レビュー結果の各指摘事項を、該当箇所の差分と照らして AI にもう一度確認させ、誤りと判定された指摘事項を結果から取り除きます (除外した指摘事項と理由はレポートの末尾に記載します)。指摘事項の数だけ API の呼び出しが増えます。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.SuggestPatches, "suggest-patches", false, "ファイルと行が特定された各指摘事項について、AI に unified diff 形式の修正案を生成させ、ローカルのクローンで 'git apply --check' により適用できることを確認できたものをレポートに含めます。修正案は差分で変更されたファイルに限ります。外部Gitコマンドを利用するアダプタが必要です。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.BaselineFile, "baseline", "", "チームが受け入れた指摘事項を記録したベースラインファイル。一致する指摘事項は再度報告せずに抑制し、--fail-on の判定対象から除きます。未指定の場合はリポジトリのルートの .gemini-review-baseline.json を使用します。ベースラインは baseline コマンドで生成します。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.IgnoreBaseline, "no-baseline", false, "ベースラインによる指摘事項の抑制を無効にし、すべての指摘事項を報告します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.Timezone, "timezone", "", "レポート・通知などに表示する日時のタイムゾーン (例: 'UTC', 'Asia/Tokyo', '+09:00')。未指定の場合はホストのタイムゾーン (環境変数 TZ) を使用します。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.MaxPromptTokens, "max-prompt-tokens", 0, "1回のリクエストで送信するプロンプトのトークン数の上限。指定すると送信前に countTokens API でトークン数を確認します。0 は無制限です。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.ParallelFiles, "parallel-files", 0, "2 以上を指定すると、差分をファイルごとに分割し、この数までのファイルを並行してレビューした上で、結果を1つのレポートに統合します。多数のファイルを変更した差分のレビュー時間を短縮します。0 または 1 は分割しません。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.OnBudgetExceeded, "on-budget-exceeded", config.BudgetRefuse, "プロンプトが --max-prompt-tokens を超えた場合の動作: 'refuse' (レビューを中止) または 'chunk' (差分をファイル単位に分割してレビュー)。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.Context, "context", config.ContextDiff, "差分に加えてプロンプトに含めるコンテキスト: 'diff' (差分のみ)、'full-files' (変更されたファイルの変更後の内容全体を周辺コードとして追加) または 'imports' (変更されたファイルがインポートしているリポジトリ内の宣言を追加。Go/TypeScript/Python)。カンマ区切りで複数指定できます (例: 'full-files,imports')。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.ContextMaxBytes, "context-max-bytes", defaultContextMaxBytes, "--context full-files / imports で追加する内容の、それぞれの合計サイズの上限 (バイト)。上限に収まらないものは省略します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.Temperature, "temperature", "", "生成時の温度 (0〜2)。'0.2' のように全モード共通の値、または 'release=0,detail=0.7' のようにモードごとの値を指定できます。未指定の場合は 0.1 です。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.TopP, "top-p", "", "生成時の top-p (0〜1)。--temperature と同じ形式でモードごとに指定できます。未指定の場合はモデルの既定値を使用します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.MaxOutputTokens, "max-output-tokens", "", "1回の応答で生成する最大トークン数。--temperature と同じ形式でモードごとに指定できます。未指定の場合はモデルの既定値を使用します。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.ImpactAnalysis, "impact-analysis", config.ImpactNone, "変更の影響範囲を求めるビルドグラフ: 'none' (解析しない)、'go' (go.mod 配下のパッケージのインポート関係) または 'bazel' (bazel query の rdeps)。影響を受けるパッケージ・ターゲットをプロンプトとレポートに追加します。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.BlastRadiusThreshold, "blast-radius-threshold", 0, "--impact-analysis で求めた影響範囲 (パッケージ・ターゲット数) がこの数を超える場合、--blast-radius-severity の深刻度の指摘事項を追加します。--fail-on と組み合わせてゲートとして使用します。0 は無効です。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.BlastRadiusSeverity, "blast-radius-severity", "high", "影響範囲が --blast-radius-threshold を超えた場合に追加する指摘事項の深刻度 ('critical', 'high', 'medium', 'low')。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.PRContext, "pr-context", config.PRContextNone, "フィーチャーブランチのオープンなプルリクエストを API で検索し、タイトル・説明・関連するイシューを変更の意図としてプロンプトに追加します: 'none' (追加しない)、'github'、'gitlab' または 'bitbucket'。認証には --pr-comment と同じ環境変数 (GITHUB_TOKEN、GITLAB_TOKEN、BITBUCKET_TOKEN など) を使用します。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.DebtScan, "debt-scan", false, "差分で追加された TODO / FIXME コメントとテストのスキップ (t.Skip, it.skip, @pytest.mark.skip, @Disabled など) を AI を使わずに検出し、種類ごとの件数をレポートに、各箇所を深刻度 LOW の指摘事項として追加します。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.DisableRedaction, "no-redact-secrets", false, "AI に送信する前に、差分とコンテキストに含まれる機密情報 (APIキー、秘密鍵、JWT、接続文字列のパスワード、高エントロピーの文字列など) をプレースホルダに置き換える組み込みのルールを無効にします。--redact-pii と --redact-patterns-file は引き続き適用されます。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.RedactPII, "redact-pii", false, "AI に送信する前に、差分とコンテキストに含まれる個人情報 (メールアドレス、電話番号) をプレースホルダに置き換えます。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.RedactPatternsFile, "redact-patterns-file", "", "追加でマスクする値の正規表現を1行に1つ記述したファイルのパス (空行と # で始まる行は無視)。(?P<value>...) のグループがある場合は、そのグループのみをマスクします。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.SessionFile, "session-file", "", "chat コマンドで使用する、最後に実行したレビューの結果と差分 (機密情報をマスクした後のもの) の保存先。レビューの実行時に保存し、chat コマンドで読み込みます。未指定の場合はユーザーのキャッシュディレクトリ (~/.cache/git-gemini-cli/last-review.json など) を使用します。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.Incremental, "incremental", false, "リポジトリ・ブランチごとに前回レビューしたコミットを記録し、2回目以降は前回のレビュー以降の新しいコミットの差分のみをレビューします。レポートの先頭に「前回のレビュー以降の変更」のセクションを追加します。リベースや force push で前回のコミットがブランチに含まれない場合は、ブランチ全体をレビューします。")
+	rootCmd.PersistentFlags().StringVar(&ReviewConfig.StateURI, "state-uri", "", "--incremental で前回レビューしたコミットを記録する状態ファイル (ローカルパス、gs://... または s3://...)。CI では実行ごとに環境が破棄されるため、クラウドストレージを指定してください。未指定の場合はユーザーのキャッシュディレクトリ (~/.cache/git-gemini-cli/review-state.json など) を使用します。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.MaxRetries, "max-retries", defaultMaxRetries, "AI の API がクォータ超過 (429) やサーバーエラー (5xx) を返した場合の最大再試行回数。0 を指定すると再試行しません。")
+	rootCmd.PersistentFlags().DurationVar(&ReviewConfig.RetryInitialBackoff, "retry-initial-backoff", defaultRetryInitialBackoff, "1回目の再試行までの待機時間の上限。以降は2倍ずつ増加し、その範囲でランダムに待機します (ジッター付き指数バックオフ)。")
+	rootCmd.PersistentFlags().DurationVar(&ReviewConfig.RetryMaxBackoff, "retry-max-backoff", defaultRetryMaxBackoff, "再試行までの待機時間の上限。API が指定した待機時間 (Retry-After) がこれを超える場合は、再試行せずにエラーとします。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.RateLimitRPM, "rate-limit-rpm", 0, "AI の呼び出しを1分あたりこの回数までに制限します (トークンバケット方式)。分割レビューや複数のレビューで共有され、大規模な実行が API のクォータ超過で途中終了するのを防ぎます。0 を指定すると制限しません。")
+	rootCmd.PersistentFlags().IntVar(&ReviewConfig.RateLimitTPM, "rate-limit-tpm", 0, "AI に送信するプロンプトを1分あたりこのトークン数 (バイト数からの概算) までに制限します。0 を指定すると制限しません。")
+	rootCmd.PersistentFlags().BoolVar(&ReviewConfig.IgnoreRepoPrompt, "ignore-repo-prompt", false, "レビュー対象リポジトリ内の .gemini-review/prompt*.md によるプロンプトの拡張・置換を無効にします。")
+
+	// ネットワーク設定
+	rootCmd.PersistentFlags().StringVar(&NetworkConfig.ProxyURL, "proxy", "", "Gemini API・Slack・ストレージへの接続に使用するプロキシのURL (http://, https://, socks5://, socks5h://)。未指定の場合は環境変数 HTTPS_PROXY 等に従います。")
+	rootCmd.PersistentFlags().StringVar(&NetworkConfig.NoProxy, "no-proxy", "", "プロキシを経由しないホストのカンマ区切りリスト (NO_PROXY 形式、例: 'localhost,.internal.example.com')。")
+	rootCmd.PersistentFlags().StringVar(&NetworkConfig.CABundle, "ca-bundle", "", "追加で信頼するCA証明書 (PEM形式) のパス。社内プロキシがTLSを中継する環境で使用します。")
+	rootCmd.PersistentFlags().StringVar(&NetworkConfig.ClientCert, "client-cert", "", "mTLS で提示するクライアント証明書 (PEM形式) のパス。社内PKIで保護された Slack 互換 Webhook や S3 互換エンドポイント向けです。")
+	rootCmd.PersistentFlags().StringVar(&NetworkConfig.ClientKey, "client-key", "", "--client-cert に対応する秘密鍵 (PEM形式) のパス。")
+	rootCmd.PersistentFlags().StringVar(&NetworkConfig.TLSMinVersion, "tls-min-version", "", "TLSの最小バージョン ('1.2' または '1.3')。")
+	rootCmd.PersistentFlags().StringVar(&S3Config.Endpoint, "s3-endpoint", "", "s3:// の接続先とする S3 互換ストレージ (MinIO, Cloudflare R2 など) のエンドポイントURL (例: 'https://minio.internal:9000')。未指定の場合は環境変数 AWS_ENDPOINT_URL_S3、どちらもない場合は Amazon S3 に接続します。")
+	rootCmd.PersistentFlags().BoolVar(&S3Config.PathStyle, "s3-path-style", false, "S3 互換ストレージにパス形式 (https://<エンドポイント>/<バケット>/<キー>) でアクセスします。MinIO などバケットごとのホスト名を使用できない環境で指定します。")
+	rootCmd.PersistentFlags().StringVar(&EncryptionConfig.GCSKMSKey, "gcs-kms-key", "", "GCS に書き込むレポート・マーカー・状態ファイルを暗号化する顧客管理の鍵 (CMEK)。Cloud KMS の鍵のリソース名 (projects/<プロジェクト>/locations/<ロケーション>/keyRings/<キーリング>/cryptoKeys/<鍵>) を指定します。")
+	rootCmd.PersistentFlags().StringVar(&EncryptionConfig.S3KMSKey, "s3-sse-kms-key", "", "S3 に書き込むレポート・マーカー・状態ファイルを SSE-KMS で暗号化する鍵の ID・ARN・エイリアス (alias/...)。'aws:kms' を指定すると AWS マネージドキー (aws/s3) を使用します。")
+
+	// repo-url は config などリポジトリを扱わないコマンドでは不要なため、initAppPreRunE で検証する
+	// feature-branch は差分を扱うコマンドでのみ必須のため、requireFeatureBranch で個別に検証する
+}
+
+// ErrRepoURLRequired は、リポジトリを扱うコマンドで --repo-url が指定されていない場合に返されるエラーです。
+var ErrRepoURLRequired = errors.New("このコマンドでは --repo-url (-u) の指定が必須です")
+
+// ErrFeatureBranchRequired は、差分を扱うコマンドで --feature-branch が指定されていない場合に返されるエラーです。
+var ErrFeatureBranchRequired = errors.New("このコマンドでは --feature-branch (-f) または --from-tag の指定が必須です")
+
+// ErrBatchUnsupported は、一括レビューに対応していないコマンドで複数のブランチが指定された場合に返されるエラーです。
+var ErrBatchUnsupported = errors.New("複数の --feature-branch および --all-branches-matching は generic・publish コマンドでのみ指定できます")
 
-	rootCmd.MarkPersistentFlagRequired("repo-url")
-	rootCmd.MarkPersistentFlagRequired("feature-branch")
+// requireFeatureBranch は、差分を扱うコマンドの実行前に --feature-branch が指定されているかを検証します。
+// タグ範囲 (--from-tag) が指定されている場合は、フィーチャーブランチは不要です。
+// 一括レビューに対応したコマンドは、isBatch で一括レビューを先に処理してから呼び出してください。
+func requireFeatureBranch() error {
+	if isBatch() {
+		return ErrBatchUnsupported
+	}
+	if ReviewConfig.FeatureBranch == "" && ReviewConfig.FromTag == "" {
+		return ErrFeatureBranchRequired
+	}
+	return nil
+}
+
+// isBatch は、複数のブランチを一括でレビューする指定がされているかを返します。
+func isBatch() bool {
+	return len(BatchConfig.Branches) > 1 || BatchConfig.Pattern != ""
+}
+
+// normalizeBranches は、--feature-branch に指定されたブランチの前後の空白を除去し、空の値と重複を取り除きます。
+func normalizeBranches(values []string) []string {
+	var branches []string
+	seen := make(map[string]bool)
+	for _, v := range values {
+		b := strings.TrimSpace(v)
+		if b == "" || seen[b] {
+			continue
+		}
+		seen[b] = true
+		branches = append(branches, b)
+	}
+	return branches
 }
 
 // --- エントリポイント ---
@@ -110,5 +439,15 @@ func Execute() {
 		initAppPreRunE,
 		genericCmd,
 		publishCmd,
+		explainCmd,
+		askCmd,
+		chatCmd,
+		applyFixesCmd,
+		baselineCmd,
+		compareModelsCmd,
+		promptCmd,
+		reviewersCmd,
+		serveCmd,
+		configCmd,
 	)
 }
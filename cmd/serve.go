@@ -0,0 +1,442 @@
+package cmd
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/netconfig"
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/repoweb"
+	"git-gemini-cli/internal/webhook"
+
+	"github.com/shouni/go-utils/urlpath"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// serveShutdownTimeout は、サーバーの停止時に、受信中のリクエストの完了を待つ時間の上限です。
+	serveShutdownTimeout = 30 * time.Second
+	// serveInterruptPollInterval は、中断シグナルの受信を確認する間隔です。
+	serveInterruptPollInterval = time.Second
+)
+
+// ServeFlags は serve コマンド固有のフラグを保持します。
+type ServeFlags struct {
+	Listen        string   // 待ち受けるアドレス
+	MetricsListen string   // HTTPクライアントのメトリクスを公開するアドレス (空の場合は公開しない)
+	WebhookSecret string   // GitHub の Webhook の署名を検証する共有シークレット
+	CloneProtocol string   // リポジトリのクローンに使用するURLの種類 ('ssh' または 'https')
+	PRComment     []string // ホスティングサービスごとのレビュー結果を投稿する形式
+	GitHubCheck   bool     // レビューしたコミットに GitHub のチェックランを作成
+	Workers       int      // 同時に実行するレビューの数
+
+	GitLabWebhookSecret    string // GitLab の Webhook のシークレットトークン
+	BitbucketWebhookSecret string // Bitbucket の Webhook の署名を検証する共有シークレット
+
+	PollInterval time.Duration // リポジトリのブランチの新しいコミットを確認する間隔 (0 の場合は確認しない)
+	PollRepos    []string      // 定期的に確認するリポジトリのURL (未指定の場合は --repo-url)
+	PollProvider string        // 確認したリポジトリのレビュー結果を投稿するホスティングサービス (未指定の場合はホスト名から判定)
+}
+
+var serveFlags ServeFlags
+
+// serveCmd は 'serve' サブコマンドを定義します。
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "GitHub・GitLab・Bitbucket の Webhook を受信し、プルリクエストを自動でレビューするサーバーを起動します。",
+	Long: `このコマンドは、GitHub の pull_request イベント、GitLab のマージリクエストのイベント、Bitbucket (Cloud / Server) のプルリクエストのイベントの Webhook を受信する HTTP サーバーを起動し、
+プルリクエストの作成・更新のたびに、ベースブランチとの差分をレビューして結果をプルリクエストに投稿します (セルフホストのレビューボット)。
+Webhook のパスは /webhook/github、/webhook/gitlab、/webhook/bitbucket です。署名 (GitLab の場合はシークレットトークン) を検証し、一致しないリクエストは拒否します。
+シークレットはサービスごとに --webhook-secret (GitHub)・--gitlab-webhook-secret・--bitbucket-webhook-secret (未指定の場合は環境変数 GITHUB_WEBHOOK_SECRET・GITLAB_WEBHOOK_SECRET・BITBUCKET_WEBHOOK_SECRET) で指定し、シークレットを設定したサービスのパスのみを受け付けます。
+レビューは Webhook への応答後にキューに追加し、--workers の数まで並行して実行します (同じリポジトリのレビューは1件ずつ実行し、実行待ちの古いコミットのレビューは新しいコミットのレビューで置き換えます)。モードやモデルなどのレビューの設定は、他のコマンドと同じフラグ・設定ファイルで指定します。
+--repo-url を指定した場合は、そのリポジトリのイベントのみを受け付けます。
+Webhook を設定できない環境では、--poll-interval を指定すると、リポジトリを定期的にフェッチし、ベースブランチに対する新しいコミットが追加されたブランチを検出してレビューします。`,
+	Example: `  GITHUB_WEBHOOK_SECRET=... GITHUB_TOKEN=... git-gemini-cli serve --listen :8080 --pr-comment github-review
+  GITLAB_WEBHOOK_SECRET=... GITLAB_TOKEN=... git-gemini-cli serve --pr-comment gitlab-review
+  git-gemini-cli serve --webhook-secret ... --github-check --clone-protocol https --fail-on high
+  git-gemini-cli serve -u git@git.example.com:org/repo.git --poll-interval 5m --all-branches-matching 'feature/*' --poll-provider gitlab`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{annotationNoRepo: "true"},
+	RunE:        serveCommand,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveFlags.Listen, "listen", ":8080", "Webhook を受信するアドレス (例: ':8080', '127.0.0.1:9000')。")
+	serveCmd.Flags().StringVar(&serveFlags.MetricsListen, "metrics-listen", "", "HTTPクライアントのメトリクス (接続先ホストごとのリクエスト数・エラー数・所要時間など) を /debug/vars で公開するアドレス (例: ':9090')。ホストを省略した場合は 127.0.0.1 で待ち受けます。未指定の場合は公開しません。")
+	serveCmd.Flags().StringVar(&serveFlags.WebhookSecret, "webhook-secret", "", "GitHub の Webhook に設定した共有シークレット。未指定の場合は環境変数 GITHUB_WEBHOOK_SECRET を使用します。シークレットがないサービスの Webhook は受け付けず、いずれのサービスのシークレットもない場合は起動しません。")
+	serveCmd.Flags().StringVar(&serveFlags.GitLabWebhookSecret, "gitlab-webhook-secret", "", "GitLab の Webhook に設定したシークレットトークン。未指定の場合は環境変数 GITLAB_WEBHOOK_SECRET を使用します。")
+	serveCmd.Flags().StringVar(&serveFlags.BitbucketWebhookSecret, "bitbucket-webhook-secret", "", "Bitbucket の Webhook に設定した共有シークレット。未指定の場合は環境変数 BITBUCKET_WEBHOOK_SECRET を使用します。")
+	serveCmd.Flags().StringVar(&serveFlags.CloneProtocol, "clone-protocol", "ssh", "レビューするリポジトリのクローンに使用するURL: 'ssh' (--ssh-key-path の鍵で認証) または 'https'。--repo-url を指定した場合はそのURLを使用します。")
+	serveCmd.Flags().StringSliceVar(&serveFlags.PRComment, "pr-comment", nil, "ホスティングサービスごとのレビュー結果の投稿形式をカンマ区切りで指定します (サービスごとに1つ)。'github' (プルリクエストのコメント。2回目以降は同じコメントを更新)、'github-review' (指摘事項を差分の該当行へのインラインコメントとして投稿)、'gitlab' (マージリクエストのコメント)、'gitlab-review' (差分の該当行へのディスカッション)、'bitbucket' (プルリクエストのコメント)、または 'none' (コメントを投稿しない。--github-check のみを使用する場合)。指定しないサービスは 'github'・'gitlab'・'bitbucket' で投稿します。認証には publish の --pr-comment と同じ環境変数 (GITHUB_TOKEN、GITLAB_TOKEN、BITBUCKET_TOKEN など) を使用します。")
+	serveCmd.Flags().DurationVar(&serveFlags.PollInterval, "poll-interval", 0, "Webhook の代わりに、この間隔 (例: '5m') でリポジトリをフェッチし、前回の確認以降にコミットが追加されたブランチ (--all-branches-matching に一致するもの。未指定の場合はすべて) をレビューします。起動時のブランチの状態を基準とするため、起動前のコミットはレビューしません。")
+	serveCmd.Flags().StringSliceVar(&serveFlags.PollRepos, "poll-repo", nil, "--poll-interval で確認するリポジトリのURL (複数指定可)。未指定の場合は --repo-url のリポジトリを確認します。--repo-url と同時には指定できません。")
+	serveCmd.Flags().StringVar(&serveFlags.PollProvider, "poll-provider", "", "--poll-interval で検出したブランチのレビュー結果を投稿するホスティングサービス: 'github'、'gitlab' または 'bitbucket'。未指定の場合はリポジトリのホスト名から判定します。ブランチのオープンなプルリクエストに投稿し、ない場合は投稿をスキップします。")
+	serveCmd.Flags().IntVar(&serveFlags.Workers, "workers", 2, "同時に実行するレビューの数。超えた分はキューで待機します。同じリポジトリのレビューは1件ずつ実行し、実行待ちの間に同じプルリクエスト (ブランチ) に新しいコミットが追加された場合は、古いコミットのレビューを省略して最新のコミットのみをレビューします。")
+	serveCmd.Flags().BoolVar(&serveFlags.GitHubCheck, "github-check", false, "GitHub のプルリクエストのレビューで、レビューしたコミットに GitHub のチェックラン ('Gemini Review') を作成します。結論は --fail-on のしきい値以上の指摘事項があれば failure、なければ success (--fail-on が未指定の場合は neutral) です。")
+}
+
+// --------------------------------------------------------------------------
+// コマンドの実行ロジック
+// --------------------------------------------------------------------------
+
+// serveCommand は、Webhook を受信するサーバーを起動し、中断シグナルを受信するまで待ち受けます。
+// 中断シグナルの受信時は新しい Webhook の受け付けを停止し、実行待ちのレビューを破棄して、実行中のレビューの完了 (または中断) を待って終了します。
+func serveCommand(cmd *cobra.Command, args []string) error {
+	cloneProtocol := strings.ToLower(strings.TrimSpace(serveFlags.CloneProtocol))
+	if cloneProtocol != "ssh" && cloneProtocol != "https" {
+		return fmt.Errorf("--clone-protocol には 'ssh' または 'https' を指定してください: %s", serveFlags.CloneProtocol)
+	}
+	commentFormats, err := parseServePRComment(serveFlags.PRComment)
+	if err != nil {
+		return err
+	}
+	if serveFlags.Workers < 1 {
+		return fmt.Errorf("--workers には 1 以上を指定してください: %d", serveFlags.Workers)
+	}
+
+	ctx := cmd.Context()
+	queue := webhook.NewQueue(serveFlags.Workers, func(req webhook.ReviewRequest) {
+		reviewPullRequest(ctx, req, commentFormats[req.Provider])
+	})
+	dispatch := queue.Enqueue
+
+	pollRepos, err := resolvePollRepos()
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	var paths []string
+	for _, p := range serveProviders {
+		secret := cmp.Or(*p.secretFlag, os.Getenv(p.secretEnv))
+		if secret == "" {
+			continue
+		}
+		path := "/webhook/" + p.name
+		mux.Handle(path, webhook.NewHandler(restrictRepo(p.parser(secret, cloneProtocol == "https")), dispatch))
+		paths = append(paths, path)
+	}
+	if len(paths) == 0 && len(pollRepos) == 0 {
+		return errors.New("Webhook の署名を検証するため、--webhook-secret・--gitlab-webhook-secret・--bitbucket-webhook-secret (または環境変数 GITHUB_WEBHOOK_SECRET・GITLAB_WEBHOOK_SECRET・BITBUCKET_WEBHOOK_SECRET) のいずれかを指定してください (Webhook を使用しない場合は --poll-interval を指定してください)")
+	}
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) { fmt.Fprintln(w, "ok") })
+
+	server := &http.Server{Addr: serveFlags.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	serveErr := make(chan error, 2)
+	go func() { serveErr <- server.ListenAndServe() }()
+	// メトリクスは Webhook と別のアドレス (既定では 127.0.0.1) でのみ公開する
+	var metricsServer *http.Server
+	if serveFlags.MetricsListen != "" {
+		metricsMux := http.NewServeMux()
+		metricsMux.Handle("/debug/vars", netconfig.MetricsHandler())
+		metricsServer = &http.Server{Addr: metricsListenAddr(serveFlags.MetricsListen), Handler: metricsMux, ReadHeaderTimeout: 10 * time.Second}
+		go func() { serveErr <- metricsServer.ListenAndServe() }()
+		slog.Info("HTTPクライアントのメトリクスの公開を開始しました。", "listen", metricsServer.Addr, "path", "/debug/vars")
+	}
+	slog.Info("Webhook の待ち受けを開始しました。", "listen", serveFlags.Listen, "paths", paths, "prComment", commentFormats, "githubCheck", serveFlags.GitHubCheck, "workers", serveFlags.Workers)
+
+	shutdown := shutdownRequested(ctx)
+	var poller sync.WaitGroup
+	if len(pollRepos) > 0 {
+		poller.Add(1)
+		go func() {
+			defer poller.Done()
+			pollBranches(ctx, shutdown, pollRepos, dispatch)
+		}()
+	}
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("Webhook のサーバーの起動に失敗しました: %w", err)
+	case <-shutdown:
+	}
+
+	slog.Info("中断シグナルを受信したため、Webhook の受け付けを停止し、実行中のレビューの完了を待ちます。")
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("Webhook のサーバーの停止中にエラーが発生しました。", "error", err)
+	}
+	if metricsServer != nil {
+		_ = metricsServer.Shutdown(shutdownCtx)
+	}
+	poller.Wait()
+	queue.Close()
+	slog.Info("Webhook のサーバーを停止しました。")
+	return nil
+}
+
+// metricsListenAddr は、--metrics-listen のアドレスを返します。ホストを省略した場合 (例: ':9090'、'9090') は 127.0.0.1 で待ち受けます。
+func metricsListenAddr(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		// ポート番号のみの指定
+		return net.JoinHostPort("127.0.0.1", addr)
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// serveProvider は、serve で Webhook を受信するホスティングサービスです。
+type serveProvider struct {
+	name       string  // Webhook のパス (/webhook/<name>) と、投稿先の既定の形式 (config.PRCommentGitHub など)
+	secretFlag *string // シークレットを指定するフラグの値 (環境変数より優先する)
+	secretEnv  string  // フラグが未指定の場合にシークレットを読み込む環境変数
+	parser     func(secret string, cloneHTTPS bool) webhook.Parser
+}
+
+// serveProviders は、serve が Webhook を受信するホスティングサービスの一覧です。
+var serveProviders = []serveProvider{
+	{name: config.PRCommentGitHub, secretFlag: &serveFlags.WebhookSecret, secretEnv: "GITHUB_WEBHOOK_SECRET", parser: webhook.GitHubParser},
+	{name: config.PRCommentGitLab, secretFlag: &serveFlags.GitLabWebhookSecret, secretEnv: "GITLAB_WEBHOOK_SECRET", parser: webhook.GitLabParser},
+	{name: config.PRCommentBitbucket, secretFlag: &serveFlags.BitbucketWebhookSecret, secretEnv: "BITBUCKET_WEBHOOK_SECRET", parser: webhook.BitbucketParser},
+}
+
+// serveCommentProviders は、--pr-comment の各形式と、その形式で投稿するホスティングサービスの対応表です。
+var serveCommentProviders = map[string]string{
+	config.PRCommentGitHub:       config.PRCommentGitHub,
+	config.PRCommentGitHubReview: config.PRCommentGitHub,
+	config.PRCommentGitLab:       config.PRCommentGitLab,
+	config.PRCommentGitLabReview: config.PRCommentGitLab,
+	config.PRCommentBitbucket:    config.PRCommentBitbucket,
+}
+
+// parseServePRComment は、serve の --pr-comment の値を、ホスティングサービスごとの投稿形式に変換します。
+// 指定がないサービスは、そのサービスの既定の形式 (1つのコメント) で投稿します。'none' の場合は、すべてのサービスでコメントを投稿しません。
+func parseServePRComment(values []string) (map[string]string, error) {
+	formats := make(map[string]string, len(serveProviders))
+	for _, p := range serveProviders {
+		formats[p.name] = p.name
+	}
+	seen := make(map[string]bool)
+	for _, v := range values {
+		format := strings.ToLower(strings.TrimSpace(v))
+		if format == "none" {
+			if len(values) > 1 {
+				return nil, errors.New("--pr-comment の 'none' は他の形式と同時に指定できません")
+			}
+			if !serveFlags.GitHubCheck {
+				return nil, errors.New("--pr-comment none の場合は --github-check を指定してください (レビュー結果の投稿先がありません)")
+			}
+			return map[string]string{}, nil
+		}
+		provider, ok := serveCommentProviders[format]
+		if !ok {
+			return nil, fmt.Errorf("--pr-comment には '%s'、'%s'、'%s'、'%s'、'%s' または 'none' を指定してください: %s",
+				config.PRCommentGitHub, config.PRCommentGitHubReview, config.PRCommentGitLab, config.PRCommentGitLabReview, config.PRCommentBitbucket, v)
+		}
+		if seen[provider] {
+			return nil, fmt.Errorf("--pr-comment には、ホスティングサービス '%s' の形式を1つだけ指定してください", provider)
+		}
+		seen[provider] = true
+		formats[provider] = format
+	}
+	return formats, nil
+}
+
+// reviewPullRequest は、Webhook で受信したプルリクエストをレビューし、結果をプルリクエストに投稿します。
+// レビューの設定はフラグ・設定ファイルの値を引き継ぎ、リポジトリとブランチ、レビューするコミットをイベントの値に置き換えます。
+// チェックラン (--github-check) は GitHub のプルリクエストの場合のみ作成します。
+func reviewPullRequest(ctx context.Context, req webhook.ReviewRequest, prComment string) {
+	githubCheck := serveFlags.GitHubCheck && req.Provider == config.PRCommentGitHub
+	if prComment == "" && !githubCheck {
+		slog.Info("レビュー結果の投稿先がないため、レビューをスキップします。", "provider", req.Provider, "repo", req.Repo, "number", req.Number)
+		return
+	}
+
+	cfg := serveReviewConfig(req.RepoURL)
+	cfg.BaseBranch = req.BaseBranch
+	cfg.FeatureBranch = req.FeatureBranch
+	cfg.FromTag, cfg.ToTag = "", ""
+	// 後続の push でブランチが進んでいても、イベントのコミットをレビューする
+	cfg.HeadCommit = req.HeadSHA
+
+	publishCfg := config.PublishConfig{
+		HttpClient:   cfg.APIClient,
+		ReviewConfig: cfg,
+		PRComment:    prComment,
+		PRNumber:     req.Number,
+		GitHubCheck:  githubCheck,
+	}
+	start := time.Now()
+	err := pipeline.ReviewAndComment(ctx, publishCfg)
+	switch {
+	case errors.Is(err, pipeline.ErrSkipReview):
+		slog.Info("レビュー対象の差分がないため、投稿をスキップしました。", "repo", req.Repo, "number", req.Number)
+	case errors.Is(err, interrupt.ErrInterrupted):
+		slog.Warn("中断シグナルを受信したため、レビューを途中で終了しました (結果は投稿しません)。", "repo", req.Repo, "number", req.Number)
+	case err != nil:
+		slog.Error("プルリクエストのレビューに失敗しました。", "repo", req.Repo, "number", req.Number, "error", err)
+	default:
+		slog.Info("プルリクエストのレビューが完了しました。", "repo", req.Repo, "number", req.Number, "duration", time.Since(start).Round(time.Second))
+	}
+}
+
+// serveReviewConfig は、repoURL のリポジトリをレビューする設定を返します。
+// --repo-url が指定されている場合は、そのリポジトリ (とクローン先) をそのまま使用します。
+func serveReviewConfig(repoURL string) config.ReviewConfig {
+	cfg := ReviewConfig
+	if cfg.RepoURL == "" {
+		cfg.RepoURL = repoURL
+		cfg.LocalPath = ""
+		if !cfg.Ephemeral {
+			cfg.LocalPath = urlpath.SanitizeURLToUniquePath(repoURL, baseRepoDirName)
+		}
+	}
+	return cfg
+}
+
+// pollRepo は、--poll-interval で定期的に確認するリポジトリです。
+type pollRepo struct {
+	url      string
+	provider string // レビュー結果を投稿するホスティングサービス (config.PRCommentGitHub など)
+}
+
+// resolvePollRepos は、--poll-interval で確認するリポジトリと、その投稿先のホスティングサービスを返します。
+// --poll-interval が指定されていない場合は nil を返します。
+func resolvePollRepos() ([]pollRepo, error) {
+	if serveFlags.PollInterval == 0 {
+		if len(serveFlags.PollRepos) > 0 {
+			return nil, errors.New("--poll-repo は --poll-interval と同時に指定してください")
+		}
+		return nil, nil
+	}
+	if serveFlags.PollInterval < time.Minute {
+		return nil, fmt.Errorf("--poll-interval には 1m 以上の間隔を指定してください: %s", serveFlags.PollInterval)
+	}
+
+	urls := normalizeURIs(serveFlags.PollRepos)
+	switch {
+	case len(urls) > 0 && ReviewConfig.RepoURL != "":
+		return nil, errors.New("--poll-repo と --repo-url は同時に指定できません")
+	case len(urls) == 0 && ReviewConfig.RepoURL == "":
+		return nil, errors.New("--poll-interval で確認するリポジトリを --poll-repo または --repo-url で指定してください")
+	case len(urls) == 0:
+		urls = []string{ReviewConfig.RepoURL}
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(serveFlags.PollProvider))
+	switch provider {
+	case "", config.PRCommentGitHub, config.PRCommentGitLab, config.PRCommentBitbucket:
+	default:
+		return nil, fmt.Errorf("--poll-provider には '%s'、'%s' または '%s' を指定してください: %s", config.PRCommentGitHub, config.PRCommentGitLab, config.PRCommentBitbucket, serveFlags.PollProvider)
+	}
+	repos := make([]pollRepo, 0, len(urls))
+	for _, u := range urls {
+		repo := pollRepo{url: u, provider: provider}
+		if repo.provider == "" {
+			host, _ := repoweb.HostPath(u)
+			for _, p := range serveProviders {
+				if strings.Contains(strings.ToLower(host), p.name) {
+					repo.provider = p.name
+				}
+			}
+			if repo.provider == "" {
+				return nil, fmt.Errorf("リポジトリ '%s' のホスティングサービスを判定できません。--poll-provider で指定してください", u)
+			}
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// pollBranches は、stop が閉じられるまで --poll-interval ごとに各リポジトリのブランチの最新のコミットを確認し、
+// 前回の確認以降にコミットが追加されたブランチ (新しく作成されたブランチを含む) のレビューを dispatch に渡します。
+// 起動直後の確認では、その時点のブランチの状態を記録するのみでレビューしません。
+func pollBranches(ctx context.Context, stop <-chan struct{}, repos []pollRepo, dispatch func(webhook.ReviewRequest)) {
+	pattern := cmp.Or(BatchConfig.Pattern, "*")
+	known := make(map[string]map[string]string, len(repos)) // リポジトリごとの、前回の確認時のブランチと最新のコミットハッシュ
+	ticker := time.NewTicker(serveFlags.PollInterval)
+	defer ticker.Stop()
+	slog.Info("リポジトリの定期的な確認を開始しました。", "repos", len(repos), "interval", serveFlags.PollInterval, "pattern", pattern)
+
+	for {
+		for _, repo := range repos {
+			cfg := serveReviewConfig(repo.url)
+			heads, err := pipeline.BranchHeads(ctx, cfg, pattern)
+			if err != nil {
+				slog.Error("リポジトリのブランチの確認に失敗しました。次回の確認で再試行します。", "repo", repo.url, "error", err)
+				continue
+			}
+			previous, initialized := known[repo.url]
+			known[repo.url] = heads
+			if !initialized {
+				slog.Info("リポジトリのブランチの状態を記録しました。以降に追加されたコミットをレビューします。", "repo", repo.url, "branches", len(heads))
+				continue
+			}
+			_, name := repoweb.HostPath(repo.url)
+			for branch, head := range heads {
+				if previous[branch] == head {
+					continue
+				}
+				slog.Info("新しいコミットが追加されたブランチを検出しました。", "repo", repo.url, "branch", branch, "commit", head)
+				dispatch(webhook.ReviewRequest{
+					Provider:      repo.provider,
+					Repo:          name,
+					RepoURL:       repo.url,
+					BaseBranch:    cfg.BaseBranch,
+					FeatureBranch: branch,
+					HeadSHA:       head,
+				})
+			}
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// restrictRepo は、--repo-url が指定されている場合に、そのリポジトリ以外のイベントを対象外とする Parser を返します。
+// リポジトリは、SSH と HTTPS のURLの違いを無視してホストとパスで比較します。
+func restrictRepo(parse webhook.Parser) webhook.Parser {
+	if ReviewConfig.RepoURL == "" {
+		return parse
+	}
+	wantHost, wantPath := repoweb.HostPath(ReviewConfig.RepoURL)
+	return func(header http.Header, body []byte) (*webhook.ReviewRequest, error) {
+		req, err := parse(header, body)
+		if err != nil || req == nil {
+			return req, err
+		}
+		if host, path := repoweb.HostPath(req.RepoURL); !strings.EqualFold(host, wantHost) || !strings.EqualFold(path, wantPath) {
+			slog.Warn("--repo-url 以外のリポジトリの Webhook のため、レビューしません。", "repo", req.Repo)
+			return nil, nil
+		}
+		return req, nil
+	}
+}
+
+// shutdownRequested は、中断シグナルを受信するか ctx が終了した時点で閉じるチャネルを返します。
+// 1回目の中断シグナルでは context はキャンセルされないため (interrupt.Install)、一定間隔で中断要求を確認します。
+func shutdownRequested(ctx context.Context) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(serveInterruptPollInterval)
+		defer ticker.Stop()
+		for !interrupt.Requested(ctx) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return done
+}
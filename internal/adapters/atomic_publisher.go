@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"path"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// atomicTempPrefix は、公開先と同じ場所に書き込む一時的なオブジェクトのファイル名の接頭辞です。
+const atomicTempPrefix = ".tmp-"
+
+// AtomicPublisher は、レポートを公開先と同じ場所の一時的なオブジェクトに書き込み、書き込みが完了してからサーバー側のコピーで公開先に反映するデコレータです。
+// 公開先のURI (一覧やマニフェストからリンクされる固定のURLなど) では、途中まで書き込まれたレポートや、失敗した書き込みの内容が読まれることはありません。
+// publisher.Publisher インターフェースを実装します。
+type AtomicPublisher struct {
+	next  publisher.Publisher
+	store objectstore.Store // 一時的なオブジェクトのコピーと削除に使用 (objectstore.Copier と objectstore.Lister を実装していること)
+}
+
+// NewAtomicPublisher は、next で一時的なオブジェクトに書き込み、store で公開先にコピーする Publisher を返します。
+// store が objectstore.Copier を実装していない場合は objectstore.ErrCopyUnsupported を返します。
+func NewAtomicPublisher(next publisher.Publisher, store objectstore.Store) (*AtomicPublisher, error) {
+	if _, ok := store.(objectstore.Copier); !ok {
+		return nil, objectstore.ErrCopyUnsupported
+	}
+	return &AtomicPublisher{next: next, store: store}, nil
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (p *AtomicPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	tmpURI, err := atomicTempURI(uri)
+	if err != nil {
+		return err
+	}
+	// 成功・失敗にかかわらず一時的なオブジェクトは削除する (キャンセルされた場合も削除できるよう、ctx のキャンセルを引き継がない)
+	defer p.removeTemp(context.WithoutCancel(ctx), tmpURI)
+
+	if err := p.next.Publish(ctx, tmpURI, data); err != nil {
+		return err
+	}
+	if err := p.store.(objectstore.Copier).Copy(ctx, tmpURI, uri); err != nil {
+		return fmt.Errorf("一時的なオブジェクトから公開先への反映に失敗しました: %w", err)
+	}
+	slog.Debug("一時的なオブジェクトから公開先にレポートを反映しました。", "temp", tmpURI, "uri", uri)
+	return nil
+}
+
+// removeTemp は、一時的なオブジェクトを削除します。削除に失敗しても公開の結果には影響しないため、警告のみを記録します。
+func (p *AtomicPublisher) removeTemp(ctx context.Context, tmpURI string) {
+	lister, ok := p.store.(objectstore.Lister)
+	if !ok {
+		slog.Warn("使用中のストレージはオブジェクトの削除に対応していないため、一時的なオブジェクトが残ります。", "uri", tmpURI)
+		return
+	}
+	if err := lister.Delete(ctx, tmpURI); err != nil {
+		slog.Warn("一時的なオブジェクトの削除に失敗しました。", "uri", tmpURI, "error", err)
+	}
+}
+
+// atomicTempURI は、公開先と同じ場所に置く一時的なオブジェクトのURIを返します (例: reviews/result.html → reviews/.tmp-1a2b3c4d-result.html)。
+// 拡張子から形式を判定する Publisher があるため、拡張子は公開先と同じにします。同時に公開する他の実行と衝突しないよう、ランダムな値を含めます。
+func atomicTempURI(uri string) (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("一時的なオブジェクトの名前の生成に失敗しました: %w", err)
+	}
+	dir, name := path.Split(uri)
+	return dir + atomicTempPrefix + hex.EncodeToString(b[:]) + "-" + name, nil
+}
@@ -0,0 +1,35 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// AzureBlobPublisher は、レビュー結果を HTML に変換して Azure Blob Storage に保存する publisher.Publisher の実装です。
+type AzureBlobPublisher struct {
+	store    objectstore.Store
+	renderer *ReportRenderer
+}
+
+// NewAzureBlobPublisher は AzureBlobPublisher の新しいインスタンスを作成します。
+// store には objectstore.AzureStore (または objectstore.WithAttributes で属性を設定したもの) を指定します。
+func NewAzureBlobPublisher(store objectstore.Store, renderer *ReportRenderer) *AzureBlobPublisher {
+	return &AzureBlobPublisher{store: store, renderer: renderer}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+// uri には az://container/blob または https://<account>.blob.core.windows.net/container/blob を指定します。
+func (p *AzureBlobPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	page, err := p.renderer.HTML(ctx, data)
+	if err != nil {
+		return err
+	}
+	if err := p.store.Write(ctx, uri, page, htmlContentType); err != nil {
+		return fmt.Errorf("Azure Blob Storage への公開に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package adapters
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"git-gemini-cli/internal/repoweb"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+const (
+	// bitbucketCloudHost と bitbucketCloudAPIURL は、Bitbucket Cloud のホストと API のベースURLです。
+	bitbucketCloudHost   = "bitbucket.org"
+	bitbucketCloudAPIURL = "https://api.bitbucket.org/2.0"
+)
+
+// BitbucketClient は、プルリクエストへのコメントなどに使用する Bitbucket の REST API のクライアントです。
+// Bitbucket Cloud (API 2.0) と Bitbucket Server / Data Center (REST API 1.0) の両方に対応します。
+type BitbucketClient struct {
+	apiURL        string
+	project, repo string // Cloud の場合はワークスペースとリポジトリのスラッグ、Server の場合はプロジェクトキーとリポジトリのスラッグ
+	server        bool   // Bitbucket Server / Data Center の場合 true
+	authorization string
+	client        httpkit.ClientInterface
+}
+
+// NewBitbucketClient は、httpClient で repoURL のリポジトリを操作する BitbucketClient を返します。
+// ホストが bitbucket.org の場合は Bitbucket Cloud、それ以外は Bitbucket Server / Data Center (https://<ホスト>/rest/api/1.0) として扱います。
+// API のベースURLは環境変数 BITBUCKET_API_URL で変更できます。
+// 認証には、環境変数 BITBUCKET_TOKEN (アクセストークン) または BITBUCKET_USERNAME と BITBUCKET_APP_PASSWORD (アプリパスワード・個人のトークン) を使用します。
+func NewBitbucketClient(httpClient httpkit.ClientInterface, repoURL string) (*BitbucketClient, error) {
+	host, repoPath := repoweb.HostPath(repoURL)
+	// Bitbucket Server の HTTPS のクローンURLは /scm/<プロジェクト>/<リポジトリ> の形式
+	repoPath = strings.TrimPrefix(repoPath, "scm/")
+	project, repo, ok := strings.Cut(repoPath, "/")
+	if host == "" || !ok || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("リポジトリのURL '%s' から Bitbucket のプロジェクトとリポジトリを取得できません", repoURL)
+	}
+
+	c := &BitbucketClient{
+		project: project,
+		repo:    repo,
+		server:  host != bitbucketCloudHost,
+		client:  httpClient,
+	}
+	c.apiURL = bitbucketCloudAPIURL
+	if c.server {
+		c.apiURL = "https://" + host + "/rest/api/1.0"
+	}
+	c.apiURL = strings.TrimRight(cmp.Or(os.Getenv("BITBUCKET_API_URL"), c.apiURL), "/")
+
+	switch user, password := os.Getenv("BITBUCKET_USERNAME"), os.Getenv("BITBUCKET_APP_PASSWORD"); {
+	case os.Getenv("BITBUCKET_TOKEN") != "":
+		c.authorization = "Bearer " + os.Getenv("BITBUCKET_TOKEN")
+	case user != "" && password != "":
+		req := http.Request{Header: http.Header{}}
+		req.SetBasicAuth(user, password)
+		c.authorization = req.Header.Get("Authorization")
+	default:
+		return nil, fmt.Errorf("Bitbucket API の認証には環境変数 BITBUCKET_TOKEN、または BITBUCKET_USERNAME と BITBUCKET_APP_PASSWORD を設定してください")
+	}
+	return c, nil
+}
+
+// Repo は、操作対象のリポジトリを "project/repo" の形式で返します。
+func (c *BitbucketClient) Repo() string {
+	return c.project + "/" + c.repo
+}
+
+// Server は、Bitbucket Server / Data Center の場合に true を返します。
+func (c *BitbucketClient) Server() bool {
+	return c.server
+}
+
+// BitbucketPullRequest は、Bitbucket のプルリクエストのうち使用する項目です (Cloud と Server の両方の項目を含みます)。
+type BitbucketPullRequest struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Destination struct {
+		Branch struct {
+			Name string `json:"name"`
+		} `json:"branch"`
+	} `json:"destination"` // Cloud
+	ToRef struct {
+		DisplayID string `json:"displayId"`
+	} `json:"toRef"` // Server
+	Links struct {
+		HTML struct {
+			Href string `json:"href"`
+		} `json:"html"` // Cloud
+		Self []struct {
+			Href string `json:"href"`
+		} `json:"self"` // Server
+	} `json:"links"`
+}
+
+// UnmarshalJSON は、Cloud と Server でリンクの形式 (links.self がオブジェクトか配列か) が異なるため、Server の形式の場合のみ links.self を読み込みます。
+func (pr *BitbucketPullRequest) UnmarshalJSON(data []byte) error {
+	type plain BitbucketPullRequest
+	var v struct {
+		plain
+		Links struct {
+			HTML struct {
+				Href string `json:"href"`
+			} `json:"html"`
+			Self json.RawMessage `json:"self"`
+		} `json:"links"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*pr = BitbucketPullRequest(v.plain)
+	pr.Links.HTML.Href = v.Links.HTML.Href
+	if bytes.HasPrefix(bytes.TrimSpace(v.Links.Self), []byte("[")) {
+		_ = json.Unmarshal(v.Links.Self, &pr.Links.Self)
+	}
+	return nil
+}
+
+// WebURL は、プルリクエストの Web ページのURLを返します。
+func (pr BitbucketPullRequest) WebURL() string {
+	if pr.Links.HTML.Href != "" || len(pr.Links.Self) == 0 {
+		return pr.Links.HTML.Href
+	}
+	return pr.Links.Self[0].Href
+}
+
+// FindPullRequest は、head のブランチからのオープンなプルリクエストを返します。複数ある場合は base をマージ先とするものを優先します。
+// 見つからない場合は nil を返します。id が 0 より大きい場合は、ブランチによらずその ID のプルリクエストを返します。
+func (c *BitbucketClient) FindPullRequest(ctx context.Context, id int, head, base string) (*BitbucketPullRequest, error) {
+	if id > 0 {
+		var pr BitbucketPullRequest
+		path := fmt.Sprintf("pullrequests/%d", id)
+		if c.server {
+			path = fmt.Sprintf("pull-requests/%d", id)
+		}
+		if err := c.Do(ctx, http.MethodGet, path, nil, &pr); err != nil {
+			return nil, fmt.Errorf("プルリクエスト #%d の取得に失敗しました: %w", id, err)
+		}
+		return &pr, nil
+	}
+
+	head, base = trimBranchPrefix(head), trimBranchPrefix(base)
+	if head == "" {
+		return nil, nil
+	}
+	var page struct {
+		Values []BitbucketPullRequest `json:"values"`
+	}
+	var path string
+	if c.server {
+		path = "pull-requests?" + url.Values{"state": {"OPEN"}, "direction": {"OUTGOING"}, "at": {"refs/heads/" + head}, "limit": {"100"}}.Encode()
+	} else {
+		path = "pullrequests?" + url.Values{"q": {fmt.Sprintf(`source.branch.name="%s" AND state="OPEN"`, head)}, "pagelen": {"50"}}.Encode()
+	}
+	if err := c.Do(ctx, http.MethodGet, path, nil, &page); err != nil {
+		return nil, fmt.Errorf("ブランチ '%s' のプルリクエストの検索に失敗しました: %w", head, err)
+	}
+	if len(page.Values) == 0 {
+		return nil, nil
+	}
+	for i, pr := range page.Values {
+		if cmp.Or(pr.Destination.Branch.Name, pr.ToRef.DisplayID) == base {
+			return &page.Values[i], nil
+		}
+	}
+	return &page.Values[0], nil
+}
+
+// Do は、リポジトリの API (Cloud: /repositories/{workspace}/{repo}/、Server: /projects/{key}/repos/{repo}/ に続くパス) にリクエストを送信し、
+// 応答の JSON を out に格納します。body が nil でない場合は JSON として送信します。out が nil の場合は応答の本文を読み捨てます。
+func (c *BitbucketClient) Do(ctx context.Context, method, repoPath string, body, out any) error {
+	endpoint := fmt.Sprintf("%s/repositories/%s/%s/%s", c.apiURL, url.PathEscape(c.project), url.PathEscape(c.repo), repoPath)
+	if c.server {
+		endpoint = fmt.Sprintf("%s/projects/%s/repos/%s/%s", c.apiURL, url.PathEscape(c.project), url.PathEscape(c.repo), repoPath)
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	req.Header.Set("Authorization", c.authorization)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("Bitbucket API がエラーを返しました (status %d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("Bitbucket API の応答の解析に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package adapters
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+)
+
+const (
+	// bitbucketCommentMaxChars は、Bitbucket のコメント本文の文字数の上限です (Bitbucket Server / Data Center の上限に合わせる)。
+	bitbucketCommentMaxChars = 32000
+	// bitbucketReviewMarker は、このツールが投稿したレビューのコメントを識別するマーカーの接頭辞です。
+	// Bitbucket は Markdown 内の HTML を表示してしまうため、表示されないリンク参照定義の形式にします (例: [//]: # (git-gemini-cli:review:detail))。
+	bitbucketReviewMarker = "[//]: # (git-gemini-cli:review:"
+)
+
+// BitbucketPRCommenter は、Bitbucket Cloud または Bitbucket Server / Data Center のフィーチャーブランチのオープンなプルリクエストに、
+// レビュー結果を1つのコメントとして投稿する PRCommenter の実装です。以前に投稿したコメントがある場合は、その内容を更新します。
+type BitbucketPRCommenter struct {
+	client *BitbucketClient
+	prID   int // 0 より大きい場合は、ブランチから検索せずにこの ID のプルリクエストに投稿する
+}
+
+// NewBitbucketPRCommenter は、client でプルリクエスト prID (0 の場合はフィーチャーブランチから検索) に投稿する BitbucketPRCommenter を返します。
+func NewBitbucketPRCommenter(client *BitbucketClient, prID int) *BitbucketPRCommenter {
+	return &BitbucketPRCommenter{client: client, prID: prID}
+}
+
+// bitbucketComment は、Cloud と Server のコメントのうち使用する項目です。
+type bitbucketComment struct {
+	ID      int64  `json:"id"`
+	Version int    `json:"version"` // Server の更新時に必要な楽観的ロックのバージョン
+	Text    string `json:"text"`    // Server の本文
+	Content struct {
+		Raw string `json:"raw"` // Cloud の本文
+	} `json:"content"`
+}
+
+// body は、コメントの本文を返します。
+func (c bitbucketComment) body() string {
+	return cmp.Or(c.Content.Raw, c.Text)
+}
+
+// Comment は PRCommenter インターフェースの実装です。
+// オープンなプルリクエストが見つからない場合は、投稿をスキップします。
+func (c *BitbucketPRCommenter) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	pr, err := c.client.FindPullRequest(ctx, c.prID, cfg.FeatureBranch, cfg.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if pr == nil {
+		slog.Info("フィーチャーブランチのオープンなプルリクエストが見つからないため、コメントの投稿をスキップします。", "repo", c.client.Repo(), "branch", cfg.FeatureBranch)
+		return nil
+	}
+	id, server := pr.ID, c.client.Server()
+
+	marker := bitbucketReviewMarker + cfg.ReviewMode + ")\n"
+	body := buildReviewComment(marker, publicURL, review, cfg, bitbucketCommentMaxChars)
+	existing, err := c.findComment(ctx, id, marker)
+	if err != nil {
+		return err
+	}
+
+	var req any = map[string]any{"content": map[string]string{"raw": body}}
+	if server {
+		req = map[string]any{"text": body}
+	}
+	switch {
+	case existing != nil && server:
+		req = map[string]any{"text": body, "version": existing.Version}
+		err = c.client.Do(ctx, http.MethodPut, fmt.Sprintf("pull-requests/%d/comments/%d", id, existing.ID), req, nil)
+	case existing != nil:
+		err = c.client.Do(ctx, http.MethodPut, fmt.Sprintf("pullrequests/%d/comments/%d", id, existing.ID), req, nil)
+	case server:
+		err = c.client.Do(ctx, http.MethodPost, fmt.Sprintf("pull-requests/%d/comments", id), req, nil)
+	default:
+		err = c.client.Do(ctx, http.MethodPost, fmt.Sprintf("pullrequests/%d/comments", id), req, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("プルリクエスト #%d へのコメントの投稿に失敗しました: %w", id, err)
+	}
+	slog.Info("レビュー結果を Bitbucket のプルリクエストのコメントに投稿しました。", "pr", pr.WebURL(), "updated", existing != nil)
+	return nil
+}
+
+// findComment は、プルリクエストのコメントから marker を含む (以前に投稿した) コメントを探します。見つからない場合は nil を返します。
+// Server ではコメントの一覧の API がないため、アクティビティからコメントを探します。
+func (c *BitbucketPRCommenter) findComment(ctx context.Context, id int, marker string) (*bitbucketComment, error) {
+	server := c.client.Server()
+	for page := 0; page < apiListMaxPages; page++ {
+		var resp struct {
+			Values []struct {
+				bitbucketComment
+				Comment *bitbucketComment `json:"comment"` // Server のアクティビティ
+			} `json:"values"`
+			Next       string `json:"next"`       // Cloud
+			IsLastPage bool   `json:"isLastPage"` // Server
+		}
+		path := fmt.Sprintf("pullrequests/%d/comments?pagelen=100&page=%d", id, page+1)
+		if server {
+			path = fmt.Sprintf("pull-requests/%d/activities?limit=100&start=%d", id, page*100)
+		}
+		if err := c.client.Do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+			return nil, fmt.Errorf("プルリクエスト #%d のコメントの取得に失敗しました: %w", id, err)
+		}
+		for _, v := range resp.Values {
+			comment := v.bitbucketComment
+			if v.Comment != nil {
+				comment = *v.Comment
+			}
+			if strings.Contains(comment.body(), marker) {
+				return &comment, nil
+			}
+		}
+		if (server && resp.IsLastPage) || (!server && resp.Next == "") || len(resp.Values) == 0 {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,143 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// branchPushMaxAttempts は、他の実行が同じブランチにプッシュしたためにプッシュが拒否された場合に、フェッチからやり直す最大回数です。
+	branchPushMaxAttempts = 3
+	// defaultCommitterName と defaultCommitterEmail は、Git の設定にユーザーが設定されていない場合 (CI など) に使用するコミットの作成者です。
+	defaultCommitterName  = "git-gemini-cli"
+	defaultCommitterEmail = "git-gemini-cli@users.noreply.github.com"
+)
+
+// ErrBranchCommitUnsupported は、使用中の GitService がブランチへのファイルのコミットとプッシュに対応していないことを示すエラーです。
+var ErrBranchCommitUnsupported = errors.New("使用中のGitアダプタはブランチへのコミットとプッシュに対応していません")
+
+// BranchCommitter は、リモートのブランチにファイルを追加するコミットを作成し、プッシュできる GitService が追加で実装するインターフェースです。
+// レポートをドキュメント用のブランチ (gh-pages など) に保存するために使用します。利用側は型アサーションで対応状況を確認してください。
+type BranchCommitter interface {
+	// CommitFile は、リモートの branch の最新のコミットに path のファイルを data で追加 (または置き換え) したコミットを作成してプッシュし、
+	// コミットのハッシュを返します。branch がリモートに存在しない場合は、履歴を持たない新しいブランチとして作成します。
+	// ワーキングツリーとインデックスは変更しません。内容が変わらない場合はコミットせずに現在のコミットを返します。
+	CommitFile(ctx context.Context, branch, path string, data []byte, message string) (string, error)
+}
+
+// CommitFile は BranchCommitter インターフェースの実装です。
+// レビューに使用するワーキングツリーを変更しないよう、一時的なインデックスファイルと Git の低レベルのコマンド (hash-object / write-tree / commit-tree) でコミットを作成します。
+// 他の実行と同時にプッシュして拒否された場合は、フェッチからやり直します。
+func (ga *LocalGitAdapter) CommitFile(ctx context.Context, branch, path string, data []byte, message string) (string, error) {
+	var lastErr error
+	for attempt := 1; attempt <= branchPushMaxAttempts; attempt++ {
+		commit, pushed, err := ga.commitFileOnce(ctx, branch, path, data, message)
+		if err == nil || !pushed {
+			return commit, err
+		}
+		lastErr = err
+		slog.Info("ブランチへのプッシュが拒否されたため、フェッチからやり直します。", "branch", branch, "attempt", attempt, "error", err)
+	}
+	return "", lastErr
+}
+
+// commitFileOnce は、CommitFile の1回の試行です。pushed は、プッシュの段階で失敗した (再試行で解消する可能性がある) かを示します。
+func (ga *LocalGitAdapter) commitFileOnce(ctx context.Context, branch, path string, data []byte, message string) (commit string, pushed bool, err error) {
+	remoteRef := "refs/remotes/origin/" + branch
+	heads, err := ga.gitOutput(ctx, nil, "", "ls-remote", "--heads", "origin", "refs/heads/"+branch)
+	if err != nil {
+		return "", false, fmt.Errorf("リモートのブランチ '%s' の確認に失敗しました: %w", branch, err)
+	}
+	var parent string
+	if heads != "" {
+		if _, err := ga.gitOutput(ctx, nil, "", "fetch", "origin", "+refs/heads/"+branch+":"+remoteRef); err != nil {
+			return "", false, fmt.Errorf("ブランチ '%s' のフェッチに失敗しました: %w", branch, err)
+		}
+		if parent, err = ga.gitOutput(ctx, nil, "", "rev-parse", remoteRef); err != nil {
+			return "", false, fmt.Errorf("ブランチ '%s' のコミットの取得に失敗しました: %w", branch, err)
+		}
+	}
+
+	// ワーキングツリーのインデックスを変更しないよう、一時的なインデックスファイルでツリーを作成する
+	indexFile, err := os.CreateTemp("", "git-gemini-cli-index-*")
+	if err != nil {
+		return "", false, fmt.Errorf("一時的なインデックスファイルの作成に失敗しました: %w", err)
+	}
+	indexFile.Close()
+	defer os.Remove(indexFile.Name())
+	env := []string{"GIT_INDEX_FILE=" + indexFile.Name()}
+
+	readTree := []string{"read-tree", "--empty"}
+	if parent != "" {
+		readTree = []string{"read-tree", parent}
+	}
+	if _, err := ga.gitOutput(ctx, env, "", readTree...); err != nil {
+		return "", false, fmt.Errorf("ブランチ '%s' のツリーの読み込みに失敗しました: %w", branch, err)
+	}
+	blob, err := ga.gitOutput(ctx, env, string(data), "hash-object", "-w", "--stdin")
+	if err != nil {
+		return "", false, fmt.Errorf("ファイル '%s' の保存に失敗しました: %w", path, err)
+	}
+	if _, err := ga.gitOutput(ctx, env, "", "update-index", "--add", "--cacheinfo", "100644,"+blob+","+filepath.ToSlash(path)); err != nil {
+		return "", false, fmt.Errorf("ファイル '%s' の追加に失敗しました: %w", path, err)
+	}
+	tree, err := ga.gitOutput(ctx, env, "", "write-tree")
+	if err != nil {
+		return "", false, fmt.Errorf("ツリーの作成に失敗しました: %w", err)
+	}
+	if parent != "" {
+		if parentTree, err := ga.gitOutput(ctx, nil, "", "rev-parse", parent+"^{tree}"); err == nil && parentTree == tree {
+			slog.Info("ブランチのファイルの内容が変わらないため、コミットを作成しません。", "branch", branch, "path", path)
+			return parent, false, nil
+		}
+	}
+
+	commitTree := []string{"commit-tree", tree, "-F", "-"}
+	if parent != "" {
+		commitTree = append(commitTree, "-p", parent)
+	}
+	if commit, err = ga.gitOutput(ctx, ga.committerEnv(ctx), message, commitTree...); err != nil {
+		return "", false, fmt.Errorf("コミットの作成に失敗しました: %w", err)
+	}
+	if _, err := ga.gitOutput(ctx, nil, "", "push", "origin", commit+":refs/heads/"+branch); err != nil {
+		return "", true, fmt.Errorf("ブランチ '%s' へのプッシュに失敗しました: %w", branch, err)
+	}
+	slog.Info("ブランチにコミットをプッシュしました。", "branch", branch, "path", path, "commit", commit)
+	return commit, false, nil
+}
+
+// committerEnv は、Git の設定にユーザー (user.name / user.email) が設定されていない場合に、コミットの作成者を指定する環境変数を返します。
+func (ga *LocalGitAdapter) committerEnv(ctx context.Context) []string {
+	var env []string
+	if name, _ := ga.gitOutput(ctx, nil, "", "config", "user.name"); name == "" && os.Getenv("GIT_AUTHOR_NAME") == "" {
+		env = append(env, "GIT_AUTHOR_NAME="+defaultCommitterName, "GIT_COMMITTER_NAME="+defaultCommitterName)
+	}
+	if email, _ := ga.gitOutput(ctx, nil, "", "config", "user.email"); email == "" && os.Getenv("GIT_AUTHOR_EMAIL") == "" {
+		env = append(env, "GIT_AUTHOR_EMAIL="+defaultCommitterEmail, "GIT_COMMITTER_EMAIL="+defaultCommitterEmail)
+	}
+	return env
+}
+
+// gitOutput は、環境変数 env を追加し、stdin を標準入力に渡して Gitコマンドを実行し、標準出力を返します。
+// 失敗が想定される確認 (設定の有無など) にも使用するため、失敗時にエラーログを出力しません。
+func (ga *LocalGitAdapter) gitOutput(ctx context.Context, env []string, stdin string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = ga.LocalPath
+	cmd.Env = append(ga.getEnvWithSSH(), env...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	slog.Debug("Gitコマンドを実行中", "dir", cmd.Dir, "args", args)
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s に失敗しました: %w. 出力:\n%s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
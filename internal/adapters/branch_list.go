@@ -0,0 +1,34 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+)
+
+// ErrBranchListUnsupported は、使用中の GitService がリモートブランチの一覧取得に対応していないことを示すエラーです。
+var ErrBranchListUnsupported = errors.New("使用中のGitアダプタはリモートブランチの一覧取得に対応していません")
+
+// BranchLister は、リモート 'origin' のブランチ一覧を取得できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type BranchLister interface {
+	// ListRemoteBranches は、リモート追跡ブランチの名前 ("origin/" を除いたもの) を名前順に返します。HEAD は含めません。
+	ListRemoteBranches(ctx context.Context) ([]string, error)
+}
+
+// MatchBranches は、branches のうち glob パターン (path.Match 形式、例: 'release/*') に一致するものを名前順に返します。
+func MatchBranches(branches []string, pattern string) ([]string, error) {
+	var matched []string
+	for _, b := range branches {
+		ok, err := path.Match(pattern, b)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, b)
+		}
+	}
+	sort.Strings(matched)
+	return matched, nil
+}
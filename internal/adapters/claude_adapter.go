@@ -0,0 +1,115 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/usage"
+)
+
+// ErrAnthropicAPIKeyNotSet は、環境変数 ANTHROPIC_API_KEY が設定されていないことを示すエラーです。
+var ErrAnthropicAPIKeyNotSet = errors.New("環境変数 ANTHROPIC_API_KEY が設定されていません")
+
+const (
+	// anthropicVersion は、Messages API の呼び出しに指定する API バージョンです。
+	anthropicVersion = "2023-06-01"
+	// defaultClaudeMaxTokens は、最大出力トークン数が指定されていない場合に使用する値です。
+	// Messages API では max_tokens が必須のため、レビュー結果が途切れない程度の値を既定にします。
+	defaultClaudeMaxTokens int32 = 8192
+)
+
+// ClaudeAdapter は、Anthropic の Messages API を呼び出して Claude でレビューするアダプタです。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type ClaudeAdapter struct {
+	api    jsonAPI
+	model  string
+	params map[string]config.GenerationParams
+}
+
+// claudeRequest は、Messages API のリクエストです。
+type claudeRequest struct {
+	Model       string        `json:"model"`
+	MaxTokens   int32         `json:"max_tokens"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+}
+
+// claudeResponse は、Messages API のレスポンスのうち、使用する項目です。
+type claudeResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// NewClaudeAdapter は、環境変数 ANTHROPIC_API_KEY を使用して ClaudeAdapter を初期化します。
+// baseURL には "https://api.anthropic.com" のような API のベースURLを指定します。httpClient が nil の場合は既定のクライアントを使用します。
+func NewClaudeAdapter(httpClient *http.Client, baseURL, model string, params map[string]config.GenerationParams) (*ClaudeAdapter, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, ErrAnthropicAPIKeyNotSet
+	}
+	if baseURL == "" {
+		return nil, errors.New("Anthropic API のベースURLが指定されていません")
+	}
+
+	header := http.Header{
+		"X-Api-Key":         {apiKey},
+		"Anthropic-Version": {anthropicVersion},
+	}
+	return &ClaudeAdapter{
+		api:    newJSONAPI("Anthropic API", httpClient, strings.TrimRight(baseURL, "/")+"/v1/messages", header),
+		model:  model,
+		params: params,
+	}, nil
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (a *ClaudeAdapter) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	mode, params := paramsFor(ctx, a.params)
+	req := claudeRequest{
+		Model:       a.model,
+		MaxTokens:   params.MaxOutputTokens,
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		Temperature: params.Temperature,
+		TopP:        params.TopP,
+	}
+	if req.MaxTokens <= 0 {
+		req.MaxTokens = defaultClaudeMaxTokens
+	}
+	// 新しいモデルでは温度と top-p を同時に指定できないため、top-p のみが指定された場合は既定の温度を適用しない
+	if req.Temperature == nil && req.TopP == nil {
+		temperature := defaultTemperature
+		req.Temperature = &temperature
+	}
+
+	var msg claudeResponse
+	if err := a.api.post(ctx, a.model, req, &msg); err != nil {
+		return "", err
+	}
+	usage.ReportTokens(ctx, msg.Usage.InputTokens, msg.Usage.OutputTokens)
+	var text strings.Builder
+	for _, c := range msg.Content {
+		if c.Type == "text" {
+			text.WriteString(c.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", ErrEmptyResponse
+	}
+	if msg.StopReason == "max_tokens" {
+		slog.Warn("生成されたトークン数が上限に達したため、レビュー結果が途中で途切れている可能性があります。", "mode", mode, "maxOutputTokens", req.MaxTokens)
+	}
+	return text.String(), nil
+}
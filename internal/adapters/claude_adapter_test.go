@@ -0,0 +1,81 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestClaudeAdapter は、Anthropic の Messages API へのリクエストと、応答・エラーの扱いを検証します。
+func TestClaudeAdapter(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		response   string
+		want       string
+		wantStatus int
+		wantWait   time.Duration
+	}{
+		{
+			name:     "テキストのブロックを連結する",
+			status:   http.StatusOK,
+			response: `{"content":[{"type":"text","text":"LG"},{"type":"tool_use"},{"type":"text","text":"TM"}],"stop_reason":"end_turn","usage":{"input_tokens":10,"output_tokens":2}}`,
+			want:     "LGTM",
+		},
+		{
+			name:       "529 (過負荷) は再試行できる StatusError を返す",
+			status:     529,
+			retryAfter: "12",
+			response:   `{"type":"error","error":{"type":"overloaded_error"}}`,
+			wantStatus: 529,
+			wantWait:   12 * time.Second,
+		},
+		{
+			name:       "400 は再試行しない",
+			status:     http.StatusBadRequest,
+			response:   `{"type":"error","error":{"type":"invalid_request_error"}}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ANTHROPIC_API_KEY", "test-key")
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v1/messages" {
+					t.Errorf("path = %q, want /v1/messages", r.URL.Path)
+				}
+				if got := r.Header.Get("x-api-key"); got != "test-key" {
+					t.Errorf("x-api-key = %q, want test-key", got)
+				}
+				if got := r.Header.Get("anthropic-version"); got != anthropicVersion {
+					t.Errorf("anthropic-version = %q, want %q", got, anthropicVersion)
+				}
+				var req claudeRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("リクエストの解析に失敗しました: %v", err)
+				}
+				if req.Model != "claude-test" || req.MaxTokens != defaultClaudeMaxTokens {
+					t.Errorf("request = %+v, want model claude-test, max_tokens %d", req, defaultClaudeMaxTokens)
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			a, err := NewClaudeAdapter(server.Client(), server.URL+"/", "claude-test", nil)
+			if err != nil {
+				t.Fatalf("NewClaudeAdapter() error = %v", err)
+			}
+			got, err := a.ReviewCodeDiff(context.Background(), "prompt")
+			checkJSONAPIResult(t, got, err, tt.want, tt.wantStatus, tt.wantWait)
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package adapters
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Commit は、ブランチ間に含まれる1コミットの情報です。
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string // 作成者のメールアドレス
+	Date    time.Time
+	Subject string
+	Body    string
+}
+
+// ShortHash は、コミットハッシュの先頭7文字を返します。
+func (c Commit) ShortHash() string {
+	if len(c.Hash) <= 7 {
+		return c.Hash
+	}
+	return c.Hash[:7]
+}
+
+// CommitLogProvider は、ブランチ間のコミットログを取得できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type CommitLogProvider interface {
+	// GetCommitLog は、baseBranch から到達できず featureBranch から到達できるコミット (base..feature) を新しい順に返します。
+	GetCommitLog(ctx context.Context, baseBranch, featureBranch string) ([]Commit, error)
+}
+
+// splitMessage は、コミットメッセージを件名 (1行目) と本文に分割します。
+func splitMessage(message string) (string, string) {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return strings.TrimSpace(subject), strings.TrimSpace(body)
+}
@@ -0,0 +1,280 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"time"
+
+	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// ErrCommitLogUnsupported は、使用中の GitService がコミットログの取得に対応していないことを示すエラーです。
+var ErrCommitLogUnsupported = errors.New("使用中のGitアダプタはコミットログの取得に対応していません")
+
+// FallbackGitService は、プライマリの GitService (go-git) が失敗した場合に、
+// フォールバック先の GitService (外部gitコマンド) へ自動で切り替えるデコレータです。
+// 切り替え時には、それまでに完了した手順 (クローン、フェッチ) をフォールバック先で再実行してから、失敗した操作を再試行します。
+// coreAdapters.GitService、CommitLogProvider、FileContentProvider、FileHistoryProvider、RefResolver、BranchLister および WorktreeProvider インターフェースを実装します。
+type FallbackGitService struct {
+	primary  coreAdapters.GitService
+	fallback func() coreAdapters.GitService
+
+	active       coreAdapters.GitService
+	switched     bool
+	repoURL      string
+	cloneDone    bool
+	fetchDone    bool
+	primaryLabel string
+}
+
+// ExternalGitAvailable は、外部の 'git' コマンドが PATH 上に存在するかを返します。
+func ExternalGitAvailable() bool {
+	_, err := exec.LookPath("git")
+	return err == nil
+}
+
+// NewFallbackGitService は、primary を使用し、失敗時に fallback で生成した GitService へ切り替える GitService を返します。
+// fallback は切り替えが必要になった時点で初めて呼び出されます。
+func NewFallbackGitService(primary coreAdapters.GitService, primaryLabel string, fallback func() coreAdapters.GitService) *FallbackGitService {
+	return &FallbackGitService{
+		primary:      primary,
+		fallback:     fallback,
+		active:       primary,
+		primaryLabel: primaryLabel,
+	}
+}
+
+// switchToFallback は、フォールバック先の GitService に切り替え、完了済みの手順を再実行します。
+// 既に切り替え済みの場合や、コンテキストが中断されている場合は元のエラーを返します。
+func (fs *FallbackGitService) switchToFallback(ctx context.Context, op string, cause error) error {
+	if fs.switched || ctx.Err() != nil {
+		return cause
+	}
+
+	slog.Warn("Gitアダプタで未対応または失敗した操作があったため、外部Gitコマンド利用アダプタに切り替えます。--use-external-git-command を指定すると、この切り替えを省略できます。",
+		"adapter", fs.primaryLabel, "operation", op, "error", cause)
+
+	fs.active = fs.fallback()
+	fs.switched = true
+
+	if fs.cloneDone {
+		if err := fs.active.CloneOrUpdate(ctx, fs.repoURL); err != nil {
+			return fmt.Errorf("フォールバック先でのリポジトリのセットアップに失敗しました (元のエラー: %v): %w", cause, err)
+		}
+	}
+	if fs.fetchDone {
+		if err := fs.active.Fetch(ctx); err != nil {
+			return fmt.Errorf("フォールバック先でのフェッチに失敗しました (元のエラー: %v): %w", cause, err)
+		}
+	}
+	return nil
+}
+
+// --- coreAdapters.GitService インターフェースの実装 ---
+
+// CloneOrUpdate はリポジトリをクローンするか、既に存在する場合は更新を試みます。
+func (fs *FallbackGitService) CloneOrUpdate(ctx context.Context, repositoryURL string) error {
+	fs.repoURL = repositoryURL
+	err := fs.active.CloneOrUpdate(ctx, repositoryURL)
+	if err != nil {
+		if switchErr := fs.switchToFallback(ctx, "clone", err); switchErr != nil {
+			return switchErr
+		}
+		err = fs.active.CloneOrUpdate(ctx, repositoryURL)
+	}
+	if err == nil {
+		fs.cloneDone = true
+	}
+	return err
+}
+
+// Fetch はリモートから最新の変更を取得します。
+func (fs *FallbackGitService) Fetch(ctx context.Context) error {
+	err := fs.active.Fetch(ctx)
+	if err != nil {
+		if switchErr := fs.switchToFallback(ctx, "fetch", err); switchErr != nil {
+			return switchErr
+		}
+		err = fs.active.Fetch(ctx)
+	}
+	if err == nil {
+		fs.fetchDone = true
+	}
+	return err
+}
+
+// GetCodeDiff は指定された2つのブランチ間の差分を取得します。
+func (fs *FallbackGitService) GetCodeDiff(ctx context.Context, baseBranch, featureBranch string) (string, error) {
+	diff, err := fs.active.GetCodeDiff(ctx, baseBranch, featureBranch)
+	if err != nil {
+		if switchErr := fs.switchToFallback(ctx, "diff", err); switchErr != nil {
+			return "", switchErr
+		}
+		return fs.active.GetCodeDiff(ctx, baseBranch, featureBranch)
+	}
+	return diff, nil
+}
+
+// CheckRemoteBranchExists は指定されたブランチがリモートに存在するか確認します。
+func (fs *FallbackGitService) CheckRemoteBranchExists(ctx context.Context, branch string) (bool, error) {
+	exists, err := fs.active.CheckRemoteBranchExists(ctx, branch)
+	if err != nil {
+		if switchErr := fs.switchToFallback(ctx, "check-branch", err); switchErr != nil {
+			return false, switchErr
+		}
+		return fs.active.CheckRemoteBranchExists(ctx, branch)
+	}
+	return exists, nil
+}
+
+// Cleanup はクリーンアップを実行します。クリーンアップの失敗ではフォールバックしません。
+func (fs *FallbackGitService) Cleanup(ctx context.Context) error {
+	return fs.active.Cleanup(ctx)
+}
+
+// GetCommitLog は、使用中の GitService がコミットログの取得に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて取得します。
+func (fs *FallbackGitService) GetCommitLog(ctx context.Context, baseBranch, featureBranch string) ([]Commit, error) {
+	if provider, ok := fs.active.(CommitLogProvider); ok {
+		return provider.GetCommitLog(ctx, baseBranch, featureBranch)
+	}
+
+	if err := fs.switchToFallback(ctx, "commit-log", ErrCommitLogUnsupported); err != nil {
+		return nil, err
+	}
+	if provider, ok := fs.active.(CommitLogProvider); ok {
+		return provider.GetCommitLog(ctx, baseBranch, featureBranch)
+	}
+	return nil, ErrCommitLogUnsupported
+}
+
+// GetFileContent は、使用中の GitService がファイル内容の取得に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて取得します。
+func (fs *FallbackGitService) GetFileContent(ctx context.Context, ref, path string) ([]byte, error) {
+	if provider, ok := fs.active.(FileContentProvider); ok {
+		return provider.GetFileContent(ctx, ref, path)
+	}
+
+	if err := fs.switchToFallback(ctx, "file-content", ErrFileContentUnsupported); err != nil {
+		return nil, err
+	}
+	if provider, ok := fs.active.(FileContentProvider); ok {
+		return provider.GetFileContent(ctx, ref, path)
+	}
+	return nil, ErrFileContentUnsupported
+}
+
+// ListFiles は、使用中の GitService がファイル一覧の取得に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて取得します。
+func (fs *FallbackGitService) ListFiles(ctx context.Context, ref, dir string) ([]string, error) {
+	if provider, ok := fs.active.(FileContentProvider); ok {
+		return provider.ListFiles(ctx, ref, dir)
+	}
+
+	if err := fs.switchToFallback(ctx, "list-files", ErrFileContentUnsupported); err != nil {
+		return nil, err
+	}
+	if provider, ok := fs.active.(FileContentProvider); ok {
+		return provider.ListFiles(ctx, ref, dir)
+	}
+	return nil, ErrFileContentUnsupported
+}
+
+// ListTree は、使用中の GitService がファイル一覧の取得に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて取得します。
+func (fs *FallbackGitService) ListTree(ctx context.Context, ref string) ([]string, error) {
+	if provider, ok := fs.active.(FileContentProvider); ok {
+		return provider.ListTree(ctx, ref)
+	}
+
+	if err := fs.switchToFallback(ctx, "list-tree", ErrFileContentUnsupported); err != nil {
+		return nil, err
+	}
+	if provider, ok := fs.active.(FileContentProvider); ok {
+		return provider.ListTree(ctx, ref)
+	}
+	return nil, ErrFileContentUnsupported
+}
+
+// GetFileHistory は、使用中の GitService がファイルの変更履歴の取得に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて取得します。
+func (fs *FallbackGitService) GetFileHistory(ctx context.Context, ref string, paths []string, since time.Time) ([]FileCommit, error) {
+	if provider, ok := fs.active.(FileHistoryProvider); ok {
+		return provider.GetFileHistory(ctx, ref, paths, since)
+	}
+
+	if err := fs.switchToFallback(ctx, "file-history", ErrFileHistoryUnsupported); err != nil {
+		return nil, err
+	}
+	if provider, ok := fs.active.(FileHistoryProvider); ok {
+		return provider.GetFileHistory(ctx, ref, paths, since)
+	}
+	return nil, ErrFileHistoryUnsupported
+}
+
+// ResolveRef は、使用中の GitService が参照の解決に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて解決します。
+func (fs *FallbackGitService) ResolveRef(ctx context.Context, ref string) (string, error) {
+	if resolver, ok := fs.active.(RefResolver); ok {
+		return resolver.ResolveRef(ctx, ref)
+	}
+
+	if err := fs.switchToFallback(ctx, "resolve-ref", ErrRefResolveUnsupported); err != nil {
+		return "", err
+	}
+	if resolver, ok := fs.active.(RefResolver); ok {
+		return resolver.ResolveRef(ctx, ref)
+	}
+	return "", ErrRefResolveUnsupported
+}
+
+// ListRemoteBranches は、使用中の GitService がリモートブランチの一覧取得に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて取得します。
+func (fs *FallbackGitService) ListRemoteBranches(ctx context.Context) ([]string, error) {
+	if lister, ok := fs.active.(BranchLister); ok {
+		return lister.ListRemoteBranches(ctx)
+	}
+
+	if err := fs.switchToFallback(ctx, "list-branches", ErrBranchListUnsupported); err != nil {
+		return nil, err
+	}
+	if lister, ok := fs.active.(BranchLister); ok {
+		return lister.ListRemoteBranches(ctx)
+	}
+	return nil, ErrBranchListUnsupported
+}
+
+// CheckPatch は、使用中の GitService がパッチの適用確認に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて確認します。
+func (fs *FallbackGitService) CheckPatch(ctx context.Context, ref, patch string) error {
+	if checker, ok := fs.active.(PatchChecker); ok {
+		return checker.CheckPatch(ctx, ref, patch)
+	}
+
+	if err := fs.switchToFallback(ctx, "check-patch", ErrPatchCheckUnsupported); err != nil {
+		return err
+	}
+	if checker, ok := fs.active.(PatchChecker); ok {
+		return checker.CheckPatch(ctx, ref, patch)
+	}
+	return ErrPatchCheckUnsupported
+}
+
+// AddWorktree は、使用中の GitService が一時的な worktree の作成に対応していれば委譲し、
+// 対応していなければフォールバック先に切り替えて作成します。
+func (fs *FallbackGitService) AddWorktree(ctx context.Context, ref string) (string, func(), error) {
+	if provider, ok := fs.active.(WorktreeProvider); ok {
+		return provider.AddWorktree(ctx, ref)
+	}
+
+	if err := fs.switchToFallback(ctx, "add-worktree", ErrWorktreeUnsupported); err != nil {
+		return "", nil, err
+	}
+	if provider, ok := fs.active.(WorktreeProvider); ok {
+		return provider.AddWorktree(ctx, ref)
+	}
+	return "", nil, ErrWorktreeUnsupported
+}
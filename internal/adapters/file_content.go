@@ -0,0 +1,20 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrFileContentUnsupported は、使用中の GitService がファイル内容の取得に対応していないことを示すエラーです。
+var ErrFileContentUnsupported = errors.New("使用中のGitアダプタはファイル内容の取得に対応していません")
+
+// FileContentProvider は、指定した参照時点のファイル内容を取得できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type FileContentProvider interface {
+	// GetFileContent は、ブランチ (または "refs/" で始まる完全な参照名) の時点でのファイル内容を返します。
+	GetFileContent(ctx context.Context, ref, path string) ([]byte, error)
+	// ListFiles は、ブランチの時点でディレクトリ直下に存在するファイルのパス (リポジトリルートからの相対パス) を返します。
+	ListFiles(ctx context.Context, ref, dir string) ([]string, error)
+	// ListTree は、ブランチの時点でリポジトリに存在するすべてのファイルのパス (リポジトリルートからの相対パス) を返します。
+	ListTree(ctx context.Context, ref string) ([]string, error)
+}
@@ -0,0 +1,24 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFileHistoryUnsupported は、使用中の GitService がファイルの変更履歴の取得に対応していないことを示すエラーです。
+var ErrFileHistoryUnsupported = errors.New("使用中のGitアダプタはファイルの変更履歴の取得に対応していません")
+
+// FileCommit は、指定したファイルのいずれかを変更した1コミットの情報です。
+type FileCommit struct {
+	Commit
+	Files []string // このコミットで変更された、指定したファイルのパス
+}
+
+// FileHistoryProvider は、ファイルの変更履歴を取得できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type FileHistoryProvider interface {
+	// GetFileHistory は、ref から到達できるコミットのうち、since 以降に paths のいずれかを変更したものを新しい順に返します。
+	// マージコミットは含めません。
+	GetFileHistory(ctx context.Context, ref string, paths []string, since time.Time) ([]FileCommit, error)
+}
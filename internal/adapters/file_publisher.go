@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// FilePublisher は、レビュー結果を HTML に変換してローカルファイルに保存する publisher.Publisher の実装です。
+// クラウドストレージを持たない環境でも publish のパイプライン (変換・重複排除・Slack通知) を使用するためのものです。
+type FilePublisher struct {
+	renderer *ReportRenderer
+}
+
+// NewFilePublisher は FilePublisher の新しいインスタンスを作成します。
+func NewFilePublisher(renderer *ReportRenderer) *FilePublisher {
+	return &FilePublisher{renderer: renderer}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+// uri には file:// のURIまたはローカルのパスを指定します。書き込み途中の内容を読まれないよう、一時ファイル経由で置き換えます。
+func (p *FilePublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	page, err := p.renderer.HTML(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	path := objectstore.LocalPath(uri)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("保存先ディレクトリの作成に失敗しました (%s): %w", filepath.Dir(path), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(page); err != nil {
+		tmp.Close()
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	// os.CreateTemp は 0600 で作成するため、通常のファイルと同じ権限にする
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
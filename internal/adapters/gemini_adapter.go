@@ -0,0 +1,106 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/usage"
+
+	"google.golang.org/genai"
+)
+
+// defaultTemperature は、温度が指定されていない場合に使用する値です。
+// コアライブラリのアダプタと同じ値にし、温度以外のパラメータのみを指定した場合に結果の傾向が変わらないようにします。
+const defaultTemperature float32 = 0.1
+
+// ErrEmptyResponse は、AI の API から本文のない応答が返されたことを示すエラーです。
+var ErrEmptyResponse = errors.New("AI の API から空の応答が返されました")
+
+// reviewModeKey は、context にレビューモードを格納するためのキーです。
+type reviewModeKey struct{}
+
+// WithReviewMode は、AI呼び出しの対象となるレビューモードを設定した context を返します。
+// CLI 側のアダプタ (GeminiAdapter, OpenAIAdapter, OllamaAdapter, ClaudeAdapter) は、この値に応じてモードごとの生成パラメータを使い分けます。
+func WithReviewMode(ctx context.Context, mode string) context.Context {
+	return context.WithValue(ctx, reviewModeKey{}, mode)
+}
+
+// paramsFor は、context に設定されたレビューモードと、そのモードに適用する生成パラメータを返します。
+// モードごとの値がない場合は "" のキーの値を返します。
+func paramsFor(ctx context.Context, params map[string]config.GenerationParams) (string, config.GenerationParams) {
+	mode, _ := ctx.Value(reviewModeKey{}).(string)
+	p, ok := params[mode]
+	if !ok {
+		p = params[""]
+	}
+	return mode, p
+}
+
+// GeminiAdapter は、生成パラメータ (温度、top-p、最大出力トークン数) を指定して Gemini API を呼び出すアダプタです。
+// コアライブラリのアダプタは温度が固定のため、生成パラメータが指定された場合にのみ使用します。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type GeminiAdapter struct {
+	client *genai.Client
+	model  string
+	params map[string]config.GenerationParams // キーはレビューモード ("" は共通の値)
+}
+
+// NewGeminiAdapter は、環境変数 GEMINI_API_KEY を使用して GeminiAdapter を初期化します。
+// httpClient には API の呼び出しに使用するクライアントを指定します (nil の場合は既定のクライアント)。
+// params にはレビューモードごとの生成パラメータを指定し、"" のキーにはモードが特定できない場合の値を指定します。
+func NewGeminiAdapter(ctx context.Context, httpClient *http.Client, model string, params map[string]config.GenerationParams) (*GeminiAdapter, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return nil, ErrGeminiAPIKeyNotSet
+	}
+
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini クライアントの初期化に失敗しました: %w", err)
+	}
+
+	return &GeminiAdapter{client: client, model: model, params: params}, nil
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (a *GeminiAdapter) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	mode, params := paramsFor(ctx, a.params)
+
+	genConfig := &genai.GenerateContentConfig{
+		Temperature:     params.Temperature,
+		TopP:            params.TopP,
+		MaxOutputTokens: params.MaxOutputTokens,
+	}
+	if genConfig.Temperature == nil {
+		temperature := defaultTemperature
+		genConfig.Temperature = &temperature
+	}
+	slog.Debug("生成パラメータを指定して Gemini API を呼び出します。", "model", a.model, "mode", mode,
+		"temperature", *genConfig.Temperature, "topP", params.TopP, "maxOutputTokens", params.MaxOutputTokens)
+
+	resp, err := a.client.Models.GenerateContent(ctx, a.model, genai.Text(prompt), genConfig)
+	if err != nil {
+		return "", fmt.Errorf("Gemini API の呼び出しに失敗しました (model: %s): %w", a.model, err)
+	}
+
+	if resp.UsageMetadata != nil {
+		usage.ReportTokens(ctx, int(resp.UsageMetadata.PromptTokenCount), int(resp.UsageMetadata.CandidatesTokenCount))
+	}
+	text := resp.Text()
+	if len(resp.Candidates) > 0 && resp.Candidates[0].FinishReason == genai.FinishReasonMaxTokens {
+		slog.Warn("生成されたトークン数が上限に達したため、レビュー結果が途中で途切れている可能性があります。", "mode", mode, "maxOutputTokens", params.MaxOutputTokens)
+	}
+	if text == "" {
+		return "", ErrEmptyResponse
+	}
+	return text, nil
+}
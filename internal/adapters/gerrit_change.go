@@ -0,0 +1,173 @@
+package adapters
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/repoweb"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+const (
+	// gerritMessageMaxChars は、Gerrit の変更メッセージの文字数の上限です (既定の change.commentSizeLimit に合わせる)。
+	gerritMessageMaxChars = 16000
+	// gerritMessageTag は、変更メッセージに付けるタグです。autogenerated: で始まるタグは、Gerrit の UI でボットのメッセージとして絞り込めます。
+	gerritMessageTag = "autogenerated:git-gemini-cli"
+)
+
+// GerritReviewer は、Gerrit の変更 (change) に、レビュー結果を変更メッセージとして投稿する PRCommenter の実装です。
+// label が空でない場合は、--fail-on のしきい値以上の指摘事項があれば -1、なければ +1 をそのラベルに投票します。
+type GerritReviewer struct {
+	apiURL   string
+	project  string
+	username string
+	password string
+	change   int // 0 より大きい場合は、フィーチャーブランチの参照によらずこの番号の変更に投稿する
+	label    string
+	client   httpkit.ClientInterface
+}
+
+// NewGerritReviewer は、httpClient で repoURL のプロジェクトの変更 change (0 の場合はフィーチャーブランチの refs/changes/ の参照から求める) に投稿する GerritReviewer を返します。
+// API のベースURLは環境変数 GERRIT_API_URL を優先し、未指定の場合は https://<リポジトリのホスト> とします。
+// 認証には環境変数 GERRIT_USERNAME と GERRIT_HTTP_PASSWORD (Gerrit の設定画面で生成する HTTP パスワード) を使用します。
+func NewGerritReviewer(httpClient httpkit.ClientInterface, repoURL string, change int, label string) (*GerritReviewer, error) {
+	host, project := repoweb.HostPath(repoURL)
+	// HTTP のクローンURLは、認証付きの場合 /a/<プロジェクト> の形式
+	project = strings.TrimPrefix(project, "a/")
+	if host == "" || project == "" {
+		return nil, fmt.Errorf("リポジトリのURL '%s' から Gerrit のプロジェクトを取得できません", repoURL)
+	}
+	username, password := os.Getenv("GERRIT_USERNAME"), os.Getenv("GERRIT_HTTP_PASSWORD")
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("Gerrit REST API の認証には環境変数 GERRIT_USERNAME と GERRIT_HTTP_PASSWORD を設定してください")
+	}
+	return &GerritReviewer{
+		apiURL:   strings.TrimRight(cmp.Or(os.Getenv("GERRIT_API_URL"), "https://"+host), "/"),
+		project:  project,
+		username: username,
+		password: password,
+		change:   change,
+		label:    strings.TrimSpace(label),
+		client:   httpClient,
+	}, nil
+}
+
+// Comment は PRCommenter インターフェースの実装です。
+// 投稿先の変更を特定できない場合 (--pr-number が未指定で、フィーチャーブランチが refs/changes/ の参照でない場合) は、投稿をスキップします。
+func (g *GerritReviewer) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	change, revision := g.change, "current"
+	if change <= 0 {
+		var ok bool
+		if change, revision, ok = parseChangeRef(cfg.FeatureBranch); !ok {
+			slog.Info("フィーチャーブランチが Gerrit の変更の参照 (refs/changes/) ではないため、変更メッセージの投稿をスキップします。--pr-number で変更の番号を指定してください。", "project", g.project, "branch", cfg.FeatureBranch)
+			return nil
+		}
+	}
+
+	// Gerrit の変更メッセージは編集できないため、マーカーは付けない
+	message := strings.TrimLeft(buildReviewComment("", publicURL, review, cfg, gerritMessageMaxChars), "\n")
+	req := map[string]any{"message": message, "tag": gerritMessageTag}
+	vote := 0
+	if g.label != "" {
+		var err error
+		if vote, err = gateVote(ctx, review, cfg.FailOn); err != nil {
+			return err
+		}
+		req["labels"] = map[string]int{g.label: vote}
+	}
+
+	path := fmt.Sprintf("changes/%s~%d/revisions/%s/review", url.PathEscape(g.project), change, revision)
+	if err := g.do(ctx, http.MethodPost, path, req); err != nil {
+		return fmt.Errorf("Gerrit の変更 %d への変更メッセージの投稿に失敗しました: %w", change, err)
+	}
+	slog.Info("レビュー結果を Gerrit の変更メッセージとして投稿しました。", "project", g.project, "change", change, "revision", revision, "label", g.label, "vote", vote)
+	return nil
+}
+
+// gateVote は、指摘事項に failOn の深刻度以上のものがあれば -1、なければ +1 を返します。
+// 指摘事項は context に格納されたもの (findings.NewContext) を優先し、ない場合は review から抽出します。
+func gateVote(ctx context.Context, review, failOn string) (int, error) {
+	threshold, err := findings.ParseSeverity(failOn)
+	if err != nil {
+		return 0, err
+	}
+	_, list := findings.Split(review)
+	if ctxList, ok := findings.FromContext(ctx); ok {
+		list = ctxList
+	}
+	if findings.CheckThreshold(list, threshold) != nil {
+		return -1, nil
+	}
+	return 1, nil
+}
+
+// parseChangeRef は、Gerrit の変更の参照 (refs/changes/<番号の下2桁>/<変更の番号>/<パッチセット>) から変更の番号とパッチセットを返します。
+// パッチセットを省略した参照 (refs/changes/34/1234) の場合は、最新のパッチセット ("current") を返します。
+func parseChangeRef(ref string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(strings.TrimSpace(ref), "origin/"), "refs/changes/")
+	if !ok {
+		return 0, "", false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, "", false
+	}
+	change, err := strconv.Atoi(parts[1])
+	if err != nil || change <= 0 {
+		return 0, "", false
+	}
+	if len(parts) == 3 {
+		if _, err := strconv.Atoi(parts[2]); err != nil {
+			return 0, "", false
+		}
+		return change, parts[2], true
+	}
+	return change, "current", true
+}
+
+// do は、Gerrit REST API の認証付きのエンドポイント (/a/ に続くパス) に body を JSON として送信します。応答の本文は読み捨てます。
+func (g *GerritReviewer) do(ctx context.Context, method, apiPath string, body any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+"/a/"+apiPath, reader)
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	req.SetBasicAuth(g.username, g.password)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("Gerrit REST API がエラーを返しました (status %d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,181 @@
+package adapters
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+const (
+	// gistScheme は、GitHub Gist の公開先を示すURIのスキームです。
+	gistScheme = "gist://"
+	// defaultGistFileName は、gist:// にファイル名を指定しなかった場合の Gist のファイル名です。
+	defaultGistFileName = "review.md"
+	// defaultGitHubAPIURL は、環境変数 GITHUB_API_URL が未指定の場合に使用する GitHub API のベースURLです。
+	defaultGitHubAPIURL = "https://api.github.com"
+)
+
+// IsGistURI は、URIが GitHub Gist の公開先 (gist://) を指すかを返します。
+func IsGistURI(uri string) bool {
+	return strings.HasPrefix(uri, gistScheme)
+}
+
+// gistFile は、Gist の作成・更新のリクエストに含める1つのファイルです。
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+// gistRequest は、Gist の作成 (POST /gists)・更新 (PATCH /gists/{id}) のリクエストです。
+type gistRequest struct {
+	Description string              `json:"description,omitempty"`
+	Public      *bool               `json:"public,omitempty"` // 作成時のみ指定する (更新時に公開範囲は変更できない)
+	Files       map[string]gistFile `json:"files"`
+}
+
+// gistResponse は、Gist の作成・更新の応答のうち使用する項目です。
+type gistResponse struct {
+	ID      string `json:"id"`
+	HTMLURL string `json:"html_url"`
+}
+
+// GistPublisher は、レビュー結果の Markdown を GitHub の秘密の Gist (secret gist) として保存する publisher.Publisher の実装です。
+// クラウドストレージを持たないチームでも、Slack 通知からレビュー結果を開けるようにするためのものです。
+// Gist は Markdown を表示するため、形式 (--format) によらず AI が出力した Markdown をそのまま保存します。
+// 保存した Gist の URL を通知に使用できるよう、remoteio.URLSigner も実装します。
+//
+// URI の形式:
+//   - gist:// または gist://<ファイル名>: 新しい Gist を作成します (ファイル名の省略時は review.md)。
+//   - gist://<Gist ID>/<ファイル名>: 既存の Gist のファイルを更新します (固定の URL で最新のレビューを共有する場合)。
+type GistPublisher struct {
+	apiURL string
+	token  string
+	client httpkit.ClientInterface
+
+	mu   sync.Mutex
+	urls map[string]string // 公開した URI ごとの Gist の URL
+}
+
+// NewGistPublisher は、httpClient と環境変数 GITHUB_TOKEN (または GH_TOKEN) のトークンで Gist を作成する GistPublisher を返します。
+// GitHub Enterprise Server の場合は、環境変数 GITHUB_API_URL に API のベースURLを指定します (GitHub Actions では自動で設定されます)。
+// トークンには gist のスコープ (Fine-grained token の場合は Gists の書き込み権限) が必要です。
+func NewGistPublisher(httpClient httpkit.ClientInterface) (*GistPublisher, error) {
+	token := cmp.Or(os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN"))
+	if token == "" {
+		return nil, fmt.Errorf("Gist への公開には環境変数 GITHUB_TOKEN (または GH_TOKEN) に GitHub のトークンを設定してください")
+	}
+	return &GistPublisher{
+		apiURL: strings.TrimRight(cmp.Or(os.Getenv("GITHUB_API_URL"), defaultGitHubAPIURL), "/"),
+		token:  token,
+		client: httpClient,
+		urls:   make(map[string]string),
+	}, nil
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (p *GistPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	gistID, fileName, err := parseGistURI(uri)
+	if err != nil {
+		return err
+	}
+
+	req := gistRequest{
+		Description: gistDescription(data),
+		Files:       map[string]gistFile{fileName: {Content: data.ReviewMarkdown}},
+	}
+	method, endpoint := http.MethodPatch, p.apiURL+"/gists/"+gistID
+	if gistID == "" {
+		secret := false
+		req.Public = &secret
+		method, endpoint = http.MethodPost, p.apiURL+"/gists"
+	}
+
+	gist, err := p.do(ctx, method, endpoint, req)
+	if err != nil {
+		return fmt.Errorf("Gist '%s' への保存に失敗しました: %w", uri, err)
+	}
+	p.mu.Lock()
+	p.urls[uri] = gist.HTMLURL
+	p.mu.Unlock()
+	slog.Info("レビュー結果を Gist に保存しました。", "uri", uri, "gist", gist.ID, "url", gist.HTMLURL)
+	return nil
+}
+
+// GenerateSignedURL は remoteio.URLSigner インターフェースの実装です。uri に公開した Gist の URL を返します。
+// 秘密の Gist は URL を知っている人のみが閲覧できるため、署名や有効期限はありません。
+func (p *GistPublisher) GenerateSignedURL(ctx context.Context, uri, method string, expiration time.Duration) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if u, ok := p.urls[uri]; ok {
+		return u, nil
+	}
+	return "", fmt.Errorf("'%s' はこの実行で公開されていないため、Gist の URL がわかりません", uri)
+}
+
+// do は、GitHub API にリクエストを送信し、Gist の応答を返します。
+func (p *GistPublisher) do(ctx context.Context, method, endpoint string, body gistRequest) (gistResponse, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return gistResponse{}, fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return gistResponse{}, fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return gistResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return gistResponse{}, fmt.Errorf("GitHub API がエラーを返しました (status %d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	var gist gistResponse
+	if err := json.NewDecoder(resp.Body).Decode(&gist); err != nil {
+		return gistResponse{}, fmt.Errorf("GitHub API の応答の解析に失敗しました: %w", err)
+	}
+	return gist, nil
+}
+
+// parseGistURI は、gist:// のURIから更新する Gist の ID (新しく作成する場合は空) とファイル名を返します。
+func parseGistURI(uri string) (gistID, fileName string, err error) {
+	rest, ok := strings.CutPrefix(uri, gistScheme)
+	if !ok {
+		return "", "", fmt.Errorf("URI '%s' は Gist の公開先ではありません", uri)
+	}
+	rest = strings.Trim(rest, "/")
+	if id, name, ok := strings.Cut(rest, "/"); ok {
+		if id == "" || name == "" || strings.Contains(name, "/") {
+			return "", "", fmt.Errorf("Gist の公開先 '%s' の形式が不正です (gist://<ファイル名> または gist://<Gist ID>/<ファイル名>)", uri)
+		}
+		return id, name, nil
+	}
+	return "", cmp.Or(rest, defaultGistFileName), nil
+}
+
+// gistDescription は、Gist の説明に使用するレビュー対象の要約を返します。
+func gistDescription(data publisher.ReviewData) string {
+	desc := "AI コードレビュー: " + data.RepoURL
+	if data.FeatureBranch != "" {
+		desc += fmt.Sprintf(" (%s...%s)", data.BaseBranch, data.FeatureBranch)
+	}
+	return desc
+}
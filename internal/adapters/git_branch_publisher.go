@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/lockfile"
+
+	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// gitBranchScheme は、リポジトリのブランチへのコミットによる公開先を示すURIのスキームです。
+const gitBranchScheme = "git-branch://"
+
+// IsGitBranchURI は、URIがリポジトリのブランチへのコミットによる公開先 (git-branch://<ブランチ>/<パス>) を指すかを返します。
+func IsGitBranchURI(uri string) bool {
+	return strings.HasPrefix(uri, gitBranchScheme)
+}
+
+// GitBranchPublisher は、レビュー結果をリポジトリのドキュメント用のブランチ (gh-pages など) にコミットしてプッシュする publisher.Publisher の実装です。
+// GitHub Pages などでレポートを配信したり、レビューの履歴をリポジトリに残したりするためのものです。
+// URI は git-branch://<ブランチ>/<パス> の形式で、ブランチ名の "/" は %2F で指定します (例: git-branch://gh-pages/reviews/result.html)。
+type GitBranchPublisher struct {
+	git         coreAdapters.GitService // BranchCommitter を実装していること
+	repoURL     string
+	localPath   string        // git のローカルリポジトリのパス (公開中はこのパスのロックを保持する)
+	lockTimeout time.Duration // ロックの取得を待機する最大時間
+	format      string
+	renderer    *ReportRenderer
+}
+
+// NewGitBranchPublisher は、git (ローカルリポジトリのパスは localPath) で repoURL のリポジトリにレポートをコミットする GitBranchPublisher を返します。
+// 同じローカルリポジトリを使用する並行実行と競合しないよう、公開中は localPath のロックを保持します (取得の待機は lockTimeout まで)。
+// git が BranchCommitter を実装していない場合は ErrBranchCommitUnsupported を返します。
+func NewGitBranchPublisher(git coreAdapters.GitService, repoURL, localPath string, lockTimeout time.Duration, format string, renderer *ReportRenderer) (*GitBranchPublisher, error) {
+	if _, ok := git.(BranchCommitter); !ok {
+		return nil, ErrBranchCommitUnsupported
+	}
+	return &GitBranchPublisher{git: git, repoURL: repoURL, localPath: localPath, lockTimeout: lockTimeout, format: format, renderer: renderer}, nil
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (p *GitBranchPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	branch, filePath, err := parseGitBranchURI(uri)
+	if err != nil {
+		return err
+	}
+
+	var page []byte
+	switch p.format {
+	case config.FormatMarkdown:
+		page = []byte(data.ReviewMarkdown)
+	case config.FormatPDF:
+		page, err = p.renderer.PDF(ctx, data)
+	default:
+		page, err = p.renderer.HTML(ctx, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	// クローンの更新からプッシュまでの間に、並行実行がワーキングツリーをリセット・チェックアウトしないようロックする
+	lock, err := lockfile.Acquire(ctx, lockfile.PathFor(p.localPath), p.lockTimeout)
+	if errors.Is(err, lockfile.ErrLocked) {
+		return fmt.Errorf("別のプロセスが公開先のローカルリポジトリを使用中です。--lock-timeout を延ばすこともできます: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("公開先のローカルリポジトリのロック取得に失敗しました: %w", err)
+	}
+	defer func() {
+		if err := lock.Release(); err != nil {
+			slog.Warn("公開先のローカルリポジトリのロック解放に失敗しました。", "error", err)
+		}
+	}()
+
+	if err := p.git.CloneOrUpdate(ctx, p.repoURL); err != nil {
+		return fmt.Errorf("公開先のリポジトリ '%s' の準備に失敗しました: %w", p.repoURL, err)
+	}
+	message := "AIコードレビューのレポートを追加: " + filePath
+	if data.FeatureBranch != "" {
+		message += fmt.Sprintf(" (%s...%s)", data.BaseBranch, data.FeatureBranch)
+	}
+	if _, err := p.git.(BranchCommitter).CommitFile(ctx, branch, filePath, page, message); err != nil {
+		return fmt.Errorf("'%s' へのレポートのコミットに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// parseGitBranchURI は、git-branch:// のURIからブランチ名とリポジトリ内のファイルのパスを返します。
+func parseGitBranchURI(uri string) (branch, filePath string, err error) {
+	rest, ok := strings.CutPrefix(uri, gitBranchScheme)
+	if !ok {
+		return "", "", fmt.Errorf("URI '%s' はブランチへのコミットの公開先ではありません", uri)
+	}
+	escapedBranch, filePath, _ := strings.Cut(rest, "/")
+	branch, err = url.PathUnescape(escapedBranch)
+	if err != nil {
+		return "", "", fmt.Errorf("URI '%s' のブランチ名が不正です: %w", uri, err)
+	}
+	filePath = path.Clean(filePath)
+	if branch == "" || filePath == "." || strings.HasSuffix(rest, "/") || strings.HasPrefix(filePath, "../") || filePath == ".." {
+		return "", "", fmt.Errorf("URI '%s' にブランチ名またはファイルのパスが含まれていません (git-branch://<ブランチ>/<パス>)", uri)
+	}
+	return branch, filePath, nil
+}
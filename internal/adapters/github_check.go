@@ -0,0 +1,138 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+)
+
+const (
+	// githubCheckName は、レビュー結果を報告するチェックランの名前です。
+	githubCheckName = "Gemini Review"
+	// githubCheckOutputMaxChars は、チェックランの出力の summary と text のそれぞれの文字数の上限です。
+	githubCheckOutputMaxChars = 65535
+)
+
+// GitHubCheckReporter は、レビューしたヘッドのコミットにチェックラン ("Gemini Review") を作成し、
+// レビュー結果をプルリクエストの Checks タブに表示する PRCommenter の実装です。
+// チェックランの結論は、--fail-on のしきい値以上の指摘事項があれば failure、なければ success、--fail-on が未指定の場合は neutral とします。
+type GitHubCheckReporter struct {
+	client *GitHubClient
+}
+
+// NewGitHubCheckReporter は、client でチェックランを作成する GitHubCheckReporter を返します。
+func NewGitHubCheckReporter(client *GitHubClient) *GitHubCheckReporter {
+	return &GitHubCheckReporter{client: client}
+}
+
+// checkRun は、チェックランの作成のリクエストと応答のうち使用する項目です。
+type checkRun struct {
+	Name       string         `json:"name"`
+	HeadSHA    string         `json:"head_sha"`
+	Status     string         `json:"status"`
+	Conclusion string         `json:"conclusion"`
+	DetailsURL string         `json:"details_url,omitempty"`
+	ExternalID string         `json:"external_id,omitempty"`
+	Output     checkRunOutput `json:"output"`
+	HTMLURL    string         `json:"html_url,omitempty"`
+}
+
+// checkRunOutput は、チェックランの出力です。
+type checkRunOutput struct {
+	Title   string `json:"title"`
+	Summary string `json:"summary"`
+	Text    string `json:"text,omitempty"`
+}
+
+// Comment は PRCommenter インターフェースの実装です。
+// ヘッドのコミットは context に格納されたもの (WithHeadCommit) を優先し、ない場合はフィーチャーブランチの最新のコミットとします。
+func (r *GitHubCheckReporter) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	sha := headCommitFromContext(ctx)
+	if sha == "" {
+		var err error
+		if sha, err = r.branchHead(ctx, cfg.FeatureBranch); err != nil {
+			return err
+		}
+	}
+
+	_, list := findings.Split(review)
+	if ctxList, ok := findings.FromContext(ctx); ok {
+		list = ctxList
+	}
+	summary := findings.Summarize(list)
+	conclusion := "neutral"
+	if cfg.FailOn != "" {
+		threshold, err := findings.ParseSeverity(cfg.FailOn)
+		if err != nil {
+			return err
+		}
+		conclusion = "success"
+		if findings.CheckThreshold(list, threshold) != nil {
+			conclusion = "failure"
+		}
+	}
+
+	var summaryText strings.Builder
+	fmt.Fprintf(&summaryText, "**モード:** `%s` / **モデル:** `%s`\n\n", cfg.ReviewMode, cfg.Model)
+	if strings.HasPrefix(publicURL, "https://") || strings.HasPrefix(publicURL, "http://") {
+		fmt.Fprintf(&summaryText, "📄 [レポートを開く](%s)\n\n", publicURL)
+	}
+	summaryText.WriteString(summary.Markdown())
+
+	req := checkRun{
+		Name:       githubCheckName,
+		HeadSHA:    sha,
+		Status:     "completed",
+		Conclusion: conclusion,
+		ExternalID: cfg.ReviewMode,
+		Output: checkRunOutput{
+			Title:   fmt.Sprintf("%s / %s", summary.Verdict, summary.CountsText()),
+			Summary: truncateRunes(summaryText.String(), githubCheckOutputMaxChars),
+			Text:    truncateRunes(strings.TrimSpace(review), githubCheckOutputMaxChars),
+		},
+	}
+	if strings.HasPrefix(publicURL, "https://") || strings.HasPrefix(publicURL, "http://") {
+		req.DetailsURL = publicURL
+	}
+
+	var created checkRun
+	if err := r.client.Do(ctx, http.MethodPost, "check-runs", req, &created); err != nil {
+		if errors.Is(err, ErrGitHubNotFound) {
+			return fmt.Errorf("チェックランの作成に失敗しました (トークンに checks: write の権限があるか確認してください): %w", err)
+		}
+		return fmt.Errorf("チェックランの作成に失敗しました: %w", err)
+	}
+	slog.Info("レビュー結果をチェックランとして報告しました。", "repo", r.client.Repo(), "commit", sha, "conclusion", conclusion, "url", created.HTMLURL)
+	return nil
+}
+
+// branchHead は、ブランチの最新のコミットハッシュを返します。
+func (r *GitHubCheckReporter) branchHead(ctx context.Context, branch string) (string, error) {
+	branch = trimBranchPrefix(branch)
+	if branch == "" {
+		return "", fmt.Errorf("チェックランを作成するコミットを特定できません: フィーチャーブランチが指定されていません")
+	}
+	var commit struct {
+		SHA string `json:"sha"`
+	}
+	if err := r.client.Do(ctx, http.MethodGet, "commits/"+branch, nil, &commit); err != nil {
+		return "", fmt.Errorf("ブランチ '%s' の最新のコミットの取得に失敗しました: %w", branch, err)
+	}
+	return commit.SHA, nil
+}
+
+// truncateRunes は、s が max 文字を超える場合に、末尾を省略した旨の注記を含めて max 文字に収まるよう切り詰めます。
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	const note = "\n\n_(以降省略)_"
+	return string([]rune(s)[:max-utf8.RuneCountInString(note)]) + note
+}
@@ -0,0 +1,261 @@
+package adapters
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"git-gemini-cli/internal/repoweb"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+const (
+	// githubAppJWTLifetime は、GitHub App の認証に使用する JWT の有効期間です (GitHub の上限は10分)。
+	githubAppJWTLifetime = 9 * time.Minute
+	// githubTokenRefreshMargin は、インストールアクセストークンの有効期限のこの時間前に再取得するマージンです。
+	githubTokenRefreshMargin = time.Minute
+)
+
+// ErrGitHubNotFound は、GitHub API が 404 を返したことを示すエラーです。
+var ErrGitHubNotFound = errors.New("GitHub API の対象が見つかりません")
+
+// GitHubClient は、プルリクエストへのコメントなどに使用する GitHub REST API のクライアントです。
+// 認証には、環境変数 GITHUB_APP_ID が設定されている場合は GitHub App のインストールアクセストークンを、
+// それ以外の場合は GITHUB_TOKEN (または GH_TOKEN) のトークンを使用します。
+type GitHubClient struct {
+	apiURL string
+	owner  string
+	repo   string
+	client httpkit.ClientInterface
+
+	token string     // 個人・Actions のトークン (GitHub App を使用しない場合)
+	app   *githubApp // GitHub App の認証情報 (使用しない場合は nil)
+	mu    sync.Mutex // app のインストールアクセストークンの取得を直列化する
+}
+
+// githubApp は、GitHub App の認証情報とインストールアクセストークンのキャッシュです。
+type githubApp struct {
+	id             string
+	key            *rsa.PrivateKey
+	installationID string // 空の場合はリポジトリから求める
+
+	token     string
+	expiresAt time.Time
+}
+
+// NewGitHubClient は、httpClient で repoURL のリポジトリを操作する GitHubClient を返します。
+// API のベースURLは環境変数 GITHUB_API_URL を優先し、未指定の場合は github.com では https://api.github.com、
+// それ以外のホスト (GitHub Enterprise Server) では https://<ホスト>/api/v3 とします。
+//
+// GitHub App で認証する場合は、GITHUB_APP_ID と GITHUB_APP_PRIVATE_KEY (PEM の内容) または GITHUB_APP_PRIVATE_KEY_PATH を設定します。
+// GITHUB_APP_INSTALLATION_ID を省略した場合は、リポジトリへのインストールを API で検索します。
+func NewGitHubClient(httpClient httpkit.ClientInterface, repoURL string) (*GitHubClient, error) {
+	host, repoPath := repoweb.HostPath(repoURL)
+	owner, repo, ok := strings.Cut(repoPath, "/")
+	if host == "" || !ok || strings.Contains(repo, "/") {
+		return nil, fmt.Errorf("リポジトリのURL '%s' から GitHub のオーナーとリポジトリ名を取得できません", repoURL)
+	}
+
+	c := &GitHubClient{
+		apiURL: strings.TrimRight(cmp.Or(os.Getenv("GITHUB_API_URL"), githubAPIURL(host)), "/"),
+		owner:  owner,
+		repo:   repo,
+		client: httpClient,
+	}
+	if appID := os.Getenv("GITHUB_APP_ID"); appID != "" {
+		key, err := loadGitHubAppKey()
+		if err != nil {
+			return nil, err
+		}
+		c.app = &githubApp{id: appID, key: key, installationID: os.Getenv("GITHUB_APP_INSTALLATION_ID")}
+		return c, nil
+	}
+	c.token = cmp.Or(os.Getenv("GITHUB_TOKEN"), os.Getenv("GH_TOKEN"))
+	if c.token == "" {
+		return nil, fmt.Errorf("GitHub API の認証情報がありません。環境変数 GITHUB_TOKEN (または GH_TOKEN)、または GitHub App の GITHUB_APP_ID と GITHUB_APP_PRIVATE_KEY を設定してください")
+	}
+	return c, nil
+}
+
+// Repo は、操作対象のリポジトリを "owner/repo" の形式で返します。
+func (c *GitHubClient) Repo() string {
+	return c.owner + "/" + c.repo
+}
+
+// Do は、リポジトリの API (/repos/{owner}/{repo}/ に続くパス) にリクエストを送信し、応答の JSON を out に格納します。
+// body が nil でない場合は JSON として送信します。out が nil の場合は応答の本文を読み捨てます。
+// 404 の応答は ErrGitHubNotFound を、それ以外の 2xx 以外の応答は本文を含むエラーを返します。
+func (c *GitHubClient) Do(ctx context.Context, method, repoPath string, body, out any) error {
+	return c.do(ctx, method, c.apiURL+"/repos/"+c.owner+"/"+c.repo+"/"+strings.TrimLeft(repoPath, "/"), body, out)
+}
+
+// do は、GitHub API の endpoint にリクエストを送信します。
+func (c *GitHubClient) do(ctx context.Context, method, endpoint string, body, out any) error {
+	token, err := c.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	return c.send(ctx, method, endpoint, "Bearer "+token, body, out)
+}
+
+// send は、認証ヘッダー authorization を付けてリクエストを送信します。
+func (c *GitHubClient) send(ctx context.Context, method, endpoint, authorization string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s %s", ErrGitHubNotFound, method, endpoint)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("GitHub API がエラーを返しました (status %d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("GitHub API の応答の解析に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// accessToken は、API の認証に使用するトークンを返します。
+// GitHub App の場合は、インストールアクセストークンを有効期限の直前まで再利用します。
+func (c *GitHubClient) accessToken(ctx context.Context) (string, error) {
+	if c.app == nil {
+		return c.token, nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	app := c.app
+	if app.token != "" && time.Now().Add(githubTokenRefreshMargin).Before(app.expiresAt) {
+		return app.token, nil
+	}
+
+	jwt, err := app.jwt(time.Now())
+	if err != nil {
+		return "", err
+	}
+	if app.installationID == "" {
+		var installation struct {
+			ID int64 `json:"id"`
+		}
+		if err := c.send(ctx, http.MethodGet, c.apiURL+"/repos/"+c.owner+"/"+c.repo+"/installation", "Bearer "+jwt, nil, &installation); err != nil {
+			return "", fmt.Errorf("GitHub App のリポジトリ '%s' へのインストールの取得に失敗しました: %w", c.Repo(), err)
+		}
+		app.installationID = fmt.Sprint(installation.ID)
+	}
+
+	var token struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := c.send(ctx, http.MethodPost, c.apiURL+"/app/installations/"+app.installationID+"/access_tokens", "Bearer "+jwt, nil, &token); err != nil {
+		return "", fmt.Errorf("GitHub App のインストールアクセストークンの取得に失敗しました: %w", err)
+	}
+	app.token, app.expiresAt = token.Token, token.ExpiresAt
+	return app.token, nil
+}
+
+// jwt は、GitHub App として認証するための RS256 で署名した JWT を返します。
+// GitHub との時刻のずれを考慮し、発行時刻を1分前にします。
+func (a *githubApp) jwt(now time.Time) (string, error) {
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(githubAppJWTLifetime).Unix(),
+		"iss": a.id,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + enc.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(nil, a.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("GitHub App の JWT の署名に失敗しました: %w", err)
+	}
+	return unsigned + "." + enc.EncodeToString(sig), nil
+}
+
+// loadGitHubAppKey は、環境変数 GITHUB_APP_PRIVATE_KEY (PEM の内容) または GITHUB_APP_PRIVATE_KEY_PATH (PEM のファイル) から GitHub App の秘密鍵を読み込みます。
+func loadGitHubAppKey() (*rsa.PrivateKey, error) {
+	data := []byte(os.Getenv("GITHUB_APP_PRIVATE_KEY"))
+	if len(data) == 0 {
+		keyPath := os.Getenv("GITHUB_APP_PRIVATE_KEY_PATH")
+		if keyPath == "" {
+			return nil, fmt.Errorf("GitHub App の認証には環境変数 GITHUB_APP_PRIVATE_KEY または GITHUB_APP_PRIVATE_KEY_PATH に秘密鍵を設定してください")
+		}
+		var err error
+		if data, err = os.ReadFile(keyPath); err != nil {
+			return nil, fmt.Errorf("GitHub App の秘密鍵 '%s' の読み込みに失敗しました: %w", keyPath, err)
+		}
+	}
+	// CI のシークレットでは改行が \n と記述される場合がある
+	data = bytes.ReplaceAll(data, []byte(`\n`), []byte("\n"))
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App の秘密鍵が PEM 形式ではありません")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("GitHub App の秘密鍵の解析に失敗しました: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App の秘密鍵が RSA の鍵ではありません")
+	}
+	return key, nil
+}
+
+// githubAPIURL は、リポジトリのホストに対応する GitHub API のベースURLを返します。
+func githubAPIURL(host string) string {
+	if host == "github.com" || host == "www.github.com" {
+		return defaultGitHubAPIURL
+	}
+	return "https://" + host + "/api/v3"
+}
@@ -0,0 +1,229 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/timeutil"
+)
+
+const (
+	// reviewCommentMarker は、このツールが投稿したレビューのコメントを識別するために本文に埋め込む HTML コメントの接頭辞です。
+	// レビューモードごとに1つのコメントを更新し続けるため、モードを続けて記録します (例: <!-- git-gemini-cli:review:detail -->)。
+	reviewCommentMarker = "<!-- git-gemini-cli:review:"
+	// githubCommentMaxChars は、GitHub のコメント本文の文字数の上限です。
+	githubCommentMaxChars = 65536
+	// apiListMaxPages は、コメントや変更されたファイルなどの一覧を API で取得する際の最大ページ数です (1ページ100件)。
+	apiListMaxPages = 30
+)
+
+// PRCommenter は、レビュー結果をプルリクエスト (マージリクエスト) のコメントとして投稿する契約を定義します。
+// publicURL はレポートを開けるURL、review はレビュー結果の Markdown です。
+type PRCommenter interface {
+	Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error
+}
+
+// PRCommenters は、複数の PRCommenter (コメントとチェックランなど) に順に投稿する PRCommenter です。
+// 一部の投稿に失敗しても残りの投稿を行い、失敗したものをまとめたエラーを返します。
+type PRCommenters []PRCommenter
+
+// Comment は PRCommenter インターフェースの実装です。
+func (cs PRCommenters) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	var errs []error
+	for _, c := range cs {
+		if err := c.Comment(ctx, publicURL, review, cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// headCommitKey は、context にレビューしたヘッドのコミットハッシュを格納するためのキーです。
+type headCommitKey struct{}
+
+// WithHeadCommit は、レビューしたヘッドのコミットハッシュを格納した context を返します。
+// チェックランなど、コミットに結び付けて報告する PRCommenter が使用します。
+func WithHeadCommit(ctx context.Context, sha string) context.Context {
+	if sha == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, headCommitKey{}, sha)
+}
+
+// headCommitFromContext は、context に格納されたヘッドのコミットハッシュを返します (WithHeadCommit)。
+func headCommitFromContext(ctx context.Context) string {
+	sha, _ := ctx.Value(headCommitKey{}).(string)
+	return sha
+}
+
+// PullRequest は、GitHub のプルリクエストのうち使用する項目です。
+type PullRequest struct {
+	Number  int    `json:"number"`
+	Title   string `json:"title"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+	Head    struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+}
+
+// FindPullRequest は、head のブランチからのオープンなプルリクエストを返します。
+// 複数ある場合は base をマージ先とするものを優先します。見つからない場合は nil を返します。
+// number が 0 より大きい場合は、ブランチによらずその番号のプルリクエストを返します。
+func (c *GitHubClient) FindPullRequest(ctx context.Context, number int, head, base string) (*PullRequest, error) {
+	if number > 0 {
+		var pr PullRequest
+		if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("pulls/%d", number), nil, &pr); err != nil {
+			return nil, fmt.Errorf("プルリクエスト #%d の取得に失敗しました: %w", number, err)
+		}
+		return &pr, nil
+	}
+
+	head = trimBranchPrefix(head)
+	if head == "" {
+		return nil, nil
+	}
+	query := url.Values{"state": {"open"}, "head": {c.owner + ":" + head}, "per_page": {"100"}}
+	var prs []PullRequest
+	if err := c.Do(ctx, http.MethodGet, "pulls?"+query.Encode(), nil, &prs); err != nil {
+		return nil, fmt.Errorf("ブランチ '%s' のプルリクエストの検索に失敗しました: %w", head, err)
+	}
+	if len(prs) == 0 {
+		return nil, nil
+	}
+	base = trimBranchPrefix(base)
+	for i := range prs {
+		if prs[i].Base.Ref == base {
+			return &prs[i], nil
+		}
+	}
+	return &prs[0], nil
+}
+
+// GitHubPRCommenter は、フィーチャーブランチのオープンなプルリクエストに、レビュー結果を1つのコメントとして投稿する PRCommenter の実装です。
+// 以前に投稿したコメントがある場合は、新しいコメントを追加せずにその内容を更新します。
+type GitHubPRCommenter struct {
+	client   *GitHubClient
+	prNumber int // 0 より大きい場合は、ブランチから検索せずにこの番号のプルリクエストに投稿する
+}
+
+// NewGitHubPRCommenter は、client で prNumber (0 の場合はフィーチャーブランチから検索) のプルリクエストに投稿する GitHubPRCommenter を返します。
+func NewGitHubPRCommenter(client *GitHubClient, prNumber int) *GitHubPRCommenter {
+	return &GitHubPRCommenter{client: client, prNumber: prNumber}
+}
+
+// issueComment は、プルリクエスト (Issue) のコメントのうち使用する項目です。
+type issueComment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Comment は PRCommenter インターフェースの実装です。
+// オープンなプルリクエストが見つからない場合は、投稿をスキップします。
+func (c *GitHubPRCommenter) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	pr, err := c.client.FindPullRequest(ctx, c.prNumber, cfg.FeatureBranch, cfg.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if pr == nil {
+		slog.Info("フィーチャーブランチのオープンなプルリクエストが見つからないため、コメントの投稿をスキップします。", "repo", c.client.Repo(), "branch", cfg.FeatureBranch)
+		return nil
+	}
+	return c.commentOn(ctx, pr, publicURL, review, cfg)
+}
+
+// commentOn は、プルリクエスト pr にレビュー結果のコメントを投稿 (以前のコメントがある場合は更新) します。
+func (c *GitHubPRCommenter) commentOn(ctx context.Context, pr *PullRequest, publicURL, review string, cfg config.ReviewConfig) error {
+	marker := reviewMarker(cfg.ReviewMode)
+	body := buildReviewComment(marker, publicURL, review, cfg, githubCommentMaxChars)
+	existing, err := c.findComment(ctx, pr.Number, marker)
+	if err != nil {
+		return err
+	}
+
+	var posted issueComment
+	if existing != nil {
+		err = c.client.Do(ctx, http.MethodPatch, fmt.Sprintf("issues/comments/%d", existing.ID), map[string]string{"body": body}, &posted)
+	} else {
+		err = c.client.Do(ctx, http.MethodPost, fmt.Sprintf("issues/%d/comments", pr.Number), map[string]string{"body": body}, &posted)
+	}
+	if err != nil {
+		return fmt.Errorf("プルリクエスト #%d へのコメントの投稿に失敗しました: %w", pr.Number, err)
+	}
+	slog.Info("レビュー結果をプルリクエストのコメントに投稿しました。", "pr", pr.HTMLURL, "comment", posted.HTMLURL, "updated", existing != nil)
+	return nil
+}
+
+// findComment は、プルリクエストのコメントから marker を含む (以前に投稿した) コメントを探します。見つからない場合は nil を返します。
+func (c *GitHubPRCommenter) findComment(ctx context.Context, number int, marker string) (*issueComment, error) {
+	for page := 1; page <= apiListMaxPages; page++ {
+		var comments []issueComment
+		if err := c.client.Do(ctx, http.MethodGet, fmt.Sprintf("issues/%d/comments?per_page=100&page=%d", number, page), nil, &comments); err != nil {
+			return nil, fmt.Errorf("プルリクエスト #%d のコメントの取得に失敗しました: %w", number, err)
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < 100 {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
+
+// buildReviewComment は、プルリクエストに投稿するレビュー結果のコメントの本文を組み立てます。
+// 本文が maxChars 文字を超える場合は、レビュー結果の末尾を省略し、レポートへのリンクで全文を参照できるようにします。
+func buildReviewComment(marker, publicURL, review string, cfg config.ReviewConfig, maxChars int) string {
+	header := reviewCommentHeader(marker, publicURL, cfg)
+	const truncated = "\n\n---\n\n_レビュー結果が長いため、以降を省略しました。全文はレポートを参照してください。_"
+	body := strings.TrimSpace(review)
+	limit := maxChars - utf8.RuneCountInString(header)
+	if utf8.RuneCountInString(body) > limit {
+		runes := []rune(body)
+		body = string(runes[:max(limit-utf8.RuneCountInString(truncated), 0)]) + truncated
+	}
+	return header + body
+}
+
+// reviewCommentHeader は、レビュー結果のコメントの見出し (マーカー・モード・モデル・日時・レビューしたコミット・レポートへのリンク) を返します。
+// publicURL が http(s) のURLでない場合 (ローカルファイルなど) は、リンクを含めません。
+func reviewCommentHeader(marker, publicURL string, cfg config.ReviewConfig) string {
+	var header strings.Builder
+	header.WriteString(marker + "\n")
+	header.WriteString("## 🤖 AIコードレビュー結果\n\n")
+	fmt.Fprintf(&header, "**モード:** `%s` / **モデル:** `%s` / **日時:** `%s`", cfg.ReviewMode, cfg.Model, timeutil.FormatReport(time.Now()))
+	if cfg.HeadCommit != "" {
+		fmt.Fprintf(&header, " / **コミット:** `%.12s`", cfg.HeadCommit)
+	}
+	if strings.HasPrefix(publicURL, "https://") || strings.HasPrefix(publicURL, "http://") {
+		fmt.Fprintf(&header, "\n\n📄 [レポートを開く](%s)", publicURL)
+	}
+	header.WriteString("\n\n---\n\n")
+	return header.String()
+}
+
+// reviewMarker は、レビューモードごとのレビュー結果のコメントを識別するマーカーを返します。
+func reviewMarker(mode string) string {
+	return reviewCommentMarker + mode + " -->"
+}
+
+// trimBranchPrefix は、ブランチ名からリモート追跡ブランチ・参照の接頭辞 (origin/、refs/heads/) を取り除きます。
+func trimBranchPrefix(branch string) string {
+	branch = strings.TrimPrefix(strings.TrimSpace(branch), "refs/heads/")
+	return strings.TrimPrefix(branch, "origin/")
+}
@@ -0,0 +1,168 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+)
+
+// githubReviewMaxComments は、1回のレビューで投稿するインラインコメントの上限です。超えた指摘事項はレビューの本文に記載します。
+const githubReviewMaxComments = 50
+
+// GitHubPRReviewer は、指摘事項をプルリクエストの差分の該当行へのインラインコメントとし、1つのレビューとしてまとめて投稿する PRCommenter の実装です。
+// 差分の行に結び付けられない指摘事項 (ファイル・行が不明、またはプルリクエストの差分に含まれない行) は、レビューの本文に記載します。
+// 結び付けられる指摘事項がない場合や、レビューの投稿に失敗した場合は、GitHubPRCommenter と同じ1つのコメントの投稿に切り替えます。
+type GitHubPRReviewer struct {
+	client   *GitHubClient
+	prNumber int
+	fallback *GitHubPRCommenter
+}
+
+// NewGitHubPRReviewer は、client で prNumber (0 の場合はフィーチャーブランチから検索) のプルリクエストにレビューを投稿する GitHubPRReviewer を返します。
+func NewGitHubPRReviewer(client *GitHubClient, prNumber int) *GitHubPRReviewer {
+	return &GitHubPRReviewer{client: client, prNumber: prNumber, fallback: NewGitHubPRCommenter(client, prNumber)}
+}
+
+// reviewComment は、レビューのインラインコメントです (変更後のファイルの行に付ける)。
+type reviewComment struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+	Side string `json:"side"`
+	Body string `json:"body"`
+}
+
+// pullRequestReview は、プルリクエストのレビューの作成のリクエストと、一覧の応答のうち使用する項目です。
+type pullRequestReview struct {
+	ID       int64           `json:"id,omitempty"`
+	CommitID string          `json:"commit_id"`
+	Body     string          `json:"body"`
+	Event    string          `json:"event,omitempty"`
+	Comments []reviewComment `json:"comments,omitempty"`
+	HTMLURL  string          `json:"html_url,omitempty"`
+}
+
+// Comment は PRCommenter インターフェースの実装です。
+// 同じコミットに対して以前に投稿したレビューがある場合は、重複して投稿しません。
+func (r *GitHubPRReviewer) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	pr, err := r.client.FindPullRequest(ctx, r.prNumber, cfg.FeatureBranch, cfg.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if pr == nil {
+		slog.Info("フィーチャーブランチのオープンなプルリクエストが見つからないため、レビューの投稿をスキップします。", "repo", r.client.Repo(), "branch", cfg.FeatureBranch)
+		return nil
+	}
+
+	if head := headCommitFromContext(ctx); head != "" && head != pr.Head.SHA {
+		// インラインコメントの行はプルリクエストの現在の差分で判定するため、古いコミットのレビューには付けられない
+		slog.Info("レビューしたコミットの後にプルリクエストへコミットが追加されているため、インラインコメントの代わりにコメントを投稿します。", "pr", pr.HTMLURL, "reviewed", head, "head", pr.Head.SHA)
+		return r.fallback.commentOn(ctx, pr, publicURL, review, cfg)
+	}
+
+	report, list := findings.Split(review)
+	if ctxList, ok := findings.FromContext(ctx); ok {
+		list = ctxList
+	}
+	lines, err := r.diffLines(ctx, pr.Number)
+	if err != nil {
+		slog.Warn("プルリクエストの差分を取得できないため、インラインコメントの代わりにコメントを投稿します。", "pr", pr.HTMLURL, "error", err)
+		return r.fallback.commentOn(ctx, pr, publicURL, review, cfg)
+	}
+
+	var comments []reviewComment
+	var unanchored []findings.Finding
+	for _, f := range list {
+		if f.File == "" || f.Line <= 0 || !lines[f.File][f.Line] || len(comments) >= githubReviewMaxComments {
+			unanchored = append(unanchored, f)
+			continue
+		}
+		comments = append(comments, reviewComment{Path: f.File, Line: f.Line, Side: "RIGHT", Body: inlineCommentBody(report, f)})
+	}
+	if len(comments) == 0 {
+		slog.Info("プルリクエストの差分の行に結び付けられる指摘事項がないため、インラインコメントの代わりにコメントを投稿します。", "pr", pr.HTMLURL, "findings", len(list))
+		return r.fallback.commentOn(ctx, pr, publicURL, review, cfg)
+	}
+
+	marker := reviewMarker(cfg.ReviewMode)
+	if posted, err := r.reviewedCommit(ctx, pr, marker); err != nil {
+		slog.Warn("以前のレビューの確認に失敗しましたが、レビューを投稿します。", "pr", pr.HTMLURL, "error", err)
+	} else if posted {
+		slog.Info("このコミットのレビューは投稿済みのため、スキップします。", "pr", pr.HTMLURL, "commit", pr.Head.SHA)
+		return nil
+	}
+
+	body := reviewCommentHeader(marker, publicURL, cfg) + findings.Summarize(list).Markdown()
+	if len(unanchored) > 0 {
+		body += "\n### 差分の行に結び付けられなかった指摘事項\n\n"
+		for _, f := range unanchored {
+			body += "- " + f.Label() + "\n"
+		}
+	}
+	req := pullRequestReview{CommitID: pr.Head.SHA, Body: body, Event: "COMMENT", Comments: comments}
+	var created pullRequestReview
+	if err := r.client.Do(ctx, http.MethodPost, fmt.Sprintf("pulls/%d/reviews", pr.Number), req, &created); err != nil {
+		// 差分の位置が受け付けられない場合 (422) など。レビュー結果を失わないよう、コメントの投稿に切り替える
+		slog.Warn("インラインコメント付きのレビューの投稿に失敗したため、コメントを投稿します。", "pr", pr.HTMLURL, "error", err)
+		return r.fallback.commentOn(ctx, pr, publicURL, review, cfg)
+	}
+	slog.Info("指摘事項をインラインコメントとしてプルリクエストのレビューに投稿しました。", "pr", pr.HTMLURL, "review", created.HTMLURL, "comments", len(comments), "unanchored", len(unanchored))
+	return nil
+}
+
+// diffLines は、プルリクエストの差分に含まれる (インラインコメントを付けられる) 変更後のファイルの行を返します。
+func (r *GitHubPRReviewer) diffLines(ctx context.Context, number int) (map[string]map[int]bool, error) {
+	var diff strings.Builder
+	for page := 1; page <= apiListMaxPages; page++ {
+		var files []struct {
+			Filename string `json:"filename"`
+			Patch    string `json:"patch"`
+		}
+		if err := r.client.Do(ctx, http.MethodGet, fmt.Sprintf("pulls/%d/files?per_page=100&page=%d", number, page), nil, &files); err != nil {
+			return nil, fmt.Errorf("プルリクエスト #%d の変更されたファイルの取得に失敗しました: %w", number, err)
+		}
+		for _, f := range files {
+			// patch はハンクのみのため、ファイルのヘッダを補って unified diff にする (バイナリや大きすぎる差分は patch が空)
+			if f.Patch != "" {
+				fmt.Fprintf(&diff, "diff --git a/%[1]s b/%[1]s\n--- a/%[1]s\n+++ b/%[1]s\n%[2]s\n", f.Filename, f.Patch)
+			}
+		}
+		if len(files) < 100 {
+			break
+		}
+	}
+	return diffutil.NewLines(diff.String()), nil
+}
+
+// reviewedCommit は、プルリクエストの現在の head のコミットに対して、marker を含むレビューを投稿済みかを返します。
+func (r *GitHubPRReviewer) reviewedCommit(ctx context.Context, pr *PullRequest, marker string) (bool, error) {
+	for page := 1; page <= apiListMaxPages; page++ {
+		var reviews []pullRequestReview
+		if err := r.client.Do(ctx, http.MethodGet, fmt.Sprintf("pulls/%d/reviews?per_page=100&page=%d", pr.Number, page), nil, &reviews); err != nil {
+			return false, err
+		}
+		for _, rv := range reviews {
+			if rv.CommitID == pr.Head.SHA && strings.Contains(rv.Body, marker) {
+				return true, nil
+			}
+		}
+		if len(reviews) < 100 {
+			return false, nil
+		}
+	}
+	return false, nil
+}
+
+// inlineCommentBody は、指摘事項のインラインコメントの本文を返します。
+// レポートに指摘事項の見出しのセクションがある場合はその内容 (説明や修正案) を、ない場合は指摘事項の見出しのみを記載します。
+func inlineCommentBody(report string, f findings.Finding) string {
+	if section := findings.Section(report, f); section != "" {
+		return section
+	}
+	return fmt.Sprintf("**[%s]** %s", f.Severity, strings.TrimSpace(f.Title))
+}
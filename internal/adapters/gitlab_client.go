@@ -0,0 +1,95 @@
+package adapters
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"git-gemini-cli/internal/repoweb"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// GitLabClient は、マージリクエストへのコメントなどに使用する GitLab REST API (v4) のクライアントです。
+// gitlab.com とセルフホストのインスタンスの両方に対応します。
+type GitLabClient struct {
+	apiURL  string
+	project string // プロジェクトのパス (例: group/subgroup/repo)
+	token   string
+	client  httpkit.ClientInterface
+}
+
+// NewGitLabClient は、httpClient で repoURL のプロジェクトを操作する GitLabClient を返します。
+// 認証には環境変数 GITLAB_TOKEN (api スコープのアクセストークン) を使用します。
+// API のベースURLは環境変数 GITLAB_API_URL、CI_API_V4_URL (GitLab CI で自動で設定されます) の順に優先し、
+// 未指定の場合はリポジトリのホストから https://<ホスト>/api/v4 とします。
+func NewGitLabClient(httpClient httpkit.ClientInterface, repoURL string) (*GitLabClient, error) {
+	host, project := repoweb.HostPath(repoURL)
+	if host == "" || !strings.Contains(project, "/") {
+		return nil, fmt.Errorf("リポジトリのURL '%s' から GitLab のプロジェクトを取得できません", repoURL)
+	}
+	token := os.Getenv("GITLAB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GitLab API の認証には環境変数 GITLAB_TOKEN に api スコープのアクセストークンを設定してください")
+	}
+	return &GitLabClient{
+		apiURL:  strings.TrimRight(cmp.Or(os.Getenv("GITLAB_API_URL"), os.Getenv("CI_API_V4_URL"), "https://"+host+"/api/v4"), "/"),
+		project: project,
+		token:   token,
+		client:  httpClient,
+	}, nil
+}
+
+// Project は、操作対象のプロジェクトのパスを返します。
+func (c *GitLabClient) Project() string {
+	return c.project
+}
+
+// Do は、プロジェクトの API (/projects/:id/ に続くパス) にリクエストを送信し、応答の JSON を out に格納します。
+// body が nil でない場合は JSON として送信します。out が nil の場合は応答の本文を読み捨てます。
+func (c *GitLabClient) Do(ctx context.Context, method, projectPath string, body, out any) error {
+	endpoint := c.apiURL + "/projects/" + url.PathEscape(c.project) + "/" + strings.TrimLeft(projectPath, "/")
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("GitLab API がエラーを返しました (status %d): %s", resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("GitLab API の応答の解析に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+)
+
+const (
+	// gitlabNoteMaxChars は、GitLab のコメント (ノート) 本文の文字数の上限です。
+	gitlabNoteMaxChars = 1000000
+	// gitlabMaxDiscussions は、1回の実行で作成するインラインのディスカッションの上限です。超えた指摘事項はコメントに記載します。
+	gitlabMaxDiscussions = 50
+	// reviewedHeadMarker は、インラインのディスカッションを作成したコミットをコメントに記録するための HTML コメントの接頭辞です。
+	reviewedHeadMarker = "<!-- git-gemini-cli:head:"
+)
+
+// MergeRequest は、GitLab のマージリクエストのうち使用する項目です。
+type MergeRequest struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	WebURL       string `json:"web_url"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	SHA          string `json:"sha"`
+	DiffRefs     struct {
+		BaseSHA  string `json:"base_sha"`
+		HeadSHA  string `json:"head_sha"`
+		StartSHA string `json:"start_sha"`
+	} `json:"diff_refs"`
+}
+
+// FindMergeRequest は、source のブランチからのオープンなマージリクエストを返します。
+// 複数ある場合は target をマージ先とするものを優先します。見つからない場合は nil を返します。
+// iid が 0 より大きい場合は、ブランチによらずその番号のマージリクエストを返します。
+func (c *GitLabClient) FindMergeRequest(ctx context.Context, iid int, source, target string) (*MergeRequest, error) {
+	if iid <= 0 {
+		source = trimBranchPrefix(source)
+		if source == "" {
+			return nil, nil
+		}
+		query := url.Values{"state": {"opened"}, "source_branch": {source}, "per_page": {"100"}}
+		var mrs []MergeRequest
+		if err := c.Do(ctx, http.MethodGet, "merge_requests?"+query.Encode(), nil, &mrs); err != nil {
+			return nil, fmt.Errorf("ブランチ '%s' のマージリクエストの検索に失敗しました: %w", source, err)
+		}
+		if len(mrs) == 0 {
+			return nil, nil
+		}
+		iid = mrs[0].IID
+		target = trimBranchPrefix(target)
+		for _, mr := range mrs {
+			if mr.TargetBranch == target {
+				iid = mr.IID
+				break
+			}
+		}
+	}
+	// 一覧の応答には diff_refs が含まれないため、個別に取得する
+	var mr MergeRequest
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("merge_requests/%d", iid), nil, &mr); err != nil {
+		return nil, fmt.Errorf("マージリクエスト !%d の取得に失敗しました: %w", iid, err)
+	}
+	return &mr, nil
+}
+
+// GitLabMRCommenter は、フィーチャーブランチのオープンなマージリクエストに、レビュー結果を1つのコメント (ノート) として投稿する PRCommenter の実装です。
+// 以前に投稿したコメントがある場合は、新しいコメントを追加せずにその内容を更新します。
+// inline が true の場合は、差分の行に結び付けられる指摘事項をその行へのディスカッションとして作成し、コメントにはサマリーと残りの指摘事項を記載します。
+type GitLabMRCommenter struct {
+	client *GitLabClient
+	iid    int // 0 より大きい場合は、ブランチから検索せずにこの番号のマージリクエストに投稿する
+	inline bool
+}
+
+// NewGitLabMRCommenter は、client で iid (0 の場合はフィーチャーブランチから検索) のマージリクエストに投稿する GitLabMRCommenter を返します。
+func NewGitLabMRCommenter(client *GitLabClient, iid int, inline bool) *GitLabMRCommenter {
+	return &GitLabMRCommenter{client: client, iid: iid, inline: inline}
+}
+
+// mrNote は、マージリクエストのコメント (ノート) のうち使用する項目です。
+type mrNote struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
+// Comment は PRCommenter インターフェースの実装です。
+// オープンなマージリクエストが見つからない場合は、投稿をスキップします。
+func (c *GitLabMRCommenter) Comment(ctx context.Context, publicURL, review string, cfg config.ReviewConfig) error {
+	mr, err := c.client.FindMergeRequest(ctx, c.iid, cfg.FeatureBranch, cfg.BaseBranch)
+	if err != nil {
+		return err
+	}
+	if mr == nil {
+		slog.Info("フィーチャーブランチのオープンなマージリクエストが見つからないため、コメントの投稿をスキップします。", "project", c.client.Project(), "branch", cfg.FeatureBranch)
+		return nil
+	}
+
+	marker := reviewMarker(cfg.ReviewMode)
+	existing, err := c.findNote(ctx, mr.IID, marker)
+	if err != nil {
+		return err
+	}
+
+	body := buildReviewComment(marker, publicURL, review, cfg, gitlabNoteMaxChars)
+	if c.inline {
+		body = c.discuss(ctx, mr, existing, marker, publicURL, review, cfg)
+	}
+
+	if existing != nil {
+		err = c.client.Do(ctx, http.MethodPut, fmt.Sprintf("merge_requests/%d/notes/%d", mr.IID, existing.ID), map[string]string{"body": body}, nil)
+	} else {
+		err = c.client.Do(ctx, http.MethodPost, fmt.Sprintf("merge_requests/%d/notes", mr.IID), map[string]string{"body": body}, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("マージリクエスト !%d へのコメントの投稿に失敗しました: %w", mr.IID, err)
+	}
+	slog.Info("レビュー結果をマージリクエストのコメントに投稿しました。", "mr", mr.WebURL, "updated", existing != nil)
+	return nil
+}
+
+// discuss は、差分の行に結び付けられる指摘事項をその行へのディスカッションとして作成し、マージリクエストに投稿するコメントの本文を返します。
+// 結び付けられる指摘事項がない場合や、差分を取得できない場合は、レビュー結果の全文をコメントの本文とします。
+// 同じコミットに対して以前にディスカッションを作成している場合 (以前のコメントにコミットが記録されている場合) は、重複して作成しません。
+func (c *GitLabMRCommenter) discuss(ctx context.Context, mr *MergeRequest, existing *mrNote, marker, publicURL, review string, cfg config.ReviewConfig) string {
+	full := buildReviewComment(marker, publicURL, review, cfg, gitlabNoteMaxChars)
+	if head := headCommitFromContext(ctx); head != "" && head != mr.DiffRefs.HeadSHA {
+		// ディスカッションの位置はマージリクエストの現在の差分で指定するため、古いコミットのレビューには作成できない
+		slog.Info("レビューしたコミットの後にマージリクエストへコミットが追加されているため、ディスカッションを作成せずにコメントを投稿します。", "mr", mr.WebURL, "reviewed", head, "head", mr.DiffRefs.HeadSHA)
+		return full
+	}
+	headMarker := reviewedHeadMarker + mr.DiffRefs.HeadSHA + " -->"
+	if existing != nil && strings.Contains(existing.Body, headMarker) {
+		slog.Info("このコミットのディスカッションは作成済みのため、コメントのみを更新します。", "mr", mr.WebURL, "commit", mr.DiffRefs.HeadSHA)
+		return existing.Body
+	}
+
+	report, list := findings.Split(review)
+	if ctxList, ok := findings.FromContext(ctx); ok {
+		list = ctxList
+	}
+	lines, err := c.diffLines(ctx, mr.IID)
+	if err != nil {
+		slog.Warn("マージリクエストの差分を取得できないため、インラインのディスカッションを作成せずにコメントを投稿します。", "mr", mr.WebURL, "error", err)
+		return full
+	}
+
+	var unanchored []findings.Finding
+	discussed := 0
+	for _, f := range list {
+		if f.File == "" || f.Line <= 0 || !lines[f.File][f.Line] || discussed >= gitlabMaxDiscussions {
+			unanchored = append(unanchored, f)
+			continue
+		}
+		if err := c.createDiscussion(ctx, mr, f, inlineCommentBody(report, f)); err != nil {
+			slog.Warn("指摘事項のディスカッションの作成に失敗したため、コメントに記載します。", "mr", mr.WebURL, "file", f.File, "line", f.Line, "error", err)
+			unanchored = append(unanchored, f)
+			continue
+		}
+		discussed++
+	}
+	if discussed == 0 {
+		slog.Info("マージリクエストの差分の行に結び付けられる指摘事項がないため、コメントのみを投稿します。", "mr", mr.WebURL, "findings", len(list))
+		return full
+	}
+	slog.Info("指摘事項をマージリクエストのディスカッションとして作成しました。", "mr", mr.WebURL, "discussions", discussed, "unanchored", len(unanchored))
+
+	body := reviewCommentHeader(marker, publicURL, cfg) + headMarker + "\n" + findings.Summarize(list).Markdown()
+	if len(unanchored) > 0 {
+		body += "\n### 差分の行に結び付けられなかった指摘事項\n\n"
+		for _, f := range unanchored {
+			body += "- " + f.Label() + "\n"
+		}
+	}
+	return body
+}
+
+// createDiscussion は、マージリクエストの差分の変更後の行に、指摘事項のディスカッションを作成します。
+func (c *GitLabMRCommenter) createDiscussion(ctx context.Context, mr *MergeRequest, f findings.Finding, body string) error {
+	req := map[string]any{
+		"body": body,
+		"position": map[string]any{
+			"position_type": "text",
+			"base_sha":      mr.DiffRefs.BaseSHA,
+			"start_sha":     mr.DiffRefs.StartSHA,
+			"head_sha":      mr.DiffRefs.HeadSHA,
+			"new_path":      f.File,
+			"old_path":      f.File,
+			"new_line":      f.Line,
+		},
+	}
+	return c.client.Do(ctx, http.MethodPost, fmt.Sprintf("merge_requests/%d/discussions", mr.IID), req, nil)
+}
+
+// diffLines は、マージリクエストの差分に含まれる (ディスカッションを作成できる) 変更後のファイルの行を返します。
+func (c *GitLabMRCommenter) diffLines(ctx context.Context, iid int) (map[string]map[int]bool, error) {
+	var diff strings.Builder
+	for page := 1; page <= apiListMaxPages; page++ {
+		var files []struct {
+			NewPath string `json:"new_path"`
+			Diff    string `json:"diff"`
+		}
+		if err := c.client.Do(ctx, http.MethodGet, fmt.Sprintf("merge_requests/%d/diffs?per_page=100&page=%d", iid, page), nil, &files); err != nil {
+			return nil, fmt.Errorf("マージリクエスト !%d の差分の取得に失敗しました: %w", iid, err)
+		}
+		for _, f := range files {
+			// diff はハンクのみのため、ファイルのヘッダを補って unified diff にする
+			if f.Diff != "" {
+				fmt.Fprintf(&diff, "diff --git a/%[1]s b/%[1]s\n--- a/%[1]s\n+++ b/%[1]s\n%[2]s\n", f.NewPath, strings.TrimRight(f.Diff, "\n"))
+			}
+		}
+		if len(files) < 100 {
+			break
+		}
+	}
+	return diffutil.NewLines(diff.String()), nil
+}
+
+// findNote は、マージリクエストのコメントから marker を含む (以前に投稿した) コメントを探します。見つからない場合は nil を返します。
+func (c *GitLabMRCommenter) findNote(ctx context.Context, iid int, marker string) (*mrNote, error) {
+	for page := 1; page <= apiListMaxPages; page++ {
+		var notes []mrNote
+		if err := c.client.Do(ctx, http.MethodGet, fmt.Sprintf("merge_requests/%d/notes?per_page=100&page=%d", iid, page), nil, &notes); err != nil {
+			return nil, fmt.Errorf("マージリクエスト !%d のコメントの取得に失敗しました: %w", iid, err)
+		}
+		for i := range notes {
+			if strings.Contains(notes[i].Body, marker) {
+				return &notes[i], nil
+			}
+		}
+		if len(notes) < 100 {
+			return nil, nil
+		}
+	}
+	return nil, nil
+}
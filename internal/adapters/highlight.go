@@ -0,0 +1,187 @@
+package adapters
+
+import (
+	"html/template"
+	"path"
+	"strings"
+)
+
+// syntax は、レポートに埋め込む差分を簡易的に色付けするための、言語ごとの字句の定義です。
+// 差分は変更前と変更後の行が混在するため、行をまたぐ状態 (複数行のコメントや文字列) は追跡せず、1行ごとに色付けします。
+type syntax struct {
+	lineComments []string        // 行末までのコメントの開始記号 (例: "//", "#")
+	blockComment [2]string       // 同じ行で閉じるブロックコメントの開始・終了記号 (例: "/*", "*/")
+	quotes       string          // 文字列リテラルの区切り文字
+	keywords     map[string]bool // 予約語
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var (
+	goSyntax = &syntax{
+		lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`",
+		keywords: words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota"),
+	}
+	jsSyntax = &syntax{
+		lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'`",
+		keywords: words("async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof interface let new null return super switch this throw try type typeof undefined var void while with yield true false"),
+	}
+	pySyntax = &syntax{
+		lineComments: []string{"#"}, quotes: "\"'",
+		keywords: words("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield None True False self"),
+	}
+	javaSyntax = &syntax{
+		lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'",
+		keywords: words("abstract boolean break byte case catch char class const continue default do double else enum extends final finally float for fun if implements import instanceof int interface long new null override package private protected public return short static super switch this throw throws try val var void volatile when while true false"),
+	}
+	cSyntax = &syntax{
+		lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"'",
+		keywords: words("auto bool break case char class const constexpr continue default delete do double else enum extern float for if inline int long namespace new nullptr private protected public return short signed sizeof static struct switch template this typedef union unsigned using virtual void volatile while true false NULL"),
+	}
+	rustSyntax = &syntax{
+		lineComments: []string{"//"}, blockComment: [2]string{"/*", "*/"}, quotes: "\"",
+		keywords: words("as async await break const continue crate else enum extern fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait type unsafe use where while true false"),
+	}
+	rubySyntax = &syntax{
+		lineComments: []string{"#"}, quotes: "\"'",
+		keywords: words("alias and begin break case class def defined? do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield"),
+	}
+	shellSyntax = &syntax{
+		lineComments: []string{"#"}, quotes: "\"'",
+		keywords: words("case do done elif else esac export fi for function if in local readonly return then until while"),
+	}
+	sqlSyntax = &syntax{
+		lineComments: []string{"--"}, blockComment: [2]string{"/*", "*/"}, quotes: "'\"",
+		keywords: words("ADD ALTER AND AS ASC BY CREATE DELETE DESC DROP FROM GROUP HAVING IN INDEX INSERT INTO IS JOIN KEY LEFT LIMIT NOT NULL ON OR ORDER PRIMARY SELECT SET TABLE UPDATE VALUES WHERE add alter and as asc by create delete desc drop from group having in index insert into is join key left limit not null on or order primary select set table update values where"),
+	}
+	configSyntax = &syntax{lineComments: []string{"#"}, quotes: "\"'"}
+)
+
+// syntaxes は、拡張子 (小文字、ドット付き) ごとの字句の定義です。
+var syntaxes = map[string]*syntax{
+	".go":   goSyntax,
+	".js":   jsSyntax,
+	".jsx":  jsSyntax,
+	".mjs":  jsSyntax,
+	".ts":   jsSyntax,
+	".tsx":  jsSyntax,
+	".py":   pySyntax,
+	".java": javaSyntax,
+	".kt":   javaSyntax,
+	".c":    cSyntax,
+	".h":    cSyntax,
+	".cc":   cSyntax,
+	".cpp":  cSyntax,
+	".hpp":  cSyntax,
+	".rs":   rustSyntax,
+	".rb":   rubySyntax,
+	".sh":   shellSyntax,
+	".bash": shellSyntax,
+	".sql":  sqlSyntax,
+	".yaml": configSyntax,
+	".yml":  configSyntax,
+	".toml": configSyntax,
+}
+
+// syntaxFor は、ファイルパスの拡張子に対応する字句の定義を返します。未対応の言語の場合は nil を返します。
+func syntaxFor(p string) *syntax {
+	return syntaxes[strings.ToLower(path.Ext(p))]
+}
+
+// highlight は、1行のコードを HTML エスケープし、予約語・文字列・コメント・数値を span 要素で囲んだ HTML を返します。
+// s が nil の場合は、エスケープのみを行います。
+func (s *syntax) highlight(line string) string {
+	if s == nil {
+		return template.HTMLEscapeString(line)
+	}
+
+	var b strings.Builder
+	span := func(class, text string) {
+		b.WriteString(`<span class="` + class + `">` + template.HTMLEscapeString(text) + `</span>`)
+	}
+	for i := 0; i < len(line); {
+		rest := line[i:]
+		if s.startsLineComment(rest) {
+			span("com", rest)
+			break
+		}
+		if open := s.blockComment[0]; open != "" && strings.HasPrefix(rest, open) {
+			end := len(rest)
+			if j := strings.Index(rest[len(open):], s.blockComment[1]); j >= 0 {
+				end = len(open) + j + len(s.blockComment[1])
+			}
+			span("com", rest[:end])
+			i += end
+			continue
+		}
+
+		c := line[i]
+		switch {
+		case strings.IndexByte(s.quotes, c) >= 0:
+			end := closingQuote(rest, c)
+			span("str", rest[:end])
+			i += end
+		case isIdentStart(c):
+			end := 1
+			for end < len(rest) && (isIdentStart(rest[end]) || isDigit(rest[end])) {
+				end++
+			}
+			if word := rest[:end]; s.keywords[word] {
+				span("kw", word)
+			} else {
+				b.WriteString(template.HTMLEscapeString(word))
+			}
+			i += end
+		case isDigit(c):
+			end := 1
+			for end < len(rest) && (isIdentStart(rest[end]) || isDigit(rest[end]) || rest[end] == '.') {
+				end++
+			}
+			span("num", rest[:end])
+			i += end
+		default:
+			b.WriteString(template.HTMLEscapeString(line[i : i+1]))
+			i++
+		}
+	}
+	return b.String()
+}
+
+func (s *syntax) startsLineComment(rest string) bool {
+	for _, prefix := range s.lineComments {
+		if strings.HasPrefix(rest, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// closingQuote は、rest の先頭の区切り文字 quote で始まる文字列リテラルの終端の位置 (終端の区切り文字の直後) を返します。
+// 行内で閉じていない場合は行末を返します。バッククォート以外ではバックスラッシュによるエスケープを考慮します。
+func closingQuote(rest string, quote byte) int {
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '\\':
+			if quote != '`' {
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(rest)
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c >= 0x80
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,37 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// htmlContentType は、HTML 形式で公開するレポートの Content-Type です。
+const htmlContentType = "text/html; charset=utf-8"
+
+// HTMLPublisher は、レビュー結果を ReportRenderer のテンプレートで HTML に変換し、ストレージに直接書き込む publisher.Publisher の実装です。
+// gemini-reviewer-core の Publisher は独自のテンプレートに対応していないため、GCS / S3 に独自のテンプレート (--html-template) で公開する場合に使用します。
+type HTMLPublisher struct {
+	store    objectstore.Store
+	renderer *ReportRenderer
+}
+
+// NewHTMLPublisher は HTMLPublisher の新しいインスタンスを作成します。
+func NewHTMLPublisher(store objectstore.Store, renderer *ReportRenderer) *HTMLPublisher {
+	return &HTMLPublisher{store: store, renderer: renderer}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (p *HTMLPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	page, err := p.renderer.HTML(ctx, data)
+	if err != nil {
+		return err
+	}
+	if err := p.store.Write(ctx, uri, page, htmlContentType); err != nil {
+		return fmt.Errorf("HTML 形式のレポートの公開に失敗しました: %w", err)
+	}
+	return nil
+}
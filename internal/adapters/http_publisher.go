@@ -0,0 +1,113 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+// IsHTTPURI は、URIが HTTP の公開先 (https:// または http://) を指すかを返します。
+// Azure Blob Storage の https:// URI は、呼び出し側で先に判定してください。
+func IsHTTPURI(uri string) bool {
+	return strings.HasPrefix(uri, "https://") || strings.HasPrefix(uri, "http://")
+}
+
+// httpReport は、HTTP の公開先に送信するレポートの JSON です。
+type httpReport struct {
+	RepoURL       string `json:"repo_url"`
+	BaseBranch    string `json:"base_branch"`
+	FeatureBranch string `json:"feature_branch"`
+	Markdown      string `json:"markdown"`
+	HTML          string `json:"html"`
+}
+
+// HTTPPublisher は、レビュー結果を JSON (Markdown と変換した HTML) として社内サービスなどに POST/PUT する publisher.Publisher の実装です。
+// クラウドストレージを経由せずに、独自のダッシュボードにレビュー結果を取り込むためのものです。
+// Markdown 形式 (--format markdown) の場合は JSON ではなく Markdown を、PDF 形式 (--format pdf) の場合は変換した PDF をそのまま送信します。
+type HTTPPublisher struct {
+	method   string
+	header   http.Header
+	format   string
+	client   httpkit.ClientInterface
+	renderer *ReportRenderer
+}
+
+// NewHTTPPublisher は、httpClient で送信する HTTPPublisher の新しいインスタンスを作成します。
+// header には認証ヘッダーなど、各リクエストに付加するヘッダーを指定します。method が空の場合は POST を使用します。
+func NewHTTPPublisher(httpClient httpkit.ClientInterface, method string, header http.Header, format string, renderer *ReportRenderer) *HTTPPublisher {
+	if method == "" {
+		method = http.MethodPost
+	}
+	return &HTTPPublisher{
+		method:   method,
+		header:   header,
+		format:   format,
+		client:   httpClient,
+		renderer: renderer,
+	}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。2xx 以外の応答はエラーとして返します。
+func (p *HTTPPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	body, contentType, err := p.body(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, p.method, uri, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	for name, values := range p.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("'%s' への送信に失敗しました: %w", uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("'%s' がエラーを返しました (status %d): %s", uri, resp.StatusCode, strings.TrimSpace(string(errBody)))
+	}
+	return nil
+}
+
+// body は、送信するリクエストの本文と Content-Type を返します。
+func (p *HTTPPublisher) body(ctx context.Context, data publisher.ReviewData) ([]byte, string, error) {
+	switch p.format {
+	case config.FormatMarkdown:
+		return []byte(data.ReviewMarkdown), markdownContentType, nil
+	case config.FormatPDF:
+		pdf, err := p.renderer.PDF(ctx, data)
+		return pdf, pdfContentType, err
+	}
+
+	page, err := p.renderer.HTML(ctx, data)
+	if err != nil {
+		return nil, "", err
+	}
+	body, err := json.Marshal(httpReport{
+		RepoURL:       data.RepoURL,
+		BaseBranch:    data.BaseBranch,
+		FeatureBranch: data.FeatureBranch,
+		Markdown:      data.ReviewMarkdown,
+		HTML:          string(page),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	return body, "application/json", nil
+}
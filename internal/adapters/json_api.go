@@ -0,0 +1,111 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrorBodyBytes は、エラー応答の本文をエラーメッセージに含める際の上限です。
+const maxErrorBodyBytes = 2048
+
+// StatusError は、JSON の HTTP API (OpenAI 互換・Ollama・Anthropic) がエラーのステータスを返したことを示すエラーです。
+// RetryingCodeReviewAI は、StatusCode で再試行の可否を、RetryAfter で待機時間を判定します。
+type StatusError struct {
+	API        string        // 呼び出した API の名前 (例: "Anthropic API")
+	Model      string        // 呼び出したモデル
+	StatusCode int           // HTTP ステータスコード
+	Body       string        // 応答の本文 (先頭 maxErrorBodyBytes バイト)
+	RetryAfter time.Duration // Retry-After ヘッダで指定された待機時間 (指定がない場合は 0)
+}
+
+// Error は error インターフェースの実装です。
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s がエラーを返しました (model: %s, status %d): %s", e.API, e.Model, e.StatusCode, e.Body)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+// jsonAPI は、JSON のリクエストを POST して JSON の応答を受け取る AI の API の呼び出し先です。
+type jsonAPI struct {
+	name     string // エラーメッセージに使用する API の名前
+	client   *http.Client
+	endpoint string
+	header   http.Header // Content-Type 以外に付加するヘッダ (認証など)
+	hint     string      // 接続に失敗した場合にエラーメッセージに追加する対処方法
+}
+
+// newJSONAPI は、endpoint を呼び出す jsonAPI を返します。
+// client が nil の場合は、プロキシ・TLS設定を反映した http.DefaultTransport を使用するクライアントを使用します。
+// AI の応答は遅いことがあるため、クライアントにはタイムアウトを設定せず、待機は context で制御します。
+func newJSONAPI(name string, client *http.Client, endpoint string, header http.Header) jsonAPI {
+	if client == nil {
+		client = &http.Client{}
+	}
+	return jsonAPI{name: name, client: client, endpoint: endpoint, header: header}
+}
+
+// post は、reqBody を JSON で POST し、成功した場合は応答を respBody に解析します。
+// 2xx 以外のステータスの場合は *StatusError を返します。
+func (a jsonAPI) post(ctx context.Context, model string, reqBody, respBody any) error {
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("リクエストの生成に失敗しました: %w", err)
+	}
+	for key, values := range a.header {
+		httpReq.Header[key] = values
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(httpReq)
+	if err != nil {
+		if a.hint != "" && ctx.Err() == nil {
+			return fmt.Errorf("%s の呼び出しに失敗しました (model: %s)。%s: %w", a.name, model, a.hint, err)
+		}
+		return fmt.Errorf("%s の呼び出しに失敗しました (model: %s): %w", a.name, model, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		errBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return &StatusError{
+			API:        a.name,
+			Model:      model,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(errBody)),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(respBody); err != nil {
+		return fmt.Errorf("%s の応答の解析に失敗しました: %w", a.name, err)
+	}
+	return nil
+}
+
+// parseRetryAfter は、Retry-After ヘッダの値 (秒数または HTTP-date) を待機時間に変換します。
+// 値がない・解析できない・過去の日時の場合は 0 を返します。
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(secs)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
@@ -1,13 +1,16 @@
 package adapters
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
 )
@@ -21,6 +24,7 @@ type LocalGitAdapter struct {
 	SSHKeyPath               string
 	BaseBranch               string
 	InsecureSkipHostKeyCheck bool
+	ReadOnly                 bool
 }
 
 // Option はLocalGitAdapterの初期化オプションを設定するための関数です。
@@ -40,6 +44,14 @@ func WithBaseBranch(branch string) Option {
 	}
 }
 
+// WithReadOnly は、ローカルリポジトリのワーキングツリーやブランチを変更しない読み取り専用モードを設定するオプションです。
+// 読み取り専用モードでは fetch によるリモート追跡ブランチの更新のみを行い、checkout や clean は実行しません。
+func WithReadOnly(readOnly bool) Option {
+	return func(ga *LocalGitAdapter) {
+		ga.ReadOnly = readOnly
+	}
+}
+
 // NewLocalGitAdapter は LocalGitAdapter を初期化します。
 // 戻り値の型をコアライブラリのインターフェース coreAdapters.GitService に変更
 func NewLocalGitAdapter(localPath string, sshKeyPath string, opts ...Option) coreAdapters.GitService {
@@ -91,6 +103,15 @@ func (ga *LocalGitAdapter) getEnvWithSSH() []string {
 	return env
 }
 
+// resolveRef は、ブランチ名をリモート追跡ブランチ (origin/<branch>) に変換します。
+// "refs/" で始まる完全な参照名 (refs/tags/v1.0.0 など) とコミットハッシュはそのまま返します。
+func resolveRef(name string) string {
+	if strings.HasPrefix(name, "refs/") || isCommitHash(name) {
+		return name
+	}
+	return fmt.Sprintf("origin/%s", name)
+}
+
 // runGitCommand は、指定されたGitコマンドをアダプタの設定（SSH環境変数など）で実行します。
 func (ga *LocalGitAdapter) runGitCommand(ctx context.Context, args ...string) (string, error) {
 	cmd := exec.CommandContext(ctx, "git", args...)
@@ -176,7 +197,8 @@ func (ga *LocalGitAdapter) CloneOrUpdate(ctx context.Context, repositoryURL stri
 
 // Fetch はリモートから最新の変更を取得します。
 func (ga *LocalGitAdapter) Fetch(ctx context.Context) error {
-	_, err := ga.runGitCommand(ctx, "fetch", "origin", "--prune")
+	// タグ範囲の差分にも対応できるよう、タグも取得する
+	_, err := ga.runGitCommand(ctx, "fetch", "origin", "--prune", "--tags")
 	if err != nil {
 		return fmt.Errorf("リモートからのフェッチに失敗しました: %w", err)
 	}
@@ -185,8 +207,8 @@ func (ga *LocalGitAdapter) Fetch(ctx context.Context) error {
 
 // GetCodeDiff は指定された2つのブランチ間の純粋な差分を、ローカルの 'git diff' コマンドで取得します。
 func (ga *LocalGitAdapter) GetCodeDiff(ctx context.Context, baseBranch, featureBranch string) (string, error) {
-	baseRef := fmt.Sprintf("origin/%s", baseBranch)
-	featureRef := fmt.Sprintf("origin/%s", featureBranch)
+	baseRef := resolveRef(baseBranch)
+	featureRef := resolveRef(featureBranch)
 
 	// 1. 存在チェック
 	_, err := ga.runGitCommand(ctx, "rev-parse", "--verify", baseRef)
@@ -213,6 +235,272 @@ func (ga *LocalGitAdapter) GetCodeDiff(ctx context.Context, baseBranch, featureB
 	return diffOutput, nil
 }
 
+// ResolveRef は、'git rev-parse' でブランチ (またはタグ) が指すコミットのハッシュを返します。
+// RefResolver インターフェースの実装です。
+func (ga *LocalGitAdapter) ResolveRef(ctx context.Context, ref string) (string, error) {
+	hash, err := ga.runGitCommand(ctx, "rev-parse", "--verify", resolveRef(ref)+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("参照 '%s' の解決に失敗しました: %w", ref, err)
+	}
+	return hash, nil
+}
+
+// ListRemoteBranches は、'git for-each-ref' でリモート 'origin' のブランチ一覧を取得します。
+// BranchLister インターフェースの実装です。
+func (ga *LocalGitAdapter) ListRemoteBranches(ctx context.Context) ([]string, error) {
+	output, err := ga.runGitCommand(ctx, "for-each-ref", "--format=%(refname:lstrip=3)", "refs/remotes/origin")
+	if err != nil {
+		return nil, fmt.Errorf("リモートブランチの一覧取得に失敗しました: %w", err)
+	}
+	var branches []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" && line != "HEAD" {
+			branches = append(branches, line)
+		}
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// GetCommitLog は、'git log origin/base..origin/feature' でブランチ (またはタグ) 間のコミットログを取得します。
+// CommitLogProvider インターフェースの実装です。マージコミットは含めません。
+func (ga *LocalGitAdapter) GetCommitLog(ctx context.Context, baseBranch, featureBranch string) ([]Commit, error) {
+	// フィールド区切りに US (\x1f)、レコード区切りに RS (\x1e) を使用し、本文中の改行と衝突しないようにする
+	logArgs := []string{
+		"log",
+		"--no-merges",
+		"--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e",
+		fmt.Sprintf("%s..%s", resolveRef(baseBranch), resolveRef(featureBranch)),
+	}
+
+	output, err := ga.runGitCommand(ctx, logArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("コミットログの取得に失敗しました: %w", err)
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(output, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[3])
+		subject, body := splitMessage(fields[4])
+		commits = append(commits, Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    date,
+			Subject: subject,
+			Body:    body,
+		})
+	}
+	return commits, nil
+}
+
+// GetFileHistory は、'git log --name-only <ref> -- <paths>' でファイルの変更履歴を取得します。
+// FileHistoryProvider インターフェースの実装です。
+func (ga *LocalGitAdapter) GetFileHistory(ctx context.Context, ref string, paths []string, since time.Time) ([]FileCommit, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+	// レコードの先頭に RS (\x1e) を置き、コミット情報の行と、続く変更ファイルの行を区切る
+	logArgs := []string{
+		"log",
+		"--no-merges",
+		"--name-only",
+		"--format=%x1e%H%x1f%an%x1f%ae%x1f%aI%x1f%s",
+		"--since=" + since.Format(time.RFC3339),
+		resolveRef(ref),
+		"--",
+	}
+	logArgs = append(logArgs, paths...)
+
+	output, err := ga.runGitCommand(ctx, logArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの変更履歴の取得に失敗しました: %w", err)
+	}
+
+	var commits []FileCommit
+	for _, record := range strings.Split(output, "\x1e") {
+		header, files, _ := strings.Cut(strings.TrimSpace(record), "\n")
+		fields := strings.SplitN(header, "\x1f", 5)
+		if len(fields) != 5 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[3])
+		fc := FileCommit{
+			Commit: Commit{Hash: fields[0], Author: fields[1], Email: fields[2], Date: date, Subject: fields[4]},
+		}
+		for _, f := range strings.Split(files, "\n") {
+			if f = strings.TrimSpace(f); f != "" {
+				fc.Files = append(fc.Files, f)
+			}
+		}
+		commits = append(commits, fc)
+	}
+	return commits, nil
+}
+
+// GetFileContent は、'git cat-file blob origin/<branch>:<path>' でローカルリポジトリからブランチ時点のファイル内容を取得します。
+// FileContentProvider インターフェースの実装です。ワーキングツリーの状態には依存しません。
+// バイナリファイルのサイズを正しく求められるよう、内容は加工せず (前後の空白や改行を取り除かず) にそのまま返します。
+func (ga *LocalGitAdapter) GetFileContent(ctx context.Context, ref, path string) ([]byte, error) {
+	// 存在しないパスでは 'git show' が失敗してエラーログが出力されるため、先に ls-tree で存在を確認する
+	// (ls-tree は存在しないパスに対しても成功し、空の出力を返す)
+	entry, err := ga.runGitCommand(ctx, "ls-tree", resolveRef(ref), "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイル '%s' の確認に失敗しました: %w", path, err)
+	}
+	if entry == "" {
+		return nil, fmt.Errorf("ファイル '%s' は存在しません: %w", path, os.ErrNotExist)
+	}
+
+	content, err := ga.readBlob(ctx, fmt.Sprintf("%s:%s", resolveRef(ref), path))
+	if err != nil {
+		return nil, fmt.Errorf("ファイル '%s' の取得に失敗しました: %w", path, err)
+	}
+	return content, nil
+}
+
+// readBlob は、'git cat-file blob' で object (例: "origin/main:path/to/file") の内容をそのまま返します。
+// runGitCommand とは異なり、標準エラー出力を内容に混在させず、出力の前後の空白も取り除きません。
+func (ga *LocalGitAdapter) readBlob(ctx context.Context, object string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "blob", object)
+	cmd.Dir = ga.LocalPath
+	cmd.Env = ga.getEnvWithSSH()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	slog.Debug("Gitコマンドを実行中", "dir", cmd.Dir, "args", cmd.Args[1:])
+	content, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file に失敗しました: %w. 出力:\n%s", err, strings.TrimSpace(stderr.String()))
+	}
+	return content, nil
+}
+
+// ListFiles は、'git ls-tree' でブランチ時点のディレクトリ直下のファイルを取得します。
+// FileContentProvider インターフェースの実装です。
+func (ga *LocalGitAdapter) ListFiles(ctx context.Context, ref, dir string) ([]string, error) {
+	args := []string{"ls-tree", resolveRef(ref)}
+	if dir != "" && dir != "." {
+		args = append(args, strings.TrimSuffix(dir, "/")+"/")
+	}
+	output, err := ga.runGitCommand(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ディレクトリ '%s' のファイル一覧の取得に失敗しました: %w", dir, err)
+	}
+
+	return parseLsTreeBlobs(output), nil
+}
+
+// ListTree は、'git ls-tree -r' でブランチ時点のすべてのファイルを取得します。
+// FileContentProvider インターフェースの実装です。
+func (ga *LocalGitAdapter) ListTree(ctx context.Context, ref string) ([]string, error) {
+	output, err := ga.runGitCommand(ctx, "ls-tree", "-r", resolveRef(ref))
+	if err != nil {
+		return nil, fmt.Errorf("ファイル一覧の取得に失敗しました (ref: %s): %w", ref, err)
+	}
+	return parseLsTreeBlobs(output), nil
+}
+
+// parseLsTreeBlobs は、'git ls-tree' の出力からファイル (blob) のパスを取り出します。
+// 出力形式: "<mode> <type> <object>\t<path>" (サブディレクトリ (tree) とサブモジュール (commit) は除外する)
+func parseLsTreeBlobs(output string) []string {
+	var files []string
+	for _, line := range strings.Split(output, "\n") {
+		meta, p, ok := strings.Cut(line, "\t")
+		if ok && strings.Contains(meta, " blob ") {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// CheckPatch は、一時的なインデックスにブランチ時点のツリーを読み込み、'git apply --check --cached' でパッチを適用できるかを確認します。
+// PatchChecker インターフェースの実装です。ワーキングツリーとリポジトリのインデックスは変更しません。
+func (ga *LocalGitAdapter) CheckPatch(ctx context.Context, ref, patch string) error {
+	dir, err := os.MkdirTemp("", "git-gemini-cli-patch-*")
+	if err != nil {
+		return fmt.Errorf("一時ディレクトリの作成に失敗しました: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	env := append(ga.getEnvWithSSH(), "GIT_INDEX_FILE="+filepath.Join(dir, "index"))
+	if err := ga.runGitWithInput(ctx, env, "", "read-tree", resolveRef(ref)); err != nil {
+		return err
+	}
+	return ga.runGitWithInput(ctx, env, patch, "apply", "--check", "--cached", "-")
+}
+
+// ApplyPatch は、'git apply' でパッチをワーキングツリーに適用します。PatchApplier インターフェースの実装です。
+func (ga *LocalGitAdapter) ApplyPatch(ctx context.Context, patch string) error {
+	if ga.ReadOnly {
+		return ErrReadOnlyWorkTree
+	}
+	return ga.runGitWithInput(ctx, ga.getEnvWithSSH(), patch, "apply", "-")
+}
+
+// PrepareWorkTree は、ワーキングツリーに未コミットの変更がないことを確認し、ブランチ時点のコミットをチェックアウトします。
+// newBranch が指定された場合はそのコミットから新しいブランチを作成し、未指定の場合は detached HEAD にします。
+// PatchApplier インターフェースの実装です。
+func (ga *LocalGitAdapter) PrepareWorkTree(ctx context.Context, ref, newBranch string) error {
+	if ga.ReadOnly {
+		return ErrReadOnlyWorkTree
+	}
+	status, err := ga.runGitCommand(ctx, "status", "--porcelain")
+	if err != nil {
+		return fmt.Errorf("ワーキングツリーの状態の確認に失敗しました: %w", err)
+	}
+	if status != "" {
+		return fmt.Errorf("%w: %s", ErrDirtyWorkTree, ga.LocalPath)
+	}
+
+	args := []string{"checkout", "--detach", resolveRef(ref)}
+	if newBranch != "" {
+		args = []string{"checkout", "-b", newBranch, resolveRef(ref)}
+	}
+	if _, err := ga.runGitCommand(ctx, args...); err != nil {
+		return fmt.Errorf("'%s' のチェックアウトに失敗しました: %w", ref, err)
+	}
+	return nil
+}
+
+// CommitAll は、ワーキングツリーの変更をすべてコミットし、作成したコミットのハッシュを返します。
+// PatchApplier インターフェースの実装です。コミットの作成者はローカルの Git の設定 (user.name / user.email) に従います。
+func (ga *LocalGitAdapter) CommitAll(ctx context.Context, message string) (string, error) {
+	if ga.ReadOnly {
+		return "", ErrReadOnlyWorkTree
+	}
+	if _, err := ga.runGitCommand(ctx, "add", "-A"); err != nil {
+		return "", fmt.Errorf("変更のステージに失敗しました: %w", err)
+	}
+	if err := ga.runGitWithInput(ctx, ga.getEnvWithSSH(), message, "commit", "-q", "-F", "-"); err != nil {
+		return "", fmt.Errorf("コミットの作成に失敗しました: %w", err)
+	}
+	return ga.runGitCommand(ctx, "rev-parse", "HEAD")
+}
+
+// runGitWithInput は、標準入力と環境変数を指定して Gitコマンドを実行します。
+// 失敗が想定される確認 (パッチを適用できるか) にも使用するため、runGitCommand と異なり失敗時にエラーログを出力しません。
+func (ga *LocalGitAdapter) runGitWithInput(ctx context.Context, env []string, stdin string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = ga.LocalPath
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(stdin)
+
+	slog.Debug("Gitコマンドを実行中", "dir", cmd.Dir, "args", args)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s に失敗しました: %w. 出力:\n%s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
 // CheckRemoteBranchExists は指定されたブランチがリモート 'origin' に存在するか確認します。
 func (ga *LocalGitAdapter) CheckRemoteBranchExists(ctx context.Context, branch string) (bool, error) {
 	if branch == "" {
@@ -231,7 +519,13 @@ func (ga *LocalGitAdapter) CheckRemoteBranchExists(ctx context.Context, branch s
 }
 
 // Cleanup はクリーンアップを実行します。
+// 読み取り専用モードでは、ワーキングツリーを変更しないよう何も行いません。
 func (ga *LocalGitAdapter) Cleanup(ctx context.Context) error {
+	if ga.ReadOnly {
+		slog.Debug("読み取り専用モードのため、クリーンアップ (checkout -B / clean) をスキップします。", "path", ga.LocalPath)
+		return nil
+	}
+
 	slog.Info("クリーンアップ: fetch -> checkout -B -> clean を実行します。", "path", ga.LocalPath)
 
 	remote := "origin"
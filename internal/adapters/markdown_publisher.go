@@ -0,0 +1,32 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// markdownContentType は、Markdown 形式で公開するレポートの Content-Type です。
+const markdownContentType = "text/markdown; charset=utf-8"
+
+// MarkdownPublisher は、レビュー結果の Markdown を HTML に変換せずにそのまま保存する publisher.Publisher の実装です。
+// Wiki や静的サイトジェネレーターなど、HTML ではなく元の Markdown を取り込む用途に使用します (--format markdown)。
+type MarkdownPublisher struct {
+	store objectstore.Store
+}
+
+// NewMarkdownPublisher は MarkdownPublisher の新しいインスタンスを作成します。
+func NewMarkdownPublisher(store objectstore.Store) *MarkdownPublisher {
+	return &MarkdownPublisher{store: store}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (p *MarkdownPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	if err := p.store.Write(ctx, uri, []byte(data.ReviewMarkdown), markdownContentType); err != nil {
+		return fmt.Errorf("Markdown 形式のレポートの公開に失敗しました: %w", err)
+	}
+	return nil
+}
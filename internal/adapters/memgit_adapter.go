@@ -0,0 +1,452 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"golang.org/x/crypto/ssh"
+)
+
+// remoteName は、インメモリクローンで使用するリモート名です。
+const remoteName = "origin"
+
+// MemoryGitAdapter は、go-git のインメモリストレージ (memfs) 上にリポジトリをクローンするアダプタです。
+// ディスクへの書き込みを一切行わないため、LocalPath が不要で、CI の使い捨て環境や小規模リポジトリに適しています。
+// リポジトリ全体をメモリに保持するため、大規模リポジトリでは LocalGitAdapter の使用を推奨します。
+// coreAdapters.GitService インターフェースを実装します。
+type MemoryGitAdapter struct {
+	SSHKeyPath               string
+	BaseBranch               string
+	InsecureSkipHostKeyCheck bool
+
+	repo *git.Repository
+	auth transport.AuthMethod
+}
+
+// MemoryOption は MemoryGitAdapter の初期化オプションを設定するための関数です。
+type MemoryOption func(*MemoryGitAdapter)
+
+// WithMemoryInsecureSkipHostKeyCheck はSSHホストキーチェックをスキップするオプションを設定します。
+func WithMemoryInsecureSkipHostKeyCheck(skip bool) MemoryOption {
+	return func(ma *MemoryGitAdapter) {
+		ma.InsecureSkipHostKeyCheck = skip
+	}
+}
+
+// WithMemoryBaseBranch はベースブランチを設定するオプションです。
+func WithMemoryBaseBranch(branch string) MemoryOption {
+	return func(ma *MemoryGitAdapter) {
+		ma.BaseBranch = branch
+	}
+}
+
+// NewMemoryGitAdapter は MemoryGitAdapter を初期化します。
+func NewMemoryGitAdapter(sshKeyPath string, opts ...MemoryOption) coreAdapters.GitService {
+	adapter := &MemoryGitAdapter{
+		SSHKeyPath: sshKeyPath,
+		BaseBranch: "main",
+	}
+
+	for _, opt := range opts {
+		opt(adapter)
+	}
+
+	return adapter
+}
+
+// buildAuth は、リポジトリURLに応じた認証方法を構築します。
+// SSH URL 以外 (https:// など) の場合は nil を返し、匿名アクセスとします。
+func (ma *MemoryGitAdapter) buildAuth(repositoryURL string) (transport.AuthMethod, error) {
+	if ma.SSHKeyPath == "" || strings.HasPrefix(repositoryURL, "http://") || strings.HasPrefix(repositoryURL, "https://") {
+		return nil, nil
+	}
+
+	keys, err := gitssh.NewPublicKeysFromFile("git", ma.SSHKeyPath, "")
+	if err != nil {
+		return nil, fmt.Errorf("SSH秘密鍵 '%s' の読み込みに失敗しました: %w", ma.SSHKeyPath, err)
+	}
+	if ma.InsecureSkipHostKeyCheck {
+		keys.HostKeyCallback = ssh.InsecureIgnoreHostKey()
+	}
+	return keys, nil
+}
+
+// --- coreAdapters.GitService インターフェースの実装 ---
+
+// CloneOrUpdate はリポジトリをメモリ上にクローンします。既にクローン済みの場合は何もしません。
+func (ma *MemoryGitAdapter) CloneOrUpdate(ctx context.Context, repositoryURL string) error {
+	if ma.repo != nil {
+		return nil
+	}
+
+	auth, err := ma.buildAuth(repositoryURL)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("リポジトリをメモリ上にクローンします。", "url", repositoryURL)
+	// ワーキングツリーを持たない (worktree = nil) ベアクローンとして作成
+	repo, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:        repositoryURL,
+		Auth:       auth,
+		RemoteName: remoteName,
+		Tags:       git.AllTags,
+	})
+	if err != nil {
+		return fmt.Errorf("リポジトリのインメモリクローンに失敗しました: %w", err)
+	}
+
+	ma.repo = repo
+	ma.auth = auth
+	slog.Info("リポジトリのインメモリクローンに成功しました。")
+	return nil
+}
+
+// Fetch はリモートから最新の変更を取得します。
+func (ma *MemoryGitAdapter) Fetch(ctx context.Context) error {
+	if ma.repo == nil {
+		return errors.New("リポジトリがクローンされていません")
+	}
+
+	err := ma.repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: remoteName,
+		Auth:       ma.auth,
+		Tags:       git.AllTags,
+		Force:      true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("リモートからのフェッチに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// GetCodeDiff は、マージベースからフィーチャーブランチまでの差分 (git diff base...feature 相当) を返します。
+func (ma *MemoryGitAdapter) GetCodeDiff(ctx context.Context, baseBranch, featureBranch string) (string, error) {
+	if ma.repo == nil {
+		return "", errors.New("リポジトリがクローンされていません")
+	}
+
+	baseCommit, err := ma.remoteCommit(baseBranch)
+	if err != nil {
+		return "", fmt.Errorf("ベースブランチ '%s' の参照解決に失敗しました: %w", baseBranch, err)
+	}
+	featureCommit, err := ma.remoteCommit(featureBranch)
+	if err != nil {
+		return "", fmt.Errorf("フィーチャーブランチ '%s' の参照解決に失敗しました: %w", featureBranch, err)
+	}
+
+	bases, err := baseCommit.MergeBase(featureCommit)
+	if err != nil {
+		return "", fmt.Errorf("マージベースの計算に失敗しました: %w", err)
+	}
+	if len(bases) == 0 {
+		return "", fmt.Errorf("ブランチ '%s' と '%s' に共通の祖先がありません", baseBranch, featureBranch)
+	}
+
+	patch, err := bases[0].PatchContext(ctx, featureCommit)
+	if err != nil {
+		return "", fmt.Errorf("差分計算に失敗しました: %w", err)
+	}
+
+	return strings.TrimSpace(patch.String()), nil
+}
+
+// ResolveRef は、ブランチ (またはタグ) が指すコミットのハッシュを返します。
+// RefResolver インターフェースの実装です。
+func (ma *MemoryGitAdapter) ResolveRef(ctx context.Context, ref string) (string, error) {
+	if ma.repo == nil {
+		return "", errors.New("リポジトリがクローンされていません")
+	}
+	commit, err := ma.remoteCommit(ref)
+	if err != nil {
+		return "", fmt.Errorf("参照 '%s' の解決に失敗しました: %w", ref, err)
+	}
+	return commit.Hash.String(), nil
+}
+
+// ListRemoteBranches は、リモート 'origin' の追跡ブランチの一覧を返します。
+// BranchLister インターフェースの実装です。
+func (ma *MemoryGitAdapter) ListRemoteBranches(ctx context.Context) ([]string, error) {
+	if ma.repo == nil {
+		return nil, errors.New("リポジトリがクローンされていません")
+	}
+	refs, err := ma.repo.References()
+	if err != nil {
+		return nil, fmt.Errorf("リモートブランチの一覧取得に失敗しました: %w", err)
+	}
+	defer refs.Close()
+
+	prefix := "refs/remotes/" + remoteName + "/"
+	var branches []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name, ok := strings.CutPrefix(ref.Name().String(), prefix)
+		if ok && name != "HEAD" {
+			branches = append(branches, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("リモートブランチの一覧取得に失敗しました: %w", err)
+	}
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// GetCommitLog は、フィーチャーブランチからマージベースに到達するまでのコミットを新しい順に返します。
+// CommitLogProvider インターフェースの実装です。マージコミットは含めません。
+func (ma *MemoryGitAdapter) GetCommitLog(ctx context.Context, baseBranch, featureBranch string) ([]Commit, error) {
+	if ma.repo == nil {
+		return nil, errors.New("リポジトリがクローンされていません")
+	}
+
+	baseCommit, err := ma.remoteCommit(baseBranch)
+	if err != nil {
+		return nil, fmt.Errorf("ベースブランチ '%s' の参照解決に失敗しました: %w", baseBranch, err)
+	}
+	featureCommit, err := ma.remoteCommit(featureBranch)
+	if err != nil {
+		return nil, fmt.Errorf("フィーチャーブランチ '%s' の参照解決に失敗しました: %w", featureBranch, err)
+	}
+
+	bases, err := baseCommit.MergeBase(featureCommit)
+	if err != nil {
+		return nil, fmt.Errorf("マージベースの計算に失敗しました: %w", err)
+	}
+	stopAt := make(map[plumbing.Hash]bool, len(bases))
+	for _, b := range bases {
+		stopAt[b.Hash] = true
+	}
+
+	iter, err := ma.repo.Log(&git.LogOptions{From: featureCommit.Hash})
+	if err != nil {
+		return nil, fmt.Errorf("コミットログの取得に失敗しました: %w", err)
+	}
+	defer iter.Close()
+
+	var commits []Commit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if stopAt[c.Hash] {
+			return storer.ErrStop
+		}
+		if c.NumParents() > 1 {
+			return nil
+		}
+		subject, body := splitMessage(c.Message)
+		commits = append(commits, Commit{
+			Hash:    c.Hash.String(),
+			Author:  c.Author.Name,
+			Email:   c.Author.Email,
+			Date:    c.Author.When,
+			Subject: subject,
+			Body:    body,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("コミットログの走査に失敗しました: %w", err)
+	}
+	return commits, nil
+}
+
+// GetFileHistory は、メモリ上のリポジトリからファイルの変更履歴を取得します。
+// FileHistoryProvider インターフェースの実装です。
+func (ma *MemoryGitAdapter) GetFileHistory(ctx context.Context, ref string, paths []string, since time.Time) ([]FileCommit, error) {
+	if ma.repo == nil {
+		return nil, errors.New("リポジトリがクローンされていません")
+	}
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	commit, err := ma.remoteCommit(ref)
+	if err != nil {
+		return nil, fmt.Errorf("参照 '%s' の解決に失敗しました: %w", ref, err)
+	}
+	wanted := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		wanted[p] = true
+	}
+
+	iter, err := ma.repo.Log(&git.LogOptions{
+		From:       commit.Hash,
+		Since:      &since,
+		PathFilter: func(p string) bool { return wanted[p] },
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの変更履歴の取得に失敗しました: %w", err)
+	}
+	defer iter.Close()
+
+	var commits []FileCommit
+	err = iter.ForEach(func(c *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if c.NumParents() > 1 {
+			return nil
+		}
+		stats, err := c.Stats()
+		if err != nil {
+			return err
+		}
+		subject, _ := splitMessage(c.Message)
+		fc := FileCommit{
+			Commit: Commit{Hash: c.Hash.String(), Author: c.Author.Name, Email: c.Author.Email, Date: c.Author.When, Subject: subject},
+		}
+		for _, st := range stats {
+			if wanted[st.Name] {
+				fc.Files = append(fc.Files, st.Name)
+			}
+		}
+		commits = append(commits, fc)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ファイルの変更履歴の走査に失敗しました: %w", err)
+	}
+	return commits, nil
+}
+
+// GetFileContent は、メモリ上のリポジトリからブランチ時点のファイル内容を取得します。
+// FileContentProvider インターフェースの実装です。
+func (ma *MemoryGitAdapter) GetFileContent(ctx context.Context, ref, path string) ([]byte, error) {
+	if ma.repo == nil {
+		return nil, errors.New("リポジトリがクローンされていません")
+	}
+
+	commit, err := ma.remoteCommit(ref)
+	if err != nil {
+		return nil, fmt.Errorf("参照 '%s' の解決に失敗しました: %w", ref, err)
+	}
+	file, err := commit.File(path)
+	if err != nil {
+		return nil, fmt.Errorf("ファイル '%s' の取得に失敗しました: %w", path, err)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("ファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	return []byte(content), nil
+}
+
+// ListFiles は、メモリ上のリポジトリからブランチ時点のディレクトリ直下のファイルを取得します。
+// FileContentProvider インターフェースの実装です。
+func (ma *MemoryGitAdapter) ListFiles(ctx context.Context, ref, dir string) ([]string, error) {
+	if ma.repo == nil {
+		return nil, errors.New("リポジトリがクローンされていません")
+	}
+
+	commit, err := ma.remoteCommit(ref)
+	if err != nil {
+		return nil, fmt.Errorf("参照 '%s' の解決に失敗しました: %w", ref, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("ツリーの取得に失敗しました: %w", err)
+	}
+	dir = strings.Trim(dir, "/")
+	if dir != "" && dir != "." {
+		if tree, err = tree.Tree(dir); err != nil {
+			return nil, fmt.Errorf("ディレクトリ '%s' の取得に失敗しました: %w", dir, err)
+		}
+	}
+
+	var files []string
+	for _, entry := range tree.Entries {
+		if entry.Mode.IsFile() {
+			files = append(files, path.Join(dir, entry.Name))
+		}
+	}
+	return files, nil
+}
+
+// ListTree は、メモリ上のリポジトリからブランチ時点のすべてのファイルを取得します。
+// FileContentProvider インターフェースの実装です。
+func (ma *MemoryGitAdapter) ListTree(ctx context.Context, ref string) ([]string, error) {
+	if ma.repo == nil {
+		return nil, errors.New("リポジトリがクローンされていません")
+	}
+
+	commit, err := ma.remoteCommit(ref)
+	if err != nil {
+		return nil, fmt.Errorf("参照 '%s' の解決に失敗しました: %w", ref, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("ツリーの取得に失敗しました: %w", err)
+	}
+
+	var files []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		files = append(files, f.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ファイル一覧の取得に失敗しました: %w", err)
+	}
+	return files, nil
+}
+
+// CheckRemoteBranchExists は指定されたブランチがリモート 'origin' に存在するか確認します。
+func (ma *MemoryGitAdapter) CheckRemoteBranchExists(ctx context.Context, branch string) (bool, error) {
+	if branch == "" {
+		return false, fmt.Errorf("リモートブランチの存在確認に失敗しました: ブランチ名が空です")
+	}
+	if ma.repo == nil {
+		return false, errors.New("リポジトリがクローンされていません")
+	}
+
+	_, err := ma.repo.Reference(plumbing.NewRemoteReferenceName(remoteName, branch), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("リモートブランチ '%s' の確認に失敗しました: %w", branch, err)
+	}
+	return true, nil
+}
+
+// Cleanup は、メモリ上のリポジトリを破棄します。ディスク上には何も残りません。
+func (ma *MemoryGitAdapter) Cleanup(ctx context.Context) error {
+	ma.repo = nil
+	ma.auth = nil
+	slog.Debug("インメモリリポジトリを破棄しました。")
+	return nil
+}
+
+// remoteCommit は、リモート追跡ブランチ origin/<branch> が指すコミットを返します。
+// "refs/" で始まる完全な参照名 (refs/tags/v1.0.0 など) は、注釈付きタグも含めてコミットに解決します。
+// コミットハッシュは、そのコミットを返します。
+func (ma *MemoryGitAdapter) remoteCommit(branch string) (*object.Commit, error) {
+	if isCommitHash(branch) {
+		return ma.repo.CommitObject(plumbing.NewHash(branch))
+	}
+	if strings.HasPrefix(branch, "refs/") {
+		hash, err := ma.repo.ResolveRevision(plumbing.Revision(branch))
+		if err != nil {
+			return nil, err
+		}
+		return ma.repo.CommitObject(*hash)
+	}
+
+	ref, err := ma.repo.Reference(plumbing.NewRemoteReferenceName(remoteName, branch), true)
+	if err != nil {
+		return nil, err
+	}
+	return ma.repo.CommitObject(ref.Hash())
+}
@@ -0,0 +1,109 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/usage"
+)
+
+// OllamaAdapter は、Ollama の HTTP API (/api/chat) を呼び出すアダプタです。
+// ローカル・閉域網で動作する LLM でレビューすることで、差分をクラウドの API に送信せずに済みます。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type OllamaAdapter struct {
+	api    jsonAPI
+	model  string
+	params map[string]config.GenerationParams
+	numCtx int // コンテキスト長 (0 の場合はサーバー・モデルの既定値)
+}
+
+// ollamaChatRequest は、Ollama の /api/chat のリクエストです。
+type ollamaChatRequest struct {
+	Model    string         `json:"model"`
+	Messages []chatMessage  `json:"messages"`
+	Stream   bool           `json:"stream"`
+	Options  map[string]any `json:"options,omitempty"`
+}
+
+// ollamaChatResponse は、Ollama の /api/chat のレスポンスのうち、使用する項目です。
+type ollamaChatResponse struct {
+	Message         chatMessage `json:"message"`
+	DoneReason      string      `json:"done_reason"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+	EvalCount       int         `json:"eval_count"`
+}
+
+// NewOllamaAdapter は、OllamaAdapter を初期化します。
+// host には "http://localhost:11434" のような Ollama サーバーのURLを、numCtx にはモデルのコンテキスト長を指定します。
+// Ollama の既定のコンテキスト長は差分全体を含めるには短く、超過した部分は警告なく切り捨てられるため、大きな差分では numCtx の指定を推奨します。
+// httpClient が nil の場合は既定のクライアントを使用します。
+func NewOllamaAdapter(httpClient *http.Client, host, model string, numCtx int, params map[string]config.GenerationParams) (*OllamaAdapter, error) {
+	if host == "" {
+		return nil, errors.New("Ollama サーバーのURLが指定されていません")
+	}
+	if !strings.Contains(host, "://") {
+		// OLLAMA_HOST は "127.0.0.1:11434" のようにスキームを省略して指定されることが多い
+		host = "http://" + host
+	}
+
+	api := newJSONAPI("Ollama サーバー", httpClient, strings.TrimRight(host, "/")+"/api/chat", nil)
+	api.hint = "サーバーが起動しているか (ollama serve) 確認してください"
+	return &OllamaAdapter{
+		api:    api,
+		model:  model,
+		params: params,
+		numCtx: numCtx,
+	}, nil
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (a *OllamaAdapter) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	mode, params := paramsFor(ctx, a.params)
+
+	temperature := defaultTemperature
+	if params.Temperature != nil {
+		temperature = *params.Temperature
+	}
+	options := map[string]any{"temperature": temperature}
+	if params.TopP != nil {
+		options["top_p"] = *params.TopP
+	}
+	if params.MaxOutputTokens > 0 {
+		options["num_predict"] = params.MaxOutputTokens
+	}
+	if a.numCtx > 0 {
+		options["num_ctx"] = a.numCtx
+	}
+
+	var chat ollamaChatResponse
+	err := a.api.post(ctx, a.model, ollamaChatRequest{
+		Model:    a.model,
+		Messages: []chatMessage{{Role: "user", Content: prompt}},
+		Options:  options,
+	}, &chat)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("Ollama サーバーにモデル '%s' がありません。'ollama pull %s' で取得してください: %w", a.model, a.model, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	usage.ReportTokens(ctx, chat.PromptEvalCount, chat.EvalCount)
+	if chat.Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+	slog.Debug("Ollama サーバーから応答を受信しました。", "mode", mode, "prompt_tokens", chat.PromptEvalCount, "output_tokens", chat.EvalCount)
+	if a.numCtx > 0 && chat.PromptEvalCount >= a.numCtx {
+		slog.Warn("プロンプトがモデルのコンテキスト長に達したため、差分の一部が切り捨てられている可能性があります。--ollama-num-ctx を大きくするか、--max-prompt-tokens と --on-budget-exceeded=chunk で分割してください。",
+			"prompt_tokens", chat.PromptEvalCount, "num_ctx", a.numCtx)
+	}
+	if chat.DoneReason == "length" {
+		slog.Warn("生成されたトークン数が上限に達したため、レビュー結果が途中で途切れている可能性があります。", "mode", mode, "maxOutputTokens", params.MaxOutputTokens)
+	}
+	return chat.Message.Content, nil
+}
@@ -0,0 +1,81 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestOllamaAdapter は、Ollama の /api/chat へのリクエストと、応答・エラーの扱いを検証します。
+func TestOllamaAdapter(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		retryAfter  string
+		response    string
+		want        string
+		wantStatus  int
+		wantWait    time.Duration
+		wantMessage string
+	}{
+		{
+			name:     "応答",
+			status:   http.StatusOK,
+			response: `{"message":{"role":"assistant","content":"LGTM"},"done_reason":"stop","prompt_eval_count":10,"eval_count":2}`,
+			want:     "LGTM",
+		},
+		{
+			name:        "モデルがない場合は取得方法を示す",
+			status:      http.StatusNotFound,
+			response:    `{"error":"model \"llama-test\" not found"}`,
+			wantStatus:  http.StatusNotFound,
+			wantMessage: "ollama pull llama-test",
+		},
+		{
+			name:       "503 は再試行できる StatusError を返す",
+			status:     http.StatusServiceUnavailable,
+			retryAfter: "3",
+			response:   `{"error":"server busy"}`,
+			wantStatus: http.StatusServiceUnavailable,
+			wantWait:   3 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/chat" {
+					t.Errorf("path = %q, want /api/chat", r.URL.Path)
+				}
+				var req ollamaChatRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("リクエストの解析に失敗しました: %v", err)
+				}
+				if req.Model != "llama-test" || req.Stream || req.Options["num_ctx"] != float64(8192) {
+					t.Errorf("request = %+v, want model llama-test, stream なし, num_ctx 8192", req)
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			// スキームを省略した OLLAMA_HOST の形式も受け付ける
+			a, err := NewOllamaAdapter(server.Client(), strings.TrimPrefix(server.URL, "http://"), "llama-test", 8192, nil)
+			if err != nil {
+				t.Fatalf("NewOllamaAdapter() error = %v", err)
+			}
+			got, err := a.ReviewCodeDiff(context.Background(), "prompt")
+			checkJSONAPIResult(t, got, err, tt.want, tt.wantStatus, tt.wantWait)
+			if tt.wantMessage != "" && (err == nil || !strings.Contains(err.Error(), tt.wantMessage)) {
+				t.Errorf("ReviewCodeDiff() error = %v, want %q を含む", err, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/usage"
+)
+
+// ErrOpenAIAPIKeyNotSet は、環境変数 OPENAI_API_KEY が設定されていないことを示すエラーです。
+var ErrOpenAIAPIKeyNotSet = errors.New("環境変数 OPENAI_API_KEY が設定されていません")
+
+// OpenAIAdapter は、OpenAI の Chat Completions API 互換のエンドポイント (OpenAI, Azure OpenAI, 社内ゲートウェイなど) を
+// 呼び出すアダプタです。coreAdapters.CodeReviewAI インターフェースを実装します。
+type OpenAIAdapter struct {
+	api    jsonAPI
+	azure  bool // true の場合、Azure OpenAI の形式 (api-key ヘッダ、デプロイメント名をURLに含める) で呼び出す
+	model  string
+	params map[string]config.GenerationParams
+}
+
+// chatRequest は、Chat Completions API のリクエストです。
+type chatRequest struct {
+	Model       string        `json:"model,omitempty"`
+	Messages    []chatMessage `json:"messages"`
+	Temperature *float32      `json:"temperature,omitempty"`
+	TopP        *float32      `json:"top_p,omitempty"`
+	MaxTokens   int32         `json:"max_tokens,omitempty"`
+}
+
+// chatMessage は、Chat Completions API のメッセージです。
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatResponse は、Chat Completions API のレスポンスのうち、使用する項目です。
+type chatResponse struct {
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// NewOpenAIAdapter は、環境変数 OPENAI_API_KEY を使用して OpenAIAdapter を初期化します。
+// baseURL には "https://api.openai.com/v1" のような API のベースURLを指定します。
+// apiVersion を指定した場合は Azure OpenAI とみなし、baseURL にはリソースのURL ("https://<resource>.openai.azure.com")、
+// model にはデプロイメント名を指定します。httpClient が nil の場合は既定のクライアントを使用します。
+func NewOpenAIAdapter(httpClient *http.Client, baseURL, apiVersion, model string, params map[string]config.GenerationParams) (*OpenAIAdapter, error) {
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, ErrOpenAIAPIKeyNotSet
+	}
+	if _, err := url.Parse(baseURL); err != nil || baseURL == "" {
+		return nil, fmt.Errorf("OpenAI 互換 API のベースURLが不正です: %s", baseURL)
+	}
+
+	azure := apiVersion != ""
+	base := strings.TrimRight(baseURL, "/")
+	endpoint := base + "/chat/completions"
+	header := http.Header{"Authorization": {"Bearer " + apiKey}}
+	if azure {
+		endpoint = fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			base, url.PathEscape(model), url.QueryEscape(apiVersion))
+		header = http.Header{"Api-Key": {apiKey}}
+	}
+	return &OpenAIAdapter{
+		api:    newJSONAPI("OpenAI 互換 API", httpClient, endpoint, header),
+		azure:  azure,
+		model:  model,
+		params: params,
+	}, nil
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (a *OpenAIAdapter) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	mode, params := paramsFor(ctx, a.params)
+	req := chatRequest{
+		Messages:    []chatMessage{{Role: "user", Content: prompt}},
+		Temperature: params.Temperature,
+		TopP:        params.TopP,
+		MaxTokens:   params.MaxOutputTokens,
+	}
+	if req.Temperature == nil {
+		temperature := defaultTemperature
+		req.Temperature = &temperature
+	}
+	// Azure OpenAI ではモデルはURLのデプロイメント名で指定する
+	if !a.azure {
+		req.Model = a.model
+	}
+
+	var chat chatResponse
+	if err := a.api.post(ctx, a.model, req, &chat); err != nil {
+		return "", err
+	}
+	usage.ReportTokens(ctx, chat.Usage.PromptTokens, chat.Usage.CompletionTokens)
+	if len(chat.Choices) == 0 || chat.Choices[0].Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+	if chat.Choices[0].FinishReason == "length" {
+		slog.Warn("生成されたトークン数が上限に達したため、レビュー結果が途中で途切れている可能性があります。", "mode", mode, "maxOutputTokens", params.MaxOutputTokens)
+	}
+	return chat.Choices[0].Message.Content, nil
+}
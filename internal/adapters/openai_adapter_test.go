@@ -0,0 +1,127 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestOpenAIAdapter は、Chat Completions API 互換のエンドポイントへのリクエストと、応答・エラーの扱いを検証します。
+func TestOpenAIAdapter(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		status     int
+		retryAfter string
+		response   string
+		wantPath   string
+		wantHeader [2]string
+		wantModel  string
+		want       string
+		wantStatus int
+		wantWait   time.Duration
+	}{
+		{
+			name:       "OpenAI",
+			status:     http.StatusOK,
+			response:   `{"choices":[{"message":{"role":"assistant","content":"LGTM"},"finish_reason":"stop"}],"usage":{"prompt_tokens":10,"completion_tokens":2}}`,
+			wantPath:   "/v1/chat/completions",
+			wantHeader: [2]string{"Authorization", "Bearer test-key"},
+			wantModel:  "gpt-test",
+			want:       "LGTM",
+		},
+		{
+			name:       "Azure OpenAI はデプロイメント名をURLで指定する",
+			apiVersion: "2024-06-01",
+			status:     http.StatusOK,
+			response:   `{"choices":[{"message":{"role":"assistant","content":"LGTM"}}]}`,
+			wantPath:   "/openai/deployments/gpt-test/chat/completions",
+			wantHeader: [2]string{"Api-Key", "test-key"},
+			want:       "LGTM",
+		},
+		{
+			name:       "429 は Retry-After の待機時間を持つ StatusError を返す",
+			status:     http.StatusTooManyRequests,
+			retryAfter: "7",
+			response:   `{"error":{"message":"rate limited"}}`,
+			wantPath:   "/v1/chat/completions",
+			wantHeader: [2]string{"Authorization", "Bearer test-key"},
+			wantModel:  "gpt-test",
+			wantStatus: http.StatusTooManyRequests,
+			wantWait:   7 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPENAI_API_KEY", "test-key")
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.wantPath {
+					t.Errorf("path = %q, want %q", r.URL.Path, tt.wantPath)
+				}
+				if got := r.Header.Get(tt.wantHeader[0]); got != tt.wantHeader[1] {
+					t.Errorf("%s = %q, want %q", tt.wantHeader[0], got, tt.wantHeader[1])
+				}
+				var req chatRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("リクエストの解析に失敗しました: %v", err)
+				}
+				if req.Model != tt.wantModel || len(req.Messages) != 1 || req.Messages[0].Content != "prompt" {
+					t.Errorf("request = %+v, want model %q と prompt のメッセージ", req, tt.wantModel)
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			baseURL := server.URL + "/v1"
+			if tt.apiVersion != "" {
+				baseURL = server.URL
+			}
+			a, err := NewOpenAIAdapter(server.Client(), baseURL, tt.apiVersion, "gpt-test", nil)
+			if err != nil {
+				t.Fatalf("NewOpenAIAdapter() error = %v", err)
+			}
+			got, err := a.ReviewCodeDiff(context.Background(), "prompt")
+			checkJSONAPIResult(t, got, err, tt.want, tt.wantStatus, tt.wantWait)
+		})
+	}
+}
+
+// checkJSONAPIResult は、アダプタの応答が want であること、または wantStatus の StatusError であることを検証します。
+// エラーの場合は、RetryingCodeReviewAI が再試行の可否と待機時間を StatusError から判定できることも検証します。
+func checkJSONAPIResult(t *testing.T, got string, err error, want string, wantStatus int, wantWait time.Duration) {
+	t.Helper()
+	if wantStatus == 0 {
+		if err != nil {
+			t.Fatalf("ReviewCodeDiff() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("ReviewCodeDiff() = %q, want %q", got, want)
+		}
+		return
+	}
+
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("ReviewCodeDiff() error = %v, want *StatusError", err)
+	}
+	if statusErr.StatusCode != wantStatus {
+		t.Errorf("StatusCode = %d, want %d", statusErr.StatusCode, wantStatus)
+	}
+	retryable := wantStatus == http.StatusTooManyRequests || wantStatus >= http.StatusInternalServerError
+	if isRetryable(err) != retryable {
+		t.Errorf("isRetryable() = %v, want %v", isRetryable(err), retryable)
+	}
+	wait, ok := retryAfter(err)
+	if wait != wantWait || ok != (wantWait > 0) {
+		t.Errorf("retryAfter() = %v, %v, want %v", wait, ok, wantWait)
+	}
+}
@@ -0,0 +1,27 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrPatchApplyUnsupported は、使用中の GitService がワーキングツリーへのパッチの適用に対応していないことを示すエラーです。
+	ErrPatchApplyUnsupported = errors.New("使用中のGitアダプタはパッチの適用に対応していません")
+	// ErrReadOnlyWorkTree は、読み取り専用モードのためワーキングツリーを変更できないことを示すエラーです。
+	ErrReadOnlyWorkTree = errors.New("読み取り専用モード (--read-only) のため、ワーキングツリーを変更できません")
+	// ErrDirtyWorkTree は、ワーキングツリーに未コミットの変更があることを示すエラーです。
+	ErrDirtyWorkTree = errors.New("ワーキングツリーに未コミットの変更があります")
+)
+
+// PatchApplier は、ローカルのクローンのワーキングツリーにパッチを適用し、コミットを作成できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type PatchApplier interface {
+	// PrepareWorkTree は、ワーキングツリーに未コミットの変更がないことを確認し、ブランチの時点のコミットをチェックアウトします。
+	// newBranch が指定された場合は、そのコミットから新しいブランチを作成します。
+	PrepareWorkTree(ctx context.Context, ref, newBranch string) error
+	// ApplyPatch は、unified diff 形式のパッチをワーキングツリーに適用します。
+	ApplyPatch(ctx context.Context, patch string) error
+	// CommitAll は、ワーキングツリーの変更をすべてコミットし、作成したコミットのハッシュを返します。
+	CommitAll(ctx context.Context, message string) (string, error)
+}
@@ -0,0 +1,17 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPatchCheckUnsupported は、使用中の GitService がパッチの適用確認に対応していないことを示すエラーです。
+var ErrPatchCheckUnsupported = errors.New("使用中のGitアダプタはパッチの適用確認に対応していません")
+
+// PatchChecker は、パッチがブランチの時点のファイルに適用できるかを確認できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type PatchChecker interface {
+	// CheckPatch は、unified diff 形式のパッチが、ブランチ (または "refs/" で始まる完全な参照名) の時点のファイルに
+	// 適用できるかを確認します。ワーキングツリーとインデックスは変更しません。
+	CheckPatch(ctx context.Context, ref, patch string) error
+}
@@ -0,0 +1,114 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+const (
+	// pdfContentType は、PDF 形式で公開するレポートの Content-Type です。
+	pdfContentType = "application/pdf"
+	// pdfRenderTimeout は、ヘッドレスブラウザによる PDF への変換のタイムアウトです。
+	pdfRenderTimeout = 2 * time.Minute
+)
+
+// chromeCandidates は、環境変数 CHROME_PATH が未設定の場合に PATH から探すブラウザのコマンド名です。
+var chromeCandidates = []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser"}
+
+// findChrome は、PDF への変換に使用するヘッドレスブラウザ (Chrome / Chromium) の実行ファイルのパスを返します。
+func findChrome() (string, error) {
+	if path := os.Getenv("CHROME_PATH"); path != "" {
+		return path, nil
+	}
+	for _, name := range chromeCandidates {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("PDF への変換に必要な Chrome / Chromium が見つかりません。インストールするか、環境変数 CHROME_PATH に実行ファイルのパスを指定してください")
+}
+
+// PDF は、レポートの HTML (ReportRenderer.HTML) をヘッドレスブラウザで印刷し、PDF を生成します。
+// 監査証跡や承認のワークフローに添付できるよう、HTML 形式と同じテンプレートから生成します。
+func (r *ReportRenderer) PDF(ctx context.Context, data publisher.ReviewData) ([]byte, error) {
+	page, err := r.HTML(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	chrome, err := findChrome()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "git-gemini-cli-pdf-")
+	if err != nil {
+		return nil, fmt.Errorf("PDF への変換用の一時ディレクトリの作成に失敗しました: %w", err)
+	}
+	defer os.RemoveAll(dir)
+	htmlPath := filepath.Join(dir, "report.html")
+	pdfPath := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(htmlPath, page, 0o600); err != nil {
+		return nil, fmt.Errorf("PDF への変換用の HTML の書き込みに失敗しました: %w", err)
+	}
+
+	args := []string{
+		"--headless",
+		"--disable-gpu",
+		"--no-first-run",
+		"--no-pdf-header-footer",
+		"--user-data-dir=" + filepath.Join(dir, "profile"), // 実行中のブラウザのプロファイルと競合しないようにする
+		"--print-to-pdf=" + pdfPath,
+	}
+	if os.Geteuid() == 0 {
+		// root で実行した場合 (CI のコンテナなど)、Chrome はサンドボックスを無効にしないと起動しない
+		args = append(args, "--no-sandbox")
+	}
+	args = append(args, "file://"+filepath.ToSlash(htmlPath))
+
+	ctx, cancel := context.WithTimeout(ctx, pdfRenderTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, chrome, args...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("PDF への変換に失敗しました (%s): %w: %s", chrome, err, strings.TrimSpace(string(out)))
+	}
+	pdf, err := os.ReadFile(pdfPath)
+	if err != nil {
+		return nil, fmt.Errorf("PDF への変換に失敗しました (%s): 出力ファイルが生成されていません: %w", chrome, err)
+	}
+	return pdf, nil
+}
+
+// PDFPublisher は、レビュー結果を HTML 形式と同じテンプレートから PDF に変換して保存する publisher.Publisher の実装です (--format pdf)。
+// 変換にはヘッドレスブラウザ (Chrome / Chromium) を使用します。
+type PDFPublisher struct {
+	store    objectstore.Store
+	renderer *ReportRenderer
+}
+
+// NewPDFPublisher は PDFPublisher の新しいインスタンスを作成します。
+func NewPDFPublisher(store objectstore.Store, renderer *ReportRenderer) *PDFPublisher {
+	return &PDFPublisher{store: store, renderer: renderer}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (p *PDFPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	pdf, err := p.renderer.PDF(ctx, data)
+	if err != nil {
+		return err
+	}
+	if err := p.store.Write(ctx, uri, pdf, pdfContentType); err != nil {
+		return fmt.Errorf("PDF 形式のレポートの公開に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// maxLinkedIssues は、プルリクエストの説明から取得する関連するイシューの上限です。
+const maxLinkedIssues = 5
+
+// closingIssuePattern は、プルリクエストの説明から、マージ時にクローズするイシューへの参照 (Fixes #123 など) を抽出する正規表現です。
+var closingIssuePattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+#(\d+)\b`)
+
+// PRMetadata は、レビュー対象のブランチに対応するプルリクエスト (マージリクエスト) の、変更の意図を示す情報です。
+type PRMetadata struct {
+	Number      int
+	Title       string
+	Description string
+	URL         string
+	Issues      []LinkedIssue // 説明などで関連付けられたイシュー
+}
+
+// LinkedIssue は、プルリクエストに関連付けられたイシューです。
+type LinkedIssue struct {
+	Number int
+	Title  string
+	Body   string
+	URL    string
+}
+
+// PRMetadataFetcher は、フィーチャーブランチのオープンなプルリクエストのメタデータを取得する契約を定義します。
+// 該当するプルリクエストがない場合は nil を返します。
+type PRMetadataFetcher interface {
+	FetchPRMetadata(ctx context.Context, head, base string) (*PRMetadata, error)
+}
+
+// FetchPRMetadata は PRMetadataFetcher インターフェースの実装です。
+// プルリクエストの説明のクローズキーワード (Fixes #123 など) で参照されたイシューを関連するイシューとして取得します。
+func (c *GitHubClient) FetchPRMetadata(ctx context.Context, head, base string) (*PRMetadata, error) {
+	pr, err := c.FindPullRequest(ctx, 0, head, base)
+	if err != nil || pr == nil {
+		return nil, err
+	}
+	md := &PRMetadata{Number: pr.Number, Title: pr.Title, Description: pr.Body, URL: pr.HTMLURL}
+	for _, number := range closingIssues(pr.Body) {
+		var issue struct {
+			Title   string `json:"title"`
+			Body    string `json:"body"`
+			HTMLURL string `json:"html_url"`
+		}
+		if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("issues/%d", number), nil, &issue); err != nil {
+			if !errors.Is(err, ErrGitHubNotFound) {
+				slog.Warn("関連するイシューの取得に失敗したため、スキップします。", "repo", c.Repo(), "issue", number, "error", err)
+			}
+			continue
+		}
+		md.Issues = append(md.Issues, LinkedIssue{Number: number, Title: issue.Title, Body: issue.Body, URL: issue.HTMLURL})
+	}
+	return md, nil
+}
+
+// FetchPRMetadata は PRMetadataFetcher インターフェースの実装です。
+// マージ時にクローズされるイシュー (説明のクローズキーワードなどで関連付けられたもの) を関連するイシューとして取得します。
+func (c *GitLabClient) FetchPRMetadata(ctx context.Context, head, base string) (*PRMetadata, error) {
+	mr, err := c.FindMergeRequest(ctx, 0, head, base)
+	if err != nil || mr == nil {
+		return nil, err
+	}
+	md := &PRMetadata{Number: mr.IID, Title: mr.Title, Description: mr.Description, URL: mr.WebURL}
+	var issues []struct {
+		IID         int    `json:"iid"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		WebURL      string `json:"web_url"`
+	}
+	if err := c.Do(ctx, http.MethodGet, fmt.Sprintf("merge_requests/%d/closes_issues", mr.IID), nil, &issues); err != nil {
+		slog.Warn("マージリクエストに関連するイシューの取得に失敗したため、スキップします。", "mr", mr.WebURL, "error", err)
+		return md, nil
+	}
+	for _, issue := range issues[:min(len(issues), maxLinkedIssues)] {
+		md.Issues = append(md.Issues, LinkedIssue{Number: issue.IID, Title: issue.Title, Body: issue.Description, URL: issue.WebURL})
+	}
+	return md, nil
+}
+
+// FetchPRMetadata は PRMetadataFetcher インターフェースの実装です。
+// Bitbucket のイシューは Jira などの外部のサービスで管理されることが多いため、関連するイシューは取得しません。
+func (c *BitbucketClient) FetchPRMetadata(ctx context.Context, head, base string) (*PRMetadata, error) {
+	pr, err := c.FindPullRequest(ctx, 0, head, base)
+	if err != nil || pr == nil {
+		return nil, err
+	}
+	return &PRMetadata{Number: pr.ID, Title: pr.Title, Description: pr.Description, URL: pr.WebURL()}, nil
+}
+
+// closingIssues は、説明のクローズキーワードで参照されたイシューの番号を、重複を除いて最大 maxLinkedIssues 件返します。
+func closingIssues(description string) []int {
+	var numbers []int
+	seen := make(map[int]bool)
+	for _, m := range closingIssuePattern.FindAllStringSubmatch(description, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || seen[n] {
+			continue
+		}
+		seen[n] = true
+		numbers = append(numbers, n)
+		if len(numbers) >= maxLinkedIssues {
+			break
+		}
+	}
+	return numbers
+}
@@ -0,0 +1,116 @@
+package adapters
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// RateLimiter は、1分あたりのリクエスト数とトークン数の上限を守るよう AI の呼び出しを待機させる、トークンバケット方式のレート制限です。
+// 分割レビューや複数のレビューで同じ RateLimiter を共有することで、プロセス全体の呼び出しを API のクォータ内に収めます。
+type RateLimiter struct {
+	mu       sync.Mutex
+	requests *bucket // nil の場合はリクエスト数を制限しない
+	tokens   *bucket // nil の場合はトークン数を制限しない
+}
+
+// NewRateLimiter は、1分あたり requestsPerMinute 回、tokensPerMinute トークンまで呼び出しを許可する RateLimiter を返します。
+// 0 以下の値を指定した項目は制限しません。
+func NewRateLimiter(requestsPerMinute, tokensPerMinute int) *RateLimiter {
+	now := time.Now()
+	return &RateLimiter{
+		requests: newBucket(requestsPerMinute, now),
+		tokens:   newBucket(tokensPerMinute, now),
+	}
+}
+
+// Wait は、tokens トークンのリクエストを送信できるまで待機します。待機中に ctx が終了した場合は、予約を取り消してエラーを返します。
+// 1分あたりの上限を超えるトークン数のリクエストは、バケットが満杯になるまで待機した上で送信を許可します。
+func (l *RateLimiter) Wait(ctx context.Context, tokens int) error {
+	l.mu.Lock()
+	now := time.Now()
+	wait := max(l.requests.reserve(1, now), l.tokens.reserve(tokens, now))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	slog.Info("API のレート制限に収めるため、AI の呼び出しを待機します。", "wait", wait.Round(time.Millisecond), "tokens", tokens)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.requests.cancel(1)
+		l.tokens.cancel(tokens)
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// bucket は、1分で満杯になる速度で補充されるトークンバケットです。
+// 予約により残量が負になった場合、その分は後続の呼び出しの待機時間として返されます。
+type bucket struct {
+	capacity float64
+	perSec   float64
+	level    float64
+	last     time.Time
+}
+
+// newBucket は、1分あたり perMinute の容量を持つ満杯のバケットを返します。perMinute が 0 以下の場合は nil を返します。
+func newBucket(perMinute int, now time.Time) *bucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &bucket{capacity: float64(perMinute), perSec: float64(perMinute) / 60, level: float64(perMinute), last: now}
+}
+
+// reserve は、バケットから n を予約し、予約した分を使用できるまでの待機時間を返します。
+// 容量を超える n は容量に切り詰めます。
+func (b *bucket) reserve(n int, now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.level = min(b.capacity, b.level+now.Sub(b.last).Seconds()*b.perSec)
+	b.last = now
+	b.level -= min(float64(n), b.capacity)
+	if b.level >= 0 {
+		return 0
+	}
+	return time.Duration(-b.level / b.perSec * float64(time.Second))
+}
+
+// cancel は、reserve で予約した n をバケットに戻します。
+func (b *bucket) cancel(n int) {
+	if b == nil {
+		return
+	}
+	b.level = min(b.capacity, b.level+min(float64(n), b.capacity))
+}
+
+// RateLimitedCodeReviewAI は、AI の呼び出しの前に RateLimiter で待機するデコレータです。
+// プロンプトのトークン数は EstimateTokens による概算値を使用します。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type RateLimitedCodeReviewAI struct {
+	next    coreAdapters.CodeReviewAI
+	limiter *RateLimiter
+}
+
+// NewRateLimitedCodeReviewAI は、next の呼び出しを limiter のレート制限に従わせる CodeReviewAI を返します。
+func NewRateLimitedCodeReviewAI(next coreAdapters.CodeReviewAI, limiter *RateLimiter) *RateLimitedCodeReviewAI {
+	return &RateLimitedCodeReviewAI{next: next, limiter: limiter}
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (r *RateLimitedCodeReviewAI) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	if err := r.limiter.Wait(ctx, EstimateTokens(prompt)); err != nil {
+		return "", err
+	}
+	return r.next.ReviewCodeDiff(ctx, prompt)
+}
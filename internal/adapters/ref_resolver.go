@@ -0,0 +1,26 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// ErrRefResolveUnsupported は、使用中の GitService が参照のコミットハッシュへの解決に対応していないことを示すエラーです。
+var ErrRefResolveUnsupported = errors.New("使用中のGitアダプタは参照のコミットハッシュへの解決に対応していません")
+
+// RefResolver は、ブランチやタグが指すコミットハッシュを取得できる GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type RefResolver interface {
+	// ResolveRef は、ブランチ (または "refs/" で始まる完全な参照名) が指すコミットのハッシュを返します。
+	ResolveRef(ctx context.Context, ref string) (string, error)
+}
+
+// commitHashPattern は、省略されていないコミットハッシュ (SHA-1 / SHA-256) の形式です。
+var commitHashPattern = regexp.MustCompile(`^([0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// isCommitHash は、参照が省略されていないコミットハッシュかを返します。
+// GitService の実装は、コミットハッシュをブランチ名ではなくコミットとして扱います (前回レビューしたコミットからの差分など)。
+func isCommitHash(ref string) bool {
+	return commitHashPattern.MatchString(ref)
+}
@@ -0,0 +1,69 @@
+package adapters
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"git-gemini-cli/internal/diffutil"
+)
+
+// maxEmbeddedDiffLines は、レポートに埋め込む差分の1ファイルあたりの最大行数です。超えた部分は省略します。
+const maxEmbeddedDiffLines = 2000
+
+// renderDiffHTML は、レビュー対象の差分をファイルごとに折りたたみ可能な HTML (details 要素) に変換し、構文を色付けします。
+// 行の種類は line add / line del / line ctx / line hunk、構文は kw / str / com / num のクラスで表します。
+func renderDiffHTML(diff string) template.HTML {
+	var b strings.Builder
+	for _, f := range diffutil.ParseFiles(diff) {
+		status := ""
+		switch {
+		case f.Created:
+			status = " (追加)"
+		case f.Deleted:
+			status = " (削除)"
+		case f.OldPath != "" && f.OldPath != f.Path:
+			status = " (" + template.HTMLEscapeString(f.OldPath) + " から名前を変更)"
+		}
+		fmt.Fprintf(&b, `<details class="diff-file"><summary><code>%s</code>%s <span class="diff-stat"><span class="add">+%d</span> <span class="del">-%d</span></span></summary>`,
+			template.HTMLEscapeString(f.Path), status, len(f.Added), len(f.Removed))
+		if f.Binary {
+			b.WriteString(`<p class="diff-note">バイナリファイルのため、差分を表示しません。</p></details>`)
+			continue
+		}
+
+		syn := syntaxFor(f.Path)
+		b.WriteString(`<pre class="diff"><code>`)
+		inHunk, lines := false, 0
+		for _, line := range strings.Split(f.Raw, "\n") {
+			if strings.HasPrefix(line, "@@") {
+				inHunk = true
+			}
+			if !inHunk {
+				// diff --git / index / --- / +++ などのヘッダはサマリーに表示済みのため省略する
+				continue
+			}
+			if lines == maxEmbeddedDiffLines {
+				fmt.Fprintf(&b, `<span class="line hunk">… %d 行を超えるため、以降の差分を省略しました。</span>`, maxEmbeddedDiffLines)
+				break
+			}
+			lines++
+
+			switch {
+			case strings.HasPrefix(line, "@@"):
+				b.WriteString(`<span class="line hunk">` + template.HTMLEscapeString(line) + `</span>`)
+			case strings.HasPrefix(line, "+"):
+				b.WriteString(`<span class="line add">+` + syn.highlight(line[1:]) + `</span>`)
+			case strings.HasPrefix(line, "-"):
+				b.WriteString(`<span class="line del">-` + syn.highlight(line[1:]) + `</span>`)
+			case strings.HasPrefix(line, " "):
+				b.WriteString(`<span class="line ctx"> ` + syn.highlight(line[1:]) + `</span>`)
+			default:
+				// "\ No newline at end of file" など
+				b.WriteString(`<span class="line ctx">` + template.HTMLEscapeString(line) + `</span>`)
+			}
+		}
+		b.WriteString(`</code></pre></details>`)
+	}
+	return template.HTML(b.String())
+}
@@ -0,0 +1,137 @@
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"time"
+
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/timeutil"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// reportTemplate は、レポートの組み込みの HTML テンプレートです (--html-template が未指定の場合に使用します)。
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 960px; margin: 2rem auto; padding: 0 1rem; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "Hiragino Sans", "Noto Sans JP", sans-serif; line-height: 1.7; color: #1f2328; }
+header { border-bottom: 1px solid #d0d7de; margin-bottom: 1.5rem; }
+header p { color: #59636e; margin-top: 0; }
+pre { background: #f6f8fa; padding: 1rem; overflow-x: auto; border-radius: 6px; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.9em; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid #d0d7de; padding: 0.3rem 0.7rem; }
+blockquote { margin: 0; padding-left: 1rem; border-left: 4px solid #d0d7de; color: #59636e; }
+.diff-file { border: 1px solid #d0d7de; border-radius: 6px; margin-bottom: 0.5rem; }
+.diff-file summary { cursor: pointer; padding: 0.4rem 0.7rem; background: #f6f8fa; }
+.diff-stat .add { color: #1a7f37; }
+.diff-stat .del { color: #cf222e; }
+pre.diff { margin: 0; padding: 0; border-radius: 0 0 6px 6px; }
+pre.diff .line { display: block; padding: 0 1rem; white-space: pre; }
+pre.diff .add { background: #e6ffec; }
+pre.diff .del { background: #ffebe9; }
+pre.diff .hunk { color: #0969da; background: #ddf4ff; }
+pre.diff .kw { color: #cf222e; }
+pre.diff .str { color: #0a3069; }
+pre.diff .com { color: #6e7781; font-style: italic; }
+pre.diff .num { color: #0550ae; }
+@media print { .diff-file { break-inside: avoid-page; } }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+{{if .RepoURL}}<p>{{.RepoURL}}{{if .FeatureBranch}} ({{.BaseBranch}}...{{.FeatureBranch}}){{end}}</p>{{end}}
+</header>
+<main>
+{{.Body}}
+</main>
+{{if .Diff}}<section>
+<h2>レビュー対象の差分</h2>
+{{.Diff}}
+</section>{{end}}
+</body>
+</html>
+`))
+
+// ReportPage は、レポートの HTML テンプレートに渡すデータです。
+// 独自のテンプレート (--html-template) では、ReviewData のフィールド (RepoURL / BaseBranch / FeatureBranch / ReviewMarkdown) に加えて、
+// 以下のフィールドを参照できます。
+type ReportPage struct {
+	publisher.ReviewData
+	Title       string        // レポートのタイトル
+	Body        template.HTML // レビュー結果を HTML に変換したもの
+	GeneratedAt string        // レポートの生成日時 (設定済みのタイムゾーンでの表記)
+	Diff        template.HTML // レビュー対象の差分をファイルごとに折りたたみ可能にした HTML (--embed-diff を指定しない場合は空)
+}
+
+// ReportRenderer は、レビュー結果の Markdown を HTML テンプレートに埋め込み、レポートのページを生成します。
+// HTML を生成するすべての公開先 (ローカルファイル・Azure・https://・sftp://・PDF) で共有します。
+type ReportRenderer struct {
+	markdown goldmark.Markdown
+	tmpl     *template.Template
+}
+
+// NewReportRenderer は ReportRenderer の新しいインスタンスを作成します。tmpl が nil の場合は組み込みのテンプレートを使用します。
+func NewReportRenderer(tmpl *template.Template) *ReportRenderer {
+	if tmpl == nil {
+		tmpl = reportTemplate
+	}
+	return &ReportRenderer{
+		markdown: goldmark.New(goldmark.WithExtensions(extension.GFM)),
+		tmpl:     tmpl,
+	}
+}
+
+// LoadReportTemplate は、社内のブランディング (ロゴ・CSS・フッター) に合わせた独自の HTML テンプレート (html/template 形式) を読み込みます。
+// 存在しないフィールドの参照などの誤りに公開時ではなく起動時に気づけるよう、空のデータで一度実行して検証します。
+func LoadReportTemplate(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("HTML テンプレートの読み込みに失敗しました: %w", err)
+	}
+	tmpl, err := template.New("report").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("HTML テンプレート %s の解析に失敗しました: %w", path, err)
+	}
+	if err := tmpl.Execute(io.Discard, ReportPage{}); err != nil {
+		return nil, fmt.Errorf("HTML テンプレート %s の検証に失敗しました: %w", path, err)
+	}
+	return tmpl, nil
+}
+
+// HTML は、レビュー結果の Markdown を HTML に変換し、レポートのページを生成します。
+// context にレビュー対象の差分が格納されている場合 (diffutil.NewContext) は、色付けした差分もページに埋め込みます。
+func (r *ReportRenderer) HTML(ctx context.Context, data publisher.ReviewData) ([]byte, error) {
+	var body bytes.Buffer
+	if err := r.markdown.Convert([]byte(data.ReviewMarkdown), &body); err != nil {
+		return nil, fmt.Errorf("レビュー結果の HTML への変換に失敗しました: %w", err)
+	}
+
+	pageData := ReportPage{
+		ReviewData:  data,
+		Title:       "AI コードレビュー結果",
+		Body:        template.HTML(body.String()), // goldmark は既定で生の HTML を出力しない
+		GeneratedAt: timeutil.FormatReport(time.Now()),
+	}
+	if diff, ok := diffutil.FromContext(ctx); ok {
+		pageData.Diff = renderDiffHTML(diff)
+	}
+
+	var page bytes.Buffer
+	if err := r.tmpl.Execute(&page, pageData); err != nil {
+		return nil, fmt.Errorf("レポートの HTML の生成に失敗しました: %w", err)
+	}
+	return page.Bytes(), nil
+}
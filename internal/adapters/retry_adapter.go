@@ -0,0 +1,160 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"git-gemini-cli/internal/usage"
+
+	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
+	"google.golang.org/genai"
+)
+
+// RetryPolicy は、AI呼び出しとレポートの公開の再試行の設定です。
+type RetryPolicy struct {
+	MaxRetries     int           // 最大再試行回数 (0 の場合は再試行しない)
+	InitialBackoff time.Duration // 1回目の再試行までの待機時間の上限 (以降は2倍ずつ増加)
+	MaxBackoff     time.Duration // 待機時間の上限 (Retry-After の指定がこれを超える場合は再試行しない)
+}
+
+var (
+	// statusCodePattern は、エラーメッセージ中の HTTP ステータスコードです (型情報が失われたエラー向けのフォールバック)。
+	// 529 は Anthropic API の過負荷 (overloaded_error) です。
+	statusCodePattern = regexp.MustCompile(`\b(?:Error|status|code)[ :=]*(429|50[0-4]|529)\b`)
+	// retryStatusPattern は、エラーメッセージ中の gRPC ステータスのうち再試行可能なものです。
+	retryStatusPattern = regexp.MustCompile(`\b(RESOURCE_EXHAUSTED|UNAVAILABLE|INTERNAL|DEADLINE_EXCEEDED)\b`)
+	// retryAfterPattern は、エラーメッセージ中の待機時間の指定です ("Please retry in 23.5s.")。
+	retryAfterPattern = regexp.MustCompile(`(?i)retry in ([0-9.]+)s`)
+)
+
+// RetryingCodeReviewAI は、AI の API (Gemini・OpenAI 互換・Ollama・Anthropic) の 429 (クォータ超過) と 5xx のエラーに対して、
+// ジッター付きの指数バックオフで再試行するデコレータです。サーバーが待機時間 (Retry-After / RetryInfo) を指定した場合はそれに従い、MaxBackoff を超える場合は再試行せずにエラーを返します。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type RetryingCodeReviewAI struct {
+	next   coreAdapters.CodeReviewAI
+	policy RetryPolicy
+}
+
+// NewRetryingCodeReviewAI は、next の呼び出しを policy に従って再試行する CodeReviewAI を返します。
+func NewRetryingCodeReviewAI(next coreAdapters.CodeReviewAI, policy RetryPolicy) *RetryingCodeReviewAI {
+	return &RetryingCodeReviewAI{next: next, policy: policy}
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (r *RetryingCodeReviewAI) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := r.next.ReviewCodeDiff(ctx, prompt)
+		if err == nil || attempt >= r.policy.MaxRetries || ctx.Err() != nil || !isRetryable(err) {
+			return result, err
+		}
+		if d, ok := retryAfter(err); ok && r.policy.MaxBackoff > 0 && d > r.policy.MaxBackoff {
+			// 上限で打ち切って早く再試行しても同じエラーになるため、サーバーの指定した待機時間を示して終了する
+			return result, fmt.Errorf("AI の API が指定した再試行までの待機時間 (%s) が上限 (%s) を超えるため、再試行しません: %w", d, r.policy.MaxBackoff, err)
+		}
+
+		wait := r.backoff(attempt, err)
+		usage.ReportRetry(ctx)
+		slog.Warn("AI の API が一時的なエラーを返したため、待機して再試行します。",
+			"attempt", attempt+1, "maxRetries", r.policy.MaxRetries, "wait", wait, "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff は、attempt 回目 (0始まり) の再試行までの待機時間を返します。
+// サーバーが待機時間を指定している場合はその値 (呼び出し元で MaxBackoff 以下であることを確認済み) を、
+// それ以外は Full Jitter の指数バックオフを使用します。
+func (r *RetryingCodeReviewAI) backoff(attempt int, err error) time.Duration {
+	if d, ok := retryAfter(err); ok {
+		return d
+	}
+
+	ceiling := r.policy.InitialBackoff << attempt
+	if ceiling <= 0 || ceiling > r.policy.MaxBackoff {
+		ceiling = r.policy.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling))) + 1
+}
+
+// isRetryable は、エラーが一時的なもの (429 または 5xx) かを判定します。
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= http.StatusInternalServerError
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+	}
+
+	// コアライブラリがエラーを文字列としてラップしている場合に備え、メッセージからも判定する
+	msg := err.Error()
+	return statusCodePattern.MatchString(msg) || retryStatusPattern.MatchString(msg)
+}
+
+// retryAfter は、エラーに含まれるサーバー指定の待機時間を返します。
+// Gemini API は RetryInfo (retryDelay) で、OpenAI 互換・Ollama・Anthropic の API は Retry-After ヘッダ (StatusError) で待機時間を示します。
+func retryAfter(err error) (time.Duration, bool) {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter, statusErr.RetryAfter > 0
+	}
+	if apiErr, ok := asAPIError(err); ok {
+		if d, ok := retryInfoDelay(apiErr.Details); ok {
+			return d, true
+		}
+	}
+
+	m := retryAfterPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	secs, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+// asAPIError は、エラーチェーンから genai.APIError (値またはポインタ) を取り出します。
+func asAPIError(err error) (genai.APIError, bool) {
+	var apiErr genai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	var apiErrPtr *genai.APIError
+	if errors.As(err, &apiErrPtr) && apiErrPtr != nil {
+		return *apiErrPtr, true
+	}
+	return genai.APIError{}, false
+}
+
+// retryInfoDelay は、エラー詳細の google.rpc.RetryInfo から待機時間 (例: "23s") を取り出します。
+func retryInfoDelay(details []map[string]any) (time.Duration, bool) {
+	for _, d := range details {
+		if t, _ := d["@type"].(string); t != "type.googleapis.com/google.rpc.RetryInfo" {
+			continue
+		}
+		if s, ok := d["retryDelay"].(string); ok {
+			if delay, err := time.ParseDuration(s); err == nil {
+				return delay, true
+			}
+		}
+	}
+	return 0, false
+}
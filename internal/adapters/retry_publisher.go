@@ -0,0 +1,102 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+	"google.golang.org/api/googleapi"
+)
+
+// publishStatusPattern は、エラーメッセージ中の HTTP ステータスコードです
+// (HTTPPublisher の "(status 403)" や Azure Blob Storage の ": 403 Forbidden" など、型情報を持たないエラー向け)。
+var publishStatusPattern = regexp.MustCompile(`(?:\bstatus[ :=]*|: )([1-5][0-9]{2})\b`)
+
+// RetryingPublisher は、公開先への書き込みの一時的なエラー (ネットワークエラー・408・429・5xx) に対して、
+// ジッター付きの指数バックオフで再試行するデコレータです。長時間のレビューの結果が、公開時の一時的な障害で失われないようにします。
+// publisher.Publisher インターフェースを実装します。
+type RetryingPublisher struct {
+	next   publisher.Publisher
+	policy RetryPolicy
+}
+
+// NewRetryingPublisher は、next の呼び出しを policy に従って再試行する Publisher を返します。
+func NewRetryingPublisher(next publisher.Publisher, policy RetryPolicy) *RetryingPublisher {
+	return &RetryingPublisher{next: next, policy: policy}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+func (r *RetryingPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	for attempt := 0; ; attempt++ {
+		err := r.next.Publish(ctx, uri, data)
+		if err == nil || attempt >= r.policy.MaxRetries || ctx.Err() != nil || !isRetryablePublishError(err) {
+			return err
+		}
+
+		wait := r.backoff(attempt)
+		slog.Warn("レポートの公開に失敗したため、待機して再試行します。",
+			"uri", uri, "attempt", attempt+1, "maxRetries", r.policy.MaxRetries, "wait", wait, "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff は、attempt 回目 (0始まり) の再試行までの待機時間を Full Jitter の指数バックオフで返します。
+func (r *RetryingPublisher) backoff(attempt int) time.Duration {
+	ceiling := r.policy.InitialBackoff << attempt
+	if ceiling <= 0 || ceiling > r.policy.MaxBackoff {
+		ceiling = r.policy.MaxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling))) + 1
+}
+
+// isRetryablePublishError は、公開のエラーが再試行で解消する可能性があるかを判定します。
+// 認証・権限・URIの誤りなどの 4xx (408 と 429 を除く) と、条件付き書き込みの競合は再試行しません。
+// ステータスを判定できないエラー (接続の切断やタイムアウトなど) は一時的なものとして扱います。
+func isRetryablePublishError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, objectstore.ErrPreconditionFailed) {
+		return false
+	}
+	code, ok := publishStatusCode(err)
+	if !ok {
+		return true
+	}
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// publishStatusCode は、エラーチェーンまたはエラーメッセージから HTTP ステータスコードを取り出します。
+func publishStatusCode(err error) (int, bool) {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code, true
+	}
+	// AWS SDK のエラー (smithy-go の ResponseError) は HTTPStatusCode を実装する
+	var respErr interface{ HTTPStatusCode() int }
+	if errors.As(err, &respErr) {
+		return respErr.HTTPStatusCode(), true
+	}
+
+	m := publishStatusPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, false
+	}
+	code, convErr := strconv.Atoi(m[1])
+	return code, convErr == nil
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/timeutil"
 
 	"github.com/shouni/go-http-kit/pkg/httpkit"
 	"github.com/shouni/go-notifier/pkg/factory"
@@ -20,6 +23,8 @@ import (
 // storageURI は内部的なストレージの場所 (s3://... など) を示します。
 type SlackNotifier interface {
 	Notify(ctx context.Context, publicURL, storageURI string, cfg config.ReviewConfig) error
+	// Post は、見出しと本文の任意のメッセージを投稿します。
+	Post(ctx context.Context, title, content string) error
 }
 
 // --- 具象アダプター ---
@@ -49,18 +54,12 @@ func (a *SlackAdapter) Notify(ctx context.Context, publicURL, storageURI string,
 		return nil
 	}
 
-	// 2. HTTP Clientの取得とSlackクライアントの初期化
-	slackClient, err := factory.GetSlackClient(a.httpClient)
-	if err != nil {
-		return fmt.Errorf("Slackクライアントの初期化に失敗しました: %w", err)
-	}
-
-	// 3. Slack に投稿するメッセージを作成
+	// 2. Slack に投稿するメッセージを作成
 	title := "✅ AIコードレビュー結果がアップロードされました。"
-	content := a.buildSlackContent(publicURL, storageURI, cfg)
+	content := a.buildSlackContent(ctx, publicURL, storageURI, cfg)
 
-	// 4. Slack投稿処理を実行
-	if err := slackClient.SendTextWithHeader(ctx, title, content); err != nil {
+	// 3. Slack投稿処理を実行
+	if err := a.Post(ctx, title, content); err != nil {
 		return fmt.Errorf("Slackへの結果URL投稿に失敗しました: %w", err)
 	}
 
@@ -68,22 +67,51 @@ func (a *SlackAdapter) Notify(ctx context.Context, publicURL, storageURI string,
 	return nil
 }
 
+// Post は SlackNotifier インターフェースの実装です。
+// 見出しと本文のメッセージを Slack に投稿します。Webhook URL が設定されていない場合は何もしません。
+func (a *SlackAdapter) Post(ctx context.Context, title, content string) error {
+	if a.webhookURL == "" {
+		slog.Info("SLACK_WEBHOOK_URL が設定されていません。Slackへの投稿をスキップします。", "title", title)
+		return nil
+	}
+
+	slackClient, err := factory.GetSlackClient(a.httpClient)
+	if err != nil {
+		return fmt.Errorf("Slackクライアントの初期化に失敗しました: %w", err)
+	}
+	return slackClient.SendTextWithHeader(ctx, title, content)
+}
+
 // buildSlackContent は投稿メッセージの本文を組み立てます。
-func (a *SlackAdapter) buildSlackContent(publicURL, storageURI string, cfg config.ReviewConfig) string {
+// context にレビュー結果の指摘事項が格納されている場合は、判定・件数・分類ごとの内訳・主なリスクのサマリーを追加します。
+func (a *SlackAdapter) buildSlackContent(ctx context.Context, publicURL, storageURI string, cfg config.ReviewConfig) string {
 	repoPath := urlpath.GetRepositoryPath(cfg.RepoURL)
 	content := fmt.Sprintf(
 		"**詳細URL:** <%s|%s>\n"+
 			"**リポジトリ:** `%s`\n"+
 			"**ブランチ:** `%s` ← `%s`\n"+
 			"**モード:** `%s`\n"+
-			"**モデル:** `%s`",
+			"**モデル:** `%s`\n"+
+			"**日時:** `%s`",
 		publicURL,
 		storageURI,
 		repoPath,
 		cfg.BaseBranch,
 		cfg.FeatureBranch,
 		cfg.ReviewMode,
-		cfg.GeminiModel,
+		cfg.Model,
+		timeutil.FormatReport(time.Now()),
 	)
+	// 詳細はリンク先のレポートに任せ、Slack には判定・件数・主なリスクのサマリーのみを投稿する
+	if list, ok := findings.FromContext(ctx); ok {
+		summary := findings.Summarize(list)
+		content += fmt.Sprintf("\n**判定:** %s\n**指摘事項:** %s", summary.Verdict, summary.CountsText())
+		if categories := findings.FormatCategoryCounts(list); categories != "" {
+			content += fmt.Sprintf("\n**分類:** %s", categories)
+		}
+		for i, f := range summary.TopRisks {
+			content += fmt.Sprintf("\n%d. %s", i+1, f.Label())
+		}
+	}
 	return strings.TrimSpace(content)
 }
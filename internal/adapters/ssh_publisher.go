@@ -0,0 +1,235 @@
+package adapters
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"os/user"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// sshDialTimeout は、SSH サーバーへの接続のタイムアウトです。
+const sshDialTimeout = 30 * time.Second
+
+// IsSSHURI は、URIが SSH 経由の公開先 (sftp://user@host/path または scp://user@host/path) を指すかを返します。
+func IsSSHURI(uri string) bool {
+	return strings.HasPrefix(uri, "sftp://") || strings.HasPrefix(uri, "scp://")
+}
+
+// SSHPublisher は、レビュー結果を HTML に変換し、SSH 経由 (SCP プロトコル) でサーバーに保存する publisher.Publisher の実装です。
+// ファイアウォールの内側にある社内の静的ファイルサーバーにレポートを置くためのもので、
+// リポジトリの取得と同じ SSH 秘密鍵とホストキーの確認の設定を使用します。
+type SSHPublisher struct {
+	sshKeyPath               string
+	insecureSkipHostKeyCheck bool
+	format                   string // 転送するレポートの形式 (config.FormatHTML / FormatMarkdown / FormatPDF)
+	renderer                 *ReportRenderer
+}
+
+// NewSSHPublisher は SSHPublisher の新しいインスタンスを作成します。
+func NewSSHPublisher(sshKeyPath string, insecureSkipHostKeyCheck bool, format string, renderer *ReportRenderer) *SSHPublisher {
+	return &SSHPublisher{
+		sshKeyPath:               sshKeyPath,
+		insecureSkipHostKeyCheck: insecureSkipHostKeyCheck,
+		format:                   format,
+		renderer:                 renderer,
+	}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。
+// 保存先のディレクトリを作成し、一時ファイルに転送した後に mv で置き換えます (サーバー上で書き込み途中の内容を読まれないようにするため)。
+// サーバーには scp コマンドが必要です。
+func (p *SSHPublisher) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	var page []byte
+	var err error
+	switch p.format {
+	case config.FormatMarkdown:
+		page = []byte(data.ReviewMarkdown)
+	case config.FormatPDF:
+		page, err = p.renderer.PDF(ctx, data)
+	default:
+		page, err = p.renderer.HTML(ctx, data)
+	}
+	if err != nil {
+		return err
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("URI '%s' の形式が不正です: %w", uri, err)
+	}
+	remotePath := u.Path
+	// sftp://host/~/reports/x.html はホームディレクトリからの相対パスとして扱う
+	if after, ok := strings.CutPrefix(remotePath, "/~/"); ok {
+		remotePath = after
+	}
+	if remotePath == "" || strings.HasSuffix(remotePath, "/") {
+		return fmt.Errorf("URI '%s' に保存先のファイルパスが含まれていません", uri)
+	}
+
+	client, err := p.dial(u)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	// ssh.Dial はコンテキストに対応していないため、キャンセル時に接続を閉じて転送を中断する
+	stop := context.AfterFunc(ctx, func() { client.Close() })
+	defer stop()
+
+	tmpPath := remotePath + ".tmp-" + strconv.Itoa(os.Getpid())
+	if err := scpUpload(client, tmpPath, page); err != nil {
+		return fmt.Errorf("'%s' へのファイルの転送に失敗しました: %w", uri, err)
+	}
+	if err := runRemote(client, "mv -f "+quotePathForShell(tmpPath)+" "+quotePathForShell(remotePath)); err != nil {
+		_ = runRemote(client, "rm -f "+quotePathForShell(tmpPath))
+		return fmt.Errorf("'%s' へのファイルの配置に失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// dial は、URIのユーザーとホストに SSH で接続します。ユーザーが省略された場合は、実行中のユーザー名を使用します。
+func (p *SSHPublisher) dial(u *url.URL) (*ssh.Client, error) {
+	if p.sshKeyPath == "" {
+		return nil, fmt.Errorf("SSH 経由の公開には SSH 秘密鍵が必要です (--ssh-key-path)")
+	}
+	key, err := os.ReadFile(p.sshKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("SSH秘密鍵 '%s' の読み込みに失敗しました: %w", p.sshKeyPath, err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("SSH秘密鍵 '%s' の解析に失敗しました (パスフレーズ付きの鍵には対応していません): %w", p.sshKeyPath, err)
+	}
+
+	hostKeyCallback := ssh.InsecureIgnoreHostKey()
+	if !p.insecureSkipHostKeyCheck {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("ホームディレクトリの取得に失敗しました: %w", err)
+		}
+		hostKeyCallback, err = knownhosts.New(filepath.Join(home, ".ssh", "known_hosts"))
+		if err != nil {
+			return nil, fmt.Errorf("known_hosts の読み込みに失敗しました (--skip-host-key-check で確認を省略できます): %w", err)
+		}
+	}
+
+	userName := u.User.Username()
+	if userName == "" {
+		current, err := user.Current()
+		if err != nil {
+			return nil, fmt.Errorf("ユーザー名を URI に指定してください (例: sftp://user@host/path): %w", err)
+		}
+		userName = current.Username
+	}
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+
+	client, err := ssh.Dial("tcp", net.JoinHostPort(u.Hostname(), port), &ssh.ClientConfig{
+		User:            userName,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         sshDialTimeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("SSH サーバー '%s' への接続に失敗しました: %w", u.Host, err)
+	}
+	return client, nil
+}
+
+// scpUpload は、SCP プロトコル (scp -t) で data を remotePath に転送します。保存先のディレクトリがなければ作成します。
+func scpUpload(client *ssh.Client, remotePath string, data []byte) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	session.Stderr = &stderr
+
+	cmd := "mkdir -p " + quotePathForShell(path.Dir(remotePath)) + " && scp -qt " + quotePathForShell(remotePath)
+	if err := session.Start(cmd); err != nil {
+		return err
+	}
+
+	acks := bufio.NewReader(stdout)
+	err = func() error {
+		if err := readSCPAck(acks); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(stdin, "C0644 %d %s\n", len(data), path.Base(remotePath)); err != nil {
+			return err
+		}
+		if err := readSCPAck(acks); err != nil {
+			return err
+		}
+		if _, err := stdin.Write(append(data, 0)); err != nil {
+			return err
+		}
+		return readSCPAck(acks)
+	}()
+	stdin.Close()
+	if waitErr := session.Wait(); err == nil {
+		err = waitErr
+	}
+	if err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+// readSCPAck は、SCP プロトコルの応答を読み込みます。0 以外の応答 (警告・エラー) はメッセージ付きのエラーとして返します。
+func readSCPAck(r *bufio.Reader) error {
+	code, err := r.ReadByte()
+	if errors.Is(err, io.EOF) {
+		return errors.New("サーバーが応答せずに終了しました")
+	}
+	if err != nil {
+		return err
+	}
+	if code == 0 {
+		return nil
+	}
+	msg, _ := r.ReadString('\n')
+	return fmt.Errorf("scp: %s", strings.TrimSpace(msg))
+}
+
+// runRemote は、サーバーでコマンドを実行します。
+func runRemote(client *ssh.Client, cmd string) error {
+	session, err := client.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+	out, err := session.CombinedOutput(cmd)
+	if err != nil && len(out) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return err
+}
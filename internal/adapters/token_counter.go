@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"google.golang.org/genai"
+)
+
+// ErrGeminiAPIKeyNotSet は、環境変数 GEMINI_API_KEY が設定されていないことを示すエラーです。
+var ErrGeminiAPIKeyNotSet = errors.New("環境変数 GEMINI_API_KEY が設定されていません")
+
+// TokenCounter は、プロンプトのトークン数を数える機能を定義します。
+type TokenCounter interface {
+	CountTokens(ctx context.Context, prompt string) (int, error)
+}
+
+// GeminiTokenCounter は、Gemini API の countTokens を使用して、送信前にプロンプトのトークン数を数えるアダプタです。
+// TokenCounter インターフェースを実装します。
+type GeminiTokenCounter struct {
+	client *genai.Client
+	model  string
+}
+
+// NewGeminiTokenCounter は、環境変数 GEMINI_API_KEY を使用して GeminiTokenCounter を初期化します。
+// httpClient には API の呼び出しに使用するクライアントを指定します (nil の場合は既定のクライアント)。
+func NewGeminiTokenCounter(ctx context.Context, httpClient *http.Client, model string) (*GeminiTokenCounter, error) {
+	apiKey := os.Getenv("GEMINI_API_KEY")
+	if apiKey == "" {
+		return nil, ErrGeminiAPIKeyNotSet
+	}
+
+	client, err := genai.NewClient(ctx, &genai.ClientConfig{
+		APIKey:     apiKey,
+		Backend:    genai.BackendGeminiAPI,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Gemini クライアントの初期化に失敗しました: %w", err)
+	}
+
+	return &GeminiTokenCounter{client: client, model: model}, nil
+}
+
+// CountTokens は TokenCounter インターフェースの実装です。
+func (c *GeminiTokenCounter) CountTokens(ctx context.Context, prompt string) (int, error) {
+	resp, err := c.client.Models.CountTokens(ctx, c.model, genai.Text(prompt), nil)
+	if err != nil {
+		return 0, fmt.Errorf("トークン数の取得に失敗しました (model: %s): %w", c.model, err)
+	}
+	return int(resp.TotalTokens), nil
+}
+
+// EstimateTokens は、API を使用せずにプロンプトのトークン数を概算します。
+// countTokens が利用できない場合のフォールバックで、日本語とコードが混在する差分を考慮して 3 バイトを 1 トークンとみなします。
+func EstimateTokens(prompt string) int {
+	return (len(prompt) + 2) / 3
+}
@@ -0,0 +1,48 @@
+package adapters
+
+import (
+	"context"
+	"time"
+
+	"git-gemini-cli/internal/usage"
+
+	coreAdapters "github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// UsageRecordingCodeReviewAI は、AI の呼び出しごとにトークン数・所要時間・再試行回数を
+// context の usage.Recorder に記録するデコレータです。Recorder が格納されていない場合は何も記録しません。
+// 再試行を1回の呼び出しとして記録するため、RetryingCodeReviewAI の外側に配置してください。
+// API の応答にトークン数が含まれない場合 (コアライブラリのアダプタなど) は、EstimateTokens による概算値を記録します。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type UsageRecordingCodeReviewAI struct {
+	next    coreAdapters.CodeReviewAI
+	backend string
+	model   string
+}
+
+// NewUsageRecordingCodeReviewAI は、next の呼び出しの使用量を記録する CodeReviewAI を返します。
+func NewUsageRecordingCodeReviewAI(next coreAdapters.CodeReviewAI, backend, model string) *UsageRecordingCodeReviewAI {
+	return &UsageRecordingCodeReviewAI{next: next, backend: backend, model: model}
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (u *UsageRecordingCodeReviewAI) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	rec := usage.FromContext(ctx)
+	if rec == nil {
+		return u.next.ReviewCodeDiff(ctx, prompt)
+	}
+
+	ctx, call := usage.StartCall(ctx)
+	start := time.Now()
+	result, err := u.next.ReviewCodeDiff(ctx, prompt)
+
+	call.Mode, _ = ctx.Value(reviewModeKey{}).(string)
+	call.Backend, call.Model = u.backend, u.model
+	call.LatencyMS = time.Since(start).Milliseconds()
+	call.Failed = err != nil
+	if call.PromptTokens == 0 && call.OutputTokens == 0 {
+		call.PromptTokens, call.OutputTokens, call.Estimated = EstimateTokens(prompt), EstimateTokens(result), true
+	}
+	rec.Add(*call)
+	return result, err
+}
@@ -0,0 +1,45 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// ErrWorktreeUnsupported は、使用中の GitService が一時的な worktree の作成に対応していないことを示すエラーです。
+var ErrWorktreeUnsupported = errors.New("使用中のGitアダプタは worktree の作成に対応していません")
+
+// WorktreeProvider は、ローカルのクローンのワーキングツリーを変更せずに、参照の時点のコミットを一時的な git worktree にチェックアウトできる
+// GitService が追加で実装するインターフェースです。
+// コアライブラリのアダプタとインメモリアダプタは実装していないため、利用側は型アサーションで対応状況を確認してください。
+type WorktreeProvider interface {
+	// AddWorktree は、ref の時点のコミットを一時的なディレクトリに worktree としてチェックアウトし、そのディレクトリと、
+	// worktree を削除する関数を返します。呼び出し元は、使用後に必ず remove を呼び出してください。
+	AddWorktree(ctx context.Context, ref string) (dir string, remove func(), err error)
+}
+
+// AddWorktree は、'git worktree add --detach' で ref の時点のコミットを一時的なディレクトリにチェックアウトします。
+// WorktreeProvider インターフェースの実装です。クローンのワーキングツリーは変更しないため、読み取り専用モードでも使用できます。
+func (ga *LocalGitAdapter) AddWorktree(ctx context.Context, ref string) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "git-gemini-cli-worktree-")
+	if err != nil {
+		return "", nil, fmt.Errorf("worktree の一時ディレクトリの作成に失敗しました: %w", err)
+	}
+	if _, err := ga.runGitCommand(ctx, "worktree", "add", "--detach", dir, resolveRef(ref)); err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, fmt.Errorf("'%s' の worktree の作成に失敗しました: %w", ref, err)
+	}
+
+	remove := func() {
+		// 中断された場合も worktree の登録が残らないよう、キャンセルされない context で削除する
+		cleanupCtx := context.WithoutCancel(ctx)
+		if _, err := ga.runGitCommand(cleanupCtx, "worktree", "remove", "--force", dir); err != nil {
+			slog.Warn("一時的な worktree の削除に失敗しました。", "dir", dir, "error", err)
+			_ = os.RemoveAll(dir)
+			_, _ = ga.runGitCommand(cleanupCtx, "worktree", "prune")
+		}
+	}
+	return dir, remove, nil
+}
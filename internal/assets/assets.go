@@ -0,0 +1,46 @@
+package assets
+
+import (
+	"bytes"
+	"image"
+	"net/http"
+	"path"
+	"strings"
+
+	// image.DecodeConfig で画像の形式と寸法を判定するためにデコーダを登録する
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Metadata は、バイナリファイルについてローカルで取得できるメタデータです。
+type Metadata struct {
+	Format string // ファイル形式 (例: "png", "application/pdf")
+	Size   int    // バイト数
+	Width  int    // 画像の幅 (画像以外、または判定できない場合は 0)
+	Height int    // 画像の高さ
+}
+
+// HasDimensions は、画像の寸法が判定できたかを返します。
+func (m Metadata) HasDimensions() bool {
+	return m.Width > 0 && m.Height > 0
+}
+
+// Inspect は、ファイルの内容からメタデータを取得します。
+// 画像 (PNG / JPEG / GIF) はヘッダから形式と寸法を、それ以外は内容から推定した MIME タイプを形式とします。
+// MIME タイプが判定できない場合は拡張子を形式とします。
+func Inspect(name string, data []byte) Metadata {
+	meta := Metadata{Size: len(data)}
+	if cfg, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		meta.Format, meta.Width, meta.Height = format, cfg.Width, cfg.Height
+		return meta
+	}
+
+	contentType, _, _ := strings.Cut(http.DetectContentType(data), ";")
+	if contentType != "application/octet-stream" {
+		meta.Format = contentType
+		return meta
+	}
+	meta.Format = strings.TrimPrefix(strings.ToLower(path.Ext(name)), ".")
+	return meta
+}
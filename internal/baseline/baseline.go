@@ -0,0 +1,107 @@
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"git-gemini-cli/internal/findings"
+)
+
+// FileName は、レビュー対象リポジトリのルートに配置するベースラインファイルの名前です。
+const FileName = ".gemini-review-baseline.json"
+
+// version は、ベースラインファイルの形式のバージョンです。
+const version = 1
+
+// Entry は、チームが受け入れた (今後は報告しない) 1件の指摘事項です。
+// 指紋以外の項目は、ファイルをレビューする人が内容を確認するためのものです。
+type Entry struct {
+	Fingerprint string            `json:"fingerprint"`
+	Severity    findings.Severity `json:"severity"`
+	Title       string            `json:"title"`
+	File        string            `json:"file,omitempty"`
+}
+
+// Baseline は、チームが受け入れた指摘事項の一覧です。
+type Baseline struct {
+	Version     int       `json:"version"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	Entries     []Entry   `json:"findings"`
+}
+
+// New は、指摘事項の一覧からベースラインを作成します。同じ指紋の指摘事項は1件にまとめます。
+func New(list []findings.Finding, now time.Time) *Baseline {
+	b := &Baseline{Version: version, GeneratedAt: now, Entries: []Entry{}}
+	seen := make(map[string]bool, len(list))
+	for _, f := range list {
+		fp := findings.Fingerprint(f)
+		if seen[fp] {
+			continue
+		}
+		seen[fp] = true
+		b.Entries = append(b.Entries, Entry{Fingerprint: fp, Severity: f.Severity, Title: f.Title, File: f.File})
+	}
+	return b
+}
+
+// Load は、path のベースラインファイルを読み込みます。
+func Load(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("ベースラインファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("ベースラインファイル '%s' の解析に失敗しました: %w", path, err)
+	}
+	if b.Version > version {
+		return nil, fmt.Errorf("ベースラインファイル '%s' の形式 (version: %d) には対応していません", path, b.Version)
+	}
+	return &b, nil
+}
+
+// LoadIfExists は、path のベースラインファイルを読み込みます。ファイルが存在しない場合は nil を返します。
+func LoadIfExists(path string) (*Baseline, error) {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return Load(path)
+}
+
+// Save は、ベースラインを path に保存します。差分の確認しやすさのため、インデントした JSON で書き出します。
+func (b *Baseline) Save(path string) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("ベースラインの変換に失敗しました: %w", err)
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("ベースラインの保存先ディレクトリの作成に失敗しました: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("ベースラインファイル '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
+
+// Match は、指摘事項に一致するベースラインの項目を返します。
+// 指紋が一致する項目を優先し、ない場合は同じファイルでタイトルが類似する項目を探します。一致しない場合は nil を返します。
+func (b *Baseline) Match(f findings.Finding) *Entry {
+	fp := findings.Fingerprint(f)
+	for i := range b.Entries {
+		if b.Entries[i].Fingerprint == fp {
+			return &b.Entries[i]
+		}
+	}
+	for i := range b.Entries {
+		if findings.SameIssue(findings.Finding{File: b.Entries[i].File, Title: b.Entries[i].Title}, f) {
+			return &b.Entries[i]
+		}
+	}
+	return nil
+}
@@ -1,58 +1,231 @@
 package builder
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"html/template"
 	"log/slog"
+	"maps"
+	"sync"
+	"time"
 
 	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffcache"
+	"git-gemini-cli/internal/objectstore"
 	"git-gemini-cli/internal/runner"
 
 	internalAdapters "git-gemini-cli/internal/adapters"
+	internalPrompts "git-gemini-cli/internal/prompts"
 
 	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
-	"github.com/shouni/gemini-reviewer-core/pkg/prompts"
 	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+	"github.com/shouni/go-remote-io/pkg/remoteio"
 )
 
+const (
+	// diffCacheEntries は、プロセス内で保持する差分の最大件数です。
+	diffCacheEntries = 16
+	// fetchReuseWindow は、同じローカルパスのフェッチ結果を後続のレビュー・サブコマンドで再利用する期間です。
+	fetchReuseWindow = 2 * time.Minute
+)
+
+// sharedDiffCache は、同じプロセス内で構築されるすべての Runner が共有する差分のキャッシュです。
+// 1回の実行で同じ差分を複数の Runner が使用する場合に、フェッチと差分の計算の繰り返しを省きます。
+var sharedDiffCache = diffcache.New(diffCacheEntries, fetchReuseWindow)
+
+// rateLimiters は、同じプロセス内で構築されるすべての Runner が共有する、バックエンドとモデルごとのレート制限です。
+// 分割レビューや複数のレビューを1つのプロセスで実行する場合も、合計の呼び出しを API のクォータ内に収めます。
+var (
+	rateLimitersMu sync.Mutex
+	rateLimiters   = map[string]*internalAdapters.RateLimiter{}
+)
+
+// sharedRateLimiter は、cfg のバックエンドとモデルに対応する共有の RateLimiter を返します。
+func sharedRateLimiter(cfg config.ReviewConfig) *internalAdapters.RateLimiter {
+	key := fmt.Sprintf("%s/%s/%d/%d", cfg.Backend, cfg.Model, cfg.RateLimitRPM, cfg.RateLimitTPM)
+	rateLimitersMu.Lock()
+	defer rateLimitersMu.Unlock()
+	limiter, ok := rateLimiters[key]
+	if !ok {
+		limiter = internalAdapters.NewRateLimiter(cfg.RateLimitRPM, cfg.RateLimitTPM)
+		rateLimiters[key] = limiter
+	}
+	return limiter
+}
+
 // buildGitService は adapters.GitService のインスタンスを構築する Factory 関数です。
-// 設定 (cfg.UseExternalGitCommand) に基づいて、内部アダプタ (os/exec) またはコアライブラリのアダプタ (go-git) を選択します。
+// 設定 (cfg.Ephemeral, cfg.UseExternalGitCommand) に基づいて、インメモリアダプタ (go-git memfs)、
+// 内部アダプタ (os/exec) またはコアライブラリのアダプタ (go-git) を選択します。
 func buildGitService(cfg config.ReviewConfig) adapters.GitService {
+	// インメモリモードでは LocalPath を使わず、go-git のメモリストレージにクローンする
+	if cfg.Ephemeral {
+		slog.Debug("GitService: インメモリアダプタ (MemoryGitAdapter/go-git memfs) を使用します。")
+		return internalAdapters.NewMemoryGitAdapter(
+			cfg.SSHKeyPath,
+			internalAdapters.WithMemoryInsecureSkipHostKeyCheck(cfg.SkipHostKeyCheck),
+			internalAdapters.WithMemoryBaseBranch(cfg.BaseBranch),
+		)
+	}
+
+	// 読み取り専用モードの挙動を保証できるのは内部アダプタのみのため、常に内部アダプタを使用
+	if cfg.ReadOnly && !cfg.UseExternalGitCommand {
+		slog.Warn("--read-only は外部Gitコマンド利用アダプタでのみサポートされるため、LocalGitAdapter を使用します。")
+		cfg.UseExternalGitCommand = true
+	}
+
+	// コミットハッシュを参照として解決できるのは内部アダプタのみのため、コミットを固定したレビューでは内部アダプタを使用
+	if cfg.HeadCommit != "" && !cfg.UseExternalGitCommand {
+		slog.Debug("コミットを固定したレビューのため、LocalGitAdapter を使用します。", "commit", cfg.HeadCommit)
+		cfg.UseExternalGitCommand = true
+	}
+
 	// フラグが true の場合、CLI固有の内部アダプタ (os/execベース) を使用
 	if cfg.UseExternalGitCommand {
 		slog.Debug("GitService: 外部Gitコマンド利用アダプタ (LocalGitAdapter/os/exec) を使用します。")
-		return internalAdapters.NewLocalGitAdapter(
-			cfg.LocalPath,
-			cfg.SSHKeyPath,
-			internalAdapters.WithInsecureSkipHostKeyCheck(cfg.SkipHostKeyCheck),
-			internalAdapters.WithBaseBranch(cfg.BaseBranch),
-		)
+		return buildLocalGitAdapter(cfg)
 	}
 
 	// フラグが false または未設定の場合、コアライブラリのアダプタ (go-gitベース) を使用
 	slog.Debug("GitService: コアライブラリのアダプタ (go-git) を使用します。")
-	return adapters.NewGitAdapter(
+	gitAdapter := adapters.NewGitAdapter(
 		cfg.LocalPath,
 		cfg.SSHKeyPath,
 		adapters.WithInsecureSkipHostKeyCheck(cfg.SkipHostKeyCheck),
 		adapters.WithBaseBranch(cfg.BaseBranch),
 	)
+
+	// go-git が未対応の機能 (一部のSSH設定など) で失敗した場合に、外部Gitコマンドへ自動で切り替える
+	if cfg.DisableGitFallback {
+		return gitAdapter
+	}
+	if !internalAdapters.ExternalGitAvailable() {
+		slog.Debug("外部Gitコマンドが見つからないため、自動フォールバックは無効です。")
+		return gitAdapter
+	}
+	return internalAdapters.NewFallbackGitService(gitAdapter, "go-git", func() adapters.GitService {
+		return buildLocalGitAdapter(cfg)
+	})
+}
+
+// buildLocalGitAdapter は、外部Gitコマンドを利用する LocalGitAdapter を構築します。
+func buildLocalGitAdapter(cfg config.ReviewConfig) adapters.GitService {
+	return internalAdapters.NewLocalGitAdapter(
+		cfg.LocalPath,
+		cfg.SSHKeyPath,
+		internalAdapters.WithInsecureSkipHostKeyCheck(cfg.SkipHostKeyCheck),
+		internalAdapters.WithBaseBranch(cfg.BaseBranch),
+		internalAdapters.WithReadOnly(cfg.ReadOnly),
+	)
 }
 
 // buildGeminiService は adapters.CodeReviewAI のインスタンスを構築します。
 // この関数は BuildReviewRunner の内部ヘルパーとして使用されます。
-func buildGeminiService(ctx context.Context, cfg config.ReviewConfig) (adapters.CodeReviewAI, error) {
-	geminiService, err := adapters.NewGeminiAdapter(ctx, cfg.GeminiModel)
+// レート制限が有効な場合は、各呼び出し (再試行を含む) の前に共有の RateLimiter で待機するデコレータでラップします。
+// 再試行が有効な場合は、429 / 5xx のエラーをバックオフ付きで再試行するデコレータでラップします。
+// ai が nil でない場合は、設定からアダプタを構築せずに ai をデコレータでラップします。
+func buildGeminiService(ctx context.Context, cfg config.ReviewConfig, ai adapters.CodeReviewAI) (adapters.CodeReviewAI, error) {
+	geminiService, err := buildAIAdapter(ctx, cfg, ai)
 	if err != nil {
-		return nil, fmt.Errorf("Gemini Service の構築に失敗しました: %w", err)
+		return nil, fmt.Errorf("AI Service の構築に失敗しました (backend: %s): %w", cfg.Backend, err)
+	}
+
+	if cfg.RateLimitRPM > 0 || cfg.RateLimitTPM > 0 {
+		geminiService = internalAdapters.NewRateLimitedCodeReviewAI(geminiService, sharedRateLimiter(cfg))
+	}
+
+	if cfg.MaxRetries > 0 {
+		geminiService = internalAdapters.NewRetryingCodeReviewAI(geminiService, internalAdapters.RetryPolicy{
+			MaxRetries:     cfg.MaxRetries,
+			InitialBackoff: cfg.RetryInitialBackoff,
+			MaxBackoff:     cfg.RetryMaxBackoff,
+		})
+	}
+
+	// 再試行を含めて1回の呼び出しとして使用量を記録するため、最も外側に配置する
+	return internalAdapters.NewUsageRecordingCodeReviewAI(geminiService, cfg.Backend, cfg.Model), nil
+}
+
+// buildAIAdapter は、設定 (cfg.Backend) に基づいてレビューに使用するAIのアダプタを選択します。
+func buildAIAdapter(ctx context.Context, cfg config.ReviewConfig, ai adapters.CodeReviewAI) (adapters.CodeReviewAI, error) {
+	if ai != nil {
+		slog.Debug("AI Service: 呼び出し側が指定したアダプタを使用します。")
+		return ai, nil
 	}
 
-	return geminiService, nil
+	switch cfg.Backend {
+	case config.BackendOpenAI:
+		params, err := generationParams(cfg)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("AI Service: OpenAI 互換アダプタを使用します。", "base_url", cfg.OpenAIBaseURL, "azure", cfg.OpenAIAPIVersion != "")
+		return internalAdapters.NewOpenAIAdapter(cfg.HTTPClient, cfg.OpenAIBaseURL, cfg.OpenAIAPIVersion, cfg.Model, params)
+	case config.BackendOllama:
+		params, err := generationParams(cfg)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("AI Service: Ollama アダプタを使用します。", "host", cfg.OllamaHost, "num_ctx", cfg.OllamaNumCtx)
+		return internalAdapters.NewOllamaAdapter(cfg.HTTPClient, cfg.OllamaHost, cfg.Model, cfg.OllamaNumCtx, params)
+	case config.BackendClaude:
+		params, err := generationParams(cfg)
+		if err != nil {
+			return nil, err
+		}
+		slog.Debug("AI Service: Claude アダプタを使用します。", "base_url", cfg.AnthropicBaseURL)
+		return internalAdapters.NewClaudeAdapter(cfg.HTTPClient, cfg.AnthropicBaseURL, cfg.Model, params)
+	default:
+		return buildGeminiAdapter(ctx, cfg)
+	}
+}
+
+// buildGeminiAdapter は、Gemini API を呼び出すアダプタを構築します。
+// 生成パラメータまたはネットワーク設定を適用した HTTP クライアントが指定されている場合は CLI 側のアダプタを、
+// それ以外はコアライブラリのアダプタを使用します (コアライブラリのアダプタにはクライアントを渡せないため)。
+func buildGeminiAdapter(ctx context.Context, cfg config.ReviewConfig) (adapters.CodeReviewAI, error) {
+	if !cfg.HasGenerationParams() && cfg.HTTPClient == nil {
+		return adapters.NewGeminiAdapter(ctx, cfg.Model)
+	}
+
+	params, err := generationParams(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return internalAdapters.NewGeminiAdapter(ctx, cfg.HTTPClient, cfg.Model, params)
+}
+
+// generationParams は、全モード共通 ("" のキー) と各モードの生成パラメータを返します。
+func generationParams(cfg config.ReviewConfig) (map[string]config.GenerationParams, error) {
+	params := make(map[string]config.GenerationParams)
+	for _, mode := range append([]string{""}, cfg.Modes()...) {
+		p, err := cfg.GenerationParams(mode)
+		if err != nil {
+			return nil, err
+		}
+		params[mode] = p
+	}
+	return params, nil
+}
+
+// buildTokenCounter は、トークン数の上限が設定されている場合に、送信前のトークン数確認に使用する countTokens API のクライアントを構築します。
+// countTokens API は Gemini のみのため、他のバックエンドや API を利用できない場合は nil を返し、トークン数は概算で判定します。
+func buildTokenCounter(ctx context.Context, cfg config.ReviewConfig) internalAdapters.TokenCounter {
+	if cfg.MaxPromptTokens <= 0 || cfg.Backend != config.BackendGemini {
+		return nil
+	}
+	counter, err := internalAdapters.NewGeminiTokenCounter(ctx, cfg.HTTPClient, cfg.Model)
+	if err != nil {
+		slog.Warn("countTokens API を利用できないため、トークン数は概算で判定します。", "error", err)
+		return nil
+	}
+	return counter
 }
 
 // BuildReviewRunner は、必要な依存関係をすべて構築し、
-// 実行可能な ReviewRunner のインスタンスを返します。
-func BuildReviewRunner(ctx context.Context, cfg config.ReviewConfig) (runner.ReviewRunner, error) {
+// 実行可能な ReviewRunner のインスタンスを返します。deps.AI が指定されている場合は、レビューにそのAIを使用します。
+func BuildReviewRunner(ctx context.Context, cfg config.ReviewConfig, deps Dependencies) (runner.ReviewRunner, error) {
 	// 1. GitService の構築
 	gitService := buildGitService(cfg)
 	slog.Debug("GitService (Adapter) を構築しました。",
@@ -61,51 +234,450 @@ func BuildReviewRunner(ctx context.Context, cfg config.ReviewConfig) (runner.Rev
 	)
 
 	// 2. GeminiService の構築
-	geminiService, err := buildGeminiService(ctx, cfg)
+	geminiService, err := buildGeminiService(ctx, cfg, deps.AI)
 	if err != nil {
 		return nil, err
 	}
-	slog.Debug("GeminiService (Adapter) を構築しました。", slog.String("model", cfg.GeminiModel))
+	slog.Debug("GeminiService (Adapter) を構築しました。", slog.String("backend", cfg.Backend), slog.String("model", cfg.Model))
 
-	// 3. Prompt Builder の構築
-	promptBuilder, err := prompts.NewPromptBuilder()
+	// 3. Prompt Builder の構築 (CLI固有モードを含む)
+	promptBuilder, err := internalPrompts.NewBuilder()
 	if err != nil {
 		return nil, fmt.Errorf("Prompt Builder の構築に失敗しました: %w", err)
 	}
 	slog.Debug("PromptBuilderを構築しました。", slog.String("component", "PromptBuilder"))
 
-	// 4. 依存関係を注入して Runner を組み立てる
+	// 4. トークン数の上限が設定されている場合のみ、送信前のトークン数確認に countTokens API を使用する
+	tokenCounter := buildTokenCounter(ctx, cfg)
+
+	// 5. 依存関係を注入して Runner を組み立てる
 	reviewRunner := runner.NewDefaultReviewRunner(
 		gitService,
 		geminiService,
 		promptBuilder,
+		tokenCounter,
+		sharedDiffCache,
+		buildPRMetadataFetcher(cfg),
 	)
 
 	slog.Debug("ReviewRunner の構築が完了しました。")
 	return reviewRunner, nil
 }
 
+// ListRemoteBranches は、GitService を構築してリポジトリをクローン (または更新) し、リモートのブランチ一覧を返します。
+// フェッチ結果は共有の差分キャッシュに記録されるため、続けて行うレビューは同じクローンを再利用します。
+func ListRemoteBranches(ctx context.Context, cfg config.ReviewConfig) ([]string, error) {
+	return runner.ListRemoteBranches(ctx, buildGitService(cfg), sharedDiffCache, cfg)
+}
+
+// ListRemoteBranchHeads は、GitService を構築してリポジトリをフェッチし、リモートのブランチと最新のコミットハッシュの対応を返します。
+func ListRemoteBranchHeads(ctx context.Context, cfg config.ReviewConfig) (map[string]string, error) {
+	return runner.ListRemoteBranchHeads(ctx, buildGitService(cfg), sharedDiffCache, cfg)
+}
+
+// BuildPromptRunner は、プロンプトの生成に必要な依存関係を構築し、AIに送信せずにプロンプトを返す PromptRunner を返します。
+// AIのバックエンドは構築しないため、APIキーは不要です (トークン数の上限が設定されている場合の countTokens API を除く)。
+func BuildPromptRunner(ctx context.Context, cfg config.ReviewConfig) (runner.PromptRunner, error) {
+	promptBuilder, err := internalPrompts.NewBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("Prompt Builder の構築に失敗しました: %w", err)
+	}
+	return runner.NewDefaultReviewRunner(
+		buildGitService(cfg),
+		nil,
+		promptBuilder,
+		buildTokenCounter(ctx, cfg),
+		sharedDiffCache,
+		buildPRMetadataFetcher(cfg),
+	), nil
+}
+
+// BuildAskRunner は、質問応答に必要な依存関係を構築し、
+// 実行可能な AskRunner のインスタンスを返します。
+func BuildAskRunner(ctx context.Context, cfg config.ReviewConfig) (runner.AskRunner, error) {
+	gitService := buildGitService(cfg)
+
+	geminiService, err := buildGeminiService(ctx, cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	promptBuilder, err := internalPrompts.NewBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("Prompt Builder の構築に失敗しました: %w", err)
+	}
+
+	askRunner := runner.NewDefaultAskRunner(
+		gitService,
+		geminiService,
+		promptBuilder,
+	)
+
+	slog.Debug("AskRunner の構築が完了しました。")
+	return askRunner, nil
+}
+
+// BuildTranslateRunner は、レポートの翻訳に必要な依存関係を構築し、
+// 実行可能な TranslateRunner のインスタンスを返します。deps.AI が指定されている場合は、翻訳にそのAIを使用します。
+func BuildTranslateRunner(ctx context.Context, cfg config.ReviewConfig, deps Dependencies) (runner.TranslateRunner, error) {
+	geminiService, err := buildGeminiService(ctx, cfg, deps.AI)
+	if err != nil {
+		return nil, err
+	}
+
+	promptBuilder, err := internalPrompts.NewBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("Prompt Builder の構築に失敗しました: %w", err)
+	}
+
+	slog.Debug("TranslateRunner の構築が完了しました。")
+	return runner.NewDefaultTranslateRunner(geminiService, promptBuilder), nil
+}
+
+// BuildChatRunner は、レビュー結果についての会話に必要な依存関係を構築し、
+// 実行可能な ChatRunner のインスタンスを返します。リポジトリは使用しないため、Gitのアダプタは構築しません。
+func BuildChatRunner(ctx context.Context, cfg config.ReviewConfig) (runner.ChatRunner, error) {
+	geminiService, err := buildGeminiService(ctx, cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	promptBuilder, err := internalPrompts.NewBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("Prompt Builder の構築に失敗しました: %w", err)
+	}
+
+	slog.Debug("ChatRunner の構築が完了しました。")
+	return runner.NewDefaultChatRunner(geminiService, promptBuilder), nil
+}
+
+// BuildApplyFixesRunner は、修正案の適用に必要な依存関係を構築し、実行可能な ApplyFixesRunner のインスタンスを返します。
+// パッチの適用には外部Gitコマンドを使用するため、--use-external-git-command の指定に関わらず LocalGitAdapter を使用します。
+func BuildApplyFixesRunner(cfg config.ReviewConfig) (runner.ApplyFixesRunner, error) {
+	if cfg.Ephemeral {
+		return nil, fmt.Errorf("--ephemeral ではディスク上のクローンがないため、修正案を適用できません")
+	}
+	if !internalAdapters.ExternalGitAvailable() {
+		return nil, fmt.Errorf("修正案の適用には git コマンドが必要です")
+	}
+	applier, ok := buildLocalGitAdapter(cfg).(internalAdapters.PatchApplier)
+	if !ok {
+		return nil, internalAdapters.ErrPatchApplyUnsupported
+	}
+
+	slog.Debug("ApplyFixesRunner の構築が完了しました。")
+	return runner.NewDefaultApplyFixesRunner(applier), nil
+}
+
+// BuildReviewersRunner は、レビュアーの推奨に必要な依存関係を構築し、
+// 実行可能な ReviewersRunner のインスタンスを返します。AI は使用しないため、AI のアダプタは構築しません。
+// cfg.PRComment が指定されている場合は推奨結果をプルリクエストに投稿するアダプタを、
+// cfg.HistoryURI が指定されている場合はレビューの履歴を読み書きするストレージを構築します。
+func BuildReviewersRunner(ctx context.Context, cfg config.ReviewersConfig) (runner.ReviewersRunner, error) {
+	gitService := buildGitService(cfg.ReviewConfig)
+	slackNotifier := buildSlackNotifier(cfg.HttpClient, cfg.SlackWebhookURL, nil)
+
+	commenter, err := buildPRCommentTarget(config.PublishConfig{
+		HttpClient:   cfg.HttpClient,
+		ReviewConfig: cfg.ReviewConfig,
+		PRComment:    cfg.PRComment,
+		PRNumber:     cfg.PRNumber,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("プルリクエストへのコメントの投稿の初期化に失敗しました: %w", err)
+	}
+
+	var history objectstore.Store
+	if cfg.HistoryURI != "" {
+		if history, err = objectstore.New(ctx, cfg.HistoryURI); err != nil {
+			return nil, fmt.Errorf("レビューの履歴のストレージの初期化に失敗しました (URI: %s): %w", cfg.HistoryURI, err)
+		}
+	}
+
+	slog.Debug("ReviewersRunner の構築が完了しました。")
+	return runner.NewDefaultReviewersRunner(gitService, slackNotifier, commenter, history, sharedDiffCache), nil
+}
+
+// buildSlackNotifier は、Slack への通知に使用するアダプタを構築します。notifier が nil でない場合は notifier をそのまま返します。
+func buildSlackNotifier(httpClient httpkit.ClientInterface, webhookURL string, notifier internalAdapters.SlackNotifier) internalAdapters.SlackNotifier {
+	if notifier != nil {
+		return notifier
+	}
+	return internalAdapters.NewSlackAdapter(httpClient, webhookURL)
+}
+
+// buildPublisher は、公開先のURIのスキームと公開する形式 (cfg.Format) に応じた Publisher と URL Signer を構築します。
+// URL Signer が不要な公開先では nil を返します。
+func buildPublisher(ctx context.Context, cfg config.PublishConfig) (publisher.Publisher, remoteio.URLSigner, error) {
+	var tmpl *template.Template
+	if cfg.HTMLTemplate != "" {
+		var err error
+		if tmpl, err = internalAdapters.LoadReportTemplate(cfg.HTMLTemplate); err != nil {
+			return nil, nil, err
+		}
+	}
+	renderer := internalAdapters.NewReportRenderer(tmpl)
+	attrs := objectAttributes(cfg)
+
+	uri := cfg.StorageURI
+	// HTML 以外の形式は、ストレージに直接書き込む Publisher を使用する
+	direct := cfg.Format == config.FormatMarkdown || cfg.Format == config.FormatPDF
+	switch {
+	case objectstore.IsAzureURI(uri):
+		// Azure Blob Storage: gemini-reviewer-core が対応していないため、SAS トークンで署名する独自の Publisher を使用する
+		azureStore, err := objectstore.NewAzureStore(cfg.HttpClient)
+		if err != nil {
+			return nil, nil, err
+		}
+		store := objectstore.WithAttributes(azureStore, attrs)
+		if direct {
+			return storePublisher(store, cfg.Format, renderer), azureStore, nil
+		}
+		return internalAdapters.NewAzureBlobPublisher(store, renderer), azureStore, nil
+	case internalAdapters.IsGistURI(uri):
+		// gist://: クラウドストレージを持たない場合に、Markdown を GitHub の秘密の Gist として保存し、その URL を通知する
+		if cfg.Format != config.FormatMarkdown {
+			slog.Info("Gist には形式 (--format) によらず、Markdown のレビュー結果を保存します。", "uri", uri, "format", cfg.Format)
+		}
+		gist, err := internalAdapters.NewGistPublisher(cfg.HttpClient)
+		if err != nil {
+			return nil, nil, err
+		}
+		return gist, gist, nil
+	case internalAdapters.IsGitBranchURI(uri):
+		// git-branch://: レビュー対象 (または --docs-repo-url) のリポジトリのブランチに、外部Gitコマンドでコミットしてプッシュする
+		if !internalAdapters.ExternalGitAvailable() {
+			return nil, nil, fmt.Errorf("ブランチへのレポートのコミットには git コマンドが必要です")
+		}
+		if cfg.DocsLocalPath == "" {
+			return nil, nil, fmt.Errorf("ブランチへのレポートのコミットに使用するローカルリポジトリのパスが決まっていません (--repo-url または --docs-repo-url を指定してください)")
+		}
+		rc := cfg.ReviewConfig
+		rc.LocalPath = cfg.DocsLocalPath
+		writer, err := internalAdapters.NewGitBranchPublisher(buildLocalGitAdapter(rc), cmp.Or(cfg.DocsRepoURL, rc.RepoURL), rc.LocalPath, rc.LockTimeout, cfg.Format, renderer)
+		return writer, nil, err
+	case internalAdapters.IsHTTPURI(uri):
+		// Azure 以外の https:// / http://: レポートを JSON (Markdown / PDF 形式の場合はそのまま) として社内サービスなどに送信する
+		return internalAdapters.NewHTTPPublisher(cfg.HttpClient, cfg.HTTPMethod, cfg.HTTPHeader, cfg.Format, renderer), nil, nil
+	case internalAdapters.IsSSHURI(uri):
+		// sftp:// / scp://: リポジトリの取得と同じ SSH 秘密鍵で、社内のファイルサーバーに転送する
+		return internalAdapters.NewSSHPublisher(cfg.ReviewConfig.SSHKeyPath, cfg.ReviewConfig.SkipHostKeyCheck, cfg.Format, renderer), nil, nil
+	case remoteio.IsS3URI(uri) && objectstore.S3Endpoint() != "":
+		// S3 互換ストレージ (MinIO, R2 など): gemini-reviewer-core の Publisher はエンドポイントを変更できないため、
+		// エンドポイントを設定したストアに直接書き込み、通知用の署名付きURLもストアで生成する
+		store, err := objectstore.New(ctx, uri)
+		if err != nil {
+			return nil, nil, err
+		}
+		urlSigner, _ := store.(remoteio.URLSigner)
+		return storePublisher(objectstore.WithAttributes(store, attrs), cfg.Format, renderer), urlSigner, nil
+	case objectstore.IsLocal(uri):
+		// file:// またはローカルのパス: クラウドストレージを使用せず、ローカルファイルに保存する
+		if direct {
+			store, err := objectstore.New(ctx, uri)
+			if err != nil {
+				return nil, nil, err
+			}
+			return storePublisher(store, cfg.Format, renderer), nil, nil
+		}
+		return internalAdapters.NewFilePublisher(renderer), nil, nil
+	}
+
+	writer, urlSigner, err := publisher.NewPublisherAndSigner(ctx, uri)
+	if err != nil || (!direct && tmpl == nil && !cfg.EmbedDiff && attrs.IsZero() && !objectstore.Encrypted(uri)) {
+		return writer, urlSigner, err
+	}
+	// gemini-reviewer-core の Publisher は常に組み込みのスタイルの HTML に変換し、オブジェクトの属性や暗号化の鍵も指定できないため、
+	// HTML 以外の形式や独自のテンプレート、差分の埋め込み、属性・暗号化の鍵の指定ではストレージに直接書き込む (署名付きURLの生成には引き続き使用する)
+	store, err := objectstore.New(ctx, uri)
+	if err != nil {
+		return nil, nil, err
+	}
+	return storePublisher(objectstore.WithAttributes(store, attrs), cfg.Format, renderer), urlSigner, nil
+}
+
+// objectAttributes は、レポート本体に設定する Content-Type・Cache-Control・メタデータを返します。
+// いずれかが指定された場合は、CDN やライフサイクルルールからレビュー対象を識別できるよう、
+// repo_url・base_branch・head_branch のメタデータを追加します (同じ名前を --object-metadata で指定した場合はそちらを優先します)。
+func objectAttributes(cfg config.PublishConfig) objectstore.Attributes {
+	attrs := objectstore.Attributes{ContentType: cfg.ContentType, CacheControl: cfg.CacheControl}
+	if cfg.Format != config.FormatPDF {
+		// PDF は内部で圧縮済みのため、圧縮しても小さくならない
+		attrs.ContentEncoding = cfg.Compression
+	}
+	if cfg.ContentType == "" && cfg.CacheControl == "" && len(cfg.ObjectMetadata) == 0 {
+		return attrs
+	}
+	baseRef, headRef := cfg.ReviewConfig.DiffRefs()
+	attrs.Metadata = map[string]string{}
+	for name, value := range map[string]string{"repo_url": cfg.ReviewConfig.RepoURL, "base_branch": baseRef, "head_branch": headRef} {
+		if value != "" {
+			attrs.Metadata[name] = value
+		}
+	}
+	maps.Copy(attrs.Metadata, cfg.ObjectMetadata)
+	return attrs
+}
+
+// storePublisher は、レポートをストレージに直接書き込む形式ごとの Publisher を返します。
+func storePublisher(store objectstore.Store, format string, renderer *internalAdapters.ReportRenderer) publisher.Publisher {
+	switch format {
+	case config.FormatPDF:
+		return internalAdapters.NewPDFPublisher(store, renderer)
+	case config.FormatMarkdown:
+		return internalAdapters.NewMarkdownPublisher(store)
+	default:
+		return internalAdapters.NewHTMLPublisher(store, renderer)
+	}
+}
+
+// decoratePublisher は、設定に応じて writer に一時的なオブジェクト経由の反映 (--atomic-publish) と再試行 (--upload-retries) を付加します。
+// 再試行は一時的なオブジェクトへの書き込みと公開先への反映をまとめてやり直すよう、外側に付加します。
+func decoratePublisher(writer publisher.Publisher, cfg config.PublishConfig, store objectstore.Store) publisher.Publisher {
+	if cfg.AtomicPublish && store != nil && !objectstore.IsLocal(cfg.StorageURI) {
+		atomic, err := internalAdapters.NewAtomicPublisher(writer, store)
+		if err != nil {
+			slog.Warn("公開先のストレージはオブジェクトのコピーに対応していないため、一時的なオブジェクトを経由せずに公開します。", "uri", cfg.StorageURI, "error", err)
+		} else {
+			writer = atomic
+		}
+	}
+	if cfg.UploadRetries > 0 {
+		writer = internalAdapters.NewRetryingPublisher(writer, internalAdapters.RetryPolicy{
+			MaxRetries:     cfg.UploadRetries,
+			InitialBackoff: cfg.ReviewConfig.RetryInitialBackoff,
+			MaxBackoff:     cfg.ReviewConfig.RetryMaxBackoff,
+		})
+	}
+	return writer
+}
+
+// buildPRMetadataFetcher は、変更の意図としてプロンプトに追加するプルリクエストのメタデータを取得するアダプタを構築します。
+// cfg.PRContext が未指定の場合や、API クライアントを構築できない場合 (認証情報がないなど) は nil を返し、メタデータなしでレビューします。
+func buildPRMetadataFetcher(cfg config.ReviewConfig) internalAdapters.PRMetadataFetcher {
+	var (
+		fetcher internalAdapters.PRMetadataFetcher
+		err     error
+	)
+	switch cfg.PRContext {
+	case config.PRContextGitHub:
+		fetcher, err = internalAdapters.NewGitHubClient(cfg.APIClient, cfg.RepoURL)
+	case config.PRContextGitLab:
+		fetcher, err = internalAdapters.NewGitLabClient(cfg.APIClient, cfg.RepoURL)
+	case config.PRContextBitbucket:
+		fetcher, err = internalAdapters.NewBitbucketClient(cfg.APIClient, cfg.RepoURL)
+	default:
+		return nil
+	}
+	if err != nil {
+		slog.Warn("プルリクエストのメタデータを取得できないため、変更の意図なしでレビューします。", "source", cfg.PRContext, "error", err)
+		return nil
+	}
+	return fetcher
+}
+
+// BuildPRCommenter は、公開後 (または serve での自動レビュー後) にレビュー結果をプルリクエストのコメントとして投稿するアダプタを構築します。
+// cfg.GitHubCheck が true の場合は、チェックランの作成も行うアダプタを組み合わせます。
+// いずれも指定されていない場合は nil を返します。
+func BuildPRCommenter(cfg config.PublishConfig) (internalAdapters.PRCommenter, error) {
+	commenter, err := buildPRCommentTarget(cfg)
+	if err != nil || !cfg.GitHubCheck {
+		return commenter, err
+	}
+	client, err := internalAdapters.NewGitHubClient(cfg.HttpClient, cfg.ReviewConfig.RepoURL)
+	if err != nil {
+		return nil, err
+	}
+	checks := internalAdapters.NewGitHubCheckReporter(client)
+	if commenter == nil {
+		return checks, nil
+	}
+	return internalAdapters.PRCommenters{commenter, checks}, nil
+}
+
+// buildPRCommentTarget は、cfg.PRComment の投稿先のアダプタを構築します。cfg.PRComment が空の場合は nil を返します。
+func buildPRCommentTarget(cfg config.PublishConfig) (internalAdapters.PRCommenter, error) {
+	switch cfg.PRComment {
+	case "":
+		return nil, nil
+	case config.PRCommentGitHub, config.PRCommentGitHubReview:
+		client, err := internalAdapters.NewGitHubClient(cfg.HttpClient, cfg.ReviewConfig.RepoURL)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.PRComment == config.PRCommentGitHubReview {
+			return internalAdapters.NewGitHubPRReviewer(client, cfg.PRNumber), nil
+		}
+		return internalAdapters.NewGitHubPRCommenter(client, cfg.PRNumber), nil
+	case config.PRCommentGitLab, config.PRCommentGitLabReview:
+		client, err := internalAdapters.NewGitLabClient(cfg.HttpClient, cfg.ReviewConfig.RepoURL)
+		if err != nil {
+			return nil, err
+		}
+		return internalAdapters.NewGitLabMRCommenter(client, cfg.PRNumber, cfg.PRComment == config.PRCommentGitLabReview), nil
+	case config.PRCommentBitbucket:
+		client, err := internalAdapters.NewBitbucketClient(cfg.HttpClient, cfg.ReviewConfig.RepoURL)
+		if err != nil {
+			return nil, err
+		}
+		return internalAdapters.NewBitbucketPRCommenter(client, cfg.PRNumber), nil
+	case config.PRCommentGerrit:
+		return internalAdapters.NewGerritReviewer(cfg.HttpClient, cfg.ReviewConfig.RepoURL, cfg.PRNumber, cfg.GerritLabel)
+	default:
+		return nil, fmt.Errorf("未対応のプルリクエストのコメントの投稿先です: %s", cfg.PRComment)
+	}
+}
+
 // BuildPublishRunner は、必要な依存関係をすべて構築し、
 // runner.PublisherRunner (インターフェース) を返します。
-func BuildPublishRunner(ctx context.Context, cfg config.PublishConfig) (runner.PublisherRunner, error) {
+// deps に指定された公開先・ストレージ・通知先は、設定から構築せずにそのまま使用します。
+func BuildPublishRunner(ctx context.Context, cfg config.PublishConfig, deps Dependencies) (runner.PublisherRunner, error) {
 
 	// 1. PublisherとSignerの初期化 (マルチクラウド対応)
-	writer, urlSigner, err := publisher.NewPublisherAndSigner(ctx, cfg.StorageURI)
-	if err != nil {
-		return nil, fmt.Errorf("Publisherの初期化に失敗しました (URI: %s): %w", cfg.StorageURI, err)
+	var writer publisher.Publisher
+	var urlSigner remoteio.URLSigner
+	if deps.Publisher != nil {
+		writer = deps.Publisher
+	} else {
+		var err error
+		writer, urlSigner, err = buildPublisher(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("Publisherの初期化に失敗しました (URI: %s): %w", cfg.StorageURI, err)
+		}
 	}
 
 	// 2. Slackアダプターの構築
-	slackNotifier := internalAdapters.NewSlackAdapter(
-		cfg.HttpClient,
-		cfg.SlackWebhookURL,
-	)
+	slackNotifier := buildSlackNotifier(cfg.HttpClient, cfg.SlackWebhookURL, deps.Notifier)
+
+	// 3. プルリクエストへのコメントの投稿先
+	prCommenter, err := BuildPRCommenter(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("プルリクエストへのコメントの投稿の初期化に失敗しました: %w", err)
+	}
+
+	// 4. 公開先の存在確認と重複排除マーカーに使用するストレージ (公開先と同じストレージ)
+	store := deps.Store
+	needsConflictCheck := cfg.OnConflict != "" && cfg.OnConflict != config.ConflictOverwrite
+	if store == nil && (!cfg.DisableIdempotency || needsConflictCheck || cfg.JSONSidecar || cfg.UpdateIndex || cfg.Manifest || cfg.AtomicPublish || cfg.RetentionAge > 0 || cfg.RetentionCount > 0) {
+		store, err = objectstore.New(ctx, cfg.StorageURI)
+		if err != nil {
+			slog.Warn("公開先のストレージを直接操作できないため、重複排除と衝突検出を無効にします。", "uri", cfg.StorageURI, "error", err)
+			store = nil
+		}
+	}
+
+	// 5. 公開の信頼性 (一時的なオブジェクト経由の反映と再試行) を付加する
+	if deps.Publisher == nil {
+		writer = decoratePublisher(writer, cfg, store)
+	}
 
-	// 3. 依存関係を注入して Runner を組み立てる
+	// 6. 依存関係を注入して Runner を組み立てる
 	publicRunner := runner.NewDefaultPublisherRunner(
 		writer,
 		urlSigner,
 		slackNotifier,
+		prCommenter,
+		store,
 	)
 	slog.Debug("PublishRunner の構築が完了しました。")
 
@@ -0,0 +1,19 @@
+package builder
+
+import (
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// Dependencies は、外部サービスに接続するアダプタのうち、設定から構築せずに呼び出し側が指定するものです。
+// 統合テスト (internal/testkit) で、AI・ストレージ・通知を偽の実装に置き換えるために使用します。
+// nil のフィールドは、設定に基づいて通常どおり構築します。
+type Dependencies struct {
+	AI        adapters.CodeReviewAI          // レビューに使用するAI (再試行のデコレータは通常どおり適用する)
+	Publisher publisher.Publisher            // レポートの公開先
+	Store     objectstore.Store              // 公開先の存在確認と重複排除マーカーに使用するストレージ
+	Notifier  internalAdapters.SlackNotifier // Slack への通知
+}
@@ -0,0 +1,93 @@
+package codeowners
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Paths は、CODEOWNERS ファイルを探すリポジトリ内のパスです (GitHub / GitLab の探索順)。
+var Paths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// Rule は、CODEOWNERS の1行 (パターンと所有者) です。
+type Rule struct {
+	Pattern string
+	Owners  []string // "@user"、"@org/team" またはメールアドレス
+	re      *regexp.Regexp
+}
+
+// File は、解析済みの CODEOWNERS ファイルです。
+type File struct {
+	Rules []Rule
+}
+
+// Parse は、CODEOWNERS ファイルの内容を解析します。
+// コメント、空行、GitLab のセクション見出し ("[Section]") は無視します。
+func Parse(data []byte) File {
+	var f File
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "[") || strings.HasPrefix(line, "^[") {
+			continue
+		}
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		f.Rules = append(f.Rules, Rule{Pattern: fields[0], Owners: fields[1:], re: compile(fields[0])})
+	}
+	return f
+}
+
+// Owners は、パスの所有者を返します。CODEOWNERS と同様に、最後に一致したルールを優先します。
+// 一致したルールに所有者が指定されていない場合 (所有者の解除) は nil を返します。
+func (f File) Owners(path string) []string {
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].re.MatchString(path) {
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// compile は、gitignore 形式のパターンを、リポジトリルートからの相対パスに一致する正規表現に変換します。
+//   - "/" で始まるパターン、または途中に "/" を含むパターンはリポジトリルートからのパスに一致します。
+//   - それ以外のパターンは、任意の階層のファイル名・ディレクトリ名に一致します。
+//   - "/" で終わるパターンや、ディレクトリに一致したパターンは、その配下のすべてのファイルに一致します ("*" で終わるパターンを除く)。
+func compile(pattern string) *regexp.Regexp {
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case c == '*' && strings.HasPrefix(pattern[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(pattern[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		b.WriteString("/.*$")
+	case strings.HasSuffix(pattern, "*") && !strings.HasSuffix(pattern, "**"):
+		// "docs/*" は docs 直下のファイルにのみ一致し、サブディレクトリ配下には一致しない (GitHub の仕様)
+		b.WriteString("$")
+	default:
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,66 @@
+package commitlint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSubjectLength は、件名行の推奨最大文字数です。
+const maxSubjectLength = 72
+
+// conventionalTypes は、Conventional Commits で一般的に使われる type の一覧です。
+var conventionalTypes = map[string]bool{
+	"feat": true, "fix": true, "docs": true, "style": true, "refactor": true, "perf": true,
+	"test": true, "build": true, "ci": true, "chore": true, "revert": true,
+}
+
+// headerPattern は、Conventional Commits のヘッダ 'type(scope)!: description' に一致するパターンです。
+var headerPattern = regexp.MustCompile(`^(\w+)(?:\(([^()]+)\))?(!)?: (.+)$`)
+
+// Result は、1件のコミットメッセージに対する機械的な検査結果です。
+type Result struct {
+	Type     string
+	Scope    string
+	Breaking bool
+	Issues   []string
+}
+
+// OK は、検査で問題が見つからなかった場合に true を返します。
+func (r Result) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Check は、コミットメッセージの件名と本文を Conventional Commits 1.0.0 の規約に照らして検査します。
+// ここでは形式的な違反のみを検出し、内容の妥当性の判断はAIに委ねます。
+func Check(subject, body string) Result {
+	var res Result
+
+	m := headerPattern.FindStringSubmatch(subject)
+	if m == nil {
+		res.Issues = append(res.Issues, "ヘッダが 'type(scope): description' の形式ではありません")
+	} else {
+		res.Type, res.Scope, res.Breaking = m[1], m[2], m[3] == "!"
+		if !conventionalTypes[res.Type] {
+			res.Issues = append(res.Issues, fmt.Sprintf("type '%s' は一般的な type (feat, fix, docs など) ではありません", res.Type))
+		}
+		if res.Type != strings.ToLower(res.Type) {
+			res.Issues = append(res.Issues, "type は小文字で記述してください")
+		}
+		description := m[4]
+		if strings.HasSuffix(description, ".") || strings.HasSuffix(description, "。") {
+			res.Issues = append(res.Issues, "description の末尾に句点は付けません")
+		}
+	}
+
+	if n := utf8.RuneCountInString(subject); n > maxSubjectLength {
+		res.Issues = append(res.Issues, fmt.Sprintf("件名が %d 文字を超えています (%d 文字)", maxSubjectLength, n))
+	}
+
+	if strings.Contains(body, "BREAKING CHANGE:") || strings.Contains(body, "BREAKING-CHANGE:") {
+		res.Breaking = true
+	}
+
+	return res
+}
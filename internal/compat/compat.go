@@ -0,0 +1,98 @@
+package compat
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/spf13/pflag"
+)
+
+// Rename は、名前が変更されたフラグ (設定ファイルのキー) です。
+type Rename struct {
+	Old  string // 旧名
+	New  string // 現在の名前
+	Note string // 意味の変更など、置き換え時に注意すべき点 (任意)
+}
+
+// FlagRenames は、旧バージョンから名前が変更されたフラグの一覧です。
+// 旧名は、コマンドライン・設定ファイルのどちらで指定された場合も現在の名前に読み替え、非推奨の警告を出力します。
+// フラグ名を変更する場合は、ここに旧名を追加して互換性を維持してください。
+var FlagRenames = []Rename{
+	{Old: "gemini", New: "model", Note: "--backend で選択したバックエンドのモデル名を指定します"},
+}
+
+// Source は、旧名が使用された箇所の種類です。
+type Source string
+
+const (
+	// SourceFlag は、コマンドラインのフラグで旧名が使用されたことを示します。
+	SourceFlag Source = "flag"
+	// SourceConfig は、設定ファイルのキーで旧名が使用されたことを示します。
+	SourceConfig Source = "config"
+)
+
+// Deprecation は、旧名の使用の記録です。
+type Deprecation struct {
+	Rename
+	Source Source
+	File   string // SourceConfig の場合の設定ファイルのパス
+}
+
+var (
+	mu   sync.Mutex
+	used []Deprecation
+)
+
+// Lookup は、旧名に対応する Rename を返します。旧名でない場合は false を返します。
+func Lookup(name string) (Rename, bool) {
+	for _, r := range FlagRenames {
+		if r.Old == name {
+			return r, true
+		}
+	}
+	return Rename{}, false
+}
+
+// NormalizeFlagName は、旧名のフラグを現在の名前に読み替える pflag の正規化関数です。
+// cobra.Command.SetGlobalNormalizationFunc に設定して使用します。旧名の使用は記録され、Report で警告として出力されます。
+func NormalizeFlagName(_ *pflag.FlagSet, name string) pflag.NormalizedName {
+	if r, ok := Lookup(name); ok {
+		Record(Deprecation{Rename: r, Source: SourceFlag})
+		return pflag.NormalizedName(r.New)
+	}
+	return pflag.NormalizedName(name)
+}
+
+// Record は、旧名の使用を記録します。同じ箇所での同じ旧名の使用は一度だけ記録します。
+func Record(d Deprecation) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, u := range used {
+		if u.Old == d.Old && u.Source == d.Source && u.File == d.File {
+			return
+		}
+	}
+	used = append(used, d)
+}
+
+// Used は、記録された旧名の使用を返します。
+func Used() []Deprecation {
+	mu.Lock()
+	defer mu.Unlock()
+	return append([]Deprecation(nil), used...)
+}
+
+// Report は、記録された旧名の使用を、置き換え先を含む構造化された警告としてログに出力します。
+// フラグの解析はロガーの設定より前に行われるため、ロガーの設定後に呼び出してください。
+func Report() {
+	for _, d := range Used() {
+		attrs := []any{"source", d.Source, "deprecated", d.Old, "replacement", d.New}
+		if d.File != "" {
+			attrs = append(attrs, "file", d.File)
+		}
+		if d.Note != "" {
+			attrs = append(attrs, "note", d.Note)
+		}
+		slog.Warn("非推奨の名前が使用されています。将来のバージョンで削除されるため、新しい名前に置き換えてください。", attrs...)
+	}
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/shouni/go-http-kit/pkg/httpkit"
 )
@@ -10,23 +12,346 @@ import (
 // この構造体は、コマンドライン引数からサービスロジックへ設定を渡すための共通のデータモデルです。
 type ReviewConfig struct {
 	ReviewMode            string
-	GeminiModel           string
+	Backend               string // レビューに使用するAIのバックエンド (BackendGemini, BackendOpenAI, BackendOllama, BackendClaude)
+	Model                 string // バックエンドのモデル名 (Azure OpenAI の場合はデプロイメント名)
+	OpenAIBaseURL         string // OpenAI 互換 API のベースURL
+	OpenAIAPIVersion      string // Azure OpenAI の API バージョン (指定した場合は Azure OpenAI の形式で呼び出す)
+	OllamaHost            string // Ollama サーバーのURL
+	AnthropicBaseURL      string // Anthropic API のベースURL
+	OllamaNumCtx          int    // Ollama で使用するモデルのコンテキスト長 (0 はサーバー・モデルの既定値)
 	RepoURL               string
 	BaseBranch            string
 	FeatureBranch         string
+	FromTag               string // タグ範囲の差分を取る場合の起点タグ
+	ToTag                 string // タグ範囲の差分を取る場合の終点タグ (省略時はフィーチャーブランチ、それもなければベースブランチ)
+	HeadCommit            string // レビューするフィーチャーブランチのコミットハッシュ (serve で Webhook のイベントのコミットに固定する。空の場合はブランチの最新のコミット)
 	SSHKeyPath            string
 	LocalPath             string
 	SkipHostKeyCheck      bool
 	UseExternalGitCommand bool
+	ReadOnly              bool          // ローカルリポジトリのワーキングツリーを変更しない (fetch のみ)
+	Ephemeral             bool          // ディスクを使わず、go-git のインメモリストレージにクローンする
+	DisableGitFallback    bool          // go-git アダプタ失敗時の外部Gitコマンドへの自動切り替えを無効にする
+	IgnoreRepoPrompt      bool          // リポジトリ内の .gemini-review プロンプト設定を無視する
+	CommitConventionFile  string        // commit-msg モードで使用するチーム独自のコミット規約ファイル
+	GlossaryFile          string        // プロンプトに追加するチームの用語集ファイル
+	Persona               string        // プロンプトの先頭に追加するレビュアーのペルソナ (PersonaFile より優先する)
+	PersonaFile           string        // プロンプトの先頭に追加するレビュアーのペルソナファイル
+	SystemInstruction     string        // 組み込みのプロンプトを置き換えるシステムプロンプト (テンプレート。SystemInstructionFile より優先する)
+	SystemInstructionFile string        // 組み込みのプロンプトを置き換えるシステムプロンプトのファイル
+	ExamplesFile          string        // プロンプトの先頭に追加するレビューの例 (差分の抜粋と理想的なレビューの組) のファイル
+	RubricFile            string        // release モードで採点するチームのリリース判定基準 (重み付きの評価項目) のファイル
+	LockTimeout           time.Duration // ローカルリポジトリのロック取得を待機する最大時間
+	FailOn                string        // この深刻度以上の指摘事項があれば失敗とする ("critical", "high", "medium", "low")
+	VerifyFindings        bool          // 各指摘事項を該当箇所の差分と照らして AI に再確認させ、誤りと判定されたものを取り除く
+	SuggestPatches        bool          // 各指摘事項の修正案を unified diff で生成し、適用できるものをレポートに含める
+	BaselineFile          string        // チームが受け入れた指摘事項のベースライン (空の場合はリポジトリのルートの .gemini-review-baseline.json)
+	IgnoreBaseline        bool          // ベースラインによる指摘事項の抑制を行わない (ベースラインの再生成時など)
+	Timezone              string        // レポート・通知・保存先キーの日時に使用するタイムゾーン (空の場合はホストのタイムゾーン)
+	MaxPromptTokens       int           // 1回のリクエストで送信するプロンプトのトークン数の上限 (0 は無制限)
+	OnBudgetExceeded      string        // トークン数が上限を超えた場合の動作 (BudgetRefuse または BudgetChunk)
+	ParallelFiles         int           // 2 以上の場合、差分をファイルごとに分割してこの数まで並行してレビューする (0, 1 は分割しない)
+	Context               string        // 差分に加えてプロンプトに含めるコンテキスト (ContextDiff, ContextFullFiles, ContextImports のカンマ区切り)
+	ContextMaxBytes       int           // ContextFullFiles / ContextImports で含める内容の、それぞれの合計サイズの上限 (バイト)
+	MaxRetries            int           // Gemini API が 429 / 5xx を返した場合の最大再試行回数 (0 は再試行しない)
+	RetryInitialBackoff   time.Duration // 1回目の再試行までの待機時間の上限 (以降は2倍ずつ増加)
+	RetryMaxBackoff       time.Duration // 再試行までの待機時間の上限
+	RateLimitRPM          int           // AI の呼び出しを1分あたりこの回数までに制限する (0 は無制限)
+	RateLimitTPM          int           // AI に送信するプロンプトを1分あたりこのトークン数 (概算) までに制限する (0 は無制限)
+	RequireFindings       bool          // --fail-on 以外の用途 (注釈付き差分など) で構造化された指摘事項を必要とする
+	Temperature           string        // 生成時の温度 ("0.2" または "release=0,detail=0.7" 形式)
+	TopP                  string        // 生成時の top-p (Temperature と同じ形式)
+	MaxOutputTokens       string        // 生成する最大トークン数 (Temperature と同じ形式)
+	ImpactAnalysis        string        // 変更の影響範囲を求めるビルドグラフ (ImpactNone, ImpactGo, ImpactBazel)
+	BlastRadiusThreshold  int           // 影響範囲がこの数を超える場合に指摘事項を追加する (0 は無効)
+	BlastRadiusSeverity   string        // BlastRadiusThreshold を超えた場合に追加する指摘事項の深刻度
+	PRContext             string        // 変更の意図としてプロンプトに追加するプルリクエストのメタデータの取得先 (PRContextNone, PRContextGitHub など)
+	DebtScan              bool          // 差分で追加された TODO / FIXME とテストのスキップを検出し、LOW の指摘事項として追加する
+	DisableRedaction      bool          // AI に送信する前の差分の機密情報のマスク (組み込みのルール) を無効にする
+	RedactPII             bool          // 差分とコンテキストに含まれる個人情報 (メールアドレス、電話番号) をマスクする
+	RedactPatternsFile    string        // 追加でマスクする値の正規表現を1行に1つ記述したファイル
+	Incremental           bool          // 前回レビューしたコミット以降の差分のみをレビューする
+	StateURI              string        // 前回レビューしたコミットを記録する状態ファイル (ローカルパス、gs:// または s3://。空の場合はキャッシュディレクトリ)
+	SessionFile           string        // chat コマンドで使用する、最後のレビュー結果と差分の保存先 (空の場合はキャッシュディレクトリ)
+	HTTPClient            *http.Client  // AI の API の呼び出しに使用する、ネットワーク設定を適用したクライアント (nil の場合は既定のクライアント)
+
+	// APIClient は、プルリクエストのメタデータの取得 (GitHub・GitLab・Bitbucket の API) に使用する httpkit のクライアントです。
+	APIClient httpkit.ClientInterface
+}
+
+const (
+	// BudgetRefuse は、プロンプトがトークン数の上限を超えた場合にレビューを中止する動作です。
+	BudgetRefuse = "refuse"
+	// BudgetChunk は、プロンプトがトークン数の上限を超えた場合に差分をファイル単位に分割してレビューする動作です。
+	BudgetChunk = "chunk"
+)
+
+const (
+	// BackendGemini は、Gemini API でレビューするバックエンドです (既定)。
+	BackendGemini = "gemini"
+	// BackendOpenAI は、OpenAI の Chat Completions API 互換のエンドポイント (OpenAI, Azure OpenAI, 社内ゲートウェイなど) でレビューするバックエンドです。
+	BackendOpenAI = "openai"
+	// BackendOllama は、Ollama の HTTP API でローカル・閉域網の LLM を使用してレビューするバックエンドです。
+	BackendOllama = "ollama"
+	// BackendClaude は、Anthropic の Messages API で Claude を使用してレビューするバックエンドです。
+	BackendClaude = "claude"
+)
+
+// DefaultModel は、モデル名が指定されていない場合に使用するバックエンドごとのモデル名を返します。
+func DefaultModel(backend string) string {
+	switch backend {
+	case BackendOpenAI:
+		return "gpt-4o-mini"
+	case BackendOllama:
+		return "codellama"
+	case BackendClaude:
+		return "claude-sonnet-4-5"
+	default:
+		return "gemini-2.5-flash"
+	}
+}
+
+const (
+	// ImpactNone は、影響範囲の解析を行わない設定です (既定)。
+	ImpactNone = "none"
+	// ImpactGo は、Go モジュールのパッケージのインポート関係から影響範囲を求める設定です。
+	ImpactGo = "go"
+	// ImpactBazel は、bazel query の逆依存関係 (rdeps) から影響範囲を求める設定です。
+	ImpactBazel = "bazel"
+)
+
+const (
+	// PRContextNone は、プルリクエストのメタデータをプロンプトに追加しない設定です (既定)。
+	PRContextNone = "none"
+	// PRContextGitHub は、GitHub のプルリクエストのタイトル・説明・クローズするイシューをプロンプトに追加する設定です。
+	PRContextGitHub = "github"
+	// PRContextGitLab は、GitLab のマージリクエストのタイトル・説明・クローズするイシューをプロンプトに追加する設定です。
+	PRContextGitLab = "gitlab"
+	// PRContextBitbucket は、Bitbucket のプルリクエストのタイトル・説明をプロンプトに追加する設定です。
+	PRContextBitbucket = "bitbucket"
+)
+
+const (
+	// FormatText は、レビュー結果をそのまま出力する形式です (既定)。
+	FormatText = "text"
+	// FormatAnnotatedDiff は、unified diff の該当箇所に指摘事項を "#>" で始まる行として埋め込んだ形式です。
+	FormatAnnotatedDiff = "annotated-diff"
+	// FormatCodeQuality は、指摘事項を GitLab の Code Quality レポート (gl-code-quality-report.json) の JSON として出力する形式です。
+	FormatCodeQuality = "code-quality"
+	// FormatGitHubAnnotations は、指摘事項を GitHub Actions のワークフローコマンド (::warning file=...,line=...::) として出力する形式です。
+	FormatGitHubAnnotations = "github-annotations"
+	// FormatHTML は、publish でレビュー結果をスタイル付きの HTML に変換して公開する形式です (既定)。
+	FormatHTML = "html"
+	// FormatMarkdown は、publish でレビュー結果の Markdown を変換せずに公開する形式です。
+	FormatMarkdown = "markdown"
+	// FormatPDF は、publish でレビュー結果を HTML と同じテンプレートから PDF に変換して公開する形式です。
+	FormatPDF = "pdf"
+)
+
+const (
+	// OutputFull は、サマリーと詳細を含むレポート全体を出力する設定です (既定)。
+	OutputFull = "full"
+	// OutputSummary は、判定・主なリスク・件数のサマリーのみを出力する設定です。
+	OutputSummary = "summary"
+)
+
+const (
+	// ContextDiff は、差分のみをプロンプトに含める設定です (既定)。
+	ContextDiff = "diff"
+	// ContextFullFiles は、差分に加えて変更されたファイルの変更後の内容全体をプロンプトに含める設定です。
+	ContextFullFiles = "full-files"
+	// ContextImports は、変更されたファイルがインポートしているリポジトリ内の宣言をプロンプトに含める設定です (Go/TypeScript/Python)。
+	ContextImports = "imports"
+)
+
+// Modes は、カンマ区切りで指定されたレビューモード (例: "detail,security") を、重複と空要素を除いて指定順に返します。
+func (rc ReviewConfig) Modes() []string {
+	return splitList(rc.ReviewMode)
+}
+
+// Contexts は、カンマ区切りで指定されたコンテキスト (例: "full-files,imports") を、重複と空要素を除いて指定順に返します。
+func (rc ReviewConfig) Contexts() []string {
+	return splitList(rc.Context)
+}
+
+// HasContext は、指定されたコンテキストが有効かを返します。
+func (rc ReviewConfig) HasContext(kind string) bool {
+	for _, c := range rc.Contexts() {
+		if c == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList は、カンマ区切りの文字列を、前後の空白・重複・空要素を除いて指定順に返します。
+func splitList(s string) []string {
+	var items []string
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		items = append(items, item)
+	}
+	return items
+}
+
+// tagRefPrefix は、タグを完全な参照名で表す際のプレフィックスです。
+const tagRefPrefix = "refs/tags/"
+
+// DiffRefs は、差分とコミットログの比較対象となる参照 (base, head) を返します。
+// タグ範囲が指定されている場合はタグの完全な参照名 (refs/tags/...) を、それ以外はブランチ名をそのまま返します。
+// HeadCommit が指定されている場合は、フィーチャーブランチの代わりにそのコミットハッシュを head とします。
+// GitService の実装は、"refs/" で始まる参照をリモート追跡ブランチではなく完全な参照名として扱います。
+func (rc ReviewConfig) DiffRefs() (string, string) {
+	if rc.FromTag == "" {
+		if rc.HeadCommit != "" {
+			return rc.BaseBranch, rc.HeadCommit
+		}
+		return rc.BaseBranch, rc.FeatureBranch
+	}
+
+	head := rc.FeatureBranch
+	switch {
+	case rc.ToTag != "":
+		head = tagRefPrefix + rc.ToTag
+	case head == "":
+		head = rc.BaseBranch
+	}
+	return tagRefPrefix + rc.FromTag, head
+}
+
+// StateRefs は、--incremental の状態ファイルのキーに使用する参照 (base, head) を返します。
+// DiffRefs と異なり、HeadCommit が指定されていてもフィーチャーブランチを head とするため、
+// serve のように push ごとにコミットを指定してレビューする場合も、同じブランチでは同じキーになります。
+func (rc ReviewConfig) StateRefs() (string, string) {
+	if rc.FeatureBranch != "" {
+		rc.HeadCommit = ""
+	}
+	return rc.DiffRefs()
+}
+
+// NetworkConfig は、外部サービス (Gemini API, Slack, ストレージ) への接続に使用するネットワーク設定です。
+type NetworkConfig struct {
+	ProxyURL      string // HTTP/HTTPS/SOCKS5 プロキシのURL (例: http://proxy:8080, socks5://proxy:1080)
+	NoProxy       string // プロキシを経由しないホストのカンマ区切りリスト (NO_PROXY 形式)
+	CABundle      string // 追加で信頼するCA証明書のPEMファイル
+	ClientCert    string // mTLS で提示するクライアント証明書のPEMファイル
+	ClientKey     string // クライアント証明書に対応する秘密鍵のPEMファイル
+	TLSMinVersion string // TLSの最小バージョン ("1.2" または "1.3")
+}
+
+// IsZero は、ネットワーク設定が何も指定されていない場合に true を返します。
+func (nc NetworkConfig) IsZero() bool {
+	return nc == NetworkConfig{}
+}
+
+// Normalize は設定値の文字列フィールドから前後の空白を一括で削除します。
+func (nc *NetworkConfig) Normalize() {
+	if nc == nil {
+		return
+	}
+	nc.ProxyURL = strings.TrimSpace(nc.ProxyURL)
+	nc.NoProxy = strings.TrimSpace(nc.NoProxy)
+	nc.CABundle = strings.TrimSpace(nc.CABundle)
+	nc.ClientCert = strings.TrimSpace(nc.ClientCert)
+	nc.ClientKey = strings.TrimSpace(nc.ClientKey)
+	nc.TLSMinVersion = strings.TrimSpace(nc.TLSMinVersion)
+}
+
+// S3Config は、s3:// のURIで S3 互換ストレージ (MinIO, Cloudflare R2 など) に接続する場合の設定です。
+type S3Config struct {
+	Endpoint  string // S3 互換ストレージのエンドポイントURL (例: https://minio.internal:9000)。空の場合は Amazon S3
+	PathStyle bool   // true の場合、バケット名をホスト名ではなくパスに含めてアクセスする
+}
+
+// EncryptionConfig は、ストレージに書き込むオブジェクトを顧客管理の鍵で暗号化する設定です。
+type EncryptionConfig struct {
+	GCSKMSKey string // GCS の CMEK (Cloud KMS の鍵のリソース名)
+	S3KMSKey  string // S3 の SSE-KMS の鍵 (ID・ARN・エイリアス、または AWS マネージドキーを示す "aws:kms")
 }
 
 type PublishConfig struct {
+	HttpClient          httpkit.ClientInterface
+	ReviewConfig        ReviewConfig
+	StorageURI          string
+	AdditionalURIs      []string // StorageURI に加えて同じレポートを公開する公開先 (Slack通知は StorageURI の公開時にのみ行う)
+	SlackWebhookURL     string
+	IdempotencyKey      string            // 再実行時の重複排除に使用するキー (省略時はCIの実行IDと公開内容から生成)
+	DisableIdempotency  bool              // true の場合、重複排除マーカーを使用しない
+	OnConflict          string            // 公開先に既にオブジェクトが存在する場合の動作 (ConflictOverwrite, ConflictVersion, ConflictFail)
+	Provisional         bool              // true の場合、分割レビューの各パートの完了ごとに暫定版のレポートを同じキーに公開する
+	PublishOnInterrupt  bool              // true の場合、中断シグナルによりレビューが途中で終了しても、途中までの結果を公開する
+	Languages           []string          // 公開するレポートの言語コード (例: ["ja", "en"])。最初の言語の版を StorageURI に公開する
+	SkipNotify          bool              // true の場合、公開後の Slack 通知を行わない (翻訳版の公開に使用)
+	HTMLTemplate        string            // レポートの HTML に使用する独自のテンプレート (html/template 形式) のパス。空の場合は組み込みのスタイルを使用する
+	EmbedDiff           bool              // true の場合、レビュー対象の差分 (ファイルごとに折りたたみ可能・色付け) を HTML / PDF のレポートに埋め込む
+	ContentType         string            // レポート本体の Content-Type (空の場合は形式ごとの既定値)
+	CacheControl        string            // レポート本体に設定する Cache-Control
+	ObjectMetadata      map[string]string // レポート本体に設定するユーザー定義メタデータ
+	Compression         string            // レポートと JSON サイドカーの圧縮形式 ("gzip")。空の場合は圧縮しない
+	SignedURLExpiration time.Duration     // 通知する署名付きURLの有効期限 (0 の場合は30分)
+	PublicURL           bool              // true の場合、公開バケットを前提として署名せずに公開URLを通知する
+	UploadRetries       int               // レポートの公開が一時的なエラーで失敗した場合の最大再試行回数 (0 は再試行しない)
+	AtomicPublish       bool              // true の場合、レポートを一時的なオブジェクトに書き込んでから公開先にコピーする (GCS / S3 / Azure)
+	RetentionAge        time.Duration     // 0 より大きい場合、公開後にこの期間より古いレポートを公開先のプレフィックスから削除する
+	RetentionCount      int               // 0 より大きい場合、公開後に新しい順にこの件数を超えるレポートを公開先のプレフィックスから削除する
+	UpdateIndex         bool              // true の場合、公開先のプレフィックスにある過去のレビューの一覧 (index.html と index.json) を更新する
+	Manifest            bool              // true の場合、公開先のプレフィックスにあるレビューのマニフェスト (manifest.json) に公開したレビューを記録する
+	JSONSidecar         bool              // true の場合、レポートと同じ場所に機械可読な JSON サイドカー (拡張子を .json に置き換えたURI) を保存する
+	Format              string            // 公開するレポートの形式 (FormatHTML / FormatMarkdown / FormatPDF。空の場合は FormatHTML)
+	DocsRepoURL         string            // git-branch:// の公開先のリポジトリ (空の場合はレビュー対象のリポジトリ)
+	DocsLocalPath       string            // git-branch:// の公開先のリポジトリのローカルのクローンのパス
+	HTTPMethod          string            // HTTP の公開先に送信するメソッド (POST または PUT)
+	PRComment           string            // 公開後にレビュー結果をコメントとして投稿するプルリクエストのホスティングサービス (PRCommentGitHub など。空の場合は投稿しない)
+	PRNumber            int               // コメントを投稿するプルリクエスト (マージリクエスト) の番号 (0 の場合はフィーチャーブランチから検索する)
+	GerritLabel         string            // Gerrit の変更に投票するラベル (例: AI-Review。空の場合は投票しない)
+	GitHubCheck         bool              // true の場合、公開後にレビューしたコミットに GitHub のチェックランを作成する
+	HTTPHeader          http.Header       // HTTP の公開先へのリクエストに付加するヘッダー (認証ヘッダーなど)
+}
+
+// ReviewersConfig は、レビュアーの推奨 (reviewers コマンド) に必要な設定です。
+type ReviewersConfig struct {
 	HttpClient      httpkit.ClientInterface
 	ReviewConfig    ReviewConfig
-	StorageURI      string
-	SlackWebhookURL string
+	Count           int           // 推奨する人数
+	History         time.Duration // 経験の集計に使用する変更履歴の期間
+	TeamFile        string        // レビュアー候補のハンドルとメールアドレスの対応、不在の候補を記述した YAML ファイル
+	HistoryURI      string        // 担当中のレビューの件数を求めるレビューの履歴 (公開先の manifest.json)。推奨したレビュアーも記録する
+	LoadWindow      time.Duration // レビューの履歴のうち、担当中として数える推奨の期間
+	IncludeTeams    bool          // CODEOWNERS のチーム ("@org/team") も候補に含める
+	SlackWebhookURL string        // 空でない場合、推奨結果を Slack に投稿する
+	PRComment       string        // 推奨結果をコメントとして投稿するプルリクエストのホスティングサービス (PRCommentGitHub など。空の場合は投稿しない)
+	PRNumber        int           // コメントを投稿するプルリクエスト (マージリクエスト) の番号 (0 の場合はフィーチャーブランチから検索する)
 }
 
+const (
+	// ConflictOverwrite は、公開先の既存オブジェクトを上書きする動作です (既定)。
+	ConflictOverwrite = "overwrite"
+	// ConflictVersion は、公開先に既存オブジェクトがある場合に、バージョン番号を付けた別のキーに保存する動作です。
+	ConflictVersion = "version"
+	// ConflictFail は、公開先に既存オブジェクトがある場合に公開を中止する動作です。
+	ConflictFail = "fail"
+)
+
+const (
+	// PRCommentGitHub は、GitHub のプルリクエストにレビュー結果をコメントとして投稿する指定です。
+	PRCommentGitHub = "github"
+	// PRCommentGitHubReview は、GitHub のプルリクエストに指摘事項を差分の行へのインラインコメントとしてレビューを投稿する指定です。
+	PRCommentGitHubReview = "github-review"
+	// PRCommentGitLab は、GitLab のマージリクエストにレビュー結果をコメント (ノート) として投稿する指定です。
+	PRCommentGitLab = "gitlab"
+	// PRCommentGitLabReview は、GitLab のマージリクエストに指摘事項を差分の行へのディスカッションとして作成し、サマリーをコメントとして投稿する指定です。
+	PRCommentGitLabReview = "gitlab-review"
+	// PRCommentBitbucket は、Bitbucket Cloud または Bitbucket Server / Data Center のプルリクエストにレビュー結果をコメントとして投稿する指定です。
+	PRCommentBitbucket = "bitbucket"
+	// PRCommentGerrit は、Gerrit の変更にレビュー結果を変更メッセージとして投稿する指定です。
+	PRCommentGerrit = "gerrit"
+)
+
 // Normalize は設定値の文字列フィールドから前後の空白を一括で削除します。
 func (rc *ReviewConfig) Normalize() {
 	if rc == nil {
@@ -35,8 +360,40 @@ func (rc *ReviewConfig) Normalize() {
 	rc.RepoURL = strings.TrimSpace(rc.RepoURL)
 	rc.BaseBranch = strings.TrimSpace(rc.BaseBranch)
 	rc.FeatureBranch = strings.TrimSpace(rc.FeatureBranch)
+	rc.FromTag = strings.TrimSpace(rc.FromTag)
+	rc.ToTag = strings.TrimSpace(rc.ToTag)
 	rc.LocalPath = strings.TrimSpace(rc.LocalPath)
-	rc.ReviewMode = strings.TrimSpace(rc.ReviewMode)
-	rc.GeminiModel = strings.TrimSpace(rc.GeminiModel)
+	rc.ReviewMode = strings.Join(rc.Modes(), ",")
+	rc.Backend = strings.ToLower(strings.TrimSpace(rc.Backend))
+	rc.Model = strings.TrimSpace(rc.Model)
+	if rc.Model == "" {
+		rc.Model = DefaultModel(rc.Backend)
+	}
+	rc.OpenAIBaseURL = strings.TrimSpace(rc.OpenAIBaseURL)
+	rc.OpenAIAPIVersion = strings.TrimSpace(rc.OpenAIAPIVersion)
+	rc.OllamaHost = strings.TrimSpace(rc.OllamaHost)
+	rc.AnthropicBaseURL = strings.TrimSpace(rc.AnthropicBaseURL)
 	rc.SSHKeyPath = strings.TrimSpace(rc.SSHKeyPath)
+	rc.CommitConventionFile = strings.TrimSpace(rc.CommitConventionFile)
+	rc.GlossaryFile = strings.TrimSpace(rc.GlossaryFile)
+	rc.Persona = strings.TrimSpace(rc.Persona)
+	rc.PersonaFile = strings.TrimSpace(rc.PersonaFile)
+	rc.FailOn = strings.ToLower(strings.TrimSpace(rc.FailOn))
+	rc.Timezone = strings.TrimSpace(rc.Timezone)
+	rc.OnBudgetExceeded = strings.ToLower(strings.TrimSpace(rc.OnBudgetExceeded))
+	rc.Context = strings.Join(splitList(strings.ToLower(rc.Context)), ",")
+	rc.Temperature = strings.Join(splitList(rc.Temperature), ",")
+	rc.TopP = strings.Join(splitList(rc.TopP), ",")
+	rc.MaxOutputTokens = strings.Join(splitList(rc.MaxOutputTokens), ",")
+	rc.ImpactAnalysis = strings.ToLower(strings.TrimSpace(rc.ImpactAnalysis))
+	rc.PRContext = strings.ToLower(strings.TrimSpace(rc.PRContext))
+	rc.BlastRadiusSeverity = strings.ToLower(strings.TrimSpace(rc.BlastRadiusSeverity))
+}
+
+// NeedsFindings は、レビュー結果から構造化された指摘事項を取り出す必要があるかを返します。
+// --debt-scan と影響範囲のしきい値 (--blast-radius-threshold) はレポートに指摘事項ブロックを追加し、ブロックがあるレポートでは
+// 見出しの深刻度タグを指摘事項として扱わないため、AI にも指摘事項ブロックを出力させます。
+func (rc ReviewConfig) NeedsFindings() bool {
+	impactFindings := rc.ImpactAnalysis != "" && rc.ImpactAnalysis != ImpactNone && rc.BlastRadiusThreshold > 0
+	return rc.FailOn != "" || rc.RequireFindings || rc.VerifyFindings || rc.SuggestPatches || rc.DebtScan || impactFindings
 }
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GenerationParams は、Gemini の生成パラメータです。
+// nil (MaxOutputTokens は 0) の項目は既定値を使用します。
+type GenerationParams struct {
+	Temperature     *float32
+	TopP            *float32
+	MaxOutputTokens int32
+}
+
+// HasGenerationParams は、生成パラメータ (--temperature, --top-p, --max-output-tokens) のいずれかが指定されているかを返します。
+func (rc ReviewConfig) HasGenerationParams() bool {
+	return rc.Temperature != "" || rc.TopP != "" || rc.MaxOutputTokens != ""
+}
+
+// GenerationParams は、指定されたモードに適用する生成パラメータを返します。
+// 各パラメータは "0.2" のように全モード共通の値、または "release=0,detail=0.7" のようにモードごとの値で指定でき、
+// 両方を組み合わせた場合 (例: "0.3,release=0") はモードごとの値を優先します。
+func (rc ReviewConfig) GenerationParams(mode string) (GenerationParams, error) {
+	var params GenerationParams
+
+	temperature, err := perModeValue(rc.Temperature, mode)
+	if err != nil {
+		return params, fmt.Errorf("--temperature の指定が不正です: %w", err)
+	}
+	if temperature != "" {
+		v, err := parseFloatIn(temperature, 0, 2)
+		if err != nil {
+			return params, fmt.Errorf("--temperature の指定が不正です: %w", err)
+		}
+		params.Temperature = &v
+	}
+
+	topP, err := perModeValue(rc.TopP, mode)
+	if err != nil {
+		return params, fmt.Errorf("--top-p の指定が不正です: %w", err)
+	}
+	if topP != "" {
+		v, err := parseFloatIn(topP, 0, 1)
+		if err != nil {
+			return params, fmt.Errorf("--top-p の指定が不正です: %w", err)
+		}
+		params.TopP = &v
+	}
+
+	maxTokens, err := perModeValue(rc.MaxOutputTokens, mode)
+	if err != nil {
+		return params, fmt.Errorf("--max-output-tokens の指定が不正です: %w", err)
+	}
+	if maxTokens != "" {
+		v, err := strconv.ParseInt(maxTokens, 10, 32)
+		if err != nil || v <= 0 {
+			return params, fmt.Errorf("--max-output-tokens の指定が不正です: 正の整数を指定してください: %s", maxTokens)
+		}
+		params.MaxOutputTokens = int32(v)
+	}
+
+	return params, nil
+}
+
+// perModeValue は、"0.3,release=0" 形式の指定から、mode に適用する値を返します。
+// モードごとの値がなければ共通の値を、どちらもなければ空文字を返します。
+func perModeValue(spec, mode string) (string, error) {
+	var common, specific string
+	for _, item := range splitList(spec) {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			if common != "" {
+				return "", fmt.Errorf("共通の値が複数指定されています: %s", spec)
+			}
+			common = item
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "" || value == "" {
+			return "", fmt.Errorf("'モード=値' の形式で指定してください: %s", item)
+		}
+		if key == mode {
+			specific = value
+		}
+	}
+	if specific != "" {
+		return specific, nil
+	}
+	return common, nil
+}
+
+// parseFloatIn は、文字列を min 以上 max 以下の float32 に変換します。
+func parseFloatIn(s string, min, max float64) (float32, error) {
+	v, err := strconv.ParseFloat(s, 32)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("%g から %g の数値を指定してください: %s", min, max, s)
+	}
+	return float32(v), nil
+}
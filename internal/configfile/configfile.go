@@ -0,0 +1,191 @@
+package configfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"git-gemini-cli/internal/compat"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPath は、--config-file が未指定の場合に参照する環境変数名です。
+const EnvPath = "GIT_GEMINI_CLI_CONFIG"
+
+// Values は、設定ファイルの内容です。キーはフラグ名 (先頭の -- を除いたもの) です。
+type Values map[string]Value
+
+// Value は、設定ファイルの1つのキーの値です。
+type Value struct {
+	Text  string   // フラグに渡す文字列 (リストの場合は要素をカンマ区切りに連結したもの)
+	Items []string // リストで指定された場合の各要素 (スカラーの場合は nil)
+}
+
+// Load は、YAML 形式の設定ファイルを読み込みます。
+// トップレベルはフラグ名をキーとするマッピングで、リストの値は要素とカンマ区切りに連結した文字列の両方を保持します。
+func Load(path string) (Values, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	root, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイル %s の解析に失敗しました: %w", path, err)
+	}
+	values := Values{}
+	if root == nil {
+		return values, nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		switch value.Kind {
+		case yaml.ScalarNode:
+			values[key] = Value{Text: value.Value}
+		case yaml.SequenceNode:
+			items := make([]string, 0, len(value.Content))
+			for _, item := range value.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("設定ファイル %s のキー '%s' のリストには文字列・数値・真偽値のみ指定できます", path, key)
+				}
+				items = append(items, item.Value)
+			}
+			values[key] = Value{Text: strings.Join(items, ","), Items: items}
+		default:
+			return nil, fmt.Errorf("設定ファイル %s のキー '%s' には文字列・数値・真偽値またはそのリストを指定してください", path, key)
+		}
+	}
+	return values, nil
+}
+
+// Apply は、設定ファイルの値をフラグに設定します。
+// キーと同名のフラグを持つすべての FlagSet に設定し、コマンドラインで明示的に指定されたフラグは上書きしません。
+// 複数回指定できるフラグ (--feature-branch など) にリストを指定した場合は、要素を分割せずにそのまま設定します。
+// 旧名のキーは現在の名前に読み替え、compat に非推奨の使用として記録します。
+// いずれの FlagSet にも存在しないキーはエラーとします (キーの誤りに気づけるようにするため)。
+func Apply(path string, values Values, sets ...*pflag.FlagSet) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var unknown []string
+	for _, key := range keys {
+		name := key
+		if r, ok := compat.Lookup(key); ok {
+			compat.Record(compat.Deprecation{Rename: r, Source: compat.SourceConfig, File: path})
+			name = r.New
+		}
+		found := false
+		for _, fs := range sets {
+			flag := fs.Lookup(name)
+			if flag == nil {
+				continue
+			}
+			found = true
+			if flag.Changed {
+				continue
+			}
+			if err := set(fs, flag, values[key]); err != nil {
+				return fmt.Errorf("設定ファイル %s のキー '%s' の値が不正です: %w", path, key, err)
+			}
+		}
+		if !found {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("設定ファイル %s に不明なキーがあります: %s", path, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// set は、value をフラグに設定します。
+// リストの値を複数回指定できるフラグに設定する場合は、要素にカンマを含むブランチ名なども1つの値として扱うよう、カンマ区切りの文字列を経由しません。
+func set(fs *pflag.FlagSet, flag *pflag.Flag, value Value) error {
+	sv, ok := flag.Value.(pflag.SliceValue)
+	if !ok || value.Items == nil {
+		return fs.Set(flag.Name, value.Text)
+	}
+	if err := sv.Replace(value.Items); err != nil {
+		return err
+	}
+	flag.Changed = true
+	return nil
+}
+
+// Migrate は、設定ファイルの旧名のキーを現在の名前に書き換えた内容と、適用した名前の変更を返します。
+// コメントとキーの順序は維持します。現在の名前のキーが既に存在する場合は、そちらを優先して旧名のキーを削除します。
+func Migrate(data []byte) ([]byte, []compat.Rename, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("設定ファイルの解析に失敗しました: %w", err)
+	}
+	root, err := mapping(&doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	if root == nil {
+		return data, nil, nil
+	}
+
+	present := map[string]bool{}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		present[root.Content[i].Value] = true
+	}
+
+	var applied []compat.Rename
+	content := make([]*yaml.Node, 0, len(root.Content))
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		if r, ok := compat.Lookup(key.Value); ok {
+			applied = append(applied, r)
+			if present[r.New] {
+				continue
+			}
+			key.Value = r.New
+			present[r.New] = true
+		}
+		content = append(content, key, value)
+	}
+	if len(applied) == 0 {
+		return data, nil, nil
+	}
+	root.Content = content
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("設定ファイルの書き出しに失敗しました: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, nil, fmt.Errorf("設定ファイルの書き出しに失敗しました: %w", err)
+	}
+	return buf.Bytes(), applied, nil
+}
+
+func parse(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return mapping(&doc)
+}
+
+// mapping は、ドキュメントのトップレベルのマッピングを返します。空のドキュメントの場合は nil を返します。
+func mapping(doc *yaml.Node) (*yaml.Node, error) {
+	if doc.Kind == 0 || len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("設定ファイルのトップレベルは 'フラグ名: 値' のマッピングにしてください")
+	}
+	return root, nil
+}
@@ -0,0 +1,121 @@
+package debt
+
+import (
+	"path"
+	"regexp"
+	"strings"
+
+	"git-gemini-cli/internal/diffutil"
+)
+
+// Kind は、技術的負債を示すマーカーの種類です。
+type Kind string
+
+const (
+	// KindTODO は、TODO コメントです。
+	KindTODO Kind = "TODO"
+	// KindFIXME は、FIXME コメントです。
+	KindFIXME Kind = "FIXME"
+	// KindSkip は、テストのスキップ (t.Skip, it.skip, @pytest.mark.skip など) です。
+	KindSkip Kind = "skip"
+)
+
+// Kinds は、マーカーの種類を表示順に並べたものです。
+var Kinds = []Kind{KindTODO, KindFIXME, KindSkip}
+
+// Marker は、差分の追加行で見つかった1件のマーカーです。
+type Marker struct {
+	Kind Kind
+	Path string // 変更後のファイルのパス
+	Line int    // 変更後のファイルにおける行番号 (1始まり)
+	Text string // マーカーを含む行 (前後の空白を除く)
+}
+
+// Result は、差分のスキャン結果です。
+type Result struct {
+	Added   []Marker     // 追加された行で見つかったマーカー (差分の出現順)
+	Removed map[Kind]int // 削除された行で見つかったマーカーの種類ごとの件数 (解消された負債)
+	counts  map[Kind]int // Added の種類ごとの件数
+}
+
+// Count は、追加された指定の種類のマーカーの件数を返します。
+func (r Result) Count(kind Kind) int {
+	return r.counts[kind]
+}
+
+// Empty は、追加・削除のいずれのマーカーも見つからなかった場合に true を返します。
+func (r Result) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0
+}
+
+// commentPattern は、TODO / FIXME のマーカーです。
+// 識別子 (todoList など) や文章中の単語を拾わないよう、大文字の単語のみを対象にします。
+var commentPattern = regexp.MustCompile(`\b(TODO|FIXME)\b`)
+
+// skipPatterns は、言語 (拡張子) ごとのテストのスキップを示す記述です。
+var skipPatterns = map[string]*regexp.Regexp{
+	".go":   regexp.MustCompile(`\bt\.Skip(?:f|Now)?\(`),
+	".py":   regexp.MustCompile(`@pytest\.mark\.(?:skip|skipif|xfail)\b|\bpytest\.skip\(|@unittest\.skip(?:If|Unless)?\b|\bself\.skipTest\(`),
+	".js":   jsSkipPattern,
+	".jsx":  jsSkipPattern,
+	".ts":   jsSkipPattern,
+	".tsx":  jsSkipPattern,
+	".mjs":  jsSkipPattern,
+	".cjs":  jsSkipPattern,
+	".java": jvmSkipPattern,
+	".kt":   jvmSkipPattern,
+	".rb":   regexp.MustCompile(`^\s*(?:skip|pending)\s*\(?["']|\bx(?:it|describe|context)\b`),
+	".rs":   regexp.MustCompile(`#\[ignore\b`),
+	".cs":   regexp.MustCompile(`\[(?:Ignore|Fact\(Skip\s*=|Theory\(Skip\s*=)`),
+	".php":  regexp.MustCompile(`\$this->markTestSkipped\(|\$this->markTestIncomplete\(`),
+}
+
+var (
+	// jsSkipPattern は、Jest / Mocha / Vitest / Playwright のテストのスキップです。
+	jsSkipPattern = regexp.MustCompile(`\b(?:it|test|describe|context|suite)\.(?:skip|todo)\b|\b(?:xit|xtest|xdescribe)\(`)
+	// jvmSkipPattern は、JUnit のテストの無効化です。
+	jvmSkipPattern = regexp.MustCompile(`@(?:Disabled|Ignore)\b`)
+)
+
+// Scan は、差分の追加行・削除行から TODO / FIXME コメントとテストのスキップを検出します。
+// AI を使用しない決定的な検出のため、同じ差分に対しては常に同じ結果を返します。
+func Scan(diff string) Result {
+	result := Result{Removed: make(map[Kind]int), counts: make(map[Kind]int)}
+	for _, f := range diffutil.ParseFiles(diff) {
+		if f.Binary {
+			continue
+		}
+		ext := strings.ToLower(path.Ext(f.Path))
+		for _, line := range f.Added {
+			for _, kind := range match(ext, line.Text) {
+				result.Added = append(result.Added, Marker{Kind: kind, Path: f.Path, Line: line.Number, Text: strings.TrimSpace(line.Text)})
+				result.counts[kind]++
+			}
+		}
+		for _, line := range f.Removed {
+			for _, kind := range match(ext, line) {
+				result.Removed[kind]++
+			}
+		}
+	}
+	if len(result.Removed) == 0 {
+		result.Removed = nil
+	}
+	return result
+}
+
+// match は、1行に含まれるマーカーの種類を返します。1行に同じ種類のマーカーが複数あっても1件として扱います。
+func match(ext, line string) []Kind {
+	var kinds []Kind
+	seen := make(map[string]bool)
+	for _, m := range commentPattern.FindAllString(line, -1) {
+		if !seen[m] {
+			seen[m] = true
+			kinds = append(kinds, Kind(m))
+		}
+	}
+	if p, ok := skipPatterns[ext]; ok && p.MatchString(line) {
+		kinds = append(kinds, KindSkip)
+	}
+	return kinds
+}
@@ -0,0 +1,96 @@
+package diffcache
+
+import (
+	"sync"
+	"time"
+)
+
+// Key は、差分を一意に識別するキーです。参照名ではなくコミットハッシュを使用するため、
+// ブランチが更新された後に古い差分を返すことはありません。
+type Key struct {
+	RepoURL string
+	BaseSHA string
+	HeadSHA string
+}
+
+// entry は、キャッシュされた1つの差分です。
+type entry struct {
+	diff     string
+	storedAt time.Time
+}
+
+// Cache は、同じプロセス内で実行される複数のレビュー・サブコマンドの間で、差分の取得結果とフェッチの実行時刻を共有するキャッシュです。
+// nil の *Cache は常にキャッシュミスとなり、何も記録しません。複数の goroutine から安全に使用できます。
+type Cache struct {
+	mu         sync.Mutex
+	maxEntries int
+	fetchReuse time.Duration
+	entries    map[Key]entry
+	fetchedAt  map[string]time.Time
+	now        func() time.Time
+}
+
+// New は、最大 maxEntries 件の差分を保持する Cache を返します。
+// fetchReuse は、同じリポジトリ (同じローカルパス) のフェッチ結果を再利用する期間です。0 の場合はフェッチを省略しません。
+func New(maxEntries int, fetchReuse time.Duration) *Cache {
+	return &Cache{
+		maxEntries: maxEntries,
+		fetchReuse: fetchReuse,
+		entries:    make(map[Key]entry),
+		fetchedAt:  make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+// Get は、キーに対応するキャッシュ済みの差分を返します。
+func (c *Cache) Get(key Key) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	return e.diff, ok
+}
+
+// Put は、差分をキャッシュに記録します。上限を超える場合は、最も古い差分を破棄します。
+func (c *Cache) Put(key Key, diff string) {
+	if c == nil || c.maxEntries <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		var oldest Key
+		var oldestAt time.Time
+		for k, e := range c.entries {
+			if oldestAt.IsZero() || e.storedAt.Before(oldestAt) {
+				oldest, oldestAt = k, e.storedAt
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = entry{diff: diff, storedAt: c.now()}
+}
+
+// Fetched は、リポジトリ (repoURL) のローカルパス (localPath) へのフェッチが、再利用期間内に実行済みかを返します。
+// ローカルパスを持たないリポジトリ (インメモリモード) は、クローンごとに独立しているため常に false を返します。
+func (c *Cache) Fetched(repoURL, localPath string) bool {
+	if c == nil || c.fetchReuse <= 0 || localPath == "" {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	at, ok := c.fetchedAt[repoURL+"\x00"+localPath]
+	return ok && c.now().Sub(at) < c.fetchReuse
+}
+
+// MarkFetched は、リポジトリのローカルパスへのフェッチを実行したことを記録します。
+func (c *Cache) MarkFetched(repoURL, localPath string) {
+	if c == nil || localPath == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetchedAt[repoURL+"\x00"+localPath] = c.now()
+}
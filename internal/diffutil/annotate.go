@@ -0,0 +1,136 @@
+package diffutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnnotationPrefix は、注釈付き差分で注釈の行に付けるプレフィックスです。
+// unified diff の行 (" ", "+", "-", "@@" など) と区別でき、パッチとして適用する際は除去しやすい形式です。
+const AnnotationPrefix = "#> "
+
+// Annotation は、差分の特定の位置に埋め込む注釈です。
+type Annotation struct {
+	Path string // 変更後のファイルパス (空の場合は差分全体への注釈)
+	Line int    // 変更後のファイルの行番号 (0 の場合はファイル全体への注釈)
+	Text string
+}
+
+// Annotate は、unified diff の該当する位置に注釈を埋め込んだテキストを返します。
+// 行番号が差分に含まれる行 (追加行・コンテキスト行) と一致する注釈はその行の直後に、
+// ファイルは差分にあるが行が差分に含まれない注釈はファイルのヘッダの直後に、
+// それ以外の注釈は差分の先頭にまとめて出力します。
+func Annotate(diff string, annotations []Annotation) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+
+	// 差分に含まれるファイルと行の位置を先に求め、その位置にない注釈をファイル単位・差分全体の注釈に振り分ける
+	type position struct {
+		path string
+		line int
+	}
+	headers := make(map[int]string)
+	lineAt := make(map[int]position)
+	inDiff := make(map[string]bool)
+	positions := make(map[position]bool)
+	walkDiff(lines,
+		func(i int, path string) {
+			headers[i] = path
+			inDiff[path] = true
+		},
+		func(i int, path string, line int) {
+			lineAt[i] = position{path, line}
+			positions[position{path, line}] = true
+		},
+	)
+
+	var general []string
+	byFile := make(map[string][]string)
+	byLine := make(map[position][]string)
+	for _, a := range annotations {
+		pos := position{a.Path, a.Line}
+		switch {
+		case !inDiff[a.Path]:
+			general = append(general, describe(a))
+		case a.Line > 0 && positions[pos]:
+			byLine[pos] = append(byLine[pos], a.Text)
+		default:
+			byFile[a.Path] = append(byFile[a.Path], describe(a))
+		}
+	}
+
+	var b strings.Builder
+	writeNotes := func(notes []string) {
+		for _, n := range notes {
+			for _, l := range strings.Split(n, "\n") {
+				b.WriteString(strings.TrimRight(AnnotationPrefix+l, " "))
+				b.WriteByte('\n')
+			}
+		}
+	}
+
+	writeNotes(general)
+	for i, l := range lines {
+		b.WriteString(l)
+		b.WriteByte('\n')
+		if path, ok := headers[i]; ok {
+			writeNotes(byFile[path])
+			delete(byFile, path)
+		}
+		if pos, ok := lineAt[i]; ok {
+			writeNotes(byLine[pos])
+			delete(byLine, pos)
+		}
+	}
+	return b.String()
+}
+
+// describe は、差分の行に結び付けられない注釈を、位置の情報を含めたテキストにします。
+func describe(a Annotation) string {
+	switch {
+	case a.Path != "" && a.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", a.Path, a.Line, a.Text)
+	case a.Path != "":
+		return fmt.Sprintf("%s: %s", a.Path, a.Text)
+	default:
+		return a.Text
+	}
+}
+
+// walkDiff は、差分の各ファイルのヘッダ ("+++" 行) について onHeader を、追加行・コンテキスト行について onLine を呼び出します。
+// i は lines 内の位置、path は変更後のファイルパス、line は変更後のファイルの行番号です。削除されたファイルは対象外です。
+func walkDiff(lines []string, onHeader func(i int, path string), onLine func(i int, path string, line int)) {
+	var path string
+	inHunk := false
+	newLine := 0
+	for i, l := range lines {
+		switch {
+		case strings.HasPrefix(l, "diff --git "):
+			inHunk = false
+			path = ""
+		case strings.HasPrefix(l, "@@"):
+			inHunk = true
+			newLine, _ = parseHunkHeader(l)
+		case !inHunk:
+			if p, ok := newPathOf(l); ok {
+				path = p
+				onHeader(i, path)
+			}
+		case path == "":
+		case strings.HasPrefix(l, "+"), strings.HasPrefix(l, " "):
+			onLine(i, path, newLine)
+			newLine++
+		}
+	}
+}
+
+// newPathOf は、"+++ b/path" 形式のヘッダ行から変更後のパスを返します。削除の場合は変更前のパスは分からないため false を返します。
+func newPathOf(line string) (string, bool) {
+	if !strings.HasPrefix(line, "+++ ") {
+		return "", false
+	}
+	p := trimPrefixPath(strings.TrimPrefix(line, "+++ "), "b/")
+	if p == devNull {
+		return "", false
+	}
+	return p, true
+}
@@ -0,0 +1,91 @@
+package diffutil
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// devNull は、ファイルの追加・削除時に unified diff のヘッダで使われるパスです。
+const devNull = "/dev/null"
+
+// ChangedFiles は、unified diff (git diff の出力) から変更後のファイルパスを出現順に重複なく返します。
+// 削除されたファイルは変更前のパスで返します。
+func ChangedFiles(diff string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(p string) {
+		if p == "" || p == devNull || seen[p] {
+			return
+		}
+		seen[p] = true
+		files = append(files, p)
+	}
+
+	var oldPath string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			oldPath = trimPrefixPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			newPath := trimPrefixPath(strings.TrimPrefix(line, "+++ "), "b/")
+			if newPath == devNull {
+				add(oldPath)
+			} else {
+				add(newPath)
+			}
+			oldPath = ""
+		case strings.HasPrefix(line, "Binary files "):
+			if binOld, binNew, ok := parseBinaryLine(line); ok {
+				if binNew == devNull {
+					add(binOld)
+				} else {
+					add(binNew)
+				}
+			}
+		}
+	}
+	return files
+}
+
+// Extensions は、ファイルパスの一覧から拡張子 (小文字、ドット付き) を出現順に重複なく返します。
+func Extensions(files []string) []string {
+	seen := make(map[string]bool)
+	var exts []string
+	for _, f := range files {
+		ext := strings.ToLower(path.Ext(f))
+		if ext == "" || seen[ext] {
+			continue
+		}
+		seen[ext] = true
+		exts = append(exts, ext)
+	}
+	return exts
+}
+
+// trimPrefixPath は、diff ヘッダのパスから "a/" "b/" プレフィックスとタイムスタンプ等の付加情報を除去します。
+func trimPrefixPath(p, prefix string) string {
+	// "--- a/path\t2024-01-01 ..." のようにタブ以降に付加情報が付く場合がある
+	if i := strings.IndexByte(p, '\t'); i >= 0 {
+		p = p[:i]
+	}
+	p = strings.TrimSpace(p)
+	if p == devNull {
+		return p
+	}
+	return strings.TrimPrefix(p, prefix)
+}
+
+// diffKey は、context にレポートに埋め込む差分を格納するためのキーです。
+type diffKey struct{}
+
+// NewContext は、公開するレポートに埋め込む差分を格納した context を返します。
+func NewContext(ctx context.Context, diff string) context.Context {
+	return context.WithValue(ctx, diffKey{}, diff)
+}
+
+// FromContext は、NewContext で格納した差分を返します。格納されていない場合は false を返します。
+func FromContext(ctx context.Context) (string, bool) {
+	diff, ok := ctx.Value(diffKey{}).(string)
+	return diff, ok
+}
@@ -0,0 +1,59 @@
+package diffutil
+
+import "strings"
+
+// HunkAt は、差分のうち path のファイルのヘッダと、変更後の line 行目を含むハンクを返します。
+// line が 0 の場合や、line を含むハンクがない場合は、そのファイルの差分全体を返します。ファイルが差分にない場合は空文字を返します。
+func HunkAt(diff, path string, line int) string {
+	for _, f := range ParseFiles(diff) {
+		if f.Path != path {
+			continue
+		}
+		if line <= 0 {
+			return f.Raw
+		}
+
+		lines := strings.Split(f.Raw, "\n")
+		var header []string
+		for i, l := range lines {
+			if strings.HasPrefix(l, "@@") {
+				header = lines[:i]
+				break
+			}
+		}
+		for i := len(header); i < len(lines); {
+			start, _ := parseHunkHeader(lines[i])
+			end := i + 1
+			newLine := start
+			contains := false
+			for ; end < len(lines) && !strings.HasPrefix(lines[end], "@@"); end++ {
+				if strings.HasPrefix(lines[end], "+") || strings.HasPrefix(lines[end], " ") {
+					contains = contains || newLine == line
+					newLine++
+				}
+			}
+			if contains {
+				return strings.Join(append(append([]string(nil), header...), lines[i:end]...), "\n")
+			}
+			i = end
+		}
+		return f.Raw
+	}
+	return ""
+}
+
+// NewLines は、差分に含まれる変更後のファイルの行 (追加行・コンテキスト行) を、ファイルパスごとの行番号の集合として返します。
+// プルリクエストの差分の行に指摘事項を結び付けられるか (インラインコメントを投稿できるか) の判定に使用します。
+func NewLines(diff string) map[string]map[int]bool {
+	lines := make(map[string]map[int]bool)
+	walkDiff(strings.Split(diff, "\n"),
+		func(i int, path string) {},
+		func(i int, path string, line int) {
+			if lines[path] == nil {
+				lines[path] = make(map[int]bool)
+			}
+			lines[path][line] = true
+		},
+	)
+	return lines
+}
@@ -0,0 +1,148 @@
+package diffutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Line は、差分内の1行と、その行の変更後ファイルにおける行番号 (1始まり) です。
+type Line struct {
+	Number int
+	Text   string
+}
+
+// FileDiff は、unified diff の1ファイル分の情報です。
+type FileDiff struct {
+	Path         string   // 変更後のパス (削除の場合は変更前のパス)
+	OldPath      string   // 変更前のパス (追加の場合は空)
+	Deleted      bool     // ファイルが削除された場合 true
+	Created      bool     // ファイルが追加された場合 true
+	Binary       bool     // バイナリファイルの場合 true (差分の内容は含まれない)
+	Added        []Line   // 追加された行
+	Removed      []string // 削除された行
+	HunkContexts []string // ハンクヘッダ (@@ ... @@) の後ろに付く関数コンテキスト
+	Raw          string   // このファイル部分の差分テキスト
+}
+
+// ParseFiles は、unified diff をファイル単位に分割して解析します。
+func ParseFiles(diff string) []FileDiff {
+	var files []FileDiff
+	var cur *FileDiff
+	var raw strings.Builder
+	var oldPath string
+	newLine := 0
+
+	flush := func() {
+		if cur != nil {
+			cur.Raw = strings.TrimRight(raw.String(), "\n")
+			files = append(files, *cur)
+		}
+		cur = nil
+		raw.Reset()
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+			cur = &FileDiff{}
+			oldPath = ""
+			newLine = 0
+		}
+		if cur == nil {
+			// "diff --git" 行を持たない形式の diff にも対応する
+			if !strings.HasPrefix(line, "--- ") {
+				continue
+			}
+			cur = &FileDiff{}
+		}
+		raw.WriteString(line)
+		raw.WriteByte('\n')
+
+		switch {
+		case strings.HasPrefix(line, "--- ") && cur.Path == "":
+			oldPath = trimPrefixPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ ") && cur.Path == "":
+			newPath := trimPrefixPath(strings.TrimPrefix(line, "+++ "), "b/")
+			if newPath == devNull {
+				cur.Path = oldPath
+				cur.Deleted = true
+			} else {
+				cur.Path = newPath
+				cur.Created = oldPath == devNull
+			}
+			if !cur.Created {
+				cur.OldPath = oldPath
+			}
+		case strings.HasPrefix(line, "Binary files ") && cur.Path == "":
+			// バイナリファイルは "---" "+++" 行を持たず、"Binary files a/x and b/x differ" のみが出力される
+			if binOld, binNew, ok := parseBinaryLine(line); ok {
+				cur.Binary = true
+				cur.Path, cur.Deleted, cur.Created = binNew, binNew == devNull, binOld == devNull
+				if cur.Deleted {
+					cur.Path = binOld
+				}
+				if !cur.Created {
+					cur.OldPath = binOld
+				}
+			}
+		case strings.HasPrefix(line, "@@"):
+			start, context := parseHunkHeader(line)
+			newLine = start
+			if context != "" {
+				cur.HunkContexts = append(cur.HunkContexts, context)
+			}
+		case strings.HasPrefix(line, "+"):
+			cur.Added = append(cur.Added, Line{Number: newLine, Text: line[1:]})
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			cur.Removed = append(cur.Removed, line[1:])
+		case strings.HasPrefix(line, " "):
+			newLine++
+		}
+	}
+	flush()
+
+	return files
+}
+
+// parseBinaryLine は、"Binary files a/x and b/x differ" 形式の行から変更前後のパスを取り出します。
+// 追加・削除の場合、該当する側は "/dev/null" になります。
+func parseBinaryLine(line string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(line, "Binary files ")
+	if !ok {
+		return "", "", false
+	}
+	rest, ok = strings.CutSuffix(rest, " differ")
+	if !ok {
+		return "", "", false
+	}
+	i := strings.LastIndex(rest, " and ")
+	if i < 0 {
+		return "", "", false
+	}
+	return trimPrefixPath(rest[:i], "a/"), trimPrefixPath(rest[i+len(" and "):], "b/"), true
+}
+
+// parseHunkHeader は、"@@ -a,b +c,d @@ context" 形式のヘッダから変更後の開始行と関数コンテキストを取り出します。
+func parseHunkHeader(header string) (int, string) {
+	rest := strings.TrimPrefix(header, "@@")
+	end := strings.Index(rest, "@@")
+	if end < 0 {
+		return 0, ""
+	}
+	ranges := strings.Fields(rest[:end])
+	context := strings.TrimSpace(rest[end+2:])
+
+	for _, r := range ranges {
+		if !strings.HasPrefix(r, "+") {
+			continue
+		}
+		startStr, _, _ := strings.Cut(strings.TrimPrefix(r, "+"), ",")
+		start, err := strconv.Atoi(startStr)
+		if err != nil {
+			return 0, context
+		}
+		return start, context
+	}
+	return 0, context
+}
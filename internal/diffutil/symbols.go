@@ -0,0 +1,125 @@
+package diffutil
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Symbol は、差分によって追加・変更された公開関数/メソッドです。
+type Symbol struct {
+	Name          string // 関数/メソッド名 (メソッドの場合は Receiver.Name)
+	File          string // 定義されているファイル
+	Line          int    // 変更後ファイルでの行番号 (ハンクコンテキストから検出した場合は0)
+	HasTestChange bool   // 差分内のテストファイルがこのシンボルに言及している場合 true
+}
+
+// symbolPattern は、言語ごとの公開関数/メソッド定義の検出パターンです。
+// 最後のキャプチャグループがシンボル名、レシーバを持つ場合はその前のグループがレシーバ型です。
+type symbolPattern struct {
+	exts []string
+	re   *regexp.Regexp
+}
+
+var symbolPatterns = []symbolPattern{
+	// Go: func Name( / func (r *Recv) Name(
+	{exts: []string{".go"}, re: regexp.MustCompile(`^func\s+(?:\(\s*\w*\s*\*?\s*(\w+)(?:\[[^\]]*\])?\s*\)\s*)?([A-Z]\w*)\s*[\[(]`)},
+	// Python: def name( (アンダースコアで始まるものは非公開)
+	{exts: []string{".py"}, re: regexp.MustCompile(`^\s*(?:async\s+)?def\s+()([A-Za-z]\w*)\s*\(`)},
+	// JavaScript/TypeScript: export function name / export const name = / export class Name
+	{exts: []string{".js", ".jsx", ".ts", ".tsx", ".mjs"}, re: regexp.MustCompile(`^\s*export\s+(?:default\s+)?(?:async\s+)?(?:function\*?|const|let|class)\s+()([A-Za-z_$][\w$]*)`)},
+	// Java/Kotlin: public ... name(
+	{exts: []string{".java"}, re: regexp.MustCompile(`^\s*public\s+(?:static\s+)?(?:final\s+)?(?:synchronized\s+)?(?:<[^>]+>\s+)?[\w<>\[\],.? ]+\s+()(\w+)\s*\(`)},
+	{exts: []string{".kt"}, re: regexp.MustCompile(`^\s*(?:public\s+)?(?:suspend\s+)?fun\s+(?:<[^>]+>\s+)?(?:(\w+)\.)?(\w+)\s*\(`)},
+}
+
+// IsTestFile は、パスが一般的な命名規則に従うテストファイルかを判定します。
+func IsTestFile(p string) bool {
+	base := path.Base(p)
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasSuffix(base, "_test.py"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."),
+		strings.HasSuffix(base, "Test.java"),
+		strings.HasSuffix(base, "Test.kt"):
+		return true
+	}
+	return strings.Contains(p, "/__tests__/") || strings.HasPrefix(p, "src/test/") || strings.Contains(p, "/src/test/")
+}
+
+// ChangedSymbols は、テスト以外のファイルで追加・変更された公開関数/メソッドを抽出し、
+// 同じ差分内のテストファイルでそれらが言及されているかを判定します。
+func ChangedSymbols(files []FileDiff) []Symbol {
+	var testText strings.Builder
+	for _, f := range files {
+		if !IsTestFile(f.Path) {
+			continue
+		}
+		for _, l := range f.Added {
+			testText.WriteString(l.Text)
+			testText.WriteByte('\n')
+		}
+		for _, l := range f.Removed {
+			testText.WriteString(l)
+			testText.WriteByte('\n')
+		}
+	}
+	tests := testText.String()
+
+	seen := make(map[string]bool)
+	var symbols []Symbol
+	add := func(file string, line int, recv, name string) {
+		full := name
+		if recv != "" {
+			full = recv + "." + name
+		}
+		key := file + "#" + full
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		symbols = append(symbols, Symbol{
+			Name:          full,
+			File:          file,
+			Line:          line,
+			HasTestChange: strings.Contains(tests, name),
+		})
+	}
+
+	for _, f := range files {
+		if f.Deleted || IsTestFile(f.Path) {
+			continue
+		}
+		pattern, ok := patternFor(f.Path)
+		if !ok {
+			continue
+		}
+		for _, l := range f.Added {
+			if m := pattern.FindStringSubmatch(l.Text); m != nil {
+				add(f.Path, l.Number, m[len(m)-2], m[len(m)-1])
+			}
+		}
+		// 関数本体のみが変更された場合は、ハンクヘッダの関数コンテキストから検出する
+		for _, c := range f.HunkContexts {
+			if m := pattern.FindStringSubmatch(c); m != nil {
+				add(f.Path, 0, m[len(m)-2], m[len(m)-1])
+			}
+		}
+	}
+	return symbols
+}
+
+// patternFor は、ファイルの拡張子に対応する検出パターンを返します。
+func patternFor(p string) (*regexp.Regexp, bool) {
+	ext := strings.ToLower(path.Ext(p))
+	for _, sp := range symbolPatterns {
+		for _, e := range sp.exts {
+			if e == ext {
+				return sp.re, true
+			}
+		}
+	}
+	return nil, false
+}
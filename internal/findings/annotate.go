@@ -0,0 +1,30 @@
+package findings
+
+import (
+	"fmt"
+
+	"git-gemini-cli/internal/diffutil"
+)
+
+// AnnotateDiff は、レビュー対象の差分の該当箇所に、指摘事項を "#> [HIGH] タイトル" 形式の行として埋め込んだテキストを返します。
+// 位置を特定できない指摘事項は、差分の先頭 (またはファイルのヘッダの直後) にまとめて出力します。
+func AnnotateDiff(diff string, list []Finding) string {
+	annotations := make([]diffutil.Annotation, 0, len(list)+1)
+	annotations = append(annotations, diffutil.Annotation{Text: summary(list)})
+	for _, f := range list {
+		annotations = append(annotations, diffutil.Annotation{
+			Path: f.File,
+			Line: f.Line,
+			Text: fmt.Sprintf("[%s] %s", f.Severity, f.Title),
+		})
+	}
+	return diffutil.Annotate(diff, annotations)
+}
+
+// summary は、注釈付き差分の先頭に出力する指摘事項の件数の要約を返します。
+func summary(list []Finding) string {
+	if len(list) == 0 {
+		return "Gemini AI レビュー: 指摘事項はありません"
+	}
+	return fmt.Sprintf("Gemini AI レビュー: 指摘事項 %d 件", len(list))
+}
@@ -0,0 +1,125 @@
+package findings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Category は、指摘事項の分類です。
+type Category string
+
+const (
+	// CategoryBug は、不具合・誤った動作の指摘です。
+	CategoryBug Category = "bug"
+	// CategoryStyle は、可読性・命名・書式などの指摘です。
+	CategoryStyle Category = "style"
+	// CategorySecurity は、セキュリティ上の問題の指摘です。
+	CategorySecurity Category = "security"
+	// CategoryPerf は、パフォーマンス上の問題の指摘です。
+	CategoryPerf Category = "perf"
+	// CategoryDocs は、コメント・ドキュメントの不足や誤りの指摘です。
+	CategoryDocs Category = "docs"
+)
+
+// Categories は、レポートやSlackのメッセージに件数を表示する順の分類の一覧です。
+var Categories = []Category{CategoryBug, CategorySecurity, CategoryPerf, CategoryStyle, CategoryDocs}
+
+// categoryAliases は、AI が出力しがちな分類の別名と Category の対応表です。
+var categoryAliases = map[string]Category{
+	"bug":           CategoryBug,
+	"bugs":          CategoryBug,
+	"correctness":   CategoryBug,
+	"style":         CategoryStyle,
+	"readability":   CategoryStyle,
+	"security":      CategorySecurity,
+	"sec":           CategorySecurity,
+	"perf":          CategoryPerf,
+	"performance":   CategoryPerf,
+	"docs":          CategoryDocs,
+	"doc":           CategoryDocs,
+	"documentation": CategoryDocs,
+}
+
+// ParseCategory は、分類の表記 (大文字小文字を区別しない、"performance" などの別名を含む) を Category に変換します。
+// 未知の表記の場合は空の Category (未分類) を返します。
+func ParseCategory(s string) Category {
+	return categoryAliases[strings.ToLower(strings.TrimSpace(s))]
+}
+
+// Label は、分類の表示名を返します。未分類の場合は "未分類" を返します。
+func (c Category) Label() string {
+	if c == "" {
+		return "未分類"
+	}
+	return string(c)
+}
+
+// UnmarshalJSON は、分類の表記を正規化して Category を復元します。未知の表記は未分類とします。
+func (c *Category) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*c = ParseCategory(name)
+	return nil
+}
+
+// CategoryCount は、1つの分類の指摘事項の件数です。
+type CategoryCount struct {
+	Category      Category
+	Count         int
+	AvgConfidence float64 // 確信度が付与された指摘事項の平均 (付与されたものがない場合は 0)
+}
+
+// CountByCategory は、指摘事項の分類ごとの件数を Categories の順 (未分類は最後) に返します。件数が 0 の分類は含めません。
+func CountByCategory(list []Finding) []CategoryCount {
+	counts := make(map[Category]int)
+	sums := make(map[Category]float64)
+	rated := make(map[Category]int)
+	for _, f := range list {
+		counts[f.Category]++
+		if f.Confidence > 0 {
+			sums[f.Category] += f.Confidence
+			rated[f.Category]++
+		}
+	}
+
+	var result []CategoryCount
+	for _, c := range append(slices.Clone(Categories), "") {
+		if counts[c] == 0 {
+			continue
+		}
+		cc := CategoryCount{Category: c, Count: counts[c]}
+		if rated[c] > 0 {
+			cc.AvgConfidence = sums[c] / float64(rated[c])
+		}
+		result = append(result, cc)
+	}
+	return result
+}
+
+// FormatCategoryCounts は、分類ごとの件数を "bug: 2, security: 1" 形式で返します。指摘事項がない場合は空文字を返します。
+func FormatCategoryCounts(list []Finding) string {
+	var parts []string
+	for _, cc := range CountByCategory(list) {
+		parts = append(parts, fmt.Sprintf("%s: %d", cc.Category.Label(), cc.Count))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// findingsKey は、context に公開するレポートの指摘事項を格納するためのキーです。
+type findingsKey struct{}
+
+// NewContext は、公開するレポートの指摘事項を格納した context を返します。Slack 通知などで件数の表示に使用します。
+func NewContext(ctx context.Context, list []Finding) context.Context {
+	return context.WithValue(ctx, findingsKey{}, list)
+}
+
+// FromContext は、NewContext で格納した指摘事項を返します。格納されていない場合は false を返します。
+func FromContext(ctx context.Context) ([]Finding, bool) {
+	list, ok := ctx.Value(findingsKey{}).([]Finding)
+	return list, ok
+}
@@ -0,0 +1,62 @@
+package findings
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// codeQualityCheckName は、Code Quality レポートの問題の check_name の接頭辞です。分類がある場合は "/<分類>" を付けます。
+const codeQualityCheckName = "gemini-review"
+
+// codeQualitySeverities は、深刻度と GitLab の Code Quality レポートの severity の対応表です。
+var codeQualitySeverities = map[Severity]string{
+	SeverityCritical: "critical",
+	SeverityHigh:     "major",
+	SeverityMedium:   "minor",
+	SeverityLow:      "info",
+}
+
+// codeQualityIssue は、GitLab の Code Quality レポート (gl-code-quality-report.json) の1件の問題です。
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+// codeQualityLocation は、Code Quality レポートの問題の位置です。
+type codeQualityLocation struct {
+	Path  string `json:"path"`
+	Lines struct {
+		Begin int `json:"begin"`
+	} `json:"lines"`
+}
+
+// CodeQualityReport は、指摘事項を GitLab の Code Quality レポートの JSON (GitLab CI の artifacts:reports:codequality に指定するもの) に変換します。
+// GitLab は位置を必須とするため、ファイルを特定できない指摘事項は含めません。行番号が不明な場合はファイルの先頭 (1 行目) とします。
+// 指紋には Fingerprint を使用するため、行番号がずれても GitLab 上で同じ問題として扱われます。
+func CodeQualityReport(list []Finding) ([]byte, error) {
+	issues := make([]codeQualityIssue, 0, len(list))
+	for _, f := range list {
+		if f.File == "" {
+			continue
+		}
+		issue := codeQualityIssue{
+			Description: fmt.Sprintf("[%s] %s", f.Severity, f.Title),
+			CheckName:   codeQualityCheckName,
+			Fingerprint: Fingerprint(f),
+			Severity:    codeQualitySeverities[f.Severity],
+			Location:    codeQualityLocation{Path: f.File},
+		}
+		if f.Category != "" {
+			issue.CheckName += "/" + string(f.Category)
+		}
+		if issue.Severity == "" {
+			issue.Severity = "info"
+		}
+		issue.Location.Lines.Begin = max(f.Line, 1)
+		issues = append(issues, issue)
+	}
+	return json.MarshalIndent(issues, "", "  ")
+}
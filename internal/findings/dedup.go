@@ -0,0 +1,114 @@
+package findings
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// dedupLineDistance は、同じ指摘事項とみなす行番号の差の上限です。
+	dedupLineDistance = 5
+	// dedupSimilarity は、同じ指摘事項とみなすタイトルの類似度 (文字 bigram の Dice 係数) の下限です。
+	dedupSimilarity = 0.6
+)
+
+// Duplicate は、重複として取り除いた指摘事項と、統合先の指摘事項です。
+type Duplicate struct {
+	Removed Finding
+	Kept    Finding
+}
+
+// Dedup は、同じファイル・近い行 (行番号の差が5行以内、または一方の行番号が不明) で、
+// タイトルが類似する指摘事項を、先に現れたものに統合します。統合先の深刻度と確信度は、重複したもののうち最も高い値にします。
+// 分割レビューや複数モードのレビューで、同じ問題が別々に指摘された場合に使用します。
+func Dedup(list []Finding) ([]Finding, []Duplicate) {
+	var kept []Finding
+	var dups []Duplicate
+	for _, f := range list {
+		i := indexOfDuplicate(kept, f)
+		if i < 0 {
+			kept = append(kept, f)
+			continue
+		}
+		dups = append(dups, Duplicate{Removed: f, Kept: kept[i]})
+		kept[i].Severity = max(kept[i].Severity, f.Severity)
+		kept[i].Confidence = max(kept[i].Confidence, f.Confidence)
+		if kept[i].Category == "" {
+			kept[i].Category = f.Category
+		}
+	}
+	return kept, dups
+}
+
+// indexOfDuplicate は、f と重複する指摘事項の list 内の位置を返します。重複がない場合は -1 を返します。
+func indexOfDuplicate(list []Finding, f Finding) int {
+	for i, k := range list {
+		if k.File != f.File {
+			continue
+		}
+		if k.Line > 0 && f.Line > 0 && abs(k.Line-f.Line) > dedupLineDistance {
+			continue
+		}
+		if similarity(k.Title, f.Title) >= dedupSimilarity {
+			return i
+		}
+	}
+	return -1
+}
+
+// similarity は、正規化したタイトルの文字 bigram の Dice 係数 (0〜1) を返します。
+// 日本語のタイトルは単語に分割できないため、単語ではなく文字の並びで比較します。
+func similarity(a, b string) float64 {
+	na, nb := normalizeTitle(a), normalizeTitle(b)
+	if na == nb {
+		return 1
+	}
+	ba, bb := bigrams(na), bigrams(nb)
+	if len(ba) == 0 || len(bb) == 0 {
+		return 0
+	}
+
+	counts := make(map[string]int, len(ba))
+	for _, g := range ba {
+		counts[g]++
+	}
+	common := 0
+	for _, g := range bb {
+		if counts[g] > 0 {
+			counts[g]--
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(ba)+len(bb))
+}
+
+// normalizeTitle は、比較のためにタイトルを小文字にし、空白・句読点・記号を取り除きます。
+func normalizeTitle(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// bigrams は、文字列の連続する2文字の組を返します。1文字の場合はその文字のみを返します。
+func bigrams(s string) []string {
+	runes := []rune(s)
+	if len(runes) == 1 {
+		return []string{s}
+	}
+	grams := make([]string, 0, len(runes)-1)
+	for i := 0; i+1 < len(runes); i++ {
+		grams = append(grams, string(runes[i:i+2]))
+	}
+	return grams
+}
+
+// abs は、整数の絶対値を返します。
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
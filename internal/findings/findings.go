@@ -0,0 +1,227 @@
+package findings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Severity は、指摘事項の深刻度です。値が大きいほど深刻です。
+type Severity int
+
+const (
+	SeverityUnknown Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// severityNames は、深刻度の表記と値の対応表です。
+var severityNames = map[string]Severity{
+	"low":      SeverityLow,
+	"medium":   SeverityMedium,
+	"high":     SeverityHigh,
+	"critical": SeverityCritical,
+}
+
+// ParseSeverity は、"critical" / "high" / "medium" / "low" (大文字小文字を区別しない) を Severity に変換します。
+func ParseSeverity(s string) (Severity, error) {
+	if sev, ok := severityNames[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return sev, nil
+	}
+	return SeverityUnknown, fmt.Errorf("深刻度 '%s' は不正です (critical, high, medium, low のいずれかを指定してください)", s)
+}
+
+// String は、深刻度を大文字の表記 (CRITICAL など) で返します。
+func (s Severity) String() string {
+	for name, sev := range severityNames {
+		if sev == s {
+			return strings.ToUpper(name)
+		}
+	}
+	return "UNKNOWN"
+}
+
+// UnmarshalJSON は、"HIGH" のような文字列表記から Severity を復元します。未知の表記は SeverityUnknown とします。
+func (s *Severity) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	*s, _ = ParseSeverity(name)
+	return nil
+}
+
+// MarshalJSON は、Severity を大文字の文字列表記で出力します。
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// Finding は、レビュー結果に含まれる1件の指摘事項です。
+type Finding struct {
+	Severity   Severity `json:"severity"`
+	Title      string   `json:"title"`
+	File       string   `json:"file,omitempty"`
+	Line       int      `json:"line,omitempty"`
+	Category   Category `json:"category,omitempty"`
+	Confidence float64  `json:"confidence,omitempty"` // 指摘が妥当である確信度 (0〜1、0 は未指定)
+}
+
+// BlockStart は、構造化された指摘事項ブロックの開始を示すマーカーです。
+// ブロックは HTML コメントとして出力させるため、Markdown/HTML として表示しても見えません。
+const BlockStart = "<!-- gemini-review:findings"
+
+var (
+	// blockPattern は、構造化された指摘事項ブロック (JSON 配列) を抽出する正規表現です。
+	blockPattern = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(BlockStart) + `\s*(.*?)\s*-->`)
+	// headingPattern は、構造化ブロックがない場合に "### [HIGH] タイトル" 形式の見出しから指摘事項を抽出する正規表現です。
+	headingPattern = regexp.MustCompile(`(?mi)^#{1,6}\s*\[(critical|high|medium|low)\]\s*(.+?)\s*$`)
+)
+
+// Split は、レビュー結果から構造化された指摘事項ブロックを取り除いたレポート本文と、指摘事項の一覧を返します。
+// 複数モードの結果のように複数のブロックが含まれる場合は、すべてのブロックの指摘事項を結合します。
+// ブロックが存在しない場合は、"### [HIGH] タイトル" 形式の見出しから指摘事項を抽出します。
+func Split(result string) (string, []Finding) {
+	matches := blockPattern.FindAllStringSubmatch(result, -1)
+	if len(matches) == 0 {
+		return result, fromHeadings(result)
+	}
+
+	var list []Finding
+	for _, m := range matches {
+		var block []Finding
+		if err := json.Unmarshal([]byte(m[1]), &block); err != nil {
+			slog.Warn("構造化された指摘事項ブロックの解析に失敗したため、このブロックは無視します。", "error", err)
+			continue
+		}
+		for i := range block {
+			block[i].Confidence = min(max(block[i].Confidence, 0), 1)
+		}
+		list = append(list, block...)
+	}
+
+	report := strings.TrimSpace(blockPattern.ReplaceAllString(result, ""))
+	return report, list
+}
+
+// fromHeadings は、Markdown の見出しに付けられた深刻度タグから指摘事項を抽出します。
+func fromHeadings(result string) []Finding {
+	var list []Finding
+	for _, m := range headingPattern.FindAllStringSubmatch(result, -1) {
+		sev, _ := ParseSeverity(m[1])
+		list = append(list, Finding{Severity: sev, Title: m[2]})
+	}
+	return list
+}
+
+// ErrThresholdExceeded は、しきい値以上の深刻度の指摘事項が存在することを示すエラーです。
+var ErrThresholdExceeded = errors.New("しきい値以上の深刻度の指摘事項が見つかりました")
+
+// CheckThreshold は、threshold 以上の深刻度の指摘事項が含まれる場合に ErrThresholdExceeded をラップしたエラーを返します。
+func CheckThreshold(list []Finding, threshold Severity) error {
+	counts := make(map[Severity]int)
+	total := 0
+	for _, f := range list {
+		if f.Severity >= threshold {
+			counts[f.Severity]++
+			total++
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var parts []string
+	for sev := SeverityCritical; sev >= threshold; sev-- {
+		if counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", sev, counts[sev]))
+		}
+	}
+	return fmt.Errorf("%w (しきい値: %s, %s)", ErrThresholdExceeded, threshold, strings.Join(parts, ", "))
+}
+
+// Block は、指摘事項の一覧を構造化された指摘事項ブロック (HTML コメント) として出力します。
+// AI 以外の解析で得た指摘事項をレビュー結果に加え、--fail-on の判定対象にする場合に使用します。
+func Block(list []Finding) string {
+	data, err := json.Marshal(list)
+	if err != nil {
+		// Finding は常に JSON に変換できる
+		data = []byte("[]")
+	}
+	return BlockStart + "\n" + string(data) + "\n-->"
+}
+
+// Section は、レポートのうち指摘事項の見出し ("### [HIGH] タイトル") で始まるセクションを返します。
+// セクションは、見出しから同じかより上位のレベルの次の見出しの直前までです。見出しが見つからない場合は空文字を返します。
+func Section(report string, f Finding) string {
+	lines := strings.Split(report, "\n")
+	start, end := sectionRange(lines, f)
+	if start < 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+}
+
+// RemoveSection は、レポートから指摘事項の見出しで始まるセクション (Section を参照) を取り除きます。
+// 同じ見出しのセクションが複数ある場合は、最初のセクションを取り除きます。見出しが見つからない場合は false を返します。
+func RemoveSection(report string, f Finding) (string, bool) {
+	lines := strings.Split(report, "\n")
+	start, end := sectionRange(lines, f)
+	return removeRange(report, lines, start, end)
+}
+
+// RemoveLastSection は、RemoveSection と同様にセクションを取り除きます。同じ見出しのセクションが複数ある場合は、最後のセクションを取り除きます。
+// 重複した指摘事項のうち、後に現れたものを取り除く場合に使用します。
+func RemoveLastSection(report string, f Finding) (string, bool) {
+	lines := strings.Split(report, "\n")
+	start, end := -1, 0
+	for offset := 0; offset < len(lines); {
+		s, e := sectionRange(lines[offset:], f)
+		if s < 0 {
+			break
+		}
+		start, end = offset+s, offset+e
+		offset += s + 1
+	}
+	return removeRange(report, lines, start, end)
+}
+
+// removeRange は、レポートの行の範囲 [start, end) を取り除きます。start が負の場合は false を返します。
+func removeRange(report string, lines []string, start, end int) (string, bool) {
+	if start < 0 {
+		return report, false
+	}
+	return strings.Join(append(lines[:start:start], lines[end:]...), "\n"), true
+}
+
+// sectionRange は、指摘事項の見出しで始まるセクションの行の範囲 [start, end) を返します。見出しが見つからない場合、start は -1 です。
+func sectionRange(lines []string, f Finding) (int, int) {
+	for i, l := range lines {
+		m := headingPattern.FindStringSubmatch(l)
+		if m == nil || !strings.EqualFold(m[1], f.Severity.String()) || m[2] != strings.TrimSpace(f.Title) {
+			continue
+		}
+		level := headingLevel(strings.TrimSpace(l))
+		for j := i + 1; j < len(lines); j++ {
+			if n := headingLevel(lines[j]); n > 0 && n <= level {
+				return i, j
+			}
+		}
+		return i, len(lines)
+	}
+	return -1, 0
+}
+
+// headingLevel は、Markdown の見出し行のレベル (# の数) を返します。見出しでない場合は 0 を返します。
+func headingLevel(line string) int {
+	rest := strings.TrimLeft(line, "#")
+	n := len(line) - len(rest)
+	if n > 6 || (rest != "" && rest[0] != ' ' && rest[0] != '[') {
+		return 0
+	}
+	return n
+}
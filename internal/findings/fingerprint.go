@@ -0,0 +1,28 @@
+package findings
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Fingerprint は、指摘事項を実行をまたいで識別するための指紋 (ファイルと正規化したタイトルの SHA-256) を返します。
+// 前後の変更で行番号がずれても同じ指摘事項とみなせるよう、行番号と深刻度は含めません。
+func Fingerprint(f Finding) string {
+	sum := sha256.Sum256([]byte(f.File + "\x00" + normalizeTitle(f.Title)))
+	return hex.EncodeToString(sum[:])
+}
+
+// SimilarTitle は、2つのタイトルが同じ問題を指していると判断できる程度に類似しているかを返します。
+// AI は実行ごとに言い回しを変えることがあるため、指紋が一致しない場合の照合に使用します。
+func SimilarTitle(a, b string) bool {
+	return similarity(a, b) >= dedupSimilarity
+}
+
+// SameIssue は、2つの指摘事項が同じファイルの同じ問題を指しているか (指紋が一致するか、タイトルが類似するか) を返します。
+// 行番号は、モデルや実行ごとに指す行が異なることがあるため比較しません。
+func SameIssue(a, b Finding) bool {
+	if a.File != b.File {
+		return false
+	}
+	return Fingerprint(a) == Fingerprint(b) || SimilarTitle(a.Title, b.Title)
+}
@@ -0,0 +1,43 @@
+package findings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// githubAnnotationTitle は、GitHub Actions の注釈に表示するタイトルです。
+const githubAnnotationTitle = "Gemini Review"
+
+// githubAnnotationDataEscaper と githubAnnotationPropertyEscaper は、GitHub Actions のワークフローコマンドのメッセージとプロパティの値をエスケープします。
+var (
+	githubAnnotationDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubAnnotationPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// GitHubAnnotations は、指摘事項を GitHub Actions のワークフローコマンド (::error file=...,line=...::メッセージ) の行に変換します。
+// GitHub Actions の実行中に標準出力に出力すると、プルリクエストの差分の該当行に注釈として表示されます。
+// 深刻度が CRITICAL / HIGH の場合は error、MEDIUM の場合は warning、それ以外は notice とします。
+// ファイルを特定できない指摘事項は、位置を付けずに (実行のサマリーにのみ表示される注釈として) 出力します。
+func GitHubAnnotations(list []Finding) string {
+	var b strings.Builder
+	for _, f := range list {
+		command := "notice"
+		switch {
+		case f.Severity >= SeverityHigh:
+			command = "error"
+		case f.Severity == SeverityMedium:
+			command = "warning"
+		}
+
+		props := []string{"title=" + githubAnnotationTitle}
+		if f.File != "" {
+			props = append(props, "file="+githubAnnotationPropertyEscaper.Replace(f.File))
+			if f.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", f.Line))
+			}
+		}
+		message := fmt.Sprintf("[%s] %s", f.Severity, f.Title)
+		fmt.Fprintf(&b, "::%s %s::%s\n", command, strings.Join(props, ","), githubAnnotationDataEscaper.Replace(message))
+	}
+	return b.String()
+}
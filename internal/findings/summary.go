@@ -0,0 +1,109 @@
+package findings
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxTopRisks は、サマリーに列挙する主なリスクの件数の上限です。
+const maxTopRisks = 3
+
+// SummaryHeading は、レポートの先頭に追加するサマリーのセクションの見出しです。
+const SummaryHeading = "## 📋 サマリー"
+
+// 判定の識別子 (Summary.Status) です。機械可読な出力 (レビューのマニフェストなど) に使用します。
+const (
+	StatusFixRequired = "fix_required" // 要修正 (CRITICAL / HIGH の指摘事項がある)
+	StatusNeedsReview = "needs_review" // 要確認 (MEDIUM の指摘事項がある)
+	StatusPass        = "pass"         // 問題なし
+)
+
+// Summary は、指摘事項から求めたレビュー結果の要約 (判定、主なリスク、件数) です。
+type Summary struct {
+	Verdict  string           // 判定 ("🔴 要修正" など)
+	Status   string           // 判定の識別子 (StatusFixRequired / StatusNeedsReview / StatusPass)
+	TopRisks []Finding        // 深刻度の高い順の主なリスク
+	Counts   map[Severity]int // 深刻度ごとの件数
+	Total    int
+	Findings []Finding
+}
+
+// Summarize は、指摘事項からレビュー結果の要約を求めます。
+// CRITICAL / HIGH があれば要修正、MEDIUM があれば要確認、それ以外は問題なしと判定します。
+func Summarize(list []Finding) Summary {
+	s := Summary{Counts: make(map[Severity]int), Total: len(list), Findings: list}
+	for _, f := range list {
+		s.Counts[f.Severity]++
+	}
+	switch {
+	case s.Counts[SeverityCritical]+s.Counts[SeverityHigh] > 0:
+		s.Verdict, s.Status = "🔴 要修正 (マージ前に対応が必要な指摘事項があります)", StatusFixRequired
+	case s.Counts[SeverityMedium] > 0:
+		s.Verdict, s.Status = "🟡 要確認 (対応を検討すべき指摘事項があります)", StatusNeedsReview
+	case s.Total > 0:
+		s.Verdict, s.Status = "🟢 問題なし (軽微な指摘事項のみです)", StatusPass
+	default:
+		s.Verdict, s.Status = "🟢 問題なし (指摘事項はありません)", StatusPass
+	}
+
+	risks := slices.Clone(list)
+	slices.SortStableFunc(risks, func(a, b Finding) int { return int(b.Severity) - int(a.Severity) })
+	for _, f := range risks {
+		if len(s.TopRisks) >= maxTopRisks || f.Severity < SeverityMedium {
+			break
+		}
+		s.TopRisks = append(s.TopRisks, f)
+	}
+	return s
+}
+
+// CountsText は、深刻度ごとの件数を "計 3 件 (HIGH: 2, LOW: 1)" 形式で返します。
+func (s Summary) CountsText() string {
+	var parts []string
+	for sev := SeverityCritical; sev >= SeverityLow; sev-- {
+		if s.Counts[sev] > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %d", sev, s.Counts[sev]))
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("計 %d 件", s.Total)
+	}
+	return fmt.Sprintf("計 %d 件 (%s)", s.Total, strings.Join(parts, ", "))
+}
+
+// Markdown は、要約をレポートの先頭に追加するサマリーのセクションとして出力します。
+func (s Summary) Markdown() string {
+	var b strings.Builder
+	b.WriteString(SummaryHeading + "\n\n")
+	fmt.Fprintf(&b, "- **判定:** %s\n", s.Verdict)
+	fmt.Fprintf(&b, "- **指摘事項:** %s\n", s.CountsText())
+	if categories := FormatCategoryCounts(s.Findings); s.Total > 0 && categories != "" {
+		fmt.Fprintf(&b, "- **分類:** %s\n", categories)
+	}
+	if len(s.TopRisks) > 0 {
+		b.WriteString("- **主なリスク:**\n")
+		for i, f := range s.TopRisks {
+			fmt.Fprintf(&b, "  %d. %s\n", i+1, f.Label())
+		}
+	}
+	return b.String()
+}
+
+// Label は、指摘事項を "[HIGH] タイトル (`path:line`)" 形式で返します。
+func (f Finding) Label() string {
+	label := fmt.Sprintf("[%s] %s", f.Severity, strings.TrimSpace(f.Title))
+	switch {
+	case f.File != "" && f.Line > 0:
+		label += fmt.Sprintf(" (`%s:%d`)", f.File, f.Line)
+	case f.File != "":
+		label += fmt.Sprintf(" (`%s`)", f.File)
+	}
+	return label
+}
+
+// HasBlock は、レビュー結果に構造化された指摘事項ブロックが含まれるかを返します。
+// ブロックが空の配列の場合も true を返すため、「指摘事項がない」と「指摘事項を出力させていない」を区別できます。
+func HasBlock(result string) bool {
+	return blockPattern.MatchString(result)
+}
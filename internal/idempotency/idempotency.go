@@ -0,0 +1,254 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/objectstore"
+)
+
+// markerSuffix は、公開先URIに付加して重複排除マーカーのURIを作る接尾辞です。
+const markerSuffix = ".publish.json"
+
+// ciRunEnvs は、CIの実行を識別する環境変数です。再実行 (リトライ) でも値が変わらず、
+// 新しい実行ほど大きな数値になるものを優先順に並べています。
+var ciRunEnvs = []string{
+	"GITHUB_RUN_ID",      // GitHub Actions
+	"CI_PIPELINE_ID",     // GitLab CI
+	"BUILDKITE_BUILD_ID", // Buildkite
+	"CIRCLE_WORKFLOW_ID", // CircleCI
+	"BUILD_ID",           // Cloud Build / Jenkins
+}
+
+// Run は、1回のパイプライン実行を識別する冪等キーと実行順序です。
+type Run struct {
+	Key      string // 同じ実行の再試行では同じ値になるキー
+	Sequence int64  // 実行の新旧を比較するための番号 (不明な場合は 0)
+}
+
+// NewRun は、公開処理の冪等キーを生成します。
+// explicitKey が指定されていればそれを使用し、未指定の場合はCIの実行IDと公開内容 (parts) から生成します。
+// CIの実行IDが取得できない場合は、実行ごとに一意なランダムキーとなり、重複排除は行われません。
+func NewRun(explicitKey string, parts ...string) Run {
+	runID, sequence := ciRun()
+
+	switch {
+	case explicitKey != "":
+		return Run{Key: explicitKey, Sequence: sequence}
+	case runID != "":
+		return Run{Key: hashKey(append([]string{runID}, parts...)...), Sequence: sequence}
+	default:
+		return Run{Key: randomKey()}
+	}
+}
+
+// ciRun は、CIの実行IDと、数値として解釈できる場合はその値を実行順序として返します。
+func ciRun() (string, int64) {
+	for _, name := range ciRunEnvs {
+		if v := strings.TrimSpace(os.Getenv(name)); v != "" {
+			seq, _ := strconv.ParseInt(v, 10, 64)
+			return name + "=" + v, seq
+		}
+	}
+	return "", 0
+}
+
+// hashKey は、要素を連結した SHA-256 ハッシュの先頭を冪等キーとして返します。
+func hashKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// randomKey は、ランダムな冪等キーを返します。
+func randomKey() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Marker は、公開先ごとに保存される重複排除マーカーの内容です。
+type Marker struct {
+	Key         string    `json:"key"`
+	Sequence    int64     `json:"sequence,omitempty"`
+	Published   bool      `json:"published"`
+	URI         string    `json:"uri,omitempty"`         // 実際に公開したURI (バージョン付きの場合は元のURIと異なる)
+	ContentHash string    `json:"contentHash,omitempty"` // 最後に公開したレポートの内容のハッシュ (別の実行に引き継ぎ、同じ内容の再公開を省略する)
+	Notified    bool      `json:"notified"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// MarkerURI は、公開先URIに対応する重複排除マーカーのURIを返します。
+func MarkerURI(storageURI string) string {
+	return storageURI + markerSuffix
+}
+
+// Guard は、重複排除マーカーを条件付き書き込みで更新し、
+// 再試行された実行による二重通知や、古い実行による新しいレポートの上書きを防ぎます。
+type Guard struct {
+	store   objectstore.Store
+	uri     string
+	run     Run
+	marker  Marker
+	version string
+	stale   bool
+}
+
+// Acquire は、マーカーを読み込み、この実行が公開先を使用することを記録します。
+// 既に同じキーの実行が公開済みの場合や、より新しい実行が公開済みの場合は、その状態を Guard に保持します。
+// 他の実行と同時に書き込みが競合した場合は、マーカーを読み直して一度だけ再試行します。
+func Acquire(ctx context.Context, store objectstore.Store, storageURI string, run Run) (*Guard, error) {
+	g := &Guard{store: store, uri: MarkerURI(storageURI), run: run}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err = g.load(ctx); err != nil {
+			return nil, err
+		}
+		if g.marker.Key == run.Key || g.stale {
+			return g, nil
+		}
+
+		next := Marker{Key: run.Key, Sequence: run.Sequence}
+		if g.marker.Published {
+			// 内容が同じレポートの再公開を省略できるよう、前回公開したレポートの内容のハッシュを引き継ぐ
+			next.URI, next.ContentHash = g.marker.URI, g.marker.ContentHash
+		}
+		err = g.save(ctx, next)
+		if !errors.Is(err, objectstore.ErrPreconditionFailed) {
+			break
+		}
+		slog.Debug("重複排除マーカーの更新が他の実行と競合したため、読み直します。", "uri", g.uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// load は、マーカーを読み込み、この実行が古いかどうかを判定します。
+func (g *Guard) load(ctx context.Context) error {
+	obj, err := g.store.Read(ctx, g.uri)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		g.marker, g.version, g.stale = Marker{}, "", false
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("重複排除マーカーの読み込みに失敗しました: %w", err)
+	}
+
+	var m Marker
+	if err := json.Unmarshal(obj.Data, &m); err != nil {
+		return fmt.Errorf("重複排除マーカー '%s' の解析に失敗しました: %w", g.uri, err)
+	}
+	g.marker, g.version = m, obj.Version
+	g.stale = m.Key != g.run.Key && g.run.Sequence > 0 && m.Sequence > g.run.Sequence
+	return nil
+}
+
+// save は、読み込み時のバージョンを条件にマーカーを書き込みます。
+func (g *Guard) save(ctx context.Context, m Marker) error {
+	m.UpdatedAt = time.Now().UTC()
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("重複排除マーカーのシリアライズに失敗しました: %w", err)
+	}
+	if err := g.store.WriteIf(ctx, g.uri, data, "application/json", g.version); err != nil {
+		return err
+	}
+
+	// 書き込み後のバージョンを得るため読み直す (以降の更新の条件に使用する)
+	obj, err := g.store.Read(ctx, g.uri)
+	if err != nil {
+		return fmt.Errorf("重複排除マーカーの再読み込みに失敗しました: %w", err)
+	}
+	g.marker, g.version = m, obj.Version
+	return nil
+}
+
+// ShouldPublish は、この実行でレポートをアップロードすべきかを返します。
+// 同じキーで公開済みの場合、またはより新しい実行が公開先を使用している場合は false を返します。
+func (g *Guard) ShouldPublish() bool {
+	if g.stale {
+		slog.Warn("より新しい実行が同じ公開先に公開済みのため、古い実行による上書きをスキップします。",
+			"uri", g.uri, "sequence", g.run.Sequence, "latestSequence", g.marker.Sequence)
+		return false
+	}
+	if g.marker.Published {
+		slog.Info("この実行のレポートは公開済みのため、アップロードをスキップします。", "uri", g.uri, "key", g.run.Key)
+		return false
+	}
+	return true
+}
+
+// ShouldNotify は、この実行で通知を送信すべきかを返します。
+func (g *Guard) ShouldNotify() bool {
+	if g.stale {
+		return false
+	}
+	if g.marker.Notified {
+		slog.Info("この実行の通知は送信済みのため、通知をスキップします。", "uri", g.uri, "key", g.run.Key)
+		return false
+	}
+	return true
+}
+
+// Unchanged は、前回公開したレポート (別の実行によるものを含む) と内容のハッシュが一致する場合に、そのレポートのURIと true を返します。
+// 一致する場合は、CIのジョブの再実行などで同じ内容を再公開・再通知する必要はありません。
+func (g *Guard) Unchanged(contentHash string) (string, bool) {
+	if g.stale || contentHash == "" || g.marker.ContentHash != contentHash || g.marker.URI == "" {
+		return "", false
+	}
+	return g.marker.URI, true
+}
+
+// MarkUnchanged は、内容が同じためにアップロードと通知を省略したことをマーカーに記録します。
+// 同じ実行の再試行でも、前回公開したURIを公開済みとして扱います。
+func (g *Guard) MarkUnchanged(ctx context.Context) error {
+	m := g.marker
+	m.Published = true
+	m.Notified = true
+	return g.save(ctx, m)
+}
+
+// MarkPublished は、レポートのアップロード完了と、公開したURI・内容のハッシュをマーカーに記録します。
+func (g *Guard) MarkPublished(ctx context.Context, uri, contentHash string) error {
+	m := g.marker
+	m.Published = true
+	m.URI = uri
+	m.ContentHash = contentHash
+	return g.save(ctx, m)
+}
+
+// ContentHash は、公開するレポートの内容を表すハッシュを返します。
+// parts には、レポート本文に加えて、変換結果に影響する設定 (形式・テンプレートなど) を指定します。
+func ContentHash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// PublishedURI は、同じキーの実行が公開済みの場合に、その実行が公開したURIを返します。
+func (g *Guard) PublishedURI() string {
+	if g.stale || !g.marker.Published {
+		return ""
+	}
+	return g.marker.URI
+}
+
+// MarkNotified は、通知の送信完了をマーカーに記録します。
+func (g *Guard) MarkNotified(ctx context.Context) error {
+	m := g.marker
+	m.Notified = true
+	return g.save(ctx, m)
+}
@@ -0,0 +1,93 @@
+package impact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// bazelPartialSuccess は、--keep_going 指定時に一部のターゲットの評価に失敗したことを示す bazel の終了コードです。
+const bazelPartialSuccess = 3
+
+// AnalyzeBazel は、workDir のワークスペースで bazel query を実行し、changedFiles の変更の影響を受けるターゲットを求めます。
+// ワークスペースは、レビュー対象のコミットがチェックアウトされている必要があります (runner は一時的な git worktree を使用します)。
+// どのパッケージにも属さないファイル (削除されたファイルなど) は無視します。
+func AnalyzeBazel(ctx context.Context, workDir string, changedFiles []string) (Result, error) {
+	result := Result{Tool: "bazel", Unit: "ターゲット"}
+	if workDir == "" {
+		return result, errors.New("bazel による解析にはローカルのワークスペースが必要です (--ephemeral では使用できません)")
+	}
+	if len(changedFiles) == 0 {
+		return result, nil
+	}
+
+	quoted := make([]string, 0, len(changedFiles))
+	for _, f := range changedFiles {
+		quoted = append(quoted, strconv.Quote(f))
+	}
+	files := "set(" + strings.Join(quoted, " ") + ")"
+
+	direct, err := bazelQuery(ctx, workDir, fmt.Sprintf("kind(rule, rdeps(//..., %s, 1))", files))
+	if err != nil {
+		return result, err
+	}
+	all, err := bazelQuery(ctx, workDir, fmt.Sprintf("kind(rule, rdeps(//..., %s))", files))
+	if err != nil {
+		return result, err
+	}
+
+	isDirect := make(map[string]bool, len(direct))
+	for _, label := range direct {
+		isDirect[label] = true
+	}
+	result.Changed = direct
+	for _, label := range all {
+		if !isDirect[label] {
+			result.Dependents = append(result.Dependents, label)
+		}
+	}
+	return result, nil
+}
+
+// ShutdownBazel は、workDir のワークスペースの bazel のサーバーを停止します。
+// 一時的なワークスペースで解析した後に、サーバーのプロセスが残らないよう使用します。停止に失敗しても警告にとどめます。
+func ShutdownBazel(ctx context.Context, workDir string) {
+	cmd := exec.CommandContext(context.WithoutCancel(ctx), "bazel", "shutdown")
+	cmd.Dir = workDir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		slog.Warn("bazel のサーバーの停止に失敗しました。", "dir", workDir, "error", err, "output", strings.TrimSpace(string(out)))
+	}
+}
+
+// bazelQuery は、bazel query を実行し、結果のラベルをソートして返します。
+func bazelQuery(ctx context.Context, workDir, expr string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, "bazel", "query", "--keep_going", "--output=label", expr)
+	cmd.Dir = workDir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	slog.Debug("bazel query を実行します。", "expr", expr)
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != bazelPartialSuccess {
+			return nil, fmt.Errorf("bazel query の実行に失敗しました: %w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		slog.Warn("bazel query で一部のターゲットを評価できませんでした。影響範囲が不完全な可能性があります。", "stderr", strings.TrimSpace(stderr.String()))
+	}
+
+	var labels []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			labels = append(labels, line)
+		}
+	}
+	sort.Strings(labels)
+	return labels, nil
+}
@@ -0,0 +1,125 @@
+package impact
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// goModuleLine は、go.mod の module ディレクティブです。
+var goModuleLine = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// AnalyzeGo は、リポジトリ内の Go モジュール (go.mod) のパッケージのインポート関係から、
+// changedFiles の変更の影響を受けるパッケージを求めます。複数の go.mod を含むモノレポにも対応します。
+// vendor と testdata 配下のファイル、および go.mod の外にあるファイルは対象外です。
+func AnalyzeGo(ctx context.Context, src Source, changedFiles []string) (Result, error) {
+	result := Result{Tool: "go", Unit: "パッケージ"}
+
+	files, err := src.ListTree(ctx)
+	if err != nil {
+		return result, fmt.Errorf("ファイル一覧の取得に失敗しました: %w", err)
+	}
+
+	// モジュールのディレクトリとモジュールパスの対応表
+	modules := make(map[string]string)
+	for _, f := range files {
+		if path.Base(f) != "go.mod" || excludedGoPath(f) {
+			continue
+		}
+		data, err := src.ReadFile(ctx, f)
+		if err != nil {
+			slog.Warn("go.mod の読み込みに失敗したため、このモジュールは対象外にします。", "path", f, "error", err)
+			continue
+		}
+		if m := goModuleLine.FindSubmatch(data); m != nil {
+			modules[path.Dir(f)] = string(m[1])
+		}
+	}
+	if len(modules) == 0 {
+		return result, fmt.Errorf("リポジトリに go.mod が見つかりません")
+	}
+
+	// パッケージごとのインポート (リポジトリ内のパッケージに限らず、後で絞り込む)
+	deps := make(map[string][]string)
+	for _, f := range files {
+		if !strings.HasSuffix(f, ".go") || excludedGoPath(f) {
+			continue
+		}
+		pkg, ok := goPackagePath(modules, path.Dir(f))
+		if !ok {
+			continue
+		}
+		if _, ok := deps[pkg]; !ok {
+			deps[pkg] = nil
+		}
+		data, err := src.ReadFile(ctx, f)
+		if err != nil {
+			slog.Debug("Goファイルの読み込みに失敗したため、インポートの解析をスキップします。", "path", f, "error", err)
+			continue
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), f, data, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+		for _, imp := range parsed.Imports {
+			if p, err := strconv.Unquote(imp.Path.Value); err == nil {
+				deps[pkg] = append(deps[pkg], p)
+			}
+		}
+	}
+
+	changed := make(map[string]bool)
+	for _, f := range changedFiles {
+		if !strings.HasSuffix(f, ".go") && path.Base(f) != "go.mod" {
+			continue
+		}
+		if pkg, ok := goPackagePath(modules, path.Dir(f)); ok {
+			if _, exists := deps[pkg]; exists {
+				changed[pkg] = true
+			}
+		}
+	}
+	for pkg := range changed {
+		result.Changed = append(result.Changed, pkg)
+	}
+	sort.Strings(result.Changed)
+
+	result.Dependents = reverseClosure(deps, result.Changed)
+	return result, nil
+}
+
+// goPackagePath は、ディレクトリを含む最も近いモジュールから、そのディレクトリのパッケージのインポートパスを返します。
+func goPackagePath(modules map[string]string, dir string) (string, bool) {
+	for d := dir; ; d = path.Dir(d) {
+		if module, ok := modules[d]; ok {
+			rel := strings.TrimPrefix(strings.TrimPrefix(dir, d), "/")
+			if d == "." {
+				rel = strings.TrimPrefix(dir, ".")
+			}
+			if rel == "" {
+				return module, true
+			}
+			return module + "/" + rel, true
+		}
+		if d == "." || d == "/" {
+			return "", false
+		}
+	}
+}
+
+// excludedGoPath は、パッケージの解析対象外とするパス (vendor, testdata 配下) かを返します。
+func excludedGoPath(p string) bool {
+	for _, elem := range strings.Split(path.Dir(p), "/") {
+		if elem == "vendor" || elem == "testdata" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package impact
+
+import (
+	"context"
+	"sort"
+)
+
+// Source は、解析対象の時点のリポジトリのファイルを読み込む機能です。
+type Source interface {
+	// ReadFile は、リポジトリルートからの相対パスのファイル内容を返します。
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+	// ListTree は、リポジトリ内のすべてのファイルのパスを返します。
+	ListTree(ctx context.Context) ([]string, error)
+}
+
+// Result は、変更の影響範囲の解析結果です。
+type Result struct {
+	Tool       string   // 解析に使用したビルドグラフ ("go" または "bazel")
+	Unit       string   // 影響範囲の単位 ("パッケージ" または "ターゲット")
+	Changed    []string // 変更されたファイルを直接含むパッケージ・ターゲット
+	Dependents []string // Changed に推移的に依存するパッケージ・ターゲット (Changed を除く)
+}
+
+// BlastRadius は、変更の影響を受けるパッケージ・ターゲットの総数 (直接・推移的の合計) を返します。
+func (r Result) BlastRadius() int {
+	return len(r.Changed) + len(r.Dependents)
+}
+
+// reverseClosure は、依存関係のグラフ (キーが依存する側) から、roots に推移的に依存するノードを roots を除いて返します。
+func reverseClosure(deps map[string][]string, roots []string) []string {
+	rdeps := make(map[string][]string)
+	for from, tos := range deps {
+		for _, to := range tos {
+			rdeps[to] = append(rdeps[to], from)
+		}
+	}
+
+	visited := make(map[string]bool, len(roots))
+	queue := append([]string(nil), roots...)
+	for _, r := range roots {
+		visited[r] = true
+	}
+	var dependents []string
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, next := range rdeps[cur] {
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			dependents = append(dependents, next)
+			queue = append(queue, next)
+		}
+	}
+	sort.Strings(dependents)
+	return dependents
+}
@@ -0,0 +1,175 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// goModuleLine は、go.mod の module ディレクティブです。
+var goModuleLine = regexp.MustCompile(`(?m)^module\s+"?([^\s"]+)"?`)
+
+// goModule は、file から親ディレクトリをたどって最も近い go.mod を探し、モジュールパスとそのディレクトリを返します。
+func goModule(ctx context.Context, src Source, file string) (string, string, error) {
+	dir := path.Dir(file)
+	for {
+		modFile := path.Join(dir, "go.mod")
+		if data, err := src.ReadFile(ctx, modFile); err == nil {
+			m := goModuleLine.FindSubmatch(data)
+			if m == nil {
+				return "", "", errNoModule
+			}
+			return string(m[1]), dir, nil
+		}
+		if dir == "." || dir == "/" || dir == "" {
+			return "", "", errNoModule
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// goImports は、同じモジュール内のパッケージのインポートと、パッケージ名.識別子 の形で参照されている識別子を返します。
+// 構文エラーなどで解析できないファイルや、go.mod が見つからない場合は空のスライスを返します。
+func goImports(ctx context.Context, src Source, file, content string) ([]importRef, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil
+	}
+	module, modDir, err := goModule(ctx, src, file)
+	if err != nil {
+		return nil, nil
+	}
+
+	byName := make(map[string]*importRef)
+	var refs []*importRef
+	for _, imp := range f.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || (importPath != module && !strings.HasPrefix(importPath, module+"/")) {
+			continue
+		}
+		name := path.Base(importPath)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		ref := &importRef{
+			candidates: []string{path.Join(modDir, strings.TrimPrefix(importPath, module))},
+			names:      make(map[string]bool),
+		}
+		byName[name] = ref
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if id, ok := sel.X.(*ast.Ident); ok {
+			if ref := byName[id.Name]; ref != nil {
+				ref.names[sel.Sel.Name] = true
+			}
+		}
+		return true
+	})
+
+	result := make([]importRef, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, *ref)
+	}
+	return result, nil
+}
+
+// goPackageFiles は、パッケージのディレクトリ直下の (テスト以外の) Go ファイルを返します。
+func goPackageFiles(ctx context.Context, src Source, candidates []string) []string {
+	var files []string
+	for _, dir := range candidates {
+		list, err := src.ListFiles(ctx, dir)
+		if err != nil {
+			continue
+		}
+		for _, f := range list {
+			if strings.HasSuffix(f, ".go") && !strings.HasSuffix(f, "_test.go") {
+				files = append(files, f)
+			}
+		}
+	}
+	return files
+}
+
+// goDeclarations は、パッケージのトップレベルの宣言のうち、names に含まれるものを返します。関数とメソッドは本体を除きます。
+func goDeclarations(file, content string, names map[string]bool) []Declaration {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, content, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	var decls []Declaration
+	add := func(name string, doc *ast.CommentGroup, node any) {
+		var buf bytes.Buffer
+		if doc != nil {
+			for _, c := range doc.List {
+				buf.WriteString(c.Text)
+				buf.WriteByte('\n')
+			}
+		}
+		if err := printer.Fprint(&buf, fset, node); err != nil {
+			return
+		}
+		decls = append(decls, Declaration{File: file, Name: name, Code: capLines(buf.String(), "//")})
+	}
+
+	for _, d := range f.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil || !names[d.Name.Name] {
+				continue
+			}
+			sig := *d
+			sig.Doc, sig.Body = nil, nil
+			add(d.Name.Name, d.Doc, &sig)
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if names[s.Name.Name] {
+						spec := *s
+						spec.Doc = nil
+						add(s.Name.Name, specDoc(d, s.Doc), &ast.GenDecl{Tok: d.Tok, Specs: []ast.Spec{&spec}})
+					}
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if names[n.Name] {
+							spec := *s
+							spec.Doc = nil
+							add(n.Name, specDoc(d, s.Doc), &ast.GenDecl{Tok: d.Tok, Specs: []ast.Spec{&spec}})
+							break
+						}
+					}
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// specDoc は、宣言のドキュメントコメントを返します。括弧でまとめた宣言の場合は、個々の宣言のコメントを優先します。
+func specDoc(d *ast.GenDecl, doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc != nil || len(d.Specs) > 1 {
+		return doc
+	}
+	return d.Doc
+}
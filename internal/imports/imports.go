@@ -0,0 +1,131 @@
+package imports
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+)
+
+// maxDeclLines は、1つの宣言として抽出する最大行数です。これを超える部分は省略します。
+const maxDeclLines = 40
+
+// Source は、解析対象のリポジトリのファイルを読み込む機能を定義します。パスはリポジトリルートからの相対パスです。
+type Source interface {
+	ReadFile(ctx context.Context, path string) ([]byte, error)
+	// ListFiles は、ディレクトリ直下のファイルのパスを返します。
+	ListFiles(ctx context.Context, dir string) ([]string, error)
+}
+
+// Declaration は、変更されたファイルがインポートしている、リポジトリ内の宣言です。
+type Declaration struct {
+	File     string // 宣言が定義されているファイル
+	Name     string // 宣言の名前
+	Code     string // 宣言のコード (関数は本体を除いたシグネチャ)
+	Language string // コードブロックの言語名
+}
+
+// importRef は、変更されたファイル内の1つのインポートと、そこから参照されている名前です。
+type importRef struct {
+	candidates []string        // インポート先のファイル (またはディレクトリ) の候補
+	names      map[string]bool // 参照されている名前
+}
+
+// Supported は、インポートの解析に対応した言語のファイルかを返します。
+func Supported(p string) bool {
+	_, ok := languageFor(p)
+	return ok
+}
+
+// Resolve は、変更されたファイル (変更後の内容) のインポートのうち、リポジトリ内のファイルに解決できるものについて、
+// 変更されたファイルから参照されている宣言を返します。対応していない言語のファイルでは空のスライスを返します。
+// 外部パッケージ (標準ライブラリやサードパーティ) のインポートは対象外です。
+func Resolve(ctx context.Context, src Source, file string, content []byte) ([]Declaration, error) {
+	lang, ok := languageFor(file)
+	if !ok {
+		return nil, nil
+	}
+
+	refs, err := lang.imports(ctx, src, file, string(content))
+	if err != nil {
+		return nil, err
+	}
+
+	var decls []Declaration
+	seen := make(map[string]bool)
+	for _, ref := range refs {
+		if len(ref.names) == 0 {
+			continue
+		}
+		for _, target := range lang.resolveFiles(ctx, src, ref.candidates) {
+			if target == file {
+				continue
+			}
+			data, err := src.ReadFile(ctx, target)
+			if err != nil {
+				continue
+			}
+			for _, d := range lang.declarations(target, string(data), ref.names) {
+				key := d.File + "#" + d.Name
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				d.Language = lang.name
+				decls = append(decls, d)
+			}
+		}
+	}
+	return decls, nil
+}
+
+// language は、言語ごとのインポートの解析と宣言の抽出の実装です。
+type language struct {
+	name         string
+	exts         []string
+	imports      func(ctx context.Context, src Source, file, content string) ([]importRef, error)
+	resolveFiles func(ctx context.Context, src Source, candidates []string) []string
+	declarations func(file, content string, names map[string]bool) []Declaration
+}
+
+// languages は、インポートの解析に対応した言語です。
+var languages = []language{
+	{name: "go", exts: []string{".go"}, imports: goImports, resolveFiles: goPackageFiles, declarations: goDeclarations},
+	{name: "typescript", exts: []string{".ts", ".tsx", ".js", ".jsx", ".mjs"}, imports: tsImports, resolveFiles: firstExisting, declarations: tsDeclarations},
+	{name: "python", exts: []string{".py"}, imports: pyImports, resolveFiles: firstExisting, declarations: pyDeclarations},
+}
+
+// languageFor は、ファイルの拡張子に対応する言語を返します。
+func languageFor(p string) (language, bool) {
+	ext := strings.ToLower(path.Ext(p))
+	for _, l := range languages {
+		for _, e := range l.exts {
+			if e == ext {
+				return l, true
+			}
+		}
+	}
+	return language{}, false
+}
+
+// firstExisting は、候補のうち最初に読み込めたファイルを返します。
+func firstExisting(ctx context.Context, src Source, candidates []string) []string {
+	for _, c := range candidates {
+		if _, err := src.ReadFile(ctx, c); err == nil {
+			return []string{c}
+		}
+	}
+	return nil
+}
+
+// errNoModule は、go.mod が見つからない、またはモジュールパスを読み取れないことを示すエラーです。
+var errNoModule = errors.New("go.mod のモジュールパスを取得できません")
+
+// capLines は、コードを maxDeclLines 行までに切り詰めます。comment は省略を示す行に使う行コメントの記号です。
+func capLines(code, comment string) string {
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	if len(lines) <= maxDeclLines {
+		return strings.Join(lines, "\n")
+	}
+	return strings.Join(lines[:maxDeclLines], "\n") + "\n" + comment + " ... (省略)"
+}
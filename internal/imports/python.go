@@ -0,0 +1,134 @@
+package imports
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	// pyFromImport は、from ... import 文です (先頭のドット, モジュール, インポートする名前)。
+	pyFromImport = regexp.MustCompile(`(?m)^\s*from\s+(\.*)([\w.]*)\s+import\s+(\([^)]*\)|[^\n#]+)`)
+	// pyImport は、import 文です (モジュール, 別名)。
+	pyImport = regexp.MustCompile(`(?m)^\s*import\s+([\w.]+)(?:\s+as\s+(\w+))?\s*(?:#.*)?$`)
+	// pyTopLevelDecl は、トップレベルの関数・クラス・変数の宣言です (def/class の名前, 変数名)。
+	pyTopLevelDecl = regexp.MustCompile(`^(?:(?:async\s+)?def\s+(\w+)\s*\(|class\s+(\w+)\b|(\w+)\s*(?::[^=]+)?=[^=])`)
+)
+
+// pyImports は、from ... import 文でインポートしている名前と、import 文でインポートしたモジュールから
+// モジュール.名前 の形で参照されている名前を返します。リポジトリ内のモジュールに解決できないものは後で除外されます。
+func pyImports(_ context.Context, _ Source, file, content string) ([]importRef, error) {
+	var refs []importRef
+	for _, m := range pyFromImport.FindAllStringSubmatch(content, -1) {
+		names := make(map[string]bool)
+		for _, item := range strings.Split(strings.Trim(m[3], "() \t\r\n"), ",") {
+			fields := strings.Fields(item)
+			if len(fields) > 0 && fields[0] != "*" {
+				names[fields[0]] = true
+			}
+		}
+		refs = append(refs, importRef{candidates: pyCandidates(file, m[1], m[2]), names: names})
+	}
+
+	for _, m := range pyImport.FindAllStringSubmatch(content, -1) {
+		alias := m[2]
+		if alias == "" {
+			alias = m[1]
+		}
+		names := make(map[string]bool)
+		usage := regexp.MustCompile(`\b` + regexp.QuoteMeta(alias) + `\.(\w+)`)
+		for _, u := range usage.FindAllStringSubmatch(content, -1) {
+			names[u[1]] = true
+		}
+		refs = append(refs, importRef{candidates: pyCandidates(file, "", m[1]), names: names})
+	}
+	return refs, nil
+}
+
+// pyCandidates は、モジュール名からインポート先のファイルの候補を返します。
+// 相対インポートはファイルのディレクトリから、絶対インポートはリポジトリルートと src/ から解決します。
+func pyCandidates(file, dots, module string) []string {
+	rel := strings.ReplaceAll(module, ".", "/")
+
+	var bases []string
+	if dots != "" {
+		dir := path.Dir(file)
+		for i := 1; i < len(dots); i++ {
+			dir = path.Dir(dir)
+		}
+		bases = []string{path.Join(dir, rel)}
+	} else {
+		bases = []string{rel, path.Join("src", rel)}
+	}
+
+	var candidates []string
+	for _, base := range bases {
+		candidates = append(candidates, base+".py", path.Join(base, "__init__.py"))
+	}
+	return candidates
+}
+
+// pyDeclarations は、トップレベルの宣言のうち names に含まれるものを返します。
+// 関数とクラスはシグネチャと docstring のみ、変数は代入文の行を返します。
+func pyDeclarations(file, content string, names map[string]bool) []Declaration {
+	lines := strings.Split(content, "\n")
+	var decls []Declaration
+	for i, line := range lines {
+		m := pyTopLevelDecl.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[1] + m[2] + m[3]
+		if !names[name] {
+			continue
+		}
+
+		code := line
+		if m[3] == "" {
+			code = pyDefinitionHead(lines[i:])
+		}
+		decls = append(decls, Declaration{File: file, Name: name, Code: capLines(code, "#")})
+	}
+	return decls
+}
+
+// pyDefinitionHead は、def/class の先頭行から、シグネチャの終わり (':') と直後の docstring までを返します。
+func pyDefinitionHead(lines []string) string {
+	end := 0
+	for end < len(lines) && end < maxDeclLines && !strings.HasSuffix(strings.TrimSpace(stripPyComment(lines[end])), ":") {
+		end++
+	}
+	if end >= len(lines) {
+		return strings.Join(lines, "\n")
+	}
+
+	head := lines[:end+1]
+	next := end + 1
+	if next < len(lines) {
+		doc := strings.TrimSpace(lines[next])
+		for _, quote := range []string{`"""`, `'''`} {
+			if !strings.HasPrefix(doc, quote) {
+				continue
+			}
+			last := next
+			if strings.Count(doc, quote) < 2 {
+				for last = next + 1; last < len(lines) && !strings.Contains(lines[last], quote); last++ {
+				}
+			}
+			if last < len(lines) {
+				head = lines[:last+1]
+			}
+			break
+		}
+	}
+	return strings.Join(head, "\n")
+}
+
+// stripPyComment は、行末のコメントを除去します (文字列内の '#' は考慮しない簡易的な処理です)。
+func stripPyComment(line string) string {
+	if i := strings.Index(line, "#"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
@@ -0,0 +1,127 @@
+package imports
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	// tsImportStmt は、相対パスからの import 文です (import 句, モジュール指定子)。
+	tsImportStmt = regexp.MustCompile(`(?s)\bimport\s+(?:type\s+)?([^;'"]*?)\s*from\s*['"](\.{1,2}/[^'"]*)['"]`)
+	// tsNamespace は、import 句の名前空間インポート (* as ns) です。
+	tsNamespace = regexp.MustCompile(`\*\s*as\s+([A-Za-z_$][\w$]*)`)
+	// tsDefault は、import 句の先頭のデフォルトインポートです。
+	tsDefault = regexp.MustCompile(`^\s*([A-Za-z_$][\w$]*)\s*(?:,|$)`)
+	// tsExportDecl は、エクスポートされた宣言の開始行です (default, 種別, 名前)。
+	tsExportDecl = regexp.MustCompile(`^\s*export\s+(default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(function\*?|class|interface|type|enum|const|let|var)\s+([A-Za-z_$][\w$]*)`)
+)
+
+// tsImports は、相対パスからのインポートと、そこから参照されている名前を返します。
+// デフォルトインポートは "default"、名前空間インポートは ns.名前 の形で参照されている名前を対象とします。
+func tsImports(_ context.Context, _ Source, file, content string) ([]importRef, error) {
+	var refs []importRef
+	for _, m := range tsImportStmt.FindAllStringSubmatch(content, -1) {
+		clause, spec := m[1], m[2]
+		names := make(map[string]bool)
+
+		if ns := tsNamespace.FindStringSubmatch(clause); ns != nil {
+			usage := regexp.MustCompile(`\b` + regexp.QuoteMeta(ns[1]) + `\.([A-Za-z_$][\w$]*)`)
+			for _, u := range usage.FindAllStringSubmatch(content, -1) {
+				names[u[1]] = true
+			}
+		} else if d := tsDefault.FindStringSubmatch(clause); d != nil {
+			names["default"] = true
+		}
+		if open, close := strings.Index(clause, "{"), strings.LastIndex(clause, "}"); open >= 0 && close > open {
+			for _, item := range strings.Split(clause[open+1:close], ",") {
+				fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(item), "type "))
+				if len(fields) > 0 {
+					names[fields[0]] = true
+				}
+			}
+		}
+
+		refs = append(refs, importRef{candidates: tsCandidates(path.Join(path.Dir(file), spec)), names: names})
+	}
+	return refs, nil
+}
+
+// tsCandidates は、モジュール指定子を解決したパスから、インポート先のファイルの候補を返します。
+func tsCandidates(base string) []string {
+	switch ext := path.Ext(base); ext {
+	case ".ts", ".tsx":
+		return []string{base}
+	case ".js", ".jsx", ".mjs":
+		// TypeScript の ESM では、'./foo.js' の指定で foo.ts を参照する
+		trimmed := strings.TrimSuffix(base, ext)
+		return []string{trimmed + ".ts", trimmed + ".tsx", base}
+	}
+	return []string{
+		base + ".ts", base + ".tsx", base + ".d.ts", base + ".js", base + ".jsx",
+		base + "/index.ts", base + "/index.tsx", base + "/index.js",
+	}
+}
+
+// tsDeclarations は、エクスポートされた宣言のうち names に含まれるものを返します。
+// インターフェース・型エイリアス・列挙型は本体を含め、関数・クラス・変数は宣言の先頭 (シグネチャ) のみを返します。
+func tsDeclarations(file, content string, names map[string]bool) []Declaration {
+	lines := strings.Split(content, "\n")
+	var decls []Declaration
+	for i, line := range lines {
+		m := tsExportDecl.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		name := m[3]
+		switch {
+		case names[name]:
+		case m[1] != "" && names["default"]:
+			name = "default"
+		default:
+			continue
+		}
+
+		var code string
+		switch m[2] {
+		case "interface", "type", "enum":
+			code = balancedBlock(lines[i:])
+		default:
+			code = signature(lines[i:])
+		}
+		decls = append(decls, Declaration{File: file, Name: name, Code: capLines(code, "//")})
+	}
+	return decls
+}
+
+// balancedBlock は、先頭行から波括弧の対応が閉じる行 (または括弧を含まない文の終わり) までを返します。
+func balancedBlock(lines []string) string {
+	depth := 0
+	opened := false
+	for i, line := range lines {
+		depth += strings.Count(line, "{") - strings.Count(line, "}")
+		if strings.Contains(line, "{") {
+			opened = true
+		}
+		trimmed := strings.TrimSpace(line)
+		if (opened && depth <= 0) || (!opened && strings.HasSuffix(trimmed, ";")) || i == maxDeclLines {
+			return strings.Join(lines[:i+1], "\n")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// signature は、先頭行から本体の開始 ('{' または '=>') または文の終わり (';') までを返します。本体は省略します。
+func signature(lines []string) string {
+	for i, line := range lines {
+		if idx := strings.Index(line, "{"); idx >= 0 {
+			head := append(append([]string{}, lines[:i]...), strings.TrimRight(line[:idx], " ")+" { ... }")
+			return strings.Join(head, "\n")
+		}
+		if strings.Contains(line, "=>") || strings.HasSuffix(strings.TrimSpace(line), ";") || i == maxDeclLines {
+			return strings.Join(lines[:i+1], "\n")
+		}
+	}
+	return lines[0]
+}
@@ -0,0 +1,59 @@
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// ErrInterrupted は、中断シグナルを受信したため、処理を途中で終了したことを示すエラーです。
+var ErrInterrupted = errors.New("中断シグナルを受信したため、レビューを途中で終了しました")
+
+// stateKey は、context に中断要求の状態を格納するためのキーです。
+type stateKey struct{}
+
+// state は、中断要求の状態です。
+type state struct {
+	requested atomic.Bool
+}
+
+// Install は、SIGINT / SIGTERM を受信した際に、実行中の処理を即座に打ち切らず、区切りの良いところで終了できるようにします。
+// 1回目のシグナルでは中断要求を記録するだけで context はキャンセルせず、処理側は Requested で確認して
+// 実行中の単位 (分割レビューの1パートなど) を完了した後に途中までの結果をまとめて終了します。
+// 2回目のシグナルで context をキャンセルし、即座に終了させます。
+func Install(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	st := &state{}
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		defer signal.Stop(sigCh)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-sigCh:
+				if st.requested.CompareAndSwap(false, true) {
+					slog.Warn("中断シグナルを受信しました。実行中のパートの完了後にレビューを終了し、途中までの結果をまとめます。もう一度受信すると即座に終了します。", "signal", sig.String())
+					continue
+				}
+				slog.Warn("中断シグナルを再度受信したため、処理を即座に終了します。", "signal", sig.String())
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return context.WithValue(ctx, stateKey{}, st)
+}
+
+// Requested は、中断シグナルを受信済みかを返します。Install されていない context では常に false を返します。
+func Requested(ctx context.Context) bool {
+	st, ok := ctx.Value(stateKey{}).(*state)
+	return ok && st.requested.Load()
+}
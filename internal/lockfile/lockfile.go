@@ -0,0 +1,106 @@
+package lockfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// pollInterval は、ロック待機中に再取得を試みる間隔です。
+const pollInterval = 500 * time.Millisecond
+
+// ErrLocked は、別のプロセスがロックを保持しており、タイムアウトまでに取得できなかったことを示すエラーです。
+var ErrLocked = errors.New("別のプロセスがロックを保持しています")
+
+// errWouldBlock は、ロックが即座に取得できないことを示す内部エラーです。
+var errWouldBlock = errors.New("lock would block")
+
+// Lock は、ローカルリポジトリや状態ファイルなど、複数のプロセスが更新するパスに対するアドバイザリロックです。
+type Lock struct {
+	path string
+	file *os.File
+}
+
+// PathFor は、ローカルリポジトリなどのパスに対応するロックファイルのパスを返します。
+// 対象がまだ存在しない (クローン前など) 場合でも使えるよう、対象の外側に配置します。
+func PathFor(localPath string) string {
+	return filepath.Clean(localPath) + ".lock"
+}
+
+// Acquire は、ロックファイルを取得します。
+// 別のプロセスがロックを保持している場合は timeout まで待機し、それでも取得できない場合は ErrLocked を返します。
+// timeout が0以下の場合は待機せずに即座に失敗します。
+func Acquire(ctx context.Context, path string, timeout time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("ロックファイルの親ディレクトリの作成に失敗しました: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	logged := false
+	for {
+		f, err := tryAcquire(path)
+		if err == nil {
+			writeOwner(f)
+			slog.Debug("ロックを取得しました。", "lock", path)
+			return &Lock{path: path, file: f}, nil
+		}
+		if !errors.Is(err, errWouldBlock) {
+			return nil, fmt.Errorf("ロックファイル '%s' の取得に失敗しました: %w", path, err)
+		}
+
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("%w (lock: %s, holder: %s)。しばらく待ってから再実行してください", ErrLocked, path, readOwner(path))
+		}
+		if !logged {
+			slog.Info("別のプロセスがロックを保持しているため、ロックの解放を待機します。", "lock", path, "holder", readOwner(path), "timeout", timeout)
+			logged = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("ロックの待機中に中断されました: %w", ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// Release は、ロックを解放します。nil レシーバに対しては何もしません。
+func (l *Lock) Release() error {
+	if l == nil || l.file == nil {
+		return nil
+	}
+	err := release(l.path, l.file)
+	l.file = nil
+	if err != nil {
+		return fmt.Errorf("ロックファイル '%s' の解放に失敗しました: %w", l.path, err)
+	}
+	slog.Debug("ロックを解放しました。", "lock", l.path)
+	return nil
+}
+
+// writeOwner は、診断用にロック保持者の情報をロックファイルに書き込みます。
+func writeOwner(f *os.File) {
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("pid=%d host=%s since=%s\n", os.Getpid(), host, time.Now().Format(time.RFC3339))
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(owner), 0)
+	}
+}
+
+// readOwner は、ロックファイルに記録された保持者の情報を返します。
+func readOwner(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "unknown"
+	}
+	owner := strings.TrimSpace(string(data))
+	if owner == "" {
+		return "unknown"
+	}
+	return owner
+}
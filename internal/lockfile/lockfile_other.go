@@ -0,0 +1,28 @@
+//go:build !unix
+
+package lockfile
+
+import (
+	"errors"
+	"os"
+)
+
+// tryAcquire は、ロックファイルの排他的な作成 (O_EXCL) によってロックを取得します。
+// flock が利用できない環境向けの実装で、異常終了時はロックファイルを手動で削除する必要があります。
+func tryAcquire(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, errWouldBlock
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// release は、ファイルを閉じてロックファイルを削除します。
+func release(path string, f *os.File) error {
+	closeErr := f.Close()
+	removeErr := os.Remove(path)
+	return errors.Join(closeErr, removeErr)
+}
@@ -0,0 +1,34 @@
+//go:build unix
+
+package lockfile
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryAcquire は、flock(2) による排他ロックをノンブロッキングで取得します。
+// プロセスが異常終了した場合でも、カーネルによってロックは自動的に解放されます。
+func tryAcquire(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errWouldBlock
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// release は、flock を解放してファイルを閉じます。
+// 別のプロセスが同じファイルを開いて待機している可能性があるため、ファイル自体は削除しません。
+func release(path string, f *os.File) error {
+	unlockErr := syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	closeErr := f.Close()
+	return errors.Join(unlockErr, closeErr)
+}
@@ -0,0 +1,179 @@
+package netconfig
+
+import (
+	"expvar"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metricsName は、HTTPクライアントのメトリクスを expvar に公開する際の名前です。
+// サーバーモードでは --metrics-listen のアドレスの /debug/vars から参照できます (MetricsHandler)。
+const metricsName = "http_client"
+
+// HostMetrics は、接続先ホストごとのHTTPクライアントの集計値です。
+type HostMetrics struct {
+	Requests         expvar.Int // 送信したリクエスト数
+	Errors           expvar.Int // 通信エラー (レスポンスを受信できなかったもの) の数
+	ServerErrors     expvar.Int // ステータスコード 5xx の数
+	RequestBytes     expvar.Int // 送信したリクエストボディの合計バイト数 (長さ不明のものは含まない)
+	ResponseBytes    expvar.Int // 受信したレスポンスボディの合計バイト数
+	LatencyMillis    expvar.Int // レスポンスボディの受信完了までの合計所要時間 (ミリ秒)
+	MaxLatencyMillis expvar.Int // 最大所要時間 (ミリ秒)
+}
+
+// String は expvar.Var インターフェースの実装です。
+func (m *HostMetrics) String() string {
+	return `{"requests":` + m.Requests.String() +
+		`,"errors":` + m.Errors.String() +
+		`,"server_errors":` + m.ServerErrors.String() +
+		`,"request_bytes":` + m.RequestBytes.String() +
+		`,"response_bytes":` + m.ResponseBytes.String() +
+		`,"latency_ms_total":` + m.LatencyMillis.String() +
+		`,"latency_ms_max":` + m.MaxLatencyMillis.String() + `}`
+}
+
+// observeLatency は、所要時間を合計と最大値に反映します。
+func (m *HostMetrics) observeLatency(d time.Duration) {
+	ms := d.Milliseconds()
+	m.LatencyMillis.Add(ms)
+
+	// expvar.Int は CAS を提供しないため、ロックで最大値の更新を保護する
+	maxLatencyMu.Lock()
+	defer maxLatencyMu.Unlock()
+	if ms > m.MaxLatencyMillis.Value() {
+		m.MaxLatencyMillis.Set(ms)
+	}
+}
+
+var (
+	maxLatencyMu  sync.Mutex
+	hostMetricsMu sync.Mutex
+	metrics       *expvar.Map
+	metricsOnce   sync.Once
+	installed     atomic.Bool
+)
+
+// Metrics は、ホストごとのHTTPクライアントのメトリクスを保持する expvar.Map を返します。
+func Metrics() *expvar.Map {
+	metricsOnce.Do(func() {
+		metrics = expvar.NewMap(metricsName)
+	})
+	return metrics
+}
+
+// MetricsHandler は、HTTPクライアントのメトリクス (http_client) のみを JSON で返す http.Handler を返します。
+// expvar.Handler と異なり、コマンドライン引数 (cmdline) やメモリの統計は含めません (フラグで渡したシークレットを公開しないため)。
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		fmt.Fprintf(w, "{%q: %s}\n", metricsName, Metrics().String())
+	})
+}
+
+// hostMetrics は、指定ホストのメトリクスを返します。存在しない場合は作成します。
+func hostMetrics(host string) *HostMetrics {
+	m := Metrics()
+	if v, ok := m.Get(host).(*HostMetrics); ok {
+		return v
+	}
+
+	// 同時に作成された場合に備え、ロック内で再確認してから登録する
+	hostMetricsMu.Lock()
+	defer hostMetricsMu.Unlock()
+	if v, ok := m.Get(host).(*HostMetrics); ok {
+		return v
+	}
+	hm := &HostMetrics{}
+	m.Set(host, hm)
+	return hm
+}
+
+// InstallLogging は、http.DefaultTransport を、リクエストごとにメソッド・ホスト・ステータス・サイズ・所要時間を
+// デバッグレベルでログ出力し、メトリクスに集計する RoundTripper でラップします。
+// ConfigureDefaultTransport の後に呼び出してください。複数回呼び出しても一度だけ適用されます。
+func InstallLogging() {
+	if !installed.CompareAndSwap(false, true) {
+		return
+	}
+	http.DefaultTransport = &loggingTransport{next: http.DefaultTransport}
+}
+
+// loggingTransport は、HTTP通信のログ出力とメトリクス集計を行う http.RoundTripper です。
+type loggingTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip は http.RoundTripper インターフェースの実装です。
+// レスポンスボディのサイズと所要時間は、ボディの読み取り完了 (またはクローズ) 時に記録します。
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	m := hostMetrics(host)
+	m.Requests.Add(1)
+	if req.ContentLength > 0 {
+		m.RequestBytes.Add(req.ContentLength)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		m.Errors.Add(1)
+		m.observeLatency(time.Since(start))
+		slog.Debug("HTTPリクエストが失敗しました。",
+			"method", req.Method, "host", host, "requestBytes", req.ContentLength,
+			"latency", time.Since(start), "error", err)
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		m.ServerErrors.Add(1)
+	}
+
+	ttfb := time.Since(start)
+	resp.Body = &countingBody{
+		ReadCloser: resp.Body,
+		onDone: func(n int64) {
+			latency := time.Since(start)
+			m.ResponseBytes.Add(n)
+			m.observeLatency(latency)
+			slog.Debug("HTTPリクエストが完了しました。",
+				"method", req.Method, "host", host, "status", resp.StatusCode,
+				"requestBytes", req.ContentLength, "responseBytes", n,
+				"ttfb", ttfb, "latency", latency)
+		},
+	}
+	return resp, nil
+}
+
+// countingBody は、読み取ったバイト数を数え、EOF またはクローズ時に一度だけ onDone を呼び出す io.ReadCloser です。
+type countingBody struct {
+	io.ReadCloser
+	n      int64
+	once   sync.Once
+	onDone func(n int64)
+}
+
+// Read は io.Reader インターフェースの実装です。
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if err == io.EOF {
+		b.done()
+	}
+	return n, err
+}
+
+// Close は io.Closer インターフェースの実装です。
+func (b *countingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
+
+// done は、読み取り完了を一度だけ通知します。
+func (b *countingBody) done() {
+	b.once.Do(func() { b.onDone(b.n) })
+}
@@ -0,0 +1,204 @@
+package netconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// supportedProxySchemes は、プロキシURLとして受け付けるスキームです。
+// socks5h はホスト名の解決をプロキシ側で行います。
+var supportedProxySchemes = map[string]bool{
+	"http":    true,
+	"https":   true,
+	"socks5":  true,
+	"socks5h": true,
+}
+
+// tlsVersions は、--tls-min-version で指定できる値と定数の対応表です。
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ConfigureDefaultTransport は、http.DefaultTransport にプロキシとTLSの設定を適用します。
+// httpkit のクライアントや Gemini SDK など、独自の Transport を指定しないクライアントはすべてこの設定を使用します。
+// 設定が空の場合は何も変更しません (環境変数 HTTPS_PROXY などによる既定の挙動を維持します)。
+func ConfigureDefaultTransport(cfg config.NetworkConfig) error {
+	if cfg.IsZero() {
+		return nil
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return fmt.Errorf("http.DefaultTransport が *http.Transport ではないため、ネットワーク設定を適用できません")
+	}
+
+	if err := Apply(transport, cfg); err != nil {
+		return err
+	}
+
+	slog.Debug("ネットワーク設定を適用しました。", "proxy", redactURL(cfg.ProxyURL), "noProxy", cfg.NoProxy, "caBundle", cfg.CABundle, "clientCert", cfg.ClientCert, "tlsMinVersion", cfg.TLSMinVersion)
+	return nil
+}
+
+// NewHTTPClient は、プロキシとTLSの設定を適用した Transport を使用する *http.Client を返します。
+// AWS SDK や GCS クライアントなど、http.DefaultTransport を使用せず独自の Transport を構築する SDK に明示的に渡します。
+// InstallLogging の後に呼び出した場合は、ログ出力とメトリクス集計も適用します。
+// 設定が空でログ出力も無効な場合は nil を返します (SDK の既定のクライアントを使用します)。
+func NewHTTPClient(cfg config.NetworkConfig) (*http.Client, error) {
+	if cfg.IsZero() && !installed.Load() {
+		return nil, nil
+	}
+
+	transport := baseTransport()
+	if err := Apply(transport, cfg); err != nil {
+		return nil, err
+	}
+	if installed.Load() {
+		return &http.Client{Transport: &loggingTransport{next: transport}}, nil
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// baseTransport は、http.DefaultTransport (ログ出力のラップを除いたもの) の複製を返します。
+func baseTransport() *http.Transport {
+	rt := http.DefaultTransport
+	if lt, ok := rt.(*loggingTransport); ok {
+		rt = lt.next
+	}
+	if t, ok := rt.(*http.Transport); ok {
+		return t.Clone()
+	}
+	return &http.Transport{Proxy: http.ProxyFromEnvironment}
+}
+
+// Apply は、指定された Transport にプロキシとTLSの設定を適用します。
+func Apply(transport *http.Transport, cfg config.NetworkConfig) error {
+	if cfg.ProxyURL != "" {
+		proxyFunc, err := buildProxyFunc(cfg.ProxyURL, cfg.NoProxy)
+		if err != nil {
+			return err
+		}
+		transport.Proxy = proxyFunc
+	}
+
+	tlsConfig, err := buildTLSConfig(transport.TLSClientConfig, cfg)
+	if err != nil {
+		return err
+	}
+	transport.TLSClientConfig = tlsConfig
+	return nil
+}
+
+// buildProxyFunc は、プロキシURLと除外ホストから Transport.Proxy 用の関数を構築します。
+func buildProxyFunc(proxyURL, noProxy string) (func(*http.Request) (*url.URL, error), error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("プロキシURL '%s' の解析に失敗しました: %w", redactURL(proxyURL), err)
+	}
+	if !supportedProxySchemes[u.Scheme] {
+		return nil, fmt.Errorf("プロキシURLのスキーム '%s' はサポートされていません (http, https, socks5, socks5h のいずれかを指定してください)", u.Scheme)
+	}
+
+	// NO_PROXY の解釈 (ドメインサフィックス、CIDR、ポート指定) は httpproxy に委ねる
+	proxyCfg := &httpproxy.Config{
+		HTTPProxy:  proxyURL,
+		HTTPSProxy: proxyURL,
+		NoProxy:    noProxy,
+	}
+	resolve := proxyCfg.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return resolve(req.URL)
+	}, nil
+}
+
+// buildTLSConfig は、既存のTLS設定を複製し、CAバンドル、クライアント証明書、最小バージョンを適用します。
+func buildTLSConfig(base *tls.Config, cfg config.NetworkConfig) (*tls.Config, error) {
+	var tlsConfig *tls.Config
+	if base != nil {
+		tlsConfig = base.Clone()
+	} else {
+		tlsConfig = &tls.Config{}
+	}
+
+	if cfg.TLSMinVersion != "" {
+		version, ok := tlsVersions[cfg.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("TLSの最小バージョン '%s' はサポートされていません (1.2 または 1.3 を指定してください)", cfg.TLSMinVersion)
+		}
+		tlsConfig.MinVersion = version
+	}
+
+	if cfg.CABundle != "" {
+		pool, err := loadCABundle(cfg.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		cert, err := loadClientCertificate(cfg.ClientCert, cfg.ClientKey)
+		if err != nil {
+			return nil, err
+		}
+		// クライアント証明書はサーバーから要求された場合にのみ提示されるため、
+		// mTLS を要求しないエンドポイント (Gemini API など) への接続には影響しない
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// loadClientCertificate は、mTLS 用のクライアント証明書と秘密鍵を読み込みます。
+func loadClientCertificate(certPath, keyPath string) (tls.Certificate, error) {
+	if certPath == "" || keyPath == "" {
+		return tls.Certificate{}, fmt.Errorf("mTLS を使用するには --client-cert と --client-key の両方を指定してください")
+	}
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("クライアント証明書の読み込みに失敗しました (cert: %s, key: %s): %w", certPath, keyPath, err)
+	}
+	return cert, nil
+}
+
+// loadCABundle は、システムの証明書プールに PEM 形式のCAバンドルを追加したプールを返します。
+// 社内CAを追加しても、公開CAで署名されたエンドポイント (Gemini API など) への接続は維持されます。
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CAバンドル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		slog.Debug("システムの証明書プールを取得できなかったため、CAバンドルのみを使用します。", "error", err)
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CAバンドル '%s' に有効なPEM形式の証明書が含まれていません", path)
+	}
+	return pool, nil
+}
+
+// redactURL は、ログ出力用にURLのユーザー情報 (パスワード) を伏せ字にします。
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "(invalid)"
+	}
+	return strings.TrimSpace(u.Redacted())
+}
@@ -0,0 +1,90 @@
+package objectstore
+
+import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// EncodingGzip は、gzip で圧縮して書き込むことを示す Content-Encoding です。
+const EncodingGzip = "gzip"
+
+// Attributes は、公開するオブジェクトに設定する HTTP のヘッダとメタデータです。
+// CDN のキャッシュやライフサイクルルールがレポートを正しく扱えるよう、レポート本体の書き込みに使用します。
+type Attributes struct {
+	ContentType     string            // 空でない場合、書き込む形式の既定の Content-Type を置き換える
+	CacheControl    string            // Cache-Control ヘッダ (例: "no-cache", "public, max-age=300")
+	ContentEncoding string            // 空でない場合、この形式 (EncodingGzip) で圧縮して Content-Encoding を設定する (ローカルファイルでは圧縮しない)
+	Metadata        map[string]string // オブジェクトのユーザー定義メタデータ (GCS のメタデータ、S3 の x-amz-meta-*、Azure の x-ms-meta-*)
+}
+
+// IsZero は、属性が何も指定されていない場合に true を返します。
+func (a Attributes) IsZero() bool {
+	return a.ContentType == "" && a.CacheControl == "" && a.ContentEncoding == "" && len(a.Metadata) == 0
+}
+
+// AttributeWriter は、Cache-Control・Content-Encoding・メタデータを指定してオブジェクトを書き込める Store が実装するインターフェースです。
+// ローカルファイルはメタデータを保持できないため実装しません。
+type AttributeWriter interface {
+	// WriteWithAttributes は、attrs の Cache-Control・Content-Encoding・メタデータを設定して、条件なしでオブジェクトを書き込みます。
+	// data は呼び出し側で Content-Encoding の形式に圧縮済みです。
+	WriteWithAttributes(ctx context.Context, uri string, data []byte, contentType string, attrs Attributes) error
+}
+
+// attributeStore は、Write で書き込むすべてのオブジェクトに attrs を設定する Store です (WithAttributes)。
+type attributeStore struct {
+	Store
+	attrs Attributes
+}
+
+// WithAttributes は、Write で書き込むオブジェクトに attrs の Content-Type・Cache-Control・メタデータを設定し、圧縮する Store を返します。
+// レポート本体を書き込む Publisher に渡し、マーカーやサイドカーなどの補助的なオブジェクトの書き込みには使用しません。
+// store が AttributeWriter を実装していない場合は、Content-Type のみを反映します。
+func WithAttributes(store Store, attrs Attributes) Store {
+	if attrs.IsZero() {
+		return store
+	}
+	return &attributeStore{Store: store, attrs: attrs}
+}
+
+// compress は、data を encoding の形式で圧縮します。
+func compress(data []byte, encoding string) ([]byte, error) {
+	if encoding != EncodingGzip {
+		return nil, fmt.Errorf("未対応の圧縮形式です: %s", encoding)
+	}
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write は Store インターフェースの実装です。
+func (s *attributeStore) Write(ctx context.Context, uri string, data []byte, contentType string) error {
+	contentType = cmp.Or(s.attrs.ContentType, contentType)
+	if w, ok := s.Store.(AttributeWriter); ok {
+		if s.attrs.ContentEncoding != "" {
+			compressed, err := compress(data, s.attrs.ContentEncoding)
+			if err != nil {
+				return fmt.Errorf("オブジェクト '%s' の圧縮に失敗しました: %w", uri, err)
+			}
+			slog.Debug("オブジェクトを圧縮しました。", "uri", uri, "encoding", s.attrs.ContentEncoding, "size", len(data), "compressed", len(compressed))
+			data = compressed
+		}
+		return w.WriteWithAttributes(ctx, uri, data, contentType, s.attrs)
+	}
+	if s.attrs.CacheControl != "" || s.attrs.ContentEncoding != "" || len(s.attrs.Metadata) > 0 {
+		slog.Debug("公開先のストレージはメタデータに対応していないため、Cache-Control・圧縮・メタデータを適用しません。", "uri", uri)
+	}
+	return s.Store.Write(ctx, uri, data, contentType)
+}
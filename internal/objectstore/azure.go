@@ -0,0 +1,405 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+const (
+	// azureAPIVersion は、Blob Storage の REST API と SAS トークンのバージョンです。
+	azureAPIVersion = "2022-11-02"
+	// defaultAzureEndpointSuffix は、接続文字列で EndpointSuffix が指定されていない場合のエンドポイントのサフィックスです。
+	defaultAzureEndpointSuffix = "core.windows.net"
+	// azureRequestSASExpiration は、各リクエストの認証に使用する SAS トークンの有効期限です。
+	azureRequestSASExpiration = 15 * time.Minute
+	// azureClockSkew は、SAS トークンの開始時刻をさかのぼらせる時間です (クライアントとサーバーの時刻のずれを許容するため)。
+	azureClockSkew = 5 * time.Minute
+)
+
+// azureHostPattern は、Blob Storage のエンドポイントのホスト名 (<account>.blob.<suffix>) に一致する正規表現です。
+var azureHostPattern = regexp.MustCompile(`^([a-z0-9]{3,24})\.blob\.(.+)$`)
+
+// IsAzureURI は、URIが Azure Blob Storage (az://container/blob または https://<account>.blob.core.windows.net/container/blob) を指すかを返します。
+func IsAzureURI(uri string) bool {
+	if strings.HasPrefix(uri, "az://") {
+		return true
+	}
+	u, err := url.Parse(uri)
+	return err == nil && u.Scheme == "https" && azureHostPattern.MatchString(u.Hostname())
+}
+
+// azureBlob は、URIから解決した Blob の場所です。
+type azureBlob struct {
+	account   string
+	container string
+	name      string
+	host      string
+}
+
+// url は、Blob の URL を返します。query には SAS トークンを指定します。
+func (b azureBlob) url(query string) string {
+	u := url.URL{Scheme: "https", Host: b.host, Path: "/" + b.container + "/" + b.name, RawQuery: query}
+	return u.String()
+}
+
+// AzureStore は、Azure Blob Storage 上のオブジェクトを読み書きする Store の実装です。
+// 認証にはストレージアカウントのキーから生成したサービス SAS トークンを使用し、
+// レポートの公開 (Write) と共有用の署名付きURLの生成 (GenerateSignedURL) にも使用します。
+type AzureStore struct {
+	account        string
+	key            []byte
+	endpointSuffix string
+	client         httpkit.ClientInterface
+}
+
+// NewAzureStore は、環境変数の認証情報と httpClient を使用して AzureStore を生成します。
+// AZURE_STORAGE_CONNECTION_STRING、または AZURE_STORAGE_ACCOUNT と AZURE_STORAGE_KEY を参照します。
+func NewAzureStore(httpClient httpkit.ClientInterface) (*AzureStore, error) {
+	account, key, suffix := os.Getenv("AZURE_STORAGE_ACCOUNT"), os.Getenv("AZURE_STORAGE_KEY"), ""
+	if conn := os.Getenv("AZURE_STORAGE_CONNECTION_STRING"); conn != "" {
+		for _, part := range strings.Split(conn, ";") {
+			name, value, _ := strings.Cut(part, "=")
+			switch name {
+			case "AccountName":
+				account = value
+			case "AccountKey":
+				key = value
+			case "EndpointSuffix":
+				suffix = value
+			}
+		}
+	}
+	if account == "" || key == "" {
+		return nil, fmt.Errorf("Azure Blob Storage の認証情報がありません。AZURE_STORAGE_CONNECTION_STRING、または AZURE_STORAGE_ACCOUNT と AZURE_STORAGE_KEY を設定してください")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("Azure Storage のアカウントキーの形式が不正です: %w", err)
+	}
+	if suffix == "" {
+		suffix = defaultAzureEndpointSuffix
+	}
+	return &AzureStore{account: account, key: decoded, endpointSuffix: suffix, client: httpClient}, nil
+}
+
+// parse は、az://container/blob または https://<account>.blob.<suffix>/container/blob 形式のURIを解決します。
+func (s *AzureStore) parse(uri string) (azureBlob, error) {
+	b, err := s.parseLocation(uri)
+	if err != nil {
+		return azureBlob{}, err
+	}
+	if b.name == "" {
+		return azureBlob{}, fmt.Errorf("URI '%s' にコンテナ名または Blob 名が含まれていません", uri)
+	}
+	return b, nil
+}
+
+// parseLocation は parse と同様にURIを解決します。Blob 名 (一覧取得ではプレフィックス) は空でも構いません。
+func (s *AzureStore) parseLocation(uri string) (azureBlob, error) {
+	b := azureBlob{account: s.account, host: s.account + ".blob." + s.endpointSuffix}
+	var rest string
+	if after, ok := strings.CutPrefix(uri, "az://"); ok {
+		rest = after
+	} else {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return azureBlob{}, fmt.Errorf("URI '%s' の形式が不正です: %w", uri, err)
+		}
+		m := azureHostPattern.FindStringSubmatch(u.Hostname())
+		if m == nil {
+			return azureBlob{}, fmt.Errorf("URI '%s' は Azure Blob Storage のURIではありません", uri)
+		}
+		if m[1] != s.account {
+			return azureBlob{}, fmt.Errorf("URI '%s' のストレージアカウント '%s' が認証情報のアカウント '%s' と一致しません", uri, m[1], s.account)
+		}
+		b.host, rest = u.Host, strings.TrimPrefix(u.Path, "/")
+	}
+	b.container, b.name, _ = strings.Cut(rest, "/")
+	if b.container == "" {
+		return azureBlob{}, fmt.Errorf("URI '%s' にコンテナ名が含まれていません", uri)
+	}
+	return b, nil
+}
+
+// sas は、Blob に対するサービス SAS トークン (クエリ文字列) を生成します。
+// permissions は r (読み取り)、c (作成)、w (書き込み)、d (削除) の組み合わせです。
+func (s *AzureStore) sas(b azureBlob, permissions string, expiration time.Duration) string {
+	return s.signSAS("/blob/"+b.account+"/"+b.container+"/"+b.name, "b", permissions, expiration)
+}
+
+// signSAS は、正規化されたリソース名 resource に対するサービス SAS トークンを生成します。
+// signedResource は Blob の場合 "b"、コンテナの場合 "c" です。
+func (s *AzureStore) signSAS(resource, signedResource, permissions string, expiration time.Duration) string {
+	now := time.Now().UTC()
+	start := now.Add(-azureClockSkew).Format(time.RFC3339)
+	expiry := now.Add(expiration).Format(time.RFC3339)
+
+	// https://learn.microsoft.com/rest/api/storageservices/create-service-sas (バージョン 2020-12-06 以降の形式)
+	stringToSign := strings.Join([]string{
+		permissions,
+		start,
+		expiry,
+		resource,
+		"", // signedIdentifier
+		"", // signedIP
+		"https",
+		azureAPIVersion,
+		signedResource,
+		"",                 // signedSnapshotTime
+		"",                 // signedEncryptionScope
+		"", "", "", "", "", // rscc, rscd, rsce, rscl, rsct
+	}, "\n")
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(stringToSign))
+
+	q := url.Values{}
+	q.Set("sv", azureAPIVersion)
+	q.Set("sr", signedResource)
+	q.Set("sp", permissions)
+	q.Set("st", start)
+	q.Set("se", expiry)
+	q.Set("spr", "https")
+	q.Set("sig", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return q.Encode()
+}
+
+// GenerateSignedURL は remoteio.URLSigner インターフェースの実装です。
+// method が GET の場合は読み取り、PUT の場合は作成・書き込みの権限を持つ SAS トークン付きのURLを返します。
+func (s *AzureStore) GenerateSignedURL(ctx context.Context, uri, method string, expiration time.Duration) (string, error) {
+	b, err := s.parse(uri)
+	if err != nil {
+		return "", err
+	}
+	permissions := "r"
+	if method == http.MethodPut {
+		permissions = "cw"
+	}
+	return b.url(s.sas(b, permissions, expiration)), nil
+}
+
+// PublicURL は、SAS トークンを付けない Blob の URL を返します。匿名の読み取りアクセスを許可したコンテナで使用します。
+func (s *AzureStore) PublicURL(uri string) (string, error) {
+	b, err := s.parse(uri)
+	if err != nil {
+		return "", err
+	}
+	return b.url(""), nil
+}
+
+// do は、Blob に対するリクエストを SAS トークンで認証して送信します。
+func (s *AzureStore) do(ctx context.Context, method, uri, permissions string, body []byte, header http.Header) (*http.Response, error) {
+	b, err := s.parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, b.url(s.sas(b, permissions, azureRequestSASExpiration)), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("x-ms-version", azureAPIVersion)
+	return s.client.Do(req)
+}
+
+// Exists は Store インターフェースの実装です。
+func (s *AzureStore) Exists(ctx context.Context, uri string) (bool, error) {
+	resp, err := s.do(ctx, http.MethodHead, uri, "r", nil, nil)
+	if err != nil {
+		return false, fmt.Errorf("Azure Blob '%s' の存在確認に失敗しました: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 300:
+		return false, fmt.Errorf("Azure Blob '%s' の存在確認に失敗しました: %w", uri, azureError(resp))
+	}
+	return true, nil
+}
+
+// Read は Store インターフェースの実装です。Version には ETag を返します。
+func (s *AzureStore) Read(ctx context.Context, uri string) (Object, error) {
+	resp, err := s.do(ctx, http.MethodGet, uri, "r", nil, nil)
+	if err != nil {
+		return Object{}, fmt.Errorf("Azure Blob '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return Object{}, ErrNotFound
+	case resp.StatusCode >= 300:
+		return Object{}, fmt.Errorf("Azure Blob '%s' の読み込みに失敗しました: %w", uri, azureError(resp))
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Object{}, fmt.Errorf("Azure Blob '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	return Object{Data: data, Version: resp.Header.Get("ETag")}, nil
+}
+
+// WriteIf は Store インターフェースの実装です。ETag の条件 (If-None-Match / If-Match) 付きで書き込みます。
+func (s *AzureStore) WriteIf(ctx context.Context, uri string, data []byte, contentType, version string) error {
+	header := http.Header{}
+	if version == "" {
+		header.Set("If-None-Match", "*")
+	} else {
+		header.Set("If-Match", version)
+	}
+	return s.put(ctx, uri, data, contentType, header)
+}
+
+// Write は Store インターフェースの実装です。レポート本体の公開にも使用します。
+func (s *AzureStore) Write(ctx context.Context, uri string, data []byte, contentType string) error {
+	return s.put(ctx, uri, data, contentType, http.Header{})
+}
+
+// WriteWithAttributes は AttributeWriter インターフェースの実装です。
+// Cache-Control は x-ms-blob-cache-control、Content-Encoding は x-ms-blob-content-encoding、メタデータは x-ms-meta-* として設定します。
+func (s *AzureStore) WriteWithAttributes(ctx context.Context, uri string, data []byte, contentType string, attrs Attributes) error {
+	header := http.Header{}
+	if attrs.CacheControl != "" {
+		header.Set("x-ms-blob-cache-control", attrs.CacheControl)
+	}
+	if attrs.ContentEncoding != "" {
+		header.Set("x-ms-blob-content-encoding", attrs.ContentEncoding)
+	}
+	for name, value := range attrs.Metadata {
+		header.Set("x-ms-meta-"+name, value)
+	}
+	return s.put(ctx, uri, data, contentType, header)
+}
+
+// put は、ブロック Blob として data を書き込みます。
+func (s *AzureStore) put(ctx context.Context, uri string, data []byte, contentType string, header http.Header) error {
+	header.Set("x-ms-blob-type", "BlockBlob")
+	header.Set("Content-Type", contentType)
+	resp, err := s.do(ctx, http.MethodPut, uri, "cw", data, header)
+	if err != nil {
+		return fmt.Errorf("Azure Blob '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	// If-None-Match: * の Blob が既に存在する場合は 409 (BlobAlreadyExists) が返る
+	case resp.StatusCode == http.StatusPreconditionFailed || resp.StatusCode == http.StatusConflict:
+		return ErrPreconditionFailed
+	case resp.StatusCode >= 300:
+		return fmt.Errorf("Azure Blob '%s' の書き込みに失敗しました: %w", uri, azureError(resp))
+	}
+	return nil
+}
+
+// azureBlobList は、List Blobs の応答のうち使用する部分です。
+type azureBlobList struct {
+	Blobs []struct {
+		Name         string `xml:"Name"`
+		LastModified string `xml:"Properties>Last-Modified"`
+	} `xml:"Blobs>Blob"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+// List は Lister インターフェースの実装です。コンテナに対する一覧取得の権限 (l) を持つ SAS トークンを使用します。
+func (s *AzureStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	b, err := s.parseLocation(prefix)
+	if err != nil {
+		return nil, err
+	}
+	// 返すURIは prefix と同じ形式 (az:// または https://) にする
+	base := "https://" + b.host + "/" + b.container + "/"
+	if strings.HasPrefix(prefix, "az://") {
+		base = "az://" + b.container + "/"
+	}
+
+	var objects []ObjectInfo
+	marker := ""
+	for {
+		q, err := url.ParseQuery(s.signSAS("/blob/"+b.account+"/"+b.container, "c", "l", azureRequestSASExpiration))
+		if err != nil {
+			return nil, err
+		}
+		q.Set("restype", "container")
+		q.Set("comp", "list")
+		q.Set("prefix", b.name)
+		if marker != "" {
+			q.Set("marker", marker)
+		}
+		u := url.URL{Scheme: "https", Host: b.host, Path: "/" + b.container, RawQuery: q.Encode()}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("x-ms-version", azureAPIVersion)
+
+		list, err := s.listPage(req)
+		if err != nil {
+			return nil, fmt.Errorf("Azure Blob '%s' の一覧取得に失敗しました: %w", prefix, err)
+		}
+		for _, blob := range list.Blobs {
+			updated, _ := http.ParseTime(blob.LastModified)
+			objects = append(objects, ObjectInfo{URI: base + blob.Name, Updated: updated})
+		}
+		if list.NextMarker == "" {
+			break
+		}
+		marker = list.NextMarker
+	}
+	return objects, nil
+}
+
+// listPage は、List Blobs のリクエストを送信し、1ページ分の応答を解析します。
+func (s *AzureStore) listPage(req *http.Request) (azureBlobList, error) {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return azureBlobList{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return azureBlobList{}, azureError(resp)
+	}
+	var list azureBlobList
+	if err := xml.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return azureBlobList{}, err
+	}
+	return list, nil
+}
+
+// Delete は Lister インターフェースの実装です。
+func (s *AzureStore) Delete(ctx context.Context, uri string) error {
+	resp, err := s.do(ctx, http.MethodDelete, uri, "d", nil, nil)
+	if err != nil {
+		return fmt.Errorf("Azure Blob '%s' の削除に失敗しました: %w", uri, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("Azure Blob '%s' の削除に失敗しました: %w", uri, azureError(resp))
+	}
+	return nil
+}
+
+// Close は Store インターフェースの実装です。
+func (s *AzureStore) Close() error {
+	return nil
+}
+
+// azureError は、エラー応答のステータスとエラーコードからエラーを生成します。
+func azureError(resp *http.Response) error {
+	if code := resp.Header.Get("x-ms-error-code"); code != "" {
+		return fmt.Errorf("%s (%s)", resp.Status, code)
+	}
+	return fmt.Errorf("%s", resp.Status)
+}
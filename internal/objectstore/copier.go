@@ -0,0 +1,124 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ErrCopyUnsupported は、使用中の Store がサーバー側でのオブジェクトのコピーに対応していないことを示すエラーです。
+var ErrCopyUnsupported = errors.New("使用中のストレージはオブジェクトのコピーに対応していません")
+
+const (
+	// azureCopyPollInterval は、Azure Blob Storage の非同期のコピーの完了を確認する間隔です。
+	azureCopyPollInterval = 500 * time.Millisecond
+	// azureCopyTimeout は、Azure Blob Storage の非同期のコピーの完了を待つ時間の上限です。
+	azureCopyTimeout = 2 * time.Minute
+)
+
+// Copier は、サーバー側でオブジェクトをコピーできる Store が追加で実装するインターフェースです。
+// 一時的なオブジェクトに書き込んでから公開先にコピーする公開 (--atomic-publish) に使用します。利用側は型アサーションで対応状況を確認してください。
+type Copier interface {
+	// Copy は、src のオブジェクトを内容・Content-Type・Cache-Control・Content-Encoding・メタデータを保ったまま dst にコピーします。
+	// dst に既存のオブジェクトがある場合は置き換えます。dst は、コピーが完了した時点で新しい内容に切り替わります。
+	Copy(ctx context.Context, src, dst string) error
+}
+
+// Copy は Copier インターフェースの実装です。
+func (s *gcsStore) Copy(ctx context.Context, src, dst string) error {
+	srcBucket, srcKey, err := splitURI(src)
+	if err != nil {
+		return err
+	}
+	dstBucket, dstKey, err := splitURI(dst)
+	if err != nil {
+		return err
+	}
+
+	// 条件なしのコピーは既定では再試行されないが、同じ内容で上書きするだけのため常に再試行する
+	dstObj := s.client.Bucket(dstBucket).Object(dstKey).Retryer(storage.WithPolicy(storage.RetryAlways))
+	c := dstObj.CopierFrom(s.client.Bucket(srcBucket).Object(srcKey))
+	c.DestinationKMSKeyName = gcsKMSKey
+	if _, err := c.Run(ctx); err != nil {
+		return fmt.Errorf("GCSオブジェクト '%s' から '%s' へのコピーに失敗しました: %w", src, dst, err)
+	}
+	return nil
+}
+
+// Copy は Copier インターフェースの実装です。メタデータはコピー元のものを引き継ぎ (MetadataDirective: COPY)、暗号化の設定は再度適用します。
+func (s *s3Store) Copy(ctx context.Context, src, dst string) error {
+	srcBucket, srcKey, err := splitURI(src)
+	if err != nil {
+		return err
+	}
+	dstBucket, dstKey, err := splitURI(dst)
+	if err != nil {
+		return err
+	}
+
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(srcBucket + "/" + (&url.URL{Path: srcKey}).EscapedPath()),
+	}
+	input.ServerSideEncryption, input.SSEKMSKeyId = s3Encryption()
+	if _, err := s.client.CopyObject(ctx, input); err != nil {
+		return fmt.Errorf("S3オブジェクト '%s' から '%s' へのコピーに失敗しました: %w", src, dst, err)
+	}
+	return nil
+}
+
+// Copy は Copier インターフェースの実装です (Copy Blob)。
+// 同じストレージアカウント内のコピーは通常すぐに完了しますが、非同期で処理された場合 (x-ms-copy-status: pending) は完了を待ちます。
+func (s *AzureStore) Copy(ctx context.Context, src, dst string) error {
+	srcURL, err := s.GenerateSignedURL(ctx, src, http.MethodGet, azureRequestSASExpiration)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	header.Set("x-ms-copy-source", srcURL)
+	resp, err := s.do(ctx, http.MethodPut, dst, "cw", nil, header)
+	if err != nil {
+		return fmt.Errorf("Azure Blob '%s' から '%s' へのコピーに失敗しました: %w", src, dst, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Azure Blob '%s' から '%s' へのコピーに失敗しました: %w", src, dst, azureError(resp))
+	}
+
+	status := resp.Header.Get("x-ms-copy-status")
+	deadline := time.Now().Add(azureCopyTimeout)
+	for status == "pending" {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Azure Blob '%s' から '%s' へのコピーが %s 以内に完了しませんでした", src, dst, azureCopyTimeout)
+		}
+		timer := time.NewTimer(azureCopyPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := s.do(ctx, http.MethodHead, dst, "r", nil, nil)
+		if err != nil {
+			return fmt.Errorf("Azure Blob '%s' のコピーの状態の確認に失敗しました: %w", dst, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("Azure Blob '%s' のコピーの状態の確認に失敗しました: %w", dst, azureError(resp))
+		}
+		status = resp.Header.Get("x-ms-copy-status")
+	}
+	if status != "" && status != "success" {
+		return fmt.Errorf("Azure Blob '%s' から '%s' へのコピーに失敗しました (x-ms-copy-status: %s)", src, dst, status)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+)
+
+// gcsChunkSize は、GCS へのアップロードの1リクエストあたりの最大サイズです (256KiB の倍数)。
+// これより大きいオブジェクトは再開可能なアップロード (resumable upload) で分割して送信し、一時的なエラーは失敗したチャンクから再送します。
+const gcsChunkSize = 4 * 1024 * 1024
+
+// gcsStore は、Google Cloud Storage 上のオブジェクトを読み書きする Store の実装です。
+type gcsStore struct {
+	client *storage.Client
+}
+
+// newGCSStore は、アプリケーションのデフォルト認証情報を使用して gcsStore を生成します。
+func newGCSStore(ctx context.Context) (*gcsStore, error) {
+	var opts []option.ClientOption
+	if httpClient != nil {
+		// option.WithHTTPClient を指定すると認証が行われないため、設定を適用した Transport を認証付きの Transport でラップする
+		transport, err := htransport.NewTransport(ctx, httpClient.Transport, option.WithScopes(storage.ScopeFullControl))
+		if err != nil {
+			return nil, fmt.Errorf("GCSクライアントの認証の初期化に失敗しました: %w", err)
+		}
+		opts = append(opts, option.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	client, err := storage.NewClient(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("GCSクライアントの初期化に失敗しました: %w", err)
+	}
+	return &gcsStore{client: client}, nil
+}
+
+// Exists は Store インターフェースの実装です。
+func (s *gcsStore) Exists(ctx context.Context, uri string) (bool, error) {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.client.Bucket(bucket).Object(key).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("GCSオブジェクト '%s' の存在確認に失敗しました: %w", uri, err)
+	}
+	return true, nil
+}
+
+// Read は Store インターフェースの実装です。Version には世代番号 (generation) を返します。
+func (s *gcsStore) Read(ctx context.Context, uri string) (Object, error) {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return Object{}, err
+	}
+
+	r, err := s.client.Bucket(bucket).Object(key).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, fmt.Errorf("GCSオブジェクト '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Object{}, fmt.Errorf("GCSオブジェクト '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	return Object{Data: data, Version: strconv.FormatInt(r.Attrs.Generation, 10)}, nil
+}
+
+// WriteIf は Store インターフェースの実装です。世代番号の条件 (DoesNotExist / GenerationMatch) 付きで書き込みます。
+func (s *gcsStore) WriteIf(ctx context.Context, uri string, data []byte, contentType, version string) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+
+	cond := storage.Conditions{DoesNotExist: true}
+	if version != "" {
+		generation, err := strconv.ParseInt(version, 10, 64)
+		if err != nil {
+			return fmt.Errorf("GCSオブジェクトの世代番号 '%s' が不正です: %w", version, err)
+		}
+		cond = storage.Conditions{GenerationMatch: generation}
+	}
+
+	w := s.client.Bucket(bucket).Object(key).If(cond).NewWriter(ctx)
+	w.ChunkSize = gcsChunkSize
+	w.ContentType = contentType
+	w.KMSKeyName = gcsKMSKey
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("GCSオブジェクト '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	if err := w.Close(); err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusPreconditionFailed {
+			return ErrPreconditionFailed
+		}
+		return fmt.Errorf("GCSオブジェクト '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// Write は Store インターフェースの実装です。
+func (s *gcsStore) Write(ctx context.Context, uri string, data []byte, contentType string) error {
+	return s.WriteWithAttributes(ctx, uri, data, contentType, Attributes{})
+}
+
+// WriteWithAttributes は AttributeWriter インターフェースの実装です。
+func (s *gcsStore) WriteWithAttributes(ctx context.Context, uri string, data []byte, contentType string, attrs Attributes) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+
+	// 条件なしの書き込みは既定では再試行されないが、同じ内容で上書きするだけのため常に再試行する
+	w := s.client.Bucket(bucket).Object(key).Retryer(storage.WithPolicy(storage.RetryAlways)).NewWriter(ctx)
+	w.ChunkSize = gcsChunkSize
+	w.ContentType = contentType
+	w.KMSKeyName = gcsKMSKey
+	w.CacheControl = attrs.CacheControl
+	w.ContentEncoding = attrs.ContentEncoding
+	w.Metadata = attrs.Metadata
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return fmt.Errorf("GCSオブジェクト '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("GCSオブジェクト '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// Close は Store インターフェースの実装です。
+func (s *gcsStore) Close() error {
+	return s.client.Close()
+}
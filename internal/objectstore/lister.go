@@ -0,0 +1,167 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"google.golang.org/api/iterator"
+)
+
+// ErrListUnsupported は、使用中の Store がオブジェクトの一覧取得と削除に対応していないことを示すエラーです。
+var ErrListUnsupported = errors.New("使用中のストレージはオブジェクトの一覧取得と削除に対応していません")
+
+// ObjectInfo は、一覧取得したオブジェクトのURIと最終更新日時です。
+type ObjectInfo struct {
+	URI     string
+	Updated time.Time
+}
+
+// Lister は、プレフィックス配下のオブジェクトの一覧取得と削除ができる Store が追加で実装するインターフェースです。
+// 公開済みのレポートの保持期間 (--retention) の適用に使用します。利用側は型アサーションで対応状況を確認してください。
+type Lister interface {
+	// List は、URIが prefix で始まるオブジェクトを返します (prefix より下の階層のオブジェクトを含みます)。
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete は、オブジェクトを削除します。存在しない場合は何もしません。
+	Delete(ctx context.Context, uri string) error
+}
+
+// splitPrefix は、"gs://bucket/path/to/" 形式のURIをバケット名とキーのプレフィックスに分割します。プレフィックスは空でも構いません。
+func splitPrefix(uri string) (string, string, error) {
+	_, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return "", "", fmt.Errorf("URI '%s' の形式が不正です", uri)
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("URI '%s' にバケット名が含まれていません", uri)
+	}
+	return bucket, prefix, nil
+}
+
+// List は Lister インターフェースの実装です。
+func (s *gcsStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	bucket, keyPrefix, err := splitPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	it := s.client.Bucket(bucket).Objects(ctx, &storage.Query{Prefix: keyPrefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("GCSオブジェクト '%s' の一覧取得に失敗しました: %w", prefix, err)
+		}
+		objects = append(objects, ObjectInfo{URI: "gs://" + bucket + "/" + attrs.Name, Updated: attrs.Updated})
+	}
+	return objects, nil
+}
+
+// Delete は Lister インターフェースの実装です。
+func (s *gcsStore) Delete(ctx context.Context, uri string) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+	err = s.client.Bucket(bucket).Object(key).Delete(ctx)
+	if err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("GCSオブジェクト '%s' の削除に失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// List は Lister インターフェースの実装です。
+func (s *s3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	bucket, keyPrefix, err := splitPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	input := &s3.ListObjectsV2Input{Bucket: aws.String(bucket), Prefix: aws.String(keyPrefix)}
+	for {
+		out, err := s.client.ListObjectsV2(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("S3オブジェクト '%s' の一覧取得に失敗しました: %w", prefix, err)
+		}
+		for _, obj := range out.Contents {
+			objects = append(objects, ObjectInfo{URI: "s3://" + bucket + "/" + aws.ToString(obj.Key), Updated: aws.ToTime(obj.LastModified)})
+		}
+		if !aws.ToBool(out.IsTruncated) {
+			break
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+	return objects, nil
+}
+
+// Delete は Lister インターフェースの実装です。S3 は存在しないオブジェクトの削除も成功として扱います。
+func (s *s3Store) Delete(ctx context.Context, uri string) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)}); err != nil {
+		return fmt.Errorf("S3オブジェクト '%s' の削除に失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// List は Lister インターフェースの実装です。
+// ローカルファイルでは、prefix にはディレクトリ (空または末尾が "/") を指定します。返すURIは prefix と同じ形式 (file:// の有無) にします。
+func (s *localStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return nil, fmt.Errorf("ローカルファイルの一覧取得にはディレクトリを指定してください: %s", prefix)
+	}
+	dir := LocalPath(prefix)
+	if dir == "" {
+		dir = "."
+	}
+
+	var objects []ObjectInfo
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{URI: prefix + filepath.ToSlash(rel), Updated: info.ModTime()})
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ディレクトリ '%s' の一覧取得に失敗しました: %w", dir, err)
+	}
+	return objects, nil
+}
+
+// Delete は Lister インターフェースの実装です。
+func (s *localStore) Delete(ctx context.Context, uri string) error {
+	if err := os.Remove(LocalPath(uri)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("ファイル '%s' の削除に失敗しました: %w", uri, err)
+	}
+	return nil
+}
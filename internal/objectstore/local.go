@@ -0,0 +1,141 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// IsLocal は、URIがローカルファイル (file:// またはスキームなしのパス) を指すかを返します。
+func IsLocal(uri string) bool {
+	return strings.HasPrefix(uri, "file://") || !strings.Contains(uri, "://")
+}
+
+// LocalPath は、file:// のURIまたはパスをローカルファイルのパスに変換します。
+func LocalPath(uri string) string {
+	return filepath.FromSlash(strings.TrimPrefix(uri, "file://"))
+}
+
+// localStore は、ローカルファイルを読み書きする Store の実装です。
+// クラウドストレージを持たない環境でも、公開先の衝突検出と重複排除マーカーを使用するためのものです。
+type localStore struct{}
+
+// newLocalStore は localStore を生成します。
+func newLocalStore() *localStore {
+	return &localStore{}
+}
+
+// Exists は Store インターフェースの実装です。
+func (s *localStore) Exists(ctx context.Context, uri string) (bool, error) {
+	_, err := os.Stat(LocalPath(uri))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("ファイル '%s' の存在確認に失敗しました: %w", uri, err)
+	}
+	return true, nil
+}
+
+// Read は Store インターフェースの実装です。Version には内容の SHA-256 を返します。
+func (s *localStore) Read(ctx context.Context, uri string) (Object, error) {
+	data, err := os.ReadFile(LocalPath(uri))
+	if errors.Is(err, os.ErrNotExist) {
+		return Object{}, ErrNotFound
+	}
+	if err != nil {
+		return Object{}, fmt.Errorf("ファイル '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	return Object{Data: data, Version: contentVersion(data)}, nil
+}
+
+// WriteIf は Store インターフェースの実装です。
+// 一時ファイルに書き込んだ後、version が空の場合はハードリンク (既に存在すれば失敗) で、
+// それ以外の場合は現在の内容の SHA-256 を確認してから置き換えます。
+// 内容の確認と置き換えの間に他のプロセスが書き込んだ場合は検出できません。
+func (s *localStore) WriteIf(ctx context.Context, uri string, data []byte, contentType, version string) error {
+	path := LocalPath(uri)
+	tmp, err := writeTemp(path, data)
+	if err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	defer os.Remove(tmp)
+
+	if version == "" {
+		if err := os.Link(tmp, path); err != nil {
+			if errors.Is(err, os.ErrExist) {
+				return ErrPreconditionFailed
+			}
+			return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", uri, err)
+		}
+		return nil
+	}
+
+	current, err := s.Read(ctx, uri)
+	if errors.Is(err, ErrNotFound) || (err == nil && current.Version != version) {
+		return ErrPreconditionFailed
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// Write は Store インターフェースの実装です。書き込み途中の内容を読まれないよう、一時ファイル経由で置き換えます。
+func (s *localStore) Write(ctx context.Context, uri string, data []byte, contentType string) error {
+	path := LocalPath(uri)
+	tmp, err := writeTemp(path, data)
+	if err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	defer os.Remove(tmp)
+	// os.CreateTemp は 0600 で作成するため、通常のファイルと同じ権限にする
+	if err := os.Chmod(tmp, 0o644); err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("ファイル '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// Close は Store インターフェースの実装です。
+func (s *localStore) Close() error {
+	return nil
+}
+
+// contentVersion は、ファイルの内容から条件付き書き込みに使用するバージョンを求めます。
+func contentVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeTemp は、path と同じディレクトリに一時ファイルを作成して data を書き込み、そのパスを返します。
+// 同じファイルシステム上に作成することで、Rename と Link で置き換えられるようにします。
+func writeTemp(path string, data []byte) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
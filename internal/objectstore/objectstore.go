@@ -0,0 +1,92 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/shouni/go-http-kit/pkg/httpkit"
+)
+
+var (
+	// ErrNotFound は、指定されたオブジェクトが存在しないことを示すエラーです。
+	ErrNotFound = errors.New("オブジェクトが存在しません")
+	// ErrPreconditionFailed は、条件付き書き込みの前提条件 (バージョンの一致・非存在) を満たさなかったことを示すエラーです。
+	ErrPreconditionFailed = errors.New("条件付き書き込みの前提条件を満たしませんでした")
+	// ErrUnsupportedScheme は、URIのスキームが直接のオブジェクト操作に対応していないことを示すエラーです。
+	ErrUnsupportedScheme = errors.New("オブジェクト操作に対応していないURIスキームです")
+)
+
+// httpClient は、GCS・S3 のクライアントに使用する *http.Client です (ConfigureHTTPClient)。nil の場合は SDK の既定のクライアントを使用します。
+var httpClient *http.Client
+
+// ConfigureHTTPClient は、GCS・S3 への接続に使用する *http.Client を設定します。
+// AWS SDK と GCS クライアントは http.DefaultTransport を使用せず独自の Transport を構築するため、
+// 社内CAやプロキシの設定を反映するには、設定を適用したクライアントを明示的に渡す必要があります。
+func ConfigureHTTPClient(client *http.Client) {
+	httpClient = client
+}
+
+// apiClient は、Azure Blob Storage の REST API の呼び出しに使用する httpkit のクライアントです (ConfigureAPIClient)。
+var apiClient httpkit.ClientInterface
+
+// ConfigureAPIClient は、Azure Blob Storage の REST API の呼び出しに使用するクライアントを設定します。
+// コマンドの実行前に生成する httpkit のクライアント (http.DefaultTransport にプロキシとTLSの設定を適用済み) を渡します。
+func ConfigureAPIClient(client httpkit.ClientInterface) {
+	apiClient = client
+}
+
+// Object は、ストレージから読み込んだオブジェクトの内容とバージョンです。
+// Version は GCS では世代番号、S3 と Azure では ETag、ローカルファイルでは内容の SHA-256 で、条件付き書き込みに使用します。
+type Object struct {
+	Data    []byte
+	Version string
+}
+
+// Store は、レポート本体の公開とは別に、ストレージ上の補助的なオブジェクト (マーカーなど) を直接読み書きするインターフェースです。
+// HTML のレポート本体の公開は gemini-reviewer-core の Publisher が担います (Markdown 形式の公開には Write を使用します)。
+type Store interface {
+	// Exists は、オブジェクトが存在するかを返します。
+	Exists(ctx context.Context, uri string) (bool, error)
+	// Read は、オブジェクトの内容とバージョンを返します。存在しない場合は ErrNotFound を返します。
+	Read(ctx context.Context, uri string) (Object, error)
+	// WriteIf は、version が空の場合はオブジェクトが存在しないときのみ、
+	// それ以外の場合は現在のバージョンが version と一致するときのみ書き込みます。
+	// 条件を満たさない場合は ErrPreconditionFailed を返します。
+	WriteIf(ctx context.Context, uri string, data []byte, contentType, version string) error
+	// Write は、条件なしでオブジェクトを書き込みます (既存のオブジェクトは上書きします)。
+	Write(ctx context.Context, uri string, data []byte, contentType string) error
+	// Close は、内部のクライアントを解放します。
+	Close() error
+}
+
+// New は、URIのスキーム (gs://, s3://, az://, file:// またはローカルパス) に応じた Store を生成します。
+func New(ctx context.Context, uri string) (Store, error) {
+	switch {
+	case IsAzureURI(uri):
+		return NewAzureStore(apiClient)
+	case IsLocal(uri):
+		return newLocalStore(), nil
+	case strings.HasPrefix(uri, "gs://"):
+		return newGCSStore(ctx)
+	case strings.HasPrefix(uri, "s3://"):
+		return newS3Store(ctx)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, uri)
+	}
+}
+
+// splitURI は、"gs://bucket/path/to/object" 形式のURIをバケット名とオブジェクトキーに分割します。
+func splitURI(uri string) (string, string, error) {
+	_, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return "", "", fmt.Errorf("URI '%s' の形式が不正です", uri)
+	}
+	bucket, key, _ := strings.Cut(rest, "/")
+	if bucket == "" || key == "" {
+		return "", "", fmt.Errorf("URI '%s' にバケット名またはオブジェクトキーが含まれていません", uri)
+	}
+	return bucket, key, nil
+}
@@ -0,0 +1,237 @@
+package objectstore
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// defaultS3Region は、AWS_REGION が未設定の場合に使用するリージョンです (公開URLの変換と同じ既定値)。
+const defaultS3Region = "ap-northeast-1"
+
+// s3Endpoint と s3PathStyle は、S3 互換ストレージ (MinIO, Cloudflare R2 など) に接続する場合の設定です (ConfigureS3)。
+var (
+	s3Endpoint  string
+	s3PathStyle bool
+)
+
+// ConfigureS3 は、s3:// のURIの接続先を S3 互換ストレージのエンドポイントに変更します。
+// endpoint が空の場合は、環境変数 AWS_ENDPOINT_URL_S3 を使用します (どちらも未設定の場合は Amazon S3)。
+// pathStyle が true の場合は、バケット名をホスト名ではなくパスに含めるパス形式でアクセスします (MinIO などで必要)。
+func ConfigureS3(endpoint string, pathStyle bool) {
+	s3Endpoint = strings.TrimSuffix(cmp.Or(strings.TrimSpace(endpoint), os.Getenv("AWS_ENDPOINT_URL_S3")), "/")
+	s3PathStyle = pathStyle
+}
+
+// S3Endpoint は、S3 互換ストレージのエンドポイントを返します。Amazon S3 を使用する場合は空文字列を返します。
+func S3Endpoint() string {
+	return s3Endpoint
+}
+
+// s3Store は、Amazon S3 (または S3 互換ストレージ) 上のオブジェクトを読み書きする Store の実装です。
+type s3Store struct {
+	client *s3.Client
+}
+
+// newS3Store は、AWS の既定の認証情報チェーンを使用して s3Store を生成します。
+func newS3Store(ctx context.Context) (*s3Store, error) {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = defaultS3Region
+	}
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if httpClient != nil {
+		opts = append(opts, awsconfig.WithHTTPClient(httpClient))
+	}
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("AWS設定の読み込みに失敗しました: %w", err)
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if s3Endpoint != "" {
+			o.BaseEndpoint = aws.String(s3Endpoint)
+		}
+		o.UsePathStyle = s3PathStyle
+	})
+	return &s3Store{client: client}, nil
+}
+
+// GenerateSignedURL は remoteio.URLSigner インターフェースの実装です。
+// S3 互換ストレージのバケットは既定で非公開のため、通知には有効期限付きの署名付きURL (SigV4) を使用します。
+func (s *s3Store) GenerateSignedURL(ctx context.Context, uri, method string, expiration time.Duration) (string, error) {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return "", err
+	}
+
+	presigner := s3.NewPresignClient(s.client, s3.WithPresignExpires(expiration))
+	var req *v4.PresignedHTTPRequest
+	if method == http.MethodPut {
+		req, err = presigner.PresignPutObject(ctx, &s3.PutObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	} else {
+		req, err = presigner.PresignGetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	}
+	if err != nil {
+		return "", fmt.Errorf("S3オブジェクト '%s' の署名付きURLの生成に失敗しました: %w", uri, err)
+	}
+	return req.URL, nil
+}
+
+// S3EndpointURL は、S3 互換ストレージのエンドポイントにおける、署名なしのオブジェクトのURLを返します。
+// パス形式の場合は {endpoint}/{bucket}/{key}、それ以外の場合は {scheme}://{bucket}.{host}/{key} の形式です。
+func S3EndpointURL(uri string) (string, error) {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return "", err
+	}
+	if s3PathStyle {
+		return s3Endpoint + "/" + bucket + "/" + key, nil
+	}
+	scheme, host, ok := strings.Cut(s3Endpoint, "://")
+	if !ok {
+		return "", fmt.Errorf("S3 互換ストレージのエンドポイント '%s' の形式が不正です", s3Endpoint)
+	}
+	return scheme + "://" + bucket + "." + host + "/" + key, nil
+}
+
+// Exists は Store インターフェースの実装です。
+func (s *s3Store) Exists(ctx context.Context, uri string) (bool, error) {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("S3オブジェクト '%s' の存在確認に失敗しました: %w", uri, err)
+	}
+	return true, nil
+}
+
+// Read は Store インターフェースの実装です。Version には ETag を返します。
+func (s *s3Store) Read(ctx context.Context, uri string) (Object, error) {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return Object{}, err
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return Object{}, ErrNotFound
+		}
+		return Object{}, fmt.Errorf("S3オブジェクト '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return Object{}, fmt.Errorf("S3オブジェクト '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	return Object{Data: data, Version: aws.ToString(out.ETag)}, nil
+}
+
+// WriteIf は Store インターフェースの実装です。S3 の条件付き書き込み (If-None-Match / If-Match) を使用します。
+func (s *s3Store) WriteIf(ctx context.Context, uri string, data []byte, contentType, version string) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	}
+	applyS3Encryption(input)
+	if version == "" {
+		input.IfNoneMatch = aws.String("*")
+	} else {
+		input.IfMatch = aws.String(version)
+	}
+
+	if _, err := s.client.PutObject(ctx, input); err != nil {
+		if isS3PreconditionFailed(err) {
+			return ErrPreconditionFailed
+		}
+		return fmt.Errorf("S3オブジェクト '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// Write は Store インターフェースの実装です。
+func (s *s3Store) Write(ctx context.Context, uri string, data []byte, contentType string) error {
+	return s.WriteWithAttributes(ctx, uri, data, contentType, Attributes{})
+}
+
+// WriteWithAttributes は AttributeWriter インターフェースの実装です。
+func (s *s3Store) WriteWithAttributes(ctx context.Context, uri string, data []byte, contentType string, attrs Attributes) error {
+	bucket, key, err := splitURI(uri)
+	if err != nil {
+		return err
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+		Metadata:    attrs.Metadata,
+	}
+	if attrs.CacheControl != "" {
+		input.CacheControl = aws.String(attrs.CacheControl)
+	}
+	if attrs.ContentEncoding != "" {
+		input.ContentEncoding = aws.String(attrs.ContentEncoding)
+	}
+	applyS3Encryption(input)
+	_, err = s.client.PutObject(ctx, input)
+	if err != nil {
+		return fmt.Errorf("S3オブジェクト '%s' の書き込みに失敗しました: %w", uri, err)
+	}
+	return nil
+}
+
+// Close は Store インターフェースの実装です。S3 クライアントは解放処理を必要としません。
+func (s *s3Store) Close() error {
+	return nil
+}
+
+// isS3PreconditionFailed は、条件付き書き込みが前提条件の不一致 (または同時書き込みの競合) で失敗したかを判定します。
+func isS3PreconditionFailed(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.ErrorCode() {
+	case "PreconditionFailed", "ConditionalRequestConflict":
+		return true
+	}
+	return false
+}
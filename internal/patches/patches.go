@@ -0,0 +1,108 @@
+package patches
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+)
+
+// SectionHeading は、レポートに追加する修正案のセクションの見出しです。
+const SectionHeading = "## 🩹 修正案"
+
+// Suggestion は、1件の指摘事項に対する、unified diff 形式の修正案です。
+type Suggestion struct {
+	Finding findings.Finding
+	Patch   string // `git apply` で適用できる unified diff (末尾は改行)
+}
+
+var (
+	// fencePattern は、AI の応答から diff のコードブロックを抽出する正規表現です。
+	fencePattern = regexp.MustCompile("(?s)```(?:diff|patch)?[ \t]*\n(.*?)\n```")
+	// suggestionPattern は、レポートの修正案の見出しと diff のコードブロックを抽出する正規表現です。
+	suggestionPattern = regexp.MustCompile("(?s)### 修正案 \\d+: ([^\n]*)\n(.*?)```diff\n(.*?)\n```")
+	// locationPattern は、修正案の場所の行 ("- 場所: `path:line`") を抽出する正規表現です。
+	locationPattern = regexp.MustCompile("- 場所: `([^`:]+)(?::(\\d+))?`")
+	// severityPattern は、修正案の深刻度の行 ("- 深刻度: HIGH") を抽出する正規表現です。
+	severityPattern = regexp.MustCompile(`- 深刻度: (\w+)`)
+)
+
+// Extract は、AI の応答から unified diff を取り出します。diff のコードブロックがあればその内容を、
+// なければ "diff --git" または "--- " で始まる行以降を返します。diff が含まれない場合は空文字を返します。
+func Extract(reply string) string {
+	patch := ""
+	if m := fencePattern.FindStringSubmatch(reply); m != nil {
+		patch = m[1]
+	} else {
+		for _, marker := range []string{"diff --git ", "--- "} {
+			if strings.HasPrefix(reply, marker) {
+				patch = reply
+				break
+			}
+			if i := strings.Index(reply, "\n"+marker); i >= 0 {
+				patch = reply[i+1:]
+				break
+			}
+		}
+	}
+	patch = strings.TrimSpace(patch)
+	if !strings.Contains(patch, "\n@@ ") {
+		return ""
+	}
+	return patch + "\n"
+}
+
+// Paths は、パッチが変更するファイルのパス (変更後のパス) を返します。
+func Paths(patch string) []string {
+	var paths []string
+	for _, f := range diffutil.ParseFiles(patch) {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+// Section は、修正案の一覧をレポートに追加するセクションとして出力します。修正案がない場合は空文字を返します。
+// apply-fixes コマンドで保存したレポートから修正案を取り出せるよう、Parse で読み戻せる形式で出力します。
+func Section(list []Suggestion) string {
+	if len(list) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(SectionHeading + "\n\n")
+	b.WriteString("以下の修正案は、レビュー対象のブランチに `git apply --check` で適用できることを確認済みです。`apply-fixes` コマンドでローカルのクローンに適用できます。\n")
+	for i, s := range list {
+		fmt.Fprintf(&b, "\n### 修正案 %d: %s\n\n", i+1, strings.TrimSpace(s.Finding.Title))
+		fmt.Fprintf(&b, "- 深刻度: %s\n", s.Finding.Severity)
+		if s.Finding.File != "" {
+			location := s.Finding.File
+			if s.Finding.Line > 0 {
+				location = fmt.Sprintf("%s:%d", s.Finding.File, s.Finding.Line)
+			}
+			fmt.Fprintf(&b, "- 場所: `%s`\n", location)
+		}
+		fmt.Fprintf(&b, "\n```diff\n%s```\n", s.Patch)
+	}
+	return b.String()
+}
+
+// Parse は、Section で出力した修正案をレポートから読み戻します。複数モードの結果のように、
+// 複数の修正案のセクションが含まれる場合は、レポートに現れる順にすべて返します。
+func Parse(report string) []Suggestion {
+	var list []Suggestion
+	for _, m := range suggestionPattern.FindAllStringSubmatch(report, -1) {
+		f := findings.Finding{Title: strings.TrimSpace(m[1])}
+		if sm := severityPattern.FindStringSubmatch(m[2]); sm != nil {
+			f.Severity, _ = findings.ParseSeverity(sm[1])
+		}
+		if lm := locationPattern.FindStringSubmatch(m[2]); lm != nil {
+			f.File = lm[1]
+			f.Line, _ = strconv.Atoi(lm[2])
+		}
+		list = append(list, Suggestion{Finding: f, Patch: m[3] + "\n"})
+	}
+	return list
+}
@@ -0,0 +1,134 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/patches"
+	"git-gemini-cli/internal/session"
+)
+
+// ApplyFixes は、最後に実行したレビューの結果から修正案を読み込み、選択された修正案をローカルのクローン (cfg.LocalPath) に適用します。
+// selection ("all" または "1,3-4" 形式の番号) が空の場合は、修正案ごとに in から適用するかを尋ねます。
+// newBranch が指定された場合は、レビュー対象のブランチからそのブランチを作成し、適用した修正案をコミットします。
+func ApplyFixes(ctx context.Context, cfg config.ReviewConfig, in io.Reader, out io.Writer, selection, newBranch string) error {
+	path, err := session.ResolvePath(cfg.SessionFile)
+	if err != nil {
+		return err
+	}
+	review, err := session.Load(path)
+	if err != nil {
+		return err
+	}
+	list := patches.Parse(review.Report)
+	if len(list) == 0 {
+		return fmt.Errorf("保存されたレビュー結果に修正案がありません。--suggest-patches を指定してレビューを実行してください")
+	}
+
+	var selected []patches.Suggestion
+	if selection != "" {
+		if selected, err = selectSuggestions(list, selection); err != nil {
+			return err
+		}
+	} else {
+		if selected, err = askSuggestions(list, in, out); err != nil {
+			return err
+		}
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(out, "適用する修正案が選択されなかったため、終了します。")
+		return nil
+	}
+
+	applyRunner, err := builder.BuildApplyFixesRunner(cfg)
+	if err != nil {
+		return fmt.Errorf("修正案の適用実行器の構築に失敗しました: %w", err)
+	}
+	result, err := applyRunner.Apply(ctx, cfg, review, selected, newBranch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%d 件の修正案を %s に適用しました。\n", len(result.Applied), cfg.LocalPath)
+	for _, s := range result.Failed {
+		fmt.Fprintf(out, "- 適用できませんでした: %s\n", s.Finding.Title)
+	}
+	if result.Commit != "" {
+		fmt.Fprintf(out, "ブランチ '%s' にコミット %s を作成しました。\n", newBranch, result.Commit)
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d 件の修正案を適用できませんでした", len(result.Failed))
+	}
+	return nil
+}
+
+// selectSuggestions は、"all" または "1,3-4" 形式の番号 (1始まり) で修正案を選択します。
+func selectSuggestions(list []patches.Suggestion, selection string) ([]patches.Suggestion, error) {
+	if strings.EqualFold(strings.TrimSpace(selection), "all") {
+		return list, nil
+	}
+
+	chosen := make([]bool, len(list))
+	for _, part := range strings.Split(selection, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+		start, err1 := strconv.Atoi(strings.TrimSpace(from))
+		end, err2 := strconv.Atoi(strings.TrimSpace(to))
+		if err1 != nil || err2 != nil || start < 1 || end > len(list) || start > end {
+			return nil, fmt.Errorf("修正案の番号 '%s' は不正です (1〜%d の番号、範囲または all を指定してください)", part, len(list))
+		}
+		for i := start; i <= end; i++ {
+			chosen[i-1] = true
+		}
+	}
+
+	var selected []patches.Suggestion
+	for i, s := range list {
+		if chosen[i] {
+			selected = append(selected, s)
+		}
+	}
+	return selected, nil
+}
+
+// askSuggestions は、修正案を1件ずつ表示し、適用するかを in から尋ねます。
+// y で適用、n (または空) でスキップ、q で以降の修正案をスキップします。
+func askSuggestions(list []patches.Suggestion, in io.Reader, out io.Writer) ([]patches.Suggestion, error) {
+	var selected []patches.Suggestion
+	scanner := bufio.NewScanner(in)
+	for i, s := range list {
+		fmt.Fprintf(out, "\n[%d/%d] [%s] %s", i+1, len(list), s.Finding.Severity, s.Finding.Title)
+		if s.Finding.File != "" {
+			fmt.Fprintf(out, " (%s", s.Finding.File)
+			if s.Finding.Line > 0 {
+				fmt.Fprintf(out, ":%d", s.Finding.Line)
+			}
+			fmt.Fprint(out, ")")
+		}
+		fmt.Fprintf(out, "\n\n%s\nこの修正案を適用しますか? [y/N/q] ", s.Patch)
+
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return selected, scanner.Err()
+		}
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "y", "yes":
+			selected = append(selected, s)
+		case "q", "quit":
+			return selected, nil
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"git-gemini-cli/internal/baseline"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+)
+
+// GenerateBaseline は、差分全体をレビューし、現在のすべての指摘事項を受け入れ済みとして記録したベースラインを path に保存します。
+// 既存のベースラインは使用せずに置き換えます。保存した指摘事項の件数を返します。
+func GenerateBaseline(ctx context.Context, cfg config.ReviewConfig, path string) (int, error) {
+	cfg.RequireFindings = true
+	cfg.IgnoreBaseline = true
+	// 一部の差分の指摘事項だけを記録しないよう、インクリメンタルレビューは行わない
+	cfg.Incremental = false
+	cfg.FailOn = ""
+
+	// 中断された場合は、途中までの指摘事項でベースラインを置き換えない
+	reviewResult, err := Review(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+
+	_, list := findings.Split(reviewResult)
+	b := baseline.New(list, time.Now())
+	if err := b.Save(path); err != nil {
+		return 0, err
+	}
+	slog.Info("ベースラインを保存しました。", "path", path, "findings", len(b.Entries))
+	return len(b.Entries), nil
+}
@@ -0,0 +1,222 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/runner"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+)
+
+// ErrNoBranches は、一括レビューの対象となるブランチが見つからなかったことを示すエラーです。
+var ErrNoBranches = errors.New("一括レビューの対象となるブランチが見つかりませんでした")
+
+// ResolveBranches は、一括レビューの対象ブランチを返します。
+// branches に続けて、pattern が指定されている場合はリモートのブランチのうち pattern に一致するものを名前順に加えます。
+// ベースブランチと重複するブランチは除外します。対象が1つもない場合は ErrNoBranches を返します。
+func ResolveBranches(ctx context.Context, cfg config.ReviewConfig, branches []string, pattern string) ([]string, error) {
+	candidates := append([]string(nil), branches...)
+	if pattern != "" {
+		remote, err := builder.ListRemoteBranches(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("リモートブランチの一覧取得に失敗しました: %w", err)
+		}
+		matched, err := internalAdapters.MatchBranches(remote, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("--all-branches-matching のパターンが不正です: %w", err)
+		}
+		slog.Info("パターンに一致するブランチを検出しました。", "pattern", pattern, "branches", len(matched))
+		candidates = append(candidates, matched...)
+	}
+
+	var resolved []string
+	seen := map[string]bool{cfg.BaseBranch: true}
+	for _, b := range candidates {
+		if seen[b] {
+			continue
+		}
+		seen[b] = true
+		resolved = append(resolved, b)
+	}
+	if len(resolved) == 0 {
+		return nil, ErrNoBranches
+	}
+	return resolved, nil
+}
+
+// ReviewBranches は、branches の各ブランチをベースブランチと比較して順にレビューします。
+// 同じプロセス内のレビューはクローンとフェッチ結果を共有するため、リポジトリの取得は1回で済みます。
+// 1つのブランチのレビューに失敗しても残りのブランチのレビューは続行し、失敗は BranchResult.Err に記録します。
+// 中断シグナルを受信した場合は、それまでに完了したブランチの結果と interrupt.ErrInterrupted を返します。
+func ReviewBranches(ctx context.Context, cfg config.ReviewConfig, branches []string) ([]runner.BranchResult, error) {
+	// 索引に判定と件数を表示するため、構造化された指摘事項を出力させる
+	cfg.RequireFindings = true
+
+	results := make([]runner.BranchResult, 0, len(branches))
+	for i, branch := range branches {
+		branchCfg := cfg
+		branchCfg.FeatureBranch = branch
+
+		slog.Info("ブランチをレビューします。", "branch", branch, "progress", fmt.Sprintf("%d/%d", i+1, len(branches)))
+		var saveState func(context.Context)
+		branchCtx := runner.WithStateHandler(ctx, func(save func(context.Context)) { saveState = save })
+		reviewResult, err := Review(branchCtx, branchCfg)
+		if errors.Is(err, interrupt.ErrInterrupted) {
+			return results, err
+		}
+
+		result := runner.BranchResult{Branch: branch, SaveState: saveState}
+		switch {
+		case errors.Is(err, ErrSkipReview):
+			result.Skipped = true
+		case err != nil:
+			slog.Error("ブランチのレビューに失敗しました。残りのブランチのレビューを続行します。", "branch", branch, "error", err)
+			result.Err = err
+		default:
+			result.Report, result.Findings = findings.Split(reviewResult)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// SaveBranchStates は、--incremental で一括レビューした各ブランチのコミットを記録します。
+// 記録すると次回のレビューでは以降の差分のみをレビューするため、結果の出力・公開に成功した後に呼び出します。
+func SaveBranchStates(ctx context.Context, results []runner.BranchResult) {
+	for _, r := range results {
+		if r.SaveState != nil && r.Err == nil {
+			r.SaveState(ctx)
+		}
+	}
+}
+
+// BatchGate は、一括レビューの結果のうち、レビューに失敗したブランチと、
+// cfg.FailOn が指定されている場合にその深刻度以上の指摘事項があるブランチを、ブランチ名付きのエラーにまとめて返します。
+// すべてのブランチが問題ない場合は nil を返します。
+func BatchGate(cfg config.ReviewConfig, results []runner.BranchResult) error {
+	var threshold findings.Severity
+	if cfg.FailOn != "" {
+		t, err := findings.ParseSeverity(cfg.FailOn)
+		if err != nil {
+			return err
+		}
+		threshold = t
+	}
+
+	var errs []error
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", r.Branch, r.Err))
+		case cfg.FailOn != "" && !r.Skipped:
+			if err := findings.CheckThreshold(r.Findings, threshold); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", r.Branch, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PublishBranches は、一括レビューの各ブランチのレポートを cfg.StorageURI と同じ場所に公開し、
+// 各レポートへの相対リンクを含む索引を cfg.StorageURI に公開します。
+// cfg.AdditionalURIs の各公開先にも同様に公開します。
+// Slack通知は索引の公開時にのみ行います。翻訳 (cfg.Languages) と暫定版の公開は行いません。
+// すべての公開先への公開と通知に成功した場合は、--incremental でレビューした各ブランチのコミットを記録します。
+func PublishBranches(ctx context.Context, cfg config.PublishConfig, results []runner.BranchResult) error {
+	cfg = expandURITemplates(cfg, time.Now(), "")
+	delivered := true
+	ctx = runner.WithDeliveryFailureHandler(ctx, func(error) { delivered = false })
+	if err := publishEach(ctx, cfg, func(ctx context.Context, target config.PublishConfig) error {
+		return publishBranches(ctx, target, results)
+	}); err != nil {
+		return err
+	}
+	if !delivered {
+		slog.Warn("レビュー結果の通知に失敗したため、レビューしたコミットを記録しません。次回は同じコミットを再度レビューします。")
+		return nil
+	}
+	SaveBranchStates(ctx, results)
+	return nil
+}
+
+// publishBranches は、1つの公開先に各ブランチのレポートと索引を公開します (PublishBranches)。
+func publishBranches(ctx context.Context, cfg config.PublishConfig, results []runner.BranchResult) error {
+	publishRunner, err := builder.BuildPublishRunner(ctx, cfg, builder.Dependencies{})
+	if err != nil {
+		return fmt.Errorf("PublishRunnerの構築に失敗しました: %w", err)
+	}
+
+	uris := branchURIs(cfg.StorageURI, results)
+	for i, r := range results {
+		if r.Err != nil || r.Skipped {
+			continue
+		}
+		branchCfg := cfg
+		branchCfg.ReviewConfig.FeatureBranch = r.Branch
+		branchCfg.StorageURI = uris[i]
+		branchCfg.SkipNotify = true
+
+		publication, err := publishRunner.Begin(ctx, branchCfg)
+		if err != nil {
+			return fmt.Errorf("ブランチ '%s' のレポートの公開に失敗しました: %w", r.Branch, err)
+		}
+		if err := publication.Complete(ctx, r.Report); err != nil {
+			return fmt.Errorf("ブランチ '%s' のレポートの公開に失敗しました: %w", r.Branch, err)
+		}
+		// --on-conflict=version では公開先のURIが変わるため、実際に公開したURIにリンクする
+		results[i].Link = path.Base(publication.URI())
+		slog.Info("ブランチのレポートを公開しました。", "branch", r.Branch, "uri", publication.URI())
+	}
+
+	indexCfg := cfg
+	indexCfg.ReviewConfig.FeatureBranch = ""
+	if err := publishRunner.Run(ctx, indexCfg, runner.BatchIndex(cfg.ReviewConfig, results)); err != nil {
+		return fmt.Errorf("索引の公開に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// unsafeBranchChars は、ブランチ名のうち公開先のファイル名に使用しない文字です。
+var unsafeBranchChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// BranchURI は、索引のURIと同じ場所に置くブランチのレポートのURIを返します (例: reports/index.html と release/1.2 → reports/release-1.2.html)。
+func BranchURI(indexURI, branch string) string {
+	dir, name := path.Split(indexURI)
+	return dir + unsafeBranchChars.ReplaceAllString(branch, "-") + path.Ext(name)
+}
+
+// branchURIs は、公開する各ブランチのレポートのURIを results と同じ順に返します (公開しないブランチは空文字列)。
+// 異なるブランチが同じURIになる場合 (例: release/1.2 と release-1.2) は、後のブランチに -2, -3, ... を付けて区別します。
+// 大文字と小文字を区別しないファイルシステムへの公開を考慮し、大文字と小文字のみが異なるURIも衝突とみなします。
+func branchURIs(indexURI string, results []runner.BranchResult) []string {
+	ext := path.Ext(indexURI)
+	taken := map[string]bool{strings.ToLower(indexURI): true}
+	uris := make([]string, len(results))
+	for i, r := range results {
+		if r.Err != nil || r.Skipped {
+			continue
+		}
+		want := BranchURI(indexURI, r.Branch)
+		uri := want
+		for n := 2; taken[strings.ToLower(uri)]; n++ {
+			uri = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(want, ext), n, ext)
+		}
+		if uri != want {
+			slog.Warn("他のブランチ (または索引) とレポートのURIが重複するため、番号を付けて公開します。", "branch", r.Branch, "uri", uri)
+		}
+		taken[strings.ToLower(uri)] = true
+		uris[i] = uri
+	}
+	return uris
+}
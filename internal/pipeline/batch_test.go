@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"git-gemini-cli/internal/runner"
+)
+
+func TestBranchURIs(t *testing.T) {
+	results := []runner.BranchResult{
+		{Branch: "release/1.2"},
+		{Branch: "release-1.2"},
+		{Branch: "release:1.2"},
+		{Branch: "failed", Err: errors.New("failed")},
+		{Branch: "index"},
+		{Branch: "Release-1.2"},
+		{Branch: "empty", Skipped: true},
+	}
+
+	got := branchURIs("gs://bucket/reports/index.html", results)
+	want := []string{
+		"gs://bucket/reports/release-1.2.html",
+		"gs://bucket/reports/release-1.2-2.html",
+		"gs://bucket/reports/release-1.2-3.html",
+		"",
+		"gs://bucket/reports/index-2.html",
+		"gs://bucket/reports/Release-1.2-4.html",
+		"",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("branchURIs() = %q, want %q", got, want)
+	}
+}
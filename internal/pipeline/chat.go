@@ -0,0 +1,81 @@
+package pipeline
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/runner"
+	"git-gemini-cli/internal/session"
+	"git-gemini-cli/internal/timeutil"
+)
+
+// chatExitCommands は、chat の対話を終了する入力です。
+var chatExitCommands = map[string]bool{"exit": true, "quit": true, ":q": true}
+
+// Chat は、最後に実行したレビューの結果と差分を読み込み、追加の質問に回答します。
+// questions が指定された場合は、それらに順に回答して終了します。
+// 指定されない場合は、in から1行ずつ質問を読み込み、exit または入力の終端 (Ctrl+D) まで対話を続けます。
+func Chat(ctx context.Context, cfg config.ReviewConfig, in io.Reader, out io.Writer, questions ...string) error {
+	path, err := session.ResolvePath(cfg.SessionFile)
+	if err != nil {
+		return err
+	}
+	review, err := session.Load(path)
+	if err != nil {
+		return err
+	}
+
+	chatRunner, err := builder.BuildChatRunner(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("会話実行器の構築に失敗しました: %w", err)
+	}
+	conv := &runner.Conversation{Review: review}
+
+	if len(questions) > 0 {
+		for _, q := range questions {
+			answer, err := chatRunner.Reply(ctx, cfg, conv, q)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(out, answer)
+		}
+		return nil
+	}
+
+	fmt.Fprintf(out, "レビュー結果 (%s, `%s...%s`, %s) について質問できます。終了するには exit と入力するか Ctrl+D を押してください。\n",
+		review.RepoURL, review.BaseRef, review.HeadRef, timeutil.FormatReport(review.ReviewedAt))
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for {
+		fmt.Fprint(out, "\n> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+		question := strings.TrimSpace(scanner.Text())
+		switch {
+		case question == "":
+			continue
+		case chatExitCommands[strings.ToLower(question)]:
+			return nil
+		}
+
+		answer, err := chatRunner.Reply(ctx, cfg, conv, question)
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		if err != nil {
+			// 1回の失敗で会話を終了せず、同じ質問を再度入力できるようにする
+			slog.Error("回答の生成に失敗しました。", "error", err)
+			continue
+		}
+		fmt.Fprintf(out, "\n%s\n", answer)
+	}
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+)
+
+// ReviewAndComment は、レビューを実行し、ストレージには公開せずに結果をプルリクエストのコメント (cfg.PRComment) や
+// チェックラン (cfg.GitHubCheck) として投稿します。serve コマンドで、Webhook を受信したプルリクエストをレビューする場合に使用します。
+// レビューがスキップされた場合は ErrSkipReview を返します。中断された場合は、不完全な結果を投稿せずに interrupt.ErrInterrupted を返します。
+// publish とは異なり投稿が唯一の出力先であるため、投稿に失敗した場合はエラーを返します。
+func ReviewAndComment(ctx context.Context, cfg config.PublishConfig) error {
+	commenter, err := builder.BuildPRCommenter(cfg)
+	if err != nil {
+		return fmt.Errorf("プルリクエストへのコメントの投稿の初期化に失敗しました: %w", err)
+	}
+	if commenter == nil {
+		return errors.New("レビュー結果の投稿先 (--pr-comment または --github-check) が指定されていません")
+	}
+
+	var commits commitRecorder
+	ctx = commits.watch(ctx)
+	var state stateRecorder
+	ctx = state.watch(ctx)
+	reviewResult, err := Review(ctx, cfg.ReviewConfig)
+	if err != nil {
+		state.skipped(ctx, err)
+		return err
+	}
+
+	report, _ := SeverityGate(cfg.ReviewConfig, reviewResult)
+	ctx = withFindings(ctx, reviewResult)
+	// チェックランとコメントは、ブランチの現在のヘッドではなくレビューしたコミットに結び付ける
+	head := cmp.Or(cfg.ReviewConfig.HeadCommit, commits.head)
+	ctx = internalAdapters.WithHeadCommit(ctx, head)
+	if err := commenter.Comment(ctx, "", report, cfg.ReviewConfig); err != nil {
+		return fmt.Errorf("レビュー結果の投稿に失敗しました: %w", err)
+	}
+	state.commit(ctx)
+	slog.Info("レビュー結果をプルリクエストに投稿しました。", "repo", cfg.ReviewConfig.RepoURL, "branch", cfg.ReviewConfig.FeatureBranch, "commit", head)
+	return nil
+}
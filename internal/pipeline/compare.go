@@ -0,0 +1,37 @@
+package pipeline
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/runner"
+)
+
+// CompareModels は、同じ差分を models の各モデルで順にレビューし、結果を並べて比較するレポートを返します。
+// 差分の取得は同じプロセス内でキャッシュされるため、各モデルは同じ差分をレビューします。
+// 差分が空の場合は ErrSkipReview を返します。
+func CompareModels(ctx context.Context, cfg config.ReviewConfig, models []string) (string, error) {
+	// 指摘事項を比較するため構造化された指摘事項を出力させ、両方のモデルが同じ差分全体をレビューするようインクリメンタルレビューは行わない
+	cfg.RequireFindings = true
+	cfg.Incremental = false
+	cfg.FailOn = ""
+
+	results := make([]runner.ModelResult, 0, len(models))
+	for _, model := range models {
+		modelCfg := cfg
+		modelCfg.Model = model
+
+		slog.Info("モデルの比較のためにレビューを実行します。", "backend", cfg.Backend, "model", model)
+		start := time.Now()
+		reviewResult, err := Review(ctx, modelCfg)
+		if err != nil {
+			return "", err
+		}
+		report, list := findings.Split(reviewResult)
+		results = append(results, runner.ModelResult{Model: model, Report: report, Findings: list, Elapsed: time.Since(start)})
+	}
+	return runner.ComparisonReport(cfg, results), nil
+}
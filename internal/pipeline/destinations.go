@@ -0,0 +1,53 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"git-gemini-cli/internal/config"
+)
+
+// publishTargets は、cfg.StorageURI と cfg.AdditionalURIs の公開先ごとの設定を返します。
+// Slack 通知は最初の公開先 (cfg.StorageURI) でのみ行います。
+func publishTargets(cfg config.PublishConfig) []config.PublishConfig {
+	primary := cfg
+	primary.AdditionalURIs = nil
+	targets := []config.PublishConfig{primary}
+	for _, uri := range cfg.AdditionalURIs {
+		target := primary
+		target.StorageURI = uri
+		target.SkipNotify = true
+		targets = append(targets, target)
+	}
+	return targets
+}
+
+// publishEach は、publish を各公開先に対して順に実行し、公開先ごとの成否をログに記録します。
+// 一部の公開先で失敗しても残りの公開先への公開は続行し、失敗した公開先のエラーをまとめて返します。
+func publishEach(ctx context.Context, cfg config.PublishConfig, publish func(ctx context.Context, target config.PublishConfig) error) error {
+	targets := publishTargets(cfg)
+	if len(targets) == 1 {
+		return publish(ctx, targets[0])
+	}
+
+	var errs []error
+	for _, target := range targets {
+		if err := publish(ctx, target); err != nil {
+			slog.Error("公開先への公開に失敗しました。", "uri", target.StorageURI, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", target.StorageURI, err))
+			continue
+		}
+		slog.Info("公開先への公開が完了しました。", "uri", target.StorageURI)
+	}
+	return joinPublishErrors(len(targets), errs)
+}
+
+// joinPublishErrors は、失敗した公開先のエラーを件数付きでまとめます。失敗がない場合は nil を返します。
+func joinPublishErrors(total int, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d 件の公開先のうち %d 件への公開に失敗しました: %w", total, len(errs), errors.Join(errs...))
+}
@@ -5,9 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"git-gemini-cli/internal/builder"
 	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/runner"
 )
 
 // ErrSkipReview は、レビュー対象の差分が存在しないためにパイプラインがスキップされたことを示すエラーです。
@@ -15,18 +20,26 @@ var ErrSkipReview = errors.New("差分が見つからなかったためレビュ
 
 // Review は、すべての依存関係を構築し、レビューパイプラインを実行します。
 // 実行結果の文字列とエラーを返します。
+// 中断シグナルにより途中で終了した場合は、完了した部分の結果と interrupt.ErrInterrupted を返します。
 func Review(
 	ctx context.Context,
 	cfg config.ReviewConfig,
 ) (string, error) {
+	return review(ctx, cfg, builder.Dependencies{})
+}
 
-	reviewRunner, err := builder.BuildReviewRunner(ctx, cfg)
+// review は、deps に指定された依存関係を使用してレビューパイプラインを実行します (Review)。
+func review(ctx context.Context, cfg config.ReviewConfig, deps builder.Dependencies) (string, error) {
+	reviewRunner, err := builder.BuildReviewRunner(ctx, cfg, deps)
 	if err != nil {
 		// BuildReviewRunner が内部でアダプタやビルダーの構築エラーをラップして返す
 		return "", fmt.Errorf("レビュー実行器の構築に失敗しました: %w", err)
 	}
 
 	reviewResult, err := reviewRunner.Run(ctx, cfg)
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		return reviewResult, err
+	}
 	if err != nil {
 		return "", err
 	}
@@ -39,15 +52,104 @@ func Review(
 	return reviewResult, nil
 }
 
+// BuildPrompts は、レビューと同じ手順でAIに送信するプロンプトを生成し、AIには送信せずに返します。
+// 差分が空の場合は ErrSkipReview を返します。
+func BuildPrompts(ctx context.Context, cfg config.ReviewConfig) ([]runner.PromptPart, error) {
+	promptRunner, err := builder.BuildPromptRunner(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプト生成器の構築に失敗しました: %w", err)
+	}
+	parts, err := promptRunner.DryRun(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		slog.Info(ErrSkipReview.Error())
+		return nil, ErrSkipReview
+	}
+	return parts, nil
+}
+
+// ReviewWithDiff は、Review と同様にレビューパイプラインを実行し、レビュー結果に加えてレビュー対象の差分を返します。
+func ReviewWithDiff(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+) (string, string, error) {
+	return reviewWithDiff(ctx, cfg, builder.Dependencies{})
+}
+
+// reviewWithDiff は、deps に指定された依存関係を使用して ReviewWithDiff を実行します。
+func reviewWithDiff(ctx context.Context, cfg config.ReviewConfig, deps builder.Dependencies) (string, string, error) {
+	var codeDiff string
+	ctx = runner.WithDiffHandler(ctx, func(diff string) { codeDiff = diff })
+
+	reviewResult, err := review(ctx, cfg, deps)
+	return reviewResult, codeDiff, err
+}
+
+// SeverityGate は、レビュー結果から構造化された指摘事項ブロックを取り除いたレポート本文を返し、
+// cfg.FailOn が指定されている場合は、その深刻度以上の指摘事項があれば findings.ErrThresholdExceeded をラップしたエラーを返します。
+// レポートの出力・公開は、エラーの有無に関わらず行うことを想定しています。
+func SeverityGate(cfg config.ReviewConfig, reviewResult string) (string, error) {
+	report, list := findings.Split(reviewResult)
+	if cfg.FailOn == "" {
+		return report, nil
+	}
+
+	threshold, err := findings.ParseSeverity(cfg.FailOn)
+	if err != nil {
+		return report, err
+	}
+	slog.Info("レビュー結果の指摘事項を判定しました。", "findings", len(list), "failOn", threshold)
+	return report, findings.CheckThreshold(list, threshold)
+}
+
+// Ask は、すべての依存関係を構築し、リポジトリに対する質問応答を実行します。
+func Ask(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+	question string,
+) (string, error) {
+
+	askRunner, err := builder.BuildAskRunner(ctx, cfg)
+	if err != nil {
+		return "", fmt.Errorf("質問応答実行器の構築に失敗しました: %w", err)
+	}
+
+	return askRunner.Run(ctx, cfg, question)
+}
+
+// SuggestReviewers は、依存関係を構築し、差分に対するレビュアーの推奨を実行します。
+// 差分が空の場合は ErrSkipReview を返します。
+func SuggestReviewers(ctx context.Context, cfg config.ReviewersConfig) (string, error) {
+	reviewersRunner, err := builder.BuildReviewersRunner(ctx, cfg)
+	if err != nil {
+		return "", fmt.Errorf("ReviewersRunnerの構築に失敗しました: %w", err)
+	}
+	report, err := reviewersRunner.Run(ctx, cfg)
+	if err != nil {
+		return report, err
+	}
+	if report == "" {
+		slog.Info(ErrSkipReview.Error())
+		return "", ErrSkipReview
+	}
+	return report, nil
+}
+
 // Publish は、すべての依存関係を構築し、パブリッシュパイプラインを実行します。
 func Publish(
 	ctx context.Context,
 	cfg config.PublishConfig,
 	reviewResult string,
 ) error {
+	return publish(ctx, cfg, builder.Dependencies{}, reviewResult)
+}
 
+// publish は、deps に指定された依存関係を使用してパブリッシュパイプラインを実行します (Publish)。
+func publish(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies, reviewResult string) error {
 	// クラウドストレージに保存し、そのURLを通知
-	publishRunner, err := builder.BuildPublishRunner(ctx, cfg)
+	publishRunner, err := builder.BuildPublishRunner(ctx, cfg, deps)
 	if err != nil {
 		return fmt.Errorf("PublishRunnerの構築に失敗しました: %w", err)
 	}
@@ -61,16 +163,249 @@ func Publish(
 
 // ReviewAndPublish は、レビューと公開処理を統合して実行します。
 // レビューがスキップされた場合は、ErrSkipReview を返します。
+// --fail-on のしきい値を超えた場合は、公開を完了した上で findings.ErrThresholdExceeded をラップしたエラーを返します。
+// 中断シグナルによりレビューが途中で終了した場合は、cfg.PublishOnInterrupt が true のときのみ途中までの結果を公開し、
+// interrupt.ErrInterrupted を返します。
+// cfg.Languages にレポートの言語以外が含まれる場合は、翻訳版も公開します。途中までの結果は翻訳しません。
+// 公開先のURIのプレースホルダ ({repo}、{branch}、{date}、{shortsha} など) は、レビューの完了後に置き換えます。
 func ReviewAndPublish(ctx context.Context, cfg config.PublishConfig) error {
+	return ReviewAndPublishWith(ctx, cfg, builder.Dependencies{})
+}
+
+// ReviewAndPublishWith は、deps に指定されたAI・公開先・ストレージ・通知先を使用して ReviewAndPublish を実行します。
+// deps の nil のフィールドは、設定に基づいて通常どおり構築します。
+func ReviewAndPublishWith(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies) error {
+	now := time.Now()
+	needsCommit := hasCommitPlaceholder(cfg)
+	if cfg.Provisional && needsCommit {
+		// 暫定版はレビューの開始前に公開先を確定させるため、レビュー中に取得するコミットハッシュを使用できない
+		slog.Warn("公開先のURIにコミットハッシュ ({sha} / {shortsha}) が含まれるため、暫定版の公開を行いません。", "uri", cfg.StorageURI)
+		cfg.Provisional = false
+	}
+	if cfg.Provisional {
+		return reviewAndPublishProvisional(ctx, expandURITemplates(cfg, now, ""), deps)
+	}
 
-	reviewResult, err := Review(ctx, cfg.ReviewConfig)
+	var commits commitRecorder
+	if needsCommit || cfg.Manifest || cfg.GitHubCheck {
+		ctx = commits.watch(ctx)
+	}
+	var state stateRecorder
+	ctx = state.watch(ctx)
+	ctx, reviewResult, err := reviewForPublish(ctx, cfg, deps)
+	cfg = expandURITemplates(cfg, now, commits.head)
+	ctx = commits.newContext(ctx)
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		return publishInterrupted(ctx, cfg, deps, reviewResult, err)
+	}
 	if err != nil {
+		state.skipped(ctx, err)
 		return err
 	}
 
-	if err := Publish(ctx, cfg, reviewResult); err != nil {
+	report, gateErr := SeverityGate(cfg.ReviewConfig, reviewResult)
+	ctx = withFindings(ctx, reviewResult)
+	// 翻訳は公開先の数によらず1回のみ行う
+	variants := translateVariants(ctx, cfg, deps, report)
+	err = publishEach(ctx, cfg, func(ctx context.Context, target config.PublishConfig) error {
+		return publishReport(ctx, target, deps, report, variants)
+	})
+	if err != nil {
 		return err
 	}
+	state.commit(ctx)
 
-	return nil
+	return gateErr
+}
+
+// reviewForPublish は、公開するレビューを実行します。
+// cfg.EmbedDiff が true の場合は、レポートに埋め込むレビュー対象の差分を格納した context を返します (暫定版のレポートには埋め込みません)。
+func reviewForPublish(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies) (context.Context, string, error) {
+	if !cfg.EmbedDiff {
+		reviewResult, err := review(ctx, cfg.ReviewConfig, deps)
+		return ctx, reviewResult, err
+	}
+	reviewResult, codeDiff, err := reviewWithDiff(ctx, cfg.ReviewConfig, deps)
+	if codeDiff != "" {
+		ctx = diffutil.NewContext(ctx, codeDiff)
+	}
+	return ctx, reviewResult, err
+}
+
+// publishInterrupted は、中断により途中で終了したレビューの結果を、cfg.PublishOnInterrupt が true の場合に公開します。
+// 途中までの結果では重大な指摘を見落としている可能性があるため、--fail-on の判定は行わず、常に interrupt.ErrInterrupted を返します。
+func publishInterrupted(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies, partial string, interruptErr error) error {
+	if !cfg.PublishOnInterrupt {
+		slog.Warn("レビューが途中で終了したため、公開をスキップします。--publish-on-interrupt を指定すると途中までの結果を公開します。", "uri", cfg.StorageURI)
+		return interruptErr
+	}
+
+	report, _ := findings.Split(partial)
+	slog.Warn("レビューが途中で終了したため、未完了の注記付きで途中までの結果を公開します。", "uri", cfg.StorageURI)
+	if err := publishEach(ctx, cfg, func(ctx context.Context, target config.PublishConfig) error {
+		return publish(ctx, target, deps, report)
+	}); err != nil {
+		return err
+	}
+	return interruptErr
+}
+
+// provisionalTarget は、暫定版を公開する1つの公開先です。
+type provisionalTarget struct {
+	cfg           config.PublishConfig
+	publishRunner runner.PublisherRunner
+	publication   *runner.Publication
+}
+
+// reviewAndPublishProvisional は、レビューの途中経過を暫定版として公開しながら、レビューと公開処理を実行します。
+// 暫定版は各公開先で最終版と同じURIに上書きされ、Slack通知は最終版の公開時にのみ行います。
+// 公開の準備に失敗した公開先は除外してレビューを続行し、すべての公開先で失敗した場合はエラーを返します。
+func reviewAndPublishProvisional(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies) error {
+	targets := publishTargets(cfg)
+	var prepared []provisionalTarget
+	var errs []error
+	for _, target := range targets {
+		publishRunner, err := builder.BuildPublishRunner(ctx, target, deps)
+		if err != nil {
+			err = fmt.Errorf("PublishRunnerの構築に失敗しました: %w", err)
+		} else {
+			var publication *runner.Publication
+			if publication, err = publishRunner.Begin(ctx, target); err == nil {
+				prepared = append(prepared, provisionalTarget{cfg: target, publishRunner: publishRunner, publication: publication})
+				continue
+			}
+			err = fmt.Errorf("公開処理の実行に失敗しました: %w", err)
+		}
+		if len(targets) == 1 {
+			return err
+		}
+		slog.Error("公開先の準備に失敗したため、この公開先を除外します。", "uri", target.StorageURI, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", target.StorageURI, err))
+	}
+	if len(prepared) == 0 {
+		return joinPublishErrors(len(targets), errs)
+	}
+
+	// 暫定版の公開に失敗してもレビューは継続し、最終版の公開で結果を確定させる
+	ctx = runner.WithPartialResultHandler(ctx, func(ctx context.Context, partial string) {
+		report, _ := findings.Split(partial)
+		for _, t := range prepared {
+			if err := t.publication.PublishProvisional(ctx, report); err != nil {
+				slog.Warn("暫定版レポートの公開に失敗しました。", "uri", t.publication.URI(), "error", err)
+				continue
+			}
+			slog.Info("暫定版レポートを公開しました。", "uri", t.publication.URI())
+		}
+	})
+
+	// complete は、準備できたすべての公開先で最終版を公開し、準備に失敗した公開先を含めてエラーをまとめます。
+	complete := func(publish func(t provisionalTarget) error) error {
+		for _, t := range prepared {
+			if err := publish(t); err != nil {
+				if len(targets) == 1 {
+					return fmt.Errorf("公開処理の実行に失敗しました: %w", err)
+				}
+				slog.Error("公開先への公開に失敗しました。", "uri", t.publication.URI(), "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", t.cfg.StorageURI, err))
+				continue
+			}
+			if len(targets) > 1 {
+				slog.Info("公開先への公開が完了しました。", "uri", t.publication.URI())
+			}
+		}
+		return joinPublishErrors(len(targets), errs)
+	}
+
+	var commits commitRecorder
+	if cfg.Manifest || cfg.GitHubCheck {
+		ctx = commits.watch(ctx)
+	}
+	var state stateRecorder
+	ctx = state.watch(ctx)
+	ctx, reviewResult, err := reviewForPublish(ctx, cfg, deps)
+	ctx = commits.newContext(ctx)
+	if errors.Is(err, interrupt.ErrInterrupted) && cfg.PublishOnInterrupt {
+		report, _ := findings.Split(reviewResult)
+		if pubErr := complete(func(t provisionalTarget) error { return t.publication.Complete(ctx, report) }); pubErr != nil {
+			return pubErr
+		}
+		return err
+	}
+	if err != nil {
+		state.skipped(ctx, err)
+		return err
+	}
+
+	report, gateErr := SeverityGate(cfg.ReviewConfig, reviewResult)
+	ctx = withFindings(ctx, reviewResult)
+	variants := translateVariants(ctx, cfg, deps, report)
+	if err := complete(func(t provisionalTarget) error {
+		return completeWithTranslations(ctx, t.cfg, t.publishRunner, t.publication, report, variants)
+	}); err != nil {
+		return err
+	}
+	state.commit(ctx)
+
+	return gateErr
+}
+
+// commitRecorder は、レビュー中に解決したレビュー対象のコミットハッシュ (runner.CommitHandler) を記録します。
+type commitRecorder struct {
+	base, head string
+}
+
+// watch は、レビュー対象のコミットハッシュを記録するよう設定した context を返します。
+func (c *commitRecorder) watch(ctx context.Context) context.Context {
+	return runner.WithCommitHandler(ctx, func(baseSHA, headSHA string) { c.base, c.head = baseSHA, headSHA })
+}
+
+// newContext は、記録したコミットハッシュを、公開処理 (レビューのマニフェスト) に渡すために格納した context を返します。
+// コミットハッシュを記録していない場合は ctx をそのまま返します。
+func (c *commitRecorder) newContext(ctx context.Context) context.Context {
+	if c.head == "" {
+		return ctx
+	}
+	return runner.WithCommits(ctx, c.base, c.head)
+}
+
+// stateRecorder は、--incremental でレビューしたコミットの記録 (runner.StateHandler) を、レビュー結果の配信に成功するまで保留します。
+type stateRecorder struct {
+	save   func(context.Context)
+	failed bool // Slack 通知またはプルリクエストへのコメントの投稿に失敗した
+}
+
+// watch は、レビューしたコミットの記録を保留し、公開後の通知・投稿の失敗を記録するよう設定した context を返します。
+func (s *stateRecorder) watch(ctx context.Context) context.Context {
+	ctx = runner.WithStateHandler(ctx, func(save func(context.Context)) { s.save = save })
+	return runner.WithDeliveryFailureHandler(ctx, func(error) { s.failed = true })
+}
+
+// commit は、保留したレビューしたコミットを記録します。通知・投稿に失敗した場合は、次回も同じコミットをレビューするよう記録しません。
+func (s *stateRecorder) commit(ctx context.Context) {
+	if s.save == nil {
+		return
+	}
+	if s.failed {
+		slog.Warn("レビュー結果の通知またはプルリクエストへの投稿に失敗したため、レビューしたコミットを記録しません。次回は同じコミットを再度レビューします。")
+		return
+	}
+	s.save(ctx)
+}
+
+// skipped は、差分が空のためレビューをスキップした場合 (ErrSkipReview) に、配信するものがないため保留した記録をそのまま行います。
+func (s *stateRecorder) skipped(ctx context.Context, err error) {
+	if errors.Is(err, ErrSkipReview) {
+		s.commit(ctx)
+	}
+}
+
+// withFindings は、Slack 通知にサマリー (判定・件数・主なリスク) を表示できるよう、レビュー結果の指摘事項を格納した context を返します。
+// 構造化された指摘事項を出力させていない場合も、見出しから抽出できた指摘事項があれば格納します。
+func withFindings(ctx context.Context, reviewResult string) context.Context {
+	_, list := findings.Split(reviewResult)
+	if len(list) == 0 && !findings.HasBlock(reviewResult) {
+		// 指摘事項を判定できない (構造化された指摘事項を出力させていない) 場合は、サマリーを表示しない
+		return ctx
+	}
+	return findings.NewContext(ctx, list)
 }
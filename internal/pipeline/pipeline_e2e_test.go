@@ -0,0 +1,100 @@
+package pipeline_test
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"git-gemini-cli/internal/pipeline"
+	"git-gemini-cli/internal/testkit"
+)
+
+// TestReviewAndPublish は、クローン → 差分 → レビュー → 公開 → 通知 のパイプライン全体を、testkit の偽の外部サービスで検証します。
+func TestReviewAndPublish(t *testing.T) {
+	h := testkit.New(t)
+	h.Repo.Commit("feature/x", map[string]string{"main.go": "package main\n\nfunc main() {}\n"}, "add main.go")
+
+	const uri = "mem://bucket/result.html"
+	ctx := context.Background()
+	if err := pipeline.ReviewAndPublishWith(ctx, h.PublishConfig("feature/x", uri), h.Dependencies()); err != nil {
+		t.Fatalf("ReviewAndPublish() error = %v", err)
+	}
+
+	prompts := h.AI.Prompts()
+	if len(prompts) == 0 {
+		t.Fatal("AI にプロンプトが送信されていません")
+	}
+	if !strings.Contains(strings.Join(prompts, "\n"), "main.go") {
+		t.Errorf("プロンプトにレビュー対象の差分 (main.go) が含まれていません")
+	}
+
+	uploads := h.Storage.Uploads()
+	if len(uploads) != 1 {
+		t.Fatalf("公開されたレポートの数 = %d, want 1", len(uploads))
+	}
+	if uploads[0].URI != uri {
+		t.Errorf("公開先 = %q, want %q", uploads[0].URI, uri)
+	}
+	if !strings.Contains(uploads[0].Data.ReviewMarkdown, "指摘事項はありません。") {
+		t.Errorf("公開されたレポートに AI のレビュー結果が含まれていません:\n%s", uploads[0].Data.ReviewMarkdown)
+	}
+
+	notifications := h.Notifier.Notifications()
+	if len(notifications) != 1 || notifications[0].StorageURI != uri {
+		t.Errorf("通知 = %+v, want %q の公開の通知1件", notifications, uri)
+	}
+
+	// 同じ内容の再実行は、重複排除マーカーにより公開と通知を行わない
+	if err := pipeline.ReviewAndPublishWith(ctx, h.PublishConfig("feature/x", uri), h.Dependencies()); err != nil {
+		t.Fatalf("2回目の ReviewAndPublish() error = %v", err)
+	}
+	if n := len(h.Storage.Uploads()); n != 1 {
+		t.Errorf("2回目の実行後の公開されたレポートの数 = %d, want 1", n)
+	}
+	if n := len(h.Notifier.Notifications()); n != 1 {
+		t.Errorf("2回目の実行後の通知の数 = %d, want 1", n)
+	}
+}
+
+// TestReviewAndPublishIncrementalHeadCommit は、serve のように push ごとにコミット (HeadCommit) を指定してレビューする場合も、
+// --incremental の2回目のレビューが前回レビューしたコミット以降の差分のみを対象とすることを検証します。
+func TestReviewAndPublishIncrementalHeadCommit(t *testing.T) {
+	h := testkit.New(t)
+	first := h.Repo.Commit("feature/x", map[string]string{"first.go": "package first\n"}, "add first.go")
+
+	ctx := context.Background()
+	stateURI := filepath.Join(t.TempDir(), "state.json")
+	review := func(headSHA, uri string) {
+		t.Helper()
+		cfg := h.PublishConfig("feature/x", uri)
+		cfg.ReviewConfig.Incremental = true
+		cfg.ReviewConfig.StateURI = stateURI
+		cfg.ReviewConfig.HeadCommit = headSHA
+		if err := pipeline.ReviewAndPublishWith(ctx, cfg, h.Dependencies()); err != nil {
+			t.Fatalf("ReviewAndPublish(%s) error = %v", headSHA, err)
+		}
+	}
+
+	review(first, "mem://bucket/first.html")
+	if n := len(h.AI.Prompts()); n == 0 {
+		t.Fatal("1回目のレビューで AI にプロンプトが送信されていません")
+	}
+	sent := len(h.AI.Prompts())
+
+	second := h.Repo.Commit("feature/x", map[string]string{"second.go": "package second\n"}, "add second.go")
+	review(second, "mem://bucket/second.html")
+
+	prompts := h.AI.Prompts()[sent:]
+	if len(prompts) == 0 {
+		t.Fatal("2回目のレビューで AI にプロンプトが送信されていません")
+	}
+	joined := strings.Join(prompts, "\n")
+	// コミットログにはブランチ全体のコミットメッセージが含まれるため、ファイルの内容で差分の範囲を判定する
+	if !strings.Contains(joined, "package second") {
+		t.Errorf("2回目のプロンプトに新しいコミットの差分 (second.go) が含まれていません")
+	}
+	if strings.Contains(joined, "package first") {
+		t.Errorf("2回目のプロンプトに前回レビューしたコミットの差分 (first.go) が含まれています")
+	}
+}
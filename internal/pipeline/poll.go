@@ -0,0 +1,39 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+)
+
+// BranchHeads は、リポジトリをフェッチし、pattern (path.Match 形式) に一致するリモートのブランチと、その最新のコミットハッシュを返します。
+// ベースブランチと、ベースブランチと同じコミットを指す (ベースブランチに対する新しいコミットがない) ブランチは含めません。
+// serve の --poll-interval で、前回の確認以降にコミットが追加されたブランチを検出するために使用します。
+func BranchHeads(ctx context.Context, cfg config.ReviewConfig, pattern string) (map[string]string, error) {
+	heads, err := builder.ListRemoteBranchHeads(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("リモートブランチの一覧取得に失敗しました: %w", err)
+	}
+	branches := make([]string, 0, len(heads))
+	for branch := range heads {
+		branches = append(branches, branch)
+	}
+	matched, err := internalAdapters.MatchBranches(branches, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("ブランチのパターンが不正です: %w", err)
+	}
+
+	baseHead := heads[cfg.BaseBranch]
+	result := make(map[string]string, len(matched))
+	for _, branch := range matched {
+		if branch == cfg.BaseBranch || heads[branch] == baseHead {
+			continue
+		}
+		result[branch] = heads[branch]
+	}
+	return result, nil
+}
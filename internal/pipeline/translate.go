@@ -0,0 +1,145 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/prompts"
+	"git-gemini-cli/internal/runner"
+)
+
+// reportVariant は、1つの言語で公開するレポートです。
+type reportVariant struct {
+	Language string
+	URI      string
+	Report   string
+}
+
+// needsTranslation は、cfg.Languages の指定により、レポートの翻訳が必要かを返します。
+func needsTranslation(cfg config.PublishConfig) bool {
+	for _, lang := range cfg.Languages {
+		if lang != prompts.ReportLanguage {
+			return true
+		}
+	}
+	return false
+}
+
+// publishReport は、最終版のレポートを公開します。variants がある場合は翻訳版も公開します (completeWithTranslations)。
+func publishReport(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies, report string, variants []reportVariant) error {
+	if len(variants) == 0 {
+		return publish(ctx, cfg, deps, report)
+	}
+
+	publishRunner, err := builder.BuildPublishRunner(ctx, cfg, deps)
+	if err != nil {
+		return fmt.Errorf("PublishRunnerの構築に失敗しました: %w", err)
+	}
+	publication, err := publishRunner.Begin(ctx, cfg)
+	if err != nil {
+		return fmt.Errorf("公開処理の実行に失敗しました: %w", err)
+	}
+	if err := completeWithTranslations(ctx, cfg, publishRunner, publication, report, variants); err != nil {
+		return fmt.Errorf("公開処理の実行に失敗しました: %w", err)
+	}
+	return nil
+}
+
+// completeWithTranslations は、最終版のレポートを公開します。
+// variants (translateVariants) がある場合は、各言語に翻訳した版を言語コード付きのURI (例: result.en.html) に公開し、
+// 最初の言語の版を publication のURIに公開します。各版の先頭には、他の言語の版への相対リンクを追加します。
+// Slack通知は、publication のURIに公開する版でのみ行います。
+func completeWithTranslations(ctx context.Context, cfg config.PublishConfig, publishRunner runner.PublisherRunner, publication *runner.Publication, report string, variants []reportVariant) error {
+	if len(variants) == 0 {
+		return publication.Complete(ctx, report)
+	}
+
+	// 複数の公開先で同じ翻訳結果を使用するため、公開先ごとに複製してURIとリンクを設定する
+	variants = append([]reportVariant(nil), variants...)
+	for i := range variants {
+		variants[i].URI = publication.URI()
+		if i > 0 {
+			variants[i].URI = runner.LanguageURI(publication.URI(), variants[i].Language)
+		}
+	}
+	addLanguageLinks(variants)
+
+	// 通知の時点で言語の切り替えリンクが有効になるよう、翻訳版を先に公開する
+	for _, v := range variants[1:] {
+		variantCfg := cfg
+		variantCfg.StorageURI = v.URI
+		variantCfg.OnConflict = config.ConflictOverwrite
+		variantCfg.SkipNotify = true
+		// 一覧とマニフェストへの記録と保持期間の適用は主となる版の公開時に1回だけ行う (翻訳版へは各版の先頭のリンクから移動できる)
+		variantCfg.UpdateIndex, variantCfg.Manifest = false, false
+		variantCfg.RetentionAge, variantCfg.RetentionCount = 0, 0
+		if err := publishRunner.Run(ctx, variantCfg, v.Report); err != nil {
+			// 翻訳版は二次的な成果物のため、公開に失敗しても主となる版の公開は続行する
+			slog.Warn("翻訳版のレポートの公開に失敗しました。", "language", v.Language, "uri", v.URI, "error", err)
+		}
+	}
+	return publication.Complete(ctx, variants[0].Report)
+}
+
+// translateVariants は、cfg.Languages の順に各言語のレポートを用意します。公開先のURIは completeWithTranslations で設定します。
+// 翻訳が不要な場合や、翻訳実行器を構築できない場合は nil を返します (元のレポートのみを公開します)。
+// 翻訳に失敗した言語は警告を出して除外します。ただし、最初の言語の翻訳に失敗した場合は、元のレポートを主となる版として公開します。
+func translateVariants(ctx context.Context, cfg config.PublishConfig, deps builder.Dependencies, report string) []reportVariant {
+	if !needsTranslation(cfg) {
+		return nil
+	}
+	translateRunner, err := builder.BuildTranslateRunner(ctx, cfg.ReviewConfig, deps)
+	if err != nil {
+		slog.Warn("翻訳実行器の構築に失敗したため、翻訳せずに公開します。", "error", err)
+		return nil
+	}
+
+	var variants []reportVariant
+	for i, lang := range cfg.Languages {
+		if lang == prompts.ReportLanguage {
+			variants = append(variants, reportVariant{Language: lang, Report: report})
+			continue
+		}
+		translated, err := translateRunner.Run(ctx, cfg.ReviewConfig, report, lang)
+		if err == nil && translated == "" {
+			err = errors.New("翻訳結果が空です")
+		}
+		if err != nil {
+			if i == 0 {
+				slog.Warn("レポートの翻訳に失敗したため、元の言語のレポートを公開します。", "language", lang, "error", err)
+				variants = append(variants, reportVariant{Language: prompts.ReportLanguage, Report: report})
+				continue
+			}
+			slog.Warn("レポートの翻訳に失敗したため、この言語の公開をスキップします。", "language", lang, "error", err)
+			continue
+		}
+		slog.Info("レポートを翻訳しました。", "language", lang)
+		variants = append(variants, reportVariant{Language: lang, Report: translated})
+	}
+	return variants
+}
+
+// addLanguageLinks は、各版のレポートの先頭に、他の言語の版への相対リンクを追加します。
+// 公開先は同じディレクトリのため、リンクにはファイル名のみを使用します。
+func addLanguageLinks(variants []reportVariant) {
+	if len(variants) < 2 {
+		return
+	}
+	for i := range variants {
+		links := make([]string, 0, len(variants))
+		for j, other := range variants {
+			if i == j {
+				links = append(links, "**"+prompts.LanguageName(other.Language)+"**")
+				continue
+			}
+			links = append(links, fmt.Sprintf("[%s](%s)", prompts.LanguageName(other.Language), path.Base(other.URI)))
+		}
+		variants[i].Report = "🌐 " + strings.Join(links, " | ") + "\n\n" + variants[i].Report
+	}
+}
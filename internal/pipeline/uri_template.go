@@ -0,0 +1,110 @@
+package pipeline
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+)
+
+// uriPlaceholderPattern は、公開先のURIに含めるプレースホルダ ({repo} など) の形式です。
+var uriPlaceholderPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// uriPlaceholders は、公開先のURIで使用できるプレースホルダの名前です。
+var uriPlaceholders = map[string]bool{
+	"repo":     true, // リポジトリ名 (URLの最後の要素から .git を除いたもの)
+	"branch":   true, // レビュー対象のブランチ (またはタグ)
+	"base":     true, // 比較元のブランチ (またはタグ)
+	"mode":     true, // レビューモード
+	"date":     true, // 公開日 (YYYY-MM-DD)
+	"time":     true, // 公開時刻 (HHMMSS)
+	"sha":      true, // レビュー対象のブランチのコミットハッシュ
+	"shortsha": true, // コミットハッシュの先頭7文字
+}
+
+// shortSHALength は、{shortsha} に使用するコミットハッシュの桁数です。
+const shortSHALength = 7
+
+// unknownCommit は、コミットハッシュを取得できなかった場合に {sha} / {shortsha} を置き換える文字列です。
+const unknownCommit = "unknown"
+
+// ValidateURITemplate は、公開先のURIに含まれるプレースホルダがすべて使用できるものかを検証します。
+// allowBranch が false の場合 (複数のブランチの一括レビュー) は、ブランチごとに異なる {branch}・{sha}・{shortsha} を使用できません。
+func ValidateURITemplate(uri string, allowBranch bool) error {
+	for _, m := range uriPlaceholderPattern.FindAllStringSubmatch(uri, -1) {
+		name := m[1]
+		if !uriPlaceholders[name] {
+			return fmt.Errorf("公開先のURI '%s' のプレースホルダ {%s} は使用できません (使用できるもの: {repo}, {branch}, {base}, {mode}, {date}, {time}, {sha}, {shortsha})", uri, name)
+		}
+		if !allowBranch && (name == "branch" || name == "sha" || name == "shortsha") {
+			return fmt.Errorf("複数のブランチの一括レビューでは、公開先のURI '%s' に {%s} を使用できません", uri, name)
+		}
+	}
+	return nil
+}
+
+// hasCommitPlaceholder は、公開先のURIのいずれかがコミットハッシュ ({sha} / {shortsha}) を含むかを返します。
+func hasCommitPlaceholder(cfg config.PublishConfig) bool {
+	for _, uri := range append([]string{cfg.StorageURI}, cfg.AdditionalURIs...) {
+		if strings.Contains(uri, "{sha}") || strings.Contains(uri, "{shortsha}") {
+			return true
+		}
+	}
+	return false
+}
+
+// expandURITemplates は、cfg.StorageURI と cfg.AdditionalURIs のプレースホルダを、レビューの設定・公開日時・コミットハッシュで置き換えます。
+// 置き換える値のうち、オブジェクトキーに使用できない文字 ("/" など) は "-" に置き換えます。
+func expandURITemplates(cfg config.PublishConfig, now time.Time, headSHA string) config.PublishConfig {
+	if hasCommitPlaceholder(cfg) && headSHA == "" {
+		slog.Warn("レビュー対象のコミットハッシュを取得できなかったため、公開先のURIの {sha} / {shortsha} を置き換えられません。", "replacement", unknownCommit)
+		headSHA = unknownCommit
+	}
+	rc := cfg.ReviewConfig
+	baseRef, headRef := rc.DiffRefs()
+	values := map[string]string{
+		"repo":     repoName(rc.RepoURL),
+		"branch":   headRef,
+		"base":     baseRef,
+		"mode":     rc.ReviewMode,
+		"date":     now.In(time.Local).Format("2006-01-02"),
+		"time":     now.In(time.Local).Format("150405"),
+		"sha":      headSHA,
+		"shortsha": headSHA[:min(len(headSHA), shortSHALength)],
+	}
+	expand := func(uri string) string {
+		expanded := uriPlaceholderPattern.ReplaceAllStringFunc(uri, func(m string) string {
+			value, ok := values[m[1:len(m)-1]]
+			if !ok {
+				return m
+			}
+			return unsafeBranchChars.ReplaceAllString(value, "-")
+		})
+		if expanded != uri {
+			slog.Debug("公開先のURIのプレースホルダを置き換えました。", "template", uri, "uri", expanded)
+		}
+		return expanded
+	}
+
+	cfg.StorageURI = expand(cfg.StorageURI)
+	if len(cfg.AdditionalURIs) > 0 {
+		uris := make([]string, len(cfg.AdditionalURIs))
+		for i, uri := range cfg.AdditionalURIs {
+			uris[i] = expand(uri)
+		}
+		cfg.AdditionalURIs = uris
+	}
+	return cfg
+}
+
+// repoName は、リポジトリのURL (https:// または git@host:owner/repo.git) からリポジトリ名を返します。
+func repoName(repoURL string) string {
+	name := strings.TrimSuffix(strings.TrimRight(repoURL, "/"), ".git")
+	if i := strings.LastIndexAny(name, "/:"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
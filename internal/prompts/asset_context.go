@@ -0,0 +1,101 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/assets"
+)
+
+// assetContextTemplateFile は、変更されたバイナリファイルのメタデータをプロンプトに追加するためのテンプレートファイルです。
+const assetContextTemplateFile = "templates/asset_context.md"
+
+// AssetChange は、変更されたバイナリファイル (画像などのアセット) のメタデータです。
+// 変更前後のメタデータは、ファイルが存在しない側や取得に失敗した側は nil です。
+type AssetChange struct {
+	Path    string
+	Created bool
+	Deleted bool
+	Old     *assets.Metadata
+	New     *assets.Metadata
+}
+
+// Status は、変更の種類 ("追加" / "削除" / "変更") を返します。
+func (c AssetChange) Status() string {
+	switch {
+	case c.Created:
+		return "追加"
+	case c.Deleted:
+		return "削除"
+	default:
+		return "変更"
+	}
+}
+
+// FormatChange は、形式を "png" または "gif → png" のような表記で返します。
+func (c AssetChange) FormatChange() string {
+	return change(c.Old, c.New, func(m assets.Metadata) string { return m.Format })
+}
+
+// SizeChange は、サイズを "1024 B → 2048 B (+1024 B, +100.0%)" のような表記で返します。
+func (c AssetChange) SizeChange() string {
+	text := change(c.Old, c.New, func(m assets.Metadata) string { return fmt.Sprintf("%d B", m.Size) })
+	if c.Old == nil || c.New == nil {
+		return text
+	}
+	delta := c.New.Size - c.Old.Size
+	if c.Old.Size == 0 {
+		return fmt.Sprintf("%s (%+d B)", text, delta)
+	}
+	return fmt.Sprintf("%s (%+d B, %+.1f%%)", text, delta, float64(delta)*100/float64(c.Old.Size))
+}
+
+// DimensionChange は、画像の寸法を "640×480 → 1280×960" のような表記で返します。画像でない場合は空文字を返します。
+func (c AssetChange) DimensionChange() string {
+	return change(c.Old, c.New, func(m assets.Metadata) string {
+		if !m.HasDimensions() {
+			return ""
+		}
+		return fmt.Sprintf("%d×%d", m.Width, m.Height)
+	})
+}
+
+// change は、変更前後の値を "前 → 後" の形式で返します。値が同じ場合や片方しかない場合は1つだけを返します。
+func change(old, new *assets.Metadata, value func(assets.Metadata) string) string {
+	var before, after string
+	if old != nil {
+		before = value(*old)
+	}
+	if new != nil {
+		after = value(*new)
+	}
+	switch {
+	case before == after, before == "":
+		return after
+	case after == "":
+		return before
+	default:
+		return before + " → " + after
+	}
+}
+
+// AssetContext は、プロンプトに追加する変更されたバイナリファイルの一覧です。
+type AssetContext struct {
+	Assets []AssetChange
+}
+
+// AppendAssetContext は、プロンプトの末尾に変更されたバイナリファイルのメタデータを追記します。
+// 追記するファイルがない場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendAssetContext(prompt string, ac AssetContext) (string, error) {
+	if len(ac.Assets) == 0 {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	if err := b.assetContext.Execute(&buf, ac); err != nil {
+		return "", fmt.Errorf("バイナリファイルのコンテキストの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,40 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+
+	"git-gemini-cli/internal/findings"
+)
+
+// chatTemplateFile は、chat コマンドのテンプレートファイルです。
+const chatTemplateFile = "templates/prompt_chat.md"
+
+// ChatTurn は、chat コマンドでの1往復の質問と回答です。
+type ChatTurn struct {
+	Question string
+	Answer   string
+}
+
+// ChatData は、chat コマンドのプロンプトに埋め込むデータです。
+type ChatData struct {
+	RepoURL       string
+	BaseRef       string
+	HeadRef       string
+	Mode          string
+	Report        string             // 構造化された指摘事項ブロックを取り除いたレビュー結果
+	Findings      []findings.Finding // 番号で参照できるよう一覧にする指摘事項
+	Diff          string
+	DiffTruncated bool
+	History       []ChatTurn // これまでの会話 (古い順)
+	Question      string
+}
+
+// BuildChat は、レビュー結果と差分、これまでの会話を前提として追加の質問に回答させるプロンプトを生成します。
+func (b *Builder) BuildChat(data ChatData) (string, error) {
+	var buf bytes.Buffer
+	if err := b.chat.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("会話プロンプトの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,101 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// examplesFile は、リポジトリ内のレビューの例 (few-shot) のファイル名です。
+	examplesFile = "examples.yaml"
+	// examplesTemplateFile は、レビューの例をプロンプトの先頭に追加するためのテンプレートファイルです。
+	examplesTemplateFile = "templates/examples.md"
+)
+
+// Example は、チームが期待するレビューの口調と深さを示す、差分の抜粋と理想的なレビューの組です。
+type Example struct {
+	Modes  []string `yaml:"modes"`  // 例を使用するモード (省略時はすべてのモード)
+	Diff   string   `yaml:"diff"`   // 差分の抜粋
+	Review string   `yaml:"review"` // この差分に対する理想的なレビュー
+}
+
+// examplesDocument は、レビューの例のファイルの形式です。
+type examplesDocument struct {
+	Examples []Example `yaml:"examples"`
+}
+
+// LoadExamples は、mode で使用するレビューの例 (few-shot) を読み込みます。
+// path が指定されていればそのファイルを、未指定の場合は repoDir 配下の .gemini-review/examples.yaml を読み込みます。
+// どちらも存在しない場合は nil を返します。
+//
+//	examples:
+//	  - modes: [detail]
+//	    diff: |
+//	      -	return nil
+//	      +	return err
+//	    review: |
+//	      ### [MEDIUM] エラーを握りつぶしていた箇所の修正
+//	      ...
+func LoadExamples(repoDir, path, mode string) ([]Example, error) {
+	if path == "" {
+		if repoDir == "" {
+			return nil, nil
+		}
+		path = filepath.Join(repoDir, RepoConfigDir, examplesFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("レビューの例のファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	var doc examplesDocument
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("レビューの例のファイル '%s' の解析に失敗しました: %w", path, err)
+	}
+
+	var examples []Example
+	for i, ex := range doc.Examples {
+		ex.Diff, ex.Review = strings.TrimSpace(ex.Diff), strings.TrimSpace(ex.Review)
+		if ex.Diff == "" || ex.Review == "" {
+			return nil, fmt.Errorf("レビューの例のファイル '%s' の %d 番目の例に diff と review の両方を指定してください", path, i+1)
+		}
+		if len(ex.Modes) > 0 && !containsMode(ex.Modes, mode) {
+			continue
+		}
+		examples = append(examples, ex)
+	}
+	return examples, nil
+}
+
+// containsMode は、modes に mode が含まれるかを返します。
+func containsMode(modes []string, mode string) bool {
+	for _, m := range modes {
+		if strings.TrimSpace(m) == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// PrependExamples は、プロンプトの先頭にレビューの例を追記します。
+// 例がない場合は、プロンプトをそのまま返します。
+func (b *Builder) PrependExamples(prompt string, examples []Example) (string, error) {
+	if len(examples) == 0 {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	if err := b.examples.Execute(&buf, examples); err != nil {
+		return "", fmt.Errorf("レビューの例のコンテキストの生成に失敗しました: %w", err)
+	}
+	buf.WriteString(prompt)
+	return buf.String(), nil
+}
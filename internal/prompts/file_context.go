@@ -0,0 +1,71 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/imports"
+)
+
+const (
+	// fileContextTemplateFile は、変更後のファイル全体をプロンプトに追加するためのテンプレートファイルです。
+	fileContextTemplateFile = "templates/file_context.md"
+	// importContextTemplateFile は、インポート先の宣言をプロンプトに追加するためのテンプレートファイルです。
+	importContextTemplateFile = "templates/import_context.md"
+)
+
+// FileContent は、変更されたファイルの変更後の内容です。
+type FileContent struct {
+	Path    string
+	Content string
+}
+
+// FileContext は、差分の周辺コードとしてプロンプトに追加するファイルの一覧です。
+type FileContext struct {
+	Files   []FileContent
+	Omitted []string // サイズの上限などにより内容を含めなかったファイル
+}
+
+// ImportContext は、変更されたファイルがインポートしている宣言としてプロンプトに追加する一覧です。
+type ImportContext struct {
+	Declarations []imports.Declaration
+	Omitted      int // サイズの上限により含めなかった宣言の数
+}
+
+// NeedsFileContext は、指定されたモードで差分以外のコンテキスト (--context full-files / imports) を使用するかを返します。
+// コミットログを対象とするモード (変更履歴、コミットメッセージ、リリースノート) では使用しません。
+func NeedsFileContext(mode string) bool {
+	return !commitLogModes[mode]
+}
+
+// AppendFileContext は、プロンプトの末尾に変更後のファイル全体を参考情報として追記します。
+// 追記するファイルがない場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendFileContext(prompt string, fc FileContext) (string, error) {
+	if len(fc.Files) == 0 && len(fc.Omitted) == 0 {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	if err := b.fileContext.Execute(&buf, fc); err != nil {
+		return "", fmt.Errorf("ファイル全体のコンテキストの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// AppendImportContext は、プロンプトの末尾に、変更されたファイルがインポートしている宣言を参考情報として追記します。
+// 追記する宣言がない場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendImportContext(prompt string, ic ImportContext) (string, error) {
+	if len(ic.Declarations) == 0 {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	if err := b.importContext.Execute(&buf, ic); err != nil {
+		return "", fmt.Errorf("インポート先の宣言のコンテキストの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,19 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// findingsInstructionFile は、構造化された指摘事項ブロックの出力を指示するテンプレートファイルです。
+const findingsInstructionFile = "templates/findings_instruction.md"
+
+// AppendFindingsInstruction は、プロンプトの末尾に、指摘事項を機械可読なブロック (findings パッケージで解析可能な形式) として
+// 出力させる指示を追記します。--fail-on など、レビュー結果を機械的に判定する機能で使用します。
+func AppendFindingsInstruction(prompt string) (string, error) {
+	instruction, err := templateFS.ReadFile(findingsInstructionFile)
+	if err != nil {
+		return "", fmt.Errorf("プロンプトテンプレート '%s' の読み込みに失敗しました: %w", findingsInstructionFile, err)
+	}
+	return strings.TrimRight(prompt, "\n") + "\n\n" + string(instruction), nil
+}
@@ -0,0 +1,68 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	// glossaryFile は、リポジトリ内のチームの用語集ファイル名です。
+	glossaryFile = "glossary.md"
+	// glossaryTemplateFile は、用語集をプロンプトに追加するためのテンプレートファイルです。
+	glossaryTemplateFile = "templates/glossary.md"
+	// translateGlossaryTemplateFile は、翻訳のプロンプトに用語集を追加するためのテンプレートファイルです。
+	// レビュー用とは異なり、用語の使い方の指摘は求めず、訳語の統一にのみ使用します。
+	translateGlossaryTemplateFile = "templates/translate_glossary.md"
+)
+
+// LoadGlossary は、チームの用語集 (ドメイン用語、社内サービス名、略語など) を読み込みます。
+// path が指定されていればそのファイルを、未指定の場合は repoDir 配下の .gemini-review/glossary.md を読み込みます。
+// どちらも存在しない場合は空文字を返します。
+func LoadGlossary(repoDir, path string) (string, error) {
+	if path == "" {
+		if repoDir == "" {
+			return "", nil
+		}
+		path = filepath.Join(repoDir, RepoConfigDir, glossaryFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("用語集ファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// AppendGlossary は、プロンプトの末尾にチームの用語集を追記します。
+// 用語集が空の場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendGlossary(prompt, glossary string) (string, error) {
+	return appendGlossary(b.glossary, prompt, glossary)
+}
+
+// AppendTranslateGlossary は、翻訳のプロンプトの末尾に、訳語を揃えるためのチームの用語集を追記します。
+// 用語集が空の場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendTranslateGlossary(prompt, glossary string) (string, error) {
+	return appendGlossary(b.translateGlossary, prompt, glossary)
+}
+
+// appendGlossary は、tmpl で用語集を埋め込んだセクションをプロンプトの末尾に追記します。
+func appendGlossary(tmpl *template.Template, prompt, glossary string) (string, error) {
+	if glossary == "" {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	if err := tmpl.Execute(&buf, glossary); err != nil {
+		return "", fmt.Errorf("用語集のコンテキストの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,60 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/impact"
+)
+
+const (
+	// impactContextTemplateFile は、ビルドグラフから求めた変更の影響範囲をプロンプトに追加するためのテンプレートファイルです。
+	impactContextTemplateFile = "templates/impact_context.md"
+	// maxImpactItems は、プロンプトに列挙する影響を受けるパッケージ・ターゲットの上限です。
+	maxImpactItems = 100
+)
+
+// impactContextData は、影響範囲のテンプレートに埋め込むデータです。列挙しきれないものは件数のみを示します。
+type impactContextData struct {
+	Tool              string
+	Unit              string
+	Changed           []string
+	Dependents        []string
+	TotalChanged      int
+	TotalDependents   int
+	OmittedChanged    int
+	OmittedDependents int
+}
+
+// AppendImpactContext は、プロンプトの末尾に、ビルドグラフから求めた変更の影響範囲を追記します。
+// 変更されたパッケージ・ターゲットがない場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendImpactContext(prompt string, result impact.Result) (string, error) {
+	if len(result.Changed) == 0 {
+		return prompt, nil
+	}
+
+	data := impactContextData{
+		Tool:            result.Tool,
+		Unit:            result.Unit,
+		TotalChanged:    len(result.Changed),
+		TotalDependents: len(result.Dependents),
+	}
+	data.Changed, data.OmittedChanged = truncateList(result.Changed, maxImpactItems)
+	data.Dependents, data.OmittedDependents = truncateList(result.Dependents, maxImpactItems)
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	if err := b.impactContext.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("影響範囲のコンテキストの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// truncateList は、list の先頭 max 件と、省略した件数を返します。
+func truncateList(list []string, max int) ([]string, int) {
+	if len(list) <= max {
+		return list, 0
+	}
+	return list[:max], len(list) - max
+}
@@ -0,0 +1,53 @@
+package prompts
+
+import "sort"
+
+// languageByExt は、ファイル拡張子と言語名の対応表です。
+var languageByExt = map[string]string{
+	".go":   "Go",
+	".py":   "Python",
+	".js":   "JavaScript/TypeScript",
+	".jsx":  "JavaScript/TypeScript",
+	".ts":   "JavaScript/TypeScript",
+	".tsx":  "JavaScript/TypeScript",
+	".java": "Java/Kotlin",
+	".kt":   "Java/Kotlin",
+	".rb":   "Ruby",
+	".php":  "PHP",
+	".rs":   "Rust",
+	".sql":  "SQL",
+}
+
+// performanceHints は、言語ごとにパフォーマンスレビューで特に注意すべき観点です。
+var performanceHints = map[string]string{
+	"Go":                    "ループ内での append による再割り当て (容量の事前確保)、文字列の + 連結、defer のループ内使用、無制限な goroutine 生成、sync.Pool の適否、不要なインターフェース変換によるヒープエスケープ",
+	"Python":                "ループ内のリスト/文字列連結、ORM (Django/SQLAlchemy) の遅延ロードによる N+1 (select_related / joinedload の欠如)、GIL を考慮しないスレッド利用、巨大なリストの一括読み込み (ジェネレータの欠如)",
+	"JavaScript/TypeScript": "ループ内の await による直列化 (Promise.all の検討)、無制限な Promise.all、配列のスプレッドによる O(n^2) のコピー、React の不要な再レンダリング (メモ化の欠如)、イベントリスナーのリーク",
+	"Java/Kotlin":           "ループ内のオブジェクト生成とオートボクシング、JPA/Hibernate の N+1 (fetch join の欠如)、無制限なスレッドプール、ストリームの過剰な中間コレクション生成",
+	"Ruby":                  "ActiveRecord の N+1 (includes/preload の欠如)、each 内でのクエリ発行、全件ロード (find_each の欠如)",
+	"PHP":                   "Eloquent/Doctrine の N+1、ループ内のクエリ発行、全件取得によるメモリ消費",
+	"Rust":                  "不要な clone/to_string、ホットパスでの Box/Vec 割り当て、ロックの保持範囲、async での blocking 呼び出し",
+	"SQL":                   "インデックスの効かない条件 (関数適用・前方一致以外の LIKE)、SELECT *、LIMIT/OFFSET による深いページング、トランザクションの長時間保持",
+}
+
+// LanguageHint は、差分に含まれる言語と、その言語固有のレビュー観点です。
+type LanguageHint struct {
+	Language string
+	Hint     string
+}
+
+// PerformanceHints は、拡張子の一覧から該当する言語のパフォーマンス観点を言語名順に返します。
+func PerformanceHints(exts []string) []LanguageHint {
+	seen := make(map[string]bool)
+	var hints []LanguageHint
+	for _, ext := range exts {
+		lang, ok := languageByExt[ext]
+		if !ok || seen[lang] {
+			continue
+		}
+		seen[lang] = true
+		hints = append(hints, LanguageHint{Language: lang, Hint: performanceHints[lang]})
+	}
+	sort.Slice(hints, func(i, j int) bool { return hints[i].Language < hints[j].Language })
+	return hints
+}
@@ -0,0 +1,153 @@
+package prompts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	// RepoConfigDir は、レビュー対象リポジトリ内のCLI設定ディレクトリ名です。
+	RepoConfigDir = ".gemini-review"
+	// commonOverrideFile は、全モード共通で適用されるプロンプトファイル名です。
+	commonOverrideFile = "prompt.md"
+	// commitConventionFile は、リポジトリ内のチーム独自のコミット規約ファイル名です。
+	commitConventionFile = "commit-convention.md"
+	// replaceDirective は、ファイル先頭に記述するとデフォルトプロンプトを置き換える指示子です。
+	replaceDirective = "<!-- gemini-review: replace -->"
+)
+
+// RepoOverride は、リポジトリに配置されたプロンプト上書きファイルの内容です。
+type RepoOverride struct {
+	Path    string // リポジトリルートからの相対パス
+	Content string // 指示子を除いた本文
+	Replace bool   // true の場合、デフォルトプロンプトを置き換える
+}
+
+// overrideFileNames は、モードに対して適用される上書きファイル名を適用順に返します。
+// 共通ファイル (prompt.md) → モード別ファイル (prompt_<mode>.md) の順に適用されます。
+func overrideFileNames(mode string) []string {
+	return []string{
+		commonOverrideFile,
+		fmt.Sprintf("prompt_%s.md", mode),
+	}
+}
+
+// LoadRepoOverrides は、repoDir 配下の .gemini-review ディレクトリから
+// 指定モードに適用されるプロンプト上書きファイルを読み込みます。
+// ファイルが存在しない場合は空のスライスを返します。
+func LoadRepoOverrides(repoDir, mode string) ([]RepoOverride, error) {
+	var overrides []RepoOverride
+	for _, name := range overrideFileNames(mode) {
+		rel := filepath.Join(RepoConfigDir, name)
+		data, err := os.ReadFile(filepath.Join(repoDir, rel))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("プロンプト上書きファイル '%s' の読み込みに失敗しました: %w", rel, err)
+		}
+
+		content := strings.TrimSpace(string(data))
+		replace := strings.HasPrefix(content, replaceDirective)
+		if replace {
+			content = strings.TrimSpace(strings.TrimPrefix(content, replaceDirective))
+		}
+		if content == "" {
+			continue
+		}
+
+		overrides = append(overrides, RepoOverride{
+			Path:    filepath.ToSlash(rel),
+			Content: content,
+			Replace: replace,
+		})
+	}
+	return overrides, nil
+}
+
+// applyOverrides は、生成済みのプロンプトにリポジトリの上書き内容を適用します。
+// Replace 指定のファイルはテンプレートとして解釈され、デフォルトプロンプトを置き換えます。
+// それ以外のファイルは「プロジェクト固有のレビューガイドライン」として末尾に追記されます。
+func applyOverrides(prompt string, overrides []RepoOverride, data any) (string, error) {
+	var extensions []RepoOverride
+	for _, ov := range overrides {
+		if !ov.Replace {
+			extensions = append(extensions, ov)
+			continue
+		}
+
+		tmpl, err := template.New(ov.Path).Parse(ov.Content)
+		if err != nil {
+			return "", fmt.Errorf("プロンプト上書きファイル '%s' のテンプレート解析に失敗しました: %w", ov.Path, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return "", fmt.Errorf("プロンプト上書きファイル '%s' の適用に失敗しました: %w", ov.Path, err)
+		}
+		prompt = buf.String()
+	}
+
+	if len(extensions) == 0 {
+		return prompt, nil
+	}
+
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\n## プロジェクト固有のレビューガイドライン\n\n")
+	b.WriteString("以下はこのリポジトリのメンテナーが定めたガイドラインです。上記の指示と矛盾する場合は、こちらを優先してください。\n")
+	for _, ov := range extensions {
+		fmt.Fprintf(&b, "\n<!-- %s -->\n%s\n", ov.Path, ov.Content)
+	}
+	return b.String(), nil
+}
+
+// systemInstructionName は、--system-instruction による置き換えをエラーメッセージで示すための名前です。
+const systemInstructionName = "--system-instruction"
+
+// LoadSystemInstruction は、組み込みのプロンプトを置き換えるシステムプロンプトを読み込みます。
+// inline が指定されていればその内容を、path が指定されていればそのファイルを使用します。どちらも未指定の場合は空文字を返します。
+func LoadSystemInstruction(inline, path string) (string, error) {
+	if inline != "" {
+		return strings.TrimSpace(inline), nil
+	}
+	if path == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("システムプロンプトのファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SystemInstructionOverride は、組み込みのプロンプトを置き換えるシステムプロンプトを、置き換え指定の上書き内容として返します。
+// リポジトリの置き換えファイルと同様に、{{.DiffContent}} や {{.Mode}} などのテンプレート変数を使用できます。
+func SystemInstructionOverride(instruction string) RepoOverride {
+	return RepoOverride{Path: systemInstructionName, Content: instruction, Replace: true}
+}
+
+// LoadCommitConvention は、チーム独自のコミット規約を読み込みます。
+// path が指定されていればそのファイルを、未指定の場合は repoDir 配下の .gemini-review/commit-convention.md を読み込みます。
+// どちらも存在しない場合は空文字を返し、Conventional Commits を既定の規約とします。
+func LoadCommitConvention(repoDir, path string) (string, error) {
+	if path == "" {
+		if repoDir == "" {
+			return "", nil
+		}
+		path = filepath.Join(repoDir, RepoConfigDir, commitConventionFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("コミット規約ファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
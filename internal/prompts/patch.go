@@ -0,0 +1,30 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+
+	"git-gemini-cli/internal/findings"
+)
+
+// patchTemplateFile は、修正案の生成のテンプレートファイルです。
+const patchTemplateFile = "templates/prompt_patch.md"
+
+// PatchData は、修正案の生成のプロンプトに埋め込むデータです。
+type PatchData struct {
+	Mode    string
+	Finding findings.Finding
+	Detail  string   // レビュー結果のうち、この指摘事項を説明するセクション
+	Hunk    string   // 指摘事項が対象とする箇所の差分
+	Content string   // 修正対象のファイルの変更後の内容
+	Files   []string // 修正案で変更してよいファイル (差分で変更されたファイル)
+}
+
+// BuildPatch は、1件の指摘事項に対する修正案を unified diff 形式で出力させるプロンプトを生成します。
+func (b *Builder) BuildPatch(data PatchData) (string, error) {
+	var buf bytes.Buffer
+	if err := b.patch.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("修正案のプロンプトの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
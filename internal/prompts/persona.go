@@ -0,0 +1,56 @@
+package prompts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// personaFile は、リポジトリ内のレビュアーのペルソナファイル名です。
+	personaFile = "persona.md"
+	// personaTemplateFile は、ペルソナをプロンプトの先頭に追加するためのテンプレートファイルです。
+	personaTemplateFile = "templates/persona.md"
+)
+
+// LoadPersona は、レビュアーのペルソナ (役割、重視する観点、チームの基準など) を読み込みます。
+// inline が指定されていればその内容を、path が指定されていればそのファイルを、
+// いずれも未指定の場合は repoDir 配下の .gemini-review/persona.md を読み込みます。
+// どれも存在しない場合は空文字を返します。
+func LoadPersona(repoDir, inline, path string) (string, error) {
+	if inline != "" {
+		return strings.TrimSpace(inline), nil
+	}
+	if path == "" {
+		if repoDir == "" {
+			return "", nil
+		}
+		path = filepath.Join(repoDir, RepoConfigDir, personaFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("ペルソナファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// PrependPersona は、プロンプトの先頭にレビュアーのペルソナを追記します。
+// ペルソナが空の場合は、プロンプトをそのまま返します。
+func (b *Builder) PrependPersona(prompt, persona string) (string, error) {
+	if persona == "" {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	if err := b.persona.Execute(&buf, persona); err != nil {
+		return "", fmt.Errorf("ペルソナのコンテキストの生成に失敗しました: %w", err)
+	}
+	buf.WriteString(prompt)
+	return buf.String(), nil
+}
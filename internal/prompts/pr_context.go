@@ -0,0 +1,50 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"git-gemini-cli/internal/adapters"
+)
+
+const (
+	// prContextTemplateFile は、プルリクエストのタイトル・説明・関連するイシューをプロンプトに追加するためのテンプレートファイルです。
+	prContextTemplateFile = "templates/pr_context.md"
+	// maxPRContextChars は、プロンプトに含めるプルリクエストの説明と、関連するイシューの本文のそれぞれの文字数の上限です。
+	maxPRContextChars = 4000
+)
+
+// AppendPRContext は、プロンプトの末尾に、プルリクエストのタイトル・説明・関連するイシューを変更の意図として追記します。
+// md が nil の場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendPRContext(prompt string, md *adapters.PRMetadata) (string, error) {
+	if md == nil {
+		return prompt, nil
+	}
+
+	data := *md
+	data.Title = strings.TrimSpace(data.Title)
+	data.Description = truncateText(data.Description, maxPRContextChars)
+	data.Issues = make([]adapters.LinkedIssue, len(md.Issues))
+	for i, issue := range md.Issues {
+		issue.Body = truncateText(issue.Body, maxPRContextChars)
+		data.Issues[i] = issue
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	if err := b.prContext.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("プルリクエストのコンテキストの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// truncateText は、前後の空白を取り除いた s を、max 文字を超える場合は末尾を省略して返します。
+func truncateText(s string, max int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return string([]rune(s)[:max]) + "\n\n(以降省略)"
+}
@@ -0,0 +1,356 @@
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+	"text/template"
+
+	"git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/commitlint"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/repoweb"
+	"git-gemini-cli/internal/retrieval"
+
+	corePrompts "github.com/shouni/gemini-reviewer-core/pkg/prompts"
+)
+
+const (
+	// ModeRelease は、コアライブラリのテンプレートでリリース可否を判定するモードです。
+	ModeRelease = "release"
+	// ModeExplain は、新メンバー向けに差分の解説を生成するモードです。
+	ModeExplain = "explain"
+	// ModeSecurity は、セキュリティ観点に限定したレビューを行うモードです。
+	ModeSecurity = "security"
+	// ModePerformance は、パフォーマンス観点に限定したレビューを行うモードです。
+	ModePerformance = "performance"
+	// ModeTestGap は、変更された公開シンボルのテスト不足を分析するモードです。
+	ModeTestGap = "test-gap"
+	// ModeChangelog は、差分とコミットログから変更履歴とPR説明文を生成するモードです。
+	ModeChangelog = "changelog"
+	// ModeCommitMsg は、コード差分ではなくコミットメッセージの品質をレビューするモードです。
+	ModeCommitMsg = "commit-msg"
+	// ModeReleaseNotes は、タグ範囲の差分とコミットログからカテゴリ別のリリースノートを生成するモードです。
+	ModeReleaseNotes = "release-notes"
+)
+
+// modeTitles は、複数モードの結果を1つのレポートにまとめる際の各セクションの見出しです。
+var modeTitles = map[string]string{
+	ModeRelease:      "リリース判定",
+	"detail":         "詳細レビュー",
+	ModeExplain:      "変更内容の解説",
+	ModeSecurity:     "セキュリティレビュー",
+	ModePerformance:  "パフォーマンスレビュー",
+	ModeTestGap:      "テスト不足の分析",
+	ModeChangelog:    "変更履歴",
+	ModeCommitMsg:    "コミットメッセージレビュー",
+	ModeReleaseNotes: "リリースノート",
+}
+
+// ModeTitle は、モードの表示用の見出しを返します。未知のモードはモード名をそのまま返します。
+func ModeTitle(mode string) string {
+	if title, ok := modeTitles[mode]; ok {
+		return title
+	}
+	return mode
+}
+
+// askTemplateFile は、ask コマンド用のテンプレートファイルです。
+const askTemplateFile = "templates/prompt_ask.md"
+
+// reduceTemplateFile は、分割レビューの結果を統合する (map-reduce の reduce) ためのテンプレートファイルです。
+const reduceTemplateFile = "templates/prompt_reduce.md"
+
+// reduceFuncs は、統合用・会話用のテンプレートで使用する関数です。
+var reduceFuncs = template.FuncMap{
+	"add":  func(a, b int) int { return a + b },
+	"join": strings.Join,
+}
+
+//go:embed templates/*.md
+var templateFS embed.FS
+
+// localTemplateFiles は、本CLI固有のモードとテンプレートファイルの対応表です。
+// ここに存在しないモードは、コアライブラリのプロンプトビルダーに委譲されます。
+var localTemplateFiles = map[string]string{
+	ModeExplain:      "templates/prompt_explain.md",
+	ModeSecurity:     "templates/prompt_security.md",
+	ModePerformance:  "templates/prompt_performance.md",
+	ModeTestGap:      "templates/prompt_testgap.md",
+	ModeChangelog:    "templates/prompt_changelog.md",
+	ModeCommitMsg:    "templates/prompt_commitmsg.md",
+	ModeReleaseNotes: "templates/prompt_releasenotes.md",
+}
+
+// templateFuncs は、テンプレート内で使用できる関数です。
+var templateFuncs = template.FuncMap{
+	"commitURL": repoweb.CommitURL,
+}
+
+// TemplateData は、プロンプトテンプレートに埋め込むデータです。
+// コアライブラリの TemplateData に、CLI固有モードで使う項目を加えたものです。
+type TemplateData struct {
+	Mode           string
+	DiffContent    string
+	RepoURL        string
+	BaseBranch     string
+	FeatureBranch  string
+	LanguageHints  []LanguageHint
+	ChangedFiles   []string
+	ChangedSymbols []diffutil.Symbol
+	Commits        []adapters.Commit
+
+	// CommitChecks は、コミットメッセージの機械的な形式チェックの結果です (commit-msg モード)。
+	CommitChecks []CommitCheck
+	// CommitConvention は、チーム独自のコミット規約です。空の場合は Conventional Commits を使用します。
+	CommitConvention string
+}
+
+// CommitCheck は、コミットとその形式チェックの結果の組です。
+type CommitCheck struct {
+	adapters.Commit
+	commitlint.Result
+}
+
+// CheckCommits は、コミットの一覧に形式チェックを適用します。
+func CheckCommits(commits []adapters.Commit) []CommitCheck {
+	checks := make([]CommitCheck, 0, len(commits))
+	for _, c := range commits {
+		checks = append(checks, CommitCheck{Commit: c, Result: commitlint.Check(c.Subject, c.Body)})
+	}
+	return checks
+}
+
+// toCore は、コアライブラリのプロンプトビルダーに渡すためのデータに変換します。
+func (d TemplateData) toCore() corePrompts.TemplateData {
+	return corePrompts.TemplateData{DiffContent: d.DiffContent}
+}
+
+// commitLogModes は、プロンプトの生成にコミットログを必要とするモードです。
+var commitLogModes = map[string]bool{
+	ModeChangelog:    true,
+	ModeCommitMsg:    true,
+	ModeReleaseNotes: true,
+}
+
+// NeedsCommitLog は、指定されたモードがコミットログを必要とするかを返します。
+func NeedsCommitLog(mode string) bool {
+	return commitLogModes[mode]
+}
+
+// Builder は、CLI固有のテンプレートとコアライブラリのテンプレートを統合するプロンプトビルダーです。
+type Builder struct {
+	core              corePrompts.ReviewPromptBuilder
+	templates         map[string]*template.Template
+	ask               *template.Template
+	reduce            *template.Template
+	fileContext       *template.Template
+	importContext     *template.Template
+	glossary          *template.Template
+	assetContext      *template.Template
+	impactContext     *template.Template
+	prContext         *template.Template
+	translate         *template.Template
+	translateGlossary *template.Template
+	persona           *template.Template
+	chat              *template.Template
+	verify            *template.Template
+	patch             *template.Template
+	rubric            *template.Template
+	examples          *template.Template
+}
+
+// ReducePart は、分割してレビューした結果の1つです。
+type ReducePart struct {
+	Files  []string // このパートでレビューしたファイル
+	Result string   // このパートのレビュー結果
+}
+
+// ReduceData は、分割レビューの結果を統合するプロンプトに埋め込むデータです。
+type ReduceData struct {
+	Mode  string
+	Parts []ReducePart
+}
+
+// ModeTitle は、統合対象のレビューのモードの見出しを返します。
+func (d ReduceData) ModeTitle() string {
+	return ModeTitle(d.Mode)
+}
+
+// AskData は、ask コマンドのプロンプトに埋め込むデータです。
+type AskData struct {
+	Question string
+	Snippets []retrieval.Snippet
+}
+
+// NewBuilder は、埋め込みテンプレートを解析し、Builder を初期化します。
+func NewBuilder() (*Builder, error) {
+	core, err := corePrompts.NewPromptBuilder()
+	if err != nil {
+		return nil, fmt.Errorf("コアライブラリのプロンプトビルダーの構築に失敗しました: %w", err)
+	}
+
+	templates := make(map[string]*template.Template, len(localTemplateFiles))
+	for mode, file := range localTemplateFiles {
+		tmpl, err := template.New(path.Base(file)).Funcs(templateFuncs).ParseFS(templateFS, file)
+		if err != nil {
+			return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", file, err)
+		}
+		templates[mode] = tmpl
+	}
+
+	ask, err := template.ParseFS(templateFS, askTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", askTemplateFile, err)
+	}
+
+	reduce, err := template.New(path.Base(reduceTemplateFile)).Funcs(reduceFuncs).ParseFS(templateFS, reduceTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", reduceTemplateFile, err)
+	}
+
+	fileContext, err := template.ParseFS(templateFS, fileContextTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", fileContextTemplateFile, err)
+	}
+
+	importContext, err := template.ParseFS(templateFS, importContextTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", importContextTemplateFile, err)
+	}
+
+	glossary, err := template.ParseFS(templateFS, glossaryTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", glossaryTemplateFile, err)
+	}
+
+	assetContext, err := template.ParseFS(templateFS, assetContextTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", assetContextTemplateFile, err)
+	}
+
+	impactContext, err := template.ParseFS(templateFS, impactContextTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", impactContextTemplateFile, err)
+	}
+
+	prContext, err := template.ParseFS(templateFS, prContextTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", prContextTemplateFile, err)
+	}
+
+	translate, err := template.ParseFS(templateFS, translateTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", translateTemplateFile, err)
+	}
+
+	translateGlossary, err := template.ParseFS(templateFS, translateGlossaryTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", translateGlossaryTemplateFile, err)
+	}
+
+	persona, err := template.ParseFS(templateFS, personaTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", personaTemplateFile, err)
+	}
+
+	chat, err := template.New(path.Base(chatTemplateFile)).Funcs(reduceFuncs).ParseFS(templateFS, chatTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", chatTemplateFile, err)
+	}
+
+	verify, err := template.ParseFS(templateFS, verifyTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", verifyTemplateFile, err)
+	}
+
+	patch, err := template.ParseFS(templateFS, patchTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", patchTemplateFile, err)
+	}
+
+	rubric, err := template.ParseFS(templateFS, rubricTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", rubricTemplateFile, err)
+	}
+
+	examples, err := template.New(path.Base(examplesTemplateFile)).Funcs(reduceFuncs).ParseFS(templateFS, examplesTemplateFile)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプトテンプレート '%s' の解析に失敗しました: %w", examplesTemplateFile, err)
+	}
+
+	return &Builder{
+		core:              core,
+		templates:         templates,
+		ask:               ask,
+		reduce:            reduce,
+		fileContext:       fileContext,
+		importContext:     importContext,
+		glossary:          glossary,
+		assetContext:      assetContext,
+		impactContext:     impactContext,
+		prContext:         prContext,
+		translate:         translate,
+		translateGlossary: translateGlossary,
+		persona:           persona,
+		chat:              chat,
+		verify:            verify,
+		patch:             patch,
+		rubric:            rubric,
+		examples:          examples,
+	}, nil
+}
+
+// Build は、モードに対応するテンプレートにデータを埋め込み、最終的なプロンプトを返します。
+func (b *Builder) Build(mode string, data TemplateData) (string, error) {
+	tmpl, ok := b.templates[mode]
+	if !ok {
+		// CLI固有のモードでない場合はコアライブラリに委譲
+		return b.core.Build(mode, data.toCore())
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("モード '%s' のプロンプト生成に失敗しました: %w", mode, err)
+	}
+
+	return buf.String(), nil
+}
+
+// BuildWithOverrides は、Build で生成したプロンプトにリポジトリ内の上書き内容を適用します。
+func (b *Builder) BuildWithOverrides(mode string, data TemplateData, overrides []RepoOverride) (string, error) {
+	prompt, err := b.Build(mode, data)
+	if err != nil {
+		return "", err
+	}
+	return applyOverrides(prompt, overrides, data)
+}
+
+// BuildAsk は、質問と関連スニペットから ask コマンド用のプロンプトを生成します。
+func (b *Builder) BuildAsk(data AskData) (string, error) {
+	var buf bytes.Buffer
+	if err := b.ask.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("質問応答プロンプトの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// BuildReduce は、分割してレビューした結果を1つのレポートに統合させるプロンプトを生成します。
+func (b *Builder) BuildReduce(data ReduceData) (string, error) {
+	var buf bytes.Buffer
+	if err := b.reduce.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("統合プロンプトの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// BuildTranslate は、レビュー結果のレポートを他の言語に翻訳させるプロンプトを生成します。
+func (b *Builder) BuildTranslate(data TranslateData) (string, error) {
+	var buf bytes.Buffer
+	if err := b.translate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("翻訳プロンプトの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,19 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// redactionNoticeFile は、機密情報をマスクしたことを AI に伝えるテンプレートファイルです。
+const redactionNoticeFile = "templates/redaction_notice.md"
+
+// AppendRedactionNotice は、プロンプトの末尾に、機密情報をプレースホルダに置き換えたことの説明を追記します。
+// プレースホルダを不正な値として指摘させず、機密情報の直接記述として指摘させるために使用します。
+func AppendRedactionNotice(prompt string) (string, error) {
+	notice, err := templateFS.ReadFile(redactionNoticeFile)
+	if err != nil {
+		return "", fmt.Errorf("プロンプトテンプレート '%s' の読み込みに失敗しました: %w", redactionNoticeFile, err)
+	}
+	return strings.TrimRight(prompt, "\n") + "\n\n" + string(notice), nil
+}
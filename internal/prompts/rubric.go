@@ -0,0 +1,35 @@
+package prompts
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/rubric"
+)
+
+// rubricTemplateFile は、リリース判定の評価項目ごとのスコアを出力させる指示のテンプレートファイルです。
+const rubricTemplateFile = "templates/rubric_instruction.md"
+
+// rubricData は、評価項目ごとのスコアを出力させる指示に埋め込むデータです。
+type rubricData struct {
+	Criteria   []rubric.Criterion
+	MaxScore   int
+	BlockStart string
+}
+
+// AppendRubric は、プロンプトの末尾に、チームの評価基準の各項目を採点し、スコアを機械可読な形式で出力させる指示を追記します。
+// 評価基準が nil の場合は、プロンプトをそのまま返します。
+func (b *Builder) AppendRubric(prompt string, r *rubric.Rubric) (string, error) {
+	if r == nil {
+		return prompt, nil
+	}
+
+	var buf strings.Builder
+	buf.WriteString(strings.TrimRight(prompt, "\n"))
+	buf.WriteString("\n\n")
+	data := rubricData{Criteria: r.Criteria, MaxScore: rubric.MaxScore, BlockStart: rubric.BlockStart}
+	if err := b.rubric.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("評価基準の指示の生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
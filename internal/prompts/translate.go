@@ -0,0 +1,42 @@
+package prompts
+
+// translateTemplateFile は、レビュー結果のレポートを他の言語に翻訳するためのテンプレートファイルです。
+const translateTemplateFile = "templates/prompt_translate.md"
+
+// ReportLanguage は、テンプレートが生成するレポートの言語 (言語コード) です。
+const ReportLanguage = "ja"
+
+// languageNames は、言語コードと、翻訳プロンプトおよび言語の切り替えリンクに使用する言語名の対応表です。
+var languageNames = map[string]string{
+	"ja":    "日本語",
+	"en":    "English",
+	"zh":    "简体中文",
+	"zh-tw": "繁體中文",
+	"ko":    "한국어",
+	"es":    "Español",
+	"fr":    "Français",
+	"de":    "Deutsch",
+	"pt":    "Português",
+	"vi":    "Tiếng Việt",
+	"th":    "ไทย",
+	"id":    "Bahasa Indonesia",
+}
+
+// LanguageName は、言語コードに対応する言語名を返します。未知の言語コードはそのまま返します。
+func LanguageName(code string) string {
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// TranslateData は、レポートを翻訳するプロンプトに埋め込むデータです。
+type TranslateData struct {
+	Language string // 翻訳先の言語コード (例: "en")
+	Report   string // 翻訳するレポート
+}
+
+// LanguageName は、翻訳先の言語名を返します。
+func (d TranslateData) LanguageName() string {
+	return LanguageName(d.Language)
+}
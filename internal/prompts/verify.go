@@ -0,0 +1,28 @@
+package prompts
+
+import (
+	"bytes"
+	"fmt"
+
+	"git-gemini-cli/internal/findings"
+)
+
+// verifyTemplateFile は、指摘事項の検証のテンプレートファイルです。
+const verifyTemplateFile = "templates/prompt_verify.md"
+
+// VerifyData は、指摘事項の検証のプロンプトに埋め込むデータです。
+type VerifyData struct {
+	Mode    string
+	Finding findings.Finding
+	Detail  string // レビュー結果のうち、この指摘事項を説明するセクション
+	Hunk    string // 指摘事項が対象とする箇所の差分
+}
+
+// BuildVerify は、1件の指摘事項が差分のコードに照らして妥当かを判定させるプロンプトを生成します。
+func (b *Builder) BuildVerify(data VerifyData) (string, error) {
+	var buf bytes.Buffer
+	if err := b.verify.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("指摘事項の検証プロンプトの生成に失敗しました: %w", err)
+	}
+	return buf.String(), nil
+}
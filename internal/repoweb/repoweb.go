@@ -0,0 +1,70 @@
+package repoweb
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WebURL は、Git リポジトリのURL (SSH / HTTPS) をブラウザで開けるURLに変換します。
+// 例: git@github.com:org/repo.git → https://github.com/org/repo
+// 変換できない場合は空文字を返します。
+func WebURL(repoURL string) string {
+	host, path := HostPath(repoURL)
+	if host == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s/%s", host, path)
+}
+
+// HostPath は、Git リポジトリのURL (SSH / HTTPS) からホスト名と、".git" を除いたリポジトリのパスを返します。
+// 例: git@github.com:org/repo.git → ("github.com", "org/repo")
+// 解析できない場合は空文字を返します。
+func HostPath(repoURL string) (host, path string) {
+	repoURL = strings.TrimSpace(repoURL)
+	if repoURL == "" {
+		return "", ""
+	}
+
+	switch {
+	case strings.HasPrefix(repoURL, "http://"), strings.HasPrefix(repoURL, "https://"), strings.HasPrefix(repoURL, "ssh://"):
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return "", ""
+		}
+		host, path = u.Hostname(), u.Path
+	default:
+		// scp 形式: [user@]host:path
+		at := strings.LastIndex(repoURL, "@")
+		rest := repoURL[at+1:]
+		h, p, ok := strings.Cut(rest, ":")
+		if !ok {
+			return "", ""
+		}
+		host, path = h, p
+	}
+
+	path = strings.Trim(strings.TrimSuffix(strings.TrimSpace(path), ".git"), "/")
+	if host == "" || path == "" {
+		return "", ""
+	}
+	return host, path
+}
+
+// CommitURL は、リポジトリのWebURL上でコミットを表示するURLを返します。
+// ホスティングサービスごとのパス形式 (GitHub: /commit/, GitLab: /-/commit/, Bitbucket: /commits/) に対応します。
+func CommitURL(repoURL, hash string) string {
+	web := WebURL(repoURL)
+	if web == "" || hash == "" {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(web, "gitlab"):
+		return fmt.Sprintf("%s/-/commit/%s", web, hash)
+	case strings.Contains(web, "bitbucket"):
+		return fmt.Sprintf("%s/commits/%s", web, hash)
+	default:
+		return fmt.Sprintf("%s/commit/%s", web, hash)
+	}
+}
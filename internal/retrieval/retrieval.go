@@ -0,0 +1,281 @@
+package retrieval
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// defaultMaxFiles は、結果に含めるファイル数の上限です。
+	defaultMaxFiles = 8
+	// defaultMaxSnippetsPerFile は、1ファイルあたりに含めるスニペット数の上限です。
+	defaultMaxSnippetsPerFile = 3
+	// defaultContextLines は、ヒット行の前後に含める行数です。
+	defaultContextLines = 6
+	// maxFileSize は、検索対象とするファイルサイズの上限です。これを超えるファイルは読み飛ばします。
+	maxFileSize = 512 * 1024
+)
+
+// skipDirs は、検索対象から除外するディレクトリ名です。
+var skipDirs = map[string]bool{
+	".git":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"dist":         true,
+	"build":        true,
+	".idea":        true,
+	".vscode":      true,
+}
+
+// stopWords は、キーワード抽出時に無視する一般的な英単語です。
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "with": true,
+	"where": true, "what": true, "which": true, "how": true, "why": true, "who": true,
+	"does": true, "this": true, "that": true, "from": true, "into": true, "implemented": true,
+	"code": true, "file": true, "files": true, "function": true, "logic": true,
+}
+
+// Options は、検索の挙動を制御します。ゼロ値の場合はデフォルト値が使われます。
+type Options struct {
+	MaxFiles           int
+	MaxSnippetsPerFile int
+	ContextLines       int
+}
+
+// Snippet は、リポジトリ内のファイルから抽出されたコード片です。
+// StartLine と EndLine は1始まりの行番号です。
+type Snippet struct {
+	Path      string
+	StartLine int
+	EndLine   int
+	Content   string
+	Score     int
+}
+
+// Citation は、スニペットを 'path:start-end' 形式で返します。
+func (s Snippet) Citation() string {
+	if s.StartLine == s.EndLine {
+		return fmt.Sprintf("%s:%d", s.Path, s.StartLine)
+	}
+	return fmt.Sprintf("%s:%d-%d", s.Path, s.StartLine, s.EndLine)
+}
+
+// NumberedContent は、各行に行番号を付与したスニペット本文を返します。
+func (s Snippet) NumberedContent() string {
+	var b strings.Builder
+	for i, line := range strings.Split(s.Content, "\n") {
+		fmt.Fprintf(&b, "%5d | %s\n", s.StartLine+i, line)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Keywords は、自然文の質問から検索用のキーワードを抽出します。
+// 英数字の連続を単語とみなし、短すぎる単語とストップワードは除外します。
+func Keywords(query string) []string {
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_')
+	})
+
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, f := range fields {
+		kw := strings.ToLower(f)
+		if len([]rune(kw)) < 3 || stopWords[kw] || seen[kw] {
+			continue
+		}
+		seen[kw] = true
+		keywords = append(keywords, kw)
+	}
+	return keywords
+}
+
+// Retrieve は、root 配下のテキストファイルから query に関連するスニペットを抽出します。
+// スコアはキーワードの出現行数に基づく単純なもので、スコアの高いファイルから順に返します。
+func Retrieve(root, query string, opts Options) ([]Snippet, error) {
+	opts = opts.withDefaults()
+
+	keywords := Keywords(query)
+	if len(keywords) == 0 {
+		return nil, fmt.Errorf("質問から検索キーワードを抽出できませんでした: %q", query)
+	}
+
+	var results []fileResult
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDirs[d.Name()] && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil || info.Size() > maxFileSize {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+
+		res, err := scanFile(path, filepath.ToSlash(rel), keywords)
+		if err != nil {
+			return err
+		}
+		if res.score > 0 {
+			results = append(results, res)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("リポジトリの走査に失敗しました (root: %s): %w", root, err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].path < results[j].path
+	})
+	if len(results) > opts.MaxFiles {
+		results = results[:opts.MaxFiles]
+	}
+
+	var snippets []Snippet
+	for _, res := range results {
+		snippets = append(snippets, res.snippets(opts)...)
+	}
+	return snippets, nil
+}
+
+// withDefaults は、未設定の項目にデフォルト値を補完した Options を返します。
+func (o Options) withDefaults() Options {
+	if o.MaxFiles <= 0 {
+		o.MaxFiles = defaultMaxFiles
+	}
+	if o.MaxSnippetsPerFile <= 0 {
+		o.MaxSnippetsPerFile = defaultMaxSnippetsPerFile
+	}
+	if o.ContextLines <= 0 {
+		o.ContextLines = defaultContextLines
+	}
+	return o
+}
+
+// fileResult は、1ファイル分の検索結果です。
+type fileResult struct {
+	path  string
+	lines []string
+	hits  map[int]int // 行インデックス(0始まり) -> その行でヒットしたキーワード数
+	score int
+}
+
+// scanFile は、ファイルを読み込み、キーワードにヒットした行を記録します。
+// バイナリファイル (NULバイトを含む) はスコア0として扱います。
+func scanFile(path, rel string, keywords []string) (fileResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fileResult{}, fmt.Errorf("ファイルの読み込みに失敗しました (%s): %w", rel, err)
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return fileResult{}, nil
+	}
+
+	res := fileResult{path: rel, hits: make(map[int]int)}
+	lowerPath := strings.ToLower(rel)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	for i := 0; scanner.Scan(); i++ {
+		line := scanner.Text()
+		res.lines = append(res.lines, line)
+
+		lower := strings.ToLower(line)
+		for _, kw := range keywords {
+			if strings.Contains(lower, kw) {
+				res.hits[i]++
+				res.score++
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		// 極端に長い行などで走査できないファイルは対象外とする
+		return fileResult{}, nil
+	}
+
+	// パスにキーワードを含むファイルは関連性が高いとみなす
+	for _, kw := range keywords {
+		if res.score > 0 && strings.Contains(lowerPath, kw) {
+			res.score += 3
+		}
+	}
+
+	return res, nil
+}
+
+// snippets は、ヒット行の周辺をスニペットとして切り出します。
+// 重なり合う範囲は1つのスニペットに統合します。
+func (r fileResult) snippets(opts Options) []Snippet {
+	hitLines := make([]int, 0, len(r.hits))
+	for i := range r.hits {
+		hitLines = append(hitLines, i)
+	}
+	// ヒット数の多い行を優先し、同数なら行番号順
+	sort.Slice(hitLines, func(a, b int) bool {
+		if r.hits[hitLines[a]] != r.hits[hitLines[b]] {
+			return r.hits[hitLines[a]] > r.hits[hitLines[b]]
+		}
+		return hitLines[a] < hitLines[b]
+	})
+
+	type span struct{ start, end, score int }
+	var spans []span
+	for _, line := range hitLines {
+		start := max(0, line-opts.ContextLines)
+		end := min(len(r.lines)-1, line+opts.ContextLines)
+
+		merged := false
+		for i := range spans {
+			if start <= spans[i].end && end >= spans[i].start {
+				spans[i].start = min(spans[i].start, start)
+				spans[i].end = max(spans[i].end, end)
+				spans[i].score += r.hits[line]
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			if len(spans) >= opts.MaxSnippetsPerFile {
+				continue
+			}
+			spans = append(spans, span{start: start, end: end, score: r.hits[line]})
+		}
+	}
+
+	sort.Slice(spans, func(a, b int) bool { return spans[a].start < spans[b].start })
+
+	snippets := make([]Snippet, 0, len(spans))
+	for _, s := range spans {
+		snippets = append(snippets, Snippet{
+			Path:      r.path,
+			StartLine: s.start + 1,
+			EndLine:   s.end + 1,
+			Content:   strings.Join(r.lines[s.start:s.end+1], "\n"),
+			Score:     s.score,
+		})
+	}
+	return snippets
+}
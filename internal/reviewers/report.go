@@ -0,0 +1,48 @@
+package reviewers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxReportFiles は、レビュアーごとに列挙する経験のあるファイルの上限です。
+const maxReportFiles = 5
+
+// Report は、推奨結果を Markdown のレポートとして出力します。
+func Report(list []Suggestion, changes []Change) string {
+	unowned := 0
+	for _, ch := range changes {
+		if len(ch.Owners) == 0 {
+			unowned++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("## 👀 レビュアーの推奨\n\n")
+	fmt.Fprintf(&b, "変更されたファイル: **%d 件** (CODEOWNERS の所有者がいないファイル: %d 件)\n\n", len(changes), unowned)
+	if len(list) == 0 {
+		b.WriteString("CODEOWNERS と変更履歴から推奨できるレビュアーが見つかりませんでした。\n")
+		return b.String()
+	}
+
+	b.WriteString("| 順位 | レビュアー | スコア | 所有するファイル | 過去のコミット | 担当中のレビュー |\n")
+	b.WriteString("| ---: | :--- | ---: | ---: | ---: | ---: |\n")
+	for i, s := range list {
+		fmt.Fprintf(&b, "| %d | %s | %.2f | %d | %d | %d |\n", i+1, s.Reviewer, s.Score, s.OwnedFiles, s.Commits, s.OpenReviews)
+	}
+
+	b.WriteString("\n")
+	for _, s := range list {
+		shown, omitted := s.Files, 0
+		if len(shown) > maxReportFiles {
+			shown, omitted = shown[:maxReportFiles], len(shown)-maxReportFiles
+		}
+		fmt.Fprintf(&b, "- **%s**: `%s`", s.Reviewer, strings.Join(shown, "`, `"))
+		if omitted > 0 {
+			fmt.Fprintf(&b, " ほか %d 件", omitted)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\nスコアは、CODEOWNERS で所有する変更ファイル (1件につき 2 点) と、変更ファイルに対する過去のコミット (90日で半減) の合計を、担当中のレビューの件数 + 1 で割った値です。\n")
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package reviewers
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ownerWeight は、変更されたファイル1件を CODEOWNERS で所有していることに対するスコアです。
+	ownerWeight = 2.0
+	// historyHalfLife は、過去の変更による経験のスコアが半減するまでの期間です。
+	historyHalfLife = 90 * 24 * time.Hour
+)
+
+// Member は、レビュアー候補の識別情報です。
+type Member struct {
+	Handle string   `yaml:"handle"` // CODEOWNERS で使用するハンドル (例: "@alice")
+	Emails []string `yaml:"emails"` // Git のコミットで使用するメールアドレス
+	Away   bool     `yaml:"away"`   // 不在 (休暇など) のため候補から除外する
+}
+
+// Team は、レビュアー候補の一覧 (チームファイル) です。
+type Team struct {
+	Members []Member `yaml:"members"`
+}
+
+// LoadTeam は、YAML 形式のチームファイルを読み込みます。
+//
+//	members:
+//	  - handle: "@alice"
+//	    emails: [alice@example.com]
+func LoadTeam(path string) (Team, error) {
+	var team Team
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return team, fmt.Errorf("チームファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &team); err != nil {
+		return team, fmt.Errorf("チームファイル '%s' の解析に失敗しました: %w", path, err)
+	}
+	return team, nil
+}
+
+// Change は、推奨の根拠となる変更されたファイルとその所有者です。
+type Change struct {
+	Path   string
+	Owners []string // CODEOWNERS の所有者
+}
+
+// Contribution は、変更されたファイルに対する過去の1コミットです。
+type Contribution struct {
+	Name  string
+	Email string
+	Date  time.Time
+	Files []string
+}
+
+// Input は、レビュアーの推奨に使用する情報です。
+type Input struct {
+	Changes      []Change
+	History      []Contribution
+	Team         Team
+	Load         map[string]int // レビュアー (小文字のハンドルまたは作成者名) ごとの担当中のレビューの件数
+	Authors      []string       // PR の作成者 (コミットの作成者のメールアドレス)。候補から除外する
+	Now          time.Time      // 経験のスコアの減衰の基準時刻
+	Count        int            // 推奨する人数
+	IncludeTeams bool           // true の場合、チーム ("@org/team") も候補に含める
+}
+
+// Suggestion は、1人のレビュアー候補の推奨結果です。
+type Suggestion struct {
+	Reviewer    string   // ハンドル (チームファイルで対応付けられない場合は作成者名)
+	Score       float64  // 負荷を考慮した最終的なスコア
+	Expertise   float64  // 負荷を考慮する前のスコア
+	OwnedFiles  int      // CODEOWNERS で所有している変更されたファイルの件数
+	Commits     int      // 変更されたファイルに対する過去のコミット件数
+	OpenReviews int      // 担当中のレビューの件数 (レビューの履歴から求めたもの)
+	Files       []string // 経験のある変更されたファイル (所有または過去に変更)
+}
+
+// candidate は、スコアを集計中のレビュアー候補です。
+type candidate struct {
+	Suggestion
+	files map[string]bool
+}
+
+// Suggest は、CODEOWNERS による所有と、変更されたファイルに対する過去のコミットから各候補の経験をスコア化し、
+// 現在の負荷で割り引いた上で、スコアの高い順に推奨するレビュアーを返します。
+// 経験のスコアは、所有するファイル1件につき ownerWeight、過去のコミット1件につき経過時間で半減する 1 点です。
+func Suggest(in Input) []Suggestion {
+	byHandle := make(map[string]Member)
+	byIdentity := make(map[string]Member)
+	for _, m := range in.Team.Members {
+		byHandle[strings.ToLower(m.Handle)] = m
+		for _, e := range m.Emails {
+			byIdentity[strings.ToLower(e)] = m
+		}
+	}
+	excluded := make(map[string]bool)
+	for _, a := range in.Authors {
+		a = strings.ToLower(a)
+		excluded[a] = true
+		if m, ok := byIdentity[a]; ok {
+			excluded[strings.ToLower(m.Handle)] = true
+		}
+	}
+
+	candidates := make(map[string]*candidate)
+	get := func(id string) *candidate {
+		key := strings.ToLower(id)
+		c, ok := candidates[key]
+		if !ok {
+			c = &candidate{Suggestion: Suggestion{Reviewer: id, OpenReviews: in.Load[key]}, files: make(map[string]bool)}
+			candidates[key] = c
+		}
+		return c
+	}
+
+	for _, ch := range in.Changes {
+		for _, owner := range ch.Owners {
+			if excluded[strings.ToLower(owner)] || (isTeam(owner) && !in.IncludeTeams) {
+				continue
+			}
+			c := get(owner)
+			c.OwnedFiles++
+			c.Expertise += ownerWeight
+			c.files[ch.Path] = true
+		}
+	}
+
+	for _, h := range in.History {
+		email, name := strings.ToLower(h.Email), strings.ToLower(h.Name)
+		if excluded[email] || excluded[name] {
+			continue
+		}
+		id := h.Name
+		if m, ok := byIdentity[email]; ok {
+			if excluded[strings.ToLower(m.Handle)] {
+				continue
+			}
+			id = m.Handle
+		}
+		c := get(id)
+		c.Commits++
+		age := in.Now.Sub(h.Date)
+		if age < 0 {
+			age = 0
+		}
+		c.Expertise += math.Pow(0.5, float64(age)/float64(historyHalfLife))
+		for _, f := range h.Files {
+			c.files[f] = true
+		}
+	}
+
+	var list []Suggestion
+	for key, c := range candidates {
+		if m, ok := byHandle[key]; ok && m.Away {
+			continue
+		}
+		c.Score = c.Expertise / float64(1+c.OpenReviews)
+		for f := range c.files {
+			c.Files = append(c.Files, f)
+		}
+		sort.Strings(c.Files)
+		list = append(list, c.Suggestion)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		return list[i].Reviewer < list[j].Reviewer
+	})
+	if in.Count > 0 && len(list) > in.Count {
+		list = list[:in.Count]
+	}
+	return list
+}
+
+// isTeam は、CODEOWNERS の所有者がチーム ("@org/team") かを返します。
+func isTeam(owner string) bool {
+	return strings.HasPrefix(owner, "@") && strings.Contains(owner, "/")
+}
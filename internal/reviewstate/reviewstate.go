@@ -0,0 +1,186 @@
+package reviewstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/lockfile"
+	"git-gemini-cli/internal/objectstore"
+)
+
+// maxSaveAttempts は、ストレージ上の状態ファイルを他の実行と同時に更新した場合に、読み直して再試行する回数の上限です。
+const maxSaveAttempts = 3
+
+// saveLockTimeout は、ローカルの状態ファイルを他の実行が更新中の場合に、ロックの解放を待機する時間の上限です。
+const saveLockTimeout = 30 * time.Second
+
+// Entry は、1つのリポジトリ・ブランチについて、前回レビューしたコミットの記録です。
+type Entry struct {
+	HeadSHA    string    `json:"headSha"`
+	ReviewedAt time.Time `json:"reviewedAt"`
+}
+
+// file は、状態ファイルの内容です。
+type file struct {
+	Entries map[string]Entry `json:"entries"`
+}
+
+// Key は、リポジトリとベース・ヘッドの参照の組から、状態ファイル内のキーを返します。
+func Key(repoURL, baseRef, headRef string) string {
+	return repoURL + "#" + baseRef + "..." + headRef
+}
+
+// DefaultURI は、状態ファイルの既定の保存先 (ユーザーのキャッシュディレクトリ配下) を返します。
+func DefaultURI() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(dir, "git-gemini-cli", "review-state.json"), nil
+}
+
+// Load は、状態ファイル (ローカルパス、gs:// または s3://) から key の記録を読み込みます。
+// 状態ファイルや key の記録が存在しない場合は false を返します。
+func Load(ctx context.Context, uri, key string) (Entry, bool, error) {
+	f, _, err := read(ctx, uri)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	e, ok := f.Entries[key]
+	return e, ok, nil
+}
+
+// Save は、状態ファイルの key の記録を更新します。他の key の記録は保持します。
+// gs:// と s3:// では条件付き書き込みを使用し、他の実行と同時に更新した場合は読み直して再試行します。
+// ローカルパスでは、他の実行の記録を上書きしないよう、読み込みから書き込みまで状態ファイルのロックを保持します。
+func Save(ctx context.Context, uri, key string, entry Entry) error {
+	if !isRemote(uri) {
+		lock, err := lockfile.Acquire(ctx, lockfile.PathFor(uri), saveLockTimeout)
+		if err != nil {
+			return fmt.Errorf("レビューの状態 '%s' のロック取得に失敗しました: %w", uri, err)
+		}
+		defer func() {
+			if err := lock.Release(); err != nil {
+				slog.Warn("レビューの状態のロック解放に失敗しました。", "uri", uri, "error", err)
+			}
+		}()
+
+		f, _, err := read(ctx, uri)
+		if err != nil {
+			return err
+		}
+		f.Entries[key] = entry
+		return writeLocal(uri, f)
+	}
+
+	store, err := objectstore.New(ctx, uri)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	for attempt := 1; ; attempt++ {
+		f, version, err := readRemote(ctx, store, uri)
+		if err != nil {
+			return err
+		}
+		f.Entries[key] = entry
+		data, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			return fmt.Errorf("レビューの状態の変換に失敗しました: %w", err)
+		}
+		err = store.WriteIf(ctx, uri, data, "application/json", version)
+		if !errors.Is(err, objectstore.ErrPreconditionFailed) || attempt >= maxSaveAttempts {
+			if err != nil {
+				return fmt.Errorf("レビューの状態 '%s' の書き込みに失敗しました: %w", uri, err)
+			}
+			return nil
+		}
+	}
+}
+
+// isRemote は、状態ファイルの保存先がクラウドストレージかを返します。
+func isRemote(uri string) bool {
+	return strings.HasPrefix(uri, "gs://") || strings.HasPrefix(uri, "s3://")
+}
+
+// read は、状態ファイルの内容とバージョン (クラウドストレージの場合) を返します。存在しない場合は空の内容を返します。
+func read(ctx context.Context, uri string) (file, string, error) {
+	if !isRemote(uri) {
+		data, err := os.ReadFile(uri)
+		if errors.Is(err, os.ErrNotExist) {
+			return file{Entries: make(map[string]Entry)}, "", nil
+		}
+		if err != nil {
+			return file{}, "", fmt.Errorf("レビューの状態 '%s' の読み込みに失敗しました: %w", uri, err)
+		}
+		f, err := decode(uri, data)
+		return f, "", err
+	}
+
+	store, err := objectstore.New(ctx, uri)
+	if err != nil {
+		return file{}, "", err
+	}
+	defer store.Close()
+	return readRemote(ctx, store, uri)
+}
+
+// readRemote は、クラウドストレージ上の状態ファイルの内容とバージョンを返します。
+func readRemote(ctx context.Context, store objectstore.Store, uri string) (file, string, error) {
+	obj, err := store.Read(ctx, uri)
+	if errors.Is(err, objectstore.ErrNotFound) {
+		return file{Entries: make(map[string]Entry)}, "", nil
+	}
+	if err != nil {
+		return file{}, "", fmt.Errorf("レビューの状態 '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	f, err := decode(uri, obj.Data)
+	return f, obj.Version, err
+}
+
+// decode は、状態ファイルの内容を解析します。
+func decode(uri string, data []byte) (file, error) {
+	var f file
+	if err := json.Unmarshal(data, &f); err != nil {
+		return file{}, fmt.Errorf("レビューの状態 '%s' の解析に失敗しました: %w", uri, err)
+	}
+	if f.Entries == nil {
+		f.Entries = make(map[string]Entry)
+	}
+	return f, nil
+}
+
+// writeLocal は、状態ファイルを一時ファイル経由で置き換えます。書き込み途中の内容を他の実行が読まないようにするためです。
+func writeLocal(path string, f file) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("レビューの状態の変換に失敗しました: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("レビューの状態の保存先ディレクトリの作成に失敗しました: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("レビューの状態 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("レビューの状態 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("レビューの状態 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("レビューの状態 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package rubric
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"git-gemini-cli/internal/findings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MaxScore は、各評価項目のスコアの上限です (0〜MaxScore の整数)。
+	MaxScore = 10
+	// DefaultThreshold は、しきい値が指定されていない場合の総合スコア (0〜100) のしきい値です。
+	DefaultThreshold = 70
+	// DefaultSeverity は、総合スコアがしきい値を下回った場合に追加する指摘事項の既定の深刻度です。
+	DefaultSeverity = "high"
+)
+
+// Criterion は、リリース判定の評価項目の1つです。
+type Criterion struct {
+	Name        string  `yaml:"name"`        // 評価項目の識別子 (例: "tests")
+	Description string  `yaml:"description"` // AI に示す評価の観点
+	Weight      float64 `yaml:"weight"`      // 総合スコアへの重み (省略時は 1)
+}
+
+// Rubric は、リリース判定の評価項目と、Go / No-Go を判定するしきい値です。
+type Rubric struct {
+	Criteria  []Criterion `yaml:"criteria"`
+	Threshold float64     `yaml:"threshold"` // 総合スコア (0〜100) がこの値未満の場合は No-Go (省略時は DefaultThreshold)
+	Severity  string      `yaml:"severity"`  // No-Go の場合に追加する指摘事項の深刻度 (省略時は DefaultSeverity)
+}
+
+// Load は、YAML 形式の評価基準ファイルを読み込みます。
+//
+//	threshold: 70
+//	severity: high
+//	criteria:
+//	  - name: tests
+//	    description: 変更に対応するテストが追加・更新されている
+//	    weight: 3
+func Load(path string) (*Rubric, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("評価基準ファイル '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	var r Rubric
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("評価基準ファイル '%s' の解析に失敗しました: %w", path, err)
+	}
+	if err := r.normalize(); err != nil {
+		return nil, fmt.Errorf("評価基準ファイル '%s' が不正です: %w", path, err)
+	}
+	return &r, nil
+}
+
+// normalize は、省略された値に既定値を設定し、評価基準を検証します。
+func (r *Rubric) normalize() error {
+	if len(r.Criteria) == 0 {
+		return fmt.Errorf("評価項目 (criteria) が1つもありません")
+	}
+	seen := make(map[string]bool, len(r.Criteria))
+	for i := range r.Criteria {
+		c := &r.Criteria[i]
+		c.Name = strings.TrimSpace(c.Name)
+		if c.Name == "" {
+			return fmt.Errorf("%d 番目の評価項目の名前 (name) が空です", i+1)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("評価項目 '%s' が重複しています", c.Name)
+		}
+		seen[c.Name] = true
+		if c.Weight == 0 {
+			c.Weight = 1
+		}
+		if c.Weight < 0 {
+			return fmt.Errorf("評価項目 '%s' の重み (weight) が負の値です", c.Name)
+		}
+	}
+	if r.Threshold == 0 {
+		r.Threshold = DefaultThreshold
+	}
+	if r.Threshold < 0 || r.Threshold > 100 {
+		return fmt.Errorf("しきい値 (threshold) は 0〜100 で指定してください: %g", r.Threshold)
+	}
+	if r.Severity == "" {
+		r.Severity = DefaultSeverity
+	}
+	if _, err := findings.ParseSeverity(r.Severity); err != nil {
+		return err
+	}
+	return nil
+}
+
+// BlockStart は、AI に出力させる評価項目ごとのスコアのブロックの開始を示すマーカーです。
+const BlockStart = "<!-- gemini-review:rubric"
+
+// blockPattern は、評価項目ごとのスコアのブロック (JSON 配列) を抽出する正規表現です。
+var blockPattern = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(BlockStart) + `\s*(.*?)\s*-->`)
+
+// Score は、AI が付けた1つの評価項目のスコアです。
+type Score struct {
+	Name   string `json:"name"`
+	Score  int    `json:"score"`
+	Reason string `json:"reason"`
+}
+
+// Split は、レビュー結果からスコアのブロックを取り除いたレポート本文と、評価項目ごとのスコアを返します。
+// 分割レビューの結果を連結した場合など、ブロックが複数ある場合は評価項目ごとに最も低いスコアを採用します。
+// ブロックがない場合やすべての解析に失敗した場合、スコアは nil です。
+func Split(result string) (string, []Score) {
+	matches := blockPattern.FindAllStringSubmatch(result, -1)
+	if matches == nil {
+		return result, nil
+	}
+	report := strings.TrimSpace(blockPattern.ReplaceAllString(result, ""))
+
+	var scores []Score
+	index := make(map[string]int)
+	for _, m := range matches {
+		var block []Score
+		if err := json.Unmarshal([]byte(m[1]), &block); err != nil {
+			slog.Warn("評価項目のスコアのブロックの解析に失敗しました。", "error", err)
+			continue
+		}
+		for _, s := range block {
+			s.Name = strings.TrimSpace(s.Name)
+			if i, ok := index[s.Name]; ok {
+				if s.Score < scores[i].Score {
+					scores[i] = s
+				}
+				continue
+			}
+			index[s.Name] = len(scores)
+			scores = append(scores, s)
+		}
+	}
+	return report, scores
+}
+
+// ItemResult は、1つの評価項目の評価結果です。
+type ItemResult struct {
+	Criterion
+	Score   int
+	Reason  string
+	Missing bool // AI がスコアを付けなかった (0 点として扱う)
+}
+
+// Result は、評価項目ごとのスコアから求めた総合スコアと Go / No-Go の判定です。
+type Result struct {
+	Items     []ItemResult
+	Overall   float64 // 重み付きの総合スコア (0〜100)
+	Threshold float64
+	Go        bool
+}
+
+// Evaluate は、評価項目ごとのスコアから重み付きの総合スコアを求め、しきい値と比較して Go / No-Go を判定します。
+// スコアのない評価項目は、確認できなかったものとして 0 点とします。
+func (r *Rubric) Evaluate(scores []Score) Result {
+	byName := make(map[string]Score, len(scores))
+	for _, s := range scores {
+		byName[strings.TrimSpace(s.Name)] = s
+	}
+
+	res := Result{Threshold: r.Threshold}
+	var total, weights float64
+	for _, c := range r.Criteria {
+		item := ItemResult{Criterion: c}
+		if s, ok := byName[c.Name]; ok {
+			item.Score = min(max(s.Score, 0), MaxScore)
+			item.Reason = strings.TrimSpace(s.Reason)
+		} else {
+			item.Missing = true
+		}
+		total += c.Weight * float64(item.Score) / MaxScore
+		weights += c.Weight
+		res.Items = append(res.Items, item)
+	}
+	if weights > 0 {
+		res.Overall = 100 * total / weights
+	}
+	res.Go = res.Overall >= r.Threshold
+	return res
+}
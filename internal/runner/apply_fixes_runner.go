@@ -0,0 +1,82 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/patches"
+	"git-gemini-cli/internal/session"
+)
+
+// FixResult は、修正案の適用結果です。
+type FixResult struct {
+	Applied []patches.Suggestion
+	Failed  []patches.Suggestion // 先に適用した修正案と競合するなど、適用できなかった修正案
+	Commit  string               // 修正のコミットのハッシュ (ブランチを作成しない場合は空)
+}
+
+// ApplyFixesRunner は、保存されたレビュー結果の修正案をローカルのクローンに適用するインターフェースです。
+type ApplyFixesRunner interface {
+	Apply(ctx context.Context, cfg config.ReviewConfig, review session.Review, selected []patches.Suggestion, newBranch string) (FixResult, error)
+}
+
+// DefaultApplyFixesRunner は、ワーキングツリーにパッチを適用できる GitService で修正案を適用します。
+type DefaultApplyFixesRunner struct {
+	gitService internalAdapters.PatchApplier
+}
+
+// NewDefaultApplyFixesRunner は DefaultApplyFixesRunner の新しいインスタンスを生成します。
+func NewDefaultApplyFixesRunner(git internalAdapters.PatchApplier) *DefaultApplyFixesRunner {
+	return &DefaultApplyFixesRunner{gitService: git}
+}
+
+// Apply は、レビュー対象のブランチをチェックアウトし、選択された修正案を順に適用します。
+// newBranch が指定された場合は、そのブランチを作成して適用した修正案をコミットします。
+// 未指定の場合は、detached HEAD のワーキングツリーに変更を残します。
+// 適用中に並行するレビューがワーキングツリーをリセット・チェックアウトしないよう、cfg.LocalPath のロックを保持します。
+func (r *DefaultApplyFixesRunner) Apply(ctx context.Context, cfg config.ReviewConfig, review session.Review, selected []patches.Suggestion, newBranch string) (FixResult, error) {
+	var result FixResult
+	lock, err := acquireRepoLock(ctx, cfg)
+	if err != nil {
+		return result, err
+	}
+	defer releaseRepoLock(lock)
+
+	if err := r.gitService.PrepareWorkTree(ctx, review.HeadRef, newBranch); err != nil {
+		return result, err
+	}
+
+	for _, s := range selected {
+		if err := r.gitService.ApplyPatch(ctx, s.Patch); err != nil {
+			slog.Warn("修正案を適用できませんでした。", "title", s.Finding.Title, "error", err)
+			result.Failed = append(result.Failed, s)
+			continue
+		}
+		result.Applied = append(result.Applied, s)
+	}
+
+	if newBranch == "" || len(result.Applied) == 0 {
+		return result, nil
+	}
+	commit, err := r.gitService.CommitAll(ctx, fixCommitMessage(review, result.Applied))
+	if err != nil {
+		return result, err
+	}
+	result.Commit = commit
+	return result, nil
+}
+
+// fixCommitMessage は、適用した修正案の一覧を本文に含むコミットメッセージを生成します。
+func fixCommitMessage(review session.Review, applied []patches.Suggestion) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "レビューの修正案を適用 (%d 件)\n\n", len(applied))
+	fmt.Fprintf(&b, "%s...%s のレビュー (%s) の修正案を適用しました。\n\n", review.BaseRef, review.HeadRef, review.Mode)
+	for _, s := range applied {
+		fmt.Fprintf(&b, "- [%s] %s\n", s.Finding.Severity, s.Finding.Title)
+	}
+	return b.String()
+}
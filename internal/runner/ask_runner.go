@@ -0,0 +1,133 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/prompts"
+	"git-gemini-cli/internal/retrieval"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// ErrNoRelevantCode は、質問に関連するコードがリポジトリ内に見つからなかったことを示すエラーです。
+var ErrNoRelevantCode = errors.New("質問に関連するコードがリポジトリ内に見つかりませんでした")
+
+// ErrWorkingTreeRequired は、ワーキングツリーを持たないモード (--ephemeral) で ask が実行されたことを示すエラーです。
+var ErrWorkingTreeRequired = errors.New("ask コマンドはワーキングツリーを必要とするため、--ephemeral とは併用できません")
+
+// AskRunner は、リポジトリに対する質問応答を実行するインターフェースです。
+type AskRunner interface {
+	Run(ctx context.Context, cfg config.ReviewConfig, question string) (string, error)
+}
+
+// DefaultAskRunner は、クローン済みリポジトリからコンテキストを検索し、AIに回答を依頼します。
+type DefaultAskRunner struct {
+	gitService    adapters.GitService
+	geminiService adapters.CodeReviewAI
+	promptBuilder *prompts.Builder
+}
+
+// NewDefaultAskRunner は DefaultAskRunner の新しいインスタンスを生成します。
+func NewDefaultAskRunner(
+	git adapters.GitService,
+	gemini adapters.CodeReviewAI,
+	pb *prompts.Builder,
+) *DefaultAskRunner {
+	return &DefaultAskRunner{
+		gitService:    git,
+		geminiService: gemini,
+		promptBuilder: pb,
+	}
+}
+
+// Run はリポジトリを最新のベースブランチに揃えた上で関連コードを検索し、質問への回答を返します。
+func (r *DefaultAskRunner) Run(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+	question string,
+) (string, error) {
+
+	// 関連コードの検索にはワーキングツリーが必要
+	if cfg.Ephemeral || cfg.LocalPath == "" {
+		return "", ErrWorkingTreeRequired
+	}
+
+	lock, err := acquireRepoLock(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	defer releaseRepoLock(lock)
+
+	slog.Info("Gitリポジトリのセットアップを開始します。")
+	if err := r.gitService.CloneOrUpdate(ctx, cfg.RepoURL); err != nil {
+		return "", fmt.Errorf("リポジトリのセットアップに失敗しました: %w", err)
+	}
+
+	// 差分は不要なため、Cleanup でワーキングツリーをベースブランチの最新状態に揃える
+	if cfg.ReadOnly {
+		slog.Info("読み取り専用モードのため、現在のワーキングツリーの内容を対象に検索します。", "path", cfg.LocalPath)
+	}
+	if err := r.gitService.Cleanup(ctx); err != nil {
+		return "", fmt.Errorf("ベースブランチへの切り替えに失敗しました: %w", err)
+	}
+
+	// 関連コードの検索
+	snippets, err := retrieval.Retrieve(cfg.LocalPath, question, retrieval.Options{})
+	if err != nil {
+		return "", fmt.Errorf("関連コードの検索に失敗しました: %w", err)
+	}
+	if len(snippets) == 0 {
+		return "", ErrNoRelevantCode
+	}
+	slog.Info("関連コードを抽出しました。", "snippets", len(snippets), "keywords", retrieval.Keywords(question))
+
+	// ワーキングツリーのコードも差分と同じルールで、AI に送信する前に機密情報をマスクする
+	redactor, err := newRedactor(cfg)
+	if err != nil {
+		return "", err
+	}
+	ctx = withRedactor(ctx, redactor)
+	for i := range snippets {
+		snippets[i].Content = redactText(ctx, snippets[i].Path, snippets[i].Content)
+	}
+	if hasRedactions(ctx) {
+		slog.Warn("関連コードに含まれる機密情報をマスクしました。", "count", len(redactor.Redactions()))
+		logRedactionAudit(redactor)
+	}
+
+	finalPrompt, err := r.promptBuilder.BuildAsk(prompts.AskData{
+		Question: question,
+		Snippets: snippets,
+	})
+	if err != nil {
+		return "", fmt.Errorf("プロンプトの組み立てに失敗しました: %w", err)
+	}
+	glossary, err := loadGlossary(cfg)
+	if err != nil {
+		return "", err
+	}
+	finalPrompt, err = r.promptBuilder.AppendGlossary(finalPrompt, glossary)
+	if err != nil {
+		return "", err
+	}
+	persona, err := loadPersona(cfg)
+	if err != nil {
+		return "", err
+	}
+	finalPrompt, err = r.promptBuilder.PrependPersona(finalPrompt, persona)
+	if err != nil {
+		return "", err
+	}
+
+	slog.Info("AIに質問を送信します。", "backend", cfg.Backend, "model", cfg.Model)
+	answer, err := r.geminiService.ReviewCodeDiff(ctx, finalPrompt)
+	if err != nil {
+		return "", fmt.Errorf("AIによる回答の生成に失敗しました: %w", err)
+	}
+
+	return answer, nil
+}
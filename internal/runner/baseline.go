@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"git-gemini-cli/internal/baseline"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+)
+
+// loadBaseline は、チームが受け入れた指摘事項のベースラインを読み込みます。
+// cfg.BaselineFile が未指定の場合はリポジトリのルートの .gemini-review-baseline.json を使用しますが、
+// cfg.IgnoreBaseline が true の場合やインメモリモードでは読み込みません。ベースラインがない場合は nil を返します。
+func loadBaseline(cfg config.ReviewConfig) (*baseline.Baseline, error) {
+	if cfg.IgnoreBaseline {
+		return nil, nil
+	}
+	if cfg.BaselineFile != "" {
+		return baseline.Load(cfg.BaselineFile)
+	}
+	if cfg.LocalPath == "" {
+		return nil, nil
+	}
+	return baseline.LoadIfExists(filepath.Join(cfg.LocalPath, baseline.FileName))
+}
+
+// suppressBaseline は、ベースラインに一致する指摘事項を本文と構造化された指摘事項ブロックから取り除き、
+// 抑制した指摘事項の一覧をレポートに追加します。抑制した指摘事項は --fail-on の判定対象になりません。
+func suppressBaseline(result string, b *baseline.Baseline) string {
+	if b == nil {
+		return result
+	}
+	report, list := findings.Split(result)
+	var kept, suppressed []findings.Finding
+	for _, f := range list {
+		if b.Match(f) == nil {
+			kept = append(kept, f)
+			continue
+		}
+		suppressed = append(suppressed, f)
+		report, _ = findings.RemoveSection(report, f)
+	}
+	if len(suppressed) == 0 {
+		return result
+	}
+	slog.Info("ベースラインに一致する指摘事項を抑制しました。", "findings", len(list), "suppressed", len(suppressed))
+
+	var sb strings.Builder
+	sb.WriteString(strings.TrimRight(report, "\n"))
+	sb.WriteString("\n\n---\n\n## 🔕 抑制した指摘事項\n\n")
+	fmt.Fprintf(&sb, "ベースライン (`%s`) でチームが受け入れ済みの指摘事項 %d 件は、再度報告せずに抑制しました。\n\n", baseline.FileName, len(suppressed))
+	sb.WriteString("| 深刻度 | 指摘事項 | 場所 |\n| :--- | :--- | :--- |\n")
+	for _, f := range suppressed {
+		fmt.Fprintf(&sb, "| %s | %s | %s |\n", f.Severity, escapeTableCell(f.Title), findingLocation(f))
+	}
+	sb.WriteString("\n")
+	sb.WriteString(findings.Block(kept))
+	return sb.String()
+}
@@ -0,0 +1,152 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffcache"
+	"git-gemini-cli/internal/findings"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// BranchResult は、一括レビューで1つのブランチをレビューした結果です。
+type BranchResult struct {
+	Branch   string
+	Report   string // 構造化された指摘事項ブロックを取り除いたレポート本文
+	Findings []findings.Finding
+	Skipped  bool   // 差分が空のためレビューしなかった
+	Err      error  // レビューに失敗した場合のエラー
+	Link     string // 索引からブランチのレポートへのリンク (公開しない場合は空)
+	// SaveState は、--incremental でレビューしたコミットを記録する関数です (結果の出力・公開に成功した後に呼び出す。記録しない場合は nil)。
+	SaveState func(ctx context.Context)
+}
+
+// ListRemoteBranches は、リポジトリをクローン (または更新) してフェッチし、リモートのブランチ一覧を返します。
+// フェッチ結果は cache に記録されるため、続けて同じプロセス内で行うレビューではフェッチを省略します。
+func ListRemoteBranches(ctx context.Context, git adapters.GitService, cache *diffcache.Cache, cfg config.ReviewConfig) ([]string, error) {
+	lister, ok := git.(internalAdapters.BranchLister)
+	if !ok {
+		return nil, internalAdapters.ErrBranchListUnsupported
+	}
+
+	lock, err := acquireRepoLock(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseRepoLock(lock)
+
+	if err := git.CloneOrUpdate(ctx, cfg.RepoURL); err != nil {
+		return nil, fmt.Errorf("リポジトリのセットアップに失敗しました: %w", err)
+	}
+	if err := fetchRepo(ctx, git, cache, cfg); err != nil {
+		return nil, err
+	}
+	return lister.ListRemoteBranches(ctx)
+}
+
+// ListRemoteBranchHeads は、リポジトリをクローン (または更新) して常にフェッチし、リモートのブランチと最新のコミットハッシュの対応を返します。
+// 新しいコミットの有無を判定するため、同じプロセス内でフェッチ済みの場合もフェッチを省略しません。
+func ListRemoteBranchHeads(ctx context.Context, git adapters.GitService, cache *diffcache.Cache, cfg config.ReviewConfig) (map[string]string, error) {
+	lister, ok := git.(internalAdapters.BranchLister)
+	if !ok {
+		return nil, internalAdapters.ErrBranchListUnsupported
+	}
+	resolver, ok := git.(internalAdapters.RefResolver)
+	if !ok {
+		return nil, internalAdapters.ErrRefResolveUnsupported
+	}
+
+	lock, err := acquireRepoLock(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseRepoLock(lock)
+
+	if err := git.CloneOrUpdate(ctx, cfg.RepoURL); err != nil {
+		return nil, fmt.Errorf("リポジトリのセットアップに失敗しました: %w", err)
+	}
+	if err := git.Fetch(ctx); err != nil {
+		return nil, fmt.Errorf("最新の変更のフェッチに失敗しました: %w", err)
+	}
+	localPath := cfg.LocalPath
+	if cfg.Ephemeral {
+		localPath = ""
+	}
+	cache.MarkFetched(cfg.RepoURL, localPath)
+
+	branches, err := lister.ListRemoteBranches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	heads := make(map[string]string, len(branches))
+	for _, branch := range branches {
+		hash, err := resolver.ResolveRef(ctx, branch)
+		if err != nil {
+			return nil, err
+		}
+		heads[branch] = hash
+	}
+	return heads, nil
+}
+
+// BatchIndex は、一括レビューした各ブランチの判定・指摘事項の件数とレポートへのリンクを並べた索引を生成します。
+// 公開しない場合 (Link が空) は、索引の後に各ブランチのレポート本文を続けます。
+func BatchIndex(cfg config.ReviewConfig, results []BranchResult) string {
+	linked := false
+	for _, r := range results {
+		linked = linked || r.Link != ""
+	}
+
+	var b strings.Builder
+	b.WriteString("# ブランチ一括レビュー\n\n")
+	fmt.Fprintf(&b, "ベースブランチ `%s` に対して %d 個のブランチをレビューしました。\n\n", cfg.BaseBranch, len(results))
+	b.WriteString("| ブランチ | 判定 | 指摘事項 |")
+	if linked {
+		b.WriteString(" レポート |")
+	}
+	b.WriteString("\n| :--- | :--- | :--- |")
+	if linked {
+		b.WriteString(" :--- |")
+	}
+	b.WriteString("\n")
+	for _, r := range results {
+		verdict, counts := branchStatus(r)
+		fmt.Fprintf(&b, "| `%s` | %s | %s |", r.Branch, escapeTableCell(verdict), escapeTableCell(counts))
+		if linked {
+			link := "—"
+			if r.Link != "" {
+				link = fmt.Sprintf("[開く](%s)", r.Link)
+			}
+			fmt.Fprintf(&b, " %s |", link)
+		}
+		b.WriteString("\n")
+	}
+
+	if linked {
+		return b.String()
+	}
+	for _, r := range results {
+		if r.Err != nil || r.Skipped {
+			continue
+		}
+		fmt.Fprintf(&b, "\n---\n\n# `%s` のレビュー結果\n\n%s\n", r.Branch, strings.TrimSpace(r.Report))
+	}
+	return b.String()
+}
+
+// branchStatus は、索引に表示するブランチの判定と指摘事項の件数を返します。
+func branchStatus(r BranchResult) (string, string) {
+	switch {
+	case r.Err != nil:
+		return "⚠️ レビュー失敗", r.Err.Error()
+	case r.Skipped:
+		return "➖ 差分なし", "—"
+	}
+	s := findings.Summarize(r.Findings)
+	return s.Verdict, s.CountsText()
+}
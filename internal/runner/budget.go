@@ -0,0 +1,364 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/prompts"
+)
+
+// ErrTokenBudgetExceeded は、プロンプトのトークン数が --max-prompt-tokens を超えたことを示すエラーです。
+var ErrTokenBudgetExceeded = errors.New("プロンプトのトークン数が上限を超えています")
+
+// chunkFillRatio は、分割時に1チャンクへ詰め込む量の上限 (予算に対する割合) です。
+// トークン数はバイト数からの推定で割り当てるため、推定誤差を吸収する余裕を持たせます。
+const chunkFillRatio = 0.9
+
+// modePlan は、1つのモードでAIに送信するプロンプトと、差分を分割してレビューするかどうかの判断です。
+type modePlan struct {
+	prompt       string      // 差分を分割しない場合のプロンプト
+	chunks       []diffChunk // 差分を分割する場合の各パート (分割しない場合は nil)
+	chunkPrompts []string    // 各パートのプロンプト
+}
+
+// reviewMode は、cfg.ReviewMode (単一モード) のプロンプトを生成してAIにレビューを依頼します。
+// 差分を分割する場合は、各パートのレビュー結果を1つのレポートに統合します。
+func (r *DefaultReviewRunner) reviewMode(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+	codeDiff string,
+	commitLog func() []internalAdapters.Commit,
+) (string, error) {
+	plan, err := r.planMode(ctx, cfg, codeDiff, commitLog)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case plan.chunks == nil:
+		return r.review(ctx, cfg, plan.prompt)
+	case cfg.ParallelFiles > 1:
+		return r.reviewChunksParallel(ctx, cfg, plan.chunks, plan.chunkPrompts)
+	default:
+		return r.reviewChunks(ctx, cfg, plan.chunks, plan.chunkPrompts)
+	}
+}
+
+// planMode は、cfg.ReviewMode (単一モード) でAIに送信するプロンプトを生成し、差分を分割するかどうかを判断します。
+// cfg.ParallelFiles が 2 以上で差分に複数のファイルが含まれる場合は、ファイルごとに分割します。
+// トークン予算が設定されている場合は送信前にトークン数を数え、超過時は設定に応じて中止するか、差分をファイル単位に分割します。
+// 予算の超過により中止する場合も、確認用に分割しない場合のプロンプトを返します。
+func (r *DefaultReviewRunner) planMode(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+	codeDiff string,
+	commitLog func() []internalAdapters.Commit,
+) (modePlan, error) {
+	if cfg.ParallelFiles > 1 {
+		if chunks := splitDiffByFile(codeDiff); len(chunks) > 1 {
+			slog.Info("差分をファイルごとに並行してレビューします。", "mode", cfg.ReviewMode, "files", len(chunks), "parallel", cfg.ParallelFiles)
+			return r.planChunks(ctx, cfg, chunks, commitLog)
+		}
+	}
+
+	finalPrompt, err := r.buildPrompt(ctx, cfg, codeDiff, commitLog)
+	if err != nil {
+		return modePlan{}, err
+	}
+	plan := modePlan{prompt: finalPrompt}
+	if cfg.MaxPromptTokens <= 0 {
+		return plan, nil
+	}
+
+	tokens := r.countTokens(ctx, finalPrompt)
+	slog.Info("プロンプトのトークン数を確認しました。", "mode", cfg.ReviewMode, "tokens", tokens, "budget", cfg.MaxPromptTokens)
+	if tokens <= cfg.MaxPromptTokens {
+		return plan, nil
+	}
+
+	if cfg.OnBudgetExceeded != config.BudgetChunk {
+		return plan, fmt.Errorf("%w (mode: %s, tokens: %d, budget: %d)。--on-budget-exceeded=chunk を指定すると差分を分割してレビューします",
+			ErrTokenBudgetExceeded, cfg.ReviewMode, tokens, cfg.MaxPromptTokens)
+	}
+
+	chunks, err := splitDiffByBudget(codeDiff, len(finalPrompt), tokens, cfg.MaxPromptTokens)
+	if err != nil {
+		return plan, err
+	}
+	slog.Warn("プロンプトがトークン数の上限を超えるため、差分を分割してレビューします。", "mode", cfg.ReviewMode, "tokens", tokens, "budget", cfg.MaxPromptTokens, "chunks", len(chunks))
+	return r.planChunks(ctx, cfg, chunks, commitLog)
+}
+
+// planChunks は、分割した差分ごとにプロンプトを生成します。
+// トークン予算が設定されている場合は、いずれかのパートが予算を超えた時点で中止します。
+func (r *DefaultReviewRunner) planChunks(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+	chunks []diffChunk,
+	commitLog func() []internalAdapters.Commit,
+) (modePlan, error) {
+	chunkPrompts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		chunkPrompt, err := r.buildPrompt(ctx, cfg, chunk.diff, commitLog)
+		if err != nil {
+			return modePlan{}, err
+		}
+		if cfg.MaxPromptTokens > 0 {
+			if chunkTokens := r.countTokens(ctx, chunkPrompt); chunkTokens > cfg.MaxPromptTokens {
+				return modePlan{}, fmt.Errorf("%w (mode: %s, 分割 %d/%d, files: %s, tokens: %d, budget: %d)",
+					ErrTokenBudgetExceeded, cfg.ReviewMode, i+1, len(chunks), strings.Join(chunk.files, ", "), chunkTokens, cfg.MaxPromptTokens)
+			}
+		}
+		chunkPrompts[i] = chunkPrompt
+	}
+	return modePlan{chunks: chunks, chunkPrompts: chunkPrompts}, nil
+}
+
+// reviewChunks は、分割した差分のパートを順にレビューし、結果を1つのレポートに統合します。
+func (r *DefaultReviewRunner) reviewChunks(ctx context.Context, cfg config.ReviewConfig, chunks []diffChunk, chunkPrompts []string) (string, error) {
+	results := make([]string, 0, len(chunks))
+	for i, chunk := range chunks {
+		slog.Info("分割した差分のレビューを依頼します。", "part", i+1, "of", len(chunks), "files", len(chunk.files))
+		result, err := r.review(ctx, cfg, chunkPrompts[i])
+		if err != nil {
+			return "", err
+		}
+		results = append(results, result)
+
+		if i == len(chunks)-1 {
+			break
+		}
+		// 中断シグナルを受信していれば、完了したパートの結果のみを統合して終了する
+		if interrupt.Requested(ctx) {
+			return pendingFilesNote(r.reduceChunks(ctx, cfg, chunks[:i+1], results), chunks[i+1:]), interrupt.ErrInterrupted
+		}
+		// 最後のパートは統合後に最終版として公開されるため、それ以前のパートのみ暫定版として通知する
+		if h := partialResultHandler(ctx); h != nil {
+			h(ctx, mergeChunks(chunks[:i+1], results))
+		}
+	}
+
+	return r.reduceChunks(ctx, cfg, chunks, results), nil
+}
+
+// reviewChunksParallel は、cfg.ParallelFiles 個までのパートを並行してレビューし、結果を1つのレポートに統合します。
+// いずれかのパートが失敗した場合は、実行中の他のパートを取り消してエラーを返します。
+// 中断シグナルを受信した場合は、新しいパートを開始せず、実行中のパートの完了を待って完了したパートの結果のみを統合します。
+func (r *DefaultReviewRunner) reviewChunksParallel(ctx context.Context, cfg config.ReviewConfig, chunks []diffChunk, chunkPrompts []string) (string, error) {
+	workCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	results := make([]string, len(chunks))
+	reviewed := make([]bool, len(chunks))
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, min(cfg.ParallelFiles, len(chunks)))
+	for i := range chunks {
+		// 最初のパートは必ず開始し、統合するレポートが空にならないようにする
+		if i > 0 && interrupt.Requested(ctx) {
+			break
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-workCtx.Done():
+		}
+		if workCtx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			slog.Info("分割した差分のレビューを依頼します。", "part", i+1, "of", len(chunks), "files", len(chunks[i].files))
+			result, err := r.review(workCtx, cfg, chunkPrompts[i])
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel(err)
+				}
+				return
+			}
+			results[i], reviewed[i] = result, true
+			// 暫定版の通知は完了順に行うため、ロックを保持したまま呼び出す
+			if done, doneResults, pending := partitionChunks(chunks, results, reviewed); len(pending) > 0 {
+				if h := partialResultHandler(ctx); h != nil {
+					h(ctx, mergeChunks(done, doneResults))
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return "", firstErr
+	}
+	done, doneResults, pending := partitionChunks(chunks, results, reviewed)
+	if len(pending) > 0 {
+		return pendingFilesNote(r.reduceChunks(ctx, cfg, done, doneResults), pending), interrupt.ErrInterrupted
+	}
+	return r.reduceChunks(ctx, cfg, chunks, results), nil
+}
+
+// partitionChunks は、パートをレビューが完了したもの (とその結果) と未完了のものに分けます。いずれも元の順序を維持します。
+func partitionChunks(chunks []diffChunk, results []string, reviewed []bool) ([]diffChunk, []string, []diffChunk) {
+	var done, pending []diffChunk
+	var doneResults []string
+	for i, chunk := range chunks {
+		if reviewed[i] {
+			done = append(done, chunk)
+			doneResults = append(doneResults, results[i])
+		} else {
+			pending = append(pending, chunk)
+		}
+	}
+	return done, doneResults, pending
+}
+
+// reduceChunks は、分割してレビューした結果を、AIに重複を除いて1つの一貫したレポートへ統合させます (map-reduce の reduce)。
+// 統合用のプロンプトが予算を超える場合や、統合に失敗した場合は、各パートの結果を連結したレポートを返します。
+func (r *DefaultReviewRunner) reduceChunks(ctx context.Context, cfg config.ReviewConfig, chunks []diffChunk, results []string) string {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	parts := make([]prompts.ReducePart, len(results))
+	for i, result := range results {
+		parts[i] = prompts.ReducePart{Files: chunks[i].files, Result: strings.TrimSpace(result)}
+	}
+
+	reducePrompt, err := r.promptBuilder.BuildReduce(prompts.ReduceData{Mode: cfg.ReviewMode, Parts: parts})
+	if err == nil && cfg.ReviewMode == prompts.ModeRelease {
+		reducePrompt, err = r.promptBuilder.AppendRubric(reducePrompt, rubricFrom(ctx))
+	}
+	if err == nil && cfg.NeedsFindings() {
+		reducePrompt, err = prompts.AppendFindingsInstruction(reducePrompt)
+	}
+	if err != nil {
+		slog.Warn("統合プロンプトの生成に失敗したため、各パートの結果を連結します。", "error", err)
+		return mergeChunks(chunks, results)
+	}
+
+	if tokens := r.countTokens(ctx, reducePrompt); cfg.MaxPromptTokens > 0 && tokens > cfg.MaxPromptTokens {
+		slog.Warn("統合プロンプトがトークン数の上限を超えるため、各パートの結果を連結します。", "tokens", tokens, "budget", cfg.MaxPromptTokens)
+		return mergeChunks(chunks, results)
+	}
+
+	slog.Info("分割したレビュー結果を統合します。", "mode", cfg.ReviewMode, "parts", len(results))
+	reduced, err := r.review(ctx, cfg, reducePrompt)
+	if err != nil {
+		slog.Warn("レビュー結果の統合に失敗したため、各パートの結果を連結します。", "error", err)
+		return mergeChunks(chunks, results)
+	}
+	return reduced
+}
+
+// review は、生成済みのプロンプトでAIにレビューを依頼します。
+func (r *DefaultReviewRunner) review(ctx context.Context, cfg config.ReviewConfig, prompt string) (string, error) {
+	slog.Info("AIによるコードレビューを開始します。", "backend", cfg.Backend, "model", cfg.Model, "mode", cfg.ReviewMode)
+
+	reviewResult, err := r.geminiService.ReviewCodeDiff(internalAdapters.WithReviewMode(ctx, cfg.ReviewMode), prompt)
+	if err != nil {
+		return "", fmt.Errorf("AIレビューの実行に失敗しました (mode: %s): %w", cfg.ReviewMode, err)
+	}
+	return reviewResult, nil
+}
+
+// countTokens は、countTokens API でプロンプトのトークン数を数えます。
+// API を利用できない場合は、バイト数からの概算値を使用します。
+func (r *DefaultReviewRunner) countTokens(ctx context.Context, prompt string) int {
+	if r.tokenCounter != nil {
+		tokens, err := r.tokenCounter.CountTokens(ctx, prompt)
+		if err == nil {
+			return tokens
+		}
+		slog.Warn("トークン数の取得に失敗したため、概算値を使用します。", "error", err)
+	}
+	return internalAdapters.EstimateTokens(prompt)
+}
+
+// diffChunk は、トークン予算に収まるよう分割した差分の1単位です。
+type diffChunk struct {
+	files []string
+	diff  string
+}
+
+// splitDiffByBudget は、差分をファイル単位で、各チャンクのプロンプトが予算に収まる見込みの大きさにまとめます。
+// プロンプト全体のトークン数とバイト数の比率から、テンプレート部分を除いた差分に割り当てられるバイト数を推定します。
+func splitDiffByBudget(codeDiff string, promptBytes, promptTokens, budget int) ([]diffChunk, error) {
+	bytesPerToken := float64(promptBytes) / float64(promptTokens)
+	overhead := promptBytes - len(codeDiff)
+	capacity := int(float64(budget)*chunkFillRatio*bytesPerToken) - overhead
+	if capacity <= 0 {
+		return nil, fmt.Errorf("%w: 差分を除いたプロンプトだけで上限に達するため、分割できません (budget: %d)", ErrTokenBudgetExceeded, budget)
+	}
+
+	var chunks []diffChunk
+	var cur diffChunk
+	size := 0
+	for _, f := range diffutil.ParseFiles(codeDiff) {
+		if size > 0 && size+len(f.Raw) > capacity {
+			chunks = append(chunks, cur)
+			cur, size = diffChunk{}, 0
+		}
+		if cur.diff != "" {
+			cur.diff += "\n"
+		}
+		cur.diff += f.Raw
+		cur.files = append(cur.files, f.Path)
+		size += len(f.Raw)
+	}
+	if size > 0 {
+		chunks = append(chunks, cur)
+	}
+	return chunks, nil
+}
+
+// splitDiffByFile は、差分をファイルごとのパートに分割します。
+func splitDiffByFile(codeDiff string) []diffChunk {
+	files := diffutil.ParseFiles(codeDiff)
+	chunks := make([]diffChunk, 0, len(files))
+	for _, f := range files {
+		chunks = append(chunks, diffChunk{files: []string{f.Path}, diff: f.Raw})
+	}
+	return chunks
+}
+
+// mergeChunks は、分割してレビューした結果を、対象ファイルを示した1つのレポートに連結します。
+func mergeChunks(chunks []diffChunk, results []string) string {
+	if len(results) == 1 {
+		return results[0]
+	}
+
+	var b strings.Builder
+	for i, result := range results {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "## パート %d/%d\n\n対象ファイル: `%s`\n\n%s", i+1, len(results), strings.Join(chunks[i].files, "`, `"), strings.TrimSpace(result))
+	}
+	return b.String()
+}
+
+// pendingFilesNote は、中断によりレビューしなかったファイルの一覧をレポートの末尾に追記します。
+func pendingFilesNote(report string, pending []diffChunk) string {
+	var files []string
+	for _, chunk := range pending {
+		files = append(files, chunk.files...)
+	}
+	return fmt.Sprintf("%s\n\n**未レビューのファイル:** `%s`", strings.TrimSpace(report), strings.Join(files, "`, `"))
+}
@@ -0,0 +1,127 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/prompts"
+	"git-gemini-cli/internal/session"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+const (
+	// maxChatHistory は、プロンプトに含めるこれまでの会話の往復数の上限です。古いものから省略します。
+	maxChatHistory = 10
+	// maxChatDiffBytes は、プロンプトに含める差分のサイズの上限 (バイト) です。
+	maxChatDiffBytes = 200_000
+)
+
+// Conversation は、chat コマンドでの1つのレビュー結果についての会話です。
+type Conversation struct {
+	Review session.Review
+	Turns  []prompts.ChatTurn
+}
+
+// ChatRunner は、レビュー結果についての追加の質問に回答するインターフェースです。
+type ChatRunner interface {
+	Reply(ctx context.Context, cfg config.ReviewConfig, conv *Conversation, question string) (string, error)
+}
+
+// DefaultChatRunner は、レビュー結果と差分、これまでの会話を前提として、AIに回答を依頼します。
+type DefaultChatRunner struct {
+	geminiService adapters.CodeReviewAI
+	promptBuilder *prompts.Builder
+}
+
+// NewDefaultChatRunner は DefaultChatRunner の新しいインスタンスを生成します。
+func NewDefaultChatRunner(gemini adapters.CodeReviewAI, pb *prompts.Builder) *DefaultChatRunner {
+	return &DefaultChatRunner{
+		geminiService: gemini,
+		promptBuilder: pb,
+	}
+}
+
+// Reply は、質問への回答を返し、会話の履歴に追加します。
+// チームの用語集とレビュアーのペルソナがある場合は、レビュー時と同様にプロンプトに追加します。
+func (r *DefaultChatRunner) Reply(ctx context.Context, cfg config.ReviewConfig, conv *Conversation, question string) (string, error) {
+	report, list := findings.Split(conv.Review.Report)
+	diff, truncated := conv.Review.Diff, false
+	if len(diff) > maxChatDiffBytes {
+		diff, truncated = strings.ToValidUTF8(diff[:maxChatDiffBytes], ""), true
+	}
+	history := conv.Turns
+	if len(history) > maxChatHistory {
+		history = history[len(history)-maxChatHistory:]
+	}
+
+	prompt, err := r.promptBuilder.BuildChat(prompts.ChatData{
+		RepoURL:       conv.Review.RepoURL,
+		BaseRef:       conv.Review.BaseRef,
+		HeadRef:       conv.Review.HeadRef,
+		Mode:          conv.Review.Mode,
+		Report:        report,
+		Findings:      list,
+		Diff:          diff,
+		DiffTruncated: truncated,
+		History:       history,
+		Question:      question,
+	})
+	if err != nil {
+		return "", fmt.Errorf("プロンプトの組み立てに失敗しました: %w", err)
+	}
+	glossary, err := loadGlossary(cfg)
+	if err != nil {
+		return "", err
+	}
+	prompt, err = r.promptBuilder.AppendGlossary(prompt, glossary)
+	if err != nil {
+		return "", err
+	}
+	persona, err := loadPersona(cfg)
+	if err != nil {
+		return "", err
+	}
+	prompt, err = r.promptBuilder.PrependPersona(prompt, persona)
+	if err != nil {
+		return "", err
+	}
+
+	slog.Debug("AIに質問を送信します。", "backend", cfg.Backend, "model", cfg.Model, "turn", len(conv.Turns)+1)
+	answer, err := r.geminiService.ReviewCodeDiff(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("AIによる回答の生成に失敗しました: %w", err)
+	}
+	answer = strings.TrimSpace(answer)
+	conv.Turns = append(conv.Turns, prompts.ChatTurn{Question: question, Answer: answer})
+	return answer, nil
+}
+
+// saveSession は、chat コマンドで使用できるよう、レビュー結果と AI に送信した差分を保存します。
+// 保存に失敗してもレビュー結果は有効なため、警告にとどめます。
+func saveSession(cfg config.ReviewConfig, baseRef, headRef, codeDiff, report string) {
+	path, err := session.ResolvePath(cfg.SessionFile)
+	if err != nil {
+		slog.Warn("レビュー結果の保存先を決定できないため、chat コマンド用の保存をスキップします。", "error", err)
+		return
+	}
+	err = session.Save(path, session.Review{
+		RepoURL:    cfg.RepoURL,
+		BaseRef:    baseRef,
+		HeadRef:    headRef,
+		Mode:       strings.Join(cfg.Modes(), ","),
+		Report:     report,
+		Diff:       codeDiff,
+		ReviewedAt: time.Now(),
+	})
+	if err != nil {
+		slog.Warn("chat コマンド用のレビュー結果の保存に失敗しました。", "path", path, "error", err)
+		return
+	}
+	slog.Debug("chat コマンド用にレビュー結果を保存しました。", "path", path)
+}
@@ -0,0 +1,114 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+)
+
+// ModelResult は、モデルの比較で1つのモデルが出力したレビュー結果です。
+type ModelResult struct {
+	Model    string
+	Report   string // 構造化された指摘事項ブロックを取り除いたレポート本文
+	Findings []findings.Finding
+	Elapsed  time.Duration
+}
+
+// ComparisonReport は、各モデルのレビュー結果の概要・指摘事項の対応・レポート本文を並べた比較レポートを生成します。
+func ComparisonReport(cfg config.ReviewConfig, results []ModelResult) string {
+	var b strings.Builder
+	b.WriteString("# モデル比較レポート\n\n")
+	fmt.Fprintf(&b, "同じ差分 (`%s`) を `%s` バックエンドの %d 個のモデルでレビューした結果を比較します。\n\n", cfg.FeatureBranch, cfg.Backend, len(results))
+
+	b.WriteString("## 📊 概要\n\n| 項目 |")
+	for _, r := range results {
+		fmt.Fprintf(&b, " `%s` |", r.Model)
+	}
+	b.WriteString("\n| :--- |" + strings.Repeat(" :--- |", len(results)) + "\n")
+	writeComparisonRow(&b, "判定", results, func(r ModelResult) string { return findings.Summarize(r.Findings).Verdict })
+	writeComparisonRow(&b, "指摘事項", results, func(r ModelResult) string { return findings.Summarize(r.Findings).CountsText() })
+	writeComparisonRow(&b, "他のモデルにない指摘事項", results, func(r ModelResult) string {
+		return fmt.Sprintf("%d 件", len(uniqueFindings(r, results)))
+	})
+	writeComparisonRow(&b, "所要時間", results, func(r ModelResult) string { return r.Elapsed.Round(100 * time.Millisecond).String() })
+	writeComparisonRow(&b, "レポートの長さ", results, func(r ModelResult) string { return fmt.Sprintf("%d 文字", len([]rune(r.Report))) })
+
+	b.WriteString("\n## 🔀 指摘事項の対応\n\n")
+	b.WriteString("同じファイルでタイトルが類似する指摘事項を、同じ問題への指摘とみなしています。\n\n| 指摘事項 | 場所 |")
+	for _, r := range results {
+		fmt.Fprintf(&b, " `%s` |", r.Model)
+	}
+	b.WriteString("\n| :--- | :--- |" + strings.Repeat(" :---: |", len(results)) + "\n")
+	for _, f := range allFindings(results) {
+		fmt.Fprintf(&b, "| %s | %s |", escapeTableCell(f.Title), findingLocation(f))
+		for _, r := range results {
+			cell := "—"
+			for _, g := range r.Findings {
+				if findings.SameIssue(f, g) {
+					cell = g.Severity.String()
+					break
+				}
+			}
+			fmt.Fprintf(&b, " %s |", cell)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, r := range results {
+		fmt.Fprintf(&b, "\n---\n\n# `%s` のレビュー結果\n\n%s\n", r.Model, strings.TrimSpace(r.Report))
+	}
+	return b.String()
+}
+
+// writeComparisonRow は、概要の表にモデルごとの値を並べた1行を書き出します。
+func writeComparisonRow(b *strings.Builder, label string, results []ModelResult, value func(ModelResult) string) {
+	fmt.Fprintf(b, "| %s |", label)
+	for _, r := range results {
+		fmt.Fprintf(b, " %s |", escapeTableCell(value(r)))
+	}
+	b.WriteString("\n")
+}
+
+// allFindings は、すべてのモデルの指摘事項から、同じ問題への指摘を1件にまとめた一覧を返します。
+func allFindings(results []ModelResult) []findings.Finding {
+	var all []findings.Finding
+	for _, r := range results {
+		for _, f := range r.Findings {
+			if !containsIssue(all, f) {
+				all = append(all, f)
+			}
+		}
+	}
+	return all
+}
+
+// uniqueFindings は、r の指摘事項のうち、他のモデルが指摘しなかったものを返します。
+func uniqueFindings(r ModelResult, results []ModelResult) []findings.Finding {
+	var unique []findings.Finding
+	for _, f := range r.Findings {
+		found := false
+		for _, other := range results {
+			if other.Model != r.Model && containsIssue(other.Findings, f) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unique = append(unique, f)
+		}
+	}
+	return unique
+}
+
+// containsIssue は、list に f と同じ問題への指摘事項が含まれるかを返します。
+func containsIssue(list []findings.Finding, f findings.Finding) bool {
+	for _, g := range list {
+		if findings.SameIssue(f, g) {
+			return true
+		}
+	}
+	return false
+}
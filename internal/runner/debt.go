@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/debt"
+	"git-gemini-cli/internal/findings"
+)
+
+// maxReportDebtMarkers は、レポートに列挙する追加されたマーカーの上限です。
+const maxReportDebtMarkers = 50
+
+// debtLabels は、マーカーの種類ごとのレポート上の表記です。
+var debtLabels = map[debt.Kind]string{
+	debt.KindTODO:  "TODO",
+	debt.KindFIXME: "FIXME",
+	debt.KindSkip:  "テストのスキップ",
+}
+
+// scanDebt は、cfg.DebtScan が有効な場合に、差分で追加された TODO / FIXME とテストのスキップを検出します。
+func scanDebt(cfg config.ReviewConfig, codeDiff string) *debt.Result {
+	if !cfg.DebtScan {
+		return nil
+	}
+	result := debt.Scan(codeDiff)
+	slog.Info("差分の TODO / FIXME とテストのスキップを検出しました。",
+		"todo", result.Count(debt.KindTODO), "fixme", result.Count(debt.KindFIXME), "skip", result.Count(debt.KindSkip))
+	return &result
+}
+
+// appendDebtReport は、レポートの末尾に追加された技術的負債の件数と一覧のセクションを追加します。
+// 追加されたマーカーは、深刻度 LOW の指摘事項としても追加します。
+func appendDebtReport(report string, result *debt.Result) string {
+	if result == nil || result.Empty() {
+		return report
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	b.WriteString("\n\n---\n\n## 技術的負債の追加 (TODO / FIXME / テストのスキップ)\n\n")
+	b.WriteString("| 種類 | 追加 | 削除 |\n| :--- | ---: | ---: |\n")
+	for _, kind := range debt.Kinds {
+		fmt.Fprintf(&b, "| %s | %d | %d |\n", debtLabels[kind], result.Count(kind), result.Removed[kind])
+	}
+	if len(result.Added) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n")
+	shown, omitted := result.Added, 0
+	if len(shown) > maxReportDebtMarkers {
+		shown, omitted = shown[:maxReportDebtMarkers], len(shown)-maxReportDebtMarkers
+	}
+	for _, m := range shown {
+		fmt.Fprintf(&b, "- **%s** `%s:%d`: `%s`\n", debtLabels[m.Kind], m.Path, m.Line, strings.ReplaceAll(m.Text, "`", "'"))
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&b, "- ほか %d 件\n", omitted)
+	}
+
+	list := make([]findings.Finding, len(result.Added))
+	for i, m := range result.Added {
+		list[i] = findings.Finding{
+			Severity: findings.SeverityLow,
+			Title:    fmt.Sprintf("%s が追加されました", debtLabels[m.Kind]),
+			File:     m.Path,
+			Line:     m.Line,
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(findings.Block(list))
+	b.WriteString("\n")
+	return b.String()
+}
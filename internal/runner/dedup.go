@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"git-gemini-cli/internal/findings"
+)
+
+// dedupFindings は、分割レビューや複数モードのレビューで重複した指摘事項を統合し、
+// 後に現れた指摘事項のセクションを本文から取り除きます。重複がない場合は、レビュー結果をそのまま返します。
+func dedupFindings(result string) string {
+	report, list := findings.Split(result)
+	kept, dups := findings.Dedup(list)
+	if len(dups) == 0 {
+		return result
+	}
+	slog.Info("重複した指摘事項を統合しました。", "findings", len(list), "duplicates", len(dups))
+
+	for _, d := range dups {
+		out, ok := findings.RemoveLastSection(report, d.Removed)
+		// 統合先と同じ見出しのセクションが1つしかない場合は、統合先の説明として残す
+		if ok && (!sameHeading(d.Removed, d.Kept) || findings.Section(out, d.Kept) != "") {
+			report = out
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	b.WriteString("\n\n---\n\n## 🧹 統合した指摘事項\n\n")
+	fmt.Fprintf(&b, "同じ箇所への類似した指摘 %d 件を、先に現れた指摘事項に統合しました。\n\n", len(dups))
+	b.WriteString("| 統合した指摘事項 | 統合先 | 場所 |\n| :--- | :--- | :--- |\n")
+	for _, d := range dups {
+		fmt.Fprintf(&b, "| [%s] %s | [%s] %s | %s |\n", d.Removed.Severity, escapeTableCell(d.Removed.Title), d.Kept.Severity, escapeTableCell(d.Kept.Title), findingLocation(d.Removed))
+	}
+	b.WriteString("\n")
+	b.WriteString(findings.Block(kept))
+	return b.String()
+}
+
+// sameHeading は、2つの指摘事項の本文の見出し ("### [HIGH] タイトル") が同じかを返します。
+func sameHeading(a, b findings.Finding) bool {
+	return a.Severity == b.Severity && strings.TrimSpace(a.Title) == strings.TrimSpace(b.Title)
+}
@@ -0,0 +1,83 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffcache"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// fetchDiff は、リモートから最新の変更をフェッチし (fetchRepo)、cfg.DiffRefs() の差分を取得します (loadDiff)。
+func fetchDiff(ctx context.Context, git adapters.GitService, cache *diffcache.Cache, cfg config.ReviewConfig) (string, error) {
+	if err := fetchRepo(ctx, git, cache, cfg); err != nil {
+		return "", err
+	}
+	baseRef, headRef := cfg.DiffRefs()
+	return loadDiff(ctx, git, cache, cfg.RepoURL, baseRef, headRef)
+}
+
+// fetchRepo は、リモートから最新の変更をフェッチします。
+// 同じプロセス内で同じローカルパスを直前にフェッチ済みの場合は、フェッチを省略します。
+// --incremental では新しいコミットの有無を判定するため、コミットを固定したレビュー (cfg.HeadCommit) ではそのコミットを取得するため、常にフェッチします。
+func fetchRepo(ctx context.Context, git adapters.GitService, cache *diffcache.Cache, cfg config.ReviewConfig) error {
+	localPath := cfg.LocalPath
+	if cfg.Ephemeral {
+		localPath = ""
+	}
+	if !cfg.Incremental && cfg.HeadCommit == "" && cache.Fetched(cfg.RepoURL, localPath) {
+		slog.Debug("同じプロセス内でフェッチ済みのため、フェッチを省略します。", "path", localPath)
+		return nil
+	}
+	if err := git.Fetch(ctx); err != nil {
+		return fmt.Errorf("最新の変更のフェッチに失敗しました: %w", err)
+	}
+	cache.MarkFetched(cfg.RepoURL, localPath)
+	return nil
+}
+
+// loadDiff は、baseRef と headRef の差分を取得します。
+// 同じプロセス内で同じリポジトリ・同じコミットの組の差分を取得済みの場合は、差分の計算を省略してキャッシュを再利用します。
+// 参照をコミットハッシュに解決できない Gitアダプタでは、差分をキャッシュしません。
+func loadDiff(ctx context.Context, git adapters.GitService, cache *diffcache.Cache, repoURL, baseRef, headRef string) (string, error) {
+	key, cacheable := diffCacheKey(ctx, git, repoURL, baseRef, headRef)
+	if cacheable {
+		if diff, ok := cache.Get(key); ok {
+			slog.Info("同じコミットの組の差分を取得済みのため、キャッシュを再利用します。", "base", key.BaseSHA, "head", key.HeadSHA)
+			return diff, nil
+		}
+	}
+
+	codeDiff, err := git.GetCodeDiff(ctx, baseRef, headRef)
+	if err != nil {
+		return "", fmt.Errorf("コード差分の取得に失敗しました: %w", err)
+	}
+	if cacheable {
+		cache.Put(key, codeDiff)
+	}
+	return codeDiff, nil
+}
+
+// diffCacheKey は、ベースとヘッドの参照をコミットハッシュに解決し、差分のキャッシュキーを返します。
+// 解決できない場合は false を返します。
+func diffCacheKey(ctx context.Context, git adapters.GitService, repoURL, baseRef, headRef string) (diffcache.Key, bool) {
+	resolver, ok := git.(internalAdapters.RefResolver)
+	if !ok {
+		return diffcache.Key{}, false
+	}
+	baseSHA, err := resolver.ResolveRef(ctx, baseRef)
+	if err != nil {
+		slog.Debug("参照を解決できないため、差分をキャッシュしません。", "ref", baseRef, "error", err)
+		return diffcache.Key{}, false
+	}
+	headSHA, err := resolver.ResolveRef(ctx, headRef)
+	if err != nil {
+		slog.Debug("参照を解決できないため、差分をキャッシュしません。", "ref", headRef, "error", err)
+		return diffcache.Key{}, false
+	}
+	return diffcache.Key{RepoURL: repoURL, BaseSHA: baseSHA, HeadSHA: headSHA}, true
+}
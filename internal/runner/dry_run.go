@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+)
+
+// PromptRunner は、AIに送信するプロンプトを生成し、送信せずに返すインターフェースです。
+type PromptRunner interface {
+	DryRun(ctx context.Context, cfg config.ReviewConfig) ([]PromptPart, error)
+}
+
+// PromptPart は、ドライランで生成した、AIに送信する1つのプロンプトです。
+type PromptPart struct {
+	Mode   string
+	Part   int      // 差分を分割する場合のパート番号 (1始まり)。分割しない場合は 0
+	Parts  int      // 差分を分割する場合のパート数
+	Files  []string // 差分を分割する場合のパートの対象ファイル
+	Tokens int      // プロンプトのトークン数 (countTokens API を利用できない場合は概算値)
+	Note   string   // 実際のレビューでの扱いについての注記 (予算の超過により中止される場合など)
+	Prompt string
+}
+
+// Label は、プロンプトの見出しに使用する、モードとパートの説明を返します。
+func (p PromptPart) Label() string {
+	if p.Part == 0 {
+		return fmt.Sprintf("mode: %s", p.Mode)
+	}
+	return fmt.Sprintf("mode: %s, パート %d/%d (%s)", p.Mode, p.Part, p.Parts, strings.Join(p.Files, ", "))
+}
+
+// DryRun は、Run と同じ手順で差分の取得・機密情報のマスク・プロンプトの生成・差分の分割の判断を行い、
+// AIには送信せずに、各モードで送信するプロンプトを返します。
+// 分割したパートの統合や指摘事項の検証など、AIの応答に依存するプロンプトは含みません。
+// 差分が空の場合 (--incremental で前回のレビュー以降に新しいコミットがない場合を含む) は nil を返します。
+func (r *DefaultReviewRunner) DryRun(ctx context.Context, cfg config.ReviewConfig) ([]PromptPart, error) {
+	lock, err := acquireRepoLock(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseRepoLock(lock)
+
+	if err := r.gitService.CloneOrUpdate(ctx, cfg.RepoURL); err != nil {
+		return nil, fmt.Errorf("リポジトリのセットアップに失敗しました: %w", err)
+	}
+	defer func() {
+		if cleanupErr := r.gitService.Cleanup(ctx); cleanupErr != nil {
+			slog.Error("Gitリポジトリのクリーンアップに失敗しました。", "error", cleanupErr)
+		}
+	}()
+	if err := fetchRepo(ctx, r.gitService, r.diffCache, cfg); err != nil {
+		return nil, err
+	}
+
+	// レビューの状態は読み込むだけで、保存しない
+	baseRef, headRef := cfg.DiffRefs()
+	inc := r.prepareIncremental(ctx, cfg, baseRef, headRef)
+	if inc != nil && inc.upToDate {
+		slog.Info("前回のレビュー以降に新しいコミットがないため、送信するプロンプトはありません。", "head", inc.headSHA)
+		return nil, nil
+	}
+	if inc != nil && inc.sinceSHA != "" {
+		baseRef = inc.sinceSHA
+	}
+
+	codeDiff, err := loadDiff(ctx, r.gitService, r.diffCache, cfg.RepoURL, baseRef, headRef)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(codeDiff) == "" {
+		return nil, nil
+	}
+	codeDiff, redactor, err := redactDiff(cfg, codeDiff)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRedactor(ctx, redactor)
+	ctx = withImpact(ctx, r.analyzeImpact(ctx, cfg, codeDiff))
+	rb, err := loadRubric(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx = withRubric(ctx, rb)
+
+	commitLog := sync.OnceValue(func() []internalAdapters.Commit {
+		return r.loadCommitLog(ctx, cfg)
+	})
+
+	var parts []PromptPart
+	for _, mode := range cfg.Modes() {
+		modeCfg := cfg
+		modeCfg.ReviewMode = mode
+
+		plan, err := r.planMode(ctx, modeCfg, codeDiff, commitLog)
+		if errors.Is(err, ErrTokenBudgetExceeded) && plan.prompt != "" {
+			// 予算の超過の原因を調べられるよう、中止される場合もプロンプトを返す
+			parts = append(parts, PromptPart{Mode: mode, Tokens: r.countTokens(ctx, plan.prompt), Note: err.Error(), Prompt: plan.prompt})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if plan.chunks == nil {
+			parts = append(parts, PromptPart{Mode: mode, Tokens: r.countTokens(ctx, plan.prompt), Prompt: plan.prompt})
+			continue
+		}
+		for i, chunk := range plan.chunks {
+			part := PromptPart{Mode: mode, Part: i + 1, Parts: len(plan.chunks), Files: chunk.files, Prompt: plan.chunkPrompts[i]}
+			part.Tokens = r.countTokens(ctx, part.Prompt)
+			if i == len(plan.chunks)-1 {
+				part.Note = "各パートのレビュー後に、結果を統合するプロンプトが追加で送信されます。"
+			}
+			parts = append(parts, part)
+		}
+	}
+	return parts, nil
+}
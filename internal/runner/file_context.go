@@ -0,0 +1,183 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/assets"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/imports"
+	"git-gemini-cli/internal/prompts"
+)
+
+// loadFileContext は、差分で変更されたファイルの変更後の内容を、cfg.ContextMaxBytes の合計サイズに収まる範囲で読み込みます。
+// 削除されたファイルとバイナリファイルは対象外です。取得に失敗したファイルは省略し、差分のみでレビューを続けます。
+func (r *DefaultReviewRunner) loadFileContext(ctx context.Context, cfg config.ReviewConfig, codeDiff string) prompts.FileContext {
+	var fc prompts.FileContext
+
+	provider, ok := r.gitService.(internalAdapters.FileContentProvider)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはファイル内容の取得に対応していないため、差分のみを使用します。")
+		return fc
+	}
+
+	_, headRef := cfg.DiffRefs()
+	remaining := cfg.ContextMaxBytes
+	for _, f := range diffutil.ParseFiles(codeDiff) {
+		if f.Deleted || f.Binary {
+			continue
+		}
+
+		content, err := provider.GetFileContent(ctx, headRef, f.Path)
+		if err != nil {
+			slog.Warn("変更後のファイル内容の取得に失敗したため、差分のみを使用します。", "path", f.Path, "error", err)
+			continue
+		}
+		if bytes.IndexByte(content, 0) >= 0 {
+			continue
+		}
+		if len(content) > remaining {
+			fc.Omitted = append(fc.Omitted, f.Path)
+			continue
+		}
+
+		remaining -= len(content)
+		fc.Files = append(fc.Files, prompts.FileContent{Path: f.Path, Content: redactText(ctx, f.Path, string(content))})
+	}
+
+	slog.Info("変更後のファイル全体をコンテキストに追加します。", "files", len(fc.Files), "omitted", len(fc.Omitted), "bytes", cfg.ContextMaxBytes-remaining)
+	return fc
+}
+
+// loadImportContext は、差分で変更されたファイルがインポートしているリポジトリ内の宣言を、
+// cfg.ContextMaxBytes の合計サイズに収まる範囲で抽出します。対応言語は Go / TypeScript / Python です。
+func (r *DefaultReviewRunner) loadImportContext(ctx context.Context, cfg config.ReviewConfig, codeDiff string) prompts.ImportContext {
+	var ic prompts.ImportContext
+
+	provider, ok := r.gitService.(internalAdapters.FileContentProvider)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはファイル内容の取得に対応していないため、インポート先の宣言は追加しません。")
+		return ic
+	}
+
+	_, headRef := cfg.DiffRefs()
+	src := &refSource{provider: provider, ref: headRef, files: make(map[string][]byte)}
+	remaining := cfg.ContextMaxBytes
+	seen := make(map[string]bool)
+	for _, f := range diffutil.ParseFiles(codeDiff) {
+		if f.Deleted || !imports.Supported(f.Path) {
+			continue
+		}
+		content, err := src.ReadFile(ctx, f.Path)
+		if err != nil {
+			slog.Warn("変更後のファイル内容の取得に失敗したため、インポートの解析をスキップします。", "path", f.Path, "error", err)
+			continue
+		}
+
+		decls, err := imports.Resolve(ctx, src, f.Path, content)
+		if err != nil {
+			slog.Warn("インポートの解析に失敗しました。", "path", f.Path, "error", err)
+			continue
+		}
+		for _, d := range decls {
+			key := d.File + "#" + d.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if len(d.Code) > remaining {
+				ic.Omitted++
+				continue
+			}
+			remaining -= len(d.Code)
+			d.Code = redactText(ctx, d.File, d.Code)
+			ic.Declarations = append(ic.Declarations, d)
+		}
+	}
+
+	slog.Info("インポート先の宣言をコンテキストに追加します。", "declarations", len(ic.Declarations), "omitted", ic.Omitted)
+	return ic
+}
+
+// loadAssetContext は、差分で変更されたバイナリファイルについて、変更前後の内容から形式・サイズ・画像の寸法を取得します。
+// 内容を取得できない場合も、変更されたことが分かるようメタデータなしで一覧に含めます。
+func (r *DefaultReviewRunner) loadAssetContext(ctx context.Context, cfg config.ReviewConfig, codeDiff string) prompts.AssetContext {
+	var ac prompts.AssetContext
+	provider, _ := r.gitService.(internalAdapters.FileContentProvider)
+
+	baseRef, headRef := cfg.DiffRefs()
+	inspect := func(ref, path string) *assets.Metadata {
+		if provider == nil || path == "" {
+			return nil
+		}
+		content, err := provider.GetFileContent(ctx, ref, path)
+		if err != nil {
+			slog.Warn("バイナリファイルの内容の取得に失敗したため、メタデータは省略します。", "ref", ref, "path", path, "error", err)
+			return nil
+		}
+		meta := assets.Inspect(path, content)
+		return &meta
+	}
+
+	for _, f := range diffutil.ParseFiles(codeDiff) {
+		if !f.Binary {
+			continue
+		}
+		change := prompts.AssetChange{Path: f.Path, Created: f.Created, Deleted: f.Deleted}
+		if !f.Created {
+			change.Old = inspect(baseRef, f.OldPath)
+		}
+		if !f.Deleted {
+			change.New = inspect(headRef, f.Path)
+		}
+		ac.Assets = append(ac.Assets, change)
+	}
+
+	if len(ac.Assets) > 0 {
+		slog.Info("変更されたバイナリファイルのメタデータをコンテキストに追加します。", "files", len(ac.Assets))
+	}
+	return ac
+}
+
+// refSource は、FileContentProvider を特定の参照に固定した imports.Source です。
+// 同じファイルを複数回参照するため、読み込んだ内容 (存在しないことを含む) をキャッシュします。
+type refSource struct {
+	provider internalAdapters.FileContentProvider
+	ref      string
+	files    map[string][]byte
+}
+
+// ReadFile は imports.Source インターフェースの実装です。
+func (s *refSource) ReadFile(ctx context.Context, path string) ([]byte, error) {
+	if content, ok := s.files[path]; ok {
+		if content == nil {
+			return nil, os.ErrNotExist
+		}
+		return content, nil
+	}
+
+	content, err := s.provider.GetFileContent(ctx, s.ref, path)
+	if err != nil {
+		s.files[path] = nil
+		return nil, err
+	}
+	if content == nil {
+		content = []byte{}
+	}
+	s.files[path] = content
+	return content, nil
+}
+
+// ListTree は impact.Source インターフェースの実装です。
+func (s *refSource) ListTree(ctx context.Context) ([]string, error) {
+	return s.provider.ListTree(ctx, s.ref)
+}
+
+// ListFiles は imports.Source インターフェースの実装です。
+func (s *refSource) ListFiles(ctx context.Context, dir string) ([]string, error) {
+	return s.provider.ListFiles(ctx, s.ref, dir)
+}
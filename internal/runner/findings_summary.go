@@ -0,0 +1,36 @@
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+)
+
+// appendFindingsSummary は、構造化された指摘事項を出力させた場合に、レポートの末尾に分類ごとの件数と平均の確信度のセクションを追加します。
+// 指摘事項がない場合や、分類・確信度が1件も付与されていない場合は、レポートをそのまま返します。
+func appendFindingsSummary(cfg config.ReviewConfig, report string) string {
+	if !cfg.NeedsFindings() {
+		return report
+	}
+	_, list := findings.Split(report)
+	counts := findings.CountByCategory(list)
+	if len(counts) == 0 || (len(counts) == 1 && counts[0].Category == "" && counts[0].AvgConfidence == 0) {
+		return report
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	b.WriteString("\n\n---\n\n## 📊 指摘事項の分類\n\n")
+	fmt.Fprintf(&b, "指摘事項 %d 件の分類ごとの件数です。\n\n", len(list))
+	b.WriteString("| 分類 | 件数 | 平均の確信度 |\n| :--- | ---: | ---: |\n")
+	for _, cc := range counts {
+		confidence := "-"
+		if cc.AvgConfidence > 0 {
+			confidence = fmt.Sprintf("%.2f", cc.AvgConfidence)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %s |\n", cc.Category.Label(), cc.Count, confidence)
+	}
+	return b.String()
+}
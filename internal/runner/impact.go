@@ -0,0 +1,132 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/impact"
+)
+
+// maxReportImpactItems は、レポートに列挙する影響を受けるパッケージ・ターゲットの上限です。
+const maxReportImpactItems = 50
+
+// impactKey は、context に影響範囲の解析結果を格納するためのキーです。
+type impactKey struct{}
+
+// withImpact は、各モードのプロンプトで共有する影響範囲の解析結果を設定した context を返します。
+func withImpact(ctx context.Context, result *impact.Result) context.Context {
+	if result == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, impactKey{}, result)
+}
+
+// impactFrom は、context に設定された影響範囲の解析結果を返します。解析していない場合は nil を返します。
+func impactFrom(ctx context.Context) *impact.Result {
+	result, _ := ctx.Value(impactKey{}).(*impact.Result)
+	return result
+}
+
+// analyzeImpact は、cfg.ImpactAnalysis に従って変更の影響範囲を求めます。
+// 解析しない設定の場合や解析に失敗した場合は nil を返し、影響範囲なしでレビューを続けます。
+func (r *DefaultReviewRunner) analyzeImpact(ctx context.Context, cfg config.ReviewConfig, codeDiff string) *impact.Result {
+	if cfg.ImpactAnalysis == "" || cfg.ImpactAnalysis == config.ImpactNone {
+		return nil
+	}
+
+	changedFiles := diffutil.ChangedFiles(codeDiff)
+	var (
+		result impact.Result
+		err    error
+	)
+	switch cfg.ImpactAnalysis {
+	case config.ImpactBazel:
+		result, err = r.analyzeBazel(ctx, cfg, changedFiles)
+	case config.ImpactGo:
+		provider, ok := r.gitService.(internalAdapters.FileContentProvider)
+		if !ok {
+			slog.Warn("使用中のGitアダプタはファイル内容の取得に対応していないため、影響範囲の解析をスキップします。")
+			return nil
+		}
+		_, headRef := cfg.DiffRefs()
+		src := &refSource{provider: provider, ref: headRef, files: make(map[string][]byte)}
+		result, err = impact.AnalyzeGo(ctx, src, changedFiles)
+	}
+	if err != nil {
+		slog.Warn("変更の影響範囲の解析に失敗したため、影響範囲なしでレビューを続けます。", "tool", cfg.ImpactAnalysis, "error", err)
+		return nil
+	}
+
+	slog.Info("変更の影響範囲を解析しました。", "tool", result.Tool, "changed", len(result.Changed), "dependents", len(result.Dependents))
+	return &result
+}
+
+// analyzeBazel は、レビュー対象のコミットを一時的な git worktree にチェックアウトし、そのワークスペースで bazel query を実行します。
+// クローンのワーキングツリーにはベースブランチ (またはデフォルトブランチ) がチェックアウトされているため、
+// ヘッドで追加・変更されたターゲットや BUILD ファイルを反映するには、レビュー対象のコミットのワークスペースが必要です。
+func (r *DefaultReviewRunner) analyzeBazel(ctx context.Context, cfg config.ReviewConfig, changedFiles []string) (impact.Result, error) {
+	provider, ok := r.gitService.(internalAdapters.WorktreeProvider)
+	if !ok {
+		return impact.Result{}, fmt.Errorf("%w: bazel による解析にはレビュー対象のコミットのチェックアウトが必要です (--ephemeral を外し、--use-external-git-command を指定してください)", internalAdapters.ErrWorktreeUnsupported)
+	}
+	_, headRef := cfg.DiffRefs()
+	dir, remove, err := provider.AddWorktree(ctx, headRef)
+	if err != nil {
+		return impact.Result{}, err
+	}
+	defer remove()
+	// worktree ごとに起動する bazel のサーバーが残らないよう、解析後に停止する
+	defer impact.ShutdownBazel(ctx, dir)
+
+	slog.Debug("レビュー対象のコミットの worktree で bazel query を実行します。", "ref", headRef, "dir", dir)
+	return impact.AnalyzeBazel(ctx, dir, changedFiles)
+}
+
+// appendImpactReport は、レポートの末尾に影響範囲のセクションを追加します。
+// 影響範囲が cfg.BlastRadiusThreshold を超える場合は、--fail-on の判定対象となる指摘事項も追加します。
+func appendImpactReport(cfg config.ReviewConfig, report string, result *impact.Result) string {
+	if result == nil || len(result.Changed) == 0 {
+		return report
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	fmt.Fprintf(&b, "\n\n---\n\n## 変更の影響範囲 (%s)\n\n", result.Tool)
+	fmt.Fprintf(&b, "影響を受ける%sは **%d 件** です (直接: %d 件、推移的: %d 件)。\n", result.Unit, result.BlastRadius(), len(result.Changed), len(result.Dependents))
+	writeImpactList(&b, "変更された"+result.Unit, result.Changed)
+	writeImpactList(&b, "依存する"+result.Unit, result.Dependents)
+
+	if cfg.BlastRadiusThreshold > 0 && result.BlastRadius() > cfg.BlastRadiusThreshold {
+		severity, _ := findings.ParseSeverity(cfg.BlastRadiusSeverity)
+		title := fmt.Sprintf("影響範囲の広い変更です (影響を受ける%s: %d 件、しきい値: %d 件)", result.Unit, result.BlastRadius(), cfg.BlastRadiusThreshold)
+		fmt.Fprintf(&b, "\n> ⚠️ **[%s] %s**\n", severity, title)
+		b.WriteString("\n")
+		b.WriteString(findings.Block([]findings.Finding{{Severity: severity, Title: title}}))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// writeImpactList は、影響範囲の一覧を上限件数まで Markdown のリストとして書き出します。
+func writeImpactList(b *strings.Builder, heading string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n**%s** (%d 件)\n\n", heading, len(items))
+	shown, omitted := items, 0
+	if len(items) > maxReportImpactItems {
+		shown, omitted = items[:maxReportImpactItems], len(items)-maxReportImpactItems
+	}
+	for _, item := range shown {
+		fmt.Fprintf(b, "- `%s`\n", item)
+	}
+	if omitted > 0 {
+		fmt.Fprintf(b, "- ほか %d 件\n", omitted)
+	}
+}
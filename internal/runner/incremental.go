@@ -0,0 +1,166 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/reviewstate"
+	"git-gemini-cli/internal/timeutil"
+)
+
+// incrementalReview は、前回レビューしたコミット以降の差分のみをレビューする (--incremental) 1回の実行の状態です。
+type incrementalReview struct {
+	stateURI   string
+	key        string
+	headSHA    string
+	sinceSHA   string    // 前回レビューしたコミット (空の場合はブランチ全体の差分をレビューする)
+	reviewedAt time.Time // 前回のレビューの日時
+	commits    int       // 前回のレビュー以降のコミット数
+	fullReason string    // 前回の記録があるにも関わらず、ブランチ全体の差分をレビューする理由
+	upToDate   bool      // 前回のレビュー以降に新しいコミットがない
+}
+
+// prepareIncremental は、cfg.Incremental が有効な場合に、前回レビューしたコミットを状態ファイルから読み込みます。
+// 前回のコミットが現在のブランチに含まれない場合 (リベースや force push) は、ブランチ全体の差分をレビューします。
+// 差分レビューを行えない場合 (参照を解決できない Gitアダプタなど) は、警告を出して nil を返します。
+func (r *DefaultReviewRunner) prepareIncremental(ctx context.Context, cfg config.ReviewConfig, baseRef, headRef string) *incrementalReview {
+	if !cfg.Incremental {
+		return nil
+	}
+	resolver, ok := r.gitService.(internalAdapters.RefResolver)
+	if !ok {
+		slog.Warn("使用中のGitアダプタは参照の解決に対応していないため、--incremental を無視してブランチ全体の差分をレビューします。")
+		return nil
+	}
+	headSHA, err := resolver.ResolveRef(ctx, headRef)
+	if err != nil {
+		slog.Warn("レビュー対象のコミットを特定できないため、--incremental を無視してブランチ全体の差分をレビューします。", "ref", headRef, "error", err)
+		return nil
+	}
+	stateURI := cfg.StateURI
+	if stateURI == "" {
+		if stateURI, err = reviewstate.DefaultURI(); err != nil {
+			slog.Warn("レビューの状態の保存先を決定できないため、--incremental を無視します。", "error", err)
+			return nil
+		}
+	}
+
+	// キーにはコミットハッシュではなくブランチを使用する (headRef が HeadCommit の場合も、前回の push の記録を参照できるよう)
+	stateBase, stateHead := cfg.StateRefs()
+	inc := &incrementalReview{stateURI: stateURI, key: reviewstate.Key(cfg.RepoURL, stateBase, stateHead), headSHA: headSHA}
+	entry, found, err := reviewstate.Load(ctx, stateURI, inc.key)
+	switch {
+	case err != nil:
+		slog.Warn("レビューの状態を読み込めないため、ブランチ全体の差分をレビューします。", "uri", stateURI, "error", err)
+		return inc
+	case !found:
+		slog.Info("前回のレビューの記録がないため、ブランチ全体の差分をレビューします。", "uri", stateURI)
+		return inc
+	case entry.HeadSHA == headSHA:
+		inc.upToDate = true
+		return inc
+	}
+
+	provider, ok := r.gitService.(internalAdapters.CommitLogProvider)
+	if !ok {
+		inc.fullReason = "使用中のGitアダプタではコミットの履歴を確認できないため"
+		return inc
+	}
+	// 前回のコミットのうち現在のブランチに含まれないものがあれば、履歴が書き換えられている
+	rewritten, err := provider.GetCommitLog(ctx, headSHA, entry.HeadSHA)
+	if err != nil {
+		inc.fullReason = fmt.Sprintf("前回レビューしたコミット `%s` を取得できないため", shortHash(entry.HeadSHA))
+		return inc
+	}
+	if len(rewritten) > 0 {
+		inc.fullReason = fmt.Sprintf("前回レビューしたコミット `%s` が現在のブランチに含まれないため (リベースまたは force push)", shortHash(entry.HeadSHA))
+		return inc
+	}
+	commits, err := provider.GetCommitLog(ctx, entry.HeadSHA, headSHA)
+	if err != nil {
+		slog.Debug("前回のレビュー以降のコミットログを取得できませんでした。", "error", err)
+	}
+
+	inc.sinceSHA = entry.HeadSHA
+	inc.reviewedAt = entry.ReviewedAt
+	inc.commits = len(commits)
+	slog.Info("前回レビューしたコミット以降の差分のみをレビューします。", "since", inc.sinceSHA, "head", headSHA, "commits", inc.commits)
+	return inc
+}
+
+// section は、レポートの先頭に追加する「前回のレビュー以降の変更」のセクションを返します。
+// 前回の記録がなく、ブランチ全体の差分をレビューした場合は空文字を返します。
+func (inc *incrementalReview) section() string {
+	var b strings.Builder
+	switch {
+	case inc.sinceSHA != "":
+		b.WriteString("## 🔁 前回のレビュー以降の変更\n\n")
+		fmt.Fprintf(&b, "前回レビューしたコミット `%s`", shortHash(inc.sinceSHA))
+		if !inc.reviewedAt.IsZero() {
+			fmt.Fprintf(&b, " (%s)", timeutil.FormatReport(inc.reviewedAt))
+		}
+		b.WriteString(" 以降の")
+		if inc.commits > 0 {
+			fmt.Fprintf(&b, " **%d 件のコミット**", inc.commits)
+		}
+		fmt.Fprintf(&b, " (`%s..%s`) の差分のみをレビューしました。それ以前の変更に対する指摘は、前回のレポートを参照してください。\n\n---\n\n", shortHash(inc.sinceSHA), shortHash(inc.headSHA))
+	case inc.fullReason != "":
+		b.WriteString("## 🔁 前回のレビュー以降の変更\n\n")
+		fmt.Fprintf(&b, "%s、ブランチ全体の差分をレビューしました。\n\n---\n\n", inc.fullReason)
+	}
+	return b.String()
+}
+
+// StateHandler は、--incremental で今回レビューしたコミットを記録する関数 save を受け取る関数です。
+type StateHandler func(save func(ctx context.Context))
+
+// stateKey は、context に StateHandler を格納するためのキーです。
+type stateKey struct{}
+
+// WithStateHandler は、今回レビューしたコミットをレビューの完了時に記録せず、記録する関数を handler に渡すよう設定した context を返します。
+// 公開やプルリクエストへの投稿に失敗したレビューのコミットを記録すると、次回のレビューで「新しいコミットがない」としてスキップされるため、
+// 呼び出し元はレビュー結果の配信に成功した後で save を呼び出します。
+func WithStateHandler(ctx context.Context, handler StateHandler) context.Context {
+	return context.WithValue(ctx, stateKey{}, handler)
+}
+
+// commit は、context に StateHandler が設定されている場合は記録を呼び出し元に委ね、それ以外の場合は今回レビューしたコミットを記録します。
+func (inc *incrementalReview) commit(ctx context.Context, cfg config.ReviewConfig, report string) {
+	if h, ok := ctx.Value(stateKey{}).(StateHandler); ok {
+		h(func(ctx context.Context) { inc.save(ctx, cfg, report) })
+		return
+	}
+	inc.save(ctx, cfg, report)
+}
+
+// save は、今回レビューしたコミットを状態ファイルに記録し、次回のレビューの起点にします。
+// --fail-on のしきい値を超える指摘事項がある場合は、修正されるまで同じ指摘で判定できるよう記録しません。
+func (inc *incrementalReview) save(ctx context.Context, cfg config.ReviewConfig, report string) {
+	if cfg.FailOn != "" {
+		if threshold, err := findings.ParseSeverity(cfg.FailOn); err == nil {
+			_, list := findings.Split(report)
+			if findings.CheckThreshold(list, threshold) != nil {
+				slog.Info("--fail-on のしきい値を超える指摘事項があるため、前回レビューしたコミットの記録を更新しません。", "head", inc.headSHA)
+				return
+			}
+		}
+	}
+
+	if err := reviewstate.Save(ctx, inc.stateURI, inc.key, reviewstate.Entry{HeadSHA: inc.headSHA, ReviewedAt: time.Now()}); err != nil {
+		// 記録に失敗しても今回のレビュー結果は有効なため、警告にとどめる (次回はブランチ全体または前回の記録からの差分をレビューする)
+		slog.Warn("レビューしたコミットの記録に失敗しました。", "uri", inc.stateURI, "error", err)
+		return
+	}
+	slog.Debug("レビューしたコミットを記録しました。", "uri", inc.stateURI, "key", inc.key, "head", inc.headSHA)
+}
+
+// shortHash は、コミットハッシュの先頭7文字を返します。
+func shortHash(sha string) string {
+	return internalAdapters.Commit{Hash: sha}.ShortHash()
+}
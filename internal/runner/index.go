@@ -0,0 +1,208 @@
+package runner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/objectstore"
+	"git-gemini-cli/internal/timeutil"
+)
+
+const (
+	// indexJSONName と indexHTMLName は、公開先のプレフィックスに保存するレビューの一覧のファイル名です。
+	indexJSONName = "index.json"
+	indexHTMLName = "index.html"
+	// indexSchemaVersion は、index.json の形式のバージョンです。互換性のない変更を行う場合に更新します。
+	indexSchemaVersion = 1
+	// indexMaxEntries は、インデックスに保持するレビューの最大件数です。超えた場合は古いものから削除します。
+	indexMaxEntries = 1000
+	// indexMaxAttempts は、他の実行と同時にインデックスを更新した場合に、読み込みからやり直す最大回数です。
+	indexMaxAttempts = 5
+)
+
+// indexEntry は、インデックスに記録する1件の公開済みレビューです。
+type indexEntry struct {
+	Report      string    `json:"report"` // インデックスからの相対パス
+	RepoURL     string    `json:"repo_url"`
+	BaseRef     string    `json:"base_ref"`
+	HeadRef     string    `json:"head_ref,omitempty"`
+	ReviewMode  string    `json:"review_mode"`
+	Findings    *int      `json:"findings,omitempty"` // 指摘事項の件数 (判定できなかった場合は省略)
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// reviewIndex は、公開先のプレフィックスに保存する過去のレビューの一覧 (index.json) です。新しいものから順に並べます。
+type reviewIndex struct {
+	SchemaVersion int          `json:"schema_version"`
+	Entries       []indexEntry `json:"entries"`
+}
+
+// indexTemplate は、過去のレビューの一覧 (index.html) のテンプレートです。
+var indexTemplate = template.Must(template.New("index").Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>AI コードレビューの一覧</title>
+<style>
+body { max-width: 1200px; margin: 2rem auto; padding: 0 1rem; font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", "Hiragino Sans", "Noto Sans JP", sans-serif; color: #1f2328; }
+table { border-collapse: collapse; width: 100%; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 0.4rem 0.7rem; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+td.num { text-align: right; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>AI コードレビューの一覧</h1>
+<p>{{len .Entries}} 件 (最終更新: {{.UpdatedAt}})</p>
+<table>
+<thead><tr><th>公開日時</th><th>リポジトリ</th><th>ブランチ</th><th>モード</th><th>指摘事項</th><th>レポート</th></tr></thead>
+<tbody>
+{{range .Entries}}<tr><td>{{.PublishedAt}}</td><td>{{.RepoURL}}</td><td><code>{{.BaseRef}}</code>{{if .HeadRef}} ... <code>{{.HeadRef}}</code>{{end}}</td><td>{{.ReviewMode}}</td><td class="num">{{if .Findings}}{{.Findings}}{{else}}-{{end}}</td><td><a href="{{.Report}}">{{.Report}}</a></td></tr>
+{{end}}</tbody>
+</table>
+</body>
+</html>
+`))
+
+// splitReportURI は、レポートのURIを公開先のプレフィックス (末尾の "/" まで) とファイル名に分割します。
+func splitReportURI(reportURI string) (prefix, name string) {
+	i := strings.LastIndex(reportURI, "/")
+	return reportURI[:i+1], reportURI[i+1:]
+}
+
+// indexURIs は、レポートと同じプレフィックス (ディレクトリ) にあるインデックスの JSON と HTML のURI、およびインデックスからのレポートの相対パスを返します。
+func indexURIs(reportURI string) (jsonURI, htmlURI, report string) {
+	prefix, report := splitReportURI(reportURI)
+	return prefix + indexJSONName, prefix + indexHTMLName, report
+}
+
+// updateIndex は、公開したレポートを公開先のプレフィックスにある過去のレビューの一覧 (index.json と index.html) に追加します。
+// 同時に公開した他の実行の記録を失わないよう、index.json は条件付き書き込みで更新します。
+// 一覧は二次的な成果物のため、更新に失敗してもエラーを記録して公開処理を続行します。
+// removed には、保持期間の適用 (applyRetention) で削除したレポートのファイル名を指定し、一覧から取り除きます。
+func (p *DefaultPublisherRunner) updateIndex(ctx context.Context, cfg config.PublishConfig, removed []string) {
+	jsonURI, htmlURI, report := indexURIs(cfg.StorageURI)
+	if p.store == nil {
+		slog.Warn("公開先のストレージを直接操作できないため、レビューの一覧を更新できません。", "uri", jsonURI)
+		return
+	}
+	if report == indexHTMLName || report == indexJSONName {
+		slog.Warn("レポートのファイル名がレビューの一覧と同じため、一覧を更新しません。", "uri", cfg.StorageURI)
+		return
+	}
+
+	entry := newIndexEntry(ctx, cfg, report)
+	var index reviewIndex
+	var err error
+	for attempt := 1; attempt <= indexMaxAttempts; attempt++ {
+		index, err = p.writeIndexJSON(ctx, jsonURI, entry, removed)
+		if !errors.Is(err, objectstore.ErrPreconditionFailed) {
+			break
+		}
+		slog.Info("他の実行がレビューの一覧を更新したため、読み込みからやり直します。", "uri", jsonURI, "attempt", attempt)
+	}
+	if err != nil {
+		slog.Error("レビューの一覧の更新に失敗しましたが、レポートの公開は成功しているため処理を続行します。", "uri", jsonURI, "error", err)
+		return
+	}
+
+	page, err := renderIndexHTML(index)
+	if err == nil {
+		err = p.store.Write(ctx, htmlURI, page, "text/html; charset=utf-8")
+	}
+	if err != nil {
+		slog.Error("レビューの一覧の HTML の保存に失敗しましたが、レポートの公開は成功しているため処理を続行します。", "uri", htmlURI, "error", err)
+		return
+	}
+	slog.Info("レビューの一覧を更新しました。", "uri", htmlURI, "entries", len(index.Entries))
+}
+
+// newIndexEntry は、公開したレポートのインデックスの項目を生成します。
+func newIndexEntry(ctx context.Context, cfg config.PublishConfig, report string) indexEntry {
+	rc := cfg.ReviewConfig
+	baseRef, headRef := rc.DiffRefs()
+	entry := indexEntry{
+		Report:      report,
+		RepoURL:     rc.RepoURL,
+		BaseRef:     baseRef,
+		HeadRef:     headRef,
+		ReviewMode:  rc.ReviewMode,
+		PublishedAt: time.Now().UTC(),
+	}
+	if list, ok := findings.FromContext(ctx); ok {
+		n := len(list)
+		entry.Findings = &n
+	}
+	return entry
+}
+
+// writeIndexJSON は、index.json を読み込んで entry を先頭に追加し、読み込んだ時点のバージョンを条件に書き込みます。
+// 同じレポートの項目が既にある場合 (同じURIへの再公開) は置き換え、removed に含まれるレポートの項目は取り除きます。
+func (p *DefaultPublisherRunner) writeIndexJSON(ctx context.Context, uri string, entry indexEntry, removed []string) (reviewIndex, error) {
+	var index reviewIndex
+	var version string
+	obj, err := p.store.Read(ctx, uri)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(obj.Data, &index); err != nil {
+			return reviewIndex{}, fmt.Errorf("レビューの一覧 '%s' の解析に失敗しました: %w", uri, err)
+		}
+		version = obj.Version
+	case !errors.Is(err, objectstore.ErrNotFound):
+		return reviewIndex{}, fmt.Errorf("レビューの一覧 '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+
+	entries := []indexEntry{entry}
+	for _, e := range index.Entries {
+		if e.Report != entry.Report && !slices.Contains(removed, e.Report) {
+			entries = append(entries, e)
+		}
+	}
+	if len(entries) > indexMaxEntries {
+		entries = entries[:indexMaxEntries]
+	}
+	index = reviewIndex{SchemaVersion: indexSchemaVersion, Entries: entries}
+
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return reviewIndex{}, fmt.Errorf("レビューの一覧の生成に失敗しました: %w", err)
+	}
+	if err := p.store.WriteIf(ctx, uri, data, "application/json", version); err != nil {
+		return reviewIndex{}, err
+	}
+	return index, nil
+}
+
+// renderIndexHTML は、レビューの一覧を HTML に変換します。
+func renderIndexHTML(index reviewIndex) ([]byte, error) {
+	type row struct {
+		indexEntry
+		PublishedAt string
+	}
+	rows := make([]row, len(index.Entries))
+	for i, e := range index.Entries {
+		rows[i] = row{indexEntry: e, PublishedAt: timeutil.FormatReport(e.PublishedAt)}
+	}
+
+	var page bytes.Buffer
+	err := indexTemplate.Execute(&page, struct {
+		Entries   []row
+		UpdatedAt string
+	}{Entries: rows, UpdatedAt: timeutil.FormatReport(time.Now())})
+	if err != nil {
+		return nil, fmt.Errorf("レビューの一覧の HTML の生成に失敗しました: %w", err)
+	}
+	return page.Bytes(), nil
+}
@@ -0,0 +1,176 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/objectstore"
+)
+
+const (
+	// manifestName は、公開先のプレフィックスに保存するレビューのマニフェストのファイル名です。
+	manifestName = "manifest.json"
+	// manifestSchemaVersion は、manifest.json の形式のバージョンです。互換性のない変更を行う場合に更新します。
+	manifestSchemaVersion = 1
+	// manifestMaxEntries は、マニフェストに保持するレビューの最大件数です。超えた場合は古いものから削除します。
+	manifestMaxEntries = 10000
+	// manifestMaxAttempts は、他の実行と同時にマニフェストを更新した場合に、読み込みからやり直す最大回数です。
+	manifestMaxAttempts = 5
+)
+
+// manifestEntry は、マニフェストに記録する1件の公開済みレビューです。
+type manifestEntry struct {
+	Report      string         `json:"report"` // マニフェストからの相対パス
+	URI         string         `json:"uri"`    // レポートの公開先のURI
+	RepoURL     string         `json:"repo_url"`
+	BaseRef     string         `json:"base_ref"`
+	HeadRef     string         `json:"head_ref,omitempty"`
+	BaseSHA     string         `json:"base_sha,omitempty"` // コミットハッシュを取得できなかった場合は省略
+	HeadSHA     string         `json:"head_sha,omitempty"`
+	ReviewMode  string         `json:"review_mode"`
+	Model       string         `json:"model,omitempty"`
+	Verdict     string         `json:"verdict,omitempty"`  // 判定 (findings.StatusFixRequired など。指摘事項を判定できなかった場合は省略)
+	Findings    *int           `json:"findings,omitempty"` // 指摘事項の件数
+	Counts      map[string]int `json:"counts,omitempty"`   // 深刻度ごとの件数
+	PublishedAt time.Time      `json:"published_at"`
+	Reviewers   []string       `json:"reviewers,omitempty"` // reviewers コマンドで推奨したレビュアー (担当中のレビューの件数の集計に使用)
+}
+
+// reviewManifest は、公開先のプレフィックスに保存する公開済みレビューの記録 (manifest.json) です。新しいものから順に並べます。
+// 過去のレビューの推移や履歴を集計する機能の元データとして使用します。
+type reviewManifest struct {
+	SchemaVersion int             `json:"schema_version"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+	Reviews       []manifestEntry `json:"reviews"`
+}
+
+// updateManifest は、公開したレポートを公開先のプレフィックスにあるレビューのマニフェスト (manifest.json) に記録します。
+// 同時に公開した他の実行の記録を失わないよう、条件付き書き込みで更新します。
+// マニフェストは二次的な成果物のため、更新に失敗してもエラーを記録して公開処理を続行します。
+// removed には、保持期間の適用 (applyRetention) で削除したレポートのファイル名を指定し、マニフェストから取り除きます。
+func (p *DefaultPublisherRunner) updateManifest(ctx context.Context, cfg config.PublishConfig, removed []string) {
+	prefix, report := splitReportURI(cfg.StorageURI)
+	uri := prefix + manifestName
+	if p.store == nil {
+		slog.Warn("公開先のストレージを直接操作できないため、レビューのマニフェストを更新できません。", "uri", uri)
+		return
+	}
+	if report == manifestName {
+		slog.Warn("レポートのファイル名がレビューのマニフェストと同じため、マニフェストを更新しません。", "uri", cfg.StorageURI)
+		return
+	}
+
+	entry := newManifestEntry(ctx, cfg, report)
+	var manifest reviewManifest
+	var err error
+	for attempt := 1; attempt <= manifestMaxAttempts; attempt++ {
+		manifest, err = p.writeManifest(ctx, uri, entry, removed)
+		if !errors.Is(err, objectstore.ErrPreconditionFailed) {
+			break
+		}
+		slog.Info("他の実行がレビューのマニフェストを更新したため、読み込みからやり直します。", "uri", uri, "attempt", attempt)
+	}
+	if err != nil {
+		slog.Error("レビューのマニフェストの更新に失敗しましたが、レポートの公開は成功しているため処理を続行します。", "uri", uri, "error", err)
+		return
+	}
+	slog.Info("レビューのマニフェストを更新しました。", "uri", uri, "reviews", len(manifest.Reviews))
+}
+
+// newManifestEntry は、公開したレポートのマニフェストの項目を生成します。
+func newManifestEntry(ctx context.Context, cfg config.PublishConfig, report string) manifestEntry {
+	rc := cfg.ReviewConfig
+	baseRef, headRef := rc.DiffRefs()
+	baseSHA, headSHA := commitsFromContext(ctx)
+	entry := manifestEntry{
+		Report:      report,
+		URI:         cfg.StorageURI,
+		RepoURL:     rc.RepoURL,
+		BaseRef:     baseRef,
+		HeadRef:     headRef,
+		BaseSHA:     baseSHA,
+		HeadSHA:     headSHA,
+		ReviewMode:  rc.ReviewMode,
+		Model:       rc.Model,
+		PublishedAt: time.Now().UTC(),
+	}
+	if list, ok := findings.FromContext(ctx); ok {
+		s := findings.Summarize(list)
+		entry.Verdict = s.Status
+		entry.Findings = &s.Total
+		entry.Counts = make(map[string]int, len(s.Counts))
+		for sev, n := range s.Counts {
+			entry.Counts[sev.String()] = n
+		}
+	}
+	return entry
+}
+
+// writeManifest は、manifest.json を読み込んで entry を先頭に追加し、読み込んだ時点のバージョンを条件に書き込みます。
+// 同じレポートの項目が既にある場合 (同じURIへの再公開) は置き換え、removed に含まれるレポートの項目は取り除きます。
+func (p *DefaultPublisherRunner) writeManifest(ctx context.Context, uri string, entry manifestEntry, removed []string) (reviewManifest, error) {
+	manifest, version, err := readManifest(ctx, p.store, uri)
+	if err != nil {
+		return reviewManifest{}, err
+	}
+
+	reviews := []manifestEntry{entry}
+	for _, e := range manifest.Reviews {
+		if e.Report == entry.Report {
+			// 同じレポートの再公開では、推奨したレビュアーの記録を引き継ぐ
+			if len(reviews[0].Reviewers) == 0 {
+				reviews[0].Reviewers = e.Reviewers
+			}
+			continue
+		}
+		if !slices.Contains(removed, e.Report) {
+			reviews = append(reviews, e)
+		}
+	}
+	if len(reviews) > manifestMaxEntries {
+		reviews = reviews[:manifestMaxEntries]
+	}
+	manifest = reviewManifest{SchemaVersion: manifestSchemaVersion, UpdatedAt: entry.PublishedAt, Reviews: reviews}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return reviewManifest{}, fmt.Errorf("レビューのマニフェストの生成に失敗しました: %w", err)
+	}
+	if err := p.store.WriteIf(ctx, uri, data, "application/json", version); err != nil {
+		return reviewManifest{}, err
+	}
+	return manifest, nil
+}
+
+// ManifestURI は、公開先のプレフィックス (または manifest.json のURI) から、レビューのマニフェストのURIを返します。
+func ManifestURI(uri string) string {
+	if path.Base(uri) == manifestName {
+		return uri
+	}
+	return strings.TrimSuffix(uri, "/") + "/" + manifestName
+}
+
+// readManifest は、manifest.json とその読み込んだ時点のバージョンを返します。存在しない場合は空のマニフェストと空のバージョンを返します。
+func readManifest(ctx context.Context, store objectstore.Store, uri string) (reviewManifest, string, error) {
+	var manifest reviewManifest
+	obj, err := store.Read(ctx, uri)
+	switch {
+	case errors.Is(err, objectstore.ErrNotFound):
+		return manifest, "", nil
+	case err != nil:
+		return manifest, "", fmt.Errorf("レビューのマニフェスト '%s' の読み込みに失敗しました: %w", uri, err)
+	}
+	if err := json.Unmarshal(obj.Data, &manifest); err != nil {
+		return manifest, "", fmt.Errorf("レビューのマニフェスト '%s' の解析に失敗しました: %w", uri, err)
+	}
+	return manifest, obj.Version, nil
+}
@@ -0,0 +1,117 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/patches"
+	"git-gemini-cli/internal/prompts"
+	"git-gemini-cli/internal/secrets"
+)
+
+const (
+	// maxPatchFindings は、1つのモードの結果で修正案を生成する指摘事項の上限です。
+	maxPatchFindings = 10
+	// maxPatchFileBytes は、修正案の生成のプロンプトに含めるファイルの内容の上限 (バイト) です。超えるファイルの修正案は生成しません。
+	maxPatchFileBytes = 100_000
+)
+
+// suggestPatches は、ファイルが特定された各指摘事項について AI に unified diff 形式の修正案を生成させ、
+// レビュー対象のブランチに適用できることを確認できた修正案をレポートに追加します。
+// 修正案で変更できるのは、差分で変更されたファイルのみです。
+func (r *DefaultReviewRunner) suggestPatches(ctx context.Context, cfg config.ReviewConfig, codeDiff, result string) string {
+	checker, ok := r.gitService.(internalAdapters.PatchChecker)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはパッチの適用確認に対応していないため、修正案を生成しません。")
+		return result
+	}
+	provider, ok := r.gitService.(internalAdapters.FileContentProvider)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはファイル内容の取得に対応していないため、修正案を生成しません。")
+		return result
+	}
+
+	var changed []string
+	for _, f := range diffutil.ParseFiles(codeDiff) {
+		if !f.Deleted && !f.Binary {
+			changed = append(changed, f.Path)
+		}
+	}
+
+	report, list := findings.Split(result)
+	_, headRef := cfg.DiffRefs()
+	var suggestions []patches.Suggestion
+	attempted := 0
+	for _, f := range list {
+		if attempted >= maxPatchFindings || interrupt.Requested(ctx) {
+			break
+		}
+		if f.File == "" || !slices.Contains(changed, f.File) {
+			continue
+		}
+
+		content, err := provider.GetFileContent(ctx, headRef, f.File)
+		if err != nil {
+			slog.Warn("変更後のファイル内容の取得に失敗したため、修正案を生成しません。", "path", f.File, "error", err)
+			continue
+		}
+		if len(content) > maxPatchFileBytes {
+			continue
+		}
+		attempted++
+
+		patch := r.generatePatch(ctx, cfg, prompts.PatchData{
+			Mode:    cfg.ReviewMode,
+			Finding: f,
+			Detail:  findings.Section(report, f),
+			Hunk:    diffutil.HunkAt(codeDiff, f.File, f.Line),
+			Content: redactText(ctx, f.File, string(content)),
+			Files:   changed,
+		})
+		if patch == "" {
+			continue
+		}
+		if paths := patches.Paths(patch); len(paths) == 0 || slices.ContainsFunc(paths, func(p string) bool { return !slices.Contains(changed, p) }) {
+			slog.Info("差分で変更されていないファイルを変更する修正案のため、除外します。", "title", f.Title, "paths", paths)
+			continue
+		}
+		if strings.Contains(patch, secrets.PlaceholderPrefix) {
+			slog.Info("マスクした値を含む修正案のため、除外します。", "title", f.Title)
+			continue
+		}
+		if err := checker.CheckPatch(ctx, headRef, patch); err != nil {
+			slog.Info("レビュー対象のブランチに適用できない修正案のため、除外します。", "title", f.Title, "error", err)
+			continue
+		}
+		suggestions = append(suggestions, patches.Suggestion{Finding: f, Patch: patch})
+	}
+
+	slog.Info("修正案を生成しました。", "mode", cfg.ReviewMode, "attempted", attempted, "applicable", len(suggestions))
+	if len(suggestions) == 0 {
+		return result
+	}
+	return strings.TrimRight(report, "\n") + "\n\n---\n\n" + patches.Section(suggestions) + "\n" + findings.Block(list)
+}
+
+// generatePatch は、1件の指摘事項に対する修正案を AI に生成させます。
+// 修正案を示せないと応答した場合や、生成に失敗した場合は空文字を返します。
+func (r *DefaultReviewRunner) generatePatch(ctx context.Context, cfg config.ReviewConfig, data prompts.PatchData) string {
+	prompt, err := r.promptBuilder.BuildPatch(data)
+	if err != nil {
+		slog.Warn("修正案のプロンプトの生成に失敗しました。", "title", data.Finding.Title, "error", err)
+		return ""
+	}
+	reply, err := r.geminiService.ReviewCodeDiff(internalAdapters.WithReviewMode(ctx, cfg.ReviewMode), prompt)
+	if err != nil {
+		slog.Warn("修正案の生成に失敗しました。", "title", data.Finding.Title, "error", err)
+		return ""
+	}
+	return patches.Extract(reply)
+}
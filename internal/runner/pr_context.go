@@ -0,0 +1,51 @@
+package runner
+
+import (
+	"context"
+	"log/slog"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+)
+
+// prMetadataKey は、context にプルリクエストのメタデータを格納するためのキーです。
+type prMetadataKey struct{}
+
+// withPRMetadata は、各モードのプロンプトで共有するプルリクエストのメタデータを設定した context を返します。
+func withPRMetadata(ctx context.Context, md *internalAdapters.PRMetadata) context.Context {
+	if md == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, prMetadataKey{}, md)
+}
+
+// prMetadataFrom は、context に設定されたプルリクエストのメタデータを返します。取得していない場合は nil を返します。
+func prMetadataFrom(ctx context.Context) *internalAdapters.PRMetadata {
+	md, _ := ctx.Value(prMetadataKey{}).(*internalAdapters.PRMetadata)
+	return md
+}
+
+// loadPRMetadata は、フィーチャーブランチのオープンなプルリクエストのタイトル・説明・関連するイシューを取得します。
+// 差分と同じく AI に送信するため、機密情報をマスクします。
+// 取得しない設定の場合や、プルリクエストが見つからない場合、取得に失敗した場合は nil を返し、変更の意図なしでレビューを続けます。
+func (r *DefaultReviewRunner) loadPRMetadata(ctx context.Context, cfg config.ReviewConfig) *internalAdapters.PRMetadata {
+	if r.prMetadata == nil {
+		return nil
+	}
+	md, err := r.prMetadata.FetchPRMetadata(ctx, cfg.FeatureBranch, cfg.BaseBranch)
+	if err != nil {
+		slog.Warn("プルリクエストのメタデータの取得に失敗したため、変更の意図なしでレビューを続けます。", "source", cfg.PRContext, "error", err)
+		return nil
+	}
+	if md == nil {
+		slog.Info("フィーチャーブランチのオープンなプルリクエストが見つからないため、変更の意図なしでレビューします。", "source", cfg.PRContext, "branch", cfg.FeatureBranch)
+		return nil
+	}
+
+	md.Description = redactText(ctx, "", md.Description)
+	for i := range md.Issues {
+		md.Issues[i].Body = redactText(ctx, "", md.Issues[i].Body)
+	}
+	slog.Info("プルリクエストのタイトル・説明を変更の意図としてプロンプトに追加します。", "pr", md.URL, "issues", len(md.Issues))
+	return md
+}
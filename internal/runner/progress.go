@@ -0,0 +1,123 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// PartialResultHandler は、レビューの途中経過 (暫定版のレポート) を受け取る関数です。
+type PartialResultHandler func(ctx context.Context, report string)
+
+// partialResultKey は、context に PartialResultHandler を格納するためのキーです。
+type partialResultKey struct{}
+
+// WithPartialResultHandler は、分割したレビューの各パートや各モードが完了するたびに、
+// それまでの結果をまとめた暫定版のレポートを handler に渡すよう設定した context を返します。
+func WithPartialResultHandler(ctx context.Context, handler PartialResultHandler) context.Context {
+	return context.WithValue(ctx, partialResultKey{}, handler)
+}
+
+// partialResultHandler は、context に設定された PartialResultHandler を返します。設定されていない場合は nil を返します。
+func partialResultHandler(ctx context.Context) PartialResultHandler {
+	h, _ := ctx.Value(partialResultKey{}).(PartialResultHandler)
+	return h
+}
+
+// DiffHandler は、レビュー対象として取得した差分を受け取る関数です。
+type DiffHandler func(diff string)
+
+// diffKey は、context に DiffHandler を格納するためのキーです。
+type diffKey struct{}
+
+// WithDiffHandler は、レビュー対象の差分を取得した時点で handler に渡すよう設定した context を返します。
+// 差分に指摘事項を埋め込んだ出力など、レビュー結果と差分を組み合わせる場合に使用します。
+func WithDiffHandler(ctx context.Context, handler DiffHandler) context.Context {
+	return context.WithValue(ctx, diffKey{}, handler)
+}
+
+// notifyDiff は、context に DiffHandler が設定されている場合に差分を渡します。
+func notifyDiff(ctx context.Context, diff string) {
+	if h, ok := ctx.Value(diffKey{}).(DiffHandler); ok {
+		h(diff)
+	}
+}
+
+// CommitHandler は、レビュー対象の比較元 (ベースブランチ) とヘッド (フィーチャーブランチ) のコミットハッシュを受け取る関数です。
+// 解決できなかった側は空文字列になります。
+type CommitHandler func(baseSHA, headSHA string)
+
+// commitKey は、context に CommitHandler を格納するためのキーです。
+type commitKey struct{}
+
+// WithCommitHandler は、フェッチ後に比較元とヘッドの参照をコミットハッシュに解決し、handler に渡すよう設定した context を返します。
+// 公開先のURIにコミットハッシュを含める場合 ({sha} / {shortsha}) や、レビューのマニフェストに記録する場合に使用します。
+// 参照をコミットハッシュに解決できない Gitアダプタでは、handler は呼び出されません。
+func WithCommitHandler(ctx context.Context, handler CommitHandler) context.Context {
+	return context.WithValue(ctx, commitKey{}, handler)
+}
+
+// notifyCommits は、context に CommitHandler が設定されている場合に、baseRef と headRef のコミットハッシュを解決して渡します。
+func notifyCommits(ctx context.Context, git adapters.GitService, baseRef, headRef string) {
+	h, ok := ctx.Value(commitKey{}).(CommitHandler)
+	if !ok {
+		return
+	}
+	resolver, ok := git.(internalAdapters.RefResolver)
+	if !ok {
+		slog.Warn("使用中のGitアダプタは参照のコミットハッシュへの解決に対応していないため、レビュー対象のコミットハッシュを取得できません。", "ref", headRef)
+		return
+	}
+	headSHA, err := resolver.ResolveRef(ctx, headRef)
+	if err != nil {
+		slog.Warn("ヘッドのコミットハッシュの取得に失敗しました。", "ref", headRef, "error", err)
+		return
+	}
+	baseSHA, err := resolver.ResolveRef(ctx, baseRef)
+	if err != nil {
+		slog.Warn("比較元のコミットハッシュの取得に失敗しました。", "ref", baseRef, "error", err)
+		baseSHA = ""
+	}
+	h(baseSHA, headSHA)
+}
+
+// commitsKey は、context にレビュー対象のコミットハッシュを格納するためのキーです。
+type commitsKey struct{}
+
+// reviewedCommits は、レビュー対象の比較元とヘッドのコミットハッシュです。
+type reviewedCommits struct {
+	base, head string
+}
+
+// WithCommits は、レビュー対象の比較元とヘッドのコミットハッシュを格納した context を返します。
+// 公開処理は、格納されたコミットハッシュをレビューのマニフェストに記録します。
+func WithCommits(ctx context.Context, baseSHA, headSHA string) context.Context {
+	return context.WithValue(ctx, commitsKey{}, reviewedCommits{base: baseSHA, head: headSHA})
+}
+
+// commitsFromContext は、context に格納されたレビュー対象のコミットハッシュを返します (WithCommits)。
+func commitsFromContext(ctx context.Context) (baseSHA, headSHA string) {
+	c, _ := ctx.Value(commitsKey{}).(reviewedCommits)
+	return c.base, c.head
+}
+
+// provisionalNotice は、暫定版のレポートの先頭に付ける、レビューが進行中であることを示す注記です。
+const provisionalNotice = "> ⏳ **暫定版**: レビューは進行中です。完了した部分の結果のみを表示しています。\n\n"
+
+// incompleteReport は、中断により途中で終了したレビューのレポートの先頭に、未完了であることを示す注記を付けます。
+// pendingModes には、実行しなかったモードを指定します。
+func incompleteReport(report string, pendingModes []string) string {
+	var b strings.Builder
+	b.WriteString("> ⚠️ **未完了**: 中断シグナルを受信したため、レビューを途中で終了しました。以下は完了した部分のみの結果です。\n")
+	if len(pendingModes) > 0 {
+		fmt.Fprintf(&b, "> 未実行のモード: `%s`\n", strings.Join(pendingModes, "`, `"))
+	}
+	b.WriteString("\n")
+	b.WriteString(report)
+	return b.String()
+}
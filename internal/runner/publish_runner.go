@@ -1,28 +1,40 @@
 package runner
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"git-gemini-cli/internal/adapters"
 	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/idempotency"
+	"git-gemini-cli/internal/objectstore"
 
 	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
 	"github.com/shouni/go-remote-io/pkg/remoteio"
 )
 
 const (
-	// signedURLExpiration は署名付きURLの有効期限を定義します。
-	signedURLExpiration = 30 * time.Minute
+	// defaultSignedURLExpiration は、cfg.SignedURLExpiration が未指定の場合の署名付きURLの有効期限です。
+	defaultSignedURLExpiration = 30 * time.Minute
 )
 
+// ErrStorageConflict は、公開先に既にオブジェクトが存在し、--on-conflict=fail が指定されていることを示すエラーです。
+var ErrStorageConflict = errors.New("公開先に既にレポートが存在します")
+
 // PublisherRunner は、レビュー結果の公開処理を実行する責務を持つインターフェースです。
 type PublisherRunner interface {
 	Run(ctx context.Context, cfg config.PublishConfig, reviewResult string) error
+	// Begin は、公開先の準備 (重複排除と衝突の解決) を行い、暫定版と最終版を公開するための Publication を返します。
+	Begin(ctx context.Context, cfg config.PublishConfig) (*Publication, error)
 }
 
 // DefaultPublisherRunner は、レビュー結果の公開処理を実行する具象構造体です。
@@ -31,28 +43,122 @@ type DefaultPublisherRunner struct {
 	writer        publisher.Publisher
 	urlSigner     remoteio.URLSigner
 	slackNotifier adapters.SlackNotifier
+	prCommenter   adapters.PRCommenter // プルリクエストへのコメントの投稿 (nil の場合は投稿しない)
+	store         objectstore.Store    // 公開先の存在確認と重複排除マーカーに使用 (nil の場合はどちらも行わない)
 }
 
 // NewDefaultPublisherRunner は DefaultPublisherRunner の新しいインスタンスを作成します。
 // DIコンテナ/builderはこの関数を利用して依存関係を構築します。
-func NewDefaultPublisherRunner(writer publisher.Publisher, urlSigner remoteio.URLSigner, slackNotifier adapters.SlackNotifier) *DefaultPublisherRunner {
+// prCommenter に nil を渡した場合、プルリクエストへのコメントの投稿は行いません。
+// store に nil を渡した場合、公開先の衝突検出と冪等キーによる重複排除は行いません。
+func NewDefaultPublisherRunner(writer publisher.Publisher, urlSigner remoteio.URLSigner, slackNotifier adapters.SlackNotifier, prCommenter adapters.PRCommenter, store objectstore.Store) *DefaultPublisherRunner {
 	return &DefaultPublisherRunner{
 		writer:        writer,
 		urlSigner:     urlSigner,
 		slackNotifier: slackNotifier,
+		prCommenter:   prCommenter,
+		store:         store,
 	}
 }
 
 // Run は公開処理のパイプライン全体を実行します。
 // このメソッドは、処理のオーケストレーションに専念します。
 func (p *DefaultPublisherRunner) Run(ctx context.Context, cfg config.PublishConfig, reviewResult string) error {
-	// 1. ストレージへのアップロード処理
-	if err := p.publishToStorage(ctx, cfg, reviewResult); err != nil {
+	pub, err := p.Begin(ctx, cfg)
+	if err != nil {
 		return err
 	}
+	return pub.Complete(ctx, reviewResult)
+}
+
+// Publication は、1回の公開処理の状態です。
+// レビューの完了前に暫定版を同じキーへ繰り返し公開し、最後に最終版を公開して通知するために使用します。
+type Publication struct {
+	runner *DefaultPublisherRunner
+	cfg    config.PublishConfig
+	guard  *idempotency.Guard
+	upload bool // この実行でアップロードを行うか (公開済みの再実行や古い実行では false)
+	// provisional は、暫定版を公開したかです。公開先の内容が前回の公開から変わっているため、内容の比較による省略を行いません。
+	provisional bool
+}
+
+// Begin は、冪等キーによる重複排除と、既存オブジェクトとの衝突の解決 (--on-conflict) を行い、
+// 実際に公開するURIを確定した Publication を返します。
+func (p *DefaultPublisherRunner) Begin(ctx context.Context, cfg config.PublishConfig) (*Publication, error) {
+	// 冪等キーによる重複排除 (CIの再実行による二重通知や、古い実行による上書きを防ぐ)
+	guard := p.acquireGuard(ctx, cfg)
+	pub := &Publication{runner: p, cfg: cfg, guard: guard, upload: guard == nil || guard.ShouldPublish()}
+
+	if pub.upload {
+		uri, err := p.resolveConflict(ctx, cfg.StorageURI, cfg.OnConflict)
+		if err != nil {
+			return nil, err
+		}
+		pub.cfg.StorageURI = uri
+	} else if uri := guard.PublishedURI(); uri != "" {
+		// 再実行時は、以前の実行が実際に公開したURI (バージョン付きの場合を含む) を通知に使用する
+		pub.cfg.StorageURI = uri
+	}
+	return pub, nil
+}
+
+// URI は、実際に公開するURIを返します。
+func (pub *Publication) URI() string {
+	return pub.cfg.StorageURI
+}
+
+// PublishProvisional は、レビューの途中経過を暫定版として最終版と同じキーに公開します。通知は行いません。
+func (pub *Publication) PublishProvisional(ctx context.Context, report string) error {
+	if !pub.upload {
+		return nil
+	}
+	pub.provisional = true
+	return pub.runner.publishToStorage(ctx, pub.cfg, report)
+}
+
+// Complete は、最終版のレポートを公開し、Slack に通知します。
+func (pub *Publication) Complete(ctx context.Context, reviewResult string) error {
+	p, cfg, guard := pub.runner, pub.cfg, pub.guard
+
+	// 内容が前回の公開と同じ場合は、CIのジョブの再実行などによる重複したアップロードと通知を省略する
+	var hash string
+	if pub.upload && guard != nil {
+		hash = contentHash(ctx, cfg, reviewResult)
+		if !pub.provisional && p.unchanged(ctx, guard, hash) {
+			return nil
+		}
+	}
+
+	// 1. ストレージへのアップロード処理
+	if pub.upload {
+		if err := p.publishToStorage(ctx, cfg, reviewResult); err != nil {
+			return err
+		}
+		if cfg.JSONSidecar {
+			p.publishSidecar(ctx, cfg, reviewResult)
+		}
+		var pruned []string
+		if cfg.RetentionAge > 0 || cfg.RetentionCount > 0 {
+			pruned = p.applyRetention(ctx, cfg)
+		}
+		if cfg.UpdateIndex {
+			p.updateIndex(ctx, cfg, pruned)
+		}
+		if cfg.Manifest {
+			p.updateManifest(ctx, cfg, pruned)
+		}
+		if guard != nil {
+			if err := guard.MarkPublished(ctx, cfg.StorageURI, hash); err != nil {
+				slog.Warn("重複排除マーカーへの公開済みの記録に失敗しました。", "error", err)
+			}
+		}
+	}
+	if cfg.SkipNotify || (guard != nil && !guard.ShouldNotify()) {
+		return nil
+	}
 
 	// 2. 公開URLの生成 (Slack通知の前に行う)
-	publicURL, err := p.getPublicURL(ctx, cfg.StorageURI)
+	publicURL, err := p.getPublicURL(ctx, cfg)
 	if err != nil {
 		// URL署名/変換が失敗しても処理は続行可能だが、エラーを記録
 		slog.Warn("公開URLの生成に失敗しました。署名なし/静的URIで通知を試みます。", "error", err, "uri", cfg.StorageURI)
@@ -61,7 +167,18 @@ func (p *DefaultPublisherRunner) Run(ctx context.Context, cfg config.PublishConf
 	}
 
 	// 3. Slack通知処理 (アップロード成功後、publicURLを使って実行)
-	p.notifyToSlack(ctx, publicURL, cfg)
+	notified := p.notifyToSlack(ctx, publicURL, cfg)
+
+	// 4. プルリクエストへのコメントの投稿 (以前のコメントを更新するため、Slack通知とは別に再実行しても重複しない)
+	if p.prCommenter != nil {
+		p.commentOnPR(ctx, publicURL, cfg, reviewResult)
+	}
+
+	if notified && guard != nil {
+		if err := guard.MarkNotified(ctx); err != nil {
+			slog.Warn("重複排除マーカーへの通知済みの記録に失敗しました。", "error", err)
+		}
+	}
 
 	return nil
 }
@@ -79,16 +196,171 @@ func (p *DefaultPublisherRunner) publishToStorage(ctx context.Context, cfg confi
 	return nil
 }
 
-// notifyToSlack はSlackに通知を送信します。
-func (p *DefaultPublisherRunner) notifyToSlack(ctx context.Context, publicURL string, cfg config.PublishConfig) {
+// contentHash は、公開するレポートの内容のハッシュを返します。
+// レポート本文に加えて、変換結果に影響する設定 (形式・テンプレート・埋め込む差分・オブジェクトの属性・圧縮) を含めます。
+func contentHash(ctx context.Context, cfg config.PublishConfig, reviewResult string) string {
+	diff, _ := diffutil.FromContext(ctx)
+	baseRef, headRef := cfg.ReviewConfig.DiffRefs()
+	return idempotency.ContentHash(reviewResult, cfg.ReviewConfig.RepoURL, baseRef, headRef, cfg.Format, cfg.HTMLTemplate, diff,
+		cfg.ContentType, cfg.CacheControl, cfg.Compression, fmt.Sprint(cfg.ObjectMetadata))
+}
+
+// unchanged は、前回公開したレポートと内容のハッシュが一致し、そのレポートが公開先に残っている場合に true を返し、省略したことをマーカーに記録します。
+func (p *DefaultPublisherRunner) unchanged(ctx context.Context, guard *idempotency.Guard, hash string) bool {
+	uri, ok := guard.Unchanged(hash)
+	if !ok {
+		return false
+	}
+	exists, err := p.store.Exists(ctx, uri)
+	if err != nil || !exists {
+		slog.Debug("前回公開したレポートを確認できないため、内容が同じでも公開します。", "uri", uri, "error", err)
+		return false
+	}
+	slog.Info("前回公開したレポートと内容が同じため、アップロードと通知をスキップします。", "uri", uri)
+	if err := guard.MarkUnchanged(ctx); err != nil {
+		slog.Warn("重複排除マーカーへの記録に失敗しました。", "error", err)
+	}
+	return true
+}
+
+// publishSidecar は、レポートと同じ場所に機械可読な JSON サイドカー (指摘事項・メタデータ・使用量) を保存します。
+// サイドカーは二次的な成果物のため、保存に失敗してもエラーを記録して公開処理を続行します。
+func (p *DefaultPublisherRunner) publishSidecar(ctx context.Context, cfg config.PublishConfig, reviewResult string) {
+	uri := sidecarURI(cfg.StorageURI)
+	if p.store == nil {
+		slog.Warn("公開先のストレージを直接操作できないため、JSON サイドカーを保存できません。", "uri", uri)
+		return
+	}
+	data, err := buildSidecar(ctx, cfg, reviewResult)
+	if err == nil {
+		err = objectstore.WithAttributes(p.store, objectstore.Attributes{ContentEncoding: cfg.Compression}).Write(ctx, uri, data, "application/json")
+	}
+	if err != nil {
+		slog.Error("JSON サイドカーの保存に失敗しましたが、レポートの公開は成功しているため処理を続行します。", "uri", uri, "error", err)
+		return
+	}
+	slog.Info("JSON サイドカーを保存しました。", "uri", uri)
+}
+
+// notifyToSlack はSlackに通知を送信し、成功したかどうかを返します。
+func (p *DefaultPublisherRunner) notifyToSlack(ctx context.Context, publicURL string, cfg config.PublishConfig) bool {
 	if err := p.slackNotifier.Notify(ctx, publicURL, cfg.StorageURI, cfg.ReviewConfig); err != nil {
 		// 🚨 ポリシー: Slack通知は二次的な機能であるため、アップロード成功後はエラーを返さない。
 		slog.Error("Slack通知の実行中にエラーが発生しましたが、アップロードは成功しているため処理を続行します。", "error", err)
+		notifyDeliveryFailure(ctx, err)
+		return false
+	}
+	return true
+}
+
+// commentOnPR は、レビュー結果をプルリクエストのコメントとして投稿します。
+// レビューしたコミットハッシュを記録している場合は、チェックランなどの投稿先が使用できるよう context に格納します。
+func (p *DefaultPublisherRunner) commentOnPR(ctx context.Context, publicURL string, cfg config.PublishConfig, reviewResult string) {
+	_, headSHA := commitsFromContext(ctx)
+	ctx = adapters.WithHeadCommit(ctx, headSHA)
+	if err := p.prCommenter.Comment(ctx, publicURL, reviewResult, cfg.ReviewConfig); err != nil {
+		// Slack通知と同様に、プルリクエストへのコメントは二次的な機能であるため、アップロード成功後はエラーを返さない。
+		slog.Error("プルリクエストへのコメントの投稿に失敗しましたが、アップロードは成功しているため処理を続行します。", "error", err)
+		notifyDeliveryFailure(ctx, err)
 	}
 }
 
+// DeliveryFailureHandler は、アップロード後の Slack 通知やプルリクエストへのコメントの投稿に失敗した場合に、そのエラーを受け取る関数です。
+// これらの失敗は公開処理のエラーとしては返さないため、配信の成否で処理を変える呼び出し元が使用します。
+type DeliveryFailureHandler func(err error)
+
+// deliveryFailureKey は、context に DeliveryFailureHandler を格納するためのキーです。
+type deliveryFailureKey struct{}
+
+// WithDeliveryFailureHandler は、Slack 通知やプルリクエストへのコメントの投稿に失敗した場合に handler を呼び出すよう設定した context を返します。
+func WithDeliveryFailureHandler(ctx context.Context, handler DeliveryFailureHandler) context.Context {
+	return context.WithValue(ctx, deliveryFailureKey{}, handler)
+}
+
+// notifyDeliveryFailure は、context に DeliveryFailureHandler が設定されている場合に err を渡します。
+func notifyDeliveryFailure(ctx context.Context, err error) {
+	if h, ok := ctx.Value(deliveryFailureKey{}).(DeliveryFailureHandler); ok {
+		h(err)
+	}
+}
+
+// acquireGuard は、公開先の重複排除マーカーを取得します。
+// 重複排除が無効な場合や、マーカーを読み書きできない場合 (権限不足など) は nil を返し、従来どおり公開と通知を行います。
+func (p *DefaultPublisherRunner) acquireGuard(ctx context.Context, cfg config.PublishConfig) *idempotency.Guard {
+	if p.store == nil || cfg.DisableIdempotency {
+		return nil
+	}
+
+	baseRef, headRef := cfg.ReviewConfig.DiffRefs()
+	run := idempotency.NewRun(cfg.IdempotencyKey,
+		cfg.ReviewConfig.RepoURL, baseRef, headRef, cfg.ReviewConfig.ReviewMode, cfg.ReviewConfig.Model, cfg.StorageURI)
+
+	guard, err := idempotency.Acquire(ctx, p.store, cfg.StorageURI, run)
+	if err != nil {
+		slog.Warn("重複排除マーカーを利用できないため、重複排除を行わずに公開します。", "error", err)
+		return nil
+	}
+	slog.Debug("重複排除マーカーを取得しました。", "uri", idempotency.MarkerURI(cfg.StorageURI), "key", run.Key, "sequence", run.Sequence)
+	return guard
+}
+
+// maxVersions は、--on-conflict=version で試行するバージョン番号の上限です。
+const maxVersions = 1000
+
+// resolveConflict は、公開先に既にオブジェクトが存在する場合の動作 (--on-conflict) に従い、実際にアップロードするURIを返します。
+// overwrite の場合は存在確認を行わずにそのまま上書きします。
+func (p *DefaultPublisherRunner) resolveConflict(ctx context.Context, storageURI, policy string) (string, error) {
+	if policy == "" || policy == config.ConflictOverwrite {
+		return storageURI, nil
+	}
+	if p.store == nil {
+		return "", fmt.Errorf("公開先 '%s' では既存オブジェクトの確認ができないため、--on-conflict=%s は使用できません", storageURI, policy)
+	}
+
+	exists, err := p.store.Exists(ctx, storageURI)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return storageURI, nil
+	}
+
+	if policy == config.ConflictFail {
+		return "", fmt.Errorf("%w: %s", ErrStorageConflict, storageURI)
+	}
+
+	for n := 2; n <= maxVersions; n++ {
+		candidate := versionedURI(storageURI, n)
+		exists, err := p.store.Exists(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			slog.Info("公開先に既存のレポートがあるため、バージョン付きのURIに保存します。", "uri", storageURI, "versionedURI", candidate)
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("%w: バージョン %d まで使用済みです (%s)", ErrStorageConflict, maxVersions, storageURI)
+}
+
+// versionedURI は、URIの拡張子の前にバージョン番号を付加します (例: report.html → report-v2.html)。
+func versionedURI(uri string, n int) string {
+	dir, name := path.Split(uri)
+	ext := path.Ext(name)
+	return fmt.Sprintf("%s%s-v%d%s", dir, strings.TrimSuffix(name, ext), n, ext)
+}
+
 // getPublicURL は URI に応じて署名付きURLを生成するか、公開URLに変換します。
-func (p *DefaultPublisherRunner) getPublicURL(ctx context.Context, storageURI string) (string, error) {
+// cfg.PublicURL が true の場合は、公開バケットを前提として署名せずに公開URLに変換します。
+func (p *DefaultPublisherRunner) getPublicURL(ctx context.Context, cfg config.PublishConfig) (string, error) {
+	storageURI := cfg.StorageURI
+	signedURLExpiration := cmp.Or(cfg.SignedURLExpiration, defaultSignedURLExpiration)
+	if cfg.PublicURL {
+		if publicURL, ok := p.unsignedPublicURL(storageURI); ok {
+			slog.Info("署名せずに公開URLに変換しました。", "url", publicURL)
+			return publicURL, nil
+		}
+	}
 	if p.urlSigner == nil {
 		// urlSignerがnilの場合、URIは署名が必要ないか、サポートされていないスキームです。
 		slog.Debug("URL Signerがnilです。静的なURI変換のみを試みます。", "uri", storageURI)
@@ -108,6 +380,16 @@ func (p *DefaultPublisherRunner) getPublicURL(ctx context.Context, storageURI st
 		return signedURL, nil
 	}
 
+	// S3 互換ストレージ (MinIO, R2 など) の場合: バケットは既定で非公開のため、エンドポイントの署名付きURLを生成
+	if remoteio.IsS3URI(storageURI) && objectstore.S3Endpoint() != "" && p.urlSigner != nil {
+		signedURL, err := p.urlSigner.GenerateSignedURL(ctx, storageURI, "GET", signedURLExpiration)
+		if err != nil {
+			return "", fmt.Errorf("S3 互換ストレージの署名付きURLの生成に失敗しました: %w", err)
+		}
+		slog.Info("S3 互換ストレージの署名付きURLの生成に成功", "endpoint", objectstore.S3Endpoint())
+		return signedURL, nil
+	}
+
 	// S3の場合: 静的な公開URL形式に変換
 	if remoteio.IsS3URI(storageURI) {
 		awsRegion := os.Getenv("AWS_REGION")
@@ -119,11 +401,66 @@ func (p *DefaultPublisherRunner) getPublicURL(ctx context.Context, storageURI st
 		return publicURL, nil
 	}
 
-	// その他: 署名や変換が不要なURI (例: ローカルファイル、未サポートのプロバイダ)
+	// Azure Blob Storage の場合: SAS トークン付きの署名付きURLを生成
+	if objectstore.IsAzureURI(storageURI) {
+		if p.urlSigner == nil {
+			return "", fmt.Errorf("Azure Blob Storage の URIが指定されましたが、URL Signerがnilです。")
+		}
+
+		signedURL, err := p.urlSigner.GenerateSignedURL(ctx, storageURI, "GET", signedURLExpiration)
+		if err != nil {
+			return "", fmt.Errorf("Azure 署名付きURLの生成に失敗しました: %w", err)
+		}
+		slog.Info("Azure 署名付きURLの生成に成功")
+		return signedURL, nil
+	}
+
+	// GitHub Gist の場合: 公開時に作成した Gist の URL を使用
+	if adapters.IsGistURI(storageURI) {
+		if p.urlSigner == nil {
+			return "", fmt.Errorf("Gist の URIが指定されましたが、URL Signerがnilです。")
+		}
+		return p.urlSigner.GenerateSignedURL(ctx, storageURI, "GET", signedURLExpiration)
+	}
+
+	// ローカルファイルの場合: 通知から開けるよう、絶対パスの file:// URL に変換
+	if objectstore.IsLocal(storageURI) {
+		absPath, err := filepath.Abs(objectstore.LocalPath(storageURI))
+		if err != nil {
+			return "", fmt.Errorf("ローカルファイルの絶対パスの取得に失敗しました: %w", err)
+		}
+		return "file://" + filepath.ToSlash(absPath), nil
+	}
+
+	// その他: 署名や変換が不要なURI (例: 未サポートのプロバイダ)
 	slog.Debug("静的な公開URL変換や署名が不要なURIです。", "uri", storageURI)
 	return storageURI, nil
 }
 
+// unsignedPublicURL は、公開バケットを前提として、GCS・Azure Blob Storage・S3 互換ストレージのURIを署名なしの公開URLに変換します。
+// Amazon S3 とローカルファイルは通常の変換 (getPublicURL) でも署名しないため、変換の対象外として false を返します。
+func (p *DefaultPublisherRunner) unsignedPublicURL(storageURI string) (string, bool) {
+	switch {
+	case remoteio.IsS3URI(storageURI) && objectstore.S3Endpoint() != "":
+		if publicURL, err := objectstore.S3EndpointURL(storageURI); err == nil {
+			return publicURL, true
+		}
+	case remoteio.IsGCSURI(storageURI):
+		return "https://storage.googleapis.com/" + strings.TrimPrefix(storageURI, "gs://"), true
+	case objectstore.IsAzureURI(storageURI):
+		if strings.HasPrefix(storageURI, "https://") {
+			return storageURI, true
+		}
+		// az:// の場合は、認証情報のストレージアカウントから Blob の URL を求める
+		if store, ok := p.urlSigner.(*objectstore.AzureStore); ok {
+			if publicURL, err := store.PublicURL(storageURI); err == nil {
+				return publicURL, true
+			}
+		}
+	}
+	return "", false
+}
+
 // convertS3URIToPublicURL は S3 URI を AWS の公開 Virtual-Hosted Style アクセス URL に変換します。
 // 形式: https://{bucketName}.s3.{region}.amazonaws.com/{objectKey}
 func convertS3URIToPublicURL(s3URI, region string) string {
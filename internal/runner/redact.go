@@ -0,0 +1,125 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/secrets"
+)
+
+// maxReportRedactions は、レポートに列挙するマスクした機密情報の上限です。
+const maxReportRedactions = 50
+
+// redactorKey は、context に機密情報のマスクに使用する Redactor を格納するためのキーです。
+type redactorKey struct{}
+
+// withRedactor は、差分以外のコンテキスト (ファイル全体、インポート先の宣言) のマスクにも
+// 差分と同じ Redactor を使用するよう設定した context を返します。
+func withRedactor(ctx context.Context, redactor *secrets.Redactor) context.Context {
+	if redactor == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, redactorKey{}, redactor)
+}
+
+// redactText は、context に Redactor が設定されている場合に、テキストに含まれる機密情報をマスクします。
+func redactText(ctx context.Context, path, text string) string {
+	redactor, ok := ctx.Value(redactorKey{}).(*secrets.Redactor)
+	if !ok {
+		return text
+	}
+	return redactor.Text(path, text)
+}
+
+// hasRedactions は、context に設定された Redactor が1件以上の機密情報をマスクしたかを返します。
+func hasRedactions(ctx context.Context) bool {
+	redactor, ok := ctx.Value(redactorKey{}).(*secrets.Redactor)
+	return ok && len(redactor.Redactions()) > 0
+}
+
+// redactDiff は、AI に送信する前に差分に含まれる機密情報 (APIキー、秘密鍵、JWT、接続文字列のパスワードなど) と、
+// 指定された場合は個人情報・利用者が指定したパターンに一致する値をマスクします。
+// マスクするルールが1つもない場合は、差分をそのまま返します。
+func redactDiff(cfg config.ReviewConfig, codeDiff string) (string, *secrets.Redactor, error) {
+	redactor, err := newRedactor(cfg)
+	if err != nil || redactor == nil {
+		return codeDiff, nil, err
+	}
+	redacted := redactor.Diff(codeDiff)
+	if n := len(redactor.Redactions()); n > 0 {
+		slog.Warn("差分に含まれる機密情報をマスクしました。", "count", n)
+	}
+	return redacted, redactor, nil
+}
+
+// newRedactor は、設定に基づいて機密情報・個人情報・利用者が指定したパターンをマスクする Redactor を生成します。
+// マスクするルールが1つもない場合は nil を返します。
+func newRedactor(cfg config.ReviewConfig) (*secrets.Redactor, error) {
+	var patterns []*regexp.Regexp
+	if cfg.RedactPatternsFile != "" {
+		var err error
+		if patterns, err = secrets.LoadPatterns(cfg.RedactPatternsFile); err != nil {
+			return nil, err
+		}
+	}
+	redactor := secrets.New(
+		secrets.WithBuiltinRules(!cfg.DisableRedaction),
+		secrets.WithPII(cfg.RedactPII),
+		secrets.WithPatterns(patterns),
+	)
+	if redactor.Empty() {
+		return nil, nil
+	}
+	return redactor, nil
+}
+
+// logRedactionAudit は、差分とコンテキストでマスクした値の件数を、監査用にファイルごと・種類ごとにログに記録します。
+// 値そのものは記録しません。
+func logRedactionAudit(redactor *secrets.Redactor) {
+	if redactor == nil {
+		return
+	}
+	for _, s := range redactor.Summary() {
+		kinds := make([]string, 0, len(s.Counts))
+		for kind, n := range s.Counts {
+			kinds = append(kinds, fmt.Sprintf("%s=%d", kind, n))
+		}
+		sort.Strings(kinds)
+		slog.Info("マスクした値の件数", "audit", "redaction", "path", s.Path, "total", s.Total, "kinds", strings.Join(kinds, ","))
+	}
+}
+
+// appendRedactionReport は、レポートの末尾にマスクした機密情報の一覧のセクションを追加します。
+// 値そのものは含めず、種類・位置・プレースホルダのみを列挙します。
+func appendRedactionReport(report string, redactor *secrets.Redactor) string {
+	if redactor == nil || len(redactor.Redactions()) == 0 {
+		return report
+	}
+	redactions := redactor.Redactions()
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	b.WriteString("\n\n---\n\n## 🔒 マスクした機密情報・個人情報\n\n")
+	fmt.Fprintf(&b, "AI に送信する前に、以下の %d 件の値をプレースホルダに置き換えました。差分に含まれる場合は、値が漏洩していないか確認してください。\n\n", len(redactions))
+	b.WriteString("| 種類 | 場所 | プレースホルダ |\n| :--- | :--- | :--- |\n")
+	shown, omitted := redactions, 0
+	if len(shown) > maxReportRedactions {
+		shown, omitted = shown[:maxReportRedactions], len(shown)-maxReportRedactions
+	}
+	for _, rd := range shown {
+		location := rd.Path
+		if rd.Line > 0 {
+			location = fmt.Sprintf("%s:%d", rd.Path, rd.Line)
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", rd.Kind, location, rd.Placeholder)
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&b, "\nほか %d 件\n", omitted)
+	}
+	return b.String()
+}
@@ -0,0 +1,165 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/idempotency"
+	"git-gemini-cli/internal/objectstore"
+)
+
+// applyRetention は、公開先のプレフィックスにある過去のレポートのうち、保持期間 (cfg.RetentionAge) を過ぎたもの、
+// または新しい順に保持件数 (cfg.RetentionCount) を超えたものを削除し、削除したレポートのファイル名を返します。
+// 対象は、公開したレポートと同じ階層にある同じ拡張子のファイル (公開したレポートとレビューの一覧を除く) のうち、
+// このツールが公開したことを確認できるもの (重複排除マーカーがあるもの、またはレビューの一覧・マニフェストに記録されているもの) です。
+// 翻訳版 (cfg.Languages。例: result.en.html) は元のレポートと合わせて1件として数え、JSON サイドカーと重複排除マーカーとともに併せて削除します。
+// 削除は二次的な処理のため、失敗してもエラーを記録して公開処理を続行します。
+func (p *DefaultPublisherRunner) applyRetention(ctx context.Context, cfg config.PublishConfig) []string {
+	lister, ok := p.store.(objectstore.Lister)
+	if !ok {
+		slog.Warn("保持期間を適用できないため、古いレポートを削除しません。", "uri", cfg.StorageURI, "error", objectstore.ErrListUnsupported)
+		return nil
+	}
+	prefix, current := splitReportURI(cfg.StorageURI)
+	ext := path.Ext(current)
+	if ext == "" {
+		slog.Warn("レポートのURIに拡張子がないため、保持期間を適用しません (削除の対象を特定できないため)。", "uri", cfg.StorageURI)
+		return nil
+	}
+
+	objects, err := lister.List(ctx, prefix)
+	if err != nil {
+		slog.Error("古いレポートの一覧取得に失敗したため、保持期間を適用しません。", "prefix", prefix, "error", err)
+		return nil
+	}
+	existing := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		existing[strings.TrimPrefix(obj.URI, prefix)] = true
+	}
+	// 翻訳版は元のレポートの一部として扱う (今回公開した版の翻訳版も、今回のレポートとして削除の対象から外れる)
+	languages := cfg.Languages[min(len(cfg.Languages), 1):]
+	variant := make(map[string]bool)
+	for name := range existing {
+		for _, lang := range languages {
+			variant[LanguageURI(name, lang)] = true
+		}
+	}
+	recorded := p.recordedReports(ctx, prefix)
+
+	var reports []objectstore.ObjectInfo
+	unrecorded := 0
+	for _, obj := range objects {
+		name := strings.TrimPrefix(obj.URI, prefix)
+		if strings.Contains(name, "/") || path.Ext(name) != ext || name == current || variant[name] || name == indexHTMLName || name == indexJSONName || name == manifestName {
+			continue
+		}
+		// 公開中の一時的なオブジェクト (--atomic-publish の .tmp-*) などの隠しファイルはレポートとして扱わない
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		// このツールが公開していないファイル (手動でアップロードしたものなど) は削除しない
+		if !recorded[name] && !existing[idempotency.MarkerURI(name)] {
+			unrecorded++
+			continue
+		}
+		reports = append(reports, obj)
+	}
+	if unrecorded > 0 {
+		slog.Debug("このツールが公開したことを確認できないファイルは、保持期間の対象外とします。", "prefix", prefix, "files", unrecorded)
+	}
+	expired := expiredReports(reports, cfg.RetentionAge, cfg.RetentionCount, time.Now())
+
+	var pruned []string
+	for _, obj := range expired {
+		name := strings.TrimPrefix(obj.URI, prefix)
+		if err := lister.Delete(ctx, obj.URI); err != nil {
+			slog.Error("古いレポートの削除に失敗しました。", "uri", obj.URI, "error", err)
+			continue
+		}
+		pruned = append(pruned, name)
+		companions := reportCompanions(name)
+		for _, lang := range languages {
+			if v := LanguageURI(name, lang); existing[v] {
+				companions = append(companions, v)
+				companions = append(companions, reportCompanions(v)...)
+			}
+		}
+		for _, companion := range companions {
+			if companion == name || !existing[companion] {
+				continue
+			}
+			if err := lister.Delete(ctx, prefix+companion); err != nil {
+				slog.Warn("古いレポートの関連ファイルの削除に失敗しました。", "uri", prefix+companion, "error", err)
+			}
+		}
+	}
+	if len(pruned) > 0 {
+		slog.Info("保持期間を過ぎた古いレポートを削除しました。", "prefix", prefix, "deleted", len(pruned))
+	}
+	return pruned
+}
+
+// reportCompanions は、レポートのファイル名 name とともに削除する関連ファイル (JSON サイドカーと重複排除マーカー) のファイル名を返します。
+func reportCompanions(name string) []string {
+	return []string{sidecarURI(name), idempotency.MarkerURI(name)}
+}
+
+// recordedReports は、公開先のプレフィックスのレビューの一覧 (index.json) とマニフェスト (manifest.json) に記録されているレポートのファイル名を返します。
+// 読み込めないファイルは無視します (重複排除マーカーのみで判定します)。
+func (p *DefaultPublisherRunner) recordedReports(ctx context.Context, prefix string) map[string]bool {
+	recorded := make(map[string]bool)
+	if obj, err := p.store.Read(ctx, prefix+indexJSONName); err == nil {
+		var index reviewIndex
+		if err := json.Unmarshal(obj.Data, &index); err == nil {
+			for _, e := range index.Entries {
+				recorded[e.Report] = true
+			}
+		}
+	} else if !errors.Is(err, objectstore.ErrNotFound) {
+		slog.Debug("レビューの一覧を読み込めないため、保持期間の対象の判定に使用しません。", "uri", prefix+indexJSONName, "error", err)
+	}
+	if obj, err := p.store.Read(ctx, prefix+manifestName); err == nil {
+		var manifest reviewManifest
+		if err := json.Unmarshal(obj.Data, &manifest); err == nil {
+			for _, e := range manifest.Reviews {
+				recorded[e.Report] = true
+			}
+		}
+	} else if !errors.Is(err, objectstore.ErrNotFound) {
+		slog.Debug("レビューのマニフェストを読み込めないため、保持期間の対象の判定に使用しません。", "uri", prefix+manifestName, "error", err)
+	}
+	return recorded
+}
+
+// LanguageURI は、公開先のURIの拡張子の前に言語コードを付加した翻訳版のURIを返します (例: result.html → result.en.html)。
+func LanguageURI(uri, language string) string {
+	dir, name := path.Split(uri)
+	ext := path.Ext(name)
+	return fmt.Sprintf("%s%s.%s%s", dir, strings.TrimSuffix(name, ext), language, ext)
+}
+
+// expiredReports は、reports のうち最終更新日時が maxAge より古いもの、または新しい順に並べて
+// 公開したレポートを含めて maxCount 件を超えるものを返します。0 の条件は適用しません。
+func expiredReports(reports []objectstore.ObjectInfo, maxAge time.Duration, maxCount int, now time.Time) []objectstore.ObjectInfo {
+	sorted := append([]objectstore.ObjectInfo(nil), reports...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Updated.After(sorted[j].Updated) })
+
+	var expired []objectstore.ObjectInfo
+	for i, obj := range sorted {
+		// 公開したレポートが1件目となるため、過去のレポートは maxCount-1 件まで保持する
+		overCount := maxCount > 0 && i >= maxCount-1
+		tooOld := maxAge > 0 && obj.Updated.Before(now.Add(-maxAge))
+		if overCount || tooOld {
+			expired = append(expired, obj)
+		}
+	}
+	return expired
+}
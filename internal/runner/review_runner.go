@@ -2,13 +2,22 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"git-gemini-cli/internal/config"
 	"log/slog"
 	"strings"
+	"sync"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/diffcache"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/lockfile"
+	"git-gemini-cli/internal/prompts"
+	"git-gemini-cli/internal/usage"
 
 	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
-	"github.com/shouni/gemini-reviewer-core/pkg/prompts"
 )
 
 // ReviewRunner は、コードレビューのビジネスロジックを実行し、レビュー結果を生成するインターフェースです。
@@ -21,7 +30,10 @@ type ReviewRunner interface {
 type DefaultReviewRunner struct {
 	gitService    adapters.GitService
 	geminiService adapters.CodeReviewAI
-	promptBuilder prompts.ReviewPromptBuilder
+	promptBuilder *prompts.Builder
+	tokenCounter  internalAdapters.TokenCounter      // nil の場合はトークン数を概算する
+	diffCache     *diffcache.Cache                   // nil の場合は差分をキャッシュしない
+	prMetadata    internalAdapters.PRMetadataFetcher // nil の場合はプルリクエストのメタデータをプロンプトに追加しない
 }
 
 // NewDefaultReviewRunner は DefaultReviewRunner の新しいインスタンスを生成します。
@@ -29,24 +41,41 @@ type DefaultReviewRunner struct {
 func NewDefaultReviewRunner(
 	git adapters.GitService,
 	gemini adapters.CodeReviewAI,
-	pb prompts.ReviewPromptBuilder,
+	pb *prompts.Builder,
+	counter internalAdapters.TokenCounter,
+	cache *diffcache.Cache,
+	prMetadata internalAdapters.PRMetadataFetcher,
 ) *DefaultReviewRunner {
 	return &DefaultReviewRunner{
 		gitService:    git,
 		geminiService: gemini,
 		promptBuilder: pb,
+		tokenCounter:  counter,
+		diffCache:     cache,
+		prMetadata:    prMetadata,
 	}
 }
 
 // Run はGit Diffを取得し、Gemini AIでレビューを実行します。
+// 中断シグナルを受信した場合は、完了した部分の結果を未完了である旨の注記付きで返し、interrupt.ErrInterrupted を返します。
 func (r *DefaultReviewRunner) Run(
 	ctx context.Context,
 	cfg config.ReviewConfig,
 ) (string, error) {
 
+	// AI の呼び出しごとの使用量を記録し、レポートの末尾に追加する
+	ctx, rec := usage.NewContext(ctx)
+
+	// 同じローカルパスに対する並行実行 (Cleanup/checkout の競合) を防ぐ
+	lock, err := acquireRepoLock(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	defer releaseRepoLock(lock)
+
 	slog.Info("Gitリポジトリのセットアップと差分取得を開始します。")
 	// Gitリポジトリのクローンまたは更新
-	err := r.gitService.CloneOrUpdate(ctx, cfg.RepoURL)
+	err = r.gitService.CloneOrUpdate(ctx, cfg.RepoURL)
 	if err != nil {
 		return "", fmt.Errorf("リポジトリのセットアップに失敗しました: %w", err)
 	}
@@ -58,38 +87,395 @@ func (r *DefaultReviewRunner) Run(
 		}
 	}()
 
-	// リモートから最新の変更をフェッチ
-	if err := r.gitService.Fetch(ctx); err != nil {
-		return "", fmt.Errorf("最新の変更のフェッチに失敗しました: %w", err)
+	// リモートから最新の変更をフェッチ (同じプロセス内でフェッチ済みの場合は省略)
+	if err := fetchRepo(ctx, r.gitService, r.diffCache, cfg); err != nil {
+		return "", err
+	}
+
+	// --incremental の場合は、前回レビューしたコミットを差分の起点にする
+	baseRef, headRef := cfg.DiffRefs()
+	notifyCommits(ctx, r.gitService, baseRef, headRef)
+	inc := r.prepareIncremental(ctx, cfg, baseRef, headRef)
+	if inc != nil && inc.upToDate {
+		slog.Info("前回のレビュー以降に新しいコミットがないため、レビューをスキップします。", "head", inc.headSHA)
+		return "", nil
+	}
+	if inc != nil && inc.sinceSHA != "" {
+		baseRef = inc.sinceSHA
 	}
 
-	// コード差分を取得
-	codeDiff, err := r.gitService.GetCodeDiff(ctx, cfg.BaseBranch, cfg.FeatureBranch)
+	// コード差分を取得 (同じプロセス内で取得済みの場合は再利用)
+	codeDiff, err := loadDiff(ctx, r.gitService, r.diffCache, cfg.RepoURL, baseRef, headRef)
 	if err != nil {
-		return "", fmt.Errorf("コード差分の取得に失敗しました: %w", err)
+		return "", err
 	}
 
 	if strings.TrimSpace(codeDiff) == "" {
+		if inc != nil {
+			inc.commit(ctx, cfg, "")
+		}
 		return "", nil
 	}
 	slog.Info("Git差分の取得に成功しました。", "size_bytes", len(codeDiff))
 
-	// プロンプトの生成
+	// AI に送信する前に機密情報をマスクする (ファイル全体などのコンテキストにも同じ Redactor を使用する)
+	codeDiff, redactor, err := redactDiff(cfg, codeDiff)
+	if err != nil {
+		return "", err
+	}
+	ctx = withRedactor(ctx, redactor)
+	notifyDiff(ctx, codeDiff)
+
+	// 影響範囲は差分全体に対して一度だけ求め、各モードのプロンプトとレポートで共有する
+	impactResult := r.analyzeImpact(ctx, cfg, codeDiff)
+	ctx = withImpact(ctx, impactResult)
+	ctx = withPRMetadata(ctx, r.loadPRMetadata(ctx, cfg))
+	debtResult := scanDebt(cfg, codeDiff)
+	rb, err := loadRubric(cfg)
+	if err != nil {
+		return "", err
+	}
+	ctx = withRubric(ctx, rb)
+	accepted, err := loadBaseline(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	// 複数モードが指定された場合は、同じ差分に対して各モードのプロンプトを順に実行する
+	modes := cfg.Modes()
+	commitLog := sync.OnceValue(func() []internalAdapters.Commit {
+		return r.loadCommitLog(ctx, cfg)
+	})
+
+	results := make([]string, 0, len(modes))
+	for i, mode := range modes {
+		modeCfg := cfg
+		modeCfg.ReviewMode = mode
+
+		reviewResult, err := r.reviewMode(withModeProgress(ctx, modes[:i+1], results), modeCfg, codeDiff, commitLog)
+		if errors.Is(err, interrupt.ErrInterrupted) {
+			results = append(results, reviewResult)
+			return incompleteReport(mergeReports(modes[:i+1], results), modes[i+1:]), err
+		}
+		if err != nil {
+			return "", err
+		}
+		reviewResult = applyRubric(ctx, modeCfg, reviewResult)
+		if cfg.VerifyFindings {
+			reviewResult = r.verifyFindings(ctx, modeCfg, codeDiff, reviewResult)
+		}
+		if cfg.SuggestPatches {
+			reviewResult = r.suggestPatches(ctx, modeCfg, codeDiff, reviewResult)
+		}
+		results = append(results, reviewResult)
+
+		if interrupt.Requested(ctx) && i < len(modes)-1 {
+			return incompleteReport(mergeReports(modes[:i+1], results), modes[i+1:]), interrupt.ErrInterrupted
+		}
+
+		if h := partialResultHandler(ctx); h != nil && i < len(modes)-1 {
+			h(ctx, provisionalNotice+mergeReports(modes[:i+1], results))
+		}
+	}
+
+	// 分割レビューの各パートや複数モードで重複した指摘事項は、公開前に統合し、ベースラインで受け入れ済みの指摘事項は抑制する
+	report := suppressBaseline(dedupFindings(mergeReports(modes, results)), accepted)
+	report = appendImpactReport(cfg, report, impactResult)
+	report = appendDebtReport(report, debtResult)
+	report = appendFindingsSummary(cfg, report)
+	logRedactionAudit(redactor)
+	report = appendRedactionReport(report, redactor)
+	report = appendUsageReport(report, rec)
+	report = prependSummary(report)
+	if inc != nil {
+		report = inc.section() + report
+		inc.commit(ctx, cfg, report)
+	}
+	saveSession(cfg, baseRef, headRef, codeDiff, report)
+	return report, nil
+}
+
+// withModeProgress は、実行中のモードの途中経過を、完了済みのモードの結果と合わせた暫定版のレポートとして
+// 通知するよう PartialResultHandler を差し替えた context を返します。
+func withModeProgress(ctx context.Context, modes, done []string) context.Context {
+	h := partialResultHandler(ctx)
+	if h == nil {
+		return ctx
+	}
+	return WithPartialResultHandler(ctx, func(ctx context.Context, partial string) {
+		results := append(done[:len(done):len(done)], partial)
+		h(ctx, provisionalNotice+mergeReports(modes, results))
+	})
+}
+
+// buildPrompt は、cfg.ReviewMode (単一モード) のプロンプトを生成します。
+// コミットログは複数モードで共有するため、必要になった時点で commitLog から取得します。
+func (r *DefaultReviewRunner) buildPrompt(
+	ctx context.Context,
+	cfg config.ReviewConfig,
+	codeDiff string,
+	commitLog func() []internalAdapters.Commit,
+) (string, error) {
 	slog.InfoContext(ctx, "AIプロンプトを生成中...", "mode", cfg.ReviewMode)
-	templateData := prompts.TemplateData{DiffContent: codeDiff}
-	finalPrompt, err := r.promptBuilder.Build(cfg.ReviewMode, templateData)
+	templateData := buildTemplateData(cfg, codeDiff)
+	if prompts.NeedsCommitLog(cfg.ReviewMode) {
+		templateData.Commits = commitLog()
+	}
+	if cfg.ReviewMode == prompts.ModeCommitMsg {
+		templateData.CommitChecks = prompts.CheckCommits(templateData.Commits)
+		convention, err := prompts.LoadCommitConvention(cfg.LocalPath, cfg.CommitConventionFile)
+		if err != nil {
+			return "", err
+		}
+		templateData.CommitConvention = convention
+	}
+	overrides, err := r.loadRepoOverrides(cfg)
+	if err != nil {
+		return "", err
+	}
+	instruction, err := prompts.LoadSystemInstruction(cfg.SystemInstruction, cfg.SystemInstructionFile)
+	if err != nil {
+		return "", err
+	}
+	if instruction != "" {
+		// リポジトリの置き換え指定より後に適用し、組み込みのプロンプトを常に置き換える
+		slog.Debug("組み込みのプロンプトを --system-instruction で置き換えます。", "mode", cfg.ReviewMode)
+		overrides = append(overrides, prompts.SystemInstructionOverride(instruction))
+	}
+	finalPrompt, err := r.promptBuilder.BuildWithOverrides(cfg.ReviewMode, templateData, overrides)
 	if err != nil {
-		return "", fmt.Errorf("プロンプトの組み立てに失敗しました: %w", err)
+		return "", fmt.Errorf("プロンプトの組み立てに失敗しました (mode: %s): %w", cfg.ReviewMode, err)
 	}
+	glossary, err := loadGlossary(cfg)
+	if err != nil {
+		return "", err
+	}
+	finalPrompt, err = r.promptBuilder.AppendGlossary(finalPrompt, glossary)
+	if err != nil {
+		return "", err
+	}
+	examples, err := loadExamples(cfg)
+	if err != nil {
+		return "", err
+	}
+	finalPrompt, err = r.promptBuilder.PrependExamples(finalPrompt, examples)
+	if err != nil {
+		return "", err
+	}
+	persona, err := loadPersona(cfg)
+	if err != nil {
+		return "", err
+	}
+	finalPrompt, err = r.promptBuilder.PrependPersona(finalPrompt, persona)
+	if err != nil {
+		return "", err
+	}
+	if result := impactFrom(ctx); result != nil && prompts.NeedsFileContext(cfg.ReviewMode) {
+		finalPrompt, err = r.promptBuilder.AppendImpactContext(finalPrompt, *result)
+		if err != nil {
+			return "", err
+		}
+	}
+	if md := prMetadataFrom(ctx); md != nil && prompts.NeedsFileContext(cfg.ReviewMode) {
+		finalPrompt, err = r.promptBuilder.AppendPRContext(finalPrompt, md)
+		if err != nil {
+			return "", err
+		}
+	}
+	if prompts.NeedsFileContext(cfg.ReviewMode) {
+		finalPrompt, err = r.promptBuilder.AppendAssetContext(finalPrompt, r.loadAssetContext(ctx, cfg, codeDiff))
+		if err != nil {
+			return "", err
+		}
+	}
+	if cfg.HasContext(config.ContextFullFiles) && prompts.NeedsFileContext(cfg.ReviewMode) {
+		finalPrompt, err = r.promptBuilder.AppendFileContext(finalPrompt, r.loadFileContext(ctx, cfg, codeDiff))
+		if err != nil {
+			return "", err
+		}
+	}
+	if cfg.HasContext(config.ContextImports) && prompts.NeedsFileContext(cfg.ReviewMode) {
+		finalPrompt, err = r.promptBuilder.AppendImportContext(finalPrompt, r.loadImportContext(ctx, cfg, codeDiff))
+		if err != nil {
+			return "", err
+		}
+	}
+	if cfg.ReviewMode == prompts.ModeRelease {
+		finalPrompt, err = r.promptBuilder.AppendRubric(finalPrompt, rubricFrom(ctx))
+		if err != nil {
+			return "", err
+		}
+	}
+	if hasRedactions(ctx) {
+		finalPrompt, err = prompts.AppendRedactionNotice(finalPrompt)
+		if err != nil {
+			return "", err
+		}
+	}
+	if cfg.NeedsFindings() {
+		return prompts.AppendFindingsInstruction(finalPrompt)
+	}
+	return finalPrompt, nil
+}
 
-	// AIレビューの実行
-	slog.Info("Gemini AIによるコードレビューを開始します。", "model", cfg.GeminiModel)
+// mergeReports は、各モードの結果をモードごとのセクションに分けた1つのレポートにまとめます。
+// 単一モードの場合は結果をそのまま返します。
+func mergeReports(modes, results []string) string {
+	if len(results) == 1 {
+		return results[0]
+	}
 
-	// Gemini Adapterにレビューを依頼
-	reviewResult, err := r.geminiService.ReviewCodeDiff(ctx, finalPrompt)
+	var b strings.Builder
+	for i, result := range results {
+		if i > 0 {
+			b.WriteString("\n\n---\n\n")
+		}
+		fmt.Fprintf(&b, "# %s (`%s`)\n\n%s", prompts.ModeTitle(modes[i]), modes[i], strings.TrimSpace(result))
+	}
+	return b.String()
+}
+
+// loadRepoOverrides は、リポジトリ内の .gemini-review ディレクトリからプロンプトの上書き内容を読み込みます。
+// cfg.IgnoreRepoPrompt が true の場合は何も読み込みません。
+func (r *DefaultReviewRunner) loadRepoOverrides(cfg config.ReviewConfig) ([]prompts.RepoOverride, error) {
+	if cfg.IgnoreRepoPrompt {
+		return nil, nil
+	}
+	// インメモリモードではワーキングツリーが存在しないため読み込めない
+	if cfg.LocalPath == "" {
+		slog.Debug("ローカルパスが存在しないため、リポジトリ内のプロンプト設定は読み込みません。")
+		return nil, nil
+	}
+
+	overrides, err := prompts.LoadRepoOverrides(cfg.LocalPath, cfg.ReviewMode)
 	if err != nil {
-		return "", fmt.Errorf("AIレビューの実行に失敗しました: %w", err)
+		return nil, fmt.Errorf("リポジトリのプロンプト設定の読み込みに失敗しました: %w", err)
+	}
+	for _, ov := range overrides {
+		slog.Info("リポジトリ内のプロンプト設定を適用します。", "path", ov.Path, "replace", ov.Replace)
 	}
+	return overrides, nil
+}
 
-	return reviewResult, nil
+// loadGlossary は、プロンプトに追加するチームの用語集を読み込みます。
+// cfg.GlossaryFile が未指定の場合はリポジトリ内の .gemini-review/glossary.md を使用しますが、
+// cfg.IgnoreRepoPrompt が true の場合やインメモリモードでは読み込みません。
+func loadGlossary(cfg config.ReviewConfig) (string, error) {
+	repoDir := cfg.LocalPath
+	if cfg.IgnoreRepoPrompt {
+		repoDir = ""
+	}
+	glossary, err := prompts.LoadGlossary(repoDir, cfg.GlossaryFile)
+	if err != nil {
+		return "", err
+	}
+	if glossary != "" {
+		slog.Debug("チームの用語集をプロンプトに追加します。", "bytes", len(glossary))
+	}
+	return glossary, nil
+}
+
+// loadPersona は、プロンプトの先頭に追加するレビュアーのペルソナを読み込みます。
+// cfg.Persona、cfg.PersonaFile の順に優先し、いずれも未指定の場合はリポジトリ内の .gemini-review/persona.md を使用しますが、
+// cfg.IgnoreRepoPrompt が true の場合やインメモリモードでは読み込みません。
+func loadPersona(cfg config.ReviewConfig) (string, error) {
+	repoDir := cfg.LocalPath
+	if cfg.IgnoreRepoPrompt {
+		repoDir = ""
+	}
+	persona, err := prompts.LoadPersona(repoDir, cfg.Persona, cfg.PersonaFile)
+	if err != nil {
+		return "", err
+	}
+	if persona != "" {
+		slog.Debug("レビュアーのペルソナをプロンプトに追加します。", "bytes", len(persona))
+	}
+	return persona, nil
+}
+
+// loadExamples は、cfg.ReviewMode のプロンプトの先頭に追加するレビューの例を読み込みます。
+// cfg.ExamplesFile が未指定の場合はリポジトリ内の .gemini-review/examples.yaml を使用しますが、
+// cfg.IgnoreRepoPrompt が true の場合やインメモリモードでは読み込みません。
+func loadExamples(cfg config.ReviewConfig) ([]prompts.Example, error) {
+	repoDir := cfg.LocalPath
+	if cfg.IgnoreRepoPrompt {
+		repoDir = ""
+	}
+	examples, err := prompts.LoadExamples(repoDir, cfg.ExamplesFile, cfg.ReviewMode)
+	if err != nil {
+		return nil, err
+	}
+	if len(examples) > 0 {
+		slog.Debug("レビューの例をプロンプトに追加します。", "mode", cfg.ReviewMode, "examples", len(examples))
+	}
+	return examples, nil
+}
+
+// acquireRepoLock は、cfg.LocalPath に対するアドバイザリロックを取得します。
+// ロックは Cleanup の完了後に解放されるよう、呼び出し側で defer の順序に注意してください。
+func acquireRepoLock(ctx context.Context, cfg config.ReviewConfig) (*lockfile.Lock, error) {
+	if cfg.LocalPath == "" {
+		return nil, nil
+	}
+	lock, err := lockfile.Acquire(ctx, lockfile.PathFor(cfg.LocalPath), cfg.LockTimeout)
+	if errors.Is(err, lockfile.ErrLocked) {
+		return nil, fmt.Errorf("別のプロセスがローカルリポジトリを使用中です。--lock-timeout を延ばすこともできます: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ローカルリポジトリのロック取得に失敗しました: %w", err)
+	}
+	return lock, nil
+}
+
+// releaseRepoLock は、ロックを解放します。解放の失敗は処理結果に影響しないため、ログに記録するだけです。
+func releaseRepoLock(lock *lockfile.Lock) {
+	if err := lock.Release(); err != nil {
+		slog.Warn("ローカルリポジトリのロック解放に失敗しました。", "error", err)
+	}
+}
+
+// buildTemplateData は、差分から各モードのテンプレートが必要とする補助情報を抽出し、TemplateData を組み立てます。
+func buildTemplateData(cfg config.ReviewConfig, codeDiff string) prompts.TemplateData {
+	changedFiles := diffutil.ChangedFiles(codeDiff)
+	baseRef, headRef := cfg.DiffRefs()
+	if cfg.HeadCommit != "" && cfg.FromTag == "" {
+		// コミットを固定した場合も、プロンプトにはフィーチャーブランチ名を表示する
+		headRef = cfg.FeatureBranch
+	}
+	data := prompts.TemplateData{
+		Mode:          cfg.ReviewMode,
+		DiffContent:   codeDiff,
+		RepoURL:       cfg.RepoURL,
+		BaseBranch:    strings.TrimPrefix(baseRef, "refs/tags/"),
+		FeatureBranch: strings.TrimPrefix(headRef, "refs/tags/"),
+		ChangedFiles:  changedFiles,
+		LanguageHints: prompts.PerformanceHints(diffutil.Extensions(changedFiles)),
+	}
+
+	// 公開シンボルの抽出はテストギャップ分析でのみ使用する
+	if cfg.ReviewMode == prompts.ModeTestGap {
+		data.ChangedSymbols = diffutil.ChangedSymbols(diffutil.ParseFiles(codeDiff))
+		slog.Info("変更された公開シンボルを抽出しました。", "symbols", len(data.ChangedSymbols))
+	}
+
+	return data
+}
+
+// loadCommitLog は、GitService がコミットログの取得に対応している場合に base..feature のコミットを返します。
+// 取得できない場合でも差分のみでプロンプトを生成できるため、エラーは警告ログに留めます。
+func (r *DefaultReviewRunner) loadCommitLog(ctx context.Context, cfg config.ReviewConfig) []internalAdapters.Commit {
+	provider, ok := r.gitService.(internalAdapters.CommitLogProvider)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはコミットログの取得に対応していないため、差分のみを使用します。")
+		return nil
+	}
+
+	baseRef, headRef := cfg.DiffRefs()
+	commits, err := provider.GetCommitLog(ctx, baseRef, headRef)
+	if err != nil {
+		slog.Warn("コミットログの取得に失敗したため、差分のみを使用します。", "error", err)
+		return nil
+	}
+	slog.Info("コミットログを取得しました。", "commits", len(commits))
+	return commits
 }
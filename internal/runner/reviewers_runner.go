@@ -0,0 +1,263 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/codeowners"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffcache"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/objectstore"
+	"git-gemini-cli/internal/reviewers"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// ReviewersRunner は、差分に対するレビュアーの推奨を実行するインターフェースです。
+type ReviewersRunner interface {
+	Run(ctx context.Context, cfg config.ReviewersConfig) (string, error)
+}
+
+// reviewersCommentMode は、推奨結果をプルリクエストに投稿する際のコメントの識別に使用するモード名です。
+// レビュー結果のコメントとは別のコメントとして、推奨をやり直すたびに同じコメントを更新します。
+const reviewersCommentMode = "reviewers"
+
+// DefaultReviewersRunner は、CODEOWNERS・変更履歴・レビューの履歴からレビュアーを推奨し、必要に応じてプルリクエストや Slack に投稿します。
+// AI は使用しません。
+type DefaultReviewersRunner struct {
+	gitService adapters.GitService
+	notifier   internalAdapters.SlackNotifier
+	commenter  internalAdapters.PRCommenter // nil の場合はプルリクエストに投稿しない
+	history    objectstore.Store            // nil の場合はレビューの履歴を使用しない
+	diffCache  *diffcache.Cache             // nil の場合は差分をキャッシュしない
+}
+
+// NewDefaultReviewersRunner は DefaultReviewersRunner の新しいインスタンスを生成します。
+func NewDefaultReviewersRunner(git adapters.GitService, notifier internalAdapters.SlackNotifier, commenter internalAdapters.PRCommenter, history objectstore.Store, cache *diffcache.Cache) *DefaultReviewersRunner {
+	return &DefaultReviewersRunner{gitService: git, notifier: notifier, commenter: commenter, history: history, diffCache: cache}
+}
+
+// Run は差分から変更されたファイルを求め、推奨するレビュアーのレポートを返します。差分が空の場合は空文字列を返します。
+func (r *DefaultReviewersRunner) Run(ctx context.Context, cfg config.ReviewersConfig) (string, error) {
+	reviewCfg := cfg.ReviewConfig
+
+	lock, err := acquireRepoLock(ctx, reviewCfg)
+	if err != nil {
+		return "", err
+	}
+	defer releaseRepoLock(lock)
+
+	if err := r.gitService.CloneOrUpdate(ctx, reviewCfg.RepoURL); err != nil {
+		return "", fmt.Errorf("リポジトリのセットアップに失敗しました: %w", err)
+	}
+	defer func() {
+		if cleanupErr := r.gitService.Cleanup(ctx); cleanupErr != nil {
+			slog.Error("Gitリポジトリのクリーンアップに失敗しました。", "error", cleanupErr)
+		}
+	}()
+	codeDiff, err := fetchDiff(ctx, r.gitService, r.diffCache, reviewCfg)
+	if err != nil {
+		return "", err
+	}
+	baseRef, headRef := reviewCfg.DiffRefs()
+	files := diffutil.ChangedFiles(codeDiff)
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	var team reviewers.Team
+	if cfg.TeamFile != "" {
+		if team, err = reviewers.LoadTeam(cfg.TeamFile); err != nil {
+			return "", err
+		}
+	}
+
+	now := time.Now()
+	in := reviewers.Input{
+		Changes:      r.loadOwners(ctx, headRef, files),
+		History:      r.loadHistory(ctx, baseRef, files, cfg.History),
+		Team:         team,
+		Load:         r.loadReviewerLoad(ctx, cfg.HistoryURI, reviewCfg.RepoURL, headRef, now.Add(-cfg.LoadWindow)),
+		Authors:      r.loadAuthors(ctx, baseRef, headRef),
+		Now:          now,
+		Count:        cfg.Count,
+		IncludeTeams: cfg.IncludeTeams,
+	}
+	list := reviewers.Suggest(in)
+	slog.Info("レビュアーの推奨を求めました。", "files", len(files), "candidates", len(list))
+	r.recordReviewers(ctx, cfg.HistoryURI, reviewCfg.RepoURL, headRef, list)
+
+	report := reviewers.Report(list, in.Changes)
+	var errs []error
+	if r.commenter != nil {
+		commentCfg := reviewCfg
+		commentCfg.ReviewMode = reviewersCommentMode
+		if err := r.commenter.Comment(ctx, "", report, commentCfg); err != nil {
+			errs = append(errs, fmt.Errorf("レビュアーの推奨のプルリクエストへの投稿に失敗しました: %w", err))
+		}
+	}
+	if cfg.SlackWebhookURL != "" {
+		if err := r.notifier.Post(ctx, "👀 レビュアーの推奨", report); err != nil {
+			errs = append(errs, fmt.Errorf("レビュアーの推奨の Slack への投稿に失敗しました: %w", err))
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// loadOwners は、変更後の時点の CODEOWNERS から、変更されたファイルごとの所有者を求めます。
+// CODEOWNERS が存在しない場合は、所有者なしとして変更履歴のみで推奨します。
+func (r *DefaultReviewersRunner) loadOwners(ctx context.Context, ref string, files []string) []reviewers.Change {
+	changes := make([]reviewers.Change, len(files))
+	for i, f := range files {
+		changes[i].Path = f
+	}
+
+	provider, ok := r.gitService.(internalAdapters.FileContentProvider)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはファイル内容の取得に対応していないため、CODEOWNERS を使用しません。")
+		return changes
+	}
+	for _, p := range codeowners.Paths {
+		data, err := provider.GetFileContent(ctx, ref, p)
+		if err != nil {
+			continue
+		}
+		owners := codeowners.Parse(data)
+		for i := range changes {
+			changes[i].Owners = owners.Owners(changes[i].Path)
+		}
+		slog.Debug("CODEOWNERS を読み込みました。", "path", p, "rules", len(owners.Rules))
+		return changes
+	}
+	slog.Info("CODEOWNERS が見つからないため、変更履歴のみからレビュアーを推奨します。")
+	return changes
+}
+
+// loadHistory は、ベースブランチにおける変更されたファイルの変更履歴を取得します。
+func (r *DefaultReviewersRunner) loadHistory(ctx context.Context, ref string, files []string, period time.Duration) []reviewers.Contribution {
+	provider, ok := r.gitService.(internalAdapters.FileHistoryProvider)
+	if !ok {
+		slog.Warn("使用中のGitアダプタはファイルの変更履歴の取得に対応していないため、CODEOWNERS のみからレビュアーを推奨します。")
+		return nil
+	}
+	commits, err := provider.GetFileHistory(ctx, ref, files, time.Now().Add(-period))
+	if err != nil {
+		slog.Warn("ファイルの変更履歴の取得に失敗したため、CODEOWNERS のみからレビュアーを推奨します。", "error", err)
+		return nil
+	}
+
+	history := make([]reviewers.Contribution, len(commits))
+	for i, c := range commits {
+		history[i] = reviewers.Contribution{Name: c.Author, Email: c.Email, Date: c.Date, Files: c.Files}
+	}
+	return history
+}
+
+// loadAuthors は、レビュー対象のコミットの作成者 (PR の作成者) のメールアドレスを返します。作成者は推奨の候補から除外します。
+// CODEOWNERS のハンドルへの対応付けはメールアドレスで行うため、作成者名ではなくメールアドレスを使用します。
+func (r *DefaultReviewersRunner) loadAuthors(ctx context.Context, baseRef, headRef string) []string {
+	provider, ok := r.gitService.(internalAdapters.CommitLogProvider)
+	if !ok {
+		return nil
+	}
+	commits, err := provider.GetCommitLog(ctx, baseRef, headRef)
+	if err != nil {
+		if !errors.Is(err, internalAdapters.ErrCommitLogUnsupported) {
+			slog.Warn("コミットログの取得に失敗したため、PR の作成者を候補から除外できません。", "error", err)
+		}
+		return nil
+	}
+	var authors []string
+	for _, c := range commits {
+		if c.Email != "" {
+			authors = append(authors, c.Email)
+		}
+	}
+	return authors
+}
+
+// loadReviewerLoad は、レビューの履歴 (manifest.json) に記録した推奨から、レビュアーごとの担当中のレビューの件数を求めます。
+// since 以降に公開されたレビューのうち、同じリポジトリの他のブランチごとに最新の推奨を1件として数えます (今回のブランチは含めません)。
+// 履歴を使用しない場合や読み込めない場合は nil を返し、負荷を考慮せずに推奨します。
+func (r *DefaultReviewersRunner) loadReviewerLoad(ctx context.Context, uri, repoURL, headRef string, since time.Time) map[string]int {
+	if r.history == nil {
+		return nil
+	}
+	manifest, _, err := readManifest(ctx, r.history, uri)
+	if err != nil {
+		slog.Warn("レビューの履歴を読み込めないため、担当中のレビューの件数を考慮せずに推奨します。", "uri", uri, "error", err)
+		return nil
+	}
+
+	load := make(map[string]int)
+	seen := make(map[string]bool)
+	for _, e := range manifest.Reviews {
+		if e.RepoURL != repoURL || e.HeadRef == headRef || len(e.Reviewers) == 0 || e.PublishedAt.Before(since) {
+			continue
+		}
+		// 新しい順に並んでいるため、ブランチごとに最初に見つかった推奨が最新のもの
+		if seen[e.HeadRef] {
+			continue
+		}
+		seen[e.HeadRef] = true
+		for _, reviewer := range e.Reviewers {
+			load[strings.ToLower(reviewer)]++
+		}
+	}
+	slog.Debug("レビューの履歴から担当中のレビューの件数を求めました。", "uri", uri, "branches", len(seen), "reviewers", len(load))
+	return load
+}
+
+// recordReviewers は、今回のブランチの最新のレビューの項目に、推奨したレビュアーを記録します。
+// 以降の推奨で担当中のレビューとして数えるためのもので、記録に失敗してもエラーを記録して処理を続行します。
+func (r *DefaultReviewersRunner) recordReviewers(ctx context.Context, uri, repoURL, headRef string, list []reviewers.Suggestion) {
+	if r.history == nil || len(list) == 0 {
+		return
+	}
+	names := make([]string, len(list))
+	for i, s := range list {
+		names[i] = s.Reviewer
+	}
+
+	for attempt := 1; attempt <= manifestMaxAttempts; attempt++ {
+		manifest, version, err := readManifest(ctx, r.history, uri)
+		if err != nil {
+			slog.Error("レビューの履歴の読み込みに失敗したため、推奨したレビュアーを記録できません。", "uri", uri, "error", err)
+			return
+		}
+		i := slices.IndexFunc(manifest.Reviews, func(e manifestEntry) bool {
+			return e.RepoURL == repoURL && e.HeadRef == headRef
+		})
+		if i < 0 {
+			slog.Info("レビューの履歴にこのブランチのレビューがないため、推奨したレビュアーを記録しません (先にレビューを --manifest 付きで公開してください)。", "uri", uri, "branch", headRef)
+			return
+		}
+		manifest.Reviews[i].Reviewers = names
+
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			slog.Error("レビューの履歴の生成に失敗しました。", "uri", uri, "error", err)
+			return
+		}
+		err = r.history.WriteIf(ctx, uri, data, "application/json", version)
+		if errors.Is(err, objectstore.ErrPreconditionFailed) {
+			slog.Info("他の実行がレビューの履歴を更新したため、読み込みからやり直します。", "uri", uri, "attempt", attempt)
+			continue
+		}
+		if err != nil {
+			slog.Error("推奨したレビュアーのレビューの履歴への記録に失敗しました。", "uri", uri, "error", err)
+			return
+		}
+		slog.Info("推奨したレビュアーをレビューの履歴に記録しました。", "uri", uri, "branch", headRef, "reviewers", names)
+		return
+	}
+	slog.Error("他の実行による更新が続いたため、推奨したレビュアーをレビューの履歴に記録できませんでした。", "uri", uri)
+}
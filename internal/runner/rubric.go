@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/prompts"
+	"git-gemini-cli/internal/rubric"
+)
+
+// rubricFile は、リポジトリ内のチームのリリース判定基準のファイル名です。
+const rubricFile = "rubric.yaml"
+
+// rubricKey は、context にリリース判定基準を格納するためのキーです。
+type rubricKey struct{}
+
+// withRubric は、release モードのプロンプトと結果の採点で使用するリリース判定基準を設定した context を返します。
+func withRubric(ctx context.Context, r *rubric.Rubric) context.Context {
+	if r == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, rubricKey{}, r)
+}
+
+// rubricFrom は、context に設定されたリリース判定基準を返します。設定されていない場合は nil を返します。
+func rubricFrom(ctx context.Context) *rubric.Rubric {
+	r, _ := ctx.Value(rubricKey{}).(*rubric.Rubric)
+	return r
+}
+
+// loadRubric は、release モードで採点するチームのリリース判定基準を読み込みます。
+// cfg.RubricFile が未指定の場合はリポジトリ内の .gemini-review/rubric.yaml を使用しますが、
+// cfg.IgnoreRepoPrompt が true の場合やインメモリモードでは読み込みません。
+// release モードを実行しない場合や、判定基準がない場合は nil を返します。
+func loadRubric(cfg config.ReviewConfig) (*rubric.Rubric, error) {
+	if !slices.Contains(cfg.Modes(), prompts.ModeRelease) {
+		return nil, nil
+	}
+	path := cfg.RubricFile
+	if path == "" {
+		if cfg.IgnoreRepoPrompt || cfg.LocalPath == "" {
+			return nil, nil
+		}
+		path = filepath.Join(cfg.LocalPath, prompts.RepoConfigDir, rubricFile)
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	}
+
+	r, err := rubric.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("チームのリリース判定基準で採点します。", "path", path, "criteria", len(r.Criteria), "threshold", r.Threshold)
+	return r, nil
+}
+
+// applyRubric は、release モードの結果から評価項目ごとのスコアを取り出して総合スコアを求め、
+// レポートの末尾にリリース判定スコアのセクションを追加します。
+// 総合スコアがしきい値を下回る (No-Go の) 場合は、--fail-on の判定対象となる指摘事項も追加します。
+func applyRubric(ctx context.Context, cfg config.ReviewConfig, result string) string {
+	rb := rubricFrom(ctx)
+	if rb == nil || cfg.ReviewMode != prompts.ModeRelease {
+		return result
+	}
+
+	report, scores := rubric.Split(result)
+	if scores == nil {
+		slog.Warn("レビュー結果に評価項目のスコアがないため、すべての評価項目を 0 点として扱います。")
+	}
+	res := rb.Evaluate(scores)
+	slog.Info("リリース判定スコアを計算しました。", "overall", res.Overall, "threshold", res.Threshold, "go", res.Go)
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	b.WriteString("\n\n---\n\n## 🎯 リリース判定スコア\n\n")
+	b.WriteString("| 評価項目 | 重み | スコア | 理由 |\n| :--- | ---: | ---: | :--- |\n")
+	for _, item := range res.Items {
+		score, reason := fmt.Sprintf("%d / %d", item.Score, rubric.MaxScore), escapeTableCell(item.Reason)
+		if item.Missing {
+			score, reason = fmt.Sprintf("0 / %d", rubric.MaxScore), "採点されませんでした"
+		}
+		fmt.Fprintf(&b, "| `%s` | %g | %s | %s |\n", item.Name, item.Weight, score, reason)
+	}
+	verdict := "✅ Go"
+	if !res.Go {
+		verdict = "⛔ No-Go"
+	}
+	fmt.Fprintf(&b, "\n総合スコア: **%.1f / 100** (しきい値: %g) → **%s**\n", res.Overall, res.Threshold, verdict)
+
+	if !res.Go {
+		severity, _ := findings.ParseSeverity(rb.Severity)
+		title := fmt.Sprintf("リリース判定基準の総合スコアがしきい値を下回っています (%.1f / 100、しきい値: %g)", res.Overall, res.Threshold)
+		fmt.Fprintf(&b, "\n> ⚠️ **[%s] %s**\n", severity, title)
+		b.WriteString("\n")
+		b.WriteString(findings.Block([]findings.Finding{{Severity: severity, Title: title}}))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/usage"
+)
+
+// sidecarSchemaVersion は、JSON サイドカーの形式のバージョンです。互換性のない変更を行う場合に更新します。
+const sidecarSchemaVersion = 1
+
+// sidecar は、公開したレポートと同じ場所に保存する機械可読な JSON サイドカーです。
+// ダッシュボードなどが HTML を解析せずにレビュー結果を集計できるようにするためのものです。
+type sidecar struct {
+	SchemaVersion int                `json:"schema_version"`
+	GeneratedAt   time.Time          `json:"generated_at"`
+	ReportURI     string             `json:"report_uri"`
+	RepoURL       string             `json:"repo_url"`
+	BaseRef       string             `json:"base_ref"`
+	HeadRef       string             `json:"head_ref,omitempty"`
+	ReviewMode    string             `json:"review_mode"`
+	Backend       string             `json:"backend"`
+	Model         string             `json:"model"`
+	Findings      []findings.Finding `json:"findings"`         // 指摘事項を判定できなかった場合は null
+	Counts        map[string]int     `json:"counts,omitempty"` // 深刻度ごとの件数
+	Usage         *usage.Summary     `json:"usage,omitempty"`  // AI の使用量 (レポートに使用量ブロックがない場合は省略)
+}
+
+// sidecarURI は、レポートのURIの拡張子を .json に置き換えた JSON サイドカーのURIを返します (例: result.html → result.json)。
+func sidecarURI(uri string) string {
+	dir, name := path.Split(uri)
+	return dir + strings.TrimSuffix(name, path.Ext(name)) + ".json"
+}
+
+// buildSidecar は、公開したレポートの JSON サイドカーを生成します。
+// 指摘事項は context に格納されたもの (findings.NewContext)、使用量はレポートの使用量ブロックから取得します。
+func buildSidecar(ctx context.Context, cfg config.PublishConfig, report string) ([]byte, error) {
+	rc := cfg.ReviewConfig
+	baseRef, headRef := rc.DiffRefs()
+	s := sidecar{
+		SchemaVersion: sidecarSchemaVersion,
+		GeneratedAt:   time.Now().UTC(),
+		ReportURI:     cfg.StorageURI,
+		RepoURL:       rc.RepoURL,
+		BaseRef:       baseRef,
+		HeadRef:       headRef,
+		ReviewMode:    rc.ReviewMode,
+		Backend:       rc.Backend,
+		Model:         rc.Model,
+	}
+	if list, ok := findings.FromContext(ctx); ok {
+		s.Findings = append([]findings.Finding{}, list...)
+		s.Counts = make(map[string]int)
+		for _, f := range list {
+			s.Counts[f.Severity.String()]++
+		}
+	}
+	if u, ok := usage.Parse(report); ok {
+		s.Usage = &u
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("JSON サイドカーの生成に失敗しました: %w", err)
+	}
+	return data, nil
+}
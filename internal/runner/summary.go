@@ -0,0 +1,17 @@
+package runner
+
+import (
+	"strings"
+
+	"git-gemini-cli/internal/findings"
+)
+
+// prependSummary は、レポートの先頭に、指摘事項から求めた判定・主なリスク・件数のサマリーを追加します。
+// 構造化された指摘事項ブロックも "### [HIGH] タイトル" 形式の見出しもなく、指摘事項を判定できない場合は、レポートをそのまま返します。
+func prependSummary(report string) string {
+	_, list := findings.Split(report)
+	if len(list) == 0 && !findings.HasBlock(report) {
+		return report
+	}
+	return findings.Summarize(list).Markdown() + "\n---\n\n" + strings.TrimLeft(report, "\n")
+}
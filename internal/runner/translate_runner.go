@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/prompts"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/adapters"
+)
+
+// TranslateRunner は、レビュー結果のレポートを他の言語に翻訳するインターフェースです。
+type TranslateRunner interface {
+	Run(ctx context.Context, cfg config.ReviewConfig, report, language string) (string, error)
+}
+
+// DefaultTranslateRunner は、AIにレポートの翻訳を依頼します。
+type DefaultTranslateRunner struct {
+	geminiService adapters.CodeReviewAI
+	promptBuilder *prompts.Builder
+}
+
+// NewDefaultTranslateRunner は DefaultTranslateRunner の新しいインスタンスを生成します。
+func NewDefaultTranslateRunner(gemini adapters.CodeReviewAI, pb *prompts.Builder) *DefaultTranslateRunner {
+	return &DefaultTranslateRunner{
+		geminiService: gemini,
+		promptBuilder: pb,
+	}
+}
+
+// Run は、レポートを language (言語コード) に翻訳した結果を返します。
+// チームの用語集がある場合は、用語の訳語を揃えるためにプロンプトに追加します。
+func (r *DefaultTranslateRunner) Run(ctx context.Context, cfg config.ReviewConfig, report, language string) (string, error) {
+	prompt, err := r.promptBuilder.BuildTranslate(prompts.TranslateData{Language: language, Report: report})
+	if err != nil {
+		return "", fmt.Errorf("プロンプトの組み立てに失敗しました: %w", err)
+	}
+	glossary, err := loadGlossary(cfg)
+	if err != nil {
+		return "", err
+	}
+	prompt, err = r.promptBuilder.AppendTranslateGlossary(prompt, glossary)
+	if err != nil {
+		return "", err
+	}
+
+	slog.Info("AIにレポートの翻訳を依頼します。", "language", language, "backend", cfg.Backend, "model", cfg.Model)
+	translated, err := r.geminiService.ReviewCodeDiff(ctx, prompt)
+	if err != nil {
+		return "", fmt.Errorf("AIによるレポートの翻訳に失敗しました (language: %s): %w", language, err)
+	}
+	return strings.TrimSpace(translated), nil
+}
@@ -0,0 +1,20 @@
+package runner
+
+import (
+	"log/slog"
+	"strings"
+
+	"git-gemini-cli/internal/usage"
+)
+
+// appendUsageReport は、レビュー中のAI呼び出しのトークン数・所要時間・再試行回数の表と、
+// コスト集計用の使用量ブロック (HTML コメント) をレポートの末尾に追加します。
+func appendUsageReport(report string, rec *usage.Recorder) string {
+	s := rec.Summary()
+	if len(s.Calls) == 0 {
+		return report
+	}
+	slog.Info("AI の使用量を集計しました。", "calls", len(s.Calls), "prompt_tokens", s.PromptTokens,
+		"output_tokens", s.OutputTokens, "latency_ms", s.LatencyMS, "retries", s.Retries)
+	return strings.TrimRight(report, "\n") + "\n\n---\n\n" + s.Markdown()
+}
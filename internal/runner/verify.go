@@ -0,0 +1,161 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalAdapters "git-gemini-cli/internal/adapters"
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/diffutil"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/interrupt"
+	"git-gemini-cli/internal/prompts"
+)
+
+const (
+	// maxVerifyFindings は、1つのモードの結果で検証する指摘事項の上限です。超えた分は検証せずに残します。
+	maxVerifyFindings = 20
+	// maxVerifyHunkBytes は、検証のプロンプトに含める差分の上限 (バイト) です。
+	maxVerifyHunkBytes = 20_000
+)
+
+const (
+	// verdictConfirmed は、指摘事項が差分のコードに照らして妥当と判定されたことを示します。
+	verdictConfirmed = "confirmed"
+	// verdictRejected は、指摘事項が誤りと判定されたことを示します。
+	verdictRejected = "rejected"
+	// verdictUncertain は、差分だけでは判断できない (または判定を取得できなかった) ことを示します。
+	verdictUncertain = "uncertain"
+)
+
+// verification は、1件の指摘事項の検証結果です。
+type verification struct {
+	Verdict string `json:"verdict"`
+	Reason  string `json:"reason"`
+}
+
+// verifyFindings は、レビュー結果の各指摘事項を該当箇所の差分と照らして AI に再確認させ、
+// 誤りと判定された指摘事項を本文と構造化された指摘事項ブロックから取り除きます。
+// 判定を取得できなかった指摘事項は、判断できないものとして残します。
+func (r *DefaultReviewRunner) verifyFindings(ctx context.Context, cfg config.ReviewConfig, codeDiff, result string) string {
+	report, list := findings.Split(result)
+	if len(list) == 0 {
+		return result
+	}
+
+	slog.Info("指摘事項の検証を開始します。", "mode", cfg.ReviewMode, "findings", len(list))
+	var kept []findings.Finding
+	var rejected []findings.Finding
+	var reasons []string
+	uncertain, skipped := 0, 0
+	for i, f := range list {
+		if i >= maxVerifyFindings || interrupt.Requested(ctx) {
+			kept = append(kept, f)
+			skipped++
+			continue
+		}
+
+		v := r.verifyFinding(ctx, cfg, codeDiff, findings.Section(report, f), f)
+		switch v.Verdict {
+		case verdictRejected:
+			slog.Info("誤りと判定された指摘事項を取り除きます。", "severity", f.Severity, "title", f.Title, "reason", v.Reason)
+			rejected = append(rejected, f)
+			reasons = append(reasons, v.Reason)
+			report, _ = findings.RemoveSection(report, f)
+		case verdictUncertain:
+			uncertain++
+			kept = append(kept, f)
+		default:
+			kept = append(kept, f)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(report, "\n"))
+	b.WriteString("\n\n---\n\n## 🔍 指摘事項の検証\n\n")
+	fmt.Fprintf(&b, "%d 件の指摘事項を該当箇所の差分と照らして再確認し、%d 件を誤りと判定して取り除きました", len(list), len(rejected))
+	if uncertain > 0 {
+		fmt.Fprintf(&b, " (差分だけでは判断できない指摘事項: %d 件)", uncertain)
+	}
+	b.WriteString("。\n")
+	if skipped > 0 {
+		fmt.Fprintf(&b, "\n上限 (%d 件) を超えたため、%d 件の指摘事項は検証せずに残しています。\n", maxVerifyFindings, skipped)
+	}
+	if len(rejected) > 0 {
+		b.WriteString("\n| 深刻度 | 指摘事項 | 場所 | 理由 |\n| :--- | :--- | :--- | :--- |\n")
+		for i, f := range rejected {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", f.Severity, escapeTableCell(f.Title), findingLocation(f), escapeTableCell(reasons[i]))
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(findings.Block(kept))
+	return b.String()
+}
+
+// verifyFinding は、1件の指摘事項を該当箇所の差分と照らして AI に判定させます。
+// プロンプトの生成や AI の呼び出しに失敗した場合、応答を解釈できない場合は verdictUncertain とします。
+func (r *DefaultReviewRunner) verifyFinding(ctx context.Context, cfg config.ReviewConfig, codeDiff, detail string, f findings.Finding) verification {
+	hunk := codeDiff
+	if f.File != "" {
+		if h := diffutil.HunkAt(codeDiff, f.File, f.Line); h != "" {
+			hunk = h
+		}
+	}
+	if len(hunk) > maxVerifyHunkBytes {
+		hunk = hunk[:maxVerifyHunkBytes]
+	}
+
+	prompt, err := r.promptBuilder.BuildVerify(prompts.VerifyData{Mode: cfg.ReviewMode, Finding: f, Detail: detail, Hunk: hunk})
+	if err != nil {
+		slog.Warn("指摘事項の検証プロンプトの生成に失敗したため、指摘事項を残します。", "title", f.Title, "error", err)
+		return verification{Verdict: verdictUncertain}
+	}
+	reply, err := r.geminiService.ReviewCodeDiff(internalAdapters.WithReviewMode(ctx, cfg.ReviewMode), prompt)
+	if err != nil {
+		slog.Warn("指摘事項の検証に失敗したため、指摘事項を残します。", "title", f.Title, "error", err)
+		return verification{Verdict: verdictUncertain}
+	}
+	return parseVerification(reply)
+}
+
+// parseVerification は、AI の応答から判定の JSON オブジェクトを取り出します。
+// 前後の説明文やコードブロックは無視し、解釈できない場合は verdictUncertain とします。
+func parseVerification(reply string) verification {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return verification{Verdict: verdictUncertain}
+	}
+	var v verification
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &v); err != nil {
+		return verification{Verdict: verdictUncertain}
+	}
+	v.Verdict = strings.ToLower(strings.TrimSpace(v.Verdict))
+	switch v.Verdict {
+	case verdictConfirmed, verdictRejected:
+	default:
+		v.Verdict = verdictUncertain
+	}
+	v.Reason = strings.TrimSpace(v.Reason)
+	return v
+}
+
+// findingLocation は、指摘事項の場所を "`path:line`" 形式で返します。場所が不明な場合は "-" を返します。
+func findingLocation(f findings.Finding) string {
+	switch {
+	case f.File == "":
+		return "-"
+	case f.Line > 0:
+		return fmt.Sprintf("`%s:%d`", f.File, f.Line)
+	default:
+		return fmt.Sprintf("`%s`", f.File)
+	}
+}
+
+// escapeTableCell は、Markdown の表のセルに含められるよう、改行とパイプをエスケープします。
+func escapeTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
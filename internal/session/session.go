@@ -0,0 +1,86 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNoSession は、保存されたレビュー結果が存在しないことを示すエラーです。
+var ErrNoSession = errors.New("保存されたレビュー結果がありません。先に generic / publish などでレビューを実行してください")
+
+// Review は、chat コマンドで会話の前提とする、最後に実行したレビューの結果と差分です。
+// 差分は機密情報をマスクした後のもの (AI に送信したもの) を保存します。
+type Review struct {
+	RepoURL    string    `json:"repoUrl"`
+	BaseRef    string    `json:"baseRef"`
+	HeadRef    string    `json:"headRef"`
+	Mode       string    `json:"mode"`
+	Report     string    `json:"report"`
+	Diff       string    `json:"diff"`
+	ReviewedAt time.Time `json:"reviewedAt"`
+}
+
+// DefaultPath は、最後のレビュー結果の既定の保存先 (ユーザーのキャッシュディレクトリ配下) を返します。
+func DefaultPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("キャッシュディレクトリの取得に失敗しました: %w", err)
+	}
+	return filepath.Join(dir, "git-gemini-cli", "last-review.json"), nil
+}
+
+// ResolvePath は、path が指定されていればそのまま、未指定の場合は DefaultPath を返します。
+func ResolvePath(path string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	return DefaultPath()
+}
+
+// Load は、path に保存されたレビュー結果を読み込みます。存在しない場合は ErrNoSession を返します。
+func Load(path string) (Review, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Review{}, fmt.Errorf("%w: %s", ErrNoSession, path)
+	}
+	if err != nil {
+		return Review{}, fmt.Errorf("レビュー結果 '%s' の読み込みに失敗しました: %w", path, err)
+	}
+	var r Review
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Review{}, fmt.Errorf("レビュー結果 '%s' の解析に失敗しました: %w", path, err)
+	}
+	return r, nil
+}
+
+// Save は、レビュー結果を path に保存します。差分を含むため、所有者のみが読み書きできる権限で作成します。
+// 書き込み途中の内容を他の実行が読まないよう、一時ファイル経由で置き換えます。
+func Save(path string, r Review) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("レビュー結果の変換に失敗しました: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("レビュー結果の保存先ディレクトリの作成に失敗しました: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("レビュー結果 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("レビュー結果 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("レビュー結果 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("レビュー結果 '%s' の書き込みに失敗しました: %w", path, err)
+	}
+	return nil
+}
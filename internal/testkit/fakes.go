@@ -0,0 +1,194 @@
+package testkit
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	"git-gemini-cli/internal/config"
+	"git-gemini-cli/internal/findings"
+	"git-gemini-cli/internal/objectstore"
+
+	"github.com/shouni/gemini-reviewer-core/pkg/publisher"
+)
+
+// DefaultReview は、FakeAI が応答を指定されていない場合に返すレビュー結果です。
+const DefaultReview = "## レビュー結果\n\n指摘事項はありません。"
+
+// FakeAI は、受け取ったプロンプトを記録し、決まった応答を返す偽のAIです。
+// coreAdapters.CodeReviewAI インターフェースを実装します。
+type FakeAI struct {
+	// Respond は、プロンプトに対する応答を返す関数です。nil の場合は DefaultReview と Findings を返します。
+	Respond func(prompt string) (string, error)
+	// Findings は、構造化された指摘事項を求めるプロンプトに対して、応答の末尾に追加する指摘事項です。
+	Findings []findings.Finding
+
+	mu      sync.Mutex
+	prompts []string
+}
+
+// ReviewCodeDiff は coreAdapters.CodeReviewAI インターフェースの実装です。
+func (f *FakeAI) ReviewCodeDiff(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	f.prompts = append(f.prompts, prompt)
+	f.mu.Unlock()
+
+	if f.Respond != nil {
+		return f.Respond(prompt)
+	}
+	if strings.Contains(prompt, findings.BlockStart) {
+		return DefaultReview + "\n\n" + findings.Block(f.Findings), nil
+	}
+	return DefaultReview, nil
+}
+
+// Prompts は、これまでに受け取ったプロンプトを受信順に返します。
+func (f *FakeAI) Prompts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.prompts...)
+}
+
+// Upload は、MemoryStorage に公開されたレポートです。
+type Upload struct {
+	URI  string
+	Data publisher.ReviewData
+}
+
+// MemoryStorage は、公開されたレポートと補助的なオブジェクト (重複排除マーカーなど) をメモリ上に保持する偽のストレージです。
+// publisher.Publisher と objectstore.Store インターフェースを実装し、両者で同じオブジェクトを共有します。
+type MemoryStorage struct {
+	mu       sync.Mutex
+	objects  map[string]objectstore.Object
+	uploads  []Upload
+	versions int
+}
+
+// NewMemoryStorage は、空の MemoryStorage を返します。
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{objects: make(map[string]objectstore.Object)}
+}
+
+// Publish は publisher.Publisher インターフェースの実装です。レポートの Markdown をそのまま保存します。
+func (s *MemoryStorage) Publish(ctx context.Context, uri string, data publisher.ReviewData) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.uploads = append(s.uploads, Upload{URI: uri, Data: data})
+	s.objects[uri] = s.nextObject([]byte(data.ReviewMarkdown))
+	return nil
+}
+
+// Exists は objectstore.Store インターフェースの実装です。
+func (s *MemoryStorage) Exists(ctx context.Context, uri string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.objects[uri]
+	return ok, nil
+}
+
+// Read は objectstore.Store インターフェースの実装です。
+func (s *MemoryStorage) Read(ctx context.Context, uri string) (objectstore.Object, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[uri]
+	if !ok {
+		return objectstore.Object{}, objectstore.ErrNotFound
+	}
+	return obj, nil
+}
+
+// WriteIf は objectstore.Store インターフェースの実装です。
+func (s *MemoryStorage) WriteIf(ctx context.Context, uri string, data []byte, contentType, version string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, exists := s.objects[uri]
+	if (version == "" && exists) || (version != "" && current.Version != version) {
+		return objectstore.ErrPreconditionFailed
+	}
+	s.objects[uri] = s.nextObject(data)
+	return nil
+}
+
+// Write は objectstore.Store インターフェースの実装です。
+func (s *MemoryStorage) Write(ctx context.Context, uri string, data []byte, contentType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[uri] = s.nextObject(data)
+	return nil
+}
+
+// Close は objectstore.Store インターフェースの実装です。
+func (s *MemoryStorage) Close() error {
+	return nil
+}
+
+// Uploads は、これまでに公開されたレポートを公開順に返します。
+func (s *MemoryStorage) Uploads() []Upload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Upload(nil), s.uploads...)
+}
+
+// Object は、URIに保存されているオブジェクトの内容を返します。
+func (s *MemoryStorage) Object(uri string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	obj, ok := s.objects[uri]
+	return obj.Data, ok
+}
+
+// nextObject は、新しいバージョン番号を付けたオブジェクトを返します。呼び出し側で mu を保持してください。
+func (s *MemoryStorage) nextObject(data []byte) objectstore.Object {
+	s.versions++
+	return objectstore.Object{Data: append([]byte(nil), data...), Version: strconv.Itoa(s.versions)}
+}
+
+// Notification は、FakeNotifier が受け取った通知です。
+type Notification struct {
+	PublicURL  string
+	StorageURI string
+	Title      string // Post で投稿された場合の見出し
+	Content    string // Post で投稿された場合の本文
+}
+
+// FakeNotifier は、Slack への通知を送信せずに記録する偽の通知先です。
+// adapters.SlackNotifier インターフェースを実装します。
+type FakeNotifier struct {
+	// Err は、通知の失敗を再現する場合に返すエラーです。
+	Err error
+
+	mu            sync.Mutex
+	notifications []Notification
+}
+
+// Notify は adapters.SlackNotifier インターフェースの実装です。
+func (n *FakeNotifier) Notify(ctx context.Context, publicURL, storageURI string, cfg config.ReviewConfig) error {
+	return n.record(Notification{PublicURL: publicURL, StorageURI: storageURI})
+}
+
+// Post は adapters.SlackNotifier インターフェースの実装です。
+func (n *FakeNotifier) Post(ctx context.Context, title, content string) error {
+	return n.record(Notification{Title: title, Content: content})
+}
+
+// Notifications は、これまでに受け取った通知を受信順に返します。失敗させた通知は含みません。
+func (n *FakeNotifier) Notifications() []Notification {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]Notification(nil), n.notifications...)
+}
+
+// record は、通知を記録します。Err が設定されている場合は記録せずに Err を返します。
+func (n *FakeNotifier) record(notification Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.Err != nil {
+		return n.Err
+	}
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
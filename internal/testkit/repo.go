@@ -0,0 +1,106 @@
+package testkit
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// DefaultBaseBranch は、Repo が作成するリポジトリの既定のブランチ名です。
+const DefaultBaseBranch = "main"
+
+// Repo は、テスト用にローカルに作成したベアリポジトリ (クローン元) と、コミットを作成するための作業用クローンです。
+// クローン元は file:// のURLで参照できるため、ネットワークやSSH鍵なしでクローン・フェッチを検証できます。
+type Repo struct {
+	tb      testing.TB
+	bareDir string
+	workDir string
+}
+
+// NewRepo は、既定のブランチに README.md のみを含むベアリポジトリを作成します。
+// 'git' コマンドが見つからない場合は、テストをスキップします。
+func NewRepo(tb testing.TB) *Repo {
+	tb.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		tb.Skip("git コマンドが見つからないため、テストをスキップします")
+	}
+
+	root := tb.TempDir()
+	r := &Repo{
+		tb:      tb,
+		bareDir: filepath.Join(root, "origin.git"),
+		workDir: filepath.Join(root, "work"),
+	}
+	r.git(root, "init", "--bare", "--initial-branch="+DefaultBaseBranch, r.bareDir)
+	r.git(root, "clone", r.bareDir, r.workDir)
+	r.Commit(DefaultBaseBranch, map[string]string{"README.md": "# fixture\n"}, "initial commit")
+	return r
+}
+
+// URL は、クローン元のリポジトリのURL (file://) を返します。
+func (r *Repo) URL() string {
+	return "file://" + filepath.ToSlash(r.bareDir)
+}
+
+// Commit は、branch にファイルを書き込んだコミットを作成してクローン元に push し、コミットハッシュを返します。
+// branch が存在しない場合は、既定のブランチから作成します。内容が空文字のファイルは削除します。
+func (r *Repo) Commit(branch string, files map[string]string, message string) string {
+	r.tb.Helper()
+
+	if r.hasBranch(branch) {
+		r.git(r.workDir, "checkout", branch)
+	} else if r.hasBranch(DefaultBaseBranch) {
+		r.git(r.workDir, "checkout", "-b", branch, DefaultBaseBranch)
+	} else {
+		// 最初のコミットの前は、ブランチが存在しない
+		r.git(r.workDir, "checkout", "--orphan", branch)
+	}
+
+	for path, content := range files {
+		full := filepath.Join(r.workDir, filepath.FromSlash(path))
+		if content == "" {
+			r.git(r.workDir, "rm", "-q", "--ignore-unmatch", path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			r.tb.Fatalf("ディレクトリの作成に失敗しました: %v", err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			r.tb.Fatalf("ファイル '%s' の書き込みに失敗しました: %v", path, err)
+		}
+		r.git(r.workDir, "add", path)
+	}
+	r.git(r.workDir, "commit", "-q", "--allow-empty", "-m", message)
+	r.git(r.workDir, "push", "-q", "origin", branch)
+	return r.git(r.workDir, "rev-parse", "HEAD")
+}
+
+// Tag は、branch の先頭に軽量タグを作成してクローン元に push します。
+func (r *Repo) Tag(branch, tag string) {
+	r.tb.Helper()
+	r.git(r.workDir, "tag", tag, branch)
+	r.git(r.workDir, "push", "-q", "origin", tag)
+}
+
+// hasBranch は、作業用クローンにローカルブランチが存在するかを返します。
+func (r *Repo) hasBranch(branch string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	cmd.Dir = r.workDir
+	return cmd.Run() == nil
+}
+
+// git は、作成者を固定した上で Git コマンドを実行し、出力を返します。失敗した場合はテストを失敗させます。
+func (r *Repo) git(dir string, args ...string) string {
+	r.tb.Helper()
+	cmd := exec.Command("git", append([]string{"-c", "user.name=testkit", "-c", "user.email=testkit@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+	cmd.Dir = dir
+	// 実行環境のグローバル設定 (フック、署名など) の影響を受けないようにする
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL="+os.DevNull, "GIT_CONFIG_NOSYSTEM=1")
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		r.tb.Fatalf("git %s に失敗しました: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,77 @@
+package testkit
+
+import (
+	"path/filepath"
+	"testing"
+
+	"git-gemini-cli/internal/builder"
+	"git-gemini-cli/internal/config"
+)
+
+// Harness は、レビューパイプライン全体 (クローン → 差分 → レビュー → 公開 → 通知) を、
+// ネットワークや外部サービスに接続せずに検証するための、テスト用のリポジトリと偽の外部サービス一式です。
+// 偽の実装は builder.Dependencies として渡すため、本番と同じ builder・runner・pipeline のコードを実行できます。
+//
+//	h := testkit.New(t)
+//	h.Repo.Commit("feature/x", map[string]string{"main.go": "package main\n"}, "add main.go")
+//	err := pipeline.ReviewAndPublishWith(ctx, h.PublishConfig("feature/x", "mem://bucket/result.html"), h.Dependencies())
+//	// h.Storage.Uploads()、h.Notifier.Notifications()、h.AI.Prompts() で結果を検証する
+type Harness struct {
+	Repo     *Repo
+	AI       *FakeAI
+	Storage  *MemoryStorage
+	Notifier *FakeNotifier
+
+	localPath string
+}
+
+// New は、既定のブランチのみを持つリポジトリと、偽の外部サービスを用意した Harness を返します。
+func New(tb testing.TB) *Harness {
+	tb.Helper()
+	return &Harness{
+		Repo:      NewRepo(tb),
+		AI:        &FakeAI{},
+		Storage:   NewMemoryStorage(),
+		Notifier:  &FakeNotifier{},
+		localPath: filepath.Join(tb.TempDir(), "clone"),
+	}
+}
+
+// Dependencies は、AI・ストレージ・通知先として Harness の偽の実装を指定した依存関係を返します。
+func (h *Harness) Dependencies() builder.Dependencies {
+	return builder.Dependencies{
+		AI:        h.AI,
+		Publisher: h.Storage,
+		Store:     h.Storage,
+		Notifier:  h.Notifier,
+	}
+}
+
+// ReviewConfig は、Harness のリポジトリの既定のブランチと featureBranch の差分をレビューする設定を返します。
+// 外部Gitコマンドを使用してテスト用のローカルパスにクローンします。必要に応じて返り値を変更してください。
+func (h *Harness) ReviewConfig(featureBranch string) config.ReviewConfig {
+	return config.ReviewConfig{
+		ReviewMode:            "detail",
+		Backend:               config.BackendGemini,
+		Model:                 config.DefaultModel(config.BackendGemini),
+		RepoURL:               h.Repo.URL(),
+		BaseBranch:            DefaultBaseBranch,
+		FeatureBranch:         featureBranch,
+		LocalPath:             h.localPath,
+		UseExternalGitCommand: true,
+		DisableGitFallback:    true,
+		ImpactAnalysis:        config.ImpactNone,
+	}
+}
+
+// PublishConfig は、ReviewConfig の差分をレビューし、uri に公開して通知する設定を返します。
+// 公開先は MemoryStorage のため、uri のスキームは任意です (例: "mem://bucket/result.html")。
+func (h *Harness) PublishConfig(featureBranch, uri string) config.PublishConfig {
+	return config.PublishConfig{
+		ReviewConfig:    h.ReviewConfig(featureBranch),
+		StorageURI:      uri,
+		SlackWebhookURL: "https://hooks.slack.invalid/testkit",
+		IdempotencyKey:  "testkit",
+		OnConflict:      config.ConflictOverwrite,
+	}
+}
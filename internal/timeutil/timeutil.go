@@ -0,0 +1,52 @@
+package timeutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// CIのコンテナイメージにタイムゾーンデータベースが含まれていない場合でも IANA 名を解決できるよう埋め込む
+	_ "time/tzdata"
+)
+
+// ReportLayout は、レポートや通知に表示する日時の書式です。
+const ReportLayout = "2006-01-02 15:04:05 MST"
+
+// LoadLocation は、タイムゾーン名を *time.Location に変換します。
+// 空文字と "Local" はホストのタイムゾーン (環境変数 TZ) を、"UTC" や "Asia/Tokyo" などの IANA 名はそのタイムゾーンを、
+// "+09:00" のような形式は固定オフセットのタイムゾーンを表します。
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	switch name {
+	case "", "Local":
+		return time.Local, nil
+	}
+
+	if strings.HasPrefix(name, "+") || strings.HasPrefix(name, "-") {
+		t, err := time.Parse("-07:00", name)
+		if err != nil {
+			return nil, fmt.Errorf("タイムゾーンのオフセット '%s' が不正です ('+09:00' の形式で指定してください): %w", name, err)
+		}
+		_, offset := t.Zone()
+		return time.FixedZone("UTC"+name, offset), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("タイムゾーン '%s' を解決できません (例: 'UTC', 'Asia/Tokyo', '+09:00'): %w", name, err)
+	}
+	return loc, nil
+}
+
+// SetDefault は、アプリケーション全体で使用するタイムゾーンを設定します。
+// time.Local を置き換えるため、依存ライブラリ (HTMLレポートの生成など) が出力する日時にも適用されます。
+func SetDefault(loc *time.Location) {
+	if loc != nil {
+		time.Local = loc
+	}
+}
+
+// FormatReport は、日時を設定済みのタイムゾーンでレポート用の書式に変換します。
+func FormatReport(t time.Time) string {
+	return t.In(time.Local).Format(ReportLayout)
+}
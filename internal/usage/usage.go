@@ -0,0 +1,200 @@
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BlockStart は、使用量ブロックの開始を示すマーカーです。
+// 指摘事項ブロックと同様に HTML コメントとして出力するため、Markdown/HTML として表示しても見えません。
+const BlockStart = "<!-- gemini-review:usage"
+
+// blockPattern は、使用量ブロック (JSON オブジェクト) を抽出する正規表現です。
+var blockPattern = regexp.MustCompile(`(?s)` + regexp.QuoteMeta(BlockStart) + `\s*(.*?)\s*-->`)
+
+// Call は、1回のAI呼び出しの使用量です。再試行を含めて1回と数えます。
+type Call struct {
+	Mode         string `json:"mode,omitempty"`
+	Backend      string `json:"backend"`
+	Model        string `json:"model"`
+	PromptTokens int    `json:"prompt_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+	Estimated    bool   `json:"estimated,omitempty"` // トークン数が API の応答ではなくバイト数からの概算値
+	LatencyMS    int64  `json:"latency_ms"`          // 再試行と待機を含む所要時間
+	Retries      int    `json:"retries"`
+	Failed       bool   `json:"failed,omitempty"`
+}
+
+// Summary は、1回のレビューでのAI呼び出しの使用量の集計です。
+type Summary struct {
+	Calls        []Call `json:"calls"`
+	PromptTokens int    `json:"prompt_tokens"`
+	OutputTokens int    `json:"output_tokens"`
+	LatencyMS    int64  `json:"latency_ms"`
+	Retries      int    `json:"retries"`
+}
+
+// Recorder は、AI呼び出しの使用量を記録します。並行して呼び出されるAIの使用量も記録できます。
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// recorderKey は、context に Recorder を格納するためのキーです。
+type recorderKey struct{}
+
+// callKey は、context に実行中のAI呼び出しの使用量を格納するためのキーです。
+type callKey struct{}
+
+// NewContext は、新しい Recorder を格納した context と、その Recorder を返します。
+func NewContext(ctx context.Context) (context.Context, *Recorder) {
+	rec := &Recorder{}
+	return context.WithValue(ctx, recorderKey{}, rec), rec
+}
+
+// FromContext は、context に格納された Recorder を返します。格納されていない場合は nil を返します。
+func FromContext(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rec
+}
+
+// StartCall は、1回のAI呼び出しの使用量を格納した context を返します。
+// アダプタは ReportTokens と ReportRetry で、この使用量に API の応答のトークン数と再試行回数を記録します。
+func StartCall(ctx context.Context) (context.Context, *Call) {
+	call := &Call{}
+	return context.WithValue(ctx, callKey{}, call), call
+}
+
+// ReportTokens は、実行中のAI呼び出しについて API の応答に含まれるトークン数を記録します。
+// StartCall で開始した呼び出しでない場合は何もしません。
+func ReportTokens(ctx context.Context, promptTokens, outputTokens int) {
+	if call, ok := ctx.Value(callKey{}).(*Call); ok {
+		call.PromptTokens, call.OutputTokens = promptTokens, outputTokens
+	}
+}
+
+// ReportRetry は、実行中のAI呼び出しの再試行を1回記録します。
+// StartCall で開始した呼び出しでない場合は何もしません。
+func ReportRetry(ctx context.Context) {
+	if call, ok := ctx.Value(callKey{}).(*Call); ok {
+		call.Retries++
+	}
+}
+
+// Add は、完了したAI呼び出しの使用量を記録します。
+func (r *Recorder) Add(call Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+// Summary は、記録したAI呼び出しの使用量を集計します。
+func (r *Recorder) Summary() Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := Summary{Calls: append([]Call(nil), r.calls...)}
+	for _, c := range s.Calls {
+		s.PromptTokens += c.PromptTokens
+		s.OutputTokens += c.OutputTokens
+		s.LatencyMS += c.LatencyMS
+		s.Retries += c.Retries
+	}
+	return s
+}
+
+// Block は、使用量の集計を使用量ブロック (HTML コメント) として出力します。
+func (s Summary) Block() string {
+	data, err := json.Marshal(s)
+	if err != nil {
+		// Summary は常に JSON に変換できる
+		data = []byte("{}")
+	}
+	return BlockStart + "\n" + string(data) + "\n-->"
+}
+
+// Parse は、レポートに含まれる使用量ブロックを解析します。ブロックがない場合は false を返します。
+func Parse(report string) (Summary, bool) {
+	m := blockPattern.FindStringSubmatch(report)
+	if m == nil {
+		return Summary{}, false
+	}
+	var s Summary
+	if err := json.Unmarshal([]byte(m[1]), &s); err != nil {
+		return Summary{}, false
+	}
+	return s, true
+}
+
+// Markdown は、バックエンド・モデル・モードごとに集計した使用量の表と、使用量ブロックを返します。
+func (s Summary) Markdown() string {
+	type key struct{ backend, model, mode string }
+	type row struct {
+		calls, prompt, output, retries int
+		latency                        int64
+		estimated                      bool
+	}
+	rows := make(map[key]*row)
+	var keys []key
+	for _, c := range s.Calls {
+		k := key{c.Backend, c.Model, c.Mode}
+		if rows[k] == nil {
+			rows[k] = &row{}
+			keys = append(keys, k)
+		}
+		r := rows[k]
+		r.calls++
+		r.prompt += c.PromptTokens
+		r.output += c.OutputTokens
+		r.retries += c.Retries
+		r.latency += c.LatencyMS
+		r.estimated = r.estimated || c.Estimated
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		if keys[i].model != keys[j].model {
+			return keys[i].model < keys[j].model
+		}
+		return keys[i].mode < keys[j].mode
+	})
+
+	var b strings.Builder
+	b.WriteString("## 📈 AI の使用量\n\n")
+	b.WriteString("| バックエンド | モデル | モード | 呼び出し | 入力トークン | 出力トークン | 所要時間 | 再試行 |\n")
+	b.WriteString("| :--- | :--- | :--- | ---: | ---: | ---: | ---: | ---: |\n")
+	estimated := false
+	for _, k := range keys {
+		r := rows[k]
+		mark := ""
+		if r.estimated {
+			mark, estimated = " *", true
+		}
+		fmt.Fprintf(&b, "| %s | `%s` | %s | %d | %d%s | %d%s | %s | %d |\n",
+			k.backend, k.model, orDash(k.mode), r.calls, r.prompt, mark, r.output, mark, formatLatency(r.latency), r.retries)
+	}
+	fmt.Fprintf(&b, "| **合計** | | | %d | %d | %d | %s | %d |\n",
+		len(s.Calls), s.PromptTokens, s.OutputTokens, formatLatency(s.LatencyMS), s.Retries)
+	if estimated {
+		b.WriteString("\n\\* API の応答にトークン数が含まれないため、バイト数からの概算値です。\n")
+	}
+	b.WriteString("\n" + s.Block() + "\n")
+	return b.String()
+}
+
+// formatLatency は、ミリ秒の所要時間を表示用の文字列に変換します。
+func formatLatency(ms int64) string {
+	return (time.Duration(ms) * time.Millisecond).Round(100 * time.Millisecond).String()
+}
+
+// orDash は、空文字列を "—" に置き換えます。
+func orDash(s string) string {
+	if s == "" {
+		return "—"
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"git-gemini-cli/internal/config"
+)
+
+// bitbucketCloudHost は、Bitbucket Cloud のホストです。Cloud のペイロードにはクローンのURLが含まれないため、リポジトリ名から組み立てます。
+const bitbucketCloudHost = "bitbucket.org"
+
+// bitbucketReviewEvents は、レビューを実行するイベント (X-Event-Key) です。
+// Cloud は pullrequest:、Server / Data Center は pr: で始まります。
+var bitbucketReviewEvents = map[string]bool{
+	"pullrequest:created": true,
+	"pullrequest:updated": true,
+	"pr:opened":           true,
+	"pr:from_ref_updated": true,
+}
+
+// bitbucketCloudEvent は、Bitbucket Cloud のプルリクエストのイベントのペイロードのうち使用する項目です。
+type bitbucketCloudEvent struct {
+	PullRequest struct {
+		ID          int                  `json:"id"`
+		Draft       bool                 `json:"draft"`
+		Source      bitbucketCloudBranch `json:"source"`
+		Destination bitbucketCloudBranch `json:"destination"`
+	} `json:"pullrequest"`
+}
+
+// bitbucketCloudBranch は、Bitbucket Cloud のプルリクエストのソース・宛先のブランチです。
+type bitbucketCloudBranch struct {
+	Branch struct {
+		Name string `json:"name"`
+	} `json:"branch"`
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// bitbucketServerEvent は、Bitbucket Server / Data Center のプルリクエストのイベントのペイロードのうち使用する項目です。
+type bitbucketServerEvent struct {
+	PullRequest struct {
+		ID      int                `json:"id"`
+		Draft   bool               `json:"draft"`
+		FromRef bitbucketServerRef `json:"fromRef"`
+		ToRef   bitbucketServerRef `json:"toRef"`
+	} `json:"pullRequest"`
+}
+
+// bitbucketServerRef は、Bitbucket Server / Data Center のプルリクエストのソース・宛先の参照です。
+type bitbucketServerRef struct {
+	DisplayID    string `json:"displayId"`
+	LatestCommit string `json:"latestCommit"`
+	Repository   struct {
+		Slug    string `json:"slug"`
+		Project struct {
+			Key string `json:"key"`
+		} `json:"project"`
+		Links struct {
+			Clone []struct {
+				Href string `json:"href"`
+				Name string `json:"name"` // "ssh" または "http"
+			} `json:"clone"`
+		} `json:"links"`
+	} `json:"repository"`
+}
+
+// BitbucketParser は、Bitbucket Cloud と Bitbucket Server / Data Center のプルリクエストのイベントの Webhook を解釈する Parser を返します。
+// 署名 (X-Hub-Signature) を secret で検証し、プルリクエストの作成・更新 (Server ではソースブランチへのコミットの追加) の場合にレビューを要求します。
+// ドラフトのプルリクエストと、フォークからのプルリクエストはレビューしません。
+// cloneHTTPS が true の場合はリポジトリの HTTPS のURL、false の場合は SSH のURLでクローンします。
+func BitbucketParser(secret string, cloneHTTPS bool) Parser {
+	return func(header http.Header, body []byte) (*ReviewRequest, error) {
+		if !validHubSignature(secret, body, header.Get("X-Hub-Signature")) {
+			return nil, ErrInvalidSignature
+		}
+		eventKey := header.Get("X-Event-Key")
+		if !bitbucketReviewEvents[eventKey] {
+			return nil, nil
+		}
+		if strings.HasPrefix(eventKey, "pr:") {
+			return parseBitbucketServer(header, body, cloneHTTPS)
+		}
+		return parseBitbucketCloud(header, body, cloneHTTPS)
+	}
+}
+
+// parseBitbucketCloud は、Bitbucket Cloud のプルリクエストのイベントからレビューを要求するプルリクエストを求めます。
+func parseBitbucketCloud(header http.Header, body []byte, cloneHTTPS bool) (*ReviewRequest, error) {
+	var event bitbucketCloudEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("プルリクエストのイベントの解析に失敗しました: %w", err)
+	}
+	pr := event.PullRequest
+	repo := pr.Destination.Repository.FullName
+	if pr.Draft || repo == "" || pr.Source.Repository.FullName != repo {
+		return nil, nil
+	}
+
+	repoURL := fmt.Sprintf("git@%s:%s.git", bitbucketCloudHost, repo)
+	if cloneHTTPS {
+		repoURL = fmt.Sprintf("https://%s/%s.git", bitbucketCloudHost, repo)
+	}
+	return &ReviewRequest{
+		Provider:      config.PRCommentBitbucket,
+		Repo:          repo,
+		RepoURL:       repoURL,
+		BaseBranch:    pr.Destination.Branch.Name,
+		FeatureBranch: pr.Source.Branch.Name,
+		Number:        pr.ID,
+		HeadSHA:       pr.Source.Commit.Hash,
+		DeliveryID:    header.Get("X-Request-UUID"),
+	}, nil
+}
+
+// parseBitbucketServer は、Bitbucket Server / Data Center のプルリクエストのイベントからレビューを要求するプルリクエストを求めます。
+func parseBitbucketServer(header http.Header, body []byte, cloneHTTPS bool) (*ReviewRequest, error) {
+	var event bitbucketServerEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, fmt.Errorf("プルリクエストのイベントの解析に失敗しました: %w", err)
+	}
+	pr := event.PullRequest
+	from, to := pr.FromRef.Repository, pr.ToRef.Repository
+	if pr.Draft || from.Project.Key != to.Project.Key || from.Slug != to.Slug {
+		return nil, nil
+	}
+
+	protocol := "ssh"
+	if cloneHTTPS {
+		protocol = "http"
+	}
+	var repoURL string
+	for _, link := range to.Links.Clone {
+		if link.Name == protocol {
+			repoURL = link.Href
+		}
+	}
+	if repoURL == "" {
+		return nil, fmt.Errorf("リポジトリ %s/%s のクローンのURL (%s) がペイロードに含まれていません", to.Project.Key, to.Slug, protocol)
+	}
+	return &ReviewRequest{
+		Provider:      config.PRCommentBitbucket,
+		Repo:          to.Project.Key + "/" + to.Slug,
+		RepoURL:       repoURL,
+		BaseBranch:    pr.ToRef.DisplayID,
+		FeatureBranch: pr.FromRef.DisplayID,
+		Number:        pr.ID,
+		HeadSHA:       pr.FromRef.LatestCommit,
+		DeliveryID:    header.Get("X-Request-Id"),
+	}, nil
+}
@@ -0,0 +1,131 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"git-gemini-cli/internal/config"
+)
+
+// bitbucketCloudEventBody は、テスト用の Bitbucket Cloud のプルリクエストのイベントのペイロードを返します。
+func bitbucketCloudEventBody(draft bool, sourceRepo string) string {
+	return fmt.Sprintf(`{
+  "pullrequest": {
+    "id": 3,
+    "draft": %t,
+    "source": {"branch": {"name": "feature/x"}, "commit": {"hash": "abc123"}, "repository": {"full_name": %q}},
+    "destination": {"branch": {"name": "main"}, "commit": {"hash": "000000"}, "repository": {"full_name": "team/repo"}}
+  }
+}`, draft, sourceRepo)
+}
+
+// bitbucketServerEventBody は、テスト用の Bitbucket Server / Data Center のプルリクエストのイベントのペイロードを返します。
+func bitbucketServerEventBody(draft bool, fromSlug string) string {
+	return fmt.Sprintf(`{
+  "pullRequest": {
+    "id": 5,
+    "draft": %t,
+    "fromRef": {"displayId": "feature/x", "latestCommit": "abc123", "repository": {"slug": %q, "project": {"key": "PRJ"}}},
+    "toRef": {
+      "displayId": "main",
+      "latestCommit": "000000",
+      "repository": {
+        "slug": "repo",
+        "project": {"key": "PRJ"},
+        "links": {"clone": [
+          {"href": "ssh://git@bitbucket.example.com:7999/prj/repo.git", "name": "ssh"},
+          {"href": "https://bitbucket.example.com/scm/prj/repo.git", "name": "http"}
+        ]}
+      }
+    }
+  }
+}`, draft, fromSlug)
+}
+
+// TestBitbucketParser は、署名の検証と、Cloud と Server / Data Center のイベントの判定を検証します。
+func TestBitbucketParser(t *testing.T) {
+	const secret = "s3cret"
+	cloud := bitbucketCloudEventBody(false, "team/repo")
+	cloudDraft := bitbucketCloudEventBody(true, "team/repo")
+	cloudFork := bitbucketCloudEventBody(false, "fork/repo")
+	server := bitbucketServerEventBody(false, "repo")
+	serverDraft := bitbucketServerEventBody(true, "repo")
+	serverFork := bitbucketServerEventBody(false, "fork")
+	serverNoLinks := strings.Replace(server, `"links"`, `"unused"`, 1)
+
+	cloudWant := &ReviewRequest{
+		Provider:      config.PRCommentBitbucket,
+		Repo:          "team/repo",
+		RepoURL:       "git@bitbucket.org:team/repo.git",
+		BaseBranch:    "main",
+		FeatureBranch: "feature/x",
+		Number:        3,
+		HeadSHA:       "abc123",
+		DeliveryID:    "cloud-delivery",
+	}
+	cloudHTTPSWant := *cloudWant
+	cloudHTTPSWant.RepoURL = "https://bitbucket.org/team/repo.git"
+	serverWant := &ReviewRequest{
+		Provider:      config.PRCommentBitbucket,
+		Repo:          "PRJ/repo",
+		RepoURL:       "ssh://git@bitbucket.example.com:7999/prj/repo.git",
+		BaseBranch:    "main",
+		FeatureBranch: "feature/x",
+		Number:        5,
+		HeadSHA:       "abc123",
+		DeliveryID:    "server-delivery",
+	}
+	serverHTTPSWant := *serverWant
+	serverHTTPSWant.RepoURL = "https://bitbucket.example.com/scm/prj/repo.git"
+
+	tests := []struct {
+		name       string
+		eventKey   string
+		body       string
+		signature  string
+		cloneHTTPS bool
+		want       *ReviewRequest
+		wantErr    bool
+		wantSigErr bool
+	}{
+		{name: "Cloud: 正しい署名の作成イベント", eventKey: "pullrequest:created", body: cloud, signature: sign(secret, cloud), want: cloudWant},
+		{name: "Cloud: 更新イベント", eventKey: "pullrequest:updated", body: cloud, signature: sign(secret, cloud), want: cloudWant},
+		{name: "Cloud: HTTPS でクローン", eventKey: "pullrequest:created", body: cloud, signature: sign(secret, cloud), cloneHTTPS: true, want: &cloudHTTPSWant},
+		{name: "Cloud: 改ざんされた本文", eventKey: "pullrequest:created", body: strings.Replace(cloud, "feature/x", "evil", 1), signature: sign(secret, cloud), wantSigErr: true},
+		{name: "Cloud: 署名のヘッダーなし", eventKey: "pullrequest:created", body: cloud, wantSigErr: true},
+		{name: "Cloud: 異なるシークレットの署名", eventKey: "pullrequest:created", body: cloud, signature: sign("other", cloud), wantSigErr: true},
+		{name: "Cloud: ドラフトのプルリクエスト", eventKey: "pullrequest:created", body: cloudDraft, signature: sign(secret, cloudDraft)},
+		{name: "Cloud: フォークからのプルリクエスト", eventKey: "pullrequest:created", body: cloudFork, signature: sign(secret, cloudFork)},
+		{name: "Cloud: 対象外のイベント", eventKey: "pullrequest:fulfilled", body: cloud, signature: sign(secret, cloud)},
+		{name: "Server: 正しい署名の作成イベント", eventKey: "pr:opened", body: server, signature: sign(secret, server), want: serverWant},
+		{name: "Server: ソースブランチの更新", eventKey: "pr:from_ref_updated", body: server, signature: sign(secret, server), want: serverWant},
+		{name: "Server: HTTPS でクローン", eventKey: "pr:opened", body: server, signature: sign(secret, server), cloneHTTPS: true, want: &serverHTTPSWant},
+		{name: "Server: 改ざんされた本文", eventKey: "pr:opened", body: strings.Replace(server, "feature/x", "evil", 1), signature: sign(secret, server), wantSigErr: true},
+		{name: "Server: 署名のヘッダーなし", eventKey: "pr:opened", body: server, wantSigErr: true},
+		{name: "Server: 異なるシークレットの署名", eventKey: "pr:opened", body: server, signature: sign("other", server), wantSigErr: true},
+		{name: "Server: ドラフトのプルリクエスト", eventKey: "pr:opened", body: serverDraft, signature: sign(secret, serverDraft)},
+		{name: "Server: フォークからのプルリクエスト", eventKey: "pr:opened", body: serverFork, signature: sign(secret, serverFork)},
+		{name: "Server: クローンのURLがない", eventKey: "pr:opened", body: serverNoLinks, signature: sign(secret, serverNoLinks), wantErr: true},
+		{name: "Server: 対象外のイベント", eventKey: "pr:merged", body: server, signature: sign(secret, server)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("X-Event-Key", tt.eventKey)
+			header.Set("X-Request-UUID", "cloud-delivery")
+			header.Set("X-Request-Id", "server-delivery")
+			if tt.signature != "" {
+				header.Set("X-Hub-Signature", tt.signature)
+			}
+
+			got, err := BitbucketParser(secret, tt.cloneHTTPS)(header, []byte(tt.body))
+			if errors.Is(err, ErrInvalidSignature) != tt.wantSigErr || (err != nil) != (tt.wantErr || tt.wantSigErr) {
+				t.Fatalf("error = %v, wantErr %v, wantSigErr %v", err, tt.wantErr, tt.wantSigErr)
+			}
+			checkReviewRequest(t, got, tt.want)
+		})
+	}
+}
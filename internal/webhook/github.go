@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"git-gemini-cli/internal/config"
+)
+
+// githubReviewActions は、レビューを実行する pull_request イベントのアクションです。
+var githubReviewActions = map[string]bool{
+	"opened":           true,
+	"synchronize":      true,
+	"reopened":         true,
+	"ready_for_review": true,
+}
+
+// githubPullRequestEvent は、GitHub の pull_request イベントのペイロードのうち使用する項目です。
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest struct {
+		Draft bool `json:"draft"`
+		Head  struct {
+			Ref  string `json:"ref"`
+			SHA  string `json:"sha"`
+			Repo struct {
+				FullName string `json:"full_name"`
+			} `json:"repo"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+		CloneURL string `json:"clone_url"`
+		SSHURL   string `json:"ssh_url"`
+	} `json:"repository"`
+}
+
+// GitHubParser は、GitHub の pull_request イベントの Webhook を解釈する Parser を返します。
+// 署名 (X-Hub-Signature-256) を secret で検証し、プルリクエストの作成・更新・再オープン・ドラフトの解除の場合にレビューを要求します。
+// ドラフトのプルリクエストと、フォークからのプルリクエスト (ヘッドのブランチがリポジトリにない) はレビューしません。
+// cloneHTTPS が true の場合はリポジトリの HTTPS のURL、false の場合は SSH のURLでクローンします。
+func GitHubParser(secret string, cloneHTTPS bool) Parser {
+	return func(header http.Header, body []byte) (*ReviewRequest, error) {
+		if !validHubSignature(secret, body, header.Get("X-Hub-Signature-256")) {
+			return nil, ErrInvalidSignature
+		}
+		if header.Get("X-GitHub-Event") != "pull_request" {
+			return nil, nil
+		}
+
+		var event githubPullRequestEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("pull_request イベントの解析に失敗しました: %w", err)
+		}
+		pr := event.PullRequest
+		if !githubReviewActions[event.Action] || pr.Draft {
+			return nil, nil
+		}
+		if pr.Head.Repo.FullName != event.Repository.FullName {
+			// フォークのブランチはリポジトリからクローンできないため、対象外とする
+			return nil, nil
+		}
+
+		repoURL := event.Repository.SSHURL
+		if cloneHTTPS {
+			repoURL = event.Repository.CloneURL
+		}
+		return &ReviewRequest{
+			Provider:      config.PRCommentGitHub,
+			Repo:          event.Repository.FullName,
+			RepoURL:       repoURL,
+			BaseBranch:    pr.Base.Ref,
+			FeatureBranch: pr.Head.Ref,
+			Number:        event.Number,
+			HeadSHA:       pr.Head.SHA,
+			DeliveryID:    header.Get("X-GitHub-Delivery"),
+		}, nil
+	}
+}
@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"git-gemini-cli/internal/config"
+)
+
+// githubEvent は、テスト用の pull_request イベントのペイロードを返します。
+func githubEvent(action string, draft bool, headRepo string) string {
+	return fmt.Sprintf(`{
+  "action": %q,
+  "number": 42,
+  "pull_request": {
+    "draft": %t,
+    "head": {"ref": "feature/x", "sha": "abc123", "repo": {"full_name": %q}},
+    "base": {"ref": "main"}
+  },
+  "repository": {
+    "full_name": "owner/repo",
+    "clone_url": "https://github.com/owner/repo.git",
+    "ssh_url": "git@github.com:owner/repo.git"
+  }
+}`, action, draft, headRepo)
+}
+
+// TestGitHubParser は、署名の検証と、レビューの対象とするイベントの判定を検証します。
+func TestGitHubParser(t *testing.T) {
+	const secret = "s3cret"
+	opened := githubEvent("opened", false, "owner/repo")
+	synchronize := githubEvent("synchronize", false, "owner/repo")
+	ready := githubEvent("ready_for_review", false, "owner/repo")
+	draft := githubEvent("opened", true, "owner/repo")
+	fork := githubEvent("opened", false, "fork/repo")
+	closed := githubEvent("closed", false, "owner/repo")
+	want := &ReviewRequest{
+		Provider:      config.PRCommentGitHub,
+		Repo:          "owner/repo",
+		RepoURL:       "git@github.com:owner/repo.git",
+		BaseBranch:    "main",
+		FeatureBranch: "feature/x",
+		Number:        42,
+		HeadSHA:       "abc123",
+		DeliveryID:    "delivery-1",
+	}
+
+	tests := []struct {
+		name       string
+		event      string
+		body       string
+		signature  string
+		cloneHTTPS bool
+		want       *ReviewRequest
+		wantErr    error
+	}{
+		{name: "正しい署名の作成イベント", event: "pull_request", body: opened, signature: sign(secret, opened), want: want},
+		{name: "HTTPS でクローン", event: "pull_request", body: opened, signature: sign(secret, opened), cloneHTTPS: true,
+			want: func() *ReviewRequest { r := *want; r.RepoURL = "https://github.com/owner/repo.git"; return &r }()},
+		{name: "コミットの追加", event: "pull_request", body: synchronize, signature: sign(secret, synchronize), want: want},
+		{name: "ドラフトの解除", event: "pull_request", body: ready, signature: sign(secret, ready), want: want},
+		{name: "改ざんされた本文", event: "pull_request", body: strings.Replace(opened, "feature/x", "evil", 1), signature: sign(secret, opened), wantErr: ErrInvalidSignature},
+		{name: "署名のヘッダーなし", event: "pull_request", body: opened, wantErr: ErrInvalidSignature},
+		{name: "異なるシークレットの署名", event: "pull_request", body: opened, signature: sign("other", opened), wantErr: ErrInvalidSignature},
+		{name: "ドラフトのプルリクエスト", event: "pull_request", body: draft, signature: sign(secret, draft)},
+		{name: "フォークからのプルリクエスト", event: "pull_request", body: fork, signature: sign(secret, fork)},
+		{name: "対象外のアクション", event: "pull_request", body: closed, signature: sign(secret, closed)},
+		{name: "pull_request 以外のイベント", event: "ping", body: `{}`, signature: sign(secret, `{}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("X-GitHub-Event", tt.event)
+			header.Set("X-GitHub-Delivery", "delivery-1")
+			if tt.signature != "" {
+				header.Set("X-Hub-Signature-256", tt.signature)
+			}
+
+			got, err := GitHubParser(secret, tt.cloneHTTPS)(header, []byte(tt.body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			checkReviewRequest(t, got, tt.want)
+		})
+	}
+}
+
+// checkReviewRequest は、Parser が返したレビューの要求を検証します。want が nil の場合はレビューの対象外であることを検証します。
+func checkReviewRequest(t *testing.T, got, want *ReviewRequest) {
+	t.Helper()
+	switch {
+	case want == nil && got != nil:
+		t.Errorf("レビューの対象外のイベントでレビューを要求しました: %+v", *got)
+	case want != nil && got == nil:
+		t.Errorf("レビューを要求しませんでした, want %+v", *want)
+	case want != nil && *got != *want:
+		t.Errorf("レビューの要求 = %+v, want %+v", *got, *want)
+	}
+}
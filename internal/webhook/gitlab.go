@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"git-gemini-cli/internal/config"
+)
+
+// gitlabMergeRequestEvent は、GitLab のマージリクエストのイベント (Merge Request Hook) のペイロードのうち使用する項目です。
+type gitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+		GitSSHURL         string `json:"git_ssh_url"`
+		GitHTTPURL        string `json:"git_http_url"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID             int    `json:"iid"`
+		Action          string `json:"action"`
+		OldRev          string `json:"oldrev"` // 新しいコミットが push された更新の場合のみ設定される
+		SourceBranch    string `json:"source_branch"`
+		TargetBranch    string `json:"target_branch"`
+		SourceProjectID int    `json:"source_project_id"`
+		TargetProjectID int    `json:"target_project_id"`
+		Draft           bool   `json:"draft"`
+		LastCommit      struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+	Changes struct {
+		Draft *struct {
+			Previous bool `json:"previous"`
+			Current  bool `json:"current"`
+		} `json:"draft"`
+	} `json:"changes"`
+}
+
+// GitLabParser は、GitLab のマージリクエストのイベントの Webhook を解釈する Parser を返します。
+// シークレットトークン (X-Gitlab-Token) を secret と比較し、マージリクエストの作成・再オープン・コミットの追加・ドラフトの解除の場合にレビューを要求します。
+// タイトルの変更などコミットを伴わない更新、ドラフトのマージリクエスト、フォークからのマージリクエストはレビューしません。
+// cloneHTTPS が true の場合はプロジェクトの HTTPS のURL、false の場合は SSH のURLでクローンします。
+func GitLabParser(secret string, cloneHTTPS bool) Parser {
+	return func(header http.Header, body []byte) (*ReviewRequest, error) {
+		if subtle.ConstantTimeCompare([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) != 1 {
+			return nil, ErrInvalidSignature
+		}
+		if header.Get("X-Gitlab-Event") != "Merge Request Hook" {
+			return nil, nil
+		}
+
+		var event gitlabMergeRequestEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, fmt.Errorf("マージリクエストのイベントの解析に失敗しました: %w", err)
+		}
+		mr := event.ObjectAttributes
+		readied := event.Changes.Draft != nil && event.Changes.Draft.Previous && !event.Changes.Draft.Current
+		switch {
+		case event.ObjectKind != "merge_request", mr.Draft:
+			return nil, nil
+		case mr.Action == "open", mr.Action == "reopen":
+		case mr.Action == "update" && (mr.OldRev != "" || readied):
+		default:
+			return nil, nil
+		}
+		if mr.SourceProjectID != mr.TargetProjectID {
+			// フォークのブランチはプロジェクトからクローンできないため、対象外とする
+			return nil, nil
+		}
+
+		repoURL := event.Project.GitSSHURL
+		if cloneHTTPS {
+			repoURL = event.Project.GitHTTPURL
+		}
+		return &ReviewRequest{
+			Provider:      config.PRCommentGitLab,
+			Repo:          event.Project.PathWithNamespace,
+			RepoURL:       repoURL,
+			BaseBranch:    mr.TargetBranch,
+			FeatureBranch: mr.SourceBranch,
+			Number:        mr.IID,
+			HeadSHA:       mr.LastCommit.ID,
+			DeliveryID:    header.Get("X-Gitlab-Event-UUID"),
+		}, nil
+	}
+}
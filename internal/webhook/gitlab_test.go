@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"git-gemini-cli/internal/config"
+)
+
+// gitlabEvent は、テスト用の Merge Request Hook のペイロードを返します。changes は "changes" の JSON です。
+func gitlabEvent(action, oldrev string, draft bool, sourceProjectID int, changes string) string {
+	return fmt.Sprintf(`{
+  "object_kind": "merge_request",
+  "project": {
+    "path_with_namespace": "group/project",
+    "git_ssh_url": "git@gitlab.com:group/project.git",
+    "git_http_url": "https://gitlab.com/group/project.git"
+  },
+  "object_attributes": {
+    "iid": 7,
+    "action": %q,
+    "oldrev": %q,
+    "source_branch": "feature/x",
+    "target_branch": "main",
+    "source_project_id": %d,
+    "target_project_id": 1,
+    "draft": %t,
+    "last_commit": {"id": "abc123"}
+  },
+  "changes": %s
+}`, action, oldrev, sourceProjectID, draft, changes)
+}
+
+// TestGitLabParser は、シークレットトークンの検証と、レビューの対象とするイベントの判定を検証します。
+func TestGitLabParser(t *testing.T) {
+	const secret = "s3cret"
+	opened := gitlabEvent("open", "", false, 1, `{}`)
+	want := &ReviewRequest{
+		Provider:      config.PRCommentGitLab,
+		Repo:          "group/project",
+		RepoURL:       "git@gitlab.com:group/project.git",
+		BaseBranch:    "main",
+		FeatureBranch: "feature/x",
+		Number:        7,
+		HeadSHA:       "abc123",
+		DeliveryID:    "delivery-1",
+	}
+	httpsWant := *want
+	httpsWant.RepoURL = "https://gitlab.com/group/project.git"
+
+	tests := []struct {
+		name       string
+		event      string
+		body       string
+		token      string
+		cloneHTTPS bool
+		want       *ReviewRequest
+		wantErr    error
+	}{
+		{name: "正しいトークンの作成イベント", event: "Merge Request Hook", body: opened, token: secret, want: want},
+		{name: "HTTPS でクローン", event: "Merge Request Hook", body: opened, token: secret, cloneHTTPS: true, want: &httpsWant},
+		{name: "再オープン", event: "Merge Request Hook", body: gitlabEvent("reopen", "", false, 1, `{}`), token: secret, want: want},
+		{name: "コミットの追加", event: "Merge Request Hook", body: gitlabEvent("update", "def456", false, 1, `{}`), token: secret, want: want},
+		{name: "ドラフトの解除", event: "Merge Request Hook", body: gitlabEvent("update", "", false, 1, `{"draft": {"previous": true, "current": false}}`), token: secret, want: want},
+		{name: "トークンのヘッダーなし", event: "Merge Request Hook", body: opened, wantErr: ErrInvalidSignature},
+		{name: "異なるトークン", event: "Merge Request Hook", body: opened, token: "other", wantErr: ErrInvalidSignature},
+		{name: "前方一致のトークン", event: "Merge Request Hook", body: opened, token: secret[:3], wantErr: ErrInvalidSignature},
+		{name: "ドラフトのマージリクエスト", event: "Merge Request Hook", body: gitlabEvent("open", "", true, 1, `{}`), token: secret},
+		{name: "ドラフトへの変更", event: "Merge Request Hook", body: gitlabEvent("update", "", true, 1, `{"draft": {"previous": false, "current": true}}`), token: secret},
+		{name: "コミットを伴わない更新", event: "Merge Request Hook", body: gitlabEvent("update", "", false, 1, `{"title": {"previous": "a", "current": "b"}}`), token: secret},
+		{name: "フォークからのマージリクエスト", event: "Merge Request Hook", body: gitlabEvent("open", "", false, 2, `{}`), token: secret},
+		{name: "対象外のアクション", event: "Merge Request Hook", body: gitlabEvent("merge", "", false, 1, `{}`), token: secret},
+		{name: "マージリクエスト以外のイベント", event: "Push Hook", body: `{"object_kind": "push"}`, token: secret},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("X-Gitlab-Event", tt.event)
+			header.Set("X-Gitlab-Event-UUID", "delivery-1")
+			if tt.token != "" {
+				header.Set("X-Gitlab-Token", tt.token)
+			}
+
+			got, err := GitLabParser(secret, tt.cloneHTTPS)(header, []byte(tt.body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			checkReviewRequest(t, got, tt.want)
+		})
+	}
+}
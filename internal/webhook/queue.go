@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// Queue は、受信したレビューの要求を、同時に実行するレビューの数を workers に制限して順に実行するキューです。
+//   - 同じリポジトリのレビューは、クローンを共有するため同時には実行せず、1件ずつ実行します。
+//   - 同じプルリクエスト (ブランチ) の要求が実行待ちの間に新しいコミットの要求を受信した場合は、古い要求を新しい要求で置き換えます。
+//   - 実行中のレビューと同じコミットの要求は、重複として破棄します。
+type Queue struct {
+	run func(ReviewRequest)
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []ReviewRequest   // 実行待ちの要求 (受信順)
+	busy    map[string]bool   // レビューを実行中のリポジトリ
+	running map[string]string // 実行中のレビューの、プルリクエストごとのコミットハッシュ
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewQueue は、workers 個のワーカーで run を実行する Queue を返します。workers が 1 未満の場合は 1 とします。
+func NewQueue(workers int, run func(ReviewRequest)) *Queue {
+	q := &Queue{
+		run:     run,
+		busy:    make(map[string]bool),
+		running: make(map[string]string),
+	}
+	q.cond = sync.NewCond(&q.mu)
+	for range max(workers, 1) {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Enqueue は、レビューの要求をキューに追加します。
+// 同じプルリクエストの要求が実行待ちの場合は、その要求を置き換えます (実行順は元の要求の位置のままとします)。
+func (q *Queue) Enqueue(req ReviewRequest) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		slog.Warn("サーバーの停止中のため、レビューの要求を破棄しました。", "repo", req.Repo, "branch", req.FeatureBranch)
+		return
+	}
+	key := pullRequestKey(req)
+	if sha, ok := q.running[key]; ok && req.HeadSHA != "" && sha == req.HeadSHA {
+		slog.Info("同じコミットのレビューを実行中のため、重複する要求を破棄しました。", "repo", req.Repo, "branch", req.FeatureBranch, "commit", req.HeadSHA)
+		return
+	}
+	for i, p := range q.pending {
+		if pullRequestKey(p) == key {
+			slog.Info("実行待ちのレビューを、新しいコミットの要求で置き換えました。", "repo", req.Repo, "branch", req.FeatureBranch, "superseded", p.HeadSHA, "commit", req.HeadSHA)
+			q.pending[i] = req
+			return
+		}
+	}
+	q.pending = append(q.pending, req)
+	slog.Info("レビューの要求をキューに追加しました。", "repo", req.Repo, "branch", req.FeatureBranch, "pending", len(q.pending))
+	q.cond.Signal()
+}
+
+// Close は、新しい要求の受け付けを停止し、実行待ちの要求を破棄して、実行中のレビューの完了を待ちます。
+func (q *Queue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	if len(q.pending) > 0 {
+		slog.Warn("サーバーの停止のため、実行待ちのレビューの要求を破棄しました。", "dropped", len(q.pending))
+	}
+	q.pending = nil
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+// work は、キューを閉じるまで、実行できる (同じリポジトリのレビューを実行中でない) 要求を取り出して実行します。
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for {
+		req, ok := q.next()
+		if !ok {
+			return
+		}
+		q.run(req)
+
+		q.mu.Lock()
+		delete(q.busy, repoKey(req))
+		delete(q.running, pullRequestKey(req))
+		// 同じリポジトリの要求を待っているワーカーがいるため、すべてのワーカーに通知する
+		q.cond.Broadcast()
+		q.mu.Unlock()
+	}
+}
+
+// next は、実行できる最も古い要求を取り出し、そのリポジトリを実行中として記録します。キューを閉じた場合は false を返します。
+func (q *Queue) next() (ReviewRequest, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		if q.closed {
+			return ReviewRequest{}, false
+		}
+		for i, req := range q.pending {
+			if q.busy[repoKey(req)] {
+				continue
+			}
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			q.busy[repoKey(req)] = true
+			q.running[pullRequestKey(req)] = req.HeadSHA
+			return req, true
+		}
+		q.cond.Wait()
+	}
+}
+
+// repoKey は、レビューを直列化する単位であるリポジトリを識別するキーを返します。
+func repoKey(req ReviewRequest) string {
+	return strings.ToLower(req.Provider + "\x00" + req.Repo)
+}
+
+// pullRequestKey は、新しいコミットの要求で置き換える単位であるプルリクエスト (リポジトリ・ベースブランチ・フィーチャーブランチの組) を識別するキーを返します。
+func pullRequestKey(req ReviewRequest) string {
+	return repoKey(req) + "\x00" + req.BaseBranch + "\x00" + req.FeatureBranch
+}
@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingRunner は、レビューの開始を started に通知し、finish で完了させるまでブロックする、Queue の run のフェイクです。
+type blockingRunner struct {
+	started chan ReviewRequest
+
+	mu    sync.Mutex
+	gates map[string]chan struct{} // コミットハッシュごとの、レビューの完了の通知
+}
+
+func newBlockingRunner() *blockingRunner {
+	return &blockingRunner{started: make(chan ReviewRequest, 16), gates: make(map[string]chan struct{})}
+}
+
+func (r *blockingRunner) gate(sha string) chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.gates[sha] == nil {
+		r.gates[sha] = make(chan struct{})
+	}
+	return r.gates[sha]
+}
+
+func (r *blockingRunner) run(req ReviewRequest) {
+	r.started <- req
+	<-r.gate(req.HeadSHA)
+}
+
+// finish は、コミットハッシュが sha のレビューを完了させます。
+func (r *blockingRunner) finish(sha string) {
+	close(r.gate(sha))
+}
+
+// waitStarted は、次に開始したレビューのコミットハッシュを返します。
+func (r *blockingRunner) waitStarted(t *testing.T) string {
+	t.Helper()
+	select {
+	case req := <-r.started:
+		return req.HeadSHA
+	case <-time.After(5 * time.Second):
+		t.Fatal("レビューが開始されませんでした")
+		return ""
+	}
+}
+
+// assertIdle は、レビューが新たに開始されないことを検証します。
+func (r *blockingRunner) assertIdle(t *testing.T) {
+	t.Helper()
+	select {
+	case req := <-r.started:
+		t.Fatalf("レビューが開始されました: %+v", req)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// pr は、テスト用のレビューの要求を返します。
+func pr(repo, branch, sha string) ReviewRequest {
+	return ReviewRequest{Provider: "github", Repo: repo, BaseBranch: "main", FeatureBranch: branch, HeadSHA: sha}
+}
+
+// TestQueueSerializesPerRepo は、同じリポジトリのレビューを同時に実行せず、受信順に実行することを検証します。
+func TestQueueSerializesPerRepo(t *testing.T) {
+	r := newBlockingRunner()
+	q := NewQueue(3, r.run)
+	defer q.Close()
+
+	q.Enqueue(pr("owner/a", "x", "a1"))
+	if got := r.waitStarted(t); got != "a1" {
+		t.Fatalf("最初に開始したレビュー = %q, want %q", got, "a1")
+	}
+	q.Enqueue(pr("Owner/A", "y", "a2")) // リポジトリ名の大文字・小文字は区別しない
+	q.Enqueue(pr("owner/b", "x", "b1"))
+
+	// a2 は実行待ちのままで、別のリポジトリの b1 が先に開始する
+	if got := r.waitStarted(t); got != "b1" {
+		t.Fatalf("2番目に開始したレビュー = %q, want %q", got, "b1")
+	}
+	r.assertIdle(t)
+
+	r.finish("a1")
+	if got := r.waitStarted(t); got != "a2" {
+		t.Fatalf("a1 の完了後に開始したレビュー = %q, want %q", got, "a2")
+	}
+	r.finish("a2")
+	r.finish("b1")
+	r.assertIdle(t)
+}
+
+// TestQueueOrder は、ワーカーが1つの場合に、受信順にレビューを実行することを検証します。
+func TestQueueOrder(t *testing.T) {
+	r := newBlockingRunner()
+	q := NewQueue(1, r.run)
+	defer q.Close()
+
+	want := []string{"1", "2", "3", "4"}
+	q.Enqueue(pr("owner/a", "w", "1"))
+	q.Enqueue(pr("owner/b", "x", "2"))
+	q.Enqueue(pr("owner/a", "y", "3"))
+	q.Enqueue(pr("owner/c", "z", "4"))
+	for _, sha := range want {
+		if got := r.waitStarted(t); got != sha {
+			t.Fatalf("開始したレビュー = %q, want %q", got, sha)
+		}
+		r.finish(sha)
+	}
+	r.assertIdle(t)
+}
+
+// TestQueueReplacesPending は、実行待ちの要求を同じプルリクエストの新しいコミットの要求で置き換え、元の位置で実行することを検証します。
+func TestQueueReplacesPending(t *testing.T) {
+	r := newBlockingRunner()
+	q := NewQueue(1, r.run)
+	defer q.Close()
+
+	q.Enqueue(pr("owner/a", "blocker", "s0"))
+	r.waitStarted(t)
+
+	q.Enqueue(pr("owner/a", "x", "x1"))
+	q.Enqueue(pr("owner/a", "y", "y1"))
+	q.Enqueue(pr("owner/a", "x", "x2")) // x1 を置き換える
+	q.Enqueue(pr("owner/a", "y", "y1")) // 実行待ちの要求と同じ配信の再送
+
+	r.finish("s0")
+	for _, sha := range []string{"x2", "y1"} {
+		if got := r.waitStarted(t); got != sha {
+			t.Fatalf("開始したレビュー = %q, want %q", got, sha)
+		}
+		r.finish(sha)
+	}
+	r.assertIdle(t)
+}
+
+// TestQueueDropsDuplicateOfRunning は、実行中のレビューと同じコミットの要求を破棄し、新しいコミットの要求は実行することを検証します。
+func TestQueueDropsDuplicateOfRunning(t *testing.T) {
+	r := newBlockingRunner()
+	q := NewQueue(2, r.run)
+	defer q.Close()
+
+	q.Enqueue(pr("owner/a", "x", "x1"))
+	r.waitStarted(t)
+
+	q.Enqueue(pr("owner/a", "x", "x1")) // 実行中のレビューと同じ配信の再送
+	r.assertIdle(t)
+	r.finish("x1")
+	r.assertIdle(t)
+
+	q.Enqueue(pr("owner/a", "x", "x2"))
+	if got := r.waitStarted(t); got != "x2" {
+		t.Fatalf("開始したレビュー = %q, want %q", got, "x2")
+	}
+	// 実行中のレビューと異なるコミットの要求は、完了後に実行する
+	q.Enqueue(pr("owner/a", "x", "x3"))
+	q.Enqueue(pr("owner/a", "x", "x3")) // 実行待ちの要求と同じ配信の再送
+	r.assertIdle(t)
+	r.finish("x2")
+	if got := r.waitStarted(t); got != "x3" {
+		t.Fatalf("開始したレビュー = %q, want %q", got, "x3")
+	}
+	r.finish("x3")
+	r.assertIdle(t)
+}
+
+// TestQueueClose は、Close が実行中のレビューの完了を待ち、実行待ちの要求と停止後の要求を破棄することを検証します。
+func TestQueueClose(t *testing.T) {
+	r := newBlockingRunner()
+	q := NewQueue(1, r.run)
+
+	q.Enqueue(pr("owner/a", "x", "x1"))
+	r.waitStarted(t)
+	q.Enqueue(pr("owner/a", "y", "y1"))
+
+	closed := make(chan struct{})
+	go func() {
+		q.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("実行中のレビューの完了を待たずに Close が終了しました")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	r.finish("x1")
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close が終了しませんでした")
+	}
+	q.Enqueue(pr("owner/a", "z", "z1"))
+	r.assertIdle(t)
+}
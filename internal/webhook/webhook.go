@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// maxPayloadBytes は、受信する Webhook のペイロードのサイズの上限です (GitHub の上限の 25MB に合わせる)。
+const maxPayloadBytes = 25 << 20
+
+// ErrInvalidSignature は、Webhook の署名 (共有シークレット) の検証に失敗したことを示すエラーです。
+var ErrInvalidSignature = errors.New("Webhook の署名が一致しません")
+
+// ReviewRequest は、Webhook のイベントから求めた、レビューを実行するプルリクエストです。
+type ReviewRequest struct {
+	Provider      string // イベントの送信元 (config.PRCommentGitHub など、結果の投稿先と同じ表記)
+	Repo          string // 表示用のリポジトリ名 (例: "owner/repo")
+	RepoURL       string // クローンに使用するリポジトリのURL
+	BaseBranch    string
+	FeatureBranch string
+	Number        int    // プルリクエスト (マージリクエスト) の番号
+	HeadSHA       string // イベントの時点のヘッドのコミットハッシュ
+	DeliveryID    string // 送信元が付与する配信の ID (ログ用)
+}
+
+// Parser は、Webhook のリクエストの署名を検証し、レビューを実行するプルリクエストを求める関数です。
+// レビューの対象外のイベント (ping やクローズなど) の場合は nil を返します。署名が不正な場合は ErrInvalidSignature を返します。
+type Parser func(header http.Header, body []byte) (*ReviewRequest, error)
+
+// Handler は、Webhook を受信し、レビューを実行するプルリクエストを dispatch に渡す http.Handler です。
+// レビューには時間がかかり、送信元は応答を待たずにタイムアウトするため、dispatch は Queue.Enqueue などの、レビューの完了を待たない関数を想定しています。
+type Handler struct {
+	parse    Parser
+	dispatch func(ReviewRequest)
+}
+
+// NewHandler は、parse でリクエストを解釈し、レビューの対象であれば dispatch を呼び出す Handler を返します。
+func NewHandler(parse Parser, dispatch func(ReviewRequest)) *Handler {
+	return &Handler{parse: parse, dispatch: dispatch}
+}
+
+// ServeHTTP は http.Handler インターフェースの実装です。
+// レビューを受け付けた場合は 202、対象外のイベントの場合は 200、署名が不正な場合は 401 を返します。
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadBytes))
+	if err != nil {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	req, err := h.parse(r.Header, body)
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		slog.Warn("署名が不正な Webhook を拒否しました。", "path", r.URL.Path, "remote", r.RemoteAddr)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	case err != nil:
+		slog.Warn("Webhook のペイロードを解釈できません。", "path", r.URL.Path, "error", err)
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	case req == nil:
+		fmt.Fprintln(w, "ignored")
+		return
+	}
+
+	slog.Info("Webhook を受信しました。レビューを要求します。", "provider", req.Provider, "repo", req.Repo, "number", req.Number, "branch", req.FeatureBranch, "delivery", req.DeliveryID)
+	h.dispatch(*req)
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintln(w, "accepted")
+}
+
+// validHubSignature は、X-Hub-Signature-256 (GitHub) や X-Hub-Signature (Bitbucket) の署名 ("sha256=<HMAC-SHA256 の16進数>") が、
+// secret によるペイロードの署名と一致するかを返します。
+func validHubSignature(secret string, body []byte, signature string) bool {
+	sig, ok := strings.CutPrefix(signature, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// sign は、secret による body の署名を X-Hub-Signature-256 の形式 ("sha256=<16進数>") で返します。
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// TestValidHubSignature は、署名の形式と値の検証を確認します。
+func TestValidHubSignature(t *testing.T) {
+	const secret, body = "s3cret", `{"action":"opened"}`
+	tests := []struct {
+		name      string
+		secret    string
+		body      string
+		signature string
+		want      bool
+	}{
+		{name: "正しい署名", secret: secret, body: body, signature: sign(secret, body), want: true},
+		{name: "改ざんされた本文", secret: secret, body: body + " ", signature: sign(secret, body)},
+		{name: "異なるシークレット", secret: "other", body: body, signature: sign(secret, body)},
+		{name: "署名なし", secret: secret, body: body, signature: ""},
+		{name: "sha256= のプレフィックスがない", secret: secret, body: body, signature: strings.TrimPrefix(sign(secret, body), "sha256=")},
+		{name: "sha1 の署名", secret: secret, body: body, signature: "sha1=" + strings.TrimPrefix(sign(secret, body), "sha256=")},
+		{name: "16進数ではない署名", secret: secret, body: body, signature: "sha256=zz"},
+		{name: "途中までの署名", secret: secret, body: body, signature: sign(secret, body)[:20]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validHubSignature(tt.secret, []byte(tt.body), tt.signature); got != tt.want {
+				t.Errorf("validHubSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHandler は、Parser の結果に応じたステータスコードと、レビューの要求の受け渡しを検証します。
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		req        *ReviewRequest
+		err        error
+		wantStatus int
+		wantCalled bool
+	}{
+		{name: "レビューを受け付ける", method: http.MethodPost, req: &ReviewRequest{Repo: "owner/repo", Number: 1}, wantStatus: http.StatusAccepted, wantCalled: true},
+		{name: "対象外のイベント", method: http.MethodPost, wantStatus: http.StatusOK},
+		{name: "署名が不正", method: http.MethodPost, err: ErrInvalidSignature, wantStatus: http.StatusUnauthorized},
+		{name: "ペイロードを解釈できない", method: http.MethodPost, err: errors.New("broken"), wantStatus: http.StatusBadRequest},
+		{name: "POST 以外", method: http.MethodGet, req: &ReviewRequest{}, wantStatus: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dispatched []ReviewRequest
+			h := NewHandler(func(http.Header, []byte) (*ReviewRequest, error) { return tt.req, tt.err }, func(req ReviewRequest) {
+				dispatched = append(dispatched, req)
+			})
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(tt.method, "/webhook/github", strings.NewReader("{}")))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if called := len(dispatched) == 1; called != tt.wantCalled || len(dispatched) > 1 {
+				t.Fatalf("dispatch の呼び出し = %d 回, want called=%v", len(dispatched), tt.wantCalled)
+			}
+			if tt.wantCalled && dispatched[0] != *tt.req {
+				t.Errorf("dispatch に渡されたレビューの要求 = %+v, want %+v", dispatched[0], *tt.req)
+			}
+		})
+	}
+}